@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// abuseClient represents the abuse tracking state of a client (IP address).
+type abuseClient struct {
+	violations  int       // violations in current window
+	windowStart time.Time // start of current window
+	bans        int       // number of bans (for escalating ban durations)
+	bannedUntil time.Time
+	lastSeen    time.Time
+}
+
+// AbuseTracker tracks malformed requests per client and bans clients temporarily.
+type AbuseTracker struct {
+	mutex       sync.Mutex
+	clients     map[string]*abuseClient
+	lastCleanup time.Time
+}
+
+// abuseTracker is the global abuse tracker
+var abuseTracker = &AbuseTracker{clients: make(map[string]*abuseClient)}
+
+/*
+isAbuseViolation checks if the HTTP status of a response indicates a malformed or abusive request.
+*/
+func isAbuseViolation(httpStatus int) bool {
+	switch httpStatus {
+	case http.StatusBadRequest, http.StatusUnauthorized, http.StatusMethodNotAllowed, http.StatusRequestEntityTooLarge:
+		return true
+	}
+	return false
+}
+
+/*
+bannedUntil returns the end of the ban for the client (zero time if not banned).
+*/
+func (t *AbuseTracker) bannedUntil(clientIP string, now time.Time) time.Time {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	client, found := t.clients[clientIP]
+	if !found || !now.Before(client.bannedUntil) {
+		return time.Time{}
+	}
+	return client.bannedUntil
+}
+
+/*
+recordViolation records a violation of the client. If the max number of violations within the window is
+exceeded, the client is banned. The ban duration doubles with each ban (up to the max ban duration).
+*/
+func (t *AbuseTracker) recordViolation(clientIP string, now time.Time) (banned bool, banDuration time.Duration) {
+	config := getProgConfig().AbuseProtection
+	window := time.Duration(config.Window) * time.Second
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.cleanup(now, window)
+
+	client, found := t.clients[clientIP]
+	if !found {
+		client = &abuseClient{windowStart: now}
+		t.clients[clientIP] = client
+	}
+	client.lastSeen = now
+	if now.Sub(client.windowStart) > window {
+		client.windowStart = now
+		client.violations = 0
+	}
+	client.violations++
+	if client.violations < config.MaxViolations {
+		return false, 0
+	}
+
+	// ban client (escalating duration)
+	banDuration = time.Duration(float64(config.BanDuration) * math.Pow(2, float64(client.bans)) * float64(time.Second))
+	maxBanDuration := time.Duration(config.MaxBanDuration) * time.Second
+	if maxBanDuration > 0 && banDuration > maxBanDuration {
+		banDuration = maxBanDuration
+	}
+	client.bans++
+	client.bannedUntil = now.Add(banDuration)
+	client.violations = 0
+	client.windowStart = now
+
+	return true, banDuration
+}
+
+/*
+cleanup removes clients without activity (called with locked mutex, at most once per minute).
+Clients with bans are kept for the max ban duration to preserve the escalation level.
+*/
+func (t *AbuseTracker) cleanup(now time.Time, window time.Duration) {
+	if now.Sub(t.lastCleanup) < time.Minute {
+		return
+	}
+	t.lastCleanup = now
+
+	retention := max(window, time.Duration(getProgConfig().AbuseProtection.MaxBanDuration)*time.Second)
+	for clientIP, client := range t.clients {
+		if now.After(client.bannedUntil) && now.Sub(client.lastSeen) > retention {
+			delete(t.clients, clientIP)
+		}
+	}
+}
+
+/*
+abuseMiddleware rejects requests of temporarily banned clients and records malformed requests
+(e.g. invalid JSON, invalid tokens, oversized bodies) to protect the GDAL workers from scripted abuse.
+*/
+func abuseMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if !getProgConfig().AbuseProtection.Enabled {
+			next.ServeHTTP(writer, request)
+			return
+		}
+
+		clientIP := getClientIP(request)
+		now := time.Now()
+
+		bannedUntil := abuseTracker.bannedUntil(clientIP, now)
+		if !bannedUntil.IsZero() {
+			atomic.AddUint64(&BannedRequests, 1)
+			retryAfter := int(math.Ceil(bannedUntil.Sub(now).Seconds()))
+			writer.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			writer.Header().Set("Content-Type", TextPlainMediaType)
+			writer.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprintf(writer, "client temporarily banned due to repeated invalid requests, retry after %d seconds", retryAfter)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: writer}
+		next.ServeHTTP(recorder, request)
+
+		if isAbuseViolation(recorder.status) {
+			banned, banDuration := abuseTracker.recordViolation(clientIP, now)
+			if banned {
+				atomic.AddUint64(&ClientBans, 1)
+				slog.WarnContext(request.Context(), "client temporarily banned due to repeated invalid requests",
+					"client ip", clientIP, "ban duration", banDuration.String())
+			}
+		}
+	})
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
+)
+
+/*
+newAdminServer creates the (access-restricted) admin server with observability and administration endpoints
+(pprof, runtime metrics, Prometheus metrics, statistics, configuration reload, usage reports).
+*/
+func newAdminServer() (*http.Server, error) {
+	allowedNetworks, err := parseNetworks(progConfig.Admin.AllowedNetworks)
+	if err != nil {
+		return nil, fmt.Errorf("error [%w] at parseNetworks()", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /debug/pprof/", pprof.Index)
+	mux.HandleFunc("GET /debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("GET /debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("GET /debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("GET /debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("GET /debug/runtime", runtimeMetricsRequest)
+	mux.HandleFunc("GET /metrics", metricsRequest)
+	mux.HandleFunc("GET /v1/stats", statisticsRequest)
+	mux.HandleFunc("POST /admin/reload", reloadRequest)
+	mux.HandleFunc("GET /admin/loglevel", logLevelRequest)
+	mux.HandleFunc("PUT /admin/loglevel", logLevelRequest)
+	mux.HandleFunc("GET /admin/usage", usageReportRequest)
+
+	server := &http.Server{
+		Addr:              progConfig.Admin.ListenAddress,
+		Handler:           restrictToNetworks(allowedNetworks, mux),
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       30 * time.Second,
+	}
+
+	return server, nil
+}
+
+/*
+parseNetworks parses a list of networks (CIDR notation, e.g. 127.0.0.1/32) or single IP addresses.
+*/
+func parseNetworks(networks []string) ([]*net.IPNet, error) {
+	var ipNets []*net.IPNet
+	for _, network := range networks {
+		_, ipNet, err := net.ParseCIDR(network)
+		if err != nil {
+			ip := net.ParseIP(network)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid network or IP address [%s]", network)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 8 * net.IPv4len
+			}
+			ipNet = &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+		}
+		ipNets = append(ipNets, ipNet)
+	}
+	return ipNets, nil
+}
+
+/*
+isIPInNetworks checks if the remote address (host:port) is part of the given networks.
+*/
+func isIPInNetworks(remoteAddr string, networks []*net.IPNet) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+restrictToNetworks only passes requests from the allowed networks to the handler.
+*/
+func restrictToNetworks(allowedNetworks []*net.IPNet, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		clientIP := getClientIP(request)
+		if !isIPInNetworks(clientIP, allowedNetworks) {
+			slog.Warn("admin request: access denied", "client ip", clientIP, "path", request.URL.Path)
+			http.Error(writer, "Forbidden", http.StatusForbidden)
+			return
+		}
+		handler.ServeHTTP(writer, request)
+	})
+}
+
+// RuntimeMetrics represents runtime metrics of this service.
+type RuntimeMetrics struct {
+	Goroutines    int
+	NumCPU        int
+	HeapAlloc     uint64
+	HeapSys       uint64
+	HeapObjects   uint64
+	TotalAlloc    uint64
+	Sys           uint64
+	NumGC         uint32
+	PauseTotalNs  uint64
+	LastGC        string
+	GCCPUFraction float64
+}
+
+/*
+runtimeMetricsRequest handles 'runtime metrics' request (goroutines, heap, GC).
+*/
+func runtimeMetricsRequest(writer http.ResponseWriter, _ *http.Request) {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	metrics := RuntimeMetrics{
+		Goroutines:    runtime.NumGoroutine(),
+		NumCPU:        runtime.NumCPU(),
+		HeapAlloc:     memStats.HeapAlloc,
+		HeapSys:       memStats.HeapSys,
+		HeapObjects:   memStats.HeapObjects,
+		TotalAlloc:    memStats.TotalAlloc,
+		Sys:           memStats.Sys,
+		NumGC:         memStats.NumGC,
+		PauseTotalNs:  memStats.PauseTotalNs,
+		LastGC:        time.Unix(0, int64(memStats.LastGC)).Format(time.RFC3339Nano),
+		GCCPUFraction: memStats.GCCPUFraction,
+	}
+
+	body, err := json.MarshalIndent(metrics, "", "  ")
+	if err != nil {
+		slog.Error("error marshaling runtime metrics", "error", err)
+		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Content-Type", JSONAPIMediaType)
+	writer.WriteHeader(http.StatusOK)
+	_, err = writer.Write(body)
+	if err != nil {
+		slog.Error("error writing HTTP response body", "error", err)
+	}
+}
+
+/*
+reloadRequest handles 'reload' request (reloads the configuration file, like SIGHUP).
+*/
+func reloadRequest(writer http.ResponseWriter, request *http.Request) {
+	slog.InfoContext(request.Context(), "admin request: reloading configuration", "client ip", getClientIP(request))
+	err := reloadProgConfig(getProgConfigFile())
+	writer.Header().Set("Content-Type", TextPlainMediaType)
+	if err != nil {
+		slog.ErrorContext(request.Context(), "error reloading configuration, current configuration remains active", "error", err)
+		writer.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(writer, "error reloading configuration: %v", err)
+		return
+	}
+	writer.WriteHeader(http.StatusOK)
+	fmt.Fprint(writer, "configuration reloaded")
+}
+
+/*
+metricsRequest handles 'metrics' request (Prometheus text exposition format).
+Counters are totals since program start (the statistics counters are reset daily).
+*/
+func metricsRequest(writer http.ResponseWriter, _ *http.Request) {
+	var metrics strings.Builder
+
+	statisticsMutex.Lock()
+	for _, statisticsCounter := range statisticsCounters {
+		name := "dtm_" + toSnakeCase(statisticsCounter.name) + "_total"
+		value := statisticsTotals[statisticsCounter.name] + atomic.LoadUint64(statisticsCounter.counter)
+		fmt.Fprintf(&metrics, "# TYPE %s counter\n%s %d\n", name, name, value)
+	}
+	statisticsMutex.Unlock()
+
+	gauge := func(name string, value float64) {
+		fmt.Fprintf(&metrics, "# TYPE %s gauge\n%s %g\n", name, name, value)
+	}
+	responseCacheEntries, responseCacheBytes := responseCache.Len()
+	gauge("dtm_response_cache_entries", float64(responseCacheEntries))
+	gauge("dtm_response_cache_bytes", float64(responseCacheBytes))
+	gauge("dtm_gdal_jobs_running", float64(gdalJobQueue.running()))
+	gauge("dtm_gdal_jobs_waiting", float64(gdalJobQueue.queueDepth()))
+	classGauge := func(name string, value func(pool *workerPool) float64) {
+		fmt.Fprintf(&metrics, "# TYPE %s gauge\n", name)
+		for _, class := range endpointClasses {
+			fmt.Fprintf(&metrics, "%s{class=%q} %g\n", name, class, value(workerPools[class]))
+		}
+	}
+	classGauge("dtm_worker_pool_running", func(pool *workerPool) float64 { return float64(pool.running()) })
+	classGauge("dtm_worker_pool_waiting", func(pool *workerPool) float64 { return float64(pool.queueDepth()) })
+	gauge("dtm_goroutines", float64(runtime.NumGoroutine()))
+	gauge("dtm_uptime_seconds", time.Since(programStartTime).Seconds())
+
+	writer.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	writer.WriteHeader(http.StatusOK)
+	_, err := fmt.Fprint(writer, metrics.String())
+	if err != nil {
+		slog.Error("error writing HTTP response body", "error", err)
+	}
+}
+
+/*
+toSnakeCase converts a counter name to snake case (e.g. 'GPXAnalyzeRequests' -> 'gpx_analyze_requests').
+*/
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var builder strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			previousLower := unicode.IsLower(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if previousLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				builder.WriteByte('_')
+			}
+		}
+		builder.WriteRune(unicode.ToLower(r))
+	}
+	return builder.String()
+}
+
+// logLevelResetTimer reverts a temporary log level change
+var (
+	logLevelMutex      sync.Mutex
+	logLevelResetTimer *time.Timer
+)
+
+/*
+logLevelRequest handles 'log level' request. GET returns the current log level. PUT changes the log level,
+e.g. PUT /admin/loglevel?level=debug&duration=900 (optional duration in seconds, then reverts to configured level).
+*/
+func logLevelRequest(writer http.ResponseWriter, request *http.Request) {
+	writer.Header().Set("Content-Type", TextPlainMediaType)
+
+	if request.Method == http.MethodPut {
+		level := strings.ToLower(request.URL.Query().Get("level"))
+		switch level {
+		case "debug", "info", "warn", "error":
+		default:
+			writer.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(writer, "invalid log level [%s] (debug, info, warn, error)", level)
+			return
+		}
+		duration := 0
+		if value := request.URL.Query().Get("duration"); value != "" {
+			var err error
+			duration, err = strconv.Atoi(value)
+			if err != nil || duration < 0 {
+				writer.WriteHeader(http.StatusBadRequest)
+				fmt.Fprintf(writer, "invalid duration [%s] (seconds)", value)
+				return
+			}
+		}
+
+		logLevelMutex.Lock()
+		if logLevelResetTimer != nil {
+			logLevelResetTimer.Stop()
+			logLevelResetTimer = nil
+		}
+		logLevel.Set(parseLogLevel(level))
+		if duration > 0 {
+			logLevelResetTimer = time.AfterFunc(time.Duration(duration)*time.Second, func() {
+				logLevel.Set(parseLogLevel(getProgConfig().LogLevel))
+				slog.Warn("temporary log level expired, reverted to configured log level", "log level", getProgConfig().LogLevel)
+			})
+		}
+		logLevelMutex.Unlock()
+
+		slog.WarnContext(request.Context(), "log level changed at runtime", "log level", level, "duration", duration,
+			"client ip", getClientIP(request))
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	fmt.Fprint(writer, strings.ToLower(logLevel.Level().String()))
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/airbusgeo/godal"
+
+	"klaus/elevation/dtm-elevation-service/pkg/dtm"
+)
+
+// ErrAdministrativeAreaNotFound is returned if the name (or key) of an administrative area is unknown.
+var ErrAdministrativeAreaNotFound = errors.New("administrative area not found")
+
+// AdministrativeArea represents the boundary of an administrative area (e.g. Gemeinde, Landkreis) in UTM coordinates.
+type AdministrativeArea struct {
+	Name   string     // name as stored in the GeoPackage (e.g. Münster)
+	Key    string     // key as stored in the GeoPackage (e.g. official regional key)
+	Zone   int        // UTM zone of the area (derived from center of area)
+	WKT    string     // boundary ((multi)polygon) in UTM coordinates
+	Bounds [4]float64 // bounding box in UTM coordinates (min easting, min northing, max easting, max northing)
+}
+
+// administrativeAreas caches the boundaries of requested administrative areas (key: GeoPackage, layer and name)
+var (
+	administrativeAreasMutex sync.Mutex
+	administrativeAreas      = make(map[string]AdministrativeArea)
+)
+
+/*
+getAdministrativeArea gets the boundary of an administrative area by name or key from the configured GeoPackage.
+Names are compared case-insensitively, ambiguous names (e.g. Neustadt) must be given by key.
+*/
+func getAdministrativeArea(name string) (AdministrativeArea, error) {
+	var area AdministrativeArea
+	config := getProgConfig().AdministrativeAreas
+
+	if config.GeoPackage == "" {
+		return area, errors.New("administrative areas not enabled")
+	}
+	name = strings.TrimSpace(name)
+	if len(name) > 256 {
+		return area, errors.New("administrative area must be 1-256 characters long")
+	}
+
+	// lookup cache
+	cacheKey := strings.Join([]string{config.GeoPackage, config.Layer, strings.ToLower(name)}, "|")
+	administrativeAreasMutex.Lock()
+	area, found := administrativeAreas[cacheKey]
+	administrativeAreasMutex.Unlock()
+	if found {
+		return area, nil
+	}
+
+	dataset, err := godal.Open(config.GeoPackage, godal.VectorOnly())
+	if err != nil {
+		return area, fmt.Errorf("error [%w] at godal.Open(), file: %s", err, config.GeoPackage)
+	}
+	defer dataset.Close()
+
+	var layer godal.Layer
+	if config.Layer != "" {
+		namedLayer := dataset.LayerByName(config.Layer)
+		if namedLayer == nil {
+			return area, fmt.Errorf("layer [%s] not found in GeoPackage [%s]", config.Layer, config.GeoPackage)
+		}
+		layer = *namedLayer
+	} else {
+		layers := dataset.Layers()
+		if len(layers) == 0 {
+			return area, fmt.Errorf("no layer in GeoPackage [%s]", config.GeoPackage)
+		}
+		layer = layers[0]
+	}
+
+	// find matching features (by key or name)
+	matches := 0
+	var boundaryWKT string
+	layer.ResetReading()
+	for feature := layer.NextFeature(); feature != nil; feature = layer.NextFeature() {
+		fields := feature.Fields()
+		featureName := fields[config.NameAttribute].String()
+		featureKey := ""
+		if config.KeyAttribute != "" {
+			featureKey = fields[config.KeyAttribute].String()
+		}
+		if strings.EqualFold(featureName, name) || (featureKey != "" && featureKey == name) {
+			matches++
+			area.Name = featureName
+			area.Key = featureKey
+			boundaryWKT, err = feature.Geometry().WKT()
+		}
+		feature.Close()
+		if err != nil {
+			return area, fmt.Errorf("error [%w] at geometry.WKT(), administrative area: %s", err, name)
+		}
+	}
+	if matches == 0 {
+		return area, fmt.Errorf("administrative area [%s]: %w", name, ErrAdministrativeAreaNotFound)
+	}
+	if matches > 1 {
+		return area, fmt.Errorf("administrative area [%s] is ambiguous (%d matches), use key (%s) instead", name, matches, config.KeyAttribute)
+	}
+
+	// reproject boundary to UTM zone (zone 32 first, zone 33 if center of area is east of 12° E)
+	layerSpatialRef := layer.SpatialRef()
+	defer layerSpatialRef.Close()
+	for _, zone := range []int{32, 33} {
+		area.Zone = zone
+		area.WKT, area.Bounds, err = reprojectBoundary(boundaryWKT, layerSpatialRef, zone)
+		if err != nil {
+			return area, err
+		}
+		longitude, _, err := transformUTMToLonLat((area.Bounds[0]+area.Bounds[2])/2, (area.Bounds[1]+area.Bounds[3])/2, zone)
+		if err != nil {
+			return area, fmt.Errorf("error [%w] at transformUTMToLonLat()", err)
+		}
+		if longitude < 12.0 {
+			break
+		}
+	}
+
+	// add to cache (boundaries are static)
+	administrativeAreasMutex.Lock()
+	administrativeAreas[cacheKey] = area
+	administrativeAreasMutex.Unlock()
+
+	return area, nil
+}
+
+/*
+reprojectBoundary reprojects a boundary (WKT) to the given UTM zone and returns the boundary (WKT) and its bounding box.
+*/
+func reprojectBoundary(boundaryWKT string, spatialRef *godal.SpatialRef, zone int) (string, [4]float64, error) {
+	var bounds [4]float64
+
+	geometry, err := godal.NewGeometryFromWKT(boundaryWKT, spatialRef)
+	if err != nil {
+		return "", bounds, fmt.Errorf("error [%w] at godal.NewGeometryFromWKT()", err)
+	}
+	defer geometry.Close()
+
+	utmSpatialRef, err := godal.NewSpatialRefFromEPSG(25800 + zone)
+	if err != nil {
+		return "", bounds, fmt.Errorf("error [%w] at godal.NewSpatialRefFromEPSG()", err)
+	}
+	defer utmSpatialRef.Close()
+
+	err = geometry.Reproject(utmSpatialRef)
+	if err != nil {
+		return "", bounds, fmt.Errorf("error [%w] at geometry.Reproject()", err)
+	}
+	bounds, err = geometry.Bounds()
+	if err != nil {
+		return "", bounds, fmt.Errorf("error [%w] at geometry.Bounds()", err)
+	}
+	utmWKT, err := geometry.WKT()
+	if err != nil {
+		return "", bounds, fmt.Errorf("error [%w] at geometry.WKT()", err)
+	}
+
+	return utmWKT, bounds, nil
+}
+
+/*
+getAdministrativeAreaTile gets a (virtual) tile for an administrative area: mosaic of all tiles clipped to the
+boundary (warped VRT with cutline, evaluated on access). The tile references in-memory files, which must be
+removed by the caller (removeVSIMemFiles with the returned file names).
+*/
+func getAdministrativeAreaTile(ctx context.Context, name string) (TileMetadata, []string, error) {
+	var areaTile TileMetadata
+
+	area, err := getAdministrativeArea(name)
+	if err != nil {
+		return areaTile, nil, err
+	}
+	tiles, err := getAllTilesArea(area.Zone, area.Bounds)
+	if err != nil {
+		return areaTile, nil, err
+	}
+
+	// run operations in memory (/vsimem)
+	vsimemPrefix := dtm.NewVSIMemPrefix("adminarea")
+	filenameMosaicVRT := vsimemPrefix + "mosaic.vrt"
+	filenameClippedVRT := vsimemPrefix + "clipped.vrt"
+	vsimemFiles := []string{filenameClippedVRT, filenameMosaicVRT}
+
+	// gdalbuildvrt
+	tilePaths := make([]string, 0, len(tiles))
+	for _, tile := range tiles {
+		tilePaths = append(tilePaths, tile.Path)
+	}
+	err = dtm.BuildVRT(ctx, tilePaths, filenameMosaicVRT, nil)
+	if err != nil {
+		removeVSIMemFiles(vsimemFiles...)
+		return areaTile, nil, fmt.Errorf("error [%w] at dtm.BuildVRT()", err)
+	}
+
+	// gdalwarp (clip to boundary, cutline from GeoPackage, warped VRT keeps the cutline)
+	config := getProgConfig().AdministrativeAreas
+	switches := []string{"-of", "VRT", "-crop_to_cutline", "-dstnodata", "-9999",
+		"-cutline", config.GeoPackage}
+	if config.Layer != "" {
+		switches = append(switches, "-cl", config.Layer)
+	}
+	where := fmt.Sprintf("\"%s\" = '%s'", config.NameAttribute, strings.ReplaceAll(area.Name, "'", "''"))
+	if area.Key != "" {
+		where = fmt.Sprintf("\"%s\" = '%s'", config.KeyAttribute, strings.ReplaceAll(area.Key, "'", "''"))
+	}
+	switches = append(switches, "-cwhere", where)
+	err = dtm.Warp(ctx, filenameMosaicVRT, filenameClippedVRT, switches)
+	if err != nil {
+		removeVSIMemFiles(vsimemFiles...)
+		return areaTile, nil, fmt.Errorf("error [%w] at dtm.Warp()", err)
+	}
+
+	// source (most tiles) and latest actuality
+	sourceCounts := make(map[string]int)
+	actualities := []string{}
+	for _, tile := range tiles {
+		sourceCounts[tile.Source]++
+		actualities = append(actualities, tile.Actuality)
+	}
+	for source, count := range sourceCounts {
+		if count > sourceCounts[areaTile.Source] {
+			areaTile.Source = source
+		}
+	}
+
+	// index (e.g. 32_muenster), restricted to safe characters
+	safeName := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		}
+		return '-'
+	}, strings.NewReplacer("ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss").Replace(strings.ToLower(area.Name)))
+	if area.Key != "" {
+		safeName += "-" + area.Key
+	}
+
+	areaTile.Index = fmt.Sprintf("%d_%s", area.Zone, safeName)
+	areaTile.Path = filenameClippedVRT
+	areaTile.Actuality = slices.Max(actualities)
+	registerVirtualSourceTiles(filenameClippedVRT, tiles)
+
+	return areaTile, vsimemFiles, nil
+}
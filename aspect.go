@@ -1,8 +1,7 @@
 package main
 
 import (
-	"bytes"
-	"compress/gzip"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,8 +10,11 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync/atomic"
+
+	"klaus/elevation/dtm-elevation-service/pkg/dtm"
 )
 
 /*
@@ -34,18 +36,14 @@ func aspectRequest(writer http.ResponseWriter, request *http.Request) {
 		// check specifically for the error returned by MaxBytesReader
 		var maxBytesErr *http.MaxBytesError
 		if errors.As(err, &maxBytesErr) {
-			slog.Warn("aspect request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
-			aspectResponse.Attributes.Error.Code = "7000"
-			aspectResponse.Attributes.Error.Title = "request body too large"
-			aspectResponse.Attributes.Error.Detail = fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit)
-			buildAspectResponse(writer, http.StatusRequestEntityTooLarge, aspectResponse)
+			slog.WarnContext(request.Context(), "aspect request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
+			aspectResponse.Attributes.Error = newErrorObject(EndpointAspect, ReasonRequestBodyTooLarge, fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit))
+			buildAspectResponse(writer, request, http.StatusRequestEntityTooLarge, aspectResponse)
 		} else {
 			// handle other read errors
-			slog.Warn("aspect request: error reading request body", "error", err, "ID", "unknown")
-			aspectResponse.Attributes.Error.Code = "7020"
-			aspectResponse.Attributes.Error.Title = "error reading request body"
-			aspectResponse.Attributes.Error.Detail = err.Error()
-			buildAspectResponse(writer, http.StatusBadRequest, aspectResponse)
+			slog.WarnContext(request.Context(), "aspect request: error reading request body", "error", err, "ID", "unknown")
+			aspectResponse.Attributes.Error = newErrorObject(EndpointAspect, ReasonReadingRequestBody, err.Error())
+			buildAspectResponse(writer, request, http.StatusBadRequest, aspectResponse)
 		}
 		return
 	}
@@ -54,14 +52,52 @@ func aspectRequest(writer http.ResponseWriter, request *http.Request) {
 	aspectRequest := AspectRequest{}
 	err = json.Unmarshal(bodyData, &aspectRequest)
 	if err != nil {
-		slog.Warn("aspect request: error unmarshaling request body", "error", err, "ID", "unknown")
-		aspectResponse.Attributes.Error.Code = "7040"
-		aspectResponse.Attributes.Error.Title = "error unmarshaling request body"
-		aspectResponse.Attributes.Error.Detail = err.Error()
-		buildAspectResponse(writer, http.StatusBadRequest, aspectResponse)
+		slog.WarnContext(request.Context(), "aspect request: error unmarshaling request body", "error", err, "ID", "unknown")
+		aspectResponse.Attributes.Error = newErrorObject(EndpointAspect, ReasonUnmarshalingRequestBody, err.Error())
+		buildAspectResponse(writer, request, http.StatusBadRequest, aspectResponse)
 		return
 	}
 
+	// resolve place name (geocoding) into lon/lat coordinates
+	if aspectRequest.Attributes.Place != "" {
+		place, err := geocodePlace(request.Context(), aspectRequest.Attributes.Place)
+		if err != nil {
+			slog.WarnContext(request.Context(), "aspect request: error geocoding place", "error", err, "place", aspectRequest.Attributes.Place, "ID", aspectRequest.ID)
+			aspectResponse.ID = aspectRequest.ID
+			aspectResponse.Attributes.Place = aspectRequest.Attributes.Place
+			if errors.Is(err, ErrPlaceNotFound) {
+				aspectResponse.Attributes.Error = newErrorObject(EndpointAspect, ReasonPlaceNotFound, err.Error())
+				buildAspectResponse(writer, request, http.StatusNotFound, aspectResponse)
+				return
+			}
+			if errors.Is(err, ErrGeocoderUnavailable) {
+				aspectResponse.Attributes.Error = newErrorObject(EndpointAspect, ReasonGeocoderUnavailable, err.Error())
+				buildAspectResponse(writer, request, http.StatusServiceUnavailable, aspectResponse)
+				return
+			}
+			aspectResponse.Attributes.Error = newErrorObject(EndpointAspect, ReasonVerifyingRequestData, err.Error())
+			buildAspectResponse(writer, request, http.StatusBadRequest, aspectResponse)
+			return
+		}
+		aspectRequest.Attributes.Zone = 0
+		aspectRequest.Attributes.Longitude = place.Longitude
+		aspectRequest.Attributes.Latitude = place.Latitude
+		aspectResponse.Attributes.PlaceName = place.DisplayName
+	}
+
+	// named color table (stored server-side) instead of color text file content
+	if aspectRequest.Attributes.ColorTableName != "" {
+		aspectRequest.Attributes.ColorTextFileContent, err = resolveColorTable(request.Context(), aspectRequest.Attributes.ColorTableName, aspectRequest.Attributes.ColorTextFileContent)
+		if err != nil {
+			slog.WarnContext(request.Context(), "aspect request: error resolving color table", "error", err, "name", aspectRequest.Attributes.ColorTableName, "ID", aspectRequest.ID)
+			aspectResponse.ID = aspectRequest.ID
+			aspectResponse.Attributes.ColorTableName = aspectRequest.Attributes.ColorTableName
+			aspectResponse.Attributes.Error = newErrorObject(EndpointAspect, ReasonVerifyingRequestData, err.Error())
+			buildAspectResponse(writer, request, http.StatusBadRequest, aspectResponse)
+			return
+		}
+	}
+
 	// copy request parameters into response
 	aspectResponse.ID = aspectRequest.ID
 	aspectResponse.Attributes.Zone = aspectRequest.Attributes.Zone
@@ -69,18 +105,23 @@ func aspectRequest(writer http.ResponseWriter, request *http.Request) {
 	aspectResponse.Attributes.Northing = aspectRequest.Attributes.Northing
 	aspectResponse.Attributes.Longitude = aspectRequest.Attributes.Longitude
 	aspectResponse.Attributes.Latitude = aspectRequest.Attributes.Latitude
+	aspectResponse.Attributes.Place = aspectRequest.Attributes.Place
+	aspectResponse.Attributes.OutputFormat = aspectRequest.Attributes.OutputFormat
 	aspectResponse.Attributes.GradientAlgorithm = aspectRequest.Attributes.GradientAlgorithm
 	aspectResponse.Attributes.ColorTextFileContent = aspectRequest.Attributes.ColorTextFileContent
+	aspectResponse.Attributes.ColorTableName = aspectRequest.Attributes.ColorTableName
 	aspectResponse.Attributes.ColoringAlgorithm = aspectRequest.Attributes.ColoringAlgorithm
+	aspectResponse.Attributes.ZeroForFlat = aspectRequest.Attributes.ZeroForFlat
+	if aspectRequest.Attributes.ColoringAlgorithm == "" {
+		aspectResponse.Attributes.Warnings = append(aspectResponse.Attributes.Warnings, "ColoringAlgorithm not set, default 'interpolation' applied")
+	}
 
 	// verify request data
 	err = verifyAspectRequestData(request, aspectRequest)
 	if err != nil {
-		slog.Warn("aspect request: error verifying request data", "error", err, "ID", aspectRequest.ID)
-		aspectResponse.Attributes.Error.Code = "7060"
-		aspectResponse.Attributes.Error.Title = "error verifying request data"
-		aspectResponse.Attributes.Error.Detail = err.Error()
-		buildAspectResponse(writer, http.StatusBadRequest, aspectResponse)
+		slog.WarnContext(request.Context(), "aspect request: error verifying request data", "error", err, "ID", aspectRequest.ID)
+		aspectResponse.Attributes.Error = newErrorObject(EndpointAspect, ReasonVerifyingRequestData, err.Error())
+		buildAspectResponse(writer, request, http.StatusBadRequest, aspectResponse)
 		return
 	}
 
@@ -103,12 +144,20 @@ func aspectRequest(writer http.ResponseWriter, request *http.Request) {
 		// get all tiles (metadata) for given UTM coordinates
 		tiles, err = getAllTilesUTM(zone, easting, northing)
 		if err != nil {
-			slog.Warn("aspect request: error getting GeoTIFF tile for UTM coordinates", "error", err,
+			slog.WarnContext(request.Context(), "aspect request: error getting GeoTIFF tile for UTM coordinates", "error", err,
 				"easting", easting, "northing", northing, "zone", zone, "ID", aspectRequest.ID)
-			aspectResponse.Attributes.Error.Code = "7080"
-			aspectResponse.Attributes.Error.Title = "getting GeoTIFF tile for UTM coordinates"
-			aspectResponse.Attributes.Error.Detail = err.Error()
-			buildAspectResponse(writer, http.StatusBadRequest, aspectResponse)
+			if errors.Is(err, ErrOutsideCoverage) {
+				aspectResponse.Attributes.Error = newErrorObject(EndpointAspect, ReasonOutsideCoverage, describeOutsideCoverage(err))
+				buildAspectResponse(writer, request, http.StatusNotFound, aspectResponse)
+				return
+			}
+			if errors.Is(err, ErrSourceUnavailable) {
+				aspectResponse.Attributes.Error = newErrorObject(EndpointAspect, ReasonSourceUnavailable, err.Error())
+				buildAspectResponse(writer, request, http.StatusServiceUnavailable, aspectResponse)
+				return
+			}
+			aspectResponse.Attributes.Error = newErrorObject(EndpointAspect, ReasonGettingTileUTM, err.Error())
+			buildAspectResponse(writer, request, http.StatusBadRequest, aspectResponse)
 			return
 		}
 	} else {
@@ -121,33 +170,75 @@ func aspectRequest(writer http.ResponseWriter, request *http.Request) {
 		tiles, err = getAllTilesLonLat(longitude, latitude)
 		if err != nil {
 			err = fmt.Errorf("error [%w] getting tile for coordinates lon: %.8f, lat: %.8f", err, longitude, latitude)
-			slog.Warn("aspect request: error getting GeoTIFF tile for lon/lat coordinates", "error", err,
+			slog.WarnContext(request.Context(), "aspect request: error getting GeoTIFF tile for lon/lat coordinates", "error", err,
 				"longitude", longitude, "latitude", latitude, "ID", aspectRequest.ID)
-			aspectResponse.Attributes.Error.Code = "7100"
-			aspectResponse.Attributes.Error.Title = "getting GeoTIFF tile for lon/lat coordinates"
-			aspectResponse.Attributes.Error.Detail = err.Error()
-			buildAspectResponse(writer, http.StatusBadRequest, aspectResponse)
+			if errors.Is(err, ErrOutsideCoverage) {
+				aspectResponse.Attributes.Error = newErrorObject(EndpointAspect, ReasonOutsideCoverage, describeOutsideCoverage(err))
+				buildAspectResponse(writer, request, http.StatusNotFound, aspectResponse)
+				return
+			}
+			if errors.Is(err, ErrSourceUnavailable) {
+				aspectResponse.Attributes.Error = newErrorObject(EndpointAspect, ReasonSourceUnavailableLonLat, err.Error())
+				buildAspectResponse(writer, request, http.StatusServiceUnavailable, aspectResponse)
+				return
+			}
+			aspectResponse.Attributes.Error = newErrorObject(EndpointAspect, ReasonGettingTileLonLat, err.Error())
+			buildAspectResponse(writer, request, http.StatusBadRequest, aspectResponse)
 			return
 		}
 	}
 
+	// explicit output format (overrides default for type of coordinates)
+	if aspectRequest.Attributes.OutputFormat != "" {
+		outputFormat = strings.ToLower(aspectRequest.Attributes.OutputFormat)
+	}
+
+	// conditional request: unchanged response (same parameters, same tiles) needs no processing
+	etag := buildETag("aspect", aspectRequest, tiles)
+	if checkNotModified(writer, request, etag) {
+		return
+	}
+
+	// color of nodata areas (transparent, fill, preserve)
+	colorTextFileContent := applyNoDataHandling(aspectRequest.Attributes.ColorTextFileContent, aspectRequest.Attributes.NoDataHandling, aspectRequest.Attributes.NoDataColor)
+
 	// build aspect for all existing tiles
-	for _, tile := range tiles {
-		aspect, err := generateAspectObjectForTile(tile, outputFormat, aspectRequest.Attributes.GradientAlgorithm, aspectRequest.Attributes.ColorTextFileContent, aspectRequest.Attributes.ColoringAlgorithm)
-		if err != nil {
-			slog.Warn("aspect request: error generating aspect object for tile", "error", err, "ID", aspectRequest.ID)
-			aspectResponse.Attributes.Error.Code = "7120"
-			aspectResponse.Attributes.Error.Title = "error generating aspect object for tile"
-			aspectResponse.Attributes.Error.Detail = err.Error()
-			buildAspectResponse(writer, http.StatusBadRequest, aspectResponse)
+	aspects, tileErrors, err := generateObjectsForTiles(request.Context(), tiles, func(tile TileMetadata) (Aspect, error) {
+		return generateAspectObjectForTile(request.Context(), tile, outputFormat, aspectRequest.Attributes.GeoTIFFOptions, aspectRequest.Attributes.GradientAlgorithm, colorTextFileContent, aspectRequest.Attributes.ColoringAlgorithm,
+			aspectRequest.Attributes.ZeroForFlat)
+	})
+	if err != nil {
+		slog.WarnContext(request.Context(), "aspect request: error generating aspect object for tile", "error", err, "ID", aspectRequest.ID)
+		if errors.Is(err, context.Canceled) {
+			// client disconnected, processing aborted, no response required
+			return
+		}
+		if errors.Is(err, ErrServerBusy) {
+			aspectResponse.Attributes.Error = newErrorObject(EndpointAspect, ReasonServerBusy, err.Error())
+			writer.Header().Set("Retry-After", strconv.Itoa(getProgConfig().GDALJobQueue.RetryAfter))
+			buildAspectResponse(writer, request, http.StatusTooManyRequests, aspectResponse)
 			return
 		}
-		aspectResponse.Attributes.Aspects = append(aspectResponse.Attributes.Aspects, aspect)
+		aspectResponse.Attributes.Error = newErrorObject(EndpointAspect, ReasonGeneratingObject, err.Error())
+		buildAspectResponse(writer, request, http.StatusBadRequest, aspectResponse)
+		return
+	}
+	aspectResponse.Attributes.Aspects = aspects
+	aspectResponse.Attributes.TileErrors = tileErrors
+	if len(tileErrors) > 0 {
+		aspectResponse.Attributes.Warnings = append(aspectResponse.Attributes.Warnings,
+			fmt.Sprintf("%d of %d tiles could not be processed (see TileErrors)", len(tileErrors), len(tiles)))
+		slog.WarnContext(request.Context(), "aspect request: partial success, tiles could not be processed", "tile errors", tileErrors, "ID", aspectRequest.ID)
 	}
+	addUsage(request.Context(), 0, len(aspects))
 
 	// success response
 	aspectResponse.Attributes.IsError = false
-	buildAspectResponse(writer, http.StatusOK, aspectResponse)
+	if len(tileErrors) == 0 {
+		// partial responses are not cacheable
+		setETag(writer, etag)
+	}
+	buildAspectResponse(writer, request, http.StatusOK, aspectResponse)
 }
 
 /*
@@ -168,18 +259,6 @@ func verifyAspectRequestData(request *http.Request, aspectRequest AspectRequest)
 		return fmt.Errorf("unexpected or missing HTTP header field Content-Type, value = [%s], expected 'application/json'", contentType)
 	}
 
-	// verify HTTP header
-	accept := request.Header.Get("Accept")
-	isAcceptValid := true
-	switch {
-	case strings.HasPrefix(strings.ToLower(accept), "application/json"):
-	default:
-		isAcceptValid = false
-	}
-	if !isAcceptValid {
-		return fmt.Errorf("unexpected or missing HTTP header field Accept, value = [%s], expected 'application/json'", accept)
-	}
-
 	// verify Type
 	if aspectRequest.Type != TypeAspectRequest {
 		return fmt.Errorf("unexpected request Type [%v]", aspectRequest.Type)
@@ -233,6 +312,27 @@ func verifyAspectRequestData(request *http.Request, aspectRequest AspectRequest)
 			return errors.New("unsupported coloring algorithm (not 'interpolation' or 'rounding')")
 		}
 	}
+	// verify output format
+	switch strings.ToLower(aspectRequest.Attributes.OutputFormat) {
+	case "":
+	case "geotiff":
+	case "png":
+	default:
+		return errors.New("unsupported output format (not geotiff, png)")
+	}
+
+	// verify GeoTIFF creation options
+	err = verifyGeoTIFFOptions(aspectRequest.Attributes.GeoTIFFOptions)
+	if err != nil {
+		return err
+	}
+
+	// verify nodata handling
+	err = verifyNoDataHandling(aspectRequest.Attributes.NoDataHandling, aspectRequest.Attributes.NoDataColor)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -241,69 +341,36 @@ buildAspectResponse builds HTTP responses with specified status and body.
 It sets the Content-Type and Content-Length headers before writing the response body.
 This function is used to construct consistent HTTP responses throughout the application.
 */
-func buildAspectResponse(writer http.ResponseWriter, httpStatus int, aspectResponse AspectResponse) {
-	// log limit length of body (e.g., the aspect objects as part of the body can be very large)
-	maxBodyLength := 1024
-
-	// CORS: allow requests from any origin
-	writer.Header().Set("Access-Control-Allow-Origin", "*")
-	// CORS: allowed methods
-	writer.Header().Set("Access-Control-Allow-Methods", "POST")
-	// CORS: allowed headers
-	writer.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
-	// marshal response
-	body, err := json.MarshalIndent(aspectResponse, "", "  ")
-	if err != nil {
-		slog.Error("error marshaling point response", "error", err, "body length", len(body),
-			fmt.Sprintf("body (limited to first %d bytes)", maxBodyLength), body[:maxBodyLength])
+func buildAspectResponse(writer http.ResponseWriter, request *http.Request, httpStatus int, aspectResponse AspectResponse) {
+	// response metadata (versions, processing duration, cache hit)
+	aspectResponse.Meta = newResponseMeta(request.Context())
 
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-
-	// gzip response body
-	var bytesBuffer bytes.Buffer
-	gz := gzip.NewWriter(&bytesBuffer)
-
-	_, err = gz.Write(body)
-	if err != nil {
-		slog.Error("error [%v] at gz.Write()", "error", err)
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-
-	err = gz.Close()
-	if err != nil {
-		slog.Error("error [%v] at gz.Close()", "error", err)
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
+	// skip compression if payload is already compressed (e.g. PNG)
+	skipCompression := len(aspectResponse.Attributes.Aspects) > 0 && isCompressedDataFormat(aspectResponse.Attributes.Aspects[0].DataFormat)
 
-	// set headers
-	writer.Header().Set("Content-Encoding", "gzip")
-	writer.Header().Set("Content-Type", JSONAPIMediaType)
-	writer.WriteHeader(httpStatus)
-
-	// send response
-	_, err = writer.Write(bytesBuffer.Bytes())
-	if err != nil {
-		slog.Error("error writing HTTP response body", "error", err, "body length", len(body),
-			fmt.Sprintf("body (limited to first %d bytes)", maxBodyLength), body[:maxBodyLength])
-	}
+	// encode (streaming) and send response
+	streamJSONResponse(writer, request, httpStatus, aspectResponse, skipCompression)
 }
 
 /*
 generateAspectObjectForTile builds aspect object for given tile index.
 */
-func generateAspectObjectForTile(tile TileMetadata, outputFormat string, gradientAlgorithm string, colorTextFileContent []string, coloringAlgorithm string) (Aspect, error) {
+func generateAspectObjectForTile(ctx context.Context, tile TileMetadata, outputFormat string, geotiffOptions GeoTIFFOptions, gradientAlgorithm string, colorTextFileContent []string, coloringAlgorithm string, zeroForFlat bool) (Aspect, error) {
 	var aspect Aspect
 	var boundingBox WGS84BoundingBox
 
-	// run operations in temp directory
-	tempDir, err := os.MkdirTemp("", "dtm-elevation-service-aspect-")
+	// lookup response cache
+	cacheKey := buildResponseCacheKey("aspect", tile.Index, tile.Actuality, outputFormat, geotiffOptions, gradientAlgorithm, colorTextFileContent, coloringAlgorithm, zeroForFlat)
+	cached, found := responseCache.Get(cacheKey)
+	recordCacheLookup(ctx, found)
+	if found {
+		return cached.(Aspect), nil
+	}
+
+	// run operations in temp directory (color text file) and in memory (/vsimem)
+	tempDir, err := createTempDirectory("aspect")
 	if err != nil {
-		return aspect, fmt.Errorf("error [%w] at os.MkdirTemp()", err)
+		return aspect, fmt.Errorf("error [%w] at createTempDirectory()", err)
 	}
 	defer func() {
 		_ = os.RemoveAll(tempDir)
@@ -316,83 +383,37 @@ func generateAspectObjectForTile(tile TileMetadata, outputFormat string, gradien
 		return aspect, fmt.Errorf("error [%w] creating 'color-text-file'", err)
 	}
 
-	inputGeoTIFF := tile.Path
-	aspectUTMGeoTIFF := filepath.Join(tempDir, tile.Index+".aspect.utm.tif")
-	aspectColorUTMGeoTIFF := filepath.Join(tempDir, tile.Index+".aspect.color.utm.tif")
-	aspectWebmercatorGeoTIFF := filepath.Join(tempDir, tile.Index+".aspect.webmercator.tif")
-	aspectColorWebmercatoPNG := filepath.Join(tempDir, tile.Index+".aspect.color.webmercator.png")
-
-	// 1. create native aspect with 'gdaldem aspect'
+	// create colored aspect (GeoTIFF in UTM or PNG in Webmercator) with 'gdaldem aspect' and 'gdaldem color-relief'
 	// e.g. gdaldem aspect dgm1_32_497_5670_1_he.tif 32_497_5670_hangexposition.utm.tif -alg Horn -compute_edges
-	commandExitStatus, commandOutput, err := runCommand("gdaldem", []string{"aspect", inputGeoTIFF, aspectUTMGeoTIFF, "-alg", gradientAlgorithm, "-compute_edges"})
+	aspectOptions := []string{"-alg", gradientAlgorithm, "-compute_edges"}
+	if zeroForFlat {
+		aspectOptions = append(aspectOptions, "-zero_for_flat")
+	}
+	data, err := dtm.GenerateProduct(ctx, tile.Path, dtm.ProductOptions{
+		Name:            "aspect",
+		Mode:            "aspect",
+		ModeSwitches:    aspectOptions,
+		ColorTextFile:   colorTextFile,
+		NearestColor:    coloringAlgorithm == "rounding",
+		OutputFormat:    outputFormat,
+		CreationOptions: buildGeoTIFFCreationOptions(geotiffOptions),
+	})
 	if err != nil {
-		return aspect, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
+		return aspect, fmt.Errorf("error [%w] at dtm.GenerateProduct()", err)
 	}
-	// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-	// fmt.Printf("commandOutput: %s\n", commandOutput)
-
-	var data []byte
-	switch strings.ToLower(outputFormat) {
-	case "geotiff":
-		// 2. colorize aspect with 'gdaldem color-relief'
-		options := []string{"color-relief", aspectUTMGeoTIFF, colorTextFile, aspectColorUTMGeoTIFF, "-alpha"}
-		if coloringAlgorithm == "rounding" {
-			options = append(options, "-nearest_color_entry")
-		}
-		commandExitStatus, commandOutput, err = runCommand("gdaldem", options)
-		if err != nil {
-			return aspect, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
-		}
-		// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-		// fmt.Printf("commandOutput: %s\n", commandOutput)
-
-		data, err = os.ReadFile(aspectColorUTMGeoTIFF)
-		if err != nil {
-			return aspect, fmt.Errorf("error [%w] at os.ReadFile()", err)
-		}
 
-	case "png":
-		// 2. convert UTM (EPSG:25832/EPSG:25833) to Webmercator (EPSG:3857) with 'gdalwarp'
-		// e.g. gdalwarp -t_srs EPSG:3857 32_497_5670_hangexposition.utm.tif 32_497_5670_hangexposition.webmercator.tif
-		commandExitStatus, commandOutput, err = runCommand("gdalwarp", []string{"-t_srs", "EPSG:3857", aspectUTMGeoTIFF, aspectWebmercatorGeoTIFF})
-		if err != nil {
-			return aspect, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
-		}
-		// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-		// fmt.Printf("commandOutput: %s\n", commandOutput)
-
-		// 3. colorize aspect with 'gdaldem color-relief' (creates PNG file)
-		// e.g. gdaldem color-relief 32_497_5670_hangexposition.webmercator.tif aspect-colors.txt 32_497_5670_hangexposition.webmercator.png -alpha
-		options := []string{"color-relief", aspectWebmercatorGeoTIFF, colorTextFile, aspectColorWebmercatoPNG, "-alpha"}
-		if coloringAlgorithm == "rounding" {
-			options = append(options, "-nearest_color_entry")
-		}
-		commandExitStatus, commandOutput, err = runCommand("gdaldem", options)
-		if err != nil {
-			return aspect, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
-		}
-		// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-		// fmt.Printf("commandOutput: %s\n", commandOutput)
-
-		// 4. get bounding box (in wgs84) for webmercator tif (georeference of webmercator png )
+	// get bounding box (in wgs84) for webmercator png (georeference of webmercator png)
+	if strings.EqualFold(outputFormat, "png") {
 		boundingBox, err = calculateWGS84BoundingBox(tile)
 		if err != nil {
 			return aspect, fmt.Errorf("error [%w] at calculateWGS84BoundingBox(), file: %s", err, tile.Path)
 		}
-
-		// read result file
-		data, err = os.ReadFile(aspectColorWebmercatoPNG)
-		if err != nil {
-			return aspect, fmt.Errorf("error [%w] at os.ReadFile()", err)
-		}
-
-	default:
-		return aspect, fmt.Errorf("unsupported format [%s]", outputFormat)
 	}
 
 	// set aspect return structure
 	aspect.Data = data
 	aspect.DataFormat = outputFormat
+	aspect.Filename = buildObjectFilename(tile.Index, "aspect", gradientAlgorithm, tile.Actuality, outputFormat)
 	aspect.Actuality = tile.Actuality
 	aspect.Origin = tile.Source
 	aspect.TileIndex = tile.Index
@@ -402,11 +423,21 @@ func generateAspectObjectForTile(tile TileMetadata, outputFormat string, gradien
 	attribution := "unknown"
 	resource, err := getElevationResource(tile.Source)
 	if err != nil {
-		slog.Error("aspect request: error getting elevation resource", "error", err, "source", tile.Source)
+		slog.ErrorContext(ctx, "aspect request: error getting elevation resource", "error", err, "source", tile.Source)
 	} else {
 		attribution = resource.Attribution
 	}
 	aspect.Attribution = attribution
 
+	// get provenance of source files (delivery file name and checksum)
+	sourceFiles, err := getSourceFiles(tile)
+	if err != nil {
+		slog.ErrorContext(ctx, "aspect request: error getting source files", "error", err, "tile", tile.Index)
+	}
+	aspect.SourceFiles = sourceFiles
+
+	// add to response cache
+	responseCache.Add(cacheKey, aspect, len(aspect.Data))
+
 	return aspect, nil
 }
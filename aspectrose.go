@@ -0,0 +1,480 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/airbusgeo/godal"
+
+	"klaus/elevation/dtm-elevation-service/pkg/dtm"
+)
+
+// default upper bounds of slope classes (degrees)
+var defaultSlopeClasses = []float64{2, 5, 10, 15, 20, 30, 45, 90}
+
+// names of aspect directions (16 sectors, 8 and 4 sectors use every 2nd or 4th name)
+var aspectDirections = []string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW"}
+
+// aspectRoseStatistic represents the (cacheable) result of an aspect rose calculation.
+type aspectRoseStatistic struct {
+	Area         float64
+	NoDataArea   float64
+	MeanSlope    float64
+	FlatArea     float64
+	AspectRose   []AspectSector
+	SlopeClasses []SlopeClass
+}
+
+/*
+aspectRoseRequest handles 'aspect rose request' from client: aspect rose (share of area per aspect sector) and
+slope distribution for a polygon spanning several tiles (e.g. ecological or viticulture site assessments).
+*/
+func aspectRoseRequest(writer http.ResponseWriter, request *http.Request) {
+	var aspectRoseResponse = AspectRoseResponse{Type: TypeAspectRoseResponse, ID: "unknown"}
+	aspectRoseResponse.Attributes.IsError = true
+
+	// statistics
+	atomic.AddUint64(&AspectRoseRequests, 1)
+
+	// limit overall request body size
+	request.Body = http.MaxBytesReader(writer, request.Body, MaxAspectRoseRequestBodySize)
+
+	// read request
+	bodyData, err := io.ReadAll(request.Body)
+	if err != nil {
+		// check specifically for the error returned by MaxBytesReader
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			slog.WarnContext(request.Context(), "aspect rose request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
+			aspectRoseResponse.Attributes.Error = newErrorObject(EndpointAspectRose, ReasonRequestBodyTooLarge, fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit))
+			buildAspectRoseResponse(writer, request, http.StatusRequestEntityTooLarge, aspectRoseResponse)
+		} else {
+			// handle other read errors
+			slog.WarnContext(request.Context(), "aspect rose request: error reading request body", "error", err, "ID", "unknown")
+			aspectRoseResponse.Attributes.Error = newErrorObject(EndpointAspectRose, ReasonReadingRequestBody, err.Error())
+			buildAspectRoseResponse(writer, request, http.StatusBadRequest, aspectRoseResponse)
+		}
+		return
+	}
+
+	// unmarshal request
+	aspectRoseRequest := AspectRoseRequest{}
+	err = json.Unmarshal(bodyData, &aspectRoseRequest)
+	if err != nil {
+		slog.WarnContext(request.Context(), "aspect rose request: error unmarshaling request body", "error", err, "ID", "unknown")
+		aspectRoseResponse.Attributes.Error = newErrorObject(EndpointAspectRose, ReasonUnmarshalingRequestBody, err.Error())
+		buildAspectRoseResponse(writer, request, http.StatusBadRequest, aspectRoseResponse)
+		return
+	}
+
+	// defaults
+	if aspectRoseRequest.Attributes.Sectors == 0 {
+		aspectRoseRequest.Attributes.Sectors = 8
+	}
+	if len(aspectRoseRequest.Attributes.SlopeClasses) == 0 {
+		aspectRoseRequest.Attributes.SlopeClasses = defaultSlopeClasses
+	}
+
+	// copy request parameters into response
+	aspectRoseResponse.ID = aspectRoseRequest.ID
+	aspectRoseResponse.Attributes.Zone = aspectRoseRequest.Attributes.Zone
+	aspectRoseResponse.Attributes.Polygon = aspectRoseRequest.Attributes.Polygon
+	aspectRoseResponse.Attributes.Sectors = aspectRoseRequest.Attributes.Sectors
+	aspectRoseResponse.Attributes.FlatSlope = aspectRoseRequest.Attributes.FlatSlope
+
+	// verify request data
+	err = verifyAspectRoseRequestData(request, aspectRoseRequest)
+	if err != nil {
+		slog.WarnContext(request.Context(), "aspect rose request: error verifying request data", "error", err, "ID", aspectRoseRequest.ID)
+		aspectRoseResponse.Attributes.Error = newErrorObject(EndpointAspectRose, ReasonVerifyingRequestData, err.Error())
+		buildAspectRoseResponse(writer, request, http.StatusBadRequest, aspectRoseResponse)
+		return
+	}
+
+	// get polygon ring in UTM coordinates
+	zone, ring, err := getContoursAreaRingUTM(aspectRoseRequest.Attributes.Zone, &ContoursArea{Polygon: aspectRoseRequest.Attributes.Polygon})
+	if err != nil {
+		slog.WarnContext(request.Context(), "aspect rose request: error transforming polygon to UTM", "error", err, "ID", aspectRoseRequest.ID)
+		aspectRoseResponse.Attributes.Error = newErrorObject(EndpointAspectRose, ReasonVerifyingRequestData, err.Error())
+		buildAspectRoseResponse(writer, request, http.StatusBadRequest, aspectRoseResponse)
+		return
+	}
+	clipWKT, bounds := buildAreaPolygonWKT(ring)
+
+	// get all tiles (metadata) within polygon
+	tiles, err := getAllTilesArea(zone, bounds)
+	if err != nil {
+		slog.WarnContext(request.Context(), "aspect rose request: error getting GeoTIFF tiles for polygon", "error", err, "zone", zone, "ID", aspectRoseRequest.ID)
+		if errors.Is(err, ErrOutsideCoverage) {
+			aspectRoseResponse.Attributes.Error = newErrorObject(EndpointAspectRose, ReasonOutsideCoverage, err.Error())
+			buildAspectRoseResponse(writer, request, http.StatusNotFound, aspectRoseResponse)
+			return
+		}
+		aspectRoseResponse.Attributes.Error = newErrorObject(EndpointAspectRose, ReasonVerifyingRequestData, err.Error())
+		buildAspectRoseResponse(writer, request, http.StatusBadRequest, aspectRoseResponse)
+		return
+	}
+
+	// conditional request: unchanged response (same parameters, same tiles) needs no processing
+	etag := buildETag("aspectrose", aspectRoseRequest, tiles)
+	if checkNotModified(writer, request, etag) {
+		return
+	}
+
+	// calculate aspect rose for polygon (mosaic of all tiles, progress reported as one step per tile)
+	addJobTiles(request.Context(), len(tiles))
+	statistic, err := generateAspectRoseForArea(request.Context(), tiles, ring, clipWKT, bounds, aspectRoseRequest.Attributes.Sectors,
+		aspectRoseRequest.Attributes.FlatSlope, aspectRoseRequest.Attributes.SlopeClasses)
+	addJobTilesDone(request.Context(), len(tiles))
+	if err != nil {
+		slog.WarnContext(request.Context(), "aspect rose request: error generating aspect rose for polygon", "error", err, "ID", aspectRoseRequest.ID)
+		if errors.Is(err, context.Canceled) {
+			// client disconnected, processing aborted, no response required
+			return
+		}
+		if errors.Is(err, ErrServerBusy) {
+			aspectRoseResponse.Attributes.Error = newErrorObject(EndpointAspectRose, ReasonServerBusy, err.Error())
+			writer.Header().Set("Retry-After", strconv.Itoa(getProgConfig().GDALJobQueue.RetryAfter))
+			buildAspectRoseResponse(writer, request, http.StatusTooManyRequests, aspectRoseResponse)
+			return
+		}
+		aspectRoseResponse.Attributes.Error = newErrorObject(EndpointAspectRose, ReasonGeneratingObject, err.Error())
+		buildAspectRoseResponse(writer, request, http.StatusBadRequest, aspectRoseResponse)
+		return
+	}
+	if statistic.Area == 0 {
+		aspectRoseResponse.Attributes.Warnings = append(aspectRoseResponse.Attributes.Warnings, "polygon contains no valid elevation data")
+	}
+
+	// distinct actualities, origins and attributions of all tiles
+	var actualities, origins, attributions []string
+	for _, tile := range tiles {
+		aspectRoseResponse.Attributes.AreaTiles = append(aspectRoseResponse.Attributes.AreaTiles, tile.Index)
+		if !slices.Contains(actualities, tile.Actuality) {
+			actualities = append(actualities, tile.Actuality)
+		}
+		if slices.Contains(origins, tile.Source) {
+			continue
+		}
+		origins = append(origins, tile.Source)
+		attribution := "unknown"
+		resource, err := getElevationResource(tile.Source)
+		if err != nil {
+			slog.ErrorContext(request.Context(), "aspect rose request: error getting elevation resource", "error", err, "source", tile.Source, "ID", aspectRoseRequest.ID)
+		} else {
+			attribution = resource.Attribution
+		}
+		attributions = append(attributions, attribution)
+	}
+	slices.Sort(actualities)
+	addUsage(request.Context(), 0, len(tiles))
+
+	// success response
+	aspectRoseResponse.Attributes.Area = statistic.Area
+	aspectRoseResponse.Attributes.NoDataArea = statistic.NoDataArea
+	aspectRoseResponse.Attributes.MeanSlope = statistic.MeanSlope
+	aspectRoseResponse.Attributes.FlatArea = statistic.FlatArea
+	aspectRoseResponse.Attributes.FlatShare = areaShare(statistic.FlatArea, statistic.Area)
+	aspectRoseResponse.Attributes.AspectRose = statistic.AspectRose
+	aspectRoseResponse.Attributes.SlopeClasses = statistic.SlopeClasses
+	aspectRoseResponse.Attributes.Actuality = strings.Join(actualities, ", ")
+	aspectRoseResponse.Attributes.Origin = strings.Join(origins, ", ")
+	aspectRoseResponse.Attributes.Attribution = strings.Join(attributions, "; ")
+	aspectRoseResponse.Attributes.IsError = false
+	setETag(writer, etag)
+	buildAspectRoseResponse(writer, request, http.StatusOK, aspectRoseResponse)
+}
+
+/*
+verifyAspectRoseRequestData verifies 'aspect rose' request data.
+*/
+func verifyAspectRoseRequestData(request *http.Request, aspectRoseRequest AspectRoseRequest) error {
+	// verify HTTP header
+	contentType := request.Header.Get("Content-Type")
+	if !strings.HasPrefix(strings.ToLower(contentType), "application/json") {
+		return fmt.Errorf("unexpected or missing HTTP header field Content-Type, value = [%s], expected 'application/json'", contentType)
+	}
+
+	// verify Type
+	if aspectRoseRequest.Type != TypeAspectRoseRequest {
+		return fmt.Errorf("unexpected request Type [%v]", aspectRoseRequest.Type)
+	}
+
+	// verify ID
+	if len(aspectRoseRequest.ID) > 1024 {
+		return errors.New("ID must be 0-1024 characters long")
+	}
+
+	// verify zone for Germany (Zone: 32 or 33)
+	if aspectRoseRequest.Attributes.Zone != 0 {
+		if aspectRoseRequest.Attributes.Zone < 32 || aspectRoseRequest.Attributes.Zone > 33 {
+			return errors.New("invalid zone for Germany")
+		}
+	}
+
+	// verify polygon (UTM coordinates if zone is set)
+	if len(aspectRoseRequest.Attributes.Polygon) == 0 {
+		return errors.New("polygon must be set")
+	}
+	err := verifyContoursArea(aspectRoseRequest.Attributes.Zone, &ContoursArea{Polygon: aspectRoseRequest.Attributes.Polygon})
+	if err != nil {
+		return err
+	}
+
+	// verify sectors
+	switch aspectRoseRequest.Attributes.Sectors {
+	case 4, 8, 16:
+	default:
+		return errors.New("number of sectors must be 4, 8 or 16")
+	}
+
+	// verify flat slope
+	if aspectRoseRequest.Attributes.FlatSlope < 0.0 || aspectRoseRequest.Attributes.FlatSlope > 10.0 {
+		return errors.New("flat slope must be between 0.0 and 10.0 degrees")
+	}
+
+	// verify slope classes (ascending upper bounds)
+	slopeClasses := aspectRoseRequest.Attributes.SlopeClasses
+	if len(slopeClasses) > 20 {
+		return errors.New("number of slope classes must be between 1 and 20")
+	}
+	for i, bound := range slopeClasses {
+		if bound <= 0.0 || bound > 90.0 {
+			return errors.New("slope class bounds must be between 0.0 (exclusive) and 90.0 degrees")
+		}
+		if i > 0 && bound <= slopeClasses[i-1] {
+			return errors.New("slope class bounds must be in ascending order")
+		}
+	}
+
+	return nil
+}
+
+/*
+buildAspectRoseResponse builds HTTP responses with specified status and body.
+*/
+func buildAspectRoseResponse(writer http.ResponseWriter, request *http.Request, httpStatus int, aspectRoseResponse AspectRoseResponse) {
+	// response metadata (versions, processing duration, cache hit)
+	aspectRoseResponse.Meta = newResponseMeta(request.Context())
+
+	// encode (streaming) and send response
+	streamJSONResponse(writer, request, httpStatus, aspectRoseResponse, false)
+}
+
+/*
+generateAspectRoseForArea calculates aspect rose and slope distribution for a polygon covered by several tiles.
+Strategy:
+- build mosaic (VRT) of all tiles and crop it to the bounding box of the polygon (with margin)
+- calculate slope and aspect once for the mosaic (no artefacts at tile seams)
+- accumulate pixels with center inside the polygon (scanline)
+*/
+func generateAspectRoseForArea(ctx context.Context, tiles []TileMetadata, ring [][2]float64, clipWKT string, bounds [4]float64,
+	sectors int, flatSlope float64, slopeClasses []float64) (aspectRoseStatistic, error) {
+	var statistic aspectRoseStatistic
+
+	// lookup response cache
+	cacheKeyParts := []any{"aspectrose", clipWKT, sectors, flatSlope, slopeClasses}
+	for _, tile := range tiles {
+		cacheKeyParts = append(cacheKeyParts, tile.Index, tile.Actuality)
+	}
+	cacheKey := buildResponseCacheKey(cacheKeyParts...)
+	cached, found := responseCache.Get(cacheKey)
+	recordCacheLookup(ctx, found)
+	if found {
+		return cached.(aspectRoseStatistic), nil
+	}
+
+	// run operations in memory (/vsimem)
+	vsimemPrefix := dtm.NewVSIMemPrefix("aspectrose")
+	filenameVRT := vsimemPrefix + "mosaic.vrt"
+	filenameCroppedTif := vsimemPrefix + "mosaic.cropped.tif"
+	filenameSlopeTif := vsimemPrefix + "mosaic.slope.tif"
+	filenameAspectTif := vsimemPrefix + "mosaic.aspect.tif"
+	defer removeVSIMemFiles(filenameVRT, filenameCroppedTif, filenameSlopeTif, filenameAspectTif)
+
+	// gdalbuildvrt
+	tilePaths := make([]string, 0, len(tiles))
+	for _, tile := range tiles {
+		tilePaths = append(tilePaths, tile.Path)
+	}
+	err := dtm.BuildVRT(ctx, tilePaths, filenameVRT, nil)
+	if err != nil {
+		return statistic, fmt.Errorf("error [%w] at dtm.BuildVRT()", err)
+	}
+
+	// gdal_translate: crop mosaic to bounding box (margin of 2 m for slope and aspect at the edges)
+	margin := 2.0
+	err = dtm.Translate(ctx, filenameVRT, filenameCroppedTif, []string{"-of", "GTiff", "-projwin",
+		strconv.FormatFloat(bounds[0]-margin, 'f', -1, 64), strconv.FormatFloat(bounds[3]+margin, 'f', -1, 64),
+		strconv.FormatFloat(bounds[2]+margin, 'f', -1, 64), strconv.FormatFloat(bounds[1]-margin, 'f', -1, 64)})
+	if err != nil {
+		return statistic, fmt.Errorf("error [%w] at dtm.Translate()", err)
+	}
+
+	// gdaldem slope and aspect (aspect -9999 for flat areas)
+	err = dtm.Dem(ctx, "slope", filenameCroppedTif, "", filenameSlopeTif, []string{"-of", "GTiff", "-alg", "Horn", "-compute_edges"})
+	if err != nil {
+		return statistic, fmt.Errorf("error [%w] at dtm.Dem()", err)
+	}
+	err = dtm.Dem(ctx, "aspect", filenameCroppedTif, "", filenameAspectTif, []string{"-of", "GTiff", "-alg", "Horn", "-compute_edges"})
+	if err != nil {
+		return statistic, fmt.Errorf("error [%w] at dtm.Dem()", err)
+	}
+
+	statistic, err = accumulateAspectRose(ctx, filenameSlopeTif, filenameAspectTif, ring, sectors, flatSlope, slopeClasses)
+	if err != nil {
+		return statistic, fmt.Errorf("error [%w] at accumulateAspectRose()", err)
+	}
+
+	// add to response cache
+	responseCache.Add(cacheKey, statistic, 64*(len(statistic.AspectRose)+len(statistic.SlopeClasses)))
+
+	return statistic, nil
+}
+
+/*
+accumulateAspectRose accumulates the area of all pixels (center inside polygon ring) per aspect sector and slope class.
+The rasters are read row by row, pixels inside the polygon are determined by scanline intersection.
+*/
+func accumulateAspectRose(ctx context.Context, slopeFile string, aspectFile string, ring [][2]float64, sectors int,
+	flatSlope float64, slopeClasses []float64) (aspectRoseStatistic, error) {
+	var statistic aspectRoseStatistic
+
+	slopeDataset, err := godal.Open(slopeFile, godal.RasterOnly())
+	if err != nil {
+		return statistic, fmt.Errorf("error [%w] at godal.Open(), file: %s", err, slopeFile)
+	}
+	defer slopeDataset.Close()
+	aspectDataset, err := godal.Open(aspectFile, godal.RasterOnly())
+	if err != nil {
+		return statistic, fmt.Errorf("error [%w] at godal.Open(), file: %s", err, aspectFile)
+	}
+	defer aspectDataset.Close()
+
+	gt, err := slopeDataset.GeoTransform()
+	if err != nil {
+		return statistic, fmt.Errorf("error [%w] at dataset.GeoTransform()", err)
+	}
+	slopeBand := slopeDataset.Bands()[0]
+	aspectBand := aspectDataset.Bands()[0]
+	slopeNoData, hasSlopeNoData := slopeBand.NoData()
+	structure := slopeBand.Structure()
+	pixelArea := math.Abs(gt[1] * gt[5])
+
+	sectorWidth := 360.0 / float64(sectors)
+	sectorArea := make([]float64, sectors)
+	sectorSlopeSum := make([]float64, sectors)
+	classArea := make([]float64, len(slopeClasses))
+	slopeSum := 0.0
+
+	slopeRow := make([]float64, structure.SizeX)
+	aspectRow := make([]float64, structure.SizeX)
+	var crossings []float64
+	for row := 0; row < structure.SizeY; row++ {
+		if ctx.Err() != nil {
+			return statistic, ctx.Err()
+		}
+
+		// intersections of the ring edges with the row center line
+		y := gt[3] + (float64(row)+0.5)*gt[5]
+		crossings = crossings[:0]
+		for i := 1; i < len(ring); i++ {
+			x1, y1, x2, y2 := ring[i-1][0], ring[i-1][1], ring[i][0], ring[i][1]
+			if (y1 <= y && y < y2) || (y2 <= y && y < y1) {
+				crossings = append(crossings, x1+(y-y1)*(x2-x1)/(y2-y1))
+			}
+		}
+		if len(crossings) < 2 {
+			continue
+		}
+		slices.Sort(crossings)
+
+		err = slopeBand.Read(0, row, slopeRow, structure.SizeX, 1)
+		if err != nil {
+			return statistic, fmt.Errorf("error [%w] at band.Read(), file: %s, row: %d", err, slopeFile, row)
+		}
+		err = aspectBand.Read(0, row, aspectRow, structure.SizeX, 1)
+		if err != nil {
+			return statistic, fmt.Errorf("error [%w] at band.Read(), file: %s, row: %d", err, aspectFile, row)
+		}
+
+		for i := 0; i+1 < len(crossings); i += 2 {
+			// pixels with center inside [crossings[i], crossings[i+1])
+			first := max(int(math.Ceil((crossings[i]-gt[0])/gt[1]-0.5)), 0)
+			last := min(int(math.Ceil((crossings[i+1]-gt[0])/gt[1]-0.5))-1, structure.SizeX-1)
+			for column := first; column <= last; column++ {
+				slope := slopeRow[column]
+				if slope == noValueSentinel || math.IsNaN(slope) || (hasSlopeNoData && slope == slopeNoData) {
+					statistic.NoDataArea += pixelArea
+					continue
+				}
+				statistic.Area += pixelArea
+				slopeSum += slope * pixelArea
+
+				// slope class (slopes above the last bound are counted in the last class)
+				class, _ := slices.BinarySearch(slopeClasses, slope)
+				if class < len(slopeClasses) && slopeClasses[class] == slope {
+					class++
+				}
+				classArea[min(class, len(slopeClasses)-1)] += pixelArea
+
+				// aspect sector (sector 0 centered on north)
+				aspect := aspectRow[column]
+				if aspect < 0 || math.IsNaN(aspect) || slope < flatSlope {
+					statistic.FlatArea += pixelArea
+					continue
+				}
+				sector := int(math.Mod(aspect+sectorWidth/2, 360.0)/sectorWidth) % sectors
+				sectorArea[sector] += pixelArea
+				sectorSlopeSum[sector] += slope * pixelArea
+			}
+		}
+	}
+
+	// build result
+	if statistic.Area > 0 {
+		statistic.MeanSlope = slopeSum / statistic.Area
+	}
+	step := len(aspectDirections) / sectors
+	for sector := range sectors {
+		center := float64(sector) * sectorWidth
+		aspectSector := AspectSector{
+			Direction: aspectDirections[sector*step],
+			From:      math.Mod(center-sectorWidth/2+360.0, 360.0),
+			To:        center + sectorWidth/2,
+			Area:      sectorArea[sector],
+			Share:     areaShare(sectorArea[sector], statistic.Area),
+		}
+		if sectorArea[sector] > 0 {
+			aspectSector.MeanSlope = sectorSlopeSum[sector] / sectorArea[sector]
+		}
+		statistic.AspectRose = append(statistic.AspectRose, aspectSector)
+	}
+	lower := 0.0
+	for class, upper := range slopeClasses {
+		statistic.SlopeClasses = append(statistic.SlopeClasses, SlopeClass{From: lower, To: upper, Area: classArea[class], Share: areaShare(classArea[class], statistic.Area)})
+		lower = upper
+	}
+
+	return statistic, nil
+}
+
+/*
+areaShare returns the share of an area in percent of the total area (0 for empty total area).
+*/
+func areaShare(area float64, total float64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100.0 * area / total
+}
@@ -0,0 +1,583 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// JWT validation settings
+const (
+	jwtClockSkew        = 60 * time.Second // tolerated clock skew for 'exp' and 'nbf'
+	jwksMinRefreshDelay = 60 * time.Second // min delay between JWKS refreshes (e.g. unknown 'kid')
+	maxJWKSResponseSize = 1024 * 1024
+)
+
+// JWTClaims represents the (relevant) claims of a validated JWT.
+type JWTClaims struct {
+	Issuer    string
+	Subject   string
+	Audience  []string
+	ExpiresAt time.Time
+	Scopes    []string
+}
+
+// issuerKeys represents the cached signing keys (JWKS) of a trusted issuer.
+type issuerKeys struct {
+	jwksURI     string
+	keys        map[string]crypto.PublicKey // key: 'kid'
+	fetchedAt   time.Time
+	attemptedAt time.Time // last fetch (successful or failed)
+}
+
+// jwksFetch represents a (re)load of the signing keys of an issuer in progress (shared by all waiting requests).
+type jwksFetch struct {
+	done    chan struct{} // closed when fetch is finished
+	keys    map[string]crypto.PublicKey
+	jwksURI string
+	err     error
+}
+
+// JWTValidator validates JWT bearer tokens of trusted OpenID Connect issuers.
+type JWTValidator struct {
+	mutex          sync.Mutex
+	trustedIssuers []string
+	audience       string
+	jwksCacheTTL   time.Duration
+	issuers        map[string]*issuerKeys
+	fetches        map[string]*jwksFetch // fetches in progress, key: issuer
+	client         *http.Client
+}
+
+// jwtValidator is the global JWT validator (nil = authentication disabled)
+var jwtValidator *JWTValidator
+
+// jwtClaimsKey is the context key for the claims of the validated bearer token
+type jwtClaimsKey struct{}
+
+/*
+withJWTClaims returns a copy of the context with the claims of the validated bearer token.
+*/
+func withJWTClaims(ctx context.Context, claims JWTClaims) context.Context {
+	return context.WithValue(ctx, jwtClaimsKey{}, claims)
+}
+
+/*
+getJWTClaims returns the claims of the validated bearer token (found = false if not authenticated).
+*/
+func getJWTClaims(ctx context.Context) (claims JWTClaims, found bool) {
+	claims, found = ctx.Value(jwtClaimsKey{}).(JWTClaims)
+	return claims, found
+}
+
+/*
+hasRequiredScopes reports whether all required scopes are granted.
+*/
+func hasRequiredScopes(grantedScopes []string, requiredScopes []string) bool {
+	for _, scope := range requiredScopes {
+		if !slices.Contains(grantedScopes, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+newJWTValidator creates a JWT validator for the given trusted issuers (e.g. 'https://auth.example.com/realms/dtm').
+*/
+func newJWTValidator(trustedIssuers []string, audience string, jwksCacheTTL time.Duration) *JWTValidator {
+	normalizedIssuers := make([]string, 0, len(trustedIssuers))
+	for _, issuer := range trustedIssuers {
+		normalizedIssuers = append(normalizedIssuers, strings.TrimSuffix(issuer, "/"))
+	}
+	return &JWTValidator{
+		trustedIssuers: normalizedIssuers,
+		audience:       audience,
+		jwksCacheTTL:   jwksCacheTTL,
+		issuers:        make(map[string]*issuerKeys),
+		fetches:        make(map[string]*jwksFetch),
+		client:         &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+/*
+validateToken verifies signature, issuer, audience and lifetime of a JWT (compact serialization).
+*/
+func (v *JWTValidator) validateToken(ctx context.Context, token string) (JWTClaims, error) {
+	var claims JWTClaims
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return claims, errors.New("malformed token")
+	}
+
+	// header
+	headerData, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return claims, fmt.Errorf("error [%w] decoding token header", err)
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	err = json.Unmarshal(headerData, &header)
+	if err != nil {
+		return claims, fmt.Errorf("error [%w] unmarshaling token header", err)
+	}
+
+	// payload
+	payloadData, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return claims, fmt.Errorf("error [%w] decoding token payload", err)
+	}
+	var payload struct {
+		Iss   string          `json:"iss"`
+		Sub   string          `json:"sub"`
+		Aud   json.RawMessage `json:"aud"`
+		Exp   *float64        `json:"exp"`
+		Nbf   *float64        `json:"nbf"`
+		Scope string          `json:"scope"`
+		Scp   []string        `json:"scp"`
+	}
+	err = json.Unmarshal(payloadData, &payload)
+	if err != nil {
+		return claims, fmt.Errorf("error [%w] unmarshaling token payload", err)
+	}
+
+	// issuer
+	issuer := strings.TrimSuffix(payload.Iss, "/")
+	if !slices.Contains(v.trustedIssuers, issuer) {
+		return claims, fmt.Errorf("untrusted issuer [%s]", payload.Iss)
+	}
+
+	// signature
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return claims, fmt.Errorf("error [%w] decoding token signature", err)
+	}
+	key, err := v.getKey(ctx, issuer, header.Kid)
+	if err != nil {
+		return claims, err
+	}
+	err = verifyJWTSignature(header.Alg, key, []byte(parts[0]+"."+parts[1]), signature)
+	if err != nil {
+		return claims, err
+	}
+
+	// lifetime
+	now := time.Now()
+	if payload.Exp == nil {
+		return claims, errors.New("token without expiration time")
+	}
+	expiresAt := time.Unix(int64(*payload.Exp), 0)
+	if now.After(expiresAt.Add(jwtClockSkew)) {
+		return claims, errors.New("token expired")
+	}
+	if payload.Nbf != nil && now.Add(jwtClockSkew).Before(time.Unix(int64(*payload.Nbf), 0)) {
+		return claims, errors.New("token not yet valid")
+	}
+
+	// audience (string or array of strings)
+	var audience []string
+	if len(payload.Aud) > 0 {
+		var single string
+		if json.Unmarshal(payload.Aud, &single) == nil {
+			audience = []string{single}
+		} else if err = json.Unmarshal(payload.Aud, &audience); err != nil {
+			return claims, fmt.Errorf("error [%w] unmarshaling token audience", err)
+		}
+	}
+	if v.audience != "" && !slices.Contains(audience, v.audience) {
+		return claims, fmt.Errorf("token audience does not contain [%s]", v.audience)
+	}
+
+	// scopes ('scope' as space separated string or 'scp' as array)
+	scopes := strings.Fields(payload.Scope)
+	scopes = append(scopes, payload.Scp...)
+
+	claims = JWTClaims{
+		Issuer:    issuer,
+		Subject:   payload.Sub,
+		Audience:  audience,
+		ExpiresAt: expiresAt,
+		Scopes:    scopes,
+	}
+	return claims, nil
+}
+
+/*
+getKey returns the signing key for the given issuer and key id. The JWKS of the issuer is (re)loaded
+if the cache has expired or the key id is unknown (e.g. after key rotation). The keys are fetched outside
+of the lock, concurrent requests for the same issuer wait for one shared fetch. If the fetch fails, known
+keys of the expired cache are still accepted.
+*/
+func (v *JWTValidator) getKey(ctx context.Context, issuer string, kid string) (crypto.PublicKey, error) {
+	v.mutex.Lock()
+	cached := v.issuers[issuer]
+	if cached != nil {
+		age := time.Since(cached.fetchedAt)
+		if key, found := cached.keys[kid]; found && age < v.jwksCacheTTL {
+			v.mutex.Unlock()
+			return key, nil
+		}
+		if time.Since(cached.attemptedAt) < jwksMinRefreshDelay {
+			v.mutex.Unlock()
+			if key, found := cached.keys[kid]; found {
+				return key, nil
+			}
+			return nil, fmt.Errorf("unknown key id [%s] for issuer [%s]", kid, issuer)
+		}
+	}
+
+	// join fetch in progress or start new fetch (not canceled by the request which started it)
+	fetch := v.fetches[issuer]
+	if fetch == nil {
+		fetch = &jwksFetch{done: make(chan struct{})}
+		if cached != nil {
+			fetch.jwksURI = cached.jwksURI
+		}
+		v.fetches[issuer] = fetch
+		go v.fetchIssuerKeys(context.WithoutCancel(ctx), issuer, fetch)
+	}
+	v.mutex.Unlock()
+
+	select {
+	case <-fetch.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if fetch.err != nil {
+		// identity provider not available: keep accepting known keys of the expired cache
+		if cached != nil {
+			if key, found := cached.keys[kid]; found {
+				slog.WarnContext(ctx, "JWKS refresh failed, using cached key", "error", fetch.err, "issuer", issuer, "kid", kid)
+				return key, nil
+			}
+		}
+		return nil, fetch.err
+	}
+
+	key, found := fetch.keys[kid]
+	if !found {
+		return nil, fmt.Errorf("unknown key id [%s] for issuer [%s]", kid, issuer)
+	}
+	return key, nil
+}
+
+/*
+fetchIssuerKeys fetches the signing keys of an issuer (OpenID Connect discovery if the JWKS URI is not known yet)
+and stores them in the cache.
+*/
+func (v *JWTValidator) fetchIssuerKeys(ctx context.Context, issuer string, fetch *jwksFetch) {
+	defer close(fetch.done)
+
+	fetch.keys, fetch.err = v.loadIssuerKeys(ctx, issuer, fetch)
+
+	v.mutex.Lock()
+	delete(v.fetches, issuer)
+	now := time.Now()
+	if fetch.err == nil {
+		v.issuers[issuer] = &issuerKeys{jwksURI: fetch.jwksURI, keys: fetch.keys, fetchedAt: now, attemptedAt: now}
+	} else if cached := v.issuers[issuer]; cached != nil {
+		// no further fetch within min refresh delay (stale keys remain usable)
+		cached.attemptedAt = now
+	}
+	v.mutex.Unlock()
+
+	if fetch.err == nil {
+		slog.InfoContext(ctx, "JWKS loaded", "issuer", issuer, "keys", len(fetch.keys))
+	}
+}
+
+/*
+loadIssuerKeys discovers the JWKS URI of the issuer (if not known yet) and fetches the JWKS.
+*/
+func (v *JWTValidator) loadIssuerKeys(ctx context.Context, issuer string, fetch *jwksFetch) (map[string]crypto.PublicKey, error) {
+	// discover JWKS URI (OpenID Connect discovery)
+	if fetch.jwksURI == "" {
+		var discovery struct {
+			JWKSURI string `json:"jwks_uri"`
+		}
+		err := v.fetchJSON(ctx, issuer+"/.well-known/openid-configuration", &discovery)
+		if err != nil {
+			return nil, fmt.Errorf("error [%w] at OpenID Connect discovery, issuer: %s", err, issuer)
+		}
+		if discovery.JWKSURI == "" {
+			return nil, fmt.Errorf("no 'jwks_uri' in OpenID Connect discovery, issuer: %s", issuer)
+		}
+		fetch.jwksURI = discovery.JWKSURI
+	}
+
+	keys, err := v.fetchJWKS(ctx, fetch.jwksURI)
+	if err != nil {
+		return nil, fmt.Errorf("error [%w] at fetchJWKS(), issuer: %s", err, issuer)
+	}
+	return keys, nil
+}
+
+/*
+fetchJSON fetches and unmarshals a JSON document.
+*/
+func (v *JWTValidator) fetchJSON(ctx context.Context, url string, target any) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("error [%w] at http.NewRequestWithContext()", err)
+	}
+	response, err := v.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("error [%w] at client.Do()", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected HTTP status [%d], url: %s", response.StatusCode, url)
+	}
+	data, err := io.ReadAll(io.LimitReader(response.Body, maxJWKSResponseSize))
+	if err != nil {
+		return fmt.Errorf("error [%w] at io.ReadAll()", err)
+	}
+	err = json.Unmarshal(data, target)
+	if err != nil {
+		return fmt.Errorf("error [%w] at json.Unmarshal()", err)
+	}
+	return nil
+}
+
+/*
+fetchJWKS fetches the JSON Web Key Set and returns all supported (RSA, EC) signing keys by key id.
+*/
+func (v *JWTValidator) fetchJWKS(ctx context.Context, jwksURI string) (map[string]crypto.PublicKey, error) {
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			Crv string `json:"crv"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"keys"`
+	}
+	err := v.fetchJSON(ctx, jwksURI, &jwks)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]crypto.PublicKey)
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		switch jwk.Kty {
+		case "RSA":
+			n, errN := base64.RawURLEncoding.DecodeString(jwk.N)
+			e, errE := base64.RawURLEncoding.DecodeString(jwk.E)
+			if errN != nil || errE != nil || len(e) > 8 {
+				slog.WarnContext(ctx, "invalid RSA key in JWKS", "kid", jwk.Kid, "jwks uri", jwksURI)
+				continue
+			}
+			keys[jwk.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		case "EC":
+			var curve elliptic.Curve
+			switch jwk.Crv {
+			case "P-256":
+				curve = elliptic.P256()
+			case "P-384":
+				curve = elliptic.P384()
+			case "P-521":
+				curve = elliptic.P521()
+			default:
+				continue
+			}
+			x, errX := base64.RawURLEncoding.DecodeString(jwk.X)
+			y, errY := base64.RawURLEncoding.DecodeString(jwk.Y)
+			if errX != nil || errY != nil {
+				slog.WarnContext(ctx, "invalid EC key in JWKS", "kid", jwk.Kid, "jwks uri", jwksURI)
+				continue
+			}
+			keys[jwk.Kid] = &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		}
+	}
+	return keys, nil
+}
+
+/*
+verifyJWTSignature verifies the JWT signature (RS256/384/512, PS256/384/512, ES256/384/512).
+*/
+func verifyJWTSignature(algorithm string, key crypto.PublicKey, signingInput []byte, signature []byte) error {
+	if len(algorithm) != 5 {
+		return fmt.Errorf("unsupported signature algorithm [%s]", algorithm)
+	}
+	var hashFunc crypto.Hash
+	var hasher hash.Hash
+	switch algorithm[2:] {
+	case "256":
+		hashFunc, hasher = crypto.SHA256, sha256.New()
+	case "384":
+		hashFunc, hasher = crypto.SHA384, sha512.New384()
+	case "512":
+		hashFunc, hasher = crypto.SHA512, sha512.New()
+	default:
+		return fmt.Errorf("unsupported signature algorithm [%s]", algorithm)
+	}
+	hasher.Write(signingInput)
+	digest := hasher.Sum(nil)
+
+	switch {
+	case strings.HasPrefix(algorithm, "RS"), strings.HasPrefix(algorithm, "PS"):
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("key type does not match signature algorithm [%s]", algorithm)
+		}
+		var err error
+		if strings.HasPrefix(algorithm, "RS") {
+			err = rsa.VerifyPKCS1v15(rsaKey, hashFunc, digest, signature)
+		} else {
+			err = rsa.VerifyPSS(rsaKey, hashFunc, digest, signature, nil)
+		}
+		if err != nil {
+			return errors.New("invalid token signature")
+		}
+	case strings.HasPrefix(algorithm, "ES"):
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("key type does not match signature algorithm [%s]", algorithm)
+		}
+		// signature is r || s (fixed length)
+		size := (ecKey.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errors.New("invalid token signature length")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(ecKey, digest, r, s) {
+			return errors.New("invalid token signature")
+		}
+	default:
+		return fmt.Errorf("unsupported signature algorithm [%s]", algorithm)
+	}
+	return nil
+}
+
+// defaultRequiredScopes lists the scopes required for paths without configured rule (service-wide data,
+// a valid token alone is not sufficient)
+var defaultRequiredScopes = map[string][]string{
+	"/v1/stats": {"dtm:stats"},
+}
+
+/*
+getRequiredScopes returns the scopes required for the path (authorization rules, otherwise built-in default rules).
+Returns public = true if the path does not require authentication.
+API v2 paths are authorized like the v1 endpoint they are based on (e.g. /v2/gpxanalyze like /v1/gpxanalyze),
+unless a public path or rule for the v2 path itself is configured.
+*/
+func getRequiredScopes(path string) (scopes []string, public bool) {
+	config := getProgConfig()
+
+	// paths to match: requested path and path of the v1 endpoint (API v2)
+	paths := []string{path}
+	index := slices.IndexFunc(v2Endpoints, func(endpoint V2Endpoint) bool { return endpoint.Path == path })
+	if index >= 0 {
+		paths = append(paths, v2Endpoints[index].Endpoint.Path)
+	}
+
+	for _, matchPath := range paths {
+		if slices.Contains(config.Authentication.PublicPaths, matchPath) {
+			return nil, true
+		}
+		// static files of the web UI (API requests issued by the UI are authorized as usual)
+		if matchPath == "/ui" || strings.HasPrefix(matchPath, "/ui/") {
+			return nil, true
+		}
+		for _, rule := range config.Authentication.Rules {
+			// a rule path ending with '/' applies to all paths below (e.g. /v1/colortables/)
+			if rule.Path == matchPath || (strings.HasSuffix(rule.Path, "/") && strings.HasPrefix(matchPath, rule.Path)) {
+				return rule.Scopes, false
+			}
+		}
+		if scopes, found := defaultRequiredScopes[matchPath]; found {
+			return scopes, false
+		}
+	}
+	return nil, false
+}
+
+/*
+authMiddleware validates the JWT bearer token (if trusted issuers are configured) and checks
+the per-endpoint authorization rules (required scopes).
+*/
+func authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		// authentication disabled or CORS preflight request
+		if jwtValidator == nil || request.Method == http.MethodOptions {
+			next.ServeHTTP(writer, request)
+			return
+		}
+
+		requiredScopes, public := getRequiredScopes(request.URL.Path)
+		if public {
+			next.ServeHTTP(writer, request)
+			return
+		}
+
+		// bearer token
+		authorization := request.Header.Get("Authorization")
+		scheme, token, found := strings.Cut(authorization, " ")
+		if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+			atomic.AddUint64(&AuthenticationFailures, 1)
+			slog.WarnContext(request.Context(), "authentication failed: missing bearer token", "path", request.URL.Path)
+			writeAuthError(writer, http.StatusUnauthorized, `Bearer`, "missing bearer token")
+			return
+		}
+
+		claims, err := jwtValidator.validateToken(request.Context(), strings.TrimSpace(token))
+		if err != nil {
+			atomic.AddUint64(&AuthenticationFailures, 1)
+			slog.WarnContext(request.Context(), "authentication failed: invalid bearer token", "error", err, "path", request.URL.Path)
+			writeAuthError(writer, http.StatusUnauthorized, `Bearer error="invalid_token"`, "invalid bearer token")
+			return
+		}
+
+		// authorization
+		if !hasRequiredScopes(claims.Scopes, requiredScopes) {
+			atomic.AddUint64(&AuthorizationFailures, 1)
+			slog.WarnContext(request.Context(), "authorization failed: insufficient scope", "subject", claims.Subject,
+				"path", request.URL.Path, "required scopes", requiredScopes)
+			writeAuthError(writer, http.StatusForbidden,
+				fmt.Sprintf(`Bearer error="insufficient_scope", scope="%s"`, strings.Join(requiredScopes, " ")), "insufficient scope")
+			return
+		}
+
+		// subject of the token identifies the tenant (usage accounting), scopes are checked again for jobs (target endpoint)
+		ctx := withJWTClaims(withTenant(request.Context(), claims.Subject), claims)
+		next.ServeHTTP(writer, request.WithContext(ctx))
+	})
+}
+
+/*
+writeAuthError sends an authentication or authorization error to the client.
+*/
+func writeAuthError(writer http.ResponseWriter, httpStatus int, challenge string, message string) {
+	writer.Header().Set("WWW-Authenticate", challenge)
+	writer.Header().Set("Content-Type", TextPlainMediaType)
+	writer.WriteHeader(httpStatus)
+	fmt.Fprint(writer, message)
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BenchConfig represents the configuration of a benchmark run (command line options).
+type BenchConfig struct {
+	BaseURL     string
+	Workload    string // point, gpx, hillshade, mixed
+	Requests    int
+	Concurrency int
+	Insecure    bool
+	Seed        int64
+	BoundingBox [4]float64 // minLon, minLat, maxLon, maxLat
+}
+
+// benchResult represents the result of one benchmark request.
+type benchResult struct {
+	workload   string
+	statusCode int
+	duration   time.Duration
+	err        error
+}
+
+/*
+parseBenchBoundingBox parses a bounding box given as 'minLon,minLat,maxLon,maxLat'.
+*/
+func parseBenchBoundingBox(value string) ([4]float64, error) {
+	var bbox [4]float64
+	parts := strings.Split(value, ",")
+	if len(parts) != 4 {
+		return bbox, fmt.Errorf("invalid bounding box [%s], expected 'minLon,minLat,maxLon,maxLat'", value)
+	}
+	for i, part := range parts {
+		number, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return bbox, fmt.Errorf("invalid bounding box value [%s]: %w", part, err)
+		}
+		bbox[i] = number
+	}
+	if bbox[0] >= bbox[2] || bbox[1] >= bbox[3] {
+		return bbox, fmt.Errorf("invalid bounding box [%s], min must be less than max", value)
+	}
+	return bbox, nil
+}
+
+/*
+runBenchmark replays a synthetic workload against a running instance and reports latency percentiles.
+Returns the exit code for the program.
+*/
+func runBenchmark(config BenchConfig) int {
+	workloads := []string{config.Workload}
+	if config.Workload == "mixed" {
+		workloads = []string{"point", "gpx", "hillshade"}
+	}
+	for _, workload := range workloads {
+		switch workload {
+		case "point", "gpx", "hillshade":
+		default:
+			fmt.Fprintf(os.Stderr, "unsupported benchmark workload [%s] (point, gpx, hillshade, mixed)\n", workload)
+			return 1
+		}
+	}
+	if config.Requests <= 0 || config.Concurrency <= 0 {
+		fmt.Fprintf(os.Stderr, "number of requests and concurrency must be greater than 0\n")
+		return 1
+	}
+
+	client := &http.Client{
+		Timeout: 180 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig:     &tls.Config{InsecureSkipVerify: config.Insecure}, //nolint:gosec
+			MaxIdleConnsPerHost: config.Concurrency,
+		},
+	}
+
+	// prepare all request bodies (deterministic for given seed)
+	random := rand.New(rand.NewSource(config.Seed))
+	type benchJob struct {
+		workload string
+		body     []byte
+	}
+	jobs := make(chan benchJob, config.Requests)
+	for i := 0; i < config.Requests; i++ {
+		workload := workloads[i%len(workloads)]
+		jobs <- benchJob{workload: workload, body: buildBenchRequestBody(workload, i, random, config.BoundingBox)}
+	}
+	close(jobs)
+
+	fmt.Printf("benchmark: %s, workload: %s, requests: %d, concurrency: %d, seed: %d\n",
+		config.BaseURL, config.Workload, config.Requests, config.Concurrency, config.Seed)
+
+	results := make(chan benchResult, config.Requests)
+	start := time.Now()
+
+	var waitGroup sync.WaitGroup
+	for w := 0; w < config.Concurrency; w++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for job := range jobs {
+				results <- sendBenchRequest(client, config.BaseURL, job.workload, job.body)
+			}
+		}()
+	}
+	waitGroup.Wait()
+	close(results)
+	elapsed := time.Since(start)
+
+	// collect results per workload
+	durations := make(map[string][]time.Duration)
+	statusCodes := make(map[int]int)
+	failures := 0
+	for result := range results {
+		if result.err != nil {
+			failures++
+			continue
+		}
+		statusCodes[result.statusCode]++
+		durations[result.workload] = append(durations[result.workload], result.duration)
+	}
+
+	// report
+	fmt.Printf("elapsed: %v, throughput: %.1f requests/s, transport errors: %d\n",
+		elapsed.Round(time.Millisecond), float64(config.Requests)/elapsed.Seconds(), failures)
+	codes := make([]int, 0, len(statusCodes))
+	for code := range statusCodes {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		fmt.Printf("HTTP status %d: %d\n", code, statusCodes[code])
+	}
+	fmt.Printf("%-10s %7s %10s %10s %10s %10s %10s %10s\n", "workload", "count", "min", "p50", "p90", "p95", "p99", "max")
+	for _, workload := range workloads {
+		samples := durations[workload]
+		if len(samples) == 0 {
+			continue
+		}
+		sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+		fmt.Printf("%-10s %7d %10v %10v %10v %10v %10v %10v\n", workload, len(samples),
+			samples[0].Round(time.Microsecond*100),
+			percentile(samples, 50).Round(time.Microsecond*100),
+			percentile(samples, 90).Round(time.Microsecond*100),
+			percentile(samples, 95).Round(time.Microsecond*100),
+			percentile(samples, 99).Round(time.Microsecond*100),
+			samples[len(samples)-1].Round(time.Microsecond*100))
+	}
+
+	if failures > 0 || len(codes) != 1 || codes[0] != http.StatusOK {
+		return 1
+	}
+	return 0
+}
+
+/*
+percentile returns the p-th percentile (nearest rank) of sorted durations.
+*/
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	rank = max(rank, 0)
+	rank = min(rank, len(sorted)-1)
+	return sorted[rank]
+}
+
+/*
+sendBenchRequest sends one benchmark request and measures the latency (including reading the body).
+*/
+func sendBenchRequest(client *http.Client, baseURL string, workload string, body []byte) benchResult {
+	result := benchResult{workload: workload}
+
+	request, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(baseURL, "/")+"/v1/"+workload, bytes.NewReader(body))
+	if err != nil {
+		result.err = err
+		return result
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Accept-Encoding", "gzip")
+
+	start := time.Now()
+	response, err := client.Do(request)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	_, err = io.Copy(io.Discard, response.Body)
+	_ = response.Body.Close()
+	result.duration = time.Since(start)
+	result.statusCode = response.StatusCode
+	result.err = err
+
+	return result
+}
+
+/*
+buildBenchRequestBody builds a synthetic request body for the given workload within the bounding box.
+*/
+func buildBenchRequestBody(workload string, index int, random *rand.Rand, bbox [4]float64) []byte {
+	randomLonLat := func() (float64, float64) {
+		return bbox[0] + random.Float64()*(bbox[2]-bbox[0]), bbox[1] + random.Float64()*(bbox[3]-bbox[1])
+	}
+	id := fmt.Sprintf("bench-%s-%d", workload, index)
+
+	var request any
+	switch workload {
+	case "point":
+		pointRequest := PointRequest{Type: TypePointRequest, ID: id}
+		pointRequest.Attributes.Longitude, pointRequest.Attributes.Latitude = randomLonLat()
+		request = pointRequest
+
+	case "gpx":
+		// synthetic track with 500 points (approx. 10 m spacing)
+		lon, lat := randomLonLat()
+		var gpxData strings.Builder
+		gpxData.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+		gpxData.WriteString(`<gpx version="1.1" creator="bench" xmlns="http://www.topografix.com/GPX/1/1"><trk><name>bench</name><trkseg>` + "\n")
+		for i := 0; i < 500; i++ {
+			fmt.Fprintf(&gpxData, `<trkpt lat="%.7f" lon="%.7f"></trkpt>`+"\n", lat+float64(i)*0.00009, lon+float64(i)*0.00009)
+		}
+		gpxData.WriteString("</trkseg></trk></gpx>\n")
+		gpxRequest := GPXRequest{Type: TypeGPXRequest, ID: id}
+		gpxRequest.Attributes.GPXData = base64.StdEncoding.EncodeToString([]byte(gpxData.String()))
+		request = gpxRequest
+
+	case "hillshade":
+		hillshadeRequest := HillshadeRequest{Type: TypeHillshadeRequest, ID: id}
+		hillshadeRequest.Attributes.Longitude, hillshadeRequest.Attributes.Latitude = randomLonLat()
+		hillshadeRequest.Attributes.GradientAlgorithm = "Horn"
+		hillshadeRequest.Attributes.VerticalExaggeration = 1.0
+		hillshadeRequest.Attributes.AzimuthOfLight = 315
+		hillshadeRequest.Attributes.AltitudeOfLight = 45
+		hillshadeRequest.Attributes.ShadingVariant = ShadingVariants{"regular"}
+		request = hillshadeRequest
+	}
+
+	body, _ := json.Marshal(request)
+	return body
+}
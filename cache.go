@@ -0,0 +1,143 @@
+package main
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ResponseCache is an in-memory LRU cache (with TTL) for generated tile objects (e.g. hillshade, slope).
+type ResponseCache struct {
+	mutex        sync.Mutex
+	maxEntries   int
+	maxBytes     int
+	ttl          time.Duration
+	currentBytes int
+	lru          *list.List
+	items        map[string]*list.Element
+}
+
+// responseCacheEntry represents one entry in response cache
+type responseCacheEntry struct {
+	key     string
+	value   any
+	size    int
+	expires time.Time
+}
+
+// responseCache is the global response cache (nil if disabled)
+var responseCache *ResponseCache
+
+/*
+newResponseCache creates a new response cache. A cache with maxEntries <= 0 is disabled (nil).
+*/
+func newResponseCache(maxEntries int, maxBytes int, ttl time.Duration) *ResponseCache {
+	if maxEntries <= 0 {
+		return nil
+	}
+	return &ResponseCache{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		ttl:        ttl,
+		lru:        list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+/*
+buildResponseCacheKey builds a cache key from all parameters which determine a generated object.
+*/
+func buildResponseCacheKey(parts ...any) string {
+	var builder strings.Builder
+	for _, part := range parts {
+		fmt.Fprintf(&builder, "%v|", part)
+	}
+	hash := sha256.Sum256([]byte(builder.String()))
+	return hex.EncodeToString(hash[:])
+}
+
+/*
+Get returns the cached value for key (if present and not expired).
+*/
+func (cache *ResponseCache) Get(key string) (any, bool) {
+	if cache == nil {
+		return nil, false
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	element, found := cache.items[key]
+	if !found {
+		atomic.AddUint64(&ResponseCacheMisses, 1)
+		return nil, false
+	}
+	entry := element.Value.(*responseCacheEntry)
+	if cache.ttl > 0 && time.Now().After(entry.expires) {
+		cache.removeElement(element)
+		atomic.AddUint64(&ResponseCacheMisses, 1)
+		return nil, false
+	}
+
+	cache.lru.MoveToFront(element)
+	atomic.AddUint64(&ResponseCacheHits, 1)
+	return entry.value, true
+}
+
+/*
+Add adds (or replaces) value for key. Size is the approximate memory size of value in bytes.
+*/
+func (cache *ResponseCache) Add(key string, value any, size int) {
+	if cache == nil {
+		return
+	}
+	if cache.maxBytes > 0 && size > cache.maxBytes {
+		// object too large for cache
+		return
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if element, found := cache.items[key]; found {
+		cache.removeElement(element)
+	}
+
+	entry := &responseCacheEntry{key: key, value: value, size: size, expires: time.Now().Add(cache.ttl)}
+	cache.items[key] = cache.lru.PushFront(entry)
+	cache.currentBytes += size
+
+	// evict least recently used entries
+	for cache.lru.Len() > cache.maxEntries || (cache.maxBytes > 0 && cache.currentBytes > cache.maxBytes) {
+		cache.removeElement(cache.lru.Back())
+	}
+}
+
+/*
+removeElement removes element from cache (caller must hold mutex).
+*/
+func (cache *ResponseCache) removeElement(element *list.Element) {
+	entry := element.Value.(*responseCacheEntry)
+	cache.lru.Remove(element)
+	delete(cache.items, entry.key)
+	cache.currentBytes -= entry.size
+}
+
+/*
+Len returns the number of entries and the approximate size (bytes) of the cache.
+*/
+func (cache *ResponseCache) Len() (int, int) {
+	if cache == nil {
+		return 0, 0
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	return cache.lru.Len(), cache.currentBytes
+}
@@ -0,0 +1,384 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"klaus/elevation/dtm-elevation-service/pkg/dtm"
+)
+
+// TypeCapabilitiesResponse is the type of the capabilities response
+const TypeCapabilitiesResponse = "CapabilitiesResponse"
+
+// CapabilitiesResponse describes the service (endpoints, output formats, coverage, limits, data actuality).
+type CapabilitiesResponse struct {
+	Type       string
+	ID         string
+	Attributes struct {
+		Version   string
+		Endpoints []CapabilityEndpoint
+		Coverage  CapabilityCoverage
+		Limits    CapabilityLimits
+	}
+}
+
+// CapabilityEndpoint represents an endpoint of the service.
+type CapabilityEndpoint struct {
+	Name               string   // symbolic name, e.g. HILLSHADE
+	Path               string   // v1 path (empty for job only endpoints)
+	PathV2             string   `json:",omitempty"` // JSON:API v2 path
+	Methods            []string // e.g. GET, POST
+	MaxRequestBodySize int64    // bytes
+	OutputFormats      []string // formats of the generated data
+	JobCapable         bool     // request can be submitted as asynchronous job (POST /v1/jobs)
+}
+
+// CapabilityCoverage represents the data coverage (all elevation sources).
+type CapabilityCoverage struct {
+	Tiles           int              // number of tiles (1 km x 1 km) incl. duplicate tiles at state borders
+	BoundingBox     WGS84BoundingBox // extent of all tiles
+	OldestActuality string           // oldest Airborne Laser Scanning (ALS) date
+	NewestActuality string           // newest Airborne Laser Scanning (ALS) date
+	Sources         []CapabilitySource
+}
+
+// CapabilitySource represents the coverage and data actuality of an elevation source (federal state).
+type CapabilitySource struct {
+	Code            string // e.g. DE-NW
+	Name            string // e.g. Nordrhein-Westfalen
+	Attribution     string
+	Disabled        bool // source disabled by configuration (e.g. during re-delivery of data)
+	Tiles           int
+	BoundingBox     WGS84BoundingBox
+	OldestActuality string
+	NewestActuality string
+}
+
+// CapabilityLimits represents the limits of the service.
+type CapabilityLimits struct {
+	MaxExportTiles         int // max tiles of an export
+	MaxContoursExportTiles int // max tiles of a contours export (GeoPackage)
+	MaxContoursAreaTiles   int // max tiles of an area (contours, hillshade, slope)
+	MaxJobs                int // max stored jobs
+	JobResultTTL           int // seconds
+	MaxColorTables         int // max named color tables per tenant
+	GDALJobQueue           struct {
+		MaxParallelJobs int
+		MaxQueueWait    int // seconds
+		RetryAfter      int // seconds
+	}
+	AbuseProtection struct {
+		Enabled       bool
+		Window        int // seconds
+		MaxViolations int // violations (e.g. rejected requests) within window before client is banned
+		BanDuration   int // seconds
+	}
+	WorkerPools  map[string]int // max concurrent requests or GDAL jobs per endpoint class (0 = not limited by class)
+	LoadShedding struct {
+		Enabled    bool
+		Endpoints  []string // endpoints rejected with HTTP 503 while the server is overloaded
+		RetryAfter int      // seconds
+	}
+}
+
+// endpointOutputFormats lists the formats of the generated data per endpoint
+var endpointOutputFormats = map[*ErrorEndpoint][]string{
+	EndpointPoint:            {"json", "text"},
+	EndpointUTMPoint:         {"json"},
+	EndpointGPX:              {"gpx"},
+	EndpointGPXAnalyze:       {"json"},
+	EndpointContours:         {"geojson"},
+	EndpointHillshade:        {"geotiff", "png"},
+	EndpointSlope:            {"geotiff", "png"},
+	EndpointAspect:           {"geotiff", "png"},
+	EndpointTPI:              {"geotiff", "png"},
+	EndpointTRI:              {"geotiff", "png"},
+	EndpointRoughness:        {"geotiff", "png"},
+	EndpointRawTIF:           {"geotiff"},
+	EndpointColorRelief:      {"geotiff", "png"},
+	EndpointHistogram:        {"json"},
+	EndpointElevationProfile: {"json", "png", "svg"},
+	EndpointVisualize:        {"geotiff", "png"},
+	EndpointExport:           {"zip"},
+	EndpointCompare:          {"json"},
+	EndpointAspectRose:       {"json"},
+	EndpointElevationRange:   {"geojson"},
+	EndpointContourLine:      {"geojson"},
+	EndpointElevationMatrix:  {"json", "float32le"},
+	EndpointLegend:           {"png", "svg"},
+	EndpointContoursExport:   {"gpkg"},
+}
+
+// coverage summary (computed once per repository, replaced on reload)
+var (
+	capabilitiesMutex      sync.Mutex
+	capabilitiesRepository *dtm.Repository
+	capabilitiesCoverage   CapabilityCoverage
+)
+
+/*
+capabilitiesRequest handles 'capabilities' request (discovery of endpoints, output formats, coverage, limits
+and data actuality). Client applications can configure themselves dynamically.
+*/
+func capabilitiesRequest(writer http.ResponseWriter, request *http.Request) {
+	config := getProgConfig()
+
+	capabilitiesResponse := CapabilitiesResponse{Type: TypeCapabilitiesResponse, ID: progVersion}
+	capabilitiesResponse.Attributes.Version = progVersion
+	capabilitiesResponse.Attributes.Endpoints = getCapabilityEndpoints()
+	capabilitiesResponse.Attributes.Coverage = getCapabilityCoverage(config.DisabledSources)
+
+	limits := &capabilitiesResponse.Attributes.Limits
+	limits.MaxExportTiles = config.Export.MaxTiles
+	limits.MaxContoursExportTiles = config.Export.MaxContoursTiles
+	limits.MaxContoursAreaTiles = maxAreaTiles
+	limits.MaxJobs = config.Jobs.MaxJobs
+	limits.JobResultTTL = config.Jobs.ResultTTL
+	limits.MaxColorTables = config.ColorTables.MaxTablesPerTenant
+	limits.GDALJobQueue.MaxParallelJobs = config.GDALJobQueue.MaxParallelJobs
+	limits.GDALJobQueue.MaxQueueWait = config.GDALJobQueue.MaxQueueWait
+	limits.GDALJobQueue.RetryAfter = config.GDALJobQueue.RetryAfter
+	limits.AbuseProtection.Enabled = config.AbuseProtection.Enabled
+	limits.AbuseProtection.Window = config.AbuseProtection.Window
+	limits.AbuseProtection.MaxViolations = config.AbuseProtection.MaxViolations
+	limits.AbuseProtection.BanDuration = config.AbuseProtection.BanDuration
+	limits.WorkerPools = map[string]int{
+		EndpointClassPoints:   config.WorkerPools.Points.MaxParallel,
+		EndpointClassGPX:      config.WorkerPools.GPX.MaxParallel,
+		EndpointClassRaster:   config.WorkerPools.Raster.MaxParallel,
+		EndpointClassContours: config.WorkerPools.Contours.MaxParallel,
+	}
+	limits.LoadShedding.Enabled = config.LoadShedding.Enabled
+	for _, endpoint := range loadSheddingEndpoints {
+		limits.LoadShedding.Endpoints = append(limits.LoadShedding.Endpoints, endpoint.Path)
+	}
+	limits.LoadShedding.RetryAfter = config.LoadShedding.RetryAfter
+
+	streamJSONResponse(writer, request, http.StatusOK, capabilitiesResponse, false)
+}
+
+/*
+getCapabilityEndpoints returns all processing endpoints (see v2Endpoints and jobOnlyEndpoints), the jobs endpoint and
+the color tables endpoint.
+*/
+func getCapabilityEndpoints() []CapabilityEndpoint {
+	var endpoints []CapabilityEndpoint
+	for _, v2Endpoint := range slices.Concat(v2Endpoints, jobOnlyEndpoints) {
+		path := v2Endpoint.Endpoint.Path
+		if v2Endpoint.Path == "" {
+			// job only endpoint (no route)
+			path = ""
+		}
+		endpoint := CapabilityEndpoint{
+			Name:               v2Endpoint.Endpoint.Name,
+			Path:               path,
+			PathV2:             v2Endpoint.Path,
+			Methods:            v2Endpoint.Methods,
+			MaxRequestBodySize: v2Endpoint.MaxBodySize,
+			OutputFormats:      endpointOutputFormats[v2Endpoint.Endpoint],
+			JobCapable:         slices.Contains(v2Endpoint.Methods, http.MethodPost),
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	endpoints = append(endpoints, CapabilityEndpoint{
+		Name:               EndpointJobs.Name,
+		Path:               EndpointJobs.Path,
+		Methods:            []string{http.MethodGet, http.MethodPost, http.MethodDelete},
+		MaxRequestBodySize: MaxJobRequestBodySize,
+		OutputFormats:      []string{"json"},
+	})
+	endpoints = append(endpoints, CapabilityEndpoint{
+		Name:               EndpointColorTables.Name,
+		Path:               EndpointColorTables.Path + "/{name}",
+		Methods:            []string{http.MethodGet, http.MethodPost, http.MethodDelete},
+		MaxRequestBodySize: MaxColorTableRequestBodySize,
+		OutputFormats:      []string{"json"},
+	})
+	return endpoints
+}
+
+/*
+getCapabilityCoverage returns the data coverage of the current repository (cached until the repository is replaced).
+*/
+func getCapabilityCoverage(disabledSources []string) CapabilityCoverage {
+	repositoryMutex.RLock()
+	repository := Repository
+	repositoryMutex.RUnlock()
+
+	capabilitiesMutex.Lock()
+	defer capabilitiesMutex.Unlock()
+
+	if repository != capabilitiesRepository || capabilitiesCoverage.Sources == nil {
+		capabilitiesCoverage = buildCapabilityCoverage(repository)
+		capabilitiesRepository = repository
+	}
+
+	// tiles of disabled sources are not part of the coverage (source listed without tiles)
+	coverage := capabilitiesCoverage
+	coverage.Sources = slices.Clone(capabilitiesCoverage.Sources)
+	for _, code := range disabledSources {
+		source := CapabilitySource{Code: code, Name: code, Disabled: true}
+		if resource, err := getElevationResource(code); err == nil {
+			source.Name = resource.Name
+			source.Attribution = resource.Attribution
+		}
+		coverage.Sources = append(coverage.Sources, source)
+	}
+	return coverage
+}
+
+// utmExtent represents the extent (in km) of tiles in an UTM zone
+type utmExtent struct {
+	minEasting, maxEasting, minNorthing, maxNorthing int
+}
+
+/*
+buildCapabilityCoverage summarizes the tiles of the repository per elevation source (number of tiles, extent,
+data actuality). The extent is derived from the tile indices (zone_easting_northing in km).
+*/
+func buildCapabilityCoverage(repository *dtm.Repository) CapabilityCoverage {
+	coverage := CapabilityCoverage{Sources: []CapabilitySource{}}
+	sources := make(map[string]*CapabilitySource)
+	extents := make(map[string]map[int]*utmExtent)
+
+	for _, index := range repository.Indices() {
+		tile, found := repository.Tile(index)
+		if !found {
+			continue
+		}
+		source, exists := sources[tile.Source]
+		if !exists {
+			source = &CapabilitySource{Code: tile.Source, Name: tile.Source}
+			if resource, err := getElevationResource(tile.Source); err == nil {
+				source.Name = resource.Name
+				source.Attribution = resource.Attribution
+			}
+			sources[tile.Source] = source
+			extents[tile.Source] = make(map[int]*utmExtent)
+		}
+		source.Tiles++
+		coverage.Tiles++
+		source.OldestActuality = minActuality(source.OldestActuality, tile.Actuality)
+		source.NewestActuality = maxActuality(source.NewestActuality, tile.Actuality)
+
+		zone, easting, northing, err := parseTileIndex(index)
+		if err != nil {
+			slog.Warn("capabilities: error parsing tile index", "error", err, "index", index)
+			continue
+		}
+		extent, exists := extents[tile.Source][zone]
+		if !exists {
+			extents[tile.Source][zone] = &utmExtent{easting, easting, northing, northing}
+			continue
+		}
+		extent.minEasting = min(extent.minEasting, easting)
+		extent.maxEasting = max(extent.maxEasting, easting)
+		extent.minNorthing = min(extent.minNorthing, northing)
+		extent.maxNorthing = max(extent.maxNorthing, northing)
+	}
+
+	coverage.BoundingBox = emptyBoundingBox()
+	for _, source := range sources {
+		source.BoundingBox = emptyBoundingBox()
+		for zone, extent := range extents[source.Code] {
+			// corners of extent (tile index is lower left corner of 1 km tile)
+			corners := [][2]float64{
+				{float64(extent.minEasting) * 1000, float64(extent.minNorthing) * 1000},
+				{float64(extent.minEasting) * 1000, float64(extent.maxNorthing+1) * 1000},
+				{float64(extent.maxEasting+1) * 1000, float64(extent.minNorthing) * 1000},
+				{float64(extent.maxEasting+1) * 1000, float64(extent.maxNorthing+1) * 1000},
+			}
+			for _, corner := range corners {
+				longitude, latitude, err := transformUTMToLonLat(corner[0], corner[1], zone)
+				if err != nil {
+					slog.Warn("capabilities: error transforming UTM coordinates", "error", err, "zone", zone)
+					continue
+				}
+				extendBoundingBox(&source.BoundingBox, longitude, latitude)
+				extendBoundingBox(&coverage.BoundingBox, longitude, latitude)
+			}
+		}
+		finishBoundingBox(&source.BoundingBox)
+		coverage.OldestActuality = minActuality(coverage.OldestActuality, source.OldestActuality)
+		coverage.NewestActuality = maxActuality(coverage.NewestActuality, source.NewestActuality)
+		coverage.Sources = append(coverage.Sources, *source)
+	}
+
+	finishBoundingBox(&coverage.BoundingBox)
+
+	slices.SortFunc(coverage.Sources, func(a, b CapabilitySource) int {
+		return strings.Compare(a.Code, b.Code)
+	})
+	return coverage
+}
+
+/*
+parseTileIndex parses the tile index (e.g. 32_383_5802 or 32_383_5802_2) into zone, easting and northing (km).
+*/
+func parseTileIndex(index string) (int, int, int, error) {
+	parts := strings.Split(index, "_")
+	if len(parts) < 3 {
+		return 0, 0, 0, fmt.Errorf("invalid tile index [%s]", index)
+	}
+	var values [3]int
+	for i := range values {
+		value, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("error [%w] at strconv.Atoi(), tile index: %s", err, index)
+		}
+		values[i] = value
+	}
+	return values[0], values[1], values[2], nil
+}
+
+/*
+emptyBoundingBox returns a bounding box which is extended by the first coordinates.
+*/
+func emptyBoundingBox() WGS84BoundingBox {
+	return WGS84BoundingBox{MinLon: math.Inf(1), MinLat: math.Inf(1), MaxLon: math.Inf(-1), MaxLat: math.Inf(-1)}
+}
+
+/*
+extendBoundingBox extends the bounding box by the given coordinates.
+*/
+func extendBoundingBox(box *WGS84BoundingBox, longitude, latitude float64) {
+	box.MinLon = math.Min(box.MinLon, longitude)
+	box.MinLat = math.Min(box.MinLat, latitude)
+	box.MaxLon = math.Max(box.MaxLon, longitude)
+	box.MaxLat = math.Max(box.MaxLat, latitude)
+}
+
+/*
+finishBoundingBox resets a bounding box without coordinates (infinite values are not valid JSON).
+*/
+func finishBoundingBox(box *WGS84BoundingBox) {
+	if math.IsInf(box.MinLon, 0) || math.IsInf(box.MinLat, 0) {
+		*box = WGS84BoundingBox{}
+	}
+}
+
+/*
+minActuality returns the older actuality (ISO dates, empty = unknown).
+*/
+func minActuality(a, b string) string {
+	if a == "" || (b != "" && b < a) {
+		return b
+	}
+	return a
+}
+
+/*
+maxActuality returns the newer actuality (ISO dates, empty = unknown).
+*/
+func maxActuality(a, b string) string {
+	if b > a {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,458 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tkrajina/gpxgo/gpx"
+)
+
+// default base URL of remote instance (command line client mode)
+const cliDefaultURL = "https://api.hoehendaten.de:14444"
+
+// cliCommands lists the subcommands of the command line client mode
+var cliCommands = []struct {
+	Name    string
+	Usage   string
+	Command func(args []string) error
+}{
+	{"point", "point [options] --lon <lon> --lat <lat> | --place <name>", cliPoint},
+	{"utmpoint", "utmpoint [options] --zone <32|33> --easting <e> --northing <n>", cliUTMPoint},
+	{"gpx", "gpx [options] [--out <file>] <file.gpx>", cliGPX},
+	{"request", "request [options] --endpoint <path> [--dir <directory>] <request.json>", cliRequest},
+}
+
+// cliOptions represents the common options of all subcommands
+type cliOptions struct {
+	URL      string // base URL of remote instance
+	Insecure bool   // skip TLS certificate verification
+	Local    bool   // operate on local tile repository (configuration file) instead of remote instance
+	Config   string // configuration file (local mode)
+	JSON     bool   // print response as JSON
+}
+
+/*
+isCLICommand checks if the first command line argument is a subcommand of the command line client mode.
+*/
+func isCLICommand(name string) bool {
+	for _, command := range cliCommands {
+		if command.Name == name {
+			return true
+		}
+	}
+	return name == "help"
+}
+
+/*
+runCLI runs a subcommand of the command line client mode and returns the exit code.
+Subcommands call a remote instance (default) or operate directly on the local tile repository (--local).
+*/
+func runCLI(name string, args []string) int {
+	for _, command := range cliCommands {
+		if command.Name != name {
+			continue
+		}
+		err := command.Command(args)
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s %s: %v\n", progName, name, err)
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Fprintf(os.Stderr, "Usage:\n")
+	fmt.Fprintf(os.Stderr, "  %s                 (run service, configuration %s)\n", progName, getProgConfigFile())
+	for _, command := range cliCommands {
+		fmt.Fprintf(os.Stderr, "  %s %s\n", progName, command.Usage)
+	}
+	fmt.Fprintf(os.Stderr, "\nCommon options: --url <base URL> (default %s, env DTM_SERVICE_URL), --insecure,\n", cliDefaultURL)
+	fmt.Fprintf(os.Stderr, "                --local (local tile repository), --config <file> (local mode), --json\n")
+	if name == "help" {
+		return 0
+	}
+	return 2
+}
+
+/*
+newCLIFlagSet creates the flag set of a subcommand with the common options.
+*/
+func newCLIFlagSet(name string, options *cliOptions) *flag.FlagSet {
+	flagSet := flag.NewFlagSet(progName+" "+name, flag.ContinueOnError)
+	defaultURL := os.Getenv("DTM_SERVICE_URL")
+	if defaultURL == "" {
+		defaultURL = cliDefaultURL
+	}
+	flagSet.StringVar(&options.URL, "url", defaultURL, "base URL of remote instance")
+	flagSet.BoolVar(&options.Insecure, "insecure", false, "skip TLS certificate verification")
+	flagSet.BoolVar(&options.Local, "local", false, "operate on local tile repository (see --config)")
+	flagSet.StringVar(&options.Config, "config", getProgConfigFile(), "configuration file with tile repositories (local mode)")
+	flagSet.BoolVar(&options.JSON, "json", false, "print response as JSON")
+	return flagSet
+}
+
+/*
+initCLILocal loads the configuration and builds the tile repository (local mode).
+*/
+func initCLILocal(options cliOptions) error {
+	config, _, err := loadProgConfig(options.Config)
+	if err != nil {
+		return fmt.Errorf("error [%w] at loadProgConfig(), file: %s", err, options.Config)
+	}
+	activeProgConfig.Store(&config)
+
+	// warnings and errors to stderr only
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})))
+
+	err = buildRepository(config.TileRepositories, config.DisabledSources)
+	if err != nil {
+		return fmt.Errorf("error [%w] at buildRepository()", err)
+	}
+	return nil
+}
+
+/*
+cliPoint prints the elevation for a point (lon/lat coordinates or place name).
+*/
+func cliPoint(args []string) error {
+	var options cliOptions
+	flagSet := newCLIFlagSet("point", &options)
+	longitude := flagSet.Float64("lon", 0, "longitude (WGS84)")
+	latitude := flagSet.Float64("lat", 0, "latitude (WGS84)")
+	place := flagSet.String("place", "", "place name (e.g. Feldberg), geocoded by remote instance")
+	err := flagSet.Parse(args)
+	if err != nil {
+		return err
+	}
+	if *place == "" && (*longitude == 0 || *latitude == 0) {
+		return errors.New("--lon and --lat (or --place) required")
+	}
+
+	pointRequest := PointRequest{Type: TypePointRequest, ID: "cli"}
+	pointRequest.Attributes.Longitude = *longitude
+	pointRequest.Attributes.Latitude = *latitude
+	pointRequest.Attributes.Place = *place
+
+	pointResponse := PointResponse{Type: TypePointResponse, ID: pointRequest.ID}
+	if options.Local {
+		if *place != "" {
+			return errors.New("--place not supported in local mode")
+		}
+		err = initCLILocal(options)
+		if err != nil {
+			return err
+		}
+		elevation, tile, err := getElevationForPoint(*longitude, *latitude)
+		if err != nil {
+			return err
+		}
+		pointResponse.Attributes.Longitude = *longitude
+		pointResponse.Attributes.Latitude = *latitude
+		pointResponse.Attributes.Elevation = elevation
+		pointResponse.Attributes.Actuality = tile.Actuality
+		pointResponse.Attributes.Origin = tile.Source
+		pointResponse.Attributes.TileIndex = tile.Index
+		if resource, err := getElevationResource(tile.Source); err == nil {
+			pointResponse.Attributes.Attribution = resource.Attribution
+		}
+	} else {
+		err = cliPostRequest(options, "/v1/point", pointRequest, &pointResponse)
+		if err != nil {
+			return err
+		}
+		if pointResponse.Attributes.IsError {
+			return fmt.Errorf("error %s: %s (%s)", pointResponse.Attributes.Error.Code, pointResponse.Attributes.Error.Title, pointResponse.Attributes.Error.Detail)
+		}
+	}
+
+	if options.JSON {
+		return cliPrintJSON(pointResponse)
+	}
+	fmt.Printf("%.2f m (tile %s, source %s, actuality %s)\n", pointResponse.Attributes.Elevation,
+		pointResponse.Attributes.TileIndex, pointResponse.Attributes.Origin, pointResponse.Attributes.Actuality)
+	return nil
+}
+
+/*
+cliUTMPoint prints the elevation for a point in UTM coordinates.
+*/
+func cliUTMPoint(args []string) error {
+	var options cliOptions
+	flagSet := newCLIFlagSet("utmpoint", &options)
+	zone := flagSet.Int("zone", 32, "UTM zone (32 or 33)")
+	easting := flagSet.Float64("easting", 0, "easting (UTM)")
+	northing := flagSet.Float64("northing", 0, "northing (UTM)")
+	err := flagSet.Parse(args)
+	if err != nil {
+		return err
+	}
+	if *easting == 0 || *northing == 0 {
+		return errors.New("--easting and --northing required")
+	}
+
+	utmPointRequest := UTMPointRequest{Type: TypeUTMPointRequest, ID: "cli"}
+	utmPointRequest.Attributes.Zone = *zone
+	utmPointRequest.Attributes.Easting = *easting
+	utmPointRequest.Attributes.Northing = *northing
+
+	utmPointResponse := UTMPointResponse{Type: TypeUTMPointResponse, ID: utmPointRequest.ID}
+	if options.Local {
+		err = initCLILocal(options)
+		if err != nil {
+			return err
+		}
+		elevation, tile, err := getElevationForUTMPoint(*zone, *easting, *northing)
+		if err != nil {
+			return err
+		}
+		utmPointResponse.Attributes.Zone = *zone
+		utmPointResponse.Attributes.Easting = *easting
+		utmPointResponse.Attributes.Northing = *northing
+		utmPointResponse.Attributes.Elevation = elevation
+		utmPointResponse.Attributes.Actuality = tile.Actuality
+		utmPointResponse.Attributes.Origin = tile.Source
+		utmPointResponse.Attributes.TileIndex = tile.Index
+		if resource, err := getElevationResource(tile.Source); err == nil {
+			utmPointResponse.Attributes.Attribution = resource.Attribution
+		}
+	} else {
+		err = cliPostRequest(options, "/v1/utmpoint", utmPointRequest, &utmPointResponse)
+		if err != nil {
+			return err
+		}
+		if utmPointResponse.Attributes.IsError {
+			return fmt.Errorf("error %s: %s (%s)", utmPointResponse.Attributes.Error.Code, utmPointResponse.Attributes.Error.Title, utmPointResponse.Attributes.Error.Detail)
+		}
+	}
+
+	if options.JSON {
+		return cliPrintJSON(utmPointResponse)
+	}
+	fmt.Printf("%.2f m (tile %s, source %s, actuality %s)\n", utmPointResponse.Attributes.Elevation,
+		utmPointResponse.Attributes.TileIndex, utmPointResponse.Attributes.Origin, utmPointResponse.Attributes.Actuality)
+	return nil
+}
+
+/*
+cliGPX adds elevations to all points of a GPX file and writes the result to a new GPX file.
+*/
+func cliGPX(args []string) error {
+	var options cliOptions
+	flagSet := newCLIFlagSet("gpx", &options)
+	outputFile := flagSet.String("out", "", "output file (default: <input>-dtm.gpx)")
+	err := flagSet.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flagSet.NArg() != 1 {
+		return errors.New("one GPX file required")
+	}
+	inputFile := flagSet.Arg(0)
+	if *outputFile == "" {
+		*outputFile = strings.TrimSuffix(inputFile, filepath.Ext(inputFile)) + "-dtm.gpx"
+	}
+
+	data, err := os.ReadFile(inputFile)
+	if err != nil {
+		return fmt.Errorf("error [%w] at os.ReadFile()", err)
+	}
+
+	var gpxPoints, dgmPoints int
+	var result []byte
+	if options.Local {
+		err = initCLILocal(options)
+		if err != nil {
+			return err
+		}
+		gpxData, err := gpx.ParseBytes(data)
+		if err != nil {
+			return fmt.Errorf("error [%w] at gpx.ParseBytes()", err)
+		}
+		processedGpxData, _, points, elevationPoints, _, err := addElevationToGPX(gpxData, "cli", allGPXPointTypes)
+		if err != nil {
+			return fmt.Errorf("error [%w] at addElevationToGPX()", err)
+		}
+		result, err = processedGpxData.ToXml(gpx.ToXmlParams{Indent: true})
+		if err != nil {
+			return fmt.Errorf("error [%w] at gpx.ToXml()", err)
+		}
+		gpxPoints, dgmPoints = points, elevationPoints
+	} else {
+		gpxRequest := GPXRequest{Type: TypeGPXRequest, ID: filepath.Base(inputFile)}
+		gpxRequest.Attributes.GPXData = base64.StdEncoding.EncodeToString(data)
+		gpxResponse := GPXResponse{}
+		err = cliPostRequest(options, "/v1/gpx", gpxRequest, &gpxResponse)
+		if err != nil {
+			return err
+		}
+		if gpxResponse.Attributes.IsError {
+			return fmt.Errorf("error %s: %s (%s)", gpxResponse.Attributes.Error.Code, gpxResponse.Attributes.Error.Title, gpxResponse.Attributes.Error.Detail)
+		}
+		result, err = base64.StdEncoding.DecodeString(gpxResponse.Attributes.GPXData)
+		if err != nil {
+			return fmt.Errorf("error [%w] at base64.DecodeString()", err)
+		}
+		gpxPoints, dgmPoints = gpxResponse.Attributes.GPXPoints, gpxResponse.Attributes.DGMPoints
+	}
+
+	err = os.WriteFile(*outputFile, result, 0644)
+	if err != nil {
+		return fmt.Errorf("error [%w] at os.WriteFile()", err)
+	}
+	fmt.Printf("%s: %d of %d points with elevation\n", *outputFile, dgmPoints, gpxPoints)
+	return nil
+}
+
+/*
+cliRequest posts a request (JSON file) to an endpoint of a remote instance and saves all objects with data
+(e.g. hillshade, contours) as files in the output directory.
+*/
+func cliRequest(args []string) error {
+	var options cliOptions
+	flagSet := newCLIFlagSet("request", &options)
+	endpoint := flagSet.String("endpoint", "", "endpoint (e.g. /v1/hillshade)")
+	directory := flagSet.String("dir", ".", "output directory for objects with data (e.g. GeoTIFF, PNG, GeoJSON)")
+	err := flagSet.Parse(args)
+	if err != nil {
+		return err
+	}
+	if *endpoint == "" || flagSet.NArg() != 1 {
+		return errors.New("--endpoint and one request file required")
+	}
+	if options.Local {
+		return errors.New("--local not supported for generic requests")
+	}
+
+	data, err := os.ReadFile(flagSet.Arg(0))
+	if err != nil {
+		return fmt.Errorf("error [%w] at os.ReadFile()", err)
+	}
+	var response map[string]any
+	err = cliPostRequest(options, *endpoint, json.RawMessage(data), &response)
+	if err != nil {
+		return err
+	}
+
+	// save objects with data (e.g. Attributes.Hillshades[].Data + Filename)
+	files, err := cliSaveObjects(response, *directory)
+	if err != nil {
+		return err
+	}
+	if options.JSON || len(files) == 0 {
+		return cliPrintJSON(response)
+	}
+	for _, file := range files {
+		fmt.Println(file)
+	}
+	return nil
+}
+
+/*
+cliSaveObjects saves all objects with 'Data' and 'Filename' (recursively) in the directory.
+*/
+func cliSaveObjects(value any, directory string) ([]string, error) {
+	var files []string
+
+	switch typed := value.(type) {
+	case map[string]any:
+		data, hasData := typed["Data"].(string)
+		filename, hasFilename := typed["Filename"].(string)
+		if hasData && hasFilename && data != "" && filename != "" {
+			content, err := base64.StdEncoding.DecodeString(data)
+			if err != nil {
+				return files, fmt.Errorf("error [%w] at base64.DecodeString(), file: %s", err, filename)
+			}
+			path := filepath.Join(directory, filepath.Base(filename))
+			err = os.WriteFile(path, content, 0644)
+			if err != nil {
+				return files, fmt.Errorf("error [%w] at os.WriteFile()", err)
+			}
+			files = append(files, path)
+			delete(typed, "Data")
+		}
+		for _, element := range typed {
+			saved, err := cliSaveObjects(element, directory)
+			files = append(files, saved...)
+			if err != nil {
+				return files, err
+			}
+		}
+	case []any:
+		for _, element := range typed {
+			saved, err := cliSaveObjects(element, directory)
+			files = append(files, saved...)
+			if err != nil {
+				return files, err
+			}
+		}
+	}
+
+	return files, nil
+}
+
+/*
+cliPostRequest posts a request to an endpoint of the remote instance and decodes the response.
+Error responses (JSON with error object) are decoded as well.
+*/
+func cliPostRequest(options cliOptions, endpoint string, requestData any, responseData any) error {
+	body, err := json.Marshal(requestData)
+	if err != nil {
+		return fmt.Errorf("error [%w] at json.Marshal()", err)
+	}
+
+	request, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(options.URL, "/")+endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error [%w] at http.NewRequest()", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("User-Agent", progName+"/"+progVersion+" (cli)")
+
+	client := &http.Client{
+		Timeout: 5 * time.Minute,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: options.Insecure}, //nolint:gosec
+		},
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("error [%w] at client.Do()", err)
+	}
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("error [%w] at io.ReadAll()", err)
+	}
+	err = json.Unmarshal(data, responseData)
+	if err != nil {
+		return fmt.Errorf("HTTP status [%d], unexpected response: %s", response.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return nil
+}
+
+/*
+cliPrintJSON prints a value as indented JSON.
+*/
+func cliPrintJSON(value any) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error [%w] at json.MarshalIndent()", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
@@ -1,18 +1,23 @@
 package main
 
 import (
-	"bytes"
-	"compress/gzip"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"math"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync/atomic"
+
+	"github.com/airbusgeo/godal"
+
+	"klaus/elevation/dtm-elevation-service/pkg/dtm"
 )
 
 /*
@@ -34,18 +39,14 @@ func colorReliefRequest(writer http.ResponseWriter, request *http.Request) {
 		// check specifically for the error returned by MaxBytesReader
 		var maxBytesErr *http.MaxBytesError
 		if errors.As(err, &maxBytesErr) {
-			slog.Warn("color relief request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
-			colorReliefResponse.Attributes.Error.Code = "12000"
-			colorReliefResponse.Attributes.Error.Title = "request body too large"
-			colorReliefResponse.Attributes.Error.Detail = fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit)
-			buildColorReliefResponse(writer, http.StatusRequestEntityTooLarge, colorReliefResponse)
+			slog.WarnContext(request.Context(), "color relief request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
+			colorReliefResponse.Attributes.Error = newErrorObject(EndpointColorRelief, ReasonRequestBodyTooLarge, fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit))
+			buildColorReliefResponse(writer, request, http.StatusRequestEntityTooLarge, colorReliefResponse)
 		} else {
 			// handle other read errors
-			slog.Warn("color relief request: error reading request body", "error", err, "ID", "unknown")
-			colorReliefResponse.Attributes.Error.Code = "12020"
-			colorReliefResponse.Attributes.Error.Title = "error reading request body"
-			colorReliefResponse.Attributes.Error.Detail = err.Error()
-			buildColorReliefResponse(writer, http.StatusBadRequest, colorReliefResponse)
+			slog.WarnContext(request.Context(), "color relief request: error reading request body", "error", err, "ID", "unknown")
+			colorReliefResponse.Attributes.Error = newErrorObject(EndpointColorRelief, ReasonReadingRequestBody, err.Error())
+			buildColorReliefResponse(writer, request, http.StatusBadRequest, colorReliefResponse)
 		}
 		return
 	}
@@ -54,14 +55,52 @@ func colorReliefRequest(writer http.ResponseWriter, request *http.Request) {
 	colorReliefRequest := ColorReliefRequest{}
 	err = json.Unmarshal(bodyData, &colorReliefRequest)
 	if err != nil {
-		slog.Warn("color relief request: error unmarshaling request body", "error", err, "ID", "unknown")
-		colorReliefResponse.Attributes.Error.Code = "12040"
-		colorReliefResponse.Attributes.Error.Title = "error unmarshaling request body"
-		colorReliefResponse.Attributes.Error.Detail = err.Error()
-		buildColorReliefResponse(writer, http.StatusBadRequest, colorReliefResponse)
+		slog.WarnContext(request.Context(), "color relief request: error unmarshaling request body", "error", err, "ID", "unknown")
+		colorReliefResponse.Attributes.Error = newErrorObject(EndpointColorRelief, ReasonUnmarshalingRequestBody, err.Error())
+		buildColorReliefResponse(writer, request, http.StatusBadRequest, colorReliefResponse)
 		return
 	}
 
+	// resolve place name (geocoding) into lon/lat coordinates
+	if colorReliefRequest.Attributes.Place != "" {
+		place, err := geocodePlace(request.Context(), colorReliefRequest.Attributes.Place)
+		if err != nil {
+			slog.WarnContext(request.Context(), "color relief request: error geocoding place", "error", err, "place", colorReliefRequest.Attributes.Place, "ID", colorReliefRequest.ID)
+			colorReliefResponse.ID = colorReliefRequest.ID
+			colorReliefResponse.Attributes.Place = colorReliefRequest.Attributes.Place
+			if errors.Is(err, ErrPlaceNotFound) {
+				colorReliefResponse.Attributes.Error = newErrorObject(EndpointColorRelief, ReasonPlaceNotFound, err.Error())
+				buildColorReliefResponse(writer, request, http.StatusNotFound, colorReliefResponse)
+				return
+			}
+			if errors.Is(err, ErrGeocoderUnavailable) {
+				colorReliefResponse.Attributes.Error = newErrorObject(EndpointColorRelief, ReasonGeocoderUnavailable, err.Error())
+				buildColorReliefResponse(writer, request, http.StatusServiceUnavailable, colorReliefResponse)
+				return
+			}
+			colorReliefResponse.Attributes.Error = newErrorObject(EndpointColorRelief, ReasonVerifyingRequestData, err.Error())
+			buildColorReliefResponse(writer, request, http.StatusBadRequest, colorReliefResponse)
+			return
+		}
+		colorReliefRequest.Attributes.Zone = 0
+		colorReliefRequest.Attributes.Longitude = place.Longitude
+		colorReliefRequest.Attributes.Latitude = place.Latitude
+		colorReliefResponse.Attributes.PlaceName = place.DisplayName
+	}
+
+	// named color table (stored server-side) instead of color text file content
+	if colorReliefRequest.Attributes.ColorTableName != "" {
+		colorReliefRequest.Attributes.ColorTextFileContent, err = resolveColorTable(request.Context(), colorReliefRequest.Attributes.ColorTableName, colorReliefRequest.Attributes.ColorTextFileContent)
+		if err != nil {
+			slog.WarnContext(request.Context(), "color relief request: error resolving color table", "error", err, "name", colorReliefRequest.Attributes.ColorTableName, "ID", colorReliefRequest.ID)
+			colorReliefResponse.ID = colorReliefRequest.ID
+			colorReliefResponse.Attributes.ColorTableName = colorReliefRequest.Attributes.ColorTableName
+			colorReliefResponse.Attributes.Error = newErrorObject(EndpointColorRelief, ReasonVerifyingRequestData, err.Error())
+			buildColorReliefResponse(writer, request, http.StatusBadRequest, colorReliefResponse)
+			return
+		}
+	}
+
 	// copy request parameters into response
 	colorReliefResponse.ID = colorReliefRequest.ID
 	colorReliefResponse.Attributes.Zone = colorReliefRequest.Attributes.Zone
@@ -69,17 +108,24 @@ func colorReliefRequest(writer http.ResponseWriter, request *http.Request) {
 	colorReliefResponse.Attributes.Northing = colorReliefRequest.Attributes.Northing
 	colorReliefResponse.Attributes.Longitude = colorReliefRequest.Attributes.Longitude
 	colorReliefResponse.Attributes.Latitude = colorReliefRequest.Attributes.Latitude
+	colorReliefResponse.Attributes.Place = colorReliefRequest.Attributes.Place
+	colorReliefResponse.Attributes.OutputFormat = colorReliefRequest.Attributes.OutputFormat
 	colorReliefResponse.Attributes.ColorTextFileContent = colorReliefRequest.Attributes.ColorTextFileContent
+	colorReliefResponse.Attributes.ColorTableName = colorReliefRequest.Attributes.ColorTableName
 	colorReliefResponse.Attributes.ColoringAlgorithm = colorReliefRequest.Attributes.ColoringAlgorithm
+	colorReliefResponse.Attributes.ColorRamp = colorReliefRequest.Attributes.ColorRamp
+	colorReliefResponse.Attributes.StretchPercentiles = colorReliefRequest.Attributes.StretchPercentiles
+	colorReliefResponse.Attributes.BlendHillshade = colorReliefRequest.Attributes.BlendHillshade
+	if colorReliefRequest.Attributes.ColoringAlgorithm == "" {
+		colorReliefResponse.Attributes.Warnings = append(colorReliefResponse.Attributes.Warnings, "ColoringAlgorithm not set, default 'interpolation' applied")
+	}
 
 	// verify request data
 	err = verifyColorReliefRequestData(request, colorReliefRequest)
 	if err != nil {
-		slog.Warn("color relief request: error verifying request data", "error", err, "ID", colorReliefRequest.ID)
-		colorReliefResponse.Attributes.Error.Code = "12060"
-		colorReliefResponse.Attributes.Error.Title = "error verifying request data"
-		colorReliefResponse.Attributes.Error.Detail = err.Error()
-		buildColorReliefResponse(writer, http.StatusBadRequest, colorReliefResponse)
+		slog.WarnContext(request.Context(), "color relief request: error verifying request data", "error", err, "ID", colorReliefRequest.ID)
+		colorReliefResponse.Attributes.Error = newErrorObject(EndpointColorRelief, ReasonVerifyingRequestData, err.Error())
+		buildColorReliefResponse(writer, request, http.StatusBadRequest, colorReliefResponse)
 		return
 	}
 
@@ -102,12 +148,20 @@ func colorReliefRequest(writer http.ResponseWriter, request *http.Request) {
 		// get all tiles (metadata) for given UTM coordinates
 		tiles, err = getAllTilesUTM(zone, easting, northing)
 		if err != nil {
-			slog.Warn("color relief request: error getting GeoTIFF tile for UTM coordinates", "error", err,
+			slog.WarnContext(request.Context(), "color relief request: error getting GeoTIFF tile for UTM coordinates", "error", err,
 				"easting", easting, "northing", northing, "zone", zone, "ID", colorReliefRequest.ID)
-			colorReliefResponse.Attributes.Error.Code = "12080"
-			colorReliefResponse.Attributes.Error.Title = "getting GeoTIFF tile for UTM coordinates"
-			colorReliefResponse.Attributes.Error.Detail = err.Error()
-			buildColorReliefResponse(writer, http.StatusBadRequest, colorReliefResponse)
+			if errors.Is(err, ErrOutsideCoverage) {
+				colorReliefResponse.Attributes.Error = newErrorObject(EndpointColorRelief, ReasonOutsideCoverage, describeOutsideCoverage(err))
+				buildColorReliefResponse(writer, request, http.StatusNotFound, colorReliefResponse)
+				return
+			}
+			if errors.Is(err, ErrSourceUnavailable) {
+				colorReliefResponse.Attributes.Error = newErrorObject(EndpointColorRelief, ReasonSourceUnavailable, err.Error())
+				buildColorReliefResponse(writer, request, http.StatusServiceUnavailable, colorReliefResponse)
+				return
+			}
+			colorReliefResponse.Attributes.Error = newErrorObject(EndpointColorRelief, ReasonGettingTileUTM, err.Error())
+			buildColorReliefResponse(writer, request, http.StatusBadRequest, colorReliefResponse)
 			return
 		}
 	} else {
@@ -120,33 +174,84 @@ func colorReliefRequest(writer http.ResponseWriter, request *http.Request) {
 		tiles, err = getAllTilesLonLat(longitude, latitude)
 		if err != nil {
 			err = fmt.Errorf("error [%w] getting tile for coordinates lon: %.8f, lat: %.8f", err, longitude, latitude)
-			slog.Warn("color relief request: error getting GeoTIFF tile for lon/lat coordinates", "error", err,
+			slog.WarnContext(request.Context(), "color relief request: error getting GeoTIFF tile for lon/lat coordinates", "error", err,
 				"longitude", longitude, "latitude", latitude, "ID", colorReliefRequest.ID)
-			colorReliefResponse.Attributes.Error.Code = "12100"
-			colorReliefResponse.Attributes.Error.Title = "getting GeoTIFF tile for lon/lat coordinates"
-			colorReliefResponse.Attributes.Error.Detail = err.Error()
-			buildColorReliefResponse(writer, http.StatusBadRequest, colorReliefResponse)
+			if errors.Is(err, ErrOutsideCoverage) {
+				colorReliefResponse.Attributes.Error = newErrorObject(EndpointColorRelief, ReasonOutsideCoverage, describeOutsideCoverage(err))
+				buildColorReliefResponse(writer, request, http.StatusNotFound, colorReliefResponse)
+				return
+			}
+			if errors.Is(err, ErrSourceUnavailable) {
+				colorReliefResponse.Attributes.Error = newErrorObject(EndpointColorRelief, ReasonSourceUnavailableLonLat, err.Error())
+				buildColorReliefResponse(writer, request, http.StatusServiceUnavailable, colorReliefResponse)
+				return
+			}
+			colorReliefResponse.Attributes.Error = newErrorObject(EndpointColorRelief, ReasonGettingTileLonLat, err.Error())
+			buildColorReliefResponse(writer, request, http.StatusBadRequest, colorReliefResponse)
 			return
 		}
 	}
 
+	// explicit output format (overrides default for type of coordinates)
+	if colorReliefRequest.Attributes.OutputFormat != "" {
+		outputFormat = strings.ToLower(colorReliefRequest.Attributes.OutputFormat)
+	}
+
+	// conditional request: unchanged response (same parameters, same tiles) needs no processing
+	etag := buildETag("colorrelief", colorReliefRequest, tiles)
+	if checkNotModified(writer, request, etag) {
+		return
+	}
+
 	// build colorRelief for all existing tiles
-	for _, tile := range tiles {
-		colorRelief, err := generateColorReliefObjectForTile(tile, outputFormat, colorReliefRequest.Attributes.ColorTextFileContent, colorReliefRequest.Attributes.ColoringAlgorithm)
-		if err != nil {
-			slog.Warn("color relief request: error generating colorRelief object for tile", "error", err, "ID", colorReliefRequest.ID)
-			colorReliefResponse.Attributes.Error.Code = "12120"
-			colorReliefResponse.Attributes.Error.Title = "error generating colorRelief object for tile"
-			colorReliefResponse.Attributes.Error.Detail = err.Error()
-			buildColorReliefResponse(writer, http.StatusBadRequest, colorReliefResponse)
+	colorReliefs, tileErrors, err := generateObjectsForTiles(request.Context(), tiles, func(tile TileMetadata) (ColorRelief, error) {
+		colorTextFileContent := colorReliefRequest.Attributes.ColorTextFileContent
+		if colorReliefRequest.Attributes.ColorRamp != "" {
+			// automatic color relief: color ramp stretched to elevations of tile
+			var err error
+			colorTextFileContent, err = buildAutoColorTextFileContent(tile, strings.ToLower(colorReliefRequest.Attributes.ColorRamp),
+				colorReliefRequest.Attributes.StretchPercentiles)
+			if err != nil {
+				return ColorRelief{}, fmt.Errorf("error [%w] at buildAutoColorTextFileContent()", err)
+			}
+		}
+		// color of nodata areas (transparent, fill, preserve)
+		colorTextFileContent = applyNoDataHandling(colorTextFileContent, colorReliefRequest.Attributes.NoDataHandling, colorReliefRequest.Attributes.NoDataColor)
+		return generateColorReliefObjectForTile(request.Context(), tile, outputFormat, colorReliefRequest.Attributes.GeoTIFFOptions, colorTextFileContent,
+			colorReliefRequest.Attributes.ColoringAlgorithm, colorReliefRequest.Attributes.BlendHillshade)
+	})
+	if err != nil {
+		slog.WarnContext(request.Context(), "color relief request: error generating colorRelief object for tile", "error", err, "ID", colorReliefRequest.ID)
+		if errors.Is(err, context.Canceled) {
+			// client disconnected, processing aborted, no response required
+			return
+		}
+		if errors.Is(err, ErrServerBusy) {
+			colorReliefResponse.Attributes.Error = newErrorObject(EndpointColorRelief, ReasonServerBusy, err.Error())
+			writer.Header().Set("Retry-After", strconv.Itoa(getProgConfig().GDALJobQueue.RetryAfter))
+			buildColorReliefResponse(writer, request, http.StatusTooManyRequests, colorReliefResponse)
 			return
 		}
-		colorReliefResponse.Attributes.ColorReliefs = append(colorReliefResponse.Attributes.ColorReliefs, colorRelief)
+		colorReliefResponse.Attributes.Error = newErrorObject(EndpointColorRelief, ReasonGeneratingObject, err.Error())
+		buildColorReliefResponse(writer, request, http.StatusBadRequest, colorReliefResponse)
+		return
+	}
+	colorReliefResponse.Attributes.ColorReliefs = colorReliefs
+	colorReliefResponse.Attributes.TileErrors = tileErrors
+	if len(tileErrors) > 0 {
+		colorReliefResponse.Attributes.Warnings = append(colorReliefResponse.Attributes.Warnings,
+			fmt.Sprintf("%d of %d tiles could not be processed (see TileErrors)", len(tileErrors), len(tiles)))
+		slog.WarnContext(request.Context(), "color relief request: partial success, tiles could not be processed", "tile errors", tileErrors, "ID", colorReliefRequest.ID)
 	}
+	addUsage(request.Context(), 0, len(colorReliefs))
 
 	// success response
 	colorReliefResponse.Attributes.IsError = false
-	buildColorReliefResponse(writer, http.StatusOK, colorReliefResponse)
+	if len(tileErrors) == 0 {
+		// partial responses are not cacheable
+		setETag(writer, etag)
+	}
+	buildColorReliefResponse(writer, request, http.StatusOK, colorReliefResponse)
 }
 
 /*
@@ -167,18 +272,6 @@ func verifyColorReliefRequestData(request *http.Request, colorReliefRequest Colo
 		return fmt.Errorf("unexpected or missing HTTP header field Content-Type, value = [%s], expected 'application/json'", contentType)
 	}
 
-	// verify HTTP header
-	accept := request.Header.Get("Accept")
-	isAcceptValid := true
-	switch {
-	case strings.HasPrefix(strings.ToLower(accept), "application/json"):
-	default:
-		isAcceptValid = false
-	}
-	if !isAcceptValid {
-		return fmt.Errorf("unexpected or missing HTTP header field Accept, value = [%s], expected 'application/json'", accept)
-	}
-
 	// verify Type
 	if colorReliefRequest.Type != TypeColorReliefRequest {
 		return fmt.Errorf("unexpected request Type [%v]", colorReliefRequest.Type)
@@ -215,10 +308,25 @@ func verifyColorReliefRequestData(request *http.Request, colorReliefRequest Colo
 		}
 	}
 
-	// verify 'color text file content'
-	err := verifyColorTextFileContent(colorReliefRequest.Attributes.ColorTextFileContent)
-	if err != nil {
-		return errors.New("invalid color text file content (%w)")
+	// verify 'color text file content' or color ramp (automatic color relief)
+	if colorReliefRequest.Attributes.ColorRamp != "" {
+		if len(colorReliefRequest.Attributes.ColorTextFileContent) > 0 {
+			return errors.New("either color text file content or color ramp must be set")
+		}
+		if _, found := colorRamps[strings.ToLower(colorReliefRequest.Attributes.ColorRamp)]; !found {
+			return fmt.Errorf("unsupported color ramp (valid: %s)", strings.Join(getColorRampNames(), ", "))
+		}
+		stretchPercentiles := colorReliefRequest.Attributes.StretchPercentiles
+		if len(stretchPercentiles) != 0 {
+			if len(stretchPercentiles) != 2 || stretchPercentiles[0] < 0 || stretchPercentiles[1] > 100 || stretchPercentiles[0] >= stretchPercentiles[1] {
+				return errors.New("stretch percentiles must be two ascending values between 0 and 100")
+			}
+		}
+	} else {
+		err := verifyColorTextFileContent(colorReliefRequest.Attributes.ColorTextFileContent)
+		if err != nil {
+			return errors.New("invalid color text file content (%w)")
+		}
 	}
 
 	// verify coloring algorithm
@@ -228,77 +336,84 @@ func verifyColorReliefRequestData(request *http.Request, colorReliefRequest Colo
 		}
 	}
 
-	return nil
-}
+	// verify output format
+	switch strings.ToLower(colorReliefRequest.Attributes.OutputFormat) {
+	case "":
+	case "geotiff":
+	case "png":
+	default:
+		return errors.New("unsupported output format (not geotiff, png)")
+	}
 
-/*
-buildColorReliefResponse builds HTTP responses with specified status and body.
-It sets the Content-Type and Content-Length headers before writing the response body.
-This function is used to construct consistent HTTP responses throughout the application.
-*/
-func buildColorReliefResponse(writer http.ResponseWriter, httpStatus int, colorReliefResponse ColorReliefResponse) {
-	// log limit length of body (e.g., the colorRelief objects as part of the body can be very large)
-	maxBodyLength := 1024
-
-	// CORS: allow requests from any origin
-	writer.Header().Set("Access-Control-Allow-Origin", "*")
-	// CORS: allowed methods
-	writer.Header().Set("Access-Control-Allow-Methods", "POST")
-	// CORS: allowed headers
-	writer.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
-	// marshal response
-	body, err := json.MarshalIndent(colorReliefResponse, "", "  ")
+	// verify GeoTIFF creation options
+	err := verifyGeoTIFFOptions(colorReliefRequest.Attributes.GeoTIFFOptions)
 	if err != nil {
-		slog.Error("error marshaling point response", "error", err, "body length", len(body),
-			fmt.Sprintf("body (limited to first %d bytes)", maxBodyLength), body[:maxBodyLength])
-
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
+		return err
 	}
 
-	// gzip response body
-	var bytesBuffer bytes.Buffer
-	gz := gzip.NewWriter(&bytesBuffer)
-
-	_, err = gz.Write(body)
+	// verify nodata handling
+	err = verifyNoDataHandling(colorReliefRequest.Attributes.NoDataHandling, colorReliefRequest.Attributes.NoDataColor)
 	if err != nil {
-		slog.Error("error [%v] at gz.Write()", "error", err)
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
+		return err
 	}
 
-	err = gz.Close()
-	if err != nil {
-		slog.Error("error [%v] at gz.Close()", "error", err)
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
+	// verify hillshade blending (optional)
+	blending := colorReliefRequest.Attributes.BlendHillshade
+	if blending != nil {
+		if blending.Opacity <= 0.0 || blending.Opacity > 1.0 {
+			return errors.New("opacity of blended hillshade must be greater than 0.0 and at most 1.0")
+		}
+		if blending.VerticalExaggeration <= 0.0 || blending.VerticalExaggeration > 100.0 {
+			return errors.New("vertical exaggeration of blended hillshade must be greater than 0.0 and at most 100.0")
+		}
+		if blending.AzimuthOfLight > 360 {
+			return errors.New("azimuth of light source of blended hillshade must be between 0 and 360")
+		}
+		if blending.AltitudeOfLight > 90 {
+			return errors.New("altitude of light source of blended hillshade must be between 0 and 90")
+		}
 	}
 
-	// set headers
-	writer.Header().Set("Content-Encoding", "gzip")
-	writer.Header().Set("Content-Type", JSONAPIMediaType)
-	writer.WriteHeader(httpStatus)
+	return nil
+}
 
-	// send response
-	_, err = writer.Write(bytesBuffer.Bytes())
-	if err != nil {
-		slog.Error("error writing HTTP response body", "error", err, "body length", len(body),
-			fmt.Sprintf("body (limited to first %d bytes)", maxBodyLength), body[:maxBodyLength])
-	}
+/*
+buildColorReliefResponse builds HTTP responses with specified status and body.
+It sets the Content-Type and Content-Length headers before writing the response body.
+This function is used to construct consistent HTTP responses throughout the application.
+*/
+func buildColorReliefResponse(writer http.ResponseWriter, request *http.Request, httpStatus int, colorReliefResponse ColorReliefResponse) {
+	// response metadata (versions, processing duration, cache hit)
+	colorReliefResponse.Meta = newResponseMeta(request.Context())
+
+	// skip compression if payload is already compressed (e.g. PNG)
+	skipCompression := len(colorReliefResponse.Attributes.ColorReliefs) > 0 && isCompressedDataFormat(colorReliefResponse.Attributes.ColorReliefs[0].DataFormat)
+
+	// encode (streaming) and send response
+	streamJSONResponse(writer, request, httpStatus, colorReliefResponse, skipCompression)
 }
 
 /*
-generateColorReliefObjectForTile builds colorRelief object for given tile index.
+generateColorReliefObjectForTile builds colorRelief object for given tile index. With hillshade blending, a hillshade
+is multiplied into the colorized output (see blendHillshadeIntoColorRelief).
 */
-func generateColorReliefObjectForTile(tile TileMetadata, outputFormat string, colorTextFileContent []string, coloringAlgorithm string) (ColorRelief, error) {
+func generateColorReliefObjectForTile(ctx context.Context, tile TileMetadata, outputFormat string, geotiffOptions GeoTIFFOptions, colorTextFileContent []string,
+	coloringAlgorithm string, blending *HillshadeBlending) (ColorRelief, error) {
 	var colorRelief ColorRelief
 	var boundingBox WGS84BoundingBox
 
-	// run operations in temp directory
-	tempDir, err := os.MkdirTemp("", "dtm-elevation-service-color-relief-")
+	// lookup response cache
+	cacheKey := buildResponseCacheKey("color-relief", tile.Index, tile.Actuality, outputFormat, geotiffOptions, colorTextFileContent, coloringAlgorithm, blending)
+	cached, found := responseCache.Get(cacheKey)
+	recordCacheLookup(ctx, found)
+	if found {
+		return cached.(ColorRelief), nil
+	}
+
+	// run operations in temp directory (color text file) and in memory (/vsimem)
+	tempDir, err := createTempDirectory("color-relief")
 	if err != nil {
-		return colorRelief, fmt.Errorf("error [%w] at os.MkdirTemp()", err)
+		return colorRelief, fmt.Errorf("error [%w] at createTempDirectory()", err)
 	}
 	defer func() {
 		_ = os.RemoveAll(tempDir)
@@ -312,46 +427,67 @@ func generateColorReliefObjectForTile(tile TileMetadata, outputFormat string, co
 	}
 
 	inputGeoTIFF := tile.Path
-	colorReliefColorUTMGeoTIFF := filepath.Join(tempDir, tile.Index+".color-relief.color.utm.tif")
-	colorReliefWebmercatorGeoTIFF := filepath.Join(tempDir, tile.Index+".color-relief.webmercator.tif")
-	colorReliefColorWebmercatoPNG := filepath.Join(tempDir, tile.Index+".color-relief.color.webmercator.png")
+	vsimemPrefix := dtm.NewVSIMemPrefix("color-relief")
+	colorReliefColorUTMGeoTIFF := vsimemPrefix + tile.Index + ".color-relief.color.utm.tif"
+	colorReliefWebmercatorGeoTIFF := vsimemPrefix + tile.Index + ".color-relief.webmercator.tif"
+	colorReliefColorWebmercatoPNG := vsimemPrefix + tile.Index + ".color-relief.color.webmercator.png"
+	colorReliefColorWebmercatorGeoTIFF := vsimemPrefix + tile.Index + ".color-relief.color.webmercator.tif"
+	defer removeVSIMemFiles(colorReliefColorUTMGeoTIFF, colorReliefWebmercatorGeoTIFF, colorReliefColorWebmercatoPNG, colorReliefColorWebmercatorGeoTIFF)
 	var data []byte
 	switch strings.ToLower(outputFormat) {
 	case "geotiff":
-		options := []string{"color-relief", inputGeoTIFF, colorTextFile, colorReliefColorUTMGeoTIFF, "-alpha"}
+		options := append([]string{"-of", "GTiff", "-alpha"}, buildGeoTIFFCreationOptions(geotiffOptions)...)
 		if coloringAlgorithm == "rounding" {
 			options = append(options, "-nearest_color_entry")
 		}
-		commandExitStatus, commandOutput, err := runCommand("gdaldem", options)
+		err := dtm.Dem(ctx, "color-relief", inputGeoTIFF, colorTextFile, colorReliefColorUTMGeoTIFF, options)
 		if err != nil {
-			return colorRelief, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
+			return colorRelief, fmt.Errorf("error [%w] at dtm.Dem()", err)
 		}
-		// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-		// fmt.Printf("commandOutput: %s\n", commandOutput)
 
-		data, err = os.ReadFile(colorReliefColorUTMGeoTIFF)
+		if blending != nil {
+			err = blendHillshadeIntoColorRelief(ctx, inputGeoTIFF, colorReliefColorUTMGeoTIFF, vsimemPrefix+tile.Index, *blending)
+			if err != nil {
+				return colorRelief, fmt.Errorf("error [%w] at blendHillshadeIntoColorRelief()", err)
+			}
+		}
+
+		data, err = dtm.ReadVSIMemFile(colorReliefColorUTMGeoTIFF)
 		if err != nil {
-			return colorRelief, fmt.Errorf("error [%w] at os.ReadFile()", err)
+			return colorRelief, fmt.Errorf("error [%w] at dtm.ReadVSIMemFile()", err)
 		}
 
 	case "png":
-		commandExitStatus, commandOutput, err := runCommand("gdalwarp", []string{"-t_srs", "EPSG:3857", inputGeoTIFF, colorReliefWebmercatorGeoTIFF})
+		err := dtm.Warp(ctx, inputGeoTIFF, colorReliefWebmercatorGeoTIFF, []string{"-of", "GTiff", "-t_srs", "EPSG:3857"})
 		if err != nil {
-			return colorRelief, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
+			return colorRelief, fmt.Errorf("error [%w] at dtm.Warp()", err)
 		}
-		// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-		// fmt.Printf("commandOutput: %s\n", commandOutput)
 
-		options := []string{"color-relief", colorReliefWebmercatorGeoTIFF, colorTextFile, colorReliefColorWebmercatoPNG, "-alpha"}
+		options := []string{"-of", "PNG", "-alpha"}
 		if coloringAlgorithm == "rounding" {
 			options = append(options, "-nearest_color_entry")
 		}
-		commandExitStatus, commandOutput, err = runCommand("gdaldem", options)
-		if err != nil {
-			return colorRelief, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
+		if blending == nil {
+			err = dtm.Dem(ctx, "color-relief", colorReliefWebmercatorGeoTIFF, colorTextFile, colorReliefColorWebmercatoPNG, options)
+			if err != nil {
+				return colorRelief, fmt.Errorf("error [%w] at dtm.Dem()", err)
+			}
+		} else {
+			// blending requires writable raster (GeoTIFF), PNG is created afterwards
+			options[1] = "GTiff"
+			err = dtm.Dem(ctx, "color-relief", colorReliefWebmercatorGeoTIFF, colorTextFile, colorReliefColorWebmercatorGeoTIFF, options)
+			if err != nil {
+				return colorRelief, fmt.Errorf("error [%w] at dtm.Dem()", err)
+			}
+			err = blendHillshadeIntoColorRelief(ctx, colorReliefWebmercatorGeoTIFF, colorReliefColorWebmercatorGeoTIFF, vsimemPrefix+tile.Index, *blending)
+			if err != nil {
+				return colorRelief, fmt.Errorf("error [%w] at blendHillshadeIntoColorRelief()", err)
+			}
+			err = dtm.Translate(ctx, colorReliefColorWebmercatorGeoTIFF, colorReliefColorWebmercatoPNG, []string{"-of", "PNG"})
+			if err != nil {
+				return colorRelief, fmt.Errorf("error [%w] at dtm.Translate()", err)
+			}
 		}
-		// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-		// fmt.Printf("commandOutput: %s\n", commandOutput)
 
 		// 4. get bounding box (in wgs84) for webmercator tif (georeference of webmercator png )
 		boundingBox, err = calculateWGS84BoundingBox(tile)
@@ -360,9 +496,9 @@ func generateColorReliefObjectForTile(tile TileMetadata, outputFormat string, co
 		}
 
 		// read result file
-		data, err = os.ReadFile(colorReliefColorWebmercatoPNG)
+		data, err = dtm.ReadVSIMemFile(colorReliefColorWebmercatoPNG)
 		if err != nil {
-			return colorRelief, fmt.Errorf("error [%w] at os.ReadFile()", err)
+			return colorRelief, fmt.Errorf("error [%w] at dtm.ReadVSIMemFile()", err)
 		}
 
 	default:
@@ -372,6 +508,11 @@ func generateColorReliefObjectForTile(tile TileMetadata, outputFormat string, co
 	// set contour return structure
 	colorRelief.Data = data
 	colorRelief.DataFormat = outputFormat
+	variant := ""
+	if blending != nil {
+		variant = "shaded"
+	}
+	colorRelief.Filename = buildObjectFilename(tile.Index, "colorrelief", variant, tile.Actuality, outputFormat)
 	colorRelief.Actuality = tile.Actuality
 	colorRelief.Origin = tile.Source
 	colorRelief.TileIndex = tile.Index
@@ -381,11 +522,120 @@ func generateColorReliefObjectForTile(tile TileMetadata, outputFormat string, co
 	attribution := "unknown"
 	resource, err := getElevationResource(tile.Source)
 	if err != nil {
-		slog.Error("color reliefrequest: error getting elevation resource", "error", err, "source", tile.Source)
+		slog.ErrorContext(ctx, "color reliefrequest: error getting elevation resource", "error", err, "source", tile.Source)
 	} else {
 		attribution = resource.Attribution
 	}
 	colorRelief.Attribution = attribution
 
+	// get provenance of source files (delivery file name and checksum)
+	sourceFiles, err := getSourceFiles(tile)
+	if err != nil {
+		slog.ErrorContext(ctx, "color relief request: error getting source files", "error", err, "tile", tile.Index)
+	}
+	colorRelief.SourceFiles = sourceFiles
+
+	// add to response cache
+	responseCache.Add(cacheKey, colorRelief, len(colorRelief.Data))
+
 	return colorRelief, nil
 }
+
+/*
+blendHillshadeIntoColorRelief multiplies a hillshade of the elevation raster into the colorized raster (RGBA):
+ 1. calculate hillshade of elevation raster (same grid as colorized raster)
+    gdaldem hillshade 32_497_5670.tif 32_497_5670.blend.hillshade.tif -compute_edges -z 1.0 -az 315 -alt 45
+ 2. multiply color bands pixel by pixel: color * (1 - opacity + opacity * shade / 255)
+
+Transparent pixels and hillshade nodata (0) are not changed.
+*/
+func blendHillshadeIntoColorRelief(ctx context.Context, elevationFile, colorReliefFile, vsimemPrefix string, blending HillshadeBlending) error {
+	// 1. calculate hillshade
+	hillshadeFile := vsimemPrefix + ".blend.hillshade.tif"
+	defer removeVSIMemFiles(hillshadeFile)
+	options := []string{"-of", "GTiff", "-compute_edges",
+		"-z", strconv.FormatFloat(blending.VerticalExaggeration, 'f', -1, 64),
+		"-az", strconv.FormatUint(uint64(blending.AzimuthOfLight), 10),
+		"-alt", strconv.FormatUint(uint64(blending.AltitudeOfLight), 10)}
+	err := dtm.Dem(ctx, "hillshade", elevationFile, "", hillshadeFile, options)
+	if err != nil {
+		return fmt.Errorf("error [%w] at dtm.Dem()", err)
+	}
+
+	// 2. multiply hillshade into color bands
+	err = acquireGDALJobSlot(ctx)
+	if err != nil {
+		return err
+	}
+	defer releaseGDALJobSlot(ctx)
+
+	hillshadeDataset, err := godal.Open(hillshadeFile, godal.RasterOnly())
+	if err != nil {
+		return fmt.Errorf("error [%w] at godal.Open(), file: %s", err, hillshadeFile)
+	}
+	defer hillshadeDataset.Close()
+
+	colorReliefDataset, err := godal.Open(colorReliefFile, godal.RasterOnly(), godal.Update())
+	if err != nil {
+		return fmt.Errorf("error [%w] at godal.Open(), file: %s", err, colorReliefFile)
+	}
+	defer func() {
+		if colorReliefDataset != nil {
+			colorReliefDataset.Close()
+		}
+	}()
+
+	structure := colorReliefDataset.Structure()
+	hillshadeStructure := hillshadeDataset.Structure()
+	if structure.SizeX != hillshadeStructure.SizeX || structure.SizeY != hillshadeStructure.SizeY || structure.NBands < 4 {
+		return fmt.Errorf("unexpected raster structure (color relief: %dx%dx%d, hillshade: %dx%d)",
+			structure.SizeX, structure.SizeY, structure.NBands, hillshadeStructure.SizeX, hillshadeStructure.SizeY)
+	}
+
+	bands := colorReliefDataset.Bands()
+	shades := make([]uint8, structure.SizeX)
+	colors := [4][]uint8{}
+	for i := range colors {
+		colors[i] = make([]uint8, structure.SizeX)
+	}
+	for row := range structure.SizeY {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		err = hillshadeDataset.Bands()[0].Read(0, row, shades, structure.SizeX, 1)
+		if err != nil {
+			return fmt.Errorf("error [%w] at band.Read(), file: %s, row: %d", err, hillshadeFile, row)
+		}
+		for i := range colors {
+			err = bands[i].Read(0, row, colors[i], structure.SizeX, 1)
+			if err != nil {
+				return fmt.Errorf("error [%w] at band.Read(), file: %s, row: %d", err, colorReliefFile, row)
+			}
+		}
+		for column := range structure.SizeX {
+			// 0 = nodata (gdaldem hillshade), alpha 0 = transparent
+			if shades[column] == 0 || colors[3][column] == 0 {
+				continue
+			}
+			factor := 1.0 - blending.Opacity + blending.Opacity*float64(shades[column])/255.0
+			for i := range 3 {
+				colors[i][column] = uint8(math.Min(255, math.Round(float64(colors[i][column])*factor)))
+			}
+		}
+		for i := range 3 {
+			err = bands[i].Write(0, row, colors[i], structure.SizeX, 1)
+			if err != nil {
+				return fmt.Errorf("error [%w] at band.Write(), file: %s, row: %d", err, colorReliefFile, row)
+			}
+		}
+	}
+
+	// flush blended values (close output dataset)
+	err = colorReliefDataset.Close()
+	colorReliefDataset = nil
+	if err != nil {
+		return fmt.Errorf("error [%w] at dataset.Close(), file: %s", err, colorReliefFile)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// ColorRampStop represents a color at a relative position (0.0 = lowest, 1.0 = highest value) of a color ramp.
+type ColorRampStop struct {
+	Position float64
+	Red      uint8
+	Green    uint8
+	Blue     uint8
+}
+
+// named color ramps for automatic (stretched) color relief
+var colorRamps = map[string][]ColorRampStop{
+	// classic hypsometric tints (green lowlands, yellow/brown hills, white peaks)
+	"hypsometric": {
+		{0.00, 46, 139, 87},
+		{0.20, 120, 180, 90},
+		{0.40, 230, 220, 140},
+		{0.60, 200, 160, 90},
+		{0.80, 150, 100, 60},
+		{1.00, 255, 255, 255},
+	},
+	// terrain (blue-green lowlands to brown and gray mountains)
+	"terrain": {
+		{0.00, 51, 102, 153},
+		{0.15, 0, 153, 102},
+		{0.35, 102, 204, 102},
+		{0.55, 204, 204, 102},
+		{0.75, 153, 102, 51},
+		{1.00, 204, 204, 204},
+	},
+	// grayscale (black to white)
+	"grayscale": {
+		{0.00, 0, 0, 0},
+		{1.00, 255, 255, 255},
+	},
+}
+
+/*
+getColorRampNames returns the (sorted) names of all color ramps.
+*/
+func getColorRampNames() []string {
+	names := make([]string, 0, len(colorRamps))
+	for name := range colorRamps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+/*
+buildStretchedColorTextFileContent builds color text file content (gdaldem color-relief) for a named color ramp
+stretched between the lower and upper elevation. No-data is transparent.
+*/
+func buildStretchedColorTextFileContent(rampName string, lower float64, upper float64) ([]string, error) {
+	ramp, found := colorRamps[rampName]
+	if !found {
+		return nil, fmt.Errorf("unknown color ramp [%s]", rampName)
+	}
+	if upper <= lower {
+		upper = lower + 1.0
+	}
+
+	content := make([]string, 0, len(ramp)+1)
+	for _, stop := range ramp {
+		elevation := lower + stop.Position*(upper-lower)
+		content = append(content, fmt.Sprintf("%.2f %d %d %d 255", elevation, stop.Red, stop.Green, stop.Blue))
+	}
+	content = append(content, "nv 0 0 0 0")
+	return content, nil
+}
+
+/*
+buildAutoColorTextFileContent builds color text file content for a tile: the named color ramp is stretched between
+the given percentiles (default: 0 and 100 = min and max) of the tile elevations.
+*/
+func buildAutoColorTextFileContent(tile TileMetadata, rampName string, stretchPercentiles []float64) ([]string, error) {
+	if len(stretchPercentiles) != 2 {
+		stretchPercentiles = []float64{0, 100}
+	}
+
+	values, _, _, err := collectRasterBandValues(tile.Path)
+	if err != nil {
+		return nil, fmt.Errorf("error [%w] at collectRasterBandValues(), file: %s", err, tile.Path)
+	}
+	percentiles := calculatePercentiles(values, slices.Clone(stretchPercentiles))
+	if len(percentiles) != 2 {
+		return nil, fmt.Errorf("no elevation data in tile [%s]", tile.Index)
+	}
+
+	return buildStretchedColorTextFileContent(rampName, percentiles[0].Value, percentiles[1].Value)
+}
@@ -0,0 +1,403 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// StoredColorTable represents a named color table of a tenant (persisted in the color tables file).
+type StoredColorTable struct {
+	Description          string
+	ColorTextFileContent []string
+	Created              time.Time
+	Modified             time.Time
+}
+
+// color tables of all tenants (tenant -> name -> color table)
+var (
+	colorTablesMutex sync.Mutex
+	colorTables      = make(map[string]map[string]*StoredColorTable)
+)
+
+// regular expression for color table names (part of the URL path)
+var colorTableNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,63}$`)
+
+/*
+getColorTablesFile returns the name of the file holding the color tables of all tenants (empty = memory only).
+*/
+func getColorTablesFile() string {
+	directory := getProgConfig().ColorTables.Directory
+	if directory == "" {
+		return ""
+	}
+	return filepath.Join(directory, "colortables.json")
+}
+
+/*
+initColorTables loads the persisted color tables (no color tables if file does not exist).
+*/
+func initColorTables() error {
+	filename := getColorTablesFile()
+	if filename == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("error [%w] at os.ReadFile(), file: %s", err, filename)
+	}
+
+	tables := make(map[string]map[string]*StoredColorTable)
+	err = json.Unmarshal(data, &tables)
+	if err != nil {
+		return fmt.Errorf("error [%w] at json.Unmarshal(), file: %s", err, filename)
+	}
+
+	colorTablesMutex.Lock()
+	colorTables = tables
+	colorTablesMutex.Unlock()
+	slog.Info("color tables loaded", "tenants", len(tables), "file", filename)
+
+	return nil
+}
+
+/*
+writeColorTables writes the color tables of all tenants to the color tables file (via temporary file and rename,
+caller holds colorTablesMutex).
+*/
+func writeColorTables() error {
+	filename := getColorTablesFile()
+	if filename == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(colorTables, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error [%w] at json.MarshalIndent()", err)
+	}
+	err = os.MkdirAll(filepath.Dir(filename), 0755)
+	if err != nil {
+		return fmt.Errorf("error [%w] at os.MkdirAll(), directory: %s", err, filepath.Dir(filename))
+	}
+	tempFilename := filename + ".tmp"
+	err = os.WriteFile(tempFilename, data, 0644)
+	if err != nil {
+		return fmt.Errorf("error [%w] at os.WriteFile(), file: %s", err, tempFilename)
+	}
+	err = os.Rename(tempFilename, filename)
+	if err != nil {
+		return fmt.Errorf("error [%w] at os.Rename(), file: %s", err, filename)
+	}
+	return nil
+}
+
+/*
+resolveColorTable returns the color text file content of the named color table of the tenant of the request
+context (used by raster requests with attribute 'ColorTableName').
+*/
+func resolveColorTable(ctx context.Context, name string, colorTextFileContent []string) ([]string, error) {
+	if len(colorTextFileContent) > 0 {
+		return nil, errors.New("either ColorTextFileContent or ColorTableName can be set")
+	}
+
+	colorTablesMutex.Lock()
+	defer colorTablesMutex.Unlock()
+
+	table, found := colorTables[getTenant(ctx)][name]
+	if !found {
+		return nil, fmt.Errorf("color table [%s] not found", name)
+	}
+	return slices.Clone(table.ColorTextFileContent), nil
+}
+
+/*
+checkColorTableAccess checks the access to the color tables: authenticated clients (tenant) or, if authentication
+is disabled, clients of the admin networks (color tables of the anonymous tenant).
+*/
+func checkColorTableAccess(request *http.Request) error {
+	if jwtValidator == nil {
+		allowedNetworks, err := parseNetworks(getProgConfig().Admin.AllowedNetworks)
+		if err != nil || !isIPInNetworks(getClientIP(request), allowedNetworks) {
+			return errors.New("client not in admin networks")
+		}
+		return nil
+	}
+	if getTenant(request.Context()) == anonymousTenant {
+		return errors.New("authentication required")
+	}
+	return nil
+}
+
+/*
+fillColorTableResponse copies the color table into the color table response.
+*/
+func fillColorTableResponse(colorTableResponse *ColorTableResponse, name string, table *StoredColorTable) {
+	colorTableResponse.Attributes.Name = name
+	colorTableResponse.Attributes.Description = table.Description
+	colorTableResponse.Attributes.ColorTextFileContent = table.ColorTextFileContent
+	colorTableResponse.Attributes.Created = table.Created.Format(time.RFC3339)
+	colorTableResponse.Attributes.Modified = table.Modified.Format(time.RFC3339)
+}
+
+/*
+colorTableStoreRequest handles 'color table store request' (POST /v1/colortables/{name}) from client:
+creates or replaces the named color table of the tenant.
+*/
+func colorTableStoreRequest(writer http.ResponseWriter, request *http.Request) {
+	name := request.PathValue("name")
+	var colorTableResponse = ColorTableResponse{Type: TypeColorTableResponse, ID: "unknown"}
+	colorTableResponse.Attributes.Name = name
+	colorTableResponse.Attributes.IsError = true
+
+	// statistics
+	atomic.AddUint64(&ColorTableRequests, 1)
+
+	// access (authenticated tenant or admin networks)
+	err := checkColorTableAccess(request)
+	if err != nil {
+		slog.WarnContext(request.Context(), "color table store request: access denied", "error", err, "client ip", getClientIP(request))
+		http.Error(writer, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	// limit overall request body size
+	request.Body = http.MaxBytesReader(writer, request.Body, MaxColorTableRequestBodySize)
+
+	// read request
+	bodyData, err := io.ReadAll(request.Body)
+	if err != nil {
+		// check specifically for the error returned by MaxBytesReader
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			slog.WarnContext(request.Context(), "color table store request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
+			colorTableResponse.Attributes.Error = newErrorObject(EndpointColorTables, ReasonRequestBodyTooLarge, fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit))
+			buildColorTableResponse(writer, request, http.StatusRequestEntityTooLarge, colorTableResponse)
+		} else {
+			// handle other read errors
+			slog.WarnContext(request.Context(), "color table store request: error reading request body", "error", err, "ID", "unknown")
+			colorTableResponse.Attributes.Error = newErrorObject(EndpointColorTables, ReasonReadingRequestBody, err.Error())
+			buildColorTableResponse(writer, request, http.StatusBadRequest, colorTableResponse)
+		}
+		return
+	}
+
+	// unmarshal request
+	colorTableRequest := ColorTableRequest{}
+	err = json.Unmarshal(bodyData, &colorTableRequest)
+	if err != nil {
+		slog.WarnContext(request.Context(), "color table store request: error unmarshaling request body", "error", err, "ID", "unknown")
+		colorTableResponse.Attributes.Error = newErrorObject(EndpointColorTables, ReasonUnmarshalingRequestBody, err.Error())
+		buildColorTableResponse(writer, request, http.StatusBadRequest, colorTableResponse)
+		return
+	}
+
+	// copy request parameters into response
+	colorTableResponse.ID = colorTableRequest.ID
+	colorTableResponse.Attributes.Description = colorTableRequest.Attributes.Description
+	colorTableResponse.Attributes.ColorTextFileContent = colorTableRequest.Attributes.ColorTextFileContent
+
+	// verify request data
+	err = verifyColorTableRequestData(request, name, colorTableRequest)
+	if err != nil {
+		slog.WarnContext(request.Context(), "color table store request: error verifying request data", "error", err, "ID", colorTableRequest.ID)
+		colorTableResponse.Attributes.Error = newErrorObject(EndpointColorTables, ReasonVerifyingRequestData, err.Error())
+		buildColorTableResponse(writer, request, http.StatusBadRequest, colorTableResponse)
+		return
+	}
+
+	// store color table (limited number of color tables per tenant)
+	tenant := getTenant(request.Context())
+	now := time.Now().UTC()
+	colorTablesMutex.Lock()
+	tenantTables := colorTables[tenant]
+	existing, found := tenantTables[name]
+	if !found && len(tenantTables) >= getProgConfig().ColorTables.MaxTablesPerTenant {
+		colorTablesMutex.Unlock()
+		slog.WarnContext(request.Context(), "color table store request: too many color tables", "tenant", tenant, "ID", colorTableRequest.ID)
+		colorTableResponse.Attributes.Error = newErrorObject(EndpointColorTables, ReasonTooManyColorTables,
+			fmt.Sprintf("max %d color tables", getProgConfig().ColorTables.MaxTablesPerTenant))
+		buildColorTableResponse(writer, request, http.StatusConflict, colorTableResponse)
+		return
+	}
+	table := &StoredColorTable{
+		Description:          colorTableRequest.Attributes.Description,
+		ColorTextFileContent: colorTableRequest.Attributes.ColorTextFileContent,
+		Created:              now,
+		Modified:             now,
+	}
+	if found {
+		table.Created = existing.Created
+	}
+	if tenantTables == nil {
+		tenantTables = make(map[string]*StoredColorTable)
+		colorTables[tenant] = tenantTables
+	}
+	tenantTables[name] = table
+	err = writeColorTables()
+	colorTablesMutex.Unlock()
+	if err != nil {
+		slog.ErrorContext(request.Context(), "color table store request: error persisting color tables", "error", err, "ID", colorTableRequest.ID)
+		colorTableResponse.Attributes.Error = newErrorObject(EndpointService, ReasonInternalServerError, "")
+		buildColorTableResponse(writer, request, http.StatusInternalServerError, colorTableResponse)
+		return
+	}
+	slog.InfoContext(request.Context(), "color table store request: color table stored", "name", name, "tenant", tenant, "ID", colorTableRequest.ID)
+
+	// success response
+	httpStatus := http.StatusOK
+	if !found {
+		httpStatus = http.StatusCreated
+	}
+	fillColorTableResponse(&colorTableResponse, name, table)
+	colorTableResponse.Attributes.IsError = false
+	buildColorTableResponse(writer, request, httpStatus, colorTableResponse)
+}
+
+/*
+colorTableGetRequest handles 'color table get request' (GET /v1/colortables/{name}) from client.
+*/
+func colorTableGetRequest(writer http.ResponseWriter, request *http.Request) {
+	name := request.PathValue("name")
+	var colorTableResponse = ColorTableResponse{Type: TypeColorTableResponse, ID: name}
+	colorTableResponse.Attributes.Name = name
+	colorTableResponse.Attributes.IsError = true
+
+	// statistics
+	atomic.AddUint64(&ColorTableRequests, 1)
+
+	// access (authenticated tenant or admin networks)
+	err := checkColorTableAccess(request)
+	if err != nil {
+		slog.WarnContext(request.Context(), "color table get request: access denied", "error", err, "client ip", getClientIP(request))
+		http.Error(writer, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	colorTablesMutex.Lock()
+	table, found := colorTables[getTenant(request.Context())][name]
+	colorTablesMutex.Unlock()
+	if !found {
+		slog.WarnContext(request.Context(), "color table get request: color table not found", "name", name)
+		colorTableResponse.Attributes.Error = newErrorObject(EndpointColorTables, ReasonColorTableNotFound, fmt.Sprintf("color table [%s] not found", name))
+		buildColorTableResponse(writer, request, http.StatusNotFound, colorTableResponse)
+		return
+	}
+
+	fillColorTableResponse(&colorTableResponse, name, table)
+	colorTableResponse.Attributes.IsError = false
+	buildColorTableResponse(writer, request, http.StatusOK, colorTableResponse)
+}
+
+/*
+colorTableDeleteRequest handles 'color table delete request' (DELETE /v1/colortables/{name}) from client.
+*/
+func colorTableDeleteRequest(writer http.ResponseWriter, request *http.Request) {
+	name := request.PathValue("name")
+	var colorTableResponse = ColorTableResponse{Type: TypeColorTableResponse, ID: name}
+	colorTableResponse.Attributes.Name = name
+	colorTableResponse.Attributes.IsError = true
+
+	// statistics
+	atomic.AddUint64(&ColorTableRequests, 1)
+
+	// access (authenticated tenant or admin networks)
+	err := checkColorTableAccess(request)
+	if err != nil {
+		slog.WarnContext(request.Context(), "color table delete request: access denied", "error", err, "client ip", getClientIP(request))
+		http.Error(writer, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	tenant := getTenant(request.Context())
+	colorTablesMutex.Lock()
+	table, found := colorTables[tenant][name]
+	if found {
+		delete(colorTables[tenant], name)
+		if len(colorTables[tenant]) == 0 {
+			delete(colorTables, tenant)
+		}
+		err = writeColorTables()
+	}
+	colorTablesMutex.Unlock()
+	if !found {
+		slog.WarnContext(request.Context(), "color table delete request: color table not found", "name", name)
+		colorTableResponse.Attributes.Error = newErrorObject(EndpointColorTables, ReasonColorTableNotFound, fmt.Sprintf("color table [%s] not found", name))
+		buildColorTableResponse(writer, request, http.StatusNotFound, colorTableResponse)
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(request.Context(), "color table delete request: error persisting color tables", "error", err, "name", name)
+		colorTableResponse.Attributes.Error = newErrorObject(EndpointService, ReasonInternalServerError, "")
+		buildColorTableResponse(writer, request, http.StatusInternalServerError, colorTableResponse)
+		return
+	}
+	slog.InfoContext(request.Context(), "color table delete request: color table deleted", "name", name, "tenant", tenant)
+
+	fillColorTableResponse(&colorTableResponse, name, table)
+	colorTableResponse.Attributes.IsError = false
+	buildColorTableResponse(writer, request, http.StatusOK, colorTableResponse)
+}
+
+/*
+verifyColorTableRequestData verifies 'color table' request data.
+*/
+func verifyColorTableRequestData(request *http.Request, name string, colorTableRequest ColorTableRequest) error {
+	// verify HTTP header
+	contentType := request.Header.Get("Content-Type")
+	if !strings.HasPrefix(strings.ToLower(contentType), "application/json") {
+		return fmt.Errorf("unexpected or missing HTTP header field Content-Type, value = [%s], expected 'application/json'", contentType)
+	}
+
+	// verify Type
+	if colorTableRequest.Type != TypeColorTableRequest {
+		return fmt.Errorf("unexpected request Type [%v]", colorTableRequest.Type)
+	}
+
+	// verify ID
+	if len(colorTableRequest.ID) > 1024 {
+		return errors.New("ID must be 0-1024 characters long")
+	}
+
+	// verify name (path)
+	if !colorTableNameRegex.MatchString(name) {
+		return errors.New("name must be 1-64 characters long (letters, digits, '_', '.', '-', starting with letter or digit)")
+	}
+
+	// verify Description
+	if len(colorTableRequest.Attributes.Description) > 1024 {
+		return errors.New("description must be 0-1024 characters long")
+	}
+
+	// verify ColorTextFileContent
+	return verifyColorTextFileContent(colorTableRequest.Attributes.ColorTextFileContent)
+}
+
+/*
+buildColorTableResponse builds HTTP responses with specified status and body.
+*/
+func buildColorTableResponse(writer http.ResponseWriter, request *http.Request, httpStatus int, colorTableResponse ColorTableResponse) {
+	// response metadata (versions, processing duration, cache hit)
+	colorTableResponse.Meta = newResponseMeta(request.Context())
+
+	// color tables are tenant specific
+	writer.Header().Set("Cache-Control", "no-store")
+
+	// encode (streaming) and send response
+	streamJSONResponse(writer, request, httpStatus, colorTableResponse, false)
+}
@@ -2,14 +2,17 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"log/slog"
 	"math"
 	"os"
-	"os/exec"
+	"runtime/debug"
+	"strconv"
 	"strings"
-	"syscall"
+	"sync"
+	"sync/atomic"
 	"time"
 	"unicode"
 )
@@ -56,6 +59,28 @@ const (
 	TypeHistogramResponse        = "HistogramResponse"
 	TypeElevationProfileRequest  = "ElevationProfileRequest"
 	TypeElevationProfileResponse = "ElevationProfileResponse"
+	TypeVisualizeRequest         = "VisualizeRequest"
+	TypeVisualizeResponse        = "VisualizeResponse"
+	TypeJobRequest               = "JobRequest"
+	TypeJobResponse              = "JobResponse"
+	TypeExportRequest            = "ExportRequest"
+	TypeExportResponse           = "ExportResponse"
+	TypeCompareRequest           = "CompareRequest"
+	TypeCompareResponse          = "CompareResponse"
+	TypeAspectRoseRequest        = "AspectRoseRequest"
+	TypeAspectRoseResponse       = "AspectRoseResponse"
+	TypeElevationRangeRequest    = "ElevationRangeRequest"
+	TypeElevationRangeResponse   = "ElevationRangeResponse"
+	TypeContourLineRequest       = "ContourLineRequest"
+	TypeContourLineResponse      = "ContourLineResponse"
+	TypeElevationMatrixRequest   = "ElevationMatrixRequest"
+	TypeElevationMatrixResponse  = "ElevationMatrixResponse"
+	TypeLegendRequest            = "LegendRequest"
+	TypeLegendResponse           = "LegendResponse"
+	TypeColorTableRequest        = "ColorTableRequest"
+	TypeColorTableResponse       = "ColorTableResponse"
+	TypeContoursExportRequest    = "ContoursExportRequest"
+	TypeContoursExportResponse   = "ContoursExportResponse"
 )
 
 // request body limits (in bytes, for security reasons)
@@ -63,7 +88,7 @@ const (
 	MaxPointRequestBodySize            = 4 * 1024
 	MaxGpxRequestBodySize              = 24 * 1024 * 1024
 	MaxGpxAnalyzeRequestBodySize       = 24 * 1024 * 1024
-	MaxContoursRequestBodySize         = 4 * 1024
+	MaxContoursRequestBodySize         = 64 * 1024
 	MaxHillshadeRequestBodySize        = 4 * 1024
 	MaxSlopeRequestBodySize            = 16 * 1024
 	MaxAspectRequestBodySize           = 16 * 1024
@@ -74,6 +99,17 @@ const (
 	MaxColorReliefRequestBodySize      = 4 * 1024
 	MaxHistogramRequestBodySize        = 4 * 1024
 	MaxElevationProfileRequestBodySize = 4 * 1024
+	MaxVisualizeRequestBodySize        = 16 * 1024
+	MaxJobRequestBodySize              = 24 * 1024 * 1024
+	MaxExportRequestBodySize           = 64 * 1024
+	MaxCompareRequestBodySize          = 4 * 1024
+	MaxAspectRoseRequestBodySize       = 64 * 1024
+	MaxElevationRangeRequestBodySize   = 4 * 1024
+	MaxContourLineRequestBodySize      = 4 * 1024
+	MaxElevationMatrixRequestBodySize  = 4 * 1024
+	MaxLegendRequestBodySize           = 16 * 1024
+	MaxColorTableRequestBodySize       = 16 * 1024
+	MaxContoursExportRequestBodySize   = 64 * 1024
 )
 
 // ErrorObject represents error details.
@@ -109,6 +145,16 @@ var elevationSources = []ElevationSource{
 	{Code: "DE-TH", Name: "Thüringen", Attribution: "© GDI-Th (2025), dl-de/by-2-0"},
 }
 
+// ErrSourceUnavailable indicates that the elevation source (state) of a tile is temporarily disabled.
+var ErrSourceUnavailable = errors.New("elevation source temporarily unavailable")
+
+// TileError represents the error of a tile which could not be processed (partial success of multi-tile responses).
+type TileError struct {
+	TileIndex string
+	Origin    string
+	Detail    string
+}
+
 // WGS84BoundingBox represents min/max longitude and latitude coordinates in WGS84.
 type WGS84BoundingBox struct {
 	MinLon float64
@@ -117,6 +163,13 @@ type WGS84BoundingBox struct {
 	MaxLat float64
 }
 
+// GeoTIFFOptions represents the creation options of returned GeoTIFF files (zero value = uncompressed, stripped).
+type GeoTIFFOptions struct {
+	Compression string // none, deflate, zstd, lzw (default: none)
+	Tiled       bool   // internal tiling (256x256 blocks) instead of strips
+	Predictor   int    // 1 = none, 2 = horizontal differencing (only with compression, default: 1)
+}
+
 //
 // --------------------------------------------------------------------------------
 // Request  : Client -> PointRequest  -> Service
@@ -128,26 +181,44 @@ type PointRequest struct {
 	Type       string
 	ID         string
 	Attributes struct {
-		Longitude float64
-		Latitude  float64
+		Longitude      float64
+		Latitude       float64
+		Place          string          // optional: place name (e.g. "Feldberg"), geocoded into lon/lat coordinates
+		VerticalOffset float64         // optional: added to elevation (e.g. -312.5 for height relative to reference level 312.5 m)
+		ReferencePoint *ReferencePoint // optional: elevation difference relative to this point
 	}
 }
 
+// ReferencePoint represents a second point (lon/lat) for relative elevations (e.g. summit relative to current position).
+type ReferencePoint struct {
+	Longitude float64
+	Latitude  float64
+}
+
 // PointResponse represents elevation for point response.
 type PointResponse struct {
 	Type       string
 	ID         string
 	Attributes struct {
-		Longitude   float64
-		Latitude    float64
-		Elevation   float64
-		Actuality   string
-		Origin      string
-		Attribution string
-		TileIndex   string
-		IsError     bool
-		Error       ErrorObject
+		Longitude           float64
+		Latitude            float64
+		Place               string
+		PlaceName           string // display name of geocoded place
+		VerticalOffset      float64
+		Elevation           float64
+		Actuality           string
+		Origin              string
+		Attribution         string
+		TileIndex           string
+		ReferencePoint      *ReferencePoint
+		ReferenceElevation  *float64 // elevation of reference point (null without reference point)
+		ReferenceTileIndex  string
+		ElevationDifference *float64 // elevation minus reference elevation (positive = point is higher)
+		Warnings            []string // non-fatal conditions (e.g. tiles not processed, defaults applied)
+		IsError             bool
+		Error               ErrorObject
 	}
+	Meta ResponseMeta
 }
 
 // --------------------------------------------------------------------------------
@@ -160,28 +231,45 @@ type UTMPointRequest struct {
 	Type       string
 	ID         string
 	Attributes struct {
-		Zone     int
-		Easting  float64
-		Northing float64
+		Zone           int
+		Easting        float64
+		Northing       float64
+		VerticalOffset float64 // optional: added to elevation (e.g. -312.5 for height relative to reference level 312.5 m)
+		Interpolation  string  // optional: nearest (default, value of grid cell), bilinear (four surrounding cell centers)
+		Debug          bool    // optional: return contributing cells and weights (bilinear only)
 	}
 }
 
+// InterpolationCell represents a grid cell contributing to an interpolated elevation.
+type InterpolationCell struct {
+	Easting   float64 // cell center
+	Northing  float64 // cell center
+	Elevation float64
+	Weight    float64 // normalized weight (sum of all weights = 1)
+	TileIndex string
+}
+
 // UTMPointResponse represents elevation for utm point response.
 type UTMPointResponse struct {
 	Type       string
 	ID         string
 	Attributes struct {
-		Zone        int
-		Easting     float64
-		Northing    float64
-		Elevation   float64
-		Actuality   string
-		Origin      string
-		Attribution string
-		TileIndex   string
-		IsError     bool
-		Error       ErrorObject
+		Zone           int
+		Easting        float64
+		Northing       float64
+		VerticalOffset float64
+		Interpolation  string
+		Elevation      float64
+		Actuality      string
+		Origin         string
+		Attribution    string
+		TileIndex      string
+		Cells          []InterpolationCell // contributing cells (bilinear interpolation with debug only)
+		Warnings       []string            // non-fatal conditions (e.g. tiles not processed, defaults applied)
+		IsError        bool
+		Error          ErrorObject
 	}
+	Meta ResponseMeta
 }
 
 // --------------------------------------------------------------------------------
@@ -194,22 +282,51 @@ type GPXRequest struct {
 	Type       string
 	ID         string
 	Attributes struct {
-		GPXData string // base64 encoded GPX XML string
+		GPXData       string // base64 encoded GPX XML string
+		WaypointsOnly bool   // optional: correct waypoints only (routes and tracks unchanged)
+		RoutesOnly    bool   // optional: correct routes only (waypoints and tracks unchanged)
+		TracksOnly    bool   // optional: correct tracks only (waypoints and routes unchanged)
 	}
 }
 
+// UncoveredPoint represents a GPX point outside DGM coverage (elevation not corrected).
+type UncoveredPoint struct {
+	PointType          string // e.g. waypoint, route 0 point, track 0 segment 1 point
+	Index              int    // index of point (within waypoints, route or segment)
+	Longitude          float64
+	Latitude           float64
+	NearestTile        string  // nearest covered tile (empty: none within search radius of 25 km)
+	DistanceToCoverage float64 // distance to nearest covered tile in meters (-1: none within search radius)
+}
+
+// GPXPointTypes represents the types of GPX points to be corrected (waypoints, route points, track points).
+type GPXPointTypes struct {
+	Waypoints bool
+	Routes    bool
+	Tracks    bool
+}
+
+// allGPXPointTypes corrects all GPX points.
+var allGPXPointTypes = GPXPointTypes{Waypoints: true, Routes: true, Tracks: true}
+
 // GPXResponse represents modified GPX data for GPX response.
 type GPXResponse struct {
 	Type       string
 	ID         string
 	Attributes struct {
-		GPXData      string // base64 encoded GPX XML string
-		GPXPoints    int
-		DGMPoints    int
-		Attributions []string
-		IsError      bool
-		Error        ErrorObject
+		GPXData         string // base64 encoded GPX XML string
+		WaypointsOnly   bool
+		RoutesOnly      bool
+		TracksOnly      bool
+		GPXPoints       int
+		DGMPoints       int
+		UncoveredPoints []UncoveredPoint // points outside DGM coverage (limited to first 1000 points)
+		Attributions    []string
+		Warnings        []string // non-fatal conditions (e.g. tiles not processed, defaults applied)
+		IsError         bool
+		Error           ErrorObject
 	}
+	Meta ResponseMeta
 }
 
 // --------------------------------------------------------------------------------
@@ -225,9 +342,25 @@ type GpxAnalyzeResult struct {
 	Creator     string
 	Time        *time.Time
 	TotalPoints int
+	Ruggedness  *GpxRuggedness // terrain ruggedness along all tracks (nil if no DGM data)
 	Tracks      []GpxAnalyzeTrackResult
 }
 
+// GpxRuggedness holds the terrain ruggedness (technicality) of a route derived from DGM slope and TRI values
+// sampled along all tracks.
+type GpxRuggedness struct {
+	SampledPoints  int     // track points with DGM data (3x3 cells)
+	SampleInterval float64 // min distance between sampled points in meters
+	MeanSlope      float64 // degrees (Horn)
+	MaxSlope       float64 // degrees (Horn)
+	SteepShare     float64 // percent of sampled points with slope >= 20 degrees
+	MeanTRI        float64 // meters (Riley)
+	MaxTRI         float64 // meters (Riley)
+	RoughShare     float64 // percent of sampled points with TRI >= 1.5 meters
+	Score          float64 // 0 (flat and smooth) - 100 (steep and rugged)
+	Rating         string  // easy, moderate, difficult, very difficult, extreme
+}
+
 // GpxAnalyzeTrackResult holds data for a single track.
 type GpxAnalyzeTrackResult struct {
 	Name        string
@@ -247,6 +380,9 @@ type GpxAnalyzeSegmentResult struct {
 	Points    int
 	Length2D  float64
 	Length3D  float64
+	// Surface (3D distance based on DGM elevations instead of GPS elevations)
+	SurfaceDistance         float64
+	SurfaceDistanceCoverage float64 // percent of points with DGM elevation (legs without DGM elevation count 2D)
 	// Moving
 	MovingTime      float64
 	StoppedTime     float64
@@ -262,10 +398,63 @@ type GpxAnalyzeSegmentResult struct {
 	DownhillWMA        float64
 	UphillUnfiltered   float64
 	DownhillUnfiltered float64
+	// Sections (ascent, descent, flat)
+	Ascents      int
+	Descents     int
+	FlatSections int
+	Sections     []GpxAnalyzeSection
+	// Anomalies (GPS jumps, recording gaps, duplicate points, elevation spikes)
+	Anomalies []GpxAnalyzeAnomaly
+	// Sun exposure (if requested)
+	SunExposure *GpxSunExposure
 	// Point Details for verbose output
 	PointDetails []GpxAnalyzePointDetail
 }
 
+// GpxAnalyzeSection holds statistics for a section of a segment with uniform trend (ascent, descent, flat).
+type GpxAnalyzeSection struct {
+	Type                string // ascent, descent, flat
+	StartIndex          int    // index of first point in segment
+	EndIndex            int    // index of last point in segment
+	Distance            float64
+	Duration            float64 // in seconds (0 without timestamps)
+	StartElevation      float64
+	EndElevation        float64
+	ElevationDifference float64 // end - start elevation
+	Uphill              float64
+	Downhill            float64
+	AverageGradient     float64 // in percent (elevation difference / distance)
+}
+
+// GpxSunExposure holds the sun exposure (sunny, shaded by terrain, night) of a segment.
+type GpxSunExposure struct {
+	SampledPoints  int     // track points with DGM data and time
+	SunnyDistance  float64 // meters
+	ShadedDistance float64 // meters (terrain casts shadow or slope faces away from sun)
+	NightDistance  float64 // meters (sun below horizon)
+	SunnyShare     float64 // percent of distance with exposure
+	Sections       []GpxSunExposureSection
+}
+
+// GpxSunExposureSection holds a section of a segment with uniform sun exposure.
+type GpxSunExposureSection struct {
+	Exposure   string // sunny, shaded, night
+	StartIndex int    // index of first point in segment
+	EndIndex   int    // index of last point in segment
+	StartTime  time.Time
+	EndTime    time.Time
+	Distance   float64 // meters
+}
+
+// GpxAnalyzeAnomaly holds a detected anomaly of a segment (candidate for cleanup).
+type GpxAnalyzeAnomaly struct {
+	Type        string  // jump, gap, duplicate, spike
+	StartIndex  int     // index of first affected point in segment
+	EndIndex    int     // index of last affected point in segment
+	Value       float64 // jump: speed in m/s, gap: duration in seconds, duplicate: number of points, spike: height in meters
+	Description string
+}
+
 // GpxAnalyzePointDetail holds detailed information for a single track point.
 type GpxAnalyzePointDetail struct {
 	Timestamp          time.Time
@@ -278,25 +467,80 @@ type GpxAnalyzePointDetail struct {
 	CumulativeDownhill float64
 }
 
+// GpxValidationResult holds the comparison of original GPX elevations with DGM elevations (track points).
+type GpxValidationResult struct {
+	Points                 int // all track points
+	ValidatedPoints        int // points with original and DGM elevation
+	PointsWithoutElevation int // points without original elevation
+	PointsWithoutDGM       int // points without DGM elevation (outside coverage or nodata)
+	Statistics             GpxValidationStatistics
+	Tracks                 []GpxValidationTrackResult
+}
+
+// GpxValidationTrackResult holds the validation of a single track.
+type GpxValidationTrackResult struct {
+	Name     string
+	Segments []GpxValidationSegmentResult
+}
+
+// GpxValidationSegmentResult holds the validation of a single segment.
+type GpxValidationSegmentResult struct {
+	Statistics   GpxValidationStatistics
+	PointDetails []GpxValidationPointDetail
+}
+
+// GpxValidationStatistics holds aggregated error statistics (deviation = GPX elevation - DGM elevation, in meters).
+type GpxValidationStatistics struct {
+	ValidatedPoints       int
+	Bias                  float64 // mean deviation (systematic offset, e.g. barometric altimeter not calibrated)
+	MeanAbsoluteError     float64
+	RMSE                  float64 // root mean square error
+	MaxDeviation          float64 // deviation with largest absolute value (signed)
+	MaxDeviationLatitude  float64
+	MaxDeviationLongitude float64
+}
+
+// GpxValidationPointDetail holds the validation of a single track point (nil values: elevation not available).
+type GpxValidationPointDetail struct {
+	Latitude     float64
+	Longitude    float64
+	GPXElevation *float64
+	DGMElevation *float64
+	Deviation    *float64 // GPX elevation - DGM elevation
+	TileIndex    string
+}
+
 // GPXAnalyzeRequest represents GPX data for GPX analyze request.
 type GPXAnalyzeRequest struct {
 	Type       string
 	ID         string
 	Attributes struct {
-		GPXData string // base64 encoded GPX XML string
+		GPXData     string              // base64 encoded GPX XML string
+		Mode        string              // optional: analyze (default), validate (compare original elevations with DGM, track unchanged)
+		SunExposure *SunExposureOptions // optional: sun exposure (sunny, shaded, night) along tracks (mode analyze only)
 	}
 }
 
+// SunExposureOptions represents the date/time settings for the sun exposure along tracks.
+type SunExposureOptions struct {
+	StartTime string  // optional: RFC 3339 start time (e.g. 2025-01-18T10:00:00+01:00), default: timestamps of track points
+	Speed     float64 // optional: speed in km/h for tracks without timestamps (default 4 km/h)
+}
+
 // GPXAnalyzeResponse represents modified GPX data for GPX analyze response.
 type GPXAnalyzeResponse struct {
 	Type       string
 	ID         string
 	Attributes struct {
-		GPXData          string // base64 encoded GPX XML string
-		GpxAnalyzeResult GpxAnalyzeResult
-		IsError          bool
-		Error            ErrorObject
+		GPXData             string // base64 encoded GPX XML string
+		Mode                string
+		GpxAnalyzeResult    GpxAnalyzeResult
+		GpxValidationResult *GpxValidationResult // mode 'validate' only
+		Warnings            []string             // non-fatal conditions (e.g. tiles not processed, defaults applied)
+		IsError             bool
+		Error               ErrorObject
 	}
+	Meta ResponseMeta
 }
 
 // --------------------------------------------------------------------------------
@@ -309,19 +553,46 @@ type ContoursRequest struct {
 	Type       string
 	ID         string
 	Attributes struct {
-		Zone         int
-		Easting      float64
-		Northing     float64
-		Longitude    float64
-		Latitude     float64
-		Equidistance float64
+		Zone               int
+		Easting            float64
+		Northing           float64
+		Longitude          float64
+		Latitude           float64
+		Place              string // optional: place name (e.g. "Feldberg"), geocoded into lon/lat coordinates
+		AdministrativeArea string // optional: name or key of administrative area (e.g. Gemeinde, Landkreis) instead of coordinates
+		Equidistance       float64
+		Area               *ContoursArea    // optional: seamless contours for an area spanning several tiles (instead of point)
+		ClipPolygon        *GeoJSONGeometry // optional: contours clipped to polygon (e.g. property boundary, study area) instead of point
+		VerticalOffset     float64          // optional: added to elevations before contouring (e.g. -312.5 for heights relative to reference level 312.5 m)
+		AttributeName      string           // optional: name of elevation attribute (default: Hoehe, e.g. ELEV)
+		Precision          *int             // optional: decimal places of elevation attribute (0-3, default: not rounded)
+		Unit               string           // optional: unit of equidistance and elevation attribute (m = default, ft)
 	}
 }
 
+// GeoJSONGeometry represents a GeoJSON polygon (type Polygon or MultiPolygon, holes supported) or a Feature with such a geometry.
+// Coordinates are UTM (easting, northing) if Zone is set, otherwise lon/lat (longitude, latitude).
+type GeoJSONGeometry struct {
+	Type        string           // Polygon, MultiPolygon or Feature
+	Coordinates json.RawMessage  // Polygon: rings, MultiPolygon: polygons (rings)
+	Geometry    *GeoJSONGeometry // Feature only
+}
+
+// ContoursArea represents an area (bounding box or polygon) for seamless contours across several tiles.
+// Coordinates are UTM (easting, northing) if Zone is set, otherwise lon/lat (longitude, latitude).
+type ContoursArea struct {
+	MinX    float64      // bounding box: min easting or min longitude
+	MinY    float64      // bounding box: min northing or min latitude
+	MaxX    float64      // bounding box: max easting or max longitude
+	MaxY    float64      // bounding box: max northing or max latitude
+	Polygon [][2]float64 // optional: polygon ring (x, y), replaces bounding box
+}
+
 // Contour represents contours lines for one tile.
 type Contour struct {
 	Data        []byte
 	DataFormat  string
+	Filename    string // suggested file name (e.g. 32_497_5670_hillshade_igor_2024.png)
 	Actuality   string
 	Origin      string
 	Attribution string
@@ -333,16 +604,29 @@ type ContoursResponse struct {
 	Type       string
 	ID         string
 	Attributes struct {
-		Zone         int
-		Easting      float64
-		Northing     float64
-		Longitude    float64
-		Latitude     float64
-		Equidistance float64
-		Contours     []Contour
-		IsError      bool
-		Error        ErrorObject
+		Zone               int
+		Easting            float64
+		Northing           float64
+		Longitude          float64
+		Latitude           float64
+		Place              string
+		PlaceName          string // display name of geocoded place
+		AdministrativeArea string
+		Equidistance       float64
+		VerticalOffset     float64
+		AttributeName      string
+		Precision          *int
+		Unit               string
+		Area               *ContoursArea
+		ClipPolygon        *GeoJSONGeometry
+		AreaTiles          []string // tiles used for area contours
+		Contours           []Contour
+		TileErrors         []TileError // tiles which could not be processed (partial success)
+		Warnings           []string    // non-fatal conditions (e.g. tiles not processed, defaults applied)
+		IsError            bool
+		Error              ErrorObject
 	}
+	Meta ResponseMeta
 }
 
 // --------------------------------------------------------------------------------
@@ -360,23 +644,48 @@ type HillshadeRequest struct {
 		Northing             float64
 		Longitude            float64
 		Latitude             float64
-		GradientAlgorithm    string // Horn, ZevenbergenThorne
-		VerticalExaggeration float64
+		Place                string               // optional: place name (e.g. "Feldberg"), geocoded into lon/lat coordinates
+		AdministrativeArea   string               // optional: name or key of administrative area (e.g. Gemeinde, Landkreis) instead of coordinates
+		GradientAlgorithm    string               // Horn, ZevenbergenThorne
+		VerticalExaggeration VerticalExaggeration // z-factor (0.0 - 100.0) or "auto" (derived from relief of tile)
 		AzimuthOfLight       uint
 		AltitudeOfLight      uint
-		ShadingVariant       string // regular, combined, multidirectional, igor
+		ShadingVariant       ShadingVariants // regular, combined, multidirectional, igor, textured, custom (single variant or list of variants)
+		LightSources         []LightSource   // light sources for shading variant 'custom' (1-8 light sources, blended by weight)
+		OutputFormat         string          // geotiff, png (default: geotiff for UTM, png for lon/lat coordinates)
+		GeoTIFFOptions       GeoTIFFOptions  // optional: creation options of returned GeoTIFF files (compression, tiling, predictor)
 	}
 }
 
+// ShadingVariants represents one or more shading variants (JSON: string or array of strings).
+type ShadingVariants []string
+
+// VerticalExaggeration represents the z-factor of a hillshade (JSON: number or "auto").
+type VerticalExaggeration float64
+
+// VerticalExaggerationAuto derives the z-factor from the relief of each tile (JSON: "auto").
+const VerticalExaggerationAuto VerticalExaggeration = -1
+
+// LightSource represents one light source of a custom (blended) hillshade.
+type LightSource struct {
+	Azimuth  float64 // degrees (0 - 360, clockwise from north)
+	Altitude float64 // degrees (0 - 90, above horizon)
+	Weight   float64 // relative weight (> 0, normalized over all light sources)
+}
+
 // Hillshade represents hillshade object (PNG or GeoTIFF) for one tile.
 type Hillshade struct {
-	Data        []byte
-	DataFormat  string
-	Actuality   string
-	Origin      string
-	Attribution string
-	TileIndex   string
-	BoundingBox WGS84BoundingBox
+	Data                 []byte
+	DataFormat           string
+	Filename             string // suggested file name (e.g. 32_497_5670_hillshade_igor_2024.png)
+	ShadingVariant       string
+	VerticalExaggeration float64 // applied z-factor (e.g. derived from relief for "auto")
+	Actuality            string
+	Origin               string
+	Attribution          string
+	TileIndex            string
+	BoundingBox          WGS84BoundingBox
+	SourceFiles          []SourceFile // source GeoTIFF files (delivery file name and checksum)
 }
 
 // HillshadeResponse represents Hillshade objects for compressed hillshade response.
@@ -389,15 +698,23 @@ type HillshadeResponse struct {
 		Northing             float64
 		Longitude            float64
 		Latitude             float64
+		Place                string
+		PlaceName            string // display name of geocoded place
+		AdministrativeArea   string
 		GradientAlgorithm    string
-		VerticalExaggeration float64
+		VerticalExaggeration VerticalExaggeration
 		AzimuthOfLight       uint
 		AltitudeOfLight      uint
-		ShadingVariant       string
+		ShadingVariant       ShadingVariants
+		LightSources         []LightSource
+		OutputFormat         string
 		Hillshades           []Hillshade
+		TileErrors           []TileError // tiles which could not be processed (partial success)
+		Warnings             []string    // non-fatal conditions (e.g. tiles not processed, defaults applied)
 		IsError              bool
 		Error                ErrorObject
 	}
+	Meta ResponseMeta
 }
 
 // --------------------------------------------------------------------------------
@@ -415,9 +732,17 @@ type SlopeRequest struct {
 		Northing             float64
 		Longitude            float64
 		Latitude             float64
+		Place                string // optional: place name (e.g. "Feldberg"), geocoded into lon/lat coordinates
+		AdministrativeArea   string // optional: name or key of administrative area (e.g. Gemeinde, Landkreis) instead of coordinates
 		GradientAlgorithm    string // Horn, ZevenbergenThorne
 		ColorTextFileContent []string
-		ColoringAlgorithm    string // interpolation, rounding
+		ColorTableName       string         // optional: named color table (see /v1/colortables/{name}) instead of ColorTextFileContent
+		ColoringAlgorithm    string         // interpolation, rounding
+		OutputFormat         string         // geotiff, png (default: geotiff for UTM, png for lon/lat coordinates)
+		GeoTIFFOptions       GeoTIFFOptions // optional: creation options of returned GeoTIFF files (compression, tiling, predictor)
+		NoDataHandling       string         // optional: rendering of nodata areas: transparent, fill, preserve (default: preserve = color text file as given)
+		NoDataColor          string         // fill color (R G B) for NoDataHandling fill (e.g. "255 255 255")
+		MaskWater            bool           // optional: set water bodies (lakes, rivers) to nodata (requires configured water mask)
 	}
 }
 
@@ -425,11 +750,13 @@ type SlopeRequest struct {
 type Slope struct {
 	Data        []byte
 	DataFormat  string
+	Filename    string // suggested file name (e.g. 32_497_5670_hillshade_igor_2024.png)
 	Actuality   string
 	Origin      string
 	Attribution string
 	TileIndex   string
 	BoundingBox WGS84BoundingBox
+	SourceFiles []SourceFile // source GeoTIFF files (delivery file name and checksum)
 }
 
 // SlopeResponse represents Slope objects for compressed slope response.
@@ -442,13 +769,21 @@ type SlopeResponse struct {
 		Northing             float64
 		Longitude            float64
 		Latitude             float64
+		Place                string
+		PlaceName            string // display name of geocoded place
+		AdministrativeArea   string
 		GradientAlgorithm    string
 		ColorTextFileContent []string
+		ColorTableName       string
 		ColoringAlgorithm    string // interpolation, rounding
+		OutputFormat         string
 		Slopes               []Slope
+		TileErrors           []TileError // tiles which could not be processed (partial success)
+		Warnings             []string    // non-fatal conditions (e.g. tiles not processed, defaults applied)
 		IsError              bool
 		Error                ErrorObject
 	}
+	Meta ResponseMeta
 }
 
 // --------------------------------------------------------------------------------
@@ -466,9 +801,16 @@ type AspectRequest struct {
 		Northing             float64
 		Longitude            float64
 		Latitude             float64
+		Place                string // optional: place name (e.g. "Feldberg"), geocoded into lon/lat coordinates
 		GradientAlgorithm    string // Horn, ZevenbergenThorne
 		ColorTextFileContent []string
-		ColoringAlgorithm    string // interpolation, rounding
+		ColorTableName       string         // optional: named color table (see /v1/colortables/{name}) instead of ColorTextFileContent
+		ColoringAlgorithm    string         // interpolation, rounding
+		OutputFormat         string         // geotiff, png (default: geotiff for UTM, png for lon/lat coordinates)
+		GeoTIFFOptions       GeoTIFFOptions // optional: creation options of returned GeoTIFF files (compression, tiling, predictor)
+		NoDataHandling       string         // optional: rendering of nodata areas: transparent, fill, preserve (default: preserve = color text file as given)
+		NoDataColor          string         // fill color (R G B) for NoDataHandling fill (e.g. "255 255 255")
+		ZeroForFlat          bool           // flat areas (slope 0) as 0 instead of no-data (gdaldem option -zero_for_flat)
 	}
 }
 
@@ -476,11 +818,13 @@ type AspectRequest struct {
 type Aspect struct {
 	Data        []byte
 	DataFormat  string
+	Filename    string // suggested file name (e.g. 32_497_5670_hillshade_igor_2024.png)
 	Actuality   string
 	Origin      string
 	Attribution string
 	TileIndex   string
 	BoundingBox WGS84BoundingBox
+	SourceFiles []SourceFile // source GeoTIFF files (delivery file name and checksum)
 }
 
 // AspectResponse represents Aspect objects for compressed aspect response.
@@ -493,13 +837,21 @@ type AspectResponse struct {
 		Northing             float64
 		Longitude            float64
 		Latitude             float64
+		Place                string
+		PlaceName            string // display name of geocoded place
 		GradientAlgorithm    string
 		ColorTextFileContent []string
+		ColorTableName       string
 		ColoringAlgorithm    string // interpolation, rounding
+		OutputFormat         string
+		ZeroForFlat          bool
 		Aspects              []Aspect
+		TileErrors           []TileError // tiles which could not be processed (partial success)
+		Warnings             []string    // non-fatal conditions (e.g. tiles not processed, defaults applied)
 		IsError              bool
 		Error                ErrorObject
 	}
+	Meta ResponseMeta
 }
 
 // --------------------------------------------------------------------------------
@@ -517,8 +869,15 @@ type TPIRequest struct {
 		Northing             float64
 		Longitude            float64
 		Latitude             float64
+		Place                string // optional: place name (e.g. "Feldberg"), geocoded into lon/lat coordinates
 		ColorTextFileContent []string
-		ColoringAlgorithm    string // interpolation, rounding
+		ColorTableName       string         // optional: named color table (see /v1/colortables/{name}) instead of ColorTextFileContent
+		ColoringAlgorithm    string         // interpolation, rounding
+		OutputFormat         string         // geotiff, png (default: geotiff for UTM, png for lon/lat coordinates)
+		GeoTIFFOptions       GeoTIFFOptions // optional: creation options of returned GeoTIFF files (compression, tiling, predictor)
+		NoDataHandling       string         // optional: rendering of nodata areas: transparent, fill, preserve (default: preserve = color text file as given)
+		NoDataColor          string         // fill color (R G B) for NoDataHandling fill (e.g. "255 255 255")
+		MaskWater            bool           // optional: set water bodies (lakes, rivers) to nodata (requires configured water mask)
 	}
 }
 
@@ -526,11 +885,13 @@ type TPIRequest struct {
 type TPI struct {
 	Data        []byte
 	DataFormat  string
+	Filename    string // suggested file name (e.g. 32_497_5670_hillshade_igor_2024.png)
 	Actuality   string
 	Origin      string
 	Attribution string
 	TileIndex   string
 	BoundingBox WGS84BoundingBox
+	SourceFiles []SourceFile // source GeoTIFF files (delivery file name and checksum)
 }
 
 // TPIResponse represents TPI objects for compressed TPI response.
@@ -543,12 +904,19 @@ type TPIResponse struct {
 		Northing             float64
 		Longitude            float64
 		Latitude             float64
+		Place                string
+		PlaceName            string // display name of geocoded place
 		ColorTextFileContent []string
+		ColorTableName       string
 		ColoringAlgorithm    string // interpolation, rounding
+		OutputFormat         string
 		TPIs                 []TPI
+		TileErrors           []TileError // tiles which could not be processed (partial success)
+		Warnings             []string    // non-fatal conditions (e.g. tiles not processed, defaults applied)
 		IsError              bool
 		Error                ErrorObject
 	}
+	Meta ResponseMeta
 }
 
 // --------------------------------------------------------------------------------
@@ -566,8 +934,14 @@ type TRIRequest struct {
 		Northing             float64
 		Longitude            float64
 		Latitude             float64
+		Place                string // optional: place name (e.g. "Feldberg"), geocoded into lon/lat coordinates
 		ColorTextFileContent []string
-		ColoringAlgorithm    string // interpolation, rounding
+		ColorTableName       string         // optional: named color table (see /v1/colortables/{name}) instead of ColorTextFileContent
+		ColoringAlgorithm    string         // interpolation, rounding
+		OutputFormat         string         // geotiff, png (default: geotiff for UTM, png for lon/lat coordinates)
+		GeoTIFFOptions       GeoTIFFOptions // optional: creation options of returned GeoTIFF files (compression, tiling, predictor)
+		NoDataHandling       string         // optional: rendering of nodata areas: transparent, fill, preserve (default: preserve = color text file as given)
+		NoDataColor          string         // fill color (R G B) for NoDataHandling fill (e.g. "255 255 255")
 	}
 }
 
@@ -575,11 +949,13 @@ type TRIRequest struct {
 type TRI struct {
 	Data        []byte
 	DataFormat  string
+	Filename    string // suggested file name (e.g. 32_497_5670_hillshade_igor_2024.png)
 	Actuality   string
 	Origin      string
 	Attribution string
 	TileIndex   string
 	BoundingBox WGS84BoundingBox
+	SourceFiles []SourceFile // source GeoTIFF files (delivery file name and checksum)
 }
 
 // TRIResponse represents TRI objects for compressed TRI response.
@@ -592,12 +968,19 @@ type TRIResponse struct {
 		Northing             float64
 		Longitude            float64
 		Latitude             float64
+		Place                string
+		PlaceName            string // display name of geocoded place
 		ColorTextFileContent []string
+		ColorTableName       string
 		ColoringAlgorithm    string // interpolation, rounding
+		OutputFormat         string
 		TRIs                 []TRI
+		TileErrors           []TileError // tiles which could not be processed (partial success)
+		Warnings             []string    // non-fatal conditions (e.g. tiles not processed, defaults applied)
 		IsError              bool
 		Error                ErrorObject
 	}
+	Meta ResponseMeta
 }
 
 // --------------------------------------------------------------------------------
@@ -615,8 +998,15 @@ type RoughnessRequest struct {
 		Northing             float64
 		Longitude            float64
 		Latitude             float64
+		Place                string // optional: place name (e.g. "Feldberg"), geocoded into lon/lat coordinates
 		ColorTextFileContent []string
-		ColoringAlgorithm    string // interpolation, rounding
+		ColorTableName       string         // optional: named color table (see /v1/colortables/{name}) instead of ColorTextFileContent
+		ColoringAlgorithm    string         // interpolation, rounding
+		OutputFormat         string         // geotiff, png (default: geotiff for UTM, png for lon/lat coordinates)
+		GeoTIFFOptions       GeoTIFFOptions // optional: creation options of returned GeoTIFF files (compression, tiling, predictor)
+		NoDataHandling       string         // optional: rendering of nodata areas: transparent, fill, preserve (default: preserve = color text file as given)
+		NoDataColor          string         // fill color (R G B) for NoDataHandling fill (e.g. "255 255 255")
+		MaskWater            bool           // optional: set water bodies (lakes, rivers) to nodata (requires configured water mask)
 	}
 }
 
@@ -624,11 +1014,13 @@ type RoughnessRequest struct {
 type Roughness struct {
 	Data        []byte
 	DataFormat  string
+	Filename    string // suggested file name (e.g. 32_497_5670_hillshade_igor_2024.png)
 	Actuality   string
 	Origin      string
 	Attribution string
 	TileIndex   string
 	BoundingBox WGS84BoundingBox
+	SourceFiles []SourceFile // source GeoTIFF files (delivery file name and checksum)
 }
 
 // RoughnessResponse represents Roughness objects for compressed RI response.
@@ -641,12 +1033,19 @@ type RoughnessResponse struct {
 		Northing             float64
 		Longitude            float64
 		Latitude             float64
+		Place                string
+		PlaceName            string // display name of geocoded place
 		ColorTextFileContent []string
+		ColorTableName       string
 		ColoringAlgorithm    string // interpolation, rounding
+		OutputFormat         string
 		Roughnesses          []Roughness
+		TileErrors           []TileError // tiles which could not be processed (partial success)
+		Warnings             []string    // non-fatal conditions (e.g. tiles not processed, defaults applied)
 		IsError              bool
 		Error                ErrorObject
 	}
+	Meta ResponseMeta
 }
 
 // --------------------------------------------------------------------------------
@@ -667,12 +1066,40 @@ type RawTIFRequest struct {
 
 // RawTIF represents compressed RawTIF object for one tile.
 type RawTIF struct {
-	Data        []byte
-	DataFormat  string
-	Actuality   string
-	Origin      string
-	Attribution string
-	TileIndex   string
+	Data             []byte
+	DataFormat       string
+	Filename         string // suggested file name (e.g. 32_497_5670_hillshade_igor_2024.png)
+	Actuality        string
+	Origin           string
+	Attribution      string
+	TileIndex        string
+	SourceFiles      []SourceFile      // source GeoTIFF files (delivery file name and checksum)
+	NoDataStatistics *NoDataStatistics // nodata coverage of tile (rawtif request only)
+}
+
+// SourceFile represents a source GeoTIFF file (provenance of derived product, verifiable by checksum).
+type SourceFile struct {
+	Filename string // original delivery file name (e.g. dgm1_32_497_5670_1_nw_2024.tif)
+	SHA256   string // checksum of file content (hex)
+}
+
+// NoDataStatistics represents the nodata coverage of a tile (e.g. gaps at borders of elevation sources or water).
+type NoDataStatistics struct {
+	Cells       int     // all cells of tile
+	NoDataCells int     // cells without elevation data
+	NoDataShare float64 // percent of cells
+	GapCount    int     // number of gaps (connected nodata cells)
+	Gaps        []NoDataGap
+}
+
+// NoDataGap represents a gap (connected nodata cells, largest gaps first) of a tile.
+type NoDataGap struct {
+	Cells       int
+	MinEasting  float64 // bounding box of gap (outer edges of cells)
+	MinNorthing float64
+	MaxEasting  float64
+	MaxNorthing float64
+	AtBorder    bool // gap touches tile border (e.g. end of coverage of elevation source)
 }
 
 // RawTIFResponse represents RawTIF objects for RawTIF response.
@@ -680,13 +1107,16 @@ type RawTIFResponse struct {
 	Type       string
 	ID         string
 	Attributes struct {
-		Zone     int
-		Easting  float64
-		Northing float64
-		RawTIFs  []RawTIF
-		IsError  bool
-		Error    ErrorObject
+		Zone       int
+		Easting    float64
+		Northing   float64
+		RawTIFs    []RawTIF
+		TileErrors []TileError // tiles which could not be processed (partial success)
+		Warnings   []string    // non-fatal conditions (e.g. tiles not processed, defaults applied)
+		IsError    bool
+		Error      ErrorObject
 	}
+	Meta ResponseMeta
 }
 
 // --------------------------------------------------------------------------------
@@ -704,20 +1134,39 @@ type ColorReliefRequest struct {
 		Northing             float64
 		Longitude            float64
 		Latitude             float64
+		Place                string // optional: place name (e.g. "Feldberg"), geocoded into lon/lat coordinates
 		ColorTextFileContent []string
-		ColoringAlgorithm    string // interpolation, rounding
+		ColorTableName       string             // optional: named color table (see /v1/colortables/{name}) instead of ColorTextFileContent
+		ColoringAlgorithm    string             // interpolation, rounding
+		OutputFormat         string             // geotiff, png (default: geotiff for UTM, png for lon/lat coordinates)
+		GeoTIFFOptions       GeoTIFFOptions     // optional: creation options of returned GeoTIFF files (compression, tiling, predictor)
+		NoDataHandling       string             // optional: rendering of nodata areas: transparent, fill, preserve (default: preserve = color text file as given)
+		NoDataColor          string             // fill color (R G B) for NoDataHandling fill (e.g. "255 255 255")
+		ColorRamp            string             // automatic color relief: hypsometric, terrain, grayscale (instead of ColorTextFileContent)
+		StretchPercentiles   []float64          // lower and upper percentile of tile elevations for color ramp (default: 0, 100 = min, max)
+		BlendHillshade       *HillshadeBlending // optional: hillshade multiplied into colorized output (finished relief map)
 	}
 }
 
+// HillshadeBlending represents the hillshade blended (multiplied) into a color relief.
+type HillshadeBlending struct {
+	Opacity              float64 // strength of shading: 0.0 (no shading) - 1.0 (full multiply)
+	VerticalExaggeration float64 // z-factor (0.0 - 100.0)
+	AzimuthOfLight       uint
+	AltitudeOfLight      uint
+}
+
 // ColorRelief represents ColorRelief object (PNG or GeoTIFF) for one tile.
 type ColorRelief struct {
 	Data        []byte
 	DataFormat  string
+	Filename    string // suggested file name (e.g. 32_497_5670_hillshade_igor_2024.png)
 	Actuality   string
 	Origin      string
 	Attribution string
 	TileIndex   string
 	BoundingBox WGS84BoundingBox
+	SourceFiles []SourceFile // source GeoTIFF files (delivery file name and checksum)
 }
 
 // ColorReliefResponse represents ColorRelief objects for compressed ColorRelief response.
@@ -730,12 +1179,22 @@ type ColorReliefResponse struct {
 		Northing             float64
 		Longitude            float64
 		Latitude             float64
+		Place                string
+		PlaceName            string // display name of geocoded place
 		ColorTextFileContent []string
+		ColorTableName       string
 		ColoringAlgorithm    string // interpolation, rounding
+		OutputFormat         string
+		ColorRamp            string
+		StretchPercentiles   []float64
+		BlendHillshade       *HillshadeBlending
 		ColorReliefs         []ColorRelief
+		TileErrors           []TileError // tiles which could not be processed (partial success)
+		Warnings             []string    // non-fatal conditions (e.g. tiles not processed, defaults applied)
 		IsError              bool
 		Error                ErrorObject
 	}
+	Meta ResponseMeta
 }
 
 // --------------------------------------------------------------------------------
@@ -753,21 +1212,31 @@ type HistogramRequest struct {
 		Northing            float64
 		Longitude           float64
 		Latitude            float64
-		TypeOfVisualization string // rawtif, slope, aspect, roughness, tri, tpi
-		GradientAlgorithm   string // Horn, ZevenbergenThorne (only relevant for slope and aspect)
+		Place               string // optional: place name (e.g. "Feldberg"), geocoded into lon/lat coordinates
+		TypeOfVisualization string // rawtif (elevation), slope, aspect, roughness, tri, tpi, hillshade (gray values)
+		GradientAlgorithm   string // Horn, ZevenbergenThorne (only relevant for slope, aspect and hillshade)
 		TypeOfHistogram     string // standard, quantile
 		NumberOfBins        int
 		MinValue            string
 		MaxValue            string
+		ElevationBands      string    // preset for rawtif: 10m, 25m, 50m, 100m, 250m (overrides bins, min and max value)
+		Percentiles         []float64 // percentiles to calculate (default: 5, 25, 50, 75, 95)
 	}
 }
 
 // Histogram represents Histogram data object for one tile.
 type HistogramEntry struct {
-	LowerBound float64
-	UpperBound float64
-	BinCount   int
-	BinPercent float64
+	LowerBound        float64
+	UpperBound        float64
+	BinCount          int
+	BinPercent        float64
+	CumulativePercent float64 // percentage of binned values up to upper bound of bin
+}
+
+// HistogramPercentile represents the value of a percentile (e.g. 50 = median).
+type HistogramPercentile struct {
+	Percentile float64
+	Value      float64
 }
 type HistogramStatistic struct {
 	NoValueCount             int
@@ -781,6 +1250,7 @@ type HistogramStatistic struct {
 	MaxValueAbsolute         float64
 	MinValueHistogram        float64
 	MaxValueHistogram        float64
+	Percentiles              []HistogramPercentile // percentiles of all values (without no-value)
 }
 type Histogram struct {
 	Statistic   HistogramStatistic
@@ -801,16 +1271,23 @@ type HistogramResponse struct {
 		Northing            float64
 		Longitude           float64
 		Latitude            float64
+		Place               string
+		PlaceName           string // display name of geocoded place
 		TypeOfVisualization string
 		GradientAlgorithm   string
 		TypeOfHistogram     string
 		NumberOfBins        int
 		MinValue            string
 		MaxValue            string
+		ElevationBands      string
+		Percentiles         []float64
 		Histograms          []Histogram
+		TileErrors          []TileError // tiles which could not be processed (partial success)
+		Warnings            []string    // non-fatal conditions (e.g. tiles not processed, defaults applied)
 		IsError             bool
 		Error               ErrorObject
 	}
+	Meta ResponseMeta
 }
 
 // --------------------------------------------------------------------------------
@@ -836,18 +1313,30 @@ type ElevationProfileRequest struct {
 		PointB                PointDefinition
 		MaxTotalProfilePoints int
 		MinStepSize           float64 // in meters
+		ChartFormat           string  // optional: render profile as chart, svg or png (default: no chart)
+		ChartWidth            int     // optional: chart width in pixels (default 800)
+		ChartHeight           int     // optional: chart height in pixels (default 300)
 	}
 }
 
+// ProfileChart represents the elevation profile rendered as chart (svg or png, labeled axes).
+type ProfileChart struct {
+	Data       []byte
+	DataFormat string
+	Width      int
+	Height     int
+}
+
 // ProfilePoint represents a single point in the calculated elevation profile.
 type ProfilePoint struct {
-	Distance    float64
-	Elevation   float64
-	Longitude   float64
-	Latitude    float64
-	Easting     float64
-	Northing    float64
-	Attribution string
+	Distance        float64 // horizontal distance from point A
+	SurfaceDistance float64 // distance along terrain surface from point A
+	Elevation       float64
+	Longitude       float64
+	Latitude        float64
+	Easting         float64
+	Northing        float64
+	Attribution     string
 }
 
 // ElevationProfileResponse represents the calculated elevation profile.
@@ -859,11 +1348,592 @@ type ElevationProfileResponse struct {
 		PointB                PointDefinition
 		MaxTotalProfilePoints int
 		MinStepSize           float64
+		Length2D              float64 // horizontal length of profile (first to last profile point)
+		SurfaceDistance       float64 // distance along terrain surface (depends on step size, DGM resolution is 1 m)
 		Profile               []ProfilePoint
+		Chart                 *ProfileChart // profile chart (if requested)
 		Attributions          []string
+		Warnings              []string // non-fatal conditions (e.g. tiles not processed, defaults applied)
 		IsError               bool
 		Error                 ErrorObject
 	}
+	Meta ResponseMeta
+}
+
+// --------------------------------------------------------------------------------
+// Request  : Client -> VisualizeRequest  -> Service
+// Response : Client <- VisualizeResponse <- Service
+// --------------------------------------------------------------------------------
+
+// VisualizeRequest represents coordinates, type of visualization and settings for visualize request.
+type VisualizeRequest struct {
+	Type       string
+	ID         string
+	Attributes struct {
+		Zone                 int
+		Easting              float64
+		Northing             float64
+		Longitude            float64
+		Latitude             float64
+		Place                string // optional: place name (e.g. "Feldberg"), geocoded into lon/lat coordinates
+		TypeOfVisualization  string // slope, aspect, tri, tpi, roughness, hillshade, colorrelief
+		GradientAlgorithm    string // Horn, ZevenbergenThorne (slope, aspect, hillshade)
+		ColorTextFileContent []string
+		ColorTableName       string         // optional: named color table (see /v1/colortables/{name}) instead of ColorTextFileContent
+		ColoringAlgorithm    string         // interpolation, rounding
+		VerticalExaggeration float64        // hillshade
+		AzimuthOfLight       uint           // hillshade
+		AltitudeOfLight      uint           // hillshade
+		ShadingVariant       string         // hillshade: regular, combined, multidirectional, igor, textured
+		ZeroForFlat          bool           // aspect: flat areas as 0 instead of no-data
+		OutputFormat         string         // geotiff, png (default: geotiff for UTM, png for lon/lat coordinates)
+		GeoTIFFOptions       GeoTIFFOptions // optional: creation options of returned GeoTIFF files (compression, tiling, predictor)
+		NoDataHandling       string         // optional: rendering of nodata areas: transparent, fill, preserve (default: preserve = color text file as given)
+		NoDataColor          string         // fill color (R G B) for NoDataHandling fill (e.g. "255 255 255")
+	}
+}
+
+// Visualization represents visualization object (PNG or GeoTIFF) for one tile.
+type Visualization struct {
+	Data        []byte
+	DataFormat  string
+	Filename    string // suggested file name (e.g. 32_497_5670_hillshade_igor_2024.png)
+	Actuality   string
+	Origin      string
+	Attribution string
+	TileIndex   string
+	BoundingBox WGS84BoundingBox
+	SourceFiles []SourceFile // source GeoTIFF files (delivery file name and checksum)
+}
+
+// VisualizeResponse represents Visualization objects for compressed visualize response.
+type VisualizeResponse struct {
+	Type       string
+	ID         string
+	Attributes struct {
+		Zone                 int
+		Easting              float64
+		Northing             float64
+		Longitude            float64
+		Latitude             float64
+		Place                string
+		PlaceName            string // display name of geocoded place
+		TypeOfVisualization  string
+		GradientAlgorithm    string
+		ColorTextFileContent []string
+		ColorTableName       string
+		ColoringAlgorithm    string
+		VerticalExaggeration float64
+		AzimuthOfLight       uint
+		AltitudeOfLight      uint
+		ShadingVariant       string
+		ZeroForFlat          bool
+		OutputFormat         string
+		Visualizations       []Visualization
+		TileErrors           []TileError // tiles which could not be processed (partial success)
+		Warnings             []string    // non-fatal conditions (e.g. tiles not processed, defaults applied)
+		IsError              bool
+		Error                ErrorObject
+	}
+	Meta ResponseMeta
+}
+
+// --------------------------------------------------------------------------------
+// Request  : Client -> JobRequest  -> Service
+// Response : Client <- JobResponse <- Service
+// --------------------------------------------------------------------------------
+
+// JobRequest represents an asynchronous job (request for a POST endpoint, e.g. /v1/contours).
+type JobRequest struct {
+	Type       string
+	ID         string
+	Attributes struct {
+		Endpoint string          // path of endpoint (e.g. /v1/contours, /v1/export for bulk export)
+		Request  json.RawMessage // request for endpoint (e.g. ContoursRequest)
+		// optional: URL called (POST JobResponse) when the job is completed or failed, signed with HMAC-SHA256
+		// (header 'X-DTM-Signature-256': sha256=hex(hmac(CallbackSecret, timestamp + "." + body)), header 'X-DTM-Timestamp')
+		CallbackURL    string
+		CallbackSecret string // shared secret for signature (16-256 characters, required with CallbackURL)
+	}
+}
+
+// JobResponse represents the state of an asynchronous job.
+type JobResponse struct {
+	Type       string
+	ID         string
+	Attributes struct {
+		JobID        string
+		Endpoint     string
+		Status       string // queued, running, completed, failed, canceled
+		Created      string // RFC 3339
+		Started      string // RFC 3339 (empty if not started)
+		Finished     string // RFC 3339 (empty if not finished)
+		ResultStatus int    // HTTP status of endpoint response (0 if not finished)
+		ResultURL    string // location of endpoint response (e.g. /v1/jobs/{id}/result)
+		TilesTotal   int    // number of tiles to process (0 if unknown or not started)
+		TilesDone    int    // number of processed tiles
+		Progress     string // e.g. "14/60 tiles processed" (empty if unknown)
+		CallbackURL  string
+		Callback     string // state of callback: pending, delivered, failed (empty without callback)
+		IsError      bool
+		Error        ErrorObject
+	}
+	Meta ResponseMeta
+}
+
+// ExportRequest represents the request for a bulk export (ZIP archive, available as asynchronous job only).
+type ExportRequest struct {
+	Type       string
+	ID         string
+	Attributes struct {
+		Products       []string      // rawtif, hillshade, contours, inspire
+		Zone           int           // 0 = area in lon/lat coordinates, 32/33 = area in UTM coordinates
+		Area           *ContoursArea // area (bounding box or polygon, tiles intersecting the bounding box are exported)
+		Tiles          []string      // alternative to area: list of tile indices (e.g. 32_497_5670)
+		Equidistance   float64       // contours: equidistance in meters (0 = 10.0)
+		ShadingVariant string        // hillshade: regular, combined, multidirectional, igor, textured (empty = regular)
+		Upload         bool          // upload archive to configured S3 bucket (response contains download URL instead of data)
+	}
+}
+
+// ExportResponse represents the ZIP archive of a bulk export.
+type ExportResponse struct {
+	Type       string
+	ID         string
+	Attributes struct {
+		Products      []string
+		Zone          int
+		Area          *ContoursArea
+		Tiles         []string
+		ExportedTiles []string // tiles contained in archive
+		Filename      string   // suggested file name (e.g. dtm-export-20250614T101500Z.zip)
+		DataFormat    string   // zip
+		Data          []byte   // ZIP archive (empty if uploaded)
+		DownloadURL   string   // presigned download URL of uploaded archive (empty if not uploaded)
+		TileErrors    []TileError
+		Warnings      []string
+		IsError       bool
+		Error         ErrorObject
+	}
+	Meta ResponseMeta
+}
+
+// ContoursExportRequest represents the request for a large-area contour export (GeoPackage, available as asynchronous job only).
+type ContoursExportRequest struct {
+	Type       string
+	ID         string
+	Attributes struct {
+		Zone               int           // 0 = area and contours in lon/lat coordinates, 32/33 = UTM coordinates
+		Area               *ContoursArea // area (bounding box or polygon)
+		AdministrativeArea string        // alternative to area: name or key of administrative area (e.g. Landkreis)
+		Equidistance       float64       // equidistance in unit (0 = 10.0)
+		VerticalOffset     float64       // optional: added to elevations before contouring
+		AttributeName      string        // optional: name of elevation attribute (default: Hoehe)
+		Precision          *int          // optional: decimal places of elevation attribute (0-3, default: not rounded)
+		Unit               string        // optional: unit of equidistance and elevation attribute (m = default, ft)
+		Upload             bool          // upload GeoPackage to configured S3 bucket (response contains download URL instead of data)
+	}
+}
+
+// ContoursExportResponse represents the GeoPackage of a large-area contour export.
+type ContoursExportResponse struct {
+	Type       string
+	ID         string
+	Attributes struct {
+		Zone               int
+		Area               *ContoursArea
+		AdministrativeArea string
+		Equidistance       float64
+		VerticalOffset     float64
+		AttributeName      string
+		Precision          *int
+		Unit               string
+		AreaTiles          int    // number of tiles (km²) combined to the mosaic
+		Filename           string // suggested file name (e.g. 32_497_5670-32_530_5702_contours_10m_2024.gpkg)
+		DataFormat         string // gpkg
+		Data               []byte // GeoPackage (empty if uploaded)
+		DownloadURL        string // presigned download URL of uploaded GeoPackage (empty if not uploaded)
+		Actuality          string
+		Origin             string
+		Attribution        string
+		IsError            bool
+		Error              ErrorObject
+	}
+	Meta ResponseMeta
+}
+
+// CompareRequest represents a point for the comparison of DGM1 and reference DEM (e.g. Copernicus GLO-30).
+type CompareRequest struct {
+	Type       string
+	ID         string
+	Attributes struct {
+		Longitude float64
+		Latitude  float64
+		Place     string // optional: place name (e.g. "Feldberg"), geocoded into lon/lat coordinates
+	}
+}
+
+// CompareResponse represents the elevations of DGM1 and reference DEM and their difference.
+type CompareResponse struct {
+	Type       string
+	ID         string
+	Attributes struct {
+		Longitude            float64
+		Latitude             float64
+		Place                string
+		PlaceName            string // display name of geocoded place
+		Elevation            float64
+		Actuality            string
+		Origin               string
+		Attribution          string
+		TileIndex            string
+		ReferenceName        string  // name of reference DEM (e.g. Copernicus GLO-30)
+		ReferenceElevation   float64 // elevation of reference DEM
+		ReferenceAttribution string
+		Difference           float64 // Elevation - ReferenceElevation (positive = DGM1 above reference)
+		Warnings             []string
+		IsError              bool
+		Error                ErrorObject
+	}
+	Meta ResponseMeta
+}
+
+// --------------------------------------------------------------------------------
+// Request  : Client -> AspectRoseRequest  -> Service
+// Response : Client <- AspectRoseResponse <- Service
+// --------------------------------------------------------------------------------
+
+// AspectRoseRequest represents a polygon for aspect rose and slope distribution (e.g. site assessment in viticulture).
+// Coordinates are UTM (easting, northing) if Zone is set, otherwise lon/lat (longitude, latitude).
+type AspectRoseRequest struct {
+	Type       string
+	ID         string
+	Attributes struct {
+		Zone         int
+		Polygon      [][2]float64 // polygon ring (x, y)
+		Sectors      int          // optional: number of aspect sectors, 4, 8, 16 (default 8)
+		FlatSlope    float64      // optional: slope (degrees) below which area is counted as flat (default 0 = only without aspect)
+		SlopeClasses []float64    // optional: upper bounds of slope classes in degrees (default 2, 5, 10, 15, 20, 30, 45, 90)
+	}
+}
+
+// AspectRoseResponse represents aspect rose (share of area per aspect sector) and slope distribution of a polygon.
+type AspectRoseResponse struct {
+	Type       string
+	ID         string
+	Attributes struct {
+		Zone         int
+		Polygon      [][2]float64
+		Sectors      int
+		FlatSlope    float64
+		Area         float64 // area with valid data in m²
+		NoDataArea   float64 // area without data in m² (e.g. outside coverage, nodata values)
+		MeanSlope    float64 // degrees
+		FlatArea     float64 // m²
+		FlatShare    float64 // percent of area
+		AspectRose   []AspectSector
+		SlopeClasses []SlopeClass
+		AreaTiles    []string // tiles used for polygon
+		Actuality    string
+		Origin       string
+		Attribution  string
+		Warnings     []string // non-fatal conditions (e.g. tiles not processed, defaults applied)
+		IsError      bool
+		Error        ErrorObject
+	}
+	Meta ResponseMeta
+}
+
+// AspectSector represents the share of area facing one direction (aspect sector, centered on direction).
+type AspectSector struct {
+	Direction string  // e.g. N, NNE, NE
+	From      float64 // degrees (clockwise from north)
+	To        float64 // degrees (clockwise from north)
+	Area      float64 // m²
+	Share     float64 // percent of area
+	MeanSlope float64 // degrees
+}
+
+// SlopeClass represents the share of area within a slope class.
+type SlopeClass struct {
+	From  float64 // degrees (inclusive)
+	To    float64 // degrees (exclusive)
+	Area  float64 // m²
+	Share float64 // percent of area
+}
+
+// --------------------------------------------------------------------------------
+// Request  : Client -> ElevationRangeRequest  -> Service
+// Response : Client <- ElevationRangeResponse <- Service
+// --------------------------------------------------------------------------------
+
+// ElevationRangeRequest represents a center point, radius and elevation band (e.g. 600 - 800 m).
+// Coordinates are UTM (easting, northing) if Zone is set, otherwise lon/lat (longitude, latitude) or place name.
+type ElevationRangeRequest struct {
+	Type       string
+	ID         string
+	Attributes struct {
+		Zone         int
+		Easting      float64
+		Northing     float64
+		Longitude    float64
+		Latitude     float64
+		Place        string  // optional: place name (geocoding), overrides coordinates
+		Radius       float64 // meters (100 - 4000)
+		MinElevation float64 // lower bound of elevation band in meters
+		MaxElevation float64 // upper bound of elevation band in meters
+	}
+}
+
+// ElevationRangeResponse represents polygon(s) of terrain within the elevation band around the center point.
+type ElevationRangeResponse struct {
+	Type       string
+	ID         string
+	Attributes struct {
+		Zone           int
+		Easting        float64
+		Northing       float64
+		Longitude      float64
+		Latitude       float64
+		Place          string
+		PlaceName      string // resolved place name (geocoding)
+		Radius         float64
+		MinElevation   float64
+		MaxElevation   float64
+		ElevationRange ElevationRange
+		AreaTiles      []string // tiles used for circle
+		IsError        bool
+		Error          ErrorObject
+	}
+	Meta ResponseMeta
+}
+
+// ElevationRange represents the polygons (GeoJSON) of terrain within an elevation band.
+type ElevationRange struct {
+	Data        []byte
+	DataFormat  string
+	Filename    string // suggested file name (e.g. 32_497_5670-32_498_5671_elevationrange_600-800m_2024.geojson)
+	Actuality   string
+	Origin      string
+	Attribution string
+	TileIndex   string
+}
+
+// --------------------------------------------------------------------------------
+// Request  : Client -> ContourLineRequest  -> Service
+// Response : Client <- ContourLineResponse <- Service
+// --------------------------------------------------------------------------------
+
+// ContourLineRequest represents a point and radius for the contour line passing through the point.
+// Coordinates are UTM (easting, northing) if Zone is set, otherwise lon/lat (longitude, latitude) or place name.
+type ContourLineRequest struct {
+	Type       string
+	ID         string
+	Attributes struct {
+		Zone      int
+		Easting   float64
+		Northing  float64
+		Longitude float64
+		Latitude  float64
+		Place     string  // optional: place name (geocoding), overrides coordinates
+		Radius    float64 // meters (100 - 4000)
+	}
+}
+
+// ContourLineResponse represents the contour line (at the elevation of the point) passing through the point.
+type ContourLineResponse struct {
+	Type       string
+	ID         string
+	Attributes struct {
+		Zone        int
+		Easting     float64
+		Northing    float64
+		Longitude   float64
+		Latitude    float64
+		Place       string
+		PlaceName   string // resolved place name (geocoding)
+		Radius      float64
+		Elevation   float64 // elevation of point
+		ContourLine ContourLine
+		AreaTiles   []string // tiles used for circle
+		IsError     bool
+		Error       ErrorObject
+	}
+	Meta ResponseMeta
+}
+
+// ContourLine represents the contour line (GeoJSON, single line string) nearest to a point.
+type ContourLine struct {
+	Elevation   float64 // level of contour line in meters
+	Distance    float64 // distance between point and contour line in meters
+	Length      float64 // length of contour line (within radius) in meters
+	IsClosed    bool    // contour line is a closed ring (e.g. around summit)
+	Data        []byte
+	DataFormat  string
+	Filename    string // suggested file name (e.g. 32_497_5670-32_498_5671_contourline_812.37m_2024.geojson)
+	Actuality   string
+	Origin      string
+	Attribution string
+	TileIndex   string
+}
+
+// --------------------------------------------------------------------------------
+// Request  : Client -> ElevationMatrixRequest  -> Service
+// Response : Client <- ElevationMatrixResponse <- Service
+// --------------------------------------------------------------------------------
+
+// ElevationMatrixRequest represents a bounding box and resolution for a grid (matrix) of elevations.
+// Coordinates are UTM (easting, northing) if Zone is set, otherwise lon/lat (longitude, latitude).
+type ElevationMatrixRequest struct {
+	Type       string
+	ID         string
+	Attributes struct {
+		Zone       int
+		MinX       float64 // bounding box: min easting or min longitude
+		MinY       float64 // bounding box: min northing or min latitude
+		MaxX       float64 // bounding box: max easting or max longitude
+		MaxY       float64 // bounding box: max northing or max latitude
+		Resolution float64 // optional: cell size in meters (1 - 1000, default 10)
+		Resampling string  // optional: average (default), nearest, bilinear, cubic
+		Format     string  // optional: json (default, array of values), binary (float32 little-endian, base64 encoded)
+	}
+}
+
+// ElevationMatrixResponse represents a grid (matrix) of elevations for a bounding box.
+type ElevationMatrixResponse struct {
+	Type       string
+	ID         string
+	Attributes struct {
+		Zone            int
+		MinX            float64
+		MinY            float64
+		MaxX            float64
+		MaxY            float64
+		Resolution      float64
+		Resampling      string
+		Format          string
+		ElevationMatrix ElevationMatrix
+		AreaTiles       []string // tiles used for bounding box
+		Warnings        []string // non-fatal conditions (e.g. tiles not processed, defaults applied)
+		IsError         bool
+		Error           ErrorObject
+	}
+	Meta ResponseMeta
+}
+
+// ElevationMatrix represents a grid of elevations (row-major order, first row is northern row, first column is western column).
+type ElevationMatrix struct {
+	Zone         int        // UTM zone of grid
+	GridBounds   [4]float64 // minEasting, minNorthing, maxEasting, maxNorthing (outer edges of cells)
+	Columns      int
+	Rows         int
+	Resolution   float64   // cell size in meters
+	NoDataValue  float64   // value of cells without elevation data
+	MinElevation float64   // lowest elevation (cells with data)
+	MaxElevation float64   // highest elevation (cells with data)
+	Values       []float32 // format json: elevations (Columns x Rows)
+	Data         []byte    // format binary: elevations as float32 little-endian (Columns x Rows x 4 bytes)
+	DataFormat   string
+	Actuality    string
+	Origin       string
+	Attribution  string
+	TileIndex    string
+	SourceFiles  []SourceFile // source GeoTIFF files (delivery file name and checksum)
+}
+
+// --------------------------------------------------------------------------------
+// Request  : Client -> LegendRequest  -> Service
+// Response : Client <- LegendResponse <- Service
+// --------------------------------------------------------------------------------
+
+// LegendRequest represents the color mapping (color text file or named color ramp) for a legend (color bar with labels).
+type LegendRequest struct {
+	Type       string
+	ID         string
+	Attributes struct {
+		ColorTextFileContent []string // color text file (e.g. of slope or color relief request)
+		ColorTableName       string   // named color table (see /v1/colortables/{name}) instead of ColorTextFileContent
+		ColorRamp            string   // named color ramp: hypsometric, terrain, grayscale (instead of ColorTextFileContent)
+		Minimum              float64  // value range of color ramp and percentage entries of color text file (e.g. 0 - 1000 m)
+		Maximum              float64
+		ColoringAlgorithm    string // interpolation (default), rounding
+		Unit                 string // optional: unit of labels (e.g. m, °, %)
+		Orientation          string // optional: vertical (default), horizontal
+		Width                int    // optional: width in pixels (default 100 for vertical, 400 for horizontal)
+		Height               int    // optional: height in pixels (default 300 for vertical, 70 for horizontal)
+		OutputFormat         string // optional: png (default), svg
+	}
+}
+
+// LegendResponse represents a legend (color bar with labels) for a color mapping.
+type LegendResponse struct {
+	Type       string
+	ID         string
+	Attributes struct {
+		ColorTextFileContent []string
+		ColorTableName       string
+		ColorRamp            string
+		Minimum              float64
+		Maximum              float64
+		ColoringAlgorithm    string
+		Unit                 string
+		Orientation          string
+		OutputFormat         string
+		Legend               Legend
+		Warnings             []string // non-fatal conditions (e.g. defaults applied)
+		IsError              bool
+		Error                ErrorObject
+	}
+	Meta ResponseMeta
+}
+
+// Legend represents a rendered legend (PNG or SVG).
+type Legend struct {
+	Data       []byte
+	DataFormat string
+	Width      int
+	Height     int
+	Entries    []LegendEntry // color entries (sorted by value, percentages resolved)
+}
+
+// LegendEntry represents a color entry of a legend.
+type LegendEntry struct {
+	Value float64
+	Red   uint8
+	Green uint8
+	Blue  uint8
+	Alpha uint8
+}
+
+// --------------------------------------------------------------------------------
+// Request  : Client -> ColorTableRequest  -> Service (POST /v1/colortables/{name})
+// Response : Client <- ColorTableResponse <- Service (POST, GET, DELETE /v1/colortables/{name})
+// --------------------------------------------------------------------------------
+
+// ColorTableRequest represents a named color table (stored server-side, referenced by ColorTableName in raster requests).
+type ColorTableRequest struct {
+	Type       string
+	ID         string
+	Attributes struct {
+		Description          string   // optional: description of color table
+		ColorTextFileContent []string // color text file (gdaldem color-relief format)
+	}
+}
+
+// ColorTableResponse represents a stored named color table.
+type ColorTableResponse struct {
+	Type       string
+	ID         string
+	Attributes struct {
+		Name                 string
+		Description          string
+		ColorTextFileContent []string
+		Created              string // RFC 3339
+		Modified             string // RFC 3339
+		IsError              bool
+		Error                ErrorObject
+	}
+	Meta ResponseMeta
 }
 
 /*
@@ -899,8 +1969,19 @@ func getGeotiffTile(easting float64, northing float64, zone int, tileVariant int
 	}
 
 	// get tile resource (GeoTIFF file)
-	tile, found := Repository[hash]
+	repositoryMutex.RLock()
+	tile, found := Repository.Tile(hash)
+	disabledTile, disabled := Repository.DisabledTile(hash)
+	repositoryMutex.RUnlock()
 	if !found {
+		// tile exists, but the elevation source is disabled by configuration (e.g. during re-delivery of data)
+		if disabled {
+			return TileMetadata{}, fmt.Errorf("tile [%s] from source [%s]: %w", hash, disabledTile.Source, ErrSourceUnavailable)
+		}
+		if tileVariant == 1 {
+			// missing primary tile: outside data coverage
+			return TileMetadata{}, &OutsideCoverageError{Index: hash, Zone: zone, Easting: easting, Northing: northing}
+		}
 		return TileMetadata{}, fmt.Errorf("tile [%s] not found", hash)
 	}
 
@@ -1071,6 +2152,7 @@ func getTileUTM(longitude, latitude float64) (TileMetadata, int, float64, float6
 		// tile in primary zone found
 		return tile, zone, x, y, nil
 	}
+	primaryErr := err
 
 	// lookup in neighbor zone
 	x, y, err = transformLonLatToUTM(longitude, latitude, neighborTargetEPSG)
@@ -1080,6 +2162,13 @@ func getTileUTM(longitude, latitude float64) (TileMetadata, int, float64, float6
 	}
 	tile, err = getGeotiffTile(x, y, neighborZone, 1)
 	if err != nil {
+		if errors.Is(primaryErr, ErrSourceUnavailable) {
+			// report disabled source instead of missing tile in neighbor zone
+			err = primaryErr
+		} else if errors.Is(primaryErr, ErrOutsideCoverage) {
+			// report missing tile in primary zone instead of missing tile in neighbor zone
+			err = primaryErr
+		}
 		err = fmt.Errorf("error [%w] getting GeoRawTIFF tile for UTM easting: %.3f, northing: %.3f, zone: %d", err, x, y, zone)
 		return tile, 0, 0.0, 0.0, err
 	}
@@ -1100,7 +2189,7 @@ func getElevationForUTMPoint(zone int, easting, northing float64) (float64, Tile
 	// lookup for tile (primary tile / variant 1, e.g. 32_437_5614)
 	tile, err = getGeotiffTile(easting, northing, zone, 1)
 	if err != nil {
-		return -8888.0, tile, fmt.Errorf("tile not found")
+		return -8888.0, tile, fmt.Errorf("tile not found: %w", err)
 	}
 
 	// retrieve elevation
@@ -1148,46 +2237,6 @@ func getElevationForUTMPoint(zone int, easting, northing float64) (float64, Tile
 	return elevation, tile, nil
 }
 
-/*
-runCommand runs a command or program.
-*/
-func runCommand(program string, args []string) (commandExitStatus int, commandOutput []byte, err error) {
-	cmd := exec.Command(program, args...)
-	commandOutput, err = cmd.CombinedOutput()
-
-	// full command for logging
-	fullCommand := program + " " + strings.Join(cmd.Args, " ")
-	//	fmt.Printf("Full command: %v\n", fullCommand)
-
-	var waitStatus syscall.WaitStatus
-	if err != nil {
-		// command was not successful
-		if exitError, ok := err.(*exec.ExitError); ok {
-			// command fails because of an unsuccessful exit code
-			waitStatus = exitError.Sys().(syscall.WaitStatus)
-			slog.Error("program exit code", "exit code", waitStatus.ExitStatus())
-		}
-		slog.Error("unexpected error at cmd.CombinedOutput()", "error", err)
-		slog.Error("program (not successful)", "program/command", fullCommand)
-		if len(commandOutput) > 0 {
-			slog.Info("program output (stdout, stderr)", "output", string(commandOutput))
-		}
-	} else {
-		// command was successful
-		waitStatus = cmd.ProcessState.Sys().(syscall.WaitStatus)
-		/*
-			slog.Info("program (successful)", "program/command", fullCommand)
-			slog.Info("program exit code", "exit code", waitStatus.ExitStatus())
-			if len(commandOutput) > 0 {
-				slog.Info("program output (stdout, stderr)", "output", string(commandOutput))
-			}
-		*/
-	}
-
-	commandExitStatus = waitStatus.ExitStatus()
-	return
-}
-
 /*
 verifyColorTextFileContent checks the content of a text file, passed as a slice of strings.
 - The total content size must not exceed 12 KB.
@@ -1256,6 +2305,87 @@ func createColorTextFile(filename string, filecontent []string) error {
 	return nil
 }
 
+/*
+verifyVerticalOffset verifies the vertical offset (custom datum, e.g. local construction reference level).
+*/
+func verifyVerticalOffset(verticalOffset float64) error {
+	if math.IsNaN(verticalOffset) || verticalOffset < -10000.0 || verticalOffset > 10000.0 {
+		return errors.New("vertical offset must be between -10000.0 and 10000.0 meters")
+	}
+	return nil
+}
+
+/*
+verifyNoDataHandling verifies the rendering of nodata areas (color relief based outputs) and the fill color.
+*/
+func verifyNoDataHandling(noDataHandling string, noDataColor string) error {
+	switch strings.ToLower(noDataHandling) {
+	case "", "preserve", "transparent":
+		if noDataColor != "" {
+			return errors.New("NoDataColor requires NoDataHandling 'fill'")
+		}
+	case "fill":
+		_, err := parseNoDataColor(noDataColor)
+		if err != nil {
+			return err
+		}
+	default:
+		return errors.New("unsupported nodata handling (not transparent, fill, preserve)")
+	}
+	return nil
+}
+
+/*
+parseNoDataColor parses the fill color for nodata areas (R G B, each 0 to 255).
+*/
+func parseNoDataColor(noDataColor string) ([3]int, error) {
+	var color [3]int
+	fields := strings.Fields(noDataColor)
+	if len(fields) != 3 {
+		return color, errors.New("invalid NoDataColor (expected 'R G B', e.g. '255 255 255')")
+	}
+	for i, field := range fields {
+		value, err := strconv.Atoi(field)
+		if err != nil || value < 0 || value > 255 {
+			return color, errors.New("invalid NoDataColor (color components must be 0 to 255)")
+		}
+		color[i] = value
+	}
+	return color, nil
+}
+
+/*
+applyNoDataHandling returns the color text file content (gdaldem color-relief) with the color of nodata areas
+('nv' entry) set according to the nodata handling: transparent (alpha 0), fill (opaque color) or preserve
+(content unchanged). Without 'nv' entry gdaldem colorizes nodata (-9999) like regular values (colored fringes).
+*/
+func applyNoDataHandling(colorTextFileContent []string, noDataHandling string, noDataColor string) []string {
+	var nvEntry string
+	switch strings.ToLower(noDataHandling) {
+	case "transparent":
+		nvEntry = "nv 0 0 0 0"
+	case "fill":
+		color, err := parseNoDataColor(noDataColor)
+		if err != nil {
+			// verified before
+			return colorTextFileContent
+		}
+		nvEntry = fmt.Sprintf("nv %d %d %d 255", color[0], color[1], color[2])
+	default:
+		return colorTextFileContent
+	}
+
+	content := make([]string, 0, len(colorTextFileContent)+1)
+	for _, line := range colorTextFileContent {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && strings.EqualFold(fields[0], "nv") {
+			continue
+		}
+		content = append(content, line)
+	}
+	return append(content, nvEntry)
+}
+
 /*
 getAllTilesUTM get metadata for all tiles specified by UTM coordinate.
 It collects associated tiles within the same UTM zone.
@@ -1358,3 +2488,160 @@ func getAllTilesLonLat(longitude float64, latitude float64) ([]TileMetadata, err
 
 	return tiles, nil
 }
+
+/*
+generateObjectsForTiles generates the objects (e.g. hillshade, slope) for all tiles concurrently.
+The order of the objects corresponds to the order of the tiles. If some tiles fail, the objects of the
+successful tiles are returned together with an error entry for each failed tile (partial success).
+An error is returned if all tiles fail or if the request can't be processed at all (e.g. server busy).
+A panic in a worker goroutine is re-raised in the calling goroutine (handled by recoveryMiddleware).
+The progress (processed tiles) is reported to the job, if the request is processed as asynchronous job.
+*/
+func generateObjectsForTiles[T any](ctx context.Context, tiles []TileMetadata, generate func(tile TileMetadata) (T, error)) ([]T, []TileError, error) {
+	objects := make([]T, len(tiles))
+	addJobTiles(ctx, len(tiles))
+
+	errs := make([]error, len(tiles))
+	panics := make([]any, len(tiles))
+
+	var waitGroup sync.WaitGroup
+	for i, tile := range tiles {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					panics[i] = fmt.Sprintf("%v (tile %s)\n%s", recovered, tile.Index, debug.Stack())
+				}
+			}()
+			objects[i], errs[i] = generate(tile)
+			addJobTilesDone(ctx, 1)
+		}()
+	}
+	waitGroup.Wait()
+
+	for _, recovered := range panics {
+		if recovered != nil {
+			panic(recovered)
+		}
+	}
+
+	// client disconnected or server busy: request can't be processed at all
+	for _, err := range errs {
+		if errors.Is(err, context.Canceled) || errors.Is(err, ErrServerBusy) {
+			return nil, nil, err
+		}
+	}
+
+	var successfulObjects []T
+	var tileErrors []TileError
+	var firstErr error
+	for i, err := range errs {
+		if err != nil {
+			tileErrors = append(tileErrors, TileError{TileIndex: tiles[i].Index, Origin: tiles[i].Source, Detail: err.Error()})
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		successfulObjects = append(successfulObjects, objects[i])
+	}
+	if len(successfulObjects) == 0 && firstErr != nil {
+		return nil, nil, firstErr
+	}
+	if len(tileErrors) > 0 {
+		atomic.AddUint64(&PartialResponses, 1)
+	}
+
+	return successfulObjects, tileErrors, nil
+}
+
+/*
+UnmarshalJSON accepts a single shading variant (string) or a list of shading variants (array of strings).
+*/
+func (s *ShadingVariants) UnmarshalJSON(data []byte) error {
+	var variant string
+	if err := json.Unmarshal(data, &variant); err == nil {
+		*s = ShadingVariants{variant}
+		return nil
+	}
+	var variants []string
+	if err := json.Unmarshal(data, &variants); err != nil {
+		return fmt.Errorf("error [%w] at json.Unmarshal(), expected string or array of strings", err)
+	}
+	*s = variants
+	return nil
+}
+
+/*
+MarshalJSON writes a single shading variant as string (compatible with previous versions), several as array.
+*/
+func (s ShadingVariants) MarshalJSON() ([]byte, error) {
+	if len(s) == 1 {
+		return json.Marshal(s[0])
+	}
+	return json.Marshal([]string(s))
+}
+
+/*
+UnmarshalJSON reads the vertical exaggeration as number or as "auto".
+*/
+func (v *VerticalExaggeration) UnmarshalJSON(data []byte) error {
+	var value float64
+	if err := json.Unmarshal(data, &value); err == nil {
+		*v = VerticalExaggeration(value)
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("error [%w] at json.Unmarshal(), expected number or \"auto\"", err)
+	}
+	if !strings.EqualFold(text, "auto") {
+		return fmt.Errorf("unsupported vertical exaggeration [%s], expected number or \"auto\"", text)
+	}
+	*v = VerticalExaggerationAuto
+	return nil
+}
+
+/*
+MarshalJSON writes the vertical exaggeration as number or as "auto".
+*/
+func (v VerticalExaggeration) MarshalJSON() ([]byte, error) {
+	if v == VerticalExaggerationAuto {
+		return json.Marshal("auto")
+	}
+	return json.Marshal(float64(v))
+}
+
+/*
+buildObjectFilename builds a stable, meaningful file name for a generated tile object,
+e.g. 32_497_5670_hillshade_igor_2024.png (tile index, product, variant, year of actuality).
+*/
+func buildObjectFilename(tileIndex string, product string, variant string, actuality string, dataFormat string) string {
+	parts := []string{tileIndex, product}
+
+	// variant (e.g. shading variant, gradient algorithm), restricted to safe characters
+	variant = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		}
+		return '-'
+	}, strings.ToLower(variant))
+	if variant != "" {
+		parts = append(parts, variant)
+	}
+
+	// year of actuality (e.g. 2017-04-19)
+	if len(actuality) >= 4 {
+		if _, err := strconv.Atoi(actuality[:4]); err == nil {
+			parts = append(parts, actuality[:4])
+		}
+	}
+
+	extension := strings.ToLower(dataFormat)
+	if extension == "geotiff" {
+		extension = "tif"
+	}
+	return strings.Join(parts, "_") + "." + extension
+}
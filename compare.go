@@ -0,0 +1,278 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/airbusgeo/godal"
+
+	"klaus/elevation/dtm-elevation-service/pkg/dtm"
+)
+
+// referenceEPSGs caches the EPSG code of the coordinate reference system of reference DEMs (key: path)
+var (
+	referenceEPSGs      = make(map[string]int)
+	referenceEPSGsMutex sync.Mutex
+)
+
+/*
+compareRequest handles 'compare' request from client: elevation of DGM1 and of the configured reference
+DEM (e.g. Copernicus GLO-30) for a point, together with the difference (e.g. for validating third-party datasets).
+*/
+func compareRequest(writer http.ResponseWriter, request *http.Request) {
+	var compareResponse = CompareResponse{Type: TypeCompareResponse, ID: "unknown"}
+	compareResponse.Attributes.Elevation = -8888.0
+	compareResponse.Attributes.ReferenceElevation = -8888.0
+	compareResponse.Attributes.IsError = true
+
+	// statistics
+	atomic.AddUint64(&CompareRequests, 1)
+
+	// limit overall request body size
+	request.Body = http.MaxBytesReader(writer, request.Body, MaxCompareRequestBodySize)
+
+	// read request
+	bodyData, err := io.ReadAll(request.Body)
+	if err != nil {
+		// check specifically for the error returned by MaxBytesReader
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			slog.WarnContext(request.Context(), "compare request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
+			compareResponse.Attributes.Error = newErrorObject(EndpointCompare, ReasonRequestBodyTooLarge, fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit))
+			buildCompareResponse(writer, request, http.StatusRequestEntityTooLarge, compareResponse)
+		} else {
+			// handle other read errors
+			slog.WarnContext(request.Context(), "compare request: error reading request body", "error", err, "ID", "unknown")
+			compareResponse.Attributes.Error = newErrorObject(EndpointCompare, ReasonReadingRequestBody, err.Error())
+			buildCompareResponse(writer, request, http.StatusBadRequest, compareResponse)
+		}
+		return
+	}
+
+	// unmarshal request
+	compareRequest := CompareRequest{}
+	err = json.Unmarshal(bodyData, &compareRequest)
+	if err != nil {
+		slog.WarnContext(request.Context(), "compare request: error unmarshaling request body", "error", err, "ID", "unknown")
+		compareResponse.Attributes.Error = newErrorObject(EndpointCompare, ReasonUnmarshalingRequestBody, err.Error())
+		buildCompareResponse(writer, request, http.StatusBadRequest, compareResponse)
+		return
+	}
+
+	// resolve place name (geocoding) into lon/lat coordinates
+	if compareRequest.Attributes.Place != "" {
+		place, err := geocodePlace(request.Context(), compareRequest.Attributes.Place)
+		if err != nil {
+			slog.WarnContext(request.Context(), "compare request: error geocoding place", "error", err, "place", compareRequest.Attributes.Place, "ID", compareRequest.ID)
+			compareResponse.ID = compareRequest.ID
+			compareResponse.Attributes.Place = compareRequest.Attributes.Place
+			if errors.Is(err, ErrPlaceNotFound) {
+				compareResponse.Attributes.Error = newErrorObject(EndpointCompare, ReasonPlaceNotFound, err.Error())
+				buildCompareResponse(writer, request, http.StatusNotFound, compareResponse)
+				return
+			}
+			if errors.Is(err, ErrGeocoderUnavailable) {
+				compareResponse.Attributes.Error = newErrorObject(EndpointCompare, ReasonGeocoderUnavailable, err.Error())
+				buildCompareResponse(writer, request, http.StatusServiceUnavailable, compareResponse)
+				return
+			}
+			compareResponse.Attributes.Error = newErrorObject(EndpointCompare, ReasonVerifyingRequestData, err.Error())
+			buildCompareResponse(writer, request, http.StatusBadRequest, compareResponse)
+			return
+		}
+		compareRequest.Attributes.Longitude = place.Longitude
+		compareRequest.Attributes.Latitude = place.Latitude
+		compareResponse.Attributes.PlaceName = place.DisplayName
+	}
+
+	// copy request parameters into response
+	compareResponse.ID = compareRequest.ID
+	compareResponse.Attributes.Longitude = compareRequest.Attributes.Longitude
+	compareResponse.Attributes.Latitude = compareRequest.Attributes.Latitude
+	compareResponse.Attributes.Place = compareRequest.Attributes.Place
+
+	// verify request data
+	err = verifyCompareRequestData(request, compareRequest)
+	if err != nil {
+		slog.WarnContext(request.Context(), "compare request: error verifying request data", "error", err, "ID", compareRequest.ID)
+		compareResponse.Attributes.Error = newErrorObject(EndpointCompare, ReasonVerifyingRequestData, err.Error())
+		buildCompareResponse(writer, request, http.StatusBadRequest, compareResponse)
+		return
+	}
+
+	// reference DEM
+	reference := getProgConfig().ReferenceDEM
+	if reference.Path == "" {
+		slog.WarnContext(request.Context(), "compare request: no reference DEM configured", "ID", compareRequest.ID)
+		compareResponse.Attributes.Error = newErrorObject(EndpointCompare, ReasonReferenceNotConfigured, "configuration 'ReferenceDEM.Path' is empty")
+		buildCompareResponse(writer, request, http.StatusNotImplemented, compareResponse)
+		return
+	}
+
+	// get elevation (DGM1)
+	elevation, tile, err := getElevationForPoint(compareRequest.Attributes.Longitude, compareRequest.Attributes.Latitude)
+	if err != nil {
+		slog.DebugContext(request.Context(), "compare request: error getting elevation for point", "error", err, "ID", compareRequest.ID)
+		if errors.Is(err, ErrOutsideCoverage) {
+			compareResponse.Attributes.Error = newErrorObject(EndpointCompare, ReasonOutsideCoverage, describeOutsideCoverage(err))
+			buildCompareResponse(writer, request, http.StatusNotFound, compareResponse)
+			return
+		}
+		if errors.Is(err, ErrSourceUnavailable) {
+			compareResponse.Attributes.Error = newErrorObject(EndpointCompare, ReasonSourceUnavailable, err.Error())
+			buildCompareResponse(writer, request, http.StatusServiceUnavailable, compareResponse)
+			return
+		}
+		compareResponse.Attributes.Error = newErrorObject(EndpointCompare, ReasonGettingElevation, err.Error())
+		buildCompareResponse(writer, request, http.StatusBadRequest, compareResponse)
+		return
+	}
+
+	// get elevation (reference DEM, point transformed into its coordinate reference system)
+	referenceElevation, err := getReferenceElevation(compareRequest.Attributes.Longitude, compareRequest.Attributes.Latitude, reference.Path)
+	if err != nil {
+		slog.DebugContext(request.Context(), "compare request: error getting reference elevation", "error", err, "ID", compareRequest.ID)
+		compareResponse.Attributes.Error = newErrorObject(EndpointCompare, ReasonGettingReferenceElevation, err.Error())
+		buildCompareResponse(writer, request, http.StatusNotFound, compareResponse)
+		return
+	}
+
+	// get attribution for resource
+	attribution := "unknown"
+	origin := "unknown"
+	resource, err := getElevationResource(tile.Source)
+	if err != nil {
+		slog.ErrorContext(request.Context(), "compare request: error getting elevation resource", "error", err, "source", tile.Source, "ID", compareRequest.ID)
+	} else {
+		attribution = resource.Attribution
+		origin = resource.Code
+	}
+
+	// success response
+	compareResponse.Attributes.Elevation = elevation
+	compareResponse.Attributes.Actuality = tile.Actuality
+	compareResponse.Attributes.Origin = origin
+	compareResponse.Attributes.Attribution = attribution
+	compareResponse.Attributes.TileIndex = tile.Index
+	compareResponse.Attributes.ReferenceName = reference.Name
+	compareResponse.Attributes.ReferenceElevation = referenceElevation
+	compareResponse.Attributes.ReferenceAttribution = reference.Attribution
+	compareResponse.Attributes.Difference = elevation - referenceElevation
+	compareResponse.Attributes.IsError = false
+	buildCompareResponse(writer, request, http.StatusOK, compareResponse)
+}
+
+/*
+verifyCompareRequestData verifies 'compare' request data.
+*/
+func verifyCompareRequestData(request *http.Request, compareRequest CompareRequest) error {
+	// verify HTTP header
+	contentType := request.Header.Get("Content-Type")
+	if !strings.HasPrefix(strings.ToLower(contentType), "application/json") {
+		return fmt.Errorf("unexpected or missing HTTP header field Content-Type, value = [%s], expected 'application/json'", contentType)
+	}
+
+	// verify Type
+	if compareRequest.Type != TypeCompareRequest {
+		return fmt.Errorf("unexpected request Type [%v]", compareRequest.Type)
+	}
+
+	// verify ID
+	if len(compareRequest.ID) > 1024 {
+		return errors.New("ID must be 0-1024 characters long")
+	}
+
+	// verify Attributes.Latitude for Germany (Latitude: from 47.2701° N to 55.0586° N)
+	if compareRequest.Attributes.Latitude > 55.3 || compareRequest.Attributes.Latitude < 47.0 {
+		return errors.New("invalid latitude for Germany")
+	}
+
+	// verify Attributes.Longitude for Germany (Longitude: from  5.8663° E to 15.0419° E)
+	if compareRequest.Attributes.Longitude > 15.3 || compareRequest.Attributes.Longitude < 5.5 {
+		return errors.New("invalid longitude for Germany")
+	}
+
+	return nil
+}
+
+/*
+buildCompareResponse builds HTTP responses with specified status and body.
+*/
+func buildCompareResponse(writer http.ResponseWriter, request *http.Request, httpStatus int, compareResponse CompareResponse) {
+	// response metadata (versions, processing duration, cache hit)
+	compareResponse.Meta = newResponseMeta(request.Context())
+
+	// encode (streaming) and send response
+	streamJSONResponse(writer, request, httpStatus, compareResponse, false)
+}
+
+/*
+getReferenceEPSG returns the EPSG code of the coordinate reference system of the reference DEM (cached).
+*/
+func getReferenceEPSG(path string) (int, error) {
+	referenceEPSGsMutex.Lock()
+	defer referenceEPSGsMutex.Unlock()
+
+	epsg, found := referenceEPSGs[path]
+	if found {
+		return epsg, nil
+	}
+
+	dataset, err := godal.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("error [%w] at godal.Open(), file: %s", err, path)
+	}
+	defer dataset.Close()
+
+	projection := dataset.Projection()
+	if projection == "" {
+		return 0, fmt.Errorf("reference DEM [%s] without coordinate reference system", path)
+	}
+	spatialRef, err := godal.NewSpatialRefFromWKT(projection)
+	if err != nil {
+		return 0, fmt.Errorf("error [%w] at godal.NewSpatialRefFromWKT(), file: %s", err, path)
+	}
+	defer spatialRef.Close()
+
+	// authority code may be missing (e.g. WKT without AUTHORITY node)
+	_ = spatialRef.AutoIdentifyEPSG()
+	if !strings.EqualFold(spatialRef.AuthorityName(""), "EPSG") {
+		return 0, fmt.Errorf("coordinate reference system of reference DEM [%s] has no EPSG code", path)
+	}
+	epsg, err = strconv.Atoi(spatialRef.AuthorityCode(""))
+	if err != nil {
+		return 0, fmt.Errorf("error [%w] at strconv.Atoi(), EPSG code of reference DEM [%s]", err, path)
+	}
+
+	referenceEPSGs[path] = epsg
+	return epsg, nil
+}
+
+/*
+getReferenceElevation returns the elevation of the reference DEM for a point (lon/lat). The point is
+transformed into the coordinate reference system of the reference DEM (e.g. UTM, if not WGS84).
+*/
+func getReferenceElevation(longitude, latitude float64, path string) (float64, error) {
+	epsg, err := getReferenceEPSG(path)
+	if err != nil {
+		return 0, err
+	}
+
+	x, y := longitude, latitude
+	if epsg != 4326 {
+		x, y, err = transformLonLatToUTM(longitude, latitude, epsg)
+		if err != nil {
+			return 0, fmt.Errorf("error [%w] at transformLonLatToUTM(), target: EPSG:%d", err, epsg)
+		}
+	}
+
+	return dtm.ElevationFromFile(x, y, path)
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// supported content encodings in order of preference
+var supportedContentEncodings = []string{"zstd", "br", "gzip", "deflate"}
+
+/*
+negotiateContentEncoding selects the content encoding for the response based on the 'Accept-Encoding' header
+of the client. Returns 'identity' if the client does not accept any supported compression.
+*/
+func negotiateContentEncoding(acceptEncoding string) string {
+	// parse header, e.g. "gzip, deflate;q=0.5, br"
+	qualities := make(map[string]float64)
+	for _, element := range strings.Split(acceptEncoding, ",") {
+		element = strings.TrimSpace(element)
+		if element == "" {
+			continue
+		}
+		coding, params, _ := strings.Cut(element, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		quality := 1.0
+		params = strings.TrimSpace(params)
+		if strings.HasPrefix(strings.ToLower(params), "q=") {
+			value, err := strconv.ParseFloat(strings.TrimSpace(params[2:]), 64)
+			if err == nil {
+				quality = value
+			}
+		}
+		qualities[coding] = quality
+	}
+
+	bestEncoding := "identity"
+	bestQuality := 0.0
+	for _, encoding := range supportedContentEncodings {
+		quality, found := qualities[encoding]
+		if !found {
+			quality, found = qualities["*"]
+		}
+		if found && quality > bestQuality {
+			bestEncoding = encoding
+			bestQuality = quality
+		}
+	}
+
+	return bestEncoding
+}
+
+/*
+newCompressionWriter returns a writer which compresses all data with the given content encoding.
+*/
+func newCompressionWriter(contentEncoding string, writer io.Writer) (io.WriteCloser, error) {
+	switch contentEncoding {
+	case "zstd":
+		// single goroutine per response (requests are processed concurrently anyway)
+		return zstd.NewWriter(writer, zstd.WithEncoderConcurrency(1))
+	case "br":
+		return brotli.NewWriter(writer), nil
+	case "gzip":
+		return gzip.NewWriter(writer), nil
+	case "deflate":
+		// HTTP 'deflate' is the zlib format (RFC 1950)
+		return zlib.NewWriter(writer), nil
+	case "identity":
+		return nopWriteCloser{writer}, nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding [%s]", contentEncoding)
+	}
+}
+
+// nopWriteCloser adds a no-op Close method to a writer
+type nopWriteCloser struct {
+	io.Writer
+}
+
+/*
+Close does nothing.
+*/
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+/*
+streamJSONResponse encodes the response as JSON directly into the (compressed) HTTP response body.
+This avoids buffering large responses (e.g. base64 encoded raster data) multiple times in memory.
+Compression is skipped if the payload is dominated by already compressed data (e.g. PNG).
+*/
+func streamJSONResponse(writer http.ResponseWriter, request *http.Request, httpStatus int, response any, skipCompression bool) {
+	// select compression (according to 'Accept-Encoding' of client)
+	contentEncoding := "identity"
+	if !skipCompression {
+		contentEncoding = negotiateContentEncoding(request.Header.Get("Accept-Encoding"))
+	}
+	compressionWriter, err := newCompressionWriter(contentEncoding, writer)
+	if err != nil {
+		slog.ErrorContext(request.Context(), "error creating compression writer", "error", err, "encoding", contentEncoding)
+		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	// set headers
+	if contentEncoding != "identity" {
+		writer.Header().Set("Content-Encoding", contentEncoding)
+	}
+	writer.Header().Set("Vary", "Accept-Encoding")
+	writer.Header().Set("Content-Type", JSONAPIMediaType)
+	writer.WriteHeader(httpStatus)
+
+	// encode and send response
+	encoder := json.NewEncoder(compressionWriter)
+	if getProgConfig().IndentJSON {
+		encoder.SetIndent("", "  ")
+	}
+	err = encoder.Encode(response)
+	if err != nil {
+		slog.ErrorContext(request.Context(), "error encoding HTTP response body", "error", err, "encoding", contentEncoding)
+	}
+	err = compressionWriter.Close()
+	if err != nil {
+		slog.ErrorContext(request.Context(), "error closing compression writer", "error", err, "encoding", contentEncoding)
+	}
+}
+
+/*
+isCompressedDataFormat reports whether data of the given format is already compressed (configurable, e.g. 'png'),
+so that a further compression of the response body is not worthwhile.
+*/
+func isCompressedDataFormat(dataFormat string) bool {
+	for _, compressedDataFormat := range getProgConfig().CompressedDataFormats {
+		if strings.EqualFold(dataFormat, compressedDataFormat) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+marshalResponse marshals the response as JSON (indented only if configured).
+*/
+func marshalResponse(response any) ([]byte, error) {
+	if getProgConfig().IndentJSON {
+		return json.MarshalIndent(response, "", "  ")
+	}
+	return json.Marshal(response)
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"gopkg.in/yaml.v3"
+)
+
+// reloadMutex serializes configuration reloads
+var reloadMutex sync.Mutex
+
+// activeProgConfig holds the current program configuration (replaced as a whole on reload)
+var activeProgConfig atomic.Pointer[ProgConfig]
+
+/*
+getProgConfig returns the current program configuration (safe for concurrent use, e.g. while reloading).
+*/
+func getProgConfig() *ProgConfig {
+	config := activeProgConfig.Load()
+	if config == nil {
+		// not yet activated (e.g. benchmark mode)
+		return &progConfig
+	}
+	return config
+}
+
+// envOverridePrefix is the prefix of environment variables overriding configuration settings
+const envOverridePrefix = "DTM_"
+
+/*
+getProgConfigFile returns the name of the configuration file (derived from program name).
+*/
+func getProgConfigFile() string {
+	return progName + ".yaml"
+}
+
+/*
+loadProgConfig reads and parses the configuration file (default values for settings not configured)
+and applies the environment variable overrides. Returns the names of the overridden settings.
+*/
+func loadProgConfig(filename string) (ProgConfig, []string, error) {
+	var config ProgConfig
+
+	source, err := os.ReadFile(filename)
+	if err != nil {
+		return config, nil, fmt.Errorf("error [%w] at os.ReadFile(), file: %s", err, filename)
+	}
+
+	// default, if not configured
+	config.CORS.Default = CORSPolicy{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{"POST"},
+		AllowedHeaders: []string{"Content-Type", "If-None-Match", "X-Request-ID", "Authorization"},
+		MaxAge:         86400,
+	}
+	config.LoadShedding.RetryAfter = 30
+	config.ACME.CacheDirectory = "./acme-cache"
+	config.ACME.HTTPListenAddress = ":80"
+	config.Jobs.MaxJobs = 100
+	config.Jobs.ResultTTL = 3600
+	config.ColorTables.MaxTablesPerTenant = 50
+	config.Export.MaxTiles = 25
+	config.Export.MaxContoursTiles = 2500
+	config.Export.S3.URLExpires = 86400
+	config.Export.INSPIRE.Namespace = "https://registry.gdi-de.org/id/de.dtm-elevation-service"
+	config.GPXAnnotation.Description = "Die Höhenangaben (ele) basieren auf DGM-Daten mit hoher Genauigkeit."
+	config.GPXAnnotation.Creator = "Höhenangaben von hoehendaten.de"
+	config.GPXAnnotation.Copyright = "{attributions}"
+	config.GPXAnnotation.PointDescription = "ele: {source}, {actuality}"
+
+	err = yaml.Unmarshal(source, &config)
+	if err != nil {
+		return config, nil, fmt.Errorf("error [%w] at yaml.Unmarshal(), file: %s", err, filename)
+	}
+
+	overrides, err := applyEnvOverrides(reflect.ValueOf(&config).Elem(), envOverridePrefix)
+	if err != nil {
+		return config, nil, fmt.Errorf("error [%w] at applyEnvOverrides()", err)
+	}
+
+	return config, overrides, nil
+}
+
+/*
+applyEnvOverrides overrides configuration settings by environment variables (12-factor style).
+The variable name is derived from the (nested) YAML keys, e.g.:
+DTM_LISTENADDRESS=:8080, DTM_RESPONSECACHE_MAXSIZE=1024, DTM_DISABLEDSOURCES=DE-NW,DE-NI
+Lists of strings are given comma separated, other lists (e.g. CORS_ENDPOINTS) in YAML flow style.
+Returns the names of the overridden settings (environment variables).
+*/
+func applyEnvOverrides(value reflect.Value, prefix string) ([]string, error) {
+	overrides := []string{}
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		key, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if key == "" || key == "-" {
+			continue
+		}
+		name := prefix + strings.ToUpper(key)
+		fieldValue := value.Field(i)
+
+		// nested settings (e.g. DTM_RESPONSECACHE_TTL)
+		if fieldValue.Kind() == reflect.Struct {
+			nestedOverrides, err := applyEnvOverrides(fieldValue, name+"_")
+			if err != nil {
+				return nil, err
+			}
+			overrides = append(overrides, nestedOverrides...)
+			continue
+		}
+
+		envValue, found := os.LookupEnv(name)
+		if !found {
+			continue
+		}
+		switch {
+		case fieldValue.Kind() == reflect.String:
+			fieldValue.SetString(envValue)
+		case fieldValue.Type() == reflect.TypeOf([]string{}) && !strings.HasPrefix(strings.TrimSpace(envValue), "["):
+			list := []string{}
+			for _, element := range strings.Split(envValue, ",") {
+				if element = strings.TrimSpace(element); element != "" {
+					list = append(list, element)
+				}
+			}
+			fieldValue.Set(reflect.ValueOf(list))
+		default:
+			// numbers, booleans, lists (YAML syntax)
+			target := reflect.New(fieldValue.Type())
+			err := yaml.Unmarshal([]byte(envValue), target.Interface())
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for environment variable [%s]: %w", name, err)
+			}
+			fieldValue.Set(target.Elem())
+		}
+		overrides = append(overrides, name)
+	}
+
+	return overrides, nil
+}
+
+/*
+keepRestartOnlySettings copies all settings, which can only be changed by a restart (e.g. listen addresses,
+certificates, sizes of caches and queues), from the current to the new configuration.
+Returns the names of the settings whose changes are ignored.
+*/
+func keepRestartOnlySettings(newConfig *ProgConfig, currentConfig *ProgConfig) []string {
+	ignored := []string{}
+	keep := func(name string, changed bool) {
+		if changed {
+			ignored = append(ignored, name)
+		}
+	}
+
+	keep("ListenAddress", keepSetting(&newConfig.ListenAddress, currentConfig.ListenAddress))
+	keep("TLSMode", keepSetting(&newConfig.TLSMode, currentConfig.TLSMode))
+	keep("ServerCertificate", keepSetting(&newConfig.ServerCertificate, currentConfig.ServerCertificate))
+	keep("ServerKey", keepSetting(&newConfig.ServerKey, currentConfig.ServerKey))
+	keep("ACME", keepSetting(&newConfig.ACME, currentConfig.ACME))
+	keep("TrustedIssuers", keepSetting(&newConfig.TrustedIssuers, currentConfig.TrustedIssuers))
+	keep("Authentication.Audience", keepSetting(&newConfig.Authentication.Audience, currentConfig.Authentication.Audience))
+	keep("Authentication.JWKSCacheTTL", keepSetting(&newConfig.Authentication.JWKSCacheTTL, currentConfig.Authentication.JWKSCacheTTL))
+	keep("ShutdownGracePeriod", keepSetting(&newConfig.ShutdownGracePeriod, currentConfig.ShutdownGracePeriod))
+	keep("LogDirectory", keepSetting(&newConfig.LogDirectory, currentConfig.LogDirectory))
+	keep("TempDirectory", keepSetting(&newConfig.TempDirectory, currentConfig.TempDirectory))
+	keep("ResponseCache", keepSetting(&newConfig.ResponseCache, currentConfig.ResponseCache))
+	keep("TileCache", keepSetting(&newConfig.TileCache, currentConfig.TileCache))
+	keep("GDALJobQueue.MaxParallelJobs", keepSetting(&newConfig.GDALJobQueue.MaxParallelJobs, currentConfig.GDALJobQueue.MaxParallelJobs))
+	keep("GDALJobQueue.MaxQueueWait", keepSetting(&newConfig.GDALJobQueue.MaxQueueWait, currentConfig.GDALJobQueue.MaxQueueWait))
+	keep("WorkerPools", keepSetting(&newConfig.WorkerPools, currentConfig.WorkerPools))
+	keep("Jobs.MaxParallelJobs", keepSetting(&newConfig.Jobs.MaxParallelJobs, currentConfig.Jobs.MaxParallelJobs))
+	keep("ColorTables.Directory", keepSetting(&newConfig.ColorTables.Directory, currentConfig.ColorTables.Directory))
+	keep("Admin", keepSetting(&newConfig.Admin, currentConfig.Admin))
+	keep("GRPC", keepSetting(&newConfig.GRPC, currentConfig.GRPC))
+
+	return ignored
+}
+
+/*
+reloadProgConfig reloads the configuration file at runtime (e.g. triggered by SIGHUP). In-flight requests
+continue with the previous configuration. The tile repository is rebuilt if the tile repositories or
+disabled sources have changed. On error, the current configuration remains active.
+*/
+func reloadProgConfig(filename string) error {
+	// serialize concurrent reloads (signal and admin endpoint)
+	reloadMutex.Lock()
+	defer reloadMutex.Unlock()
+
+	// notify systemd about reload (service remains ready, also if reload fails)
+	sdNotifyReloading()
+	defer sdNotifyState("READY=1\nSTATUS=serving requests")
+
+	currentConfig := getProgConfig()
+
+	newConfig, overrides, err := loadProgConfig(filename)
+	if err != nil {
+		return err
+	}
+	if len(overrides) > 0 {
+		slog.Info("configuration reload: settings overridden by environment variables", "variables", overrides)
+	}
+
+	filter, err := newIPFilter(newConfig.IPFilter.AllowedNetworks, newConfig.IPFilter.DeniedNetworks)
+	if err != nil {
+		return fmt.Errorf("error [%w] at newIPFilter()", err)
+	}
+	proxies, err := parseNetworks(newConfig.TrustedProxies)
+	if err != nil {
+		return fmt.Errorf("error [%w] at parseNetworks(), trusted proxies", err)
+	}
+
+	ignored := keepRestartOnlySettings(&newConfig, currentConfig)
+	if len(ignored) > 0 {
+		slog.Warn("configuration reload: changed settings require a restart and are ignored", "settings", ignored)
+	}
+
+	// rebuild tile repository
+	if !slices.Equal(newConfig.TileRepositories, currentConfig.TileRepositories) ||
+		!slices.Equal(newConfig.DisabledSources, currentConfig.DisabledSources) {
+		sdNotifyState("STATUS=reloading: rebuilding tile repository")
+		err = buildRepository(newConfig.TileRepositories, newConfig.DisabledSources)
+		if err != nil {
+			return fmt.Errorf("error [%w] at buildRepository()", err)
+		}
+		err = saveRepository()
+		if err != nil {
+			slog.Error("configuration reload: error saving global tile repository", "error", err)
+		}
+	}
+
+	// activate new configuration
+	logLevel.Set(parseLogLevel(newConfig.LogLevel))
+	ipFilter.Store(filter)
+	trustedProxies.Store(&proxies)
+	activeProgConfig.Store(&newConfig)
+
+	slog.Info("configuration reloaded", "configuration file", filename, "log level", newConfig.LogLevel)
+	return nil
+}
+
+/*
+keepSetting replaces the new value by the current value. Returns true if the values differ.
+*/
+func keepSetting[T any](newValue *T, currentValue T) bool {
+	changed := !reflect.DeepEqual(*newValue, currentValue)
+	*newValue = currentValue
+	return changed
+}
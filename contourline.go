@@ -0,0 +1,496 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"klaus/elevation/dtm-elevation-service/pkg/dtm"
+)
+
+// contourLineFeatureCollection represents (the relevant parts of) a GeoJSON feature collection with line strings.
+type contourLineFeatureCollection struct {
+	Type     string               `json:"type"`
+	Name     string               `json:"name,omitempty"`
+	CRS      json.RawMessage      `json:"crs,omitempty"`
+	Features []contourLineFeature `json:"features"`
+}
+
+// contourLineFeature represents a GeoJSON feature with line string geometry.
+type contourLineFeature struct {
+	Type       string         `json:"type"`
+	Properties map[string]any `json:"properties"`
+	Geometry   struct {
+		Type        string      `json:"type"`
+		Coordinates [][]float64 `json:"coordinates"`
+	} `json:"geometry"`
+}
+
+/*
+contourLineRequest handles 'contour line request' from client: the single contour line passing through a point
+(at the elevation of the point) within a radius around the point (e.g. walking along the same elevation).
+*/
+func contourLineRequest(writer http.ResponseWriter, request *http.Request) {
+	var contourLineResponse = ContourLineResponse{Type: TypeContourLineResponse, ID: "unknown"}
+	contourLineResponse.Attributes.IsError = true
+
+	// statistics
+	atomic.AddUint64(&ContourLineRequests, 1)
+
+	// limit overall request body size
+	request.Body = http.MaxBytesReader(writer, request.Body, MaxContourLineRequestBodySize)
+
+	// read request
+	bodyData, err := io.ReadAll(request.Body)
+	if err != nil {
+		// check specifically for the error returned by MaxBytesReader
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			slog.WarnContext(request.Context(), "contour line request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
+			contourLineResponse.Attributes.Error = newErrorObject(EndpointContourLine, ReasonRequestBodyTooLarge, fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit))
+			buildContourLineResponse(writer, request, http.StatusRequestEntityTooLarge, contourLineResponse)
+		} else {
+			// handle other read errors
+			slog.WarnContext(request.Context(), "contour line request: error reading request body", "error", err, "ID", "unknown")
+			contourLineResponse.Attributes.Error = newErrorObject(EndpointContourLine, ReasonReadingRequestBody, err.Error())
+			buildContourLineResponse(writer, request, http.StatusBadRequest, contourLineResponse)
+		}
+		return
+	}
+
+	// unmarshal request
+	contourLineRequest := ContourLineRequest{}
+	err = json.Unmarshal(bodyData, &contourLineRequest)
+	if err != nil {
+		slog.WarnContext(request.Context(), "contour line request: error unmarshaling request body", "error", err, "ID", "unknown")
+		contourLineResponse.Attributes.Error = newErrorObject(EndpointContourLine, ReasonUnmarshalingRequestBody, err.Error())
+		buildContourLineResponse(writer, request, http.StatusBadRequest, contourLineResponse)
+		return
+	}
+
+	// resolve place name (geocoding) into lon/lat coordinates
+	if contourLineRequest.Attributes.Place != "" {
+		place, err := geocodePlace(request.Context(), contourLineRequest.Attributes.Place)
+		if err != nil {
+			slog.WarnContext(request.Context(), "contour line request: error geocoding place", "error", err, "place", contourLineRequest.Attributes.Place, "ID", contourLineRequest.ID)
+			contourLineResponse.ID = contourLineRequest.ID
+			contourLineResponse.Attributes.Place = contourLineRequest.Attributes.Place
+			if errors.Is(err, ErrPlaceNotFound) {
+				contourLineResponse.Attributes.Error = newErrorObject(EndpointContourLine, ReasonPlaceNotFound, err.Error())
+				buildContourLineResponse(writer, request, http.StatusNotFound, contourLineResponse)
+				return
+			}
+			if errors.Is(err, ErrGeocoderUnavailable) {
+				contourLineResponse.Attributes.Error = newErrorObject(EndpointContourLine, ReasonGeocoderUnavailable, err.Error())
+				buildContourLineResponse(writer, request, http.StatusServiceUnavailable, contourLineResponse)
+				return
+			}
+			contourLineResponse.Attributes.Error = newErrorObject(EndpointContourLine, ReasonVerifyingRequestData, err.Error())
+			buildContourLineResponse(writer, request, http.StatusBadRequest, contourLineResponse)
+			return
+		}
+		contourLineRequest.Attributes.Zone = 0
+		contourLineRequest.Attributes.Longitude = place.Longitude
+		contourLineRequest.Attributes.Latitude = place.Latitude
+		contourLineResponse.Attributes.PlaceName = place.DisplayName
+	}
+
+	// copy request parameters into response
+	contourLineResponse.ID = contourLineRequest.ID
+	contourLineResponse.Attributes.Zone = contourLineRequest.Attributes.Zone
+	contourLineResponse.Attributes.Easting = contourLineRequest.Attributes.Easting
+	contourLineResponse.Attributes.Northing = contourLineRequest.Attributes.Northing
+	contourLineResponse.Attributes.Longitude = contourLineRequest.Attributes.Longitude
+	contourLineResponse.Attributes.Latitude = contourLineRequest.Attributes.Latitude
+	contourLineResponse.Attributes.Place = contourLineRequest.Attributes.Place
+	contourLineResponse.Attributes.Radius = contourLineRequest.Attributes.Radius
+
+	// verify request data
+	err = verifyContourLineRequestData(request, contourLineRequest)
+	if err != nil {
+		slog.WarnContext(request.Context(), "contour line request: error verifying request data", "error", err, "ID", contourLineRequest.ID)
+		contourLineResponse.Attributes.Error = newErrorObject(EndpointContourLine, ReasonVerifyingRequestData, err.Error())
+		buildContourLineResponse(writer, request, http.StatusBadRequest, contourLineResponse)
+		return
+	}
+
+	// elevation of point (level of contour line)
+	var elevation float64
+	if contourLineRequest.Attributes.Zone != 0 {
+		elevation, _, err = getElevationForUTMPoint(contourLineRequest.Attributes.Zone, contourLineRequest.Attributes.Easting, contourLineRequest.Attributes.Northing)
+	} else {
+		elevation, _, err = getElevationForPoint(contourLineRequest.Attributes.Longitude, contourLineRequest.Attributes.Latitude)
+	}
+	if err != nil {
+		slog.DebugContext(request.Context(), "contour line request: error getting elevation for point", "error", err, "ID", contourLineRequest.ID)
+		if errors.Is(err, ErrOutsideCoverage) {
+			contourLineResponse.Attributes.Error = newErrorObject(EndpointContourLine, ReasonOutsideCoverage, describeOutsideCoverage(err))
+			buildContourLineResponse(writer, request, http.StatusNotFound, contourLineResponse)
+			return
+		}
+		if errors.Is(err, ErrSourceUnavailable) {
+			contourLineResponse.Attributes.Error = newErrorObject(EndpointContourLine, ReasonSourceUnavailable, err.Error())
+			buildContourLineResponse(writer, request, http.StatusServiceUnavailable, contourLineResponse)
+			return
+		}
+		contourLineResponse.Attributes.Error = newErrorObject(EndpointContourLine, ReasonGettingElevation, err.Error())
+		buildContourLineResponse(writer, request, http.StatusBadRequest, contourLineResponse)
+		return
+	}
+	contourLineResponse.Attributes.Elevation = elevation
+
+	// circle around point in UTM coordinates
+	isLonLat := contourLineRequest.Attributes.Zone == 0
+	zone, ring, err := getCircleRingUTM(contourLineRequest.Attributes.Zone, contourLineRequest.Attributes.Easting, contourLineRequest.Attributes.Northing,
+		contourLineRequest.Attributes.Longitude, contourLineRequest.Attributes.Latitude, contourLineRequest.Attributes.Radius)
+	if err != nil {
+		slog.WarnContext(request.Context(), "contour line request: error transforming point to UTM", "error", err, "ID", contourLineRequest.ID)
+		contourLineResponse.Attributes.Error = newErrorObject(EndpointContourLine, ReasonVerifyingRequestData, err.Error())
+		buildContourLineResponse(writer, request, http.StatusBadRequest, contourLineResponse)
+		return
+	}
+	clipWKT, bounds := buildAreaPolygonWKT(ring)
+
+	// center of circle is the point (in UTM coordinates)
+	easting := (bounds[0] + bounds[2]) / 2
+	northing := (bounds[1] + bounds[3]) / 2
+
+	// get all tiles (metadata) within circle
+	tiles, err := getAllTilesArea(zone, bounds)
+	if err != nil {
+		slog.WarnContext(request.Context(), "contour line request: error getting GeoTIFF tiles for circle", "error", err, "zone", zone, "ID", contourLineRequest.ID)
+		if errors.Is(err, ErrOutsideCoverage) {
+			contourLineResponse.Attributes.Error = newErrorObject(EndpointContourLine, ReasonOutsideCoverage, err.Error())
+			buildContourLineResponse(writer, request, http.StatusNotFound, contourLineResponse)
+			return
+		}
+		contourLineResponse.Attributes.Error = newErrorObject(EndpointContourLine, ReasonVerifyingRequestData, err.Error())
+		buildContourLineResponse(writer, request, http.StatusBadRequest, contourLineResponse)
+		return
+	}
+
+	// conditional request: unchanged response (same parameters, same tiles) needs no processing
+	etag := buildETag("contourline", contourLineRequest, tiles)
+	if checkNotModified(writer, request, etag) {
+		return
+	}
+
+	// build contour line (mosaic of all tiles, progress reported as one step per tile)
+	addJobTiles(request.Context(), len(tiles))
+	contourLine, err := generateContourLineObjectForArea(request.Context(), tiles, zone, clipWKT, bounds, easting, northing, elevation, isLonLat)
+	addJobTilesDone(request.Context(), len(tiles))
+	if err != nil {
+		slog.WarnContext(request.Context(), "contour line request: error generating contour line object", "error", err, "ID", contourLineRequest.ID)
+		if errors.Is(err, context.Canceled) {
+			// client disconnected, processing aborted, no response required
+			return
+		}
+		if errors.Is(err, ErrServerBusy) {
+			contourLineResponse.Attributes.Error = newErrorObject(EndpointContourLine, ReasonServerBusy, err.Error())
+			writer.Header().Set("Retry-After", strconv.Itoa(getProgConfig().GDALJobQueue.RetryAfter))
+			buildContourLineResponse(writer, request, http.StatusTooManyRequests, contourLineResponse)
+			return
+		}
+		contourLineResponse.Attributes.Error = newErrorObject(EndpointContourLine, ReasonGeneratingObject, err.Error())
+		buildContourLineResponse(writer, request, http.StatusBadRequest, contourLineResponse)
+		return
+	}
+	contourLineResponse.Attributes.ContourLine = contourLine
+	for _, tile := range tiles {
+		contourLineResponse.Attributes.AreaTiles = append(contourLineResponse.Attributes.AreaTiles, tile.Index)
+	}
+	addUsage(request.Context(), 0, len(tiles))
+
+	// success response
+	contourLineResponse.Attributes.IsError = false
+	setETag(writer, etag)
+	buildContourLineResponse(writer, request, http.StatusOK, contourLineResponse)
+}
+
+/*
+verifyContourLineRequestData verifies 'contour line' request data.
+*/
+func verifyContourLineRequestData(request *http.Request, contourLineRequest ContourLineRequest) error {
+	// verify HTTP header
+	contentType := request.Header.Get("Content-Type")
+	if !strings.HasPrefix(strings.ToLower(contentType), "application/json") {
+		return fmt.Errorf("unexpected or missing HTTP header field Content-Type, value = [%s], expected 'application/json'", contentType)
+	}
+
+	// verify Type
+	if contourLineRequest.Type != TypeContourLineRequest {
+		return fmt.Errorf("unexpected request Type [%v]", contourLineRequest.Type)
+	}
+
+	// verify ID
+	if len(contourLineRequest.ID) > 1024 {
+		return errors.New("ID must be 0-1024 characters long")
+	}
+
+	// verify coordinates (either utm or lon/lat coordinates must be set)
+	attributes := contourLineRequest.Attributes
+	if attributes.Zone != 0 {
+		if attributes.Zone < 32 || attributes.Zone > 33 {
+			return errors.New("invalid zone for Germany")
+		}
+		if attributes.Easting < 100000 || attributes.Easting > 900000 || attributes.Northing < 5200000 || attributes.Northing > 6200000 {
+			return errors.New("invalid UTM coordinates for Germany")
+		}
+	} else {
+		if attributes.Longitude > 15.3 || attributes.Longitude < 5.5 {
+			return errors.New("invalid longitude for Germany")
+		}
+		if attributes.Latitude > 55.3 || attributes.Latitude < 47.0 {
+			return errors.New("invalid latitude for Germany")
+		}
+	}
+
+	// verify radius (limited by max number of area tiles)
+	if attributes.Radius < 100.0 || attributes.Radius > 4000.0 {
+		return errors.New("radius must be between 100.0 and 4000.0 meters")
+	}
+
+	return nil
+}
+
+/*
+buildContourLineResponse builds HTTP responses with specified status and body.
+*/
+func buildContourLineResponse(writer http.ResponseWriter, request *http.Request, httpStatus int, contourLineResponse ContourLineResponse) {
+	// response metadata (versions, processing duration, cache hit)
+	contourLineResponse.Meta = newResponseMeta(request.Context())
+
+	// encode (streaming) and send response
+	streamJSONResponse(writer, request, httpStatus, contourLineResponse, false)
+}
+
+/*
+generateContourLineObjectForArea builds the contour line through a point for an area (circle) covered by several tiles:
+- build mosaic (VRT) of all tiles and crop it to the bounding box of the area
+- generate contour lines for the single level (elevation of point)
+- clip contour lines to the area and split them into single line strings
+- select the line string nearest to the point (convert to lon/lat if requested)
+*/
+func generateContourLineObjectForArea(ctx context.Context, tiles []TileMetadata, zone int, clipWKT string, bounds [4]float64,
+	easting, northing, level float64, isLonLat bool) (ContourLine, error) {
+	var contourLine ContourLine
+
+	// area index (e.g. 32_497_5670-32_503_5675)
+	areaIndex := tiles[0].Index + "-" + tiles[len(tiles)-1].Index
+
+	// lookup response cache
+	cacheKeyParts := []any{"contourline", clipWKT, easting, northing, level, isLonLat}
+	for _, tile := range tiles {
+		cacheKeyParts = append(cacheKeyParts, tile.Index, tile.Actuality)
+	}
+	cacheKey := buildResponseCacheKey(cacheKeyParts...)
+	cached, found := responseCache.Get(cacheKey)
+	recordCacheLookup(ctx, found)
+	if found {
+		return cached.(ContourLine), nil
+	}
+
+	// run operations in memory (/vsimem)
+	vsimemPrefix := dtm.NewVSIMemPrefix("contourline")
+	filenameVRT := vsimemPrefix + "mosaic.vrt"
+	filenameCroppedTif := vsimemPrefix + "mosaic.cropped.tif"
+	filenameUtmGeoJSON := vsimemPrefix + "mosaic.utm.geojson"
+	filenameClippedGeoJSON := vsimemPrefix + "mosaic.clipped.geojson"
+	defer removeVSIMemFiles(filenameVRT, filenameCroppedTif, filenameUtmGeoJSON, filenameClippedGeoJSON)
+
+	// gdalbuildvrt
+	tilePaths := make([]string, 0, len(tiles))
+	for _, tile := range tiles {
+		tilePaths = append(tilePaths, tile.Path)
+	}
+	err := dtm.BuildVRT(ctx, tilePaths, filenameVRT, nil)
+	if err != nil {
+		return contourLine, fmt.Errorf("error [%w] at dtm.BuildVRT()", err)
+	}
+
+	// gdal_translate: crop mosaic to bounding box of area
+	margin := 2.0
+	err = dtm.Translate(ctx, filenameVRT, filenameCroppedTif, []string{"-of", "GTiff", "-projwin",
+		strconv.FormatFloat(bounds[0]-margin, 'f', -1, 64), strconv.FormatFloat(bounds[3]+margin, 'f', -1, 64),
+		strconv.FormatFloat(bounds[2]+margin, 'f', -1, 64), strconv.FormatFloat(bounds[1]-margin, 'f', -1, 64)})
+	if err != nil {
+		return contourLine, fmt.Errorf("error [%w] at dtm.Translate()", err)
+	}
+
+	// gdal_contour -fl level
+	nameOutputLayer := fmt.Sprintf("Höhenlinie %.2f Meter für Gebiet %s", level, areaIndex)
+	err = dtm.ContourLevel(ctx, filenameCroppedTif, filenameUtmGeoJSON, nameOutputLayer, "Hoehe", level)
+	if err != nil {
+		return contourLine, fmt.Errorf("error [%w] at dtm.ContourLevel()", err)
+	}
+
+	// ogr2ogr: clip to area, split multi line strings into line strings
+	err = dtm.VectorTranslate(ctx, filenameUtmGeoJSON, filenameClippedGeoJSON, []string{"-f", "GeoJSON", "-clipsrc", clipWKT, "-explodecollections"})
+	if err != nil {
+		return contourLine, fmt.Errorf("error [%w] at dtm.VectorTranslate()", err)
+	}
+
+	// read result file
+	data, err := dtm.ReadVSIMemFile(filenameClippedGeoJSON)
+	if err != nil {
+		return contourLine, fmt.Errorf("error [%w] at dtm.ReadVSIMemFile()", err)
+	}
+	var featureCollection contourLineFeatureCollection
+	err = json.Unmarshal(data, &featureCollection)
+	if err != nil {
+		return contourLine, fmt.Errorf("error [%w] at json.Unmarshal()", err)
+	}
+
+	// select line string nearest to point
+	nearest := -1
+	minDistance := math.Inf(1)
+	for i, feature := range featureCollection.Features {
+		if feature.Geometry.Type != "LineString" {
+			continue
+		}
+		distance := distancePointToLine(easting, northing, feature.Geometry.Coordinates)
+		if distance < minDistance {
+			nearest = i
+			minDistance = distance
+		}
+	}
+	if nearest < 0 {
+		return contourLine, fmt.Errorf("no contour line at elevation %.2f m found within area", level)
+	}
+	feature := featureCollection.Features[nearest]
+	coordinates := feature.Geometry.Coordinates
+	first := coordinates[0]
+	last := coordinates[len(coordinates)-1]
+	contourLine.IsClosed = first[0] == last[0] && first[1] == last[1]
+	contourLine.Length = lengthOfLine(coordinates)
+	contourLine.Distance = minDistance
+	contourLine.Elevation = level
+
+	// transform line string into lon/lat coordinates
+	if isLonLat {
+		err = transformLineUTMToLonLat(coordinates, zone)
+		if err != nil {
+			return contourLine, fmt.Errorf("error [%w] at transformLineUTMToLonLat()", err)
+		}
+		featureCollection.CRS = nil
+	}
+
+	// feature collection with selected contour line only
+	featureCollection.Features = []contourLineFeature{feature}
+	data, err = json.Marshal(featureCollection)
+	if err != nil {
+		return contourLine, fmt.Errorf("error [%w] at json.Marshal()", err)
+	}
+
+	// distinct actualities, origins and attributions of all tiles
+	var actualities, origins, attributions []string
+	for _, tile := range tiles {
+		if !slices.Contains(actualities, tile.Actuality) {
+			actualities = append(actualities, tile.Actuality)
+		}
+		if slices.Contains(origins, tile.Source) {
+			continue
+		}
+		origins = append(origins, tile.Source)
+		attribution := "unknown"
+		resource, err := getElevationResource(tile.Source)
+		if err != nil {
+			slog.ErrorContext(ctx, "contour line request: error getting elevation resource", "error", err, "source", tile.Source)
+		} else {
+			attribution = resource.Attribution
+		}
+		attributions = append(attributions, attribution)
+	}
+	slices.Sort(actualities)
+
+	// set contour line return structure
+	contourLine.Data = data
+	contourLine.DataFormat = "geojson"
+	contourLine.Filename = buildObjectFilename(areaIndex, "contourline", strconv.FormatFloat(level, 'f', 2, 64)+"m", actualities[len(actualities)-1], contourLine.DataFormat)
+	contourLine.Actuality = strings.Join(actualities, ", ")
+	contourLine.Origin = strings.Join(origins, ", ")
+	contourLine.Attribution = strings.Join(attributions, "; ")
+	contourLine.TileIndex = areaIndex
+
+	// add to response cache
+	responseCache.Add(cacheKey, contourLine, len(contourLine.Data))
+
+	return contourLine, nil
+}
+
+/*
+distancePointToLine calculates the (minimal) distance between a point and a line string (planar coordinates).
+*/
+func distancePointToLine(x, y float64, line [][]float64) float64 {
+	minDistance := math.Inf(1)
+	for i := 1; i < len(line); i++ {
+		x1, y1 := line[i-1][0], line[i-1][1]
+		x2, y2 := line[i][0], line[i][1]
+
+		// projection of point onto segment (limited to segment end points)
+		dx, dy := x2-x1, y2-y1
+		t := 0.0
+		if dx != 0 || dy != 0 {
+			t = ((x-x1)*dx + (y-y1)*dy) / (dx*dx + dy*dy)
+			t = math.Max(0, math.Min(1, t))
+		}
+		distance := math.Hypot(x-(x1+t*dx), y-(y1+t*dy))
+		minDistance = math.Min(minDistance, distance)
+	}
+	return minDistance
+}
+
+/*
+lengthOfLine calculates the length of a line string (planar coordinates).
+*/
+func lengthOfLine(line [][]float64) float64 {
+	length := 0.0
+	for i := 1; i < len(line); i++ {
+		length += math.Hypot(line[i][0]-line[i-1][0], line[i][1]-line[i-1][1])
+	}
+	return length
+}
+
+/*
+transformLineUTMToLonLat transforms the coordinates of a line string (in place) from UTM (ETRS89) into lon/lat (WGS84).
+*/
+func transformLineUTMToLonLat(line [][]float64, zone int) error {
+	// get (cached) transformation from UTM zone (e.g. 25832) to WGS84 (EPSG:4326)
+	transform, err := acquireTransform(25800+zone, 4326)
+	if err != nil {
+		return fmt.Errorf("error [%w] at acquireTransform()", err)
+	}
+	defer releaseTransform(transform)
+
+	xCoords := make([]float64, len(line))
+	yCoords := make([]float64, len(line))
+	for i, point := range line {
+		xCoords[i] = point[0]
+		yCoords[i] = point[1]
+	}
+	successFlags := make([]bool, len(line))
+
+	// perform transformation
+	err = transform.TransformEx(xCoords, yCoords, []float64{}, successFlags)
+	if err != nil {
+		return fmt.Errorf("error during coordinate transformation: %w", err)
+	}
+
+	for i := range line {
+		if !successFlags[i] {
+			return fmt.Errorf("transformation from EPSG:%d to EPSG:4326 failed for coordinates (%.3f, %.3f)", 25800+zone, xCoords[i], yCoords[i])
+		}
+		line[i] = []float64{xCoords[i], yCoords[i]}
+	}
+
+	return nil
+}
@@ -0,0 +1,499 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"klaus/elevation/dtm-elevation-service/pkg/dtm"
+)
+
+// max number of tiles (1 km²) for area requests (limits memory and processing time)
+const maxAreaTiles = 100
+
+/*
+contoursAreaRequest handles 'contours request' for an area (bounding box, polygon, clip polygon or administrative area) spanning
+several tiles. All tiles are combined to a mosaic (VRT), contours are generated once and clipped to the area.
+This results in one seamless GeoJSON without duplicate lines at tile seams.
+*/
+func contoursAreaRequest(writer http.ResponseWriter, request *http.Request, contoursRequest ContoursRequest, contoursResponse ContoursResponse) {
+	isLonLat := contoursRequest.Attributes.Zone == 0
+	var zone int
+	var clipWKT string
+	var bounds [4]float64
+
+	if contoursRequest.Attributes.AdministrativeArea != "" {
+		// get boundary of administrative area in UTM coordinates
+		area, err := getAdministrativeArea(contoursRequest.Attributes.AdministrativeArea)
+		if err != nil {
+			slog.WarnContext(request.Context(), "contours request: error getting administrative area", "error", err,
+				"administrative area", contoursRequest.Attributes.AdministrativeArea, "ID", contoursRequest.ID)
+			if errors.Is(err, ErrAdministrativeAreaNotFound) {
+				contoursResponse.Attributes.Error = newErrorObject(EndpointContours, ReasonAdministrativeAreaNotFound, err.Error())
+				buildContoursResponse(writer, request, http.StatusNotFound, contoursResponse)
+				return
+			}
+			contoursResponse.Attributes.Error = newErrorObject(EndpointContours, ReasonVerifyingRequestData, err.Error())
+			buildContoursResponse(writer, request, http.StatusBadRequest, contoursResponse)
+			return
+		}
+		zone, clipWKT, bounds = area.Zone, area.WKT, area.Bounds
+	} else if contoursRequest.Attributes.ClipPolygon != nil {
+		// get clip polygon (GeoJSON) in UTM coordinates
+		var err error
+		zone, clipWKT, bounds, err = getClipPolygonUTM(contoursRequest.Attributes.Zone, contoursRequest.Attributes.ClipPolygon)
+		if err != nil {
+			slog.WarnContext(request.Context(), "contours request: error transforming clip polygon to UTM", "error", err, "ID", contoursRequest.ID)
+			contoursResponse.Attributes.Error = newErrorObject(EndpointContours, ReasonVerifyingRequestData, err.Error())
+			buildContoursResponse(writer, request, http.StatusBadRequest, contoursResponse)
+			return
+		}
+	} else {
+		// get area ring in UTM coordinates
+		var ring [][2]float64
+		var err error
+		zone, ring, err = getContoursAreaRingUTM(contoursRequest.Attributes.Zone, contoursRequest.Attributes.Area)
+		if err != nil {
+			slog.WarnContext(request.Context(), "contours request: error transforming area to UTM", "error", err, "ID", contoursRequest.ID)
+			contoursResponse.Attributes.Error = newErrorObject(EndpointContours, ReasonVerifyingRequestData, err.Error())
+			buildContoursResponse(writer, request, http.StatusBadRequest, contoursResponse)
+			return
+		}
+		clipWKT, bounds = buildAreaPolygonWKT(ring)
+	}
+	if !isLonLat {
+		// contours in UTM coordinates of the area zone
+		contoursResponse.Attributes.Zone = zone
+	}
+
+	// get all tiles (metadata) within area
+	tiles, err := getAllTilesArea(zone, bounds)
+	if err != nil {
+		slog.WarnContext(request.Context(), "contours request: error getting GeoTIFF tiles for area", "error", err, "zone", zone, "ID", contoursRequest.ID)
+		if errors.Is(err, ErrOutsideCoverage) {
+			contoursResponse.Attributes.Error = newErrorObject(EndpointContours, ReasonOutsideCoverage, err.Error())
+			buildContoursResponse(writer, request, http.StatusNotFound, contoursResponse)
+			return
+		}
+		contoursResponse.Attributes.Error = newErrorObject(EndpointContours, ReasonVerifyingRequestData, err.Error())
+		buildContoursResponse(writer, request, http.StatusBadRequest, contoursResponse)
+		return
+	}
+
+	// conditional request: unchanged response (same parameters, same tiles) needs no processing
+	etag := buildETag("contours-area", contoursRequest, tiles)
+	if checkNotModified(writer, request, etag) {
+		return
+	}
+
+	// build contours for area (mosaic of all tiles, progress reported as one step per tile)
+	addJobTiles(request.Context(), len(tiles))
+	attribute := newContourAttribute(contoursRequest.Attributes.AttributeName, contoursRequest.Attributes.Unit, contoursRequest.Attributes.Precision)
+	contour, err := generateContourObjectForArea(request.Context(), tiles, zone, clipWKT, contoursRequest.Attributes.Equidistance, isLonLat,
+		contoursRequest.Attributes.VerticalOffset, attribute)
+	addJobTilesDone(request.Context(), len(tiles))
+	if err != nil {
+		slog.WarnContext(request.Context(), "contours request: error generating contours object for area", "error", err, "ID", contoursRequest.ID)
+		if errors.Is(err, context.Canceled) {
+			// client disconnected, processing aborted, no response required
+			return
+		}
+		if errors.Is(err, ErrServerBusy) {
+			contoursResponse.Attributes.Error = newErrorObject(EndpointContours, ReasonServerBusy, err.Error())
+			writer.Header().Set("Retry-After", strconv.Itoa(getProgConfig().GDALJobQueue.RetryAfter))
+			buildContoursResponse(writer, request, http.StatusTooManyRequests, contoursResponse)
+			return
+		}
+		contoursResponse.Attributes.Error = newErrorObject(EndpointContours, ReasonGeneratingObject, err.Error())
+		buildContoursResponse(writer, request, http.StatusBadRequest, contoursResponse)
+		return
+	}
+	contoursResponse.Attributes.Contours = []Contour{contour}
+	for _, tile := range tiles {
+		contoursResponse.Attributes.AreaTiles = append(contoursResponse.Attributes.AreaTiles, tile.Index)
+	}
+	addUsage(request.Context(), 0, len(tiles))
+
+	// success response
+	contoursResponse.Attributes.IsError = false
+	setETag(writer, etag)
+	buildContoursResponse(writer, request, http.StatusOK, contoursResponse)
+}
+
+/*
+verifyContoursArea verifies the area (bounding box or polygon) of a 'contours' request.
+*/
+func verifyContoursArea(zone int, area *ContoursArea) error {
+	points := area.Polygon
+	if len(points) == 0 {
+		if area.MinX >= area.MaxX || area.MinY >= area.MaxY {
+			return errors.New("invalid area bounding box (min must be less than max)")
+		}
+		points = [][2]float64{{area.MinX, area.MinY}, {area.MaxX, area.MaxY}}
+	} else if len(points) < 3 {
+		return errors.New("area polygon must have at least 3 points")
+	}
+
+	for _, point := range points {
+		if zone != 0 {
+			// plausible UTM coordinates for Germany
+			if point[0] < 100000 || point[0] > 900000 || point[1] < 5200000 || point[1] > 6200000 {
+				return fmt.Errorf("invalid UTM coordinates in area (%.2f, %.2f)", point[0], point[1])
+			}
+		} else {
+			// lon/lat coordinates for Germany
+			if point[0] > 15.3 || point[0] < 5.5 || point[1] > 55.3 || point[1] < 47.0 {
+				return fmt.Errorf("invalid lon/lat coordinates in area (%.8f, %.8f)", point[0], point[1])
+			}
+		}
+	}
+
+	return nil
+}
+
+/*
+getClipPolygons returns the polygons (rings, first ring is exterior ring, further rings are holes) of a GeoJSON
+Polygon, MultiPolygon or Feature with such a geometry.
+*/
+func getClipPolygons(geometry *GeoJSONGeometry) ([][][][2]float64, error) {
+	var polygons [][][][2]float64
+
+	if geometry.Type == "Feature" {
+		if geometry.Geometry == nil {
+			return nil, errors.New("clip polygon: feature without geometry")
+		}
+		geometry = geometry.Geometry
+	}
+
+	switch geometry.Type {
+	case "Polygon":
+		var polygon [][][2]float64
+		err := json.Unmarshal(geometry.Coordinates, &polygon)
+		if err != nil {
+			return nil, fmt.Errorf("clip polygon: invalid coordinates of Polygon: %w", err)
+		}
+		polygons = append(polygons, polygon)
+	case "MultiPolygon":
+		err := json.Unmarshal(geometry.Coordinates, &polygons)
+		if err != nil {
+			return nil, fmt.Errorf("clip polygon: invalid coordinates of MultiPolygon: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("clip polygon: unsupported GeoJSON type [%s], expected Polygon, MultiPolygon or Feature", geometry.Type)
+	}
+
+	if len(polygons) == 0 {
+		return nil, errors.New("clip polygon: no polygon")
+	}
+	for _, polygon := range polygons {
+		if len(polygon) == 0 {
+			return nil, errors.New("clip polygon: polygon without rings")
+		}
+	}
+
+	return polygons, nil
+}
+
+/*
+verifyClipPolygon verifies the clip polygon (GeoJSON) of a 'contours' request.
+*/
+func verifyClipPolygon(zone int, geometry *GeoJSONGeometry) error {
+	polygons, err := getClipPolygons(geometry)
+	if err != nil {
+		return err
+	}
+
+	// each ring must be a valid area polygon (at least 3 points, plausible coordinates)
+	for _, polygon := range polygons {
+		for _, ring := range polygon {
+			err = verifyContoursArea(zone, &ContoursArea{Polygon: ring})
+			if err != nil {
+				return fmt.Errorf("clip polygon: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+/*
+getClipPolygonUTM returns the UTM zone, the clip polygon (WKT) and its bounding box in UTM coordinates.
+For lon/lat input the zone is derived from the center of the clip polygon.
+*/
+func getClipPolygonUTM(zone int, geometry *GeoJSONGeometry) (int, string, [4]float64, error) {
+	var bounds [4]float64
+
+	polygons, err := getClipPolygons(geometry)
+	if err != nil {
+		return 0, "", bounds, err
+	}
+
+	if zone == 0 {
+		// lon/lat input: zone 32 (6° - 12° E) or zone 33 (12° - 18° E)
+		minLon, maxLon := math.Inf(1), math.Inf(-1)
+		for _, polygon := range polygons {
+			for _, point := range polygon[0] {
+				minLon = min(minLon, point[0])
+				maxLon = max(maxLon, point[0])
+			}
+		}
+		zone = 32
+		if (minLon+maxLon)/2 >= 12.0 {
+			zone = 33
+		}
+
+		for _, polygon := range polygons {
+			for _, ring := range polygon {
+				for i, point := range ring {
+					easting, northing, err := transformLonLatToUTM(point[0], point[1], 25800+zone)
+					if err != nil {
+						return 0, "", bounds, fmt.Errorf("error [%w] at transformLonLatToUTM()", err)
+					}
+					ring[i] = [2]float64{easting, northing}
+				}
+			}
+		}
+	}
+
+	// MULTIPOLYGON (closed rings), bounding box of exterior rings
+	bounds = [4]float64{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+	polygonsWKT := make([]string, 0, len(polygons))
+	for _, polygon := range polygons {
+		ringsWKT := make([]string, 0, len(polygon))
+		for i, ring := range polygon {
+			if ring[0] != ring[len(ring)-1] {
+				ring = append(ring, ring[0])
+			}
+			vertices := make([]string, 0, len(ring))
+			for _, point := range ring {
+				if i == 0 {
+					bounds[0] = min(bounds[0], point[0])
+					bounds[1] = min(bounds[1], point[1])
+					bounds[2] = max(bounds[2], point[0])
+					bounds[3] = max(bounds[3], point[1])
+				}
+				vertices = append(vertices, fmt.Sprintf("%.3f %.3f", point[0], point[1]))
+			}
+			ringsWKT = append(ringsWKT, "("+strings.Join(vertices, ", ")+")")
+		}
+		polygonsWKT = append(polygonsWKT, "("+strings.Join(ringsWKT, ", ")+")")
+	}
+
+	return zone, "MULTIPOLYGON(" + strings.Join(polygonsWKT, ", ") + ")", bounds, nil
+}
+
+/*
+getContoursAreaRingUTM returns the UTM zone and the (closed) ring of the area in UTM coordinates.
+For lon/lat input the zone is derived from the center of the area.
+*/
+func getContoursAreaRingUTM(zone int, area *ContoursArea) (int, [][2]float64, error) {
+	ring := slices.Clone(area.Polygon)
+	if len(ring) == 0 {
+		ring = [][2]float64{
+			{area.MinX, area.MinY},
+			{area.MaxX, area.MinY},
+			{area.MaxX, area.MaxY},
+			{area.MinX, area.MaxY},
+		}
+	}
+	if ring[0] != ring[len(ring)-1] {
+		ring = append(ring, ring[0])
+	}
+
+	if zone != 0 {
+		return zone, ring, nil
+	}
+
+	// lon/lat input: zone 32 (6° - 12° E) or zone 33 (12° - 18° E)
+	minLon, maxLon := math.Inf(1), math.Inf(-1)
+	for _, point := range ring {
+		minLon = min(minLon, point[0])
+		maxLon = max(maxLon, point[0])
+	}
+	zone = 32
+	if (minLon+maxLon)/2 >= 12.0 {
+		zone = 33
+	}
+
+	for i, point := range ring {
+		easting, northing, err := transformLonLatToUTM(point[0], point[1], 25800+zone)
+		if err != nil {
+			return 0, nil, fmt.Errorf("error [%w] at transformLonLatToUTM()", err)
+		}
+		ring[i] = [2]float64{easting, northing}
+	}
+
+	return zone, ring, nil
+}
+
+/*
+buildAreaPolygonWKT builds the WKT polygon and the bounding box (min x, min y, max x, max y) for a (closed) ring.
+*/
+func buildAreaPolygonWKT(ring [][2]float64) (string, [4]float64) {
+	bounds := [4]float64{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+	vertices := make([]string, 0, len(ring))
+	for _, point := range ring {
+		bounds[0] = min(bounds[0], point[0])
+		bounds[1] = min(bounds[1], point[1])
+		bounds[2] = max(bounds[2], point[0])
+		bounds[3] = max(bounds[3], point[1])
+		vertices = append(vertices, fmt.Sprintf("%.3f %.3f", point[0], point[1]))
+	}
+	return "POLYGON((" + strings.Join(vertices, ", ") + "))", bounds
+}
+
+/*
+getAllTilesArea gets metadata for all (primary) tiles intersecting the bounding box (UTM: min easting, min northing,
+max easting, max northing). Tiles outside the data coverage are skipped.
+*/
+func getAllTilesArea(zone int, bounds [4]float64) ([]TileMetadata, error) {
+	return getAllTilesAreaLimited(zone, bounds, maxAreaTiles)
+}
+
+/*
+getAllTilesAreaLimited gets metadata for all (primary) tiles intersecting the bounding box (UTM) like getAllTilesArea,
+but with a custom limit for the number of tiles (e.g. large-area contours export).
+*/
+func getAllTilesAreaLimited(zone int, bounds [4]float64, maxTiles int) ([]TileMetadata, error) {
+	// 1000 x 1000 m grid
+	minEastingPrefix := int(math.Floor(bounds[0] / 1000.0))
+	minNorthingPrefix := int(math.Floor(bounds[1] / 1000.0))
+	maxEastingPrefix := int(math.Floor(bounds[2] / 1000.0))
+	maxNorthingPrefix := int(math.Floor(bounds[3] / 1000.0))
+
+	count := (maxEastingPrefix - minEastingPrefix + 1) * (maxNorthingPrefix - minNorthingPrefix + 1)
+	if count > maxTiles {
+		return nil, fmt.Errorf("area spans %d tiles, max %d tiles (km²) supported", count, maxTiles)
+	}
+
+	var tiles []TileMetadata
+	repositoryMutex.RLock()
+	for eastingPrefix := minEastingPrefix; eastingPrefix <= maxEastingPrefix; eastingPrefix++ {
+		for northingPrefix := minNorthingPrefix; northingPrefix <= maxNorthingPrefix; northingPrefix++ {
+			tile, found := Repository.Tile(fmt.Sprintf("%d_%d_%d", zone, eastingPrefix, northingPrefix))
+			if found {
+				tiles = append(tiles, tile)
+			}
+		}
+	}
+	repositoryMutex.RUnlock()
+
+	if len(tiles) == 0 {
+		return nil, fmt.Errorf("no tiles found in area (zone %d): %w", zone, ErrOutsideCoverage)
+	}
+
+	return tiles, nil
+}
+
+/*
+generateContourObjectForArea builds one contour object for an area covered by several tiles.
+Strategy to avoid duplicate lines at tile seams:
+- build mosaic (VRT) of all tiles
+- generate contours once in the source SRS
+- clip contours to area (and convert to the target SRS)
+*/
+func generateContourObjectForArea(ctx context.Context, tiles []TileMetadata, zone int, clipWKT string, equidistance float64, isLonLat bool, verticalOffset float64,
+	attribute contourAttribute) (Contour, error) {
+	var contour Contour
+
+	// area index (e.g. 32_497_5670-32_503_5675)
+	firstIndex := tiles[0].Index
+	lastIndex := tiles[len(tiles)-1].Index
+	areaIndex := firstIndex + "-" + lastIndex
+
+	// lookup response cache
+	cacheKeyParts := []any{"contours-area", clipWKT, equidistance, isLonLat, verticalOffset, attribute.name, attribute.unit, attribute.precision}
+	for _, tile := range tiles {
+		cacheKeyParts = append(cacheKeyParts, tile.Index, tile.Actuality)
+	}
+	cacheKey := buildResponseCacheKey(cacheKeyParts...)
+	cached, found := responseCache.Get(cacheKey)
+	recordCacheLookup(ctx, found)
+	if found {
+		return cached.(Contour), nil
+	}
+
+	// run operations in memory (/vsimem)
+	vsimemPrefix := dtm.NewVSIMemPrefix("contours-area")
+	filenameVRT := vsimemPrefix + "mosaic.vrt"
+	filenameUtmGeoJSON := vsimemPrefix + "mosaic.utm.geojson"
+	filenameClippedGeoJSON := vsimemPrefix + "mosaic.clipped.geojson"
+	defer removeVSIMemFiles(filenameVRT, filenameUtmGeoJSON, filenameClippedGeoJSON)
+
+	// gdalbuildvrt
+	tilePaths := make([]string, 0, len(tiles))
+	for _, tile := range tiles {
+		tilePaths = append(tilePaths, tile.Path)
+	}
+	err := dtm.BuildVRT(ctx, tilePaths, filenameVRT, nil)
+	if err != nil {
+		return contour, fmt.Errorf("error [%w] at dtm.BuildVRT()", err)
+	}
+
+	equidistanceString := fmt.Sprintf("%.2f", equidistance)
+	nameOutputLayer := fmt.Sprintf("Höhenlinien %s %s für Gebiet %s", equidistanceString, attribute.layerUnitName(), areaIndex)
+
+	// gdal_contour (once for mosaic)
+	err = dtm.Contour(ctx, filenameVRT, filenameUtmGeoJSON, nameOutputLayer, attribute.dtmAttribute(), equidistance, verticalOffset)
+	if err != nil {
+		return contour, fmt.Errorf("error [%w] at dtm.Contour()", err)
+	}
+
+	// ogr2ogr: clip to area (clip geometry in source SRS)
+	switches := []string{"-f", "GeoJSON", "-clipsrc", clipWKT}
+	if isLonLat {
+		switches = append(switches, "-s_srs", fmt.Sprintf("EPSG:258%d", zone), "-t_srs", "EPSG:4326")
+	}
+	err = dtm.VectorTranslate(ctx, filenameUtmGeoJSON, filenameClippedGeoJSON, switches)
+	if err != nil {
+		return contour, fmt.Errorf("error [%w] at dtm.VectorTranslate()", err)
+	}
+
+	// read result file
+	data, err := dtm.ReadVSIMemFile(filenameClippedGeoJSON)
+	if err != nil {
+		return contour, fmt.Errorf("error [%w] at dtm.ReadVSIMemFile()", err)
+	}
+
+	// distinct actualities, origins and attributions of all tiles
+	var actualities, origins, attributions []string
+	for _, tile := range tiles {
+		if !slices.Contains(actualities, tile.Actuality) {
+			actualities = append(actualities, tile.Actuality)
+		}
+		if slices.Contains(origins, tile.Source) {
+			continue
+		}
+		origins = append(origins, tile.Source)
+		attribution := "unknown"
+		resource, err := getElevationResource(tile.Source)
+		if err != nil {
+			slog.ErrorContext(ctx, "contours request: error getting elevation resource", "error", err, "source", tile.Source)
+		} else {
+			attribution = resource.Attribution
+		}
+		attributions = append(attributions, attribution)
+	}
+	slices.Sort(actualities)
+
+	// set contour return structure
+	contour.Data = data
+	contour.DataFormat = "geojson"
+	contour.Filename = buildObjectFilename(areaIndex, "contours", strconv.FormatFloat(equidistance, 'f', -1, 64)+attribute.unit, actualities[len(actualities)-1], contour.DataFormat)
+	contour.Actuality = strings.Join(actualities, ", ")
+	contour.Origin = strings.Join(origins, ", ")
+	contour.Attribution = strings.Join(attributions, "; ")
+	contour.TileIndex = areaIndex
+
+	// add to response cache
+	responseCache.Add(cacheKey, contour, len(contour.Data))
+
+	return contour, nil
+}
@@ -0,0 +1,363 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"klaus/elevation/dtm-elevation-service/pkg/dtm"
+)
+
+/*
+contoursExportRequest handles 'contours export request' (large-area contours, available as asynchronous job only).
+All tiles of the area (e.g. Landkreis with several hundred tiles) are combined to one mosaic (VRT), contours are
+generated once for the whole mosaic and clipped to the area. Lines therefore run continuously across tile boundaries
+(no seams, no duplicate lines). The result is delivered as one GeoPackage, optionally uploaded to the configured S3 bucket.
+*/
+func contoursExportRequest(writer http.ResponseWriter, request *http.Request) {
+	var contoursExportResponse = ContoursExportResponse{Type: TypeContoursExportResponse, ID: "unknown"}
+	contoursExportResponse.Attributes.IsError = true
+
+	// statistics
+	atomic.AddUint64(&ContoursExportRequests, 1)
+
+	// limit overall request body size
+	request.Body = http.MaxBytesReader(writer, request.Body, MaxContoursExportRequestBodySize)
+
+	// read request
+	bodyData, err := io.ReadAll(request.Body)
+	if err != nil {
+		// check specifically for the error returned by MaxBytesReader
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			slog.WarnContext(request.Context(), "contours export request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
+			contoursExportResponse.Attributes.Error = newErrorObject(EndpointContoursExport, ReasonRequestBodyTooLarge, fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit))
+			buildContoursExportResponse(writer, request, http.StatusRequestEntityTooLarge, contoursExportResponse)
+		} else {
+			// handle other read errors
+			slog.WarnContext(request.Context(), "contours export request: error reading request body", "error", err, "ID", "unknown")
+			contoursExportResponse.Attributes.Error = newErrorObject(EndpointContoursExport, ReasonReadingRequestBody, err.Error())
+			buildContoursExportResponse(writer, request, http.StatusBadRequest, contoursExportResponse)
+		}
+		return
+	}
+
+	// unmarshal request
+	contoursExportRequest := ContoursExportRequest{}
+	err = json.Unmarshal(bodyData, &contoursExportRequest)
+	if err != nil {
+		slog.WarnContext(request.Context(), "contours export request: error unmarshaling request body", "error", err, "ID", "unknown")
+		contoursExportResponse.Attributes.Error = newErrorObject(EndpointContoursExport, ReasonUnmarshalingRequestBody, err.Error())
+		buildContoursExportResponse(writer, request, http.StatusBadRequest, contoursExportResponse)
+		return
+	}
+
+	// set defaults
+	if contoursExportRequest.Attributes.Equidistance == 0 {
+		contoursExportRequest.Attributes.Equidistance = 10.0
+	}
+
+	// copy request parameters into response
+	contoursExportResponse.ID = contoursExportRequest.ID
+	contoursExportResponse.Attributes.Zone = contoursExportRequest.Attributes.Zone
+	contoursExportResponse.Attributes.Area = contoursExportRequest.Attributes.Area
+	contoursExportResponse.Attributes.AdministrativeArea = contoursExportRequest.Attributes.AdministrativeArea
+	contoursExportResponse.Attributes.Equidistance = contoursExportRequest.Attributes.Equidistance
+	contoursExportResponse.Attributes.VerticalOffset = contoursExportRequest.Attributes.VerticalOffset
+	contoursExportResponse.Attributes.AttributeName = contoursExportRequest.Attributes.AttributeName
+	contoursExportResponse.Attributes.Precision = contoursExportRequest.Attributes.Precision
+	contoursExportResponse.Attributes.Unit = contoursExportRequest.Attributes.Unit
+
+	// verify request data
+	err = verifyContoursExportRequestData(request, contoursExportRequest)
+	if err != nil {
+		slog.WarnContext(request.Context(), "contours export request: error verifying request data", "error", err, "ID", contoursExportRequest.ID)
+		contoursExportResponse.Attributes.Error = newErrorObject(EndpointContoursExport, ReasonVerifyingRequestData, err.Error())
+		buildContoursExportResponse(writer, request, http.StatusBadRequest, contoursExportResponse)
+		return
+	}
+
+	// get area (clip polygon) in UTM coordinates
+	isLonLat := contoursExportRequest.Attributes.Zone == 0
+	var zone int
+	var clipWKT string
+	var bounds [4]float64
+	if contoursExportRequest.Attributes.AdministrativeArea != "" {
+		area, err := getAdministrativeArea(contoursExportRequest.Attributes.AdministrativeArea)
+		if err != nil {
+			slog.WarnContext(request.Context(), "contours export request: error getting administrative area", "error", err,
+				"administrative area", contoursExportRequest.Attributes.AdministrativeArea, "ID", contoursExportRequest.ID)
+			if errors.Is(err, ErrAdministrativeAreaNotFound) {
+				contoursExportResponse.Attributes.Error = newErrorObject(EndpointContoursExport, ReasonAdministrativeAreaNotFound, err.Error())
+				buildContoursExportResponse(writer, request, http.StatusNotFound, contoursExportResponse)
+				return
+			}
+			contoursExportResponse.Attributes.Error = newErrorObject(EndpointContoursExport, ReasonVerifyingRequestData, err.Error())
+			buildContoursExportResponse(writer, request, http.StatusBadRequest, contoursExportResponse)
+			return
+		}
+		zone, clipWKT, bounds = area.Zone, area.WKT, area.Bounds
+	} else {
+		var ring [][2]float64
+		zone, ring, err = getContoursAreaRingUTM(contoursExportRequest.Attributes.Zone, contoursExportRequest.Attributes.Area)
+		if err != nil {
+			slog.WarnContext(request.Context(), "contours export request: error transforming area to UTM", "error", err, "ID", contoursExportRequest.ID)
+			contoursExportResponse.Attributes.Error = newErrorObject(EndpointContoursExport, ReasonVerifyingRequestData, err.Error())
+			buildContoursExportResponse(writer, request, http.StatusBadRequest, contoursExportResponse)
+			return
+		}
+		clipWKT, bounds = buildAreaPolygonWKT(ring)
+	}
+	if !isLonLat {
+		// contours in UTM coordinates of the area zone
+		contoursExportResponse.Attributes.Zone = zone
+	}
+
+	// get all tiles (metadata) within area
+	tiles, err := getAllTilesAreaLimited(zone, bounds, getProgConfig().Export.MaxContoursTiles)
+	if err != nil {
+		slog.WarnContext(request.Context(), "contours export request: error getting GeoTIFF tiles for area", "error", err, "zone", zone, "ID", contoursExportRequest.ID)
+		if errors.Is(err, ErrOutsideCoverage) {
+			contoursExportResponse.Attributes.Error = newErrorObject(EndpointContoursExport, ReasonOutsideCoverage, err.Error())
+			buildContoursExportResponse(writer, request, http.StatusNotFound, contoursExportResponse)
+			return
+		}
+		contoursExportResponse.Attributes.Error = newErrorObject(EndpointContoursExport, ReasonVerifyingRequestData, err.Error())
+		buildContoursExportResponse(writer, request, http.StatusBadRequest, contoursExportResponse)
+		return
+	}
+	contoursExportResponse.Attributes.AreaTiles = len(tiles)
+
+	// build GeoPackage for area (mosaic of all tiles, progress reported as one step per tile)
+	addJobTiles(request.Context(), len(tiles))
+	attribute := newContourAttribute(contoursExportRequest.Attributes.AttributeName, contoursExportRequest.Attributes.Unit, contoursExportRequest.Attributes.Precision)
+	data, err := generateContoursGeoPackageForArea(request.Context(), tiles, zone, clipWKT, contoursExportRequest.Attributes.Equidistance, isLonLat,
+		contoursExportRequest.Attributes.VerticalOffset, attribute)
+	addJobTilesDone(request.Context(), len(tiles))
+	if err != nil {
+		slog.WarnContext(request.Context(), "contours export request: error generating GeoPackage for area", "error", err, "ID", contoursExportRequest.ID)
+		if errors.Is(err, context.Canceled) {
+			// client disconnected (job deleted), processing aborted, no response required
+			return
+		}
+		if errors.Is(err, ErrServerBusy) {
+			contoursExportResponse.Attributes.Error = newErrorObject(EndpointContoursExport, ReasonServerBusy, err.Error())
+			writer.Header().Set("Retry-After", strconv.Itoa(getProgConfig().GDALJobQueue.RetryAfter))
+			buildContoursExportResponse(writer, request, http.StatusTooManyRequests, contoursExportResponse)
+			return
+		}
+		contoursExportResponse.Attributes.Error = newErrorObject(EndpointContoursExport, ReasonGeneratingObject, err.Error())
+		buildContoursExportResponse(writer, request, http.StatusBadRequest, contoursExportResponse)
+		return
+	}
+
+	// distinct actualities, origins and attributions of all tiles
+	var actualities, origins, attributions []string
+	for _, tile := range tiles {
+		if !slices.Contains(actualities, tile.Actuality) {
+			actualities = append(actualities, tile.Actuality)
+		}
+		if slices.Contains(origins, tile.Source) {
+			continue
+		}
+		origins = append(origins, tile.Source)
+		attribution := "unknown"
+		resource, err := getElevationResource(tile.Source)
+		if err != nil {
+			slog.ErrorContext(request.Context(), "contours export request: error getting elevation resource", "error", err, "source", tile.Source)
+		} else {
+			attribution = resource.Attribution
+		}
+		attributions = append(attributions, attribution)
+	}
+	slices.Sort(actualities)
+	areaIndex := tiles[0].Index + "-" + tiles[len(tiles)-1].Index
+	variant := strconv.FormatFloat(contoursExportRequest.Attributes.Equidistance, 'f', -1, 64) + attribute.unit
+	contoursExportResponse.Attributes.Filename = buildObjectFilename(areaIndex, "contours", variant, actualities[len(actualities)-1], "gpkg")
+	contoursExportResponse.Attributes.DataFormat = "gpkg"
+	contoursExportResponse.Attributes.Actuality = strings.Join(actualities, ", ")
+	contoursExportResponse.Attributes.Origin = strings.Join(origins, ", ")
+	contoursExportResponse.Attributes.Attribution = strings.Join(attributions, "; ")
+
+	// deliver GeoPackage (upload to S3 bucket or in response)
+	if contoursExportRequest.Attributes.Upload {
+		key := contoursExportResponse.Attributes.Filename
+		if requestID := getRequestID(request.Context()); requestID != "" {
+			key = requestID + "/" + key
+		}
+		downloadURL, err := uploadToS3(request.Context(), key, "application/geopackage+sqlite3", data)
+		if err != nil {
+			slog.WarnContext(request.Context(), "contours export request: error uploading GeoPackage", "error", err, "ID", contoursExportRequest.ID)
+			contoursExportResponse.Attributes.Error = newErrorObject(EndpointContoursExport, ReasonUploadFailed, err.Error())
+			buildContoursExportResponse(writer, request, http.StatusBadGateway, contoursExportResponse)
+			return
+		}
+		contoursExportResponse.Attributes.DownloadURL = downloadURL
+	} else {
+		contoursExportResponse.Attributes.Data = data
+	}
+	addUsage(request.Context(), 0, len(tiles))
+
+	// success response
+	contoursExportResponse.Attributes.IsError = false
+	buildContoursExportResponse(writer, request, http.StatusOK, contoursExportResponse)
+}
+
+/*
+verifyContoursExportRequestData verifies 'contours export' request data.
+*/
+func verifyContoursExportRequestData(request *http.Request, contoursExportRequest ContoursExportRequest) error {
+	// verify HTTP header
+	contentType := request.Header.Get("Content-Type")
+	if !strings.HasPrefix(strings.ToLower(contentType), "application/json") {
+		return fmt.Errorf("unexpected or missing HTTP header field Content-Type, value = [%s], expected 'application/json'", contentType)
+	}
+
+	// verify Type
+	if contoursExportRequest.Type != TypeContoursExportRequest {
+		return fmt.Errorf("unexpected request Type [%v]", contoursExportRequest.Type)
+	}
+
+	// verify ID
+	if len(contoursExportRequest.ID) > 1024 {
+		return errors.New("ID must be 0-1024 characters long")
+	}
+
+	// verify zone for Germany (Zone: 32 or 33)
+	if contoursExportRequest.Attributes.Zone != 0 {
+		if contoursExportRequest.Attributes.Zone < 32 || contoursExportRequest.Attributes.Zone > 33 {
+			return errors.New("invalid zone for Germany")
+		}
+	}
+
+	// verify area or administrative area (exactly one of them)
+	switch {
+	case contoursExportRequest.Attributes.Area != nil && contoursExportRequest.Attributes.AdministrativeArea != "":
+		return errors.New("either area or administrative area must be set, not both")
+	case contoursExportRequest.Attributes.Area != nil:
+		err := verifyContoursArea(contoursExportRequest.Attributes.Zone, contoursExportRequest.Attributes.Area)
+		if err != nil {
+			return err
+		}
+	case contoursExportRequest.Attributes.AdministrativeArea == "":
+		return errors.New("area or administrative area must be set")
+	}
+
+	// verify unit of equidistance and elevation attribute
+	switch contoursExportRequest.Attributes.Unit {
+	case "", "m", "ft":
+	default:
+		return errors.New("unit must be 'm' or 'ft'")
+	}
+
+	// verify equidistance (in meters)
+	attribute := newContourAttribute(contoursExportRequest.Attributes.AttributeName, contoursExportRequest.Attributes.Unit, contoursExportRequest.Attributes.Precision)
+	equidistance := contoursExportRequest.Attributes.Equidistance * attribute.metersPerUnit()
+	if equidistance < 0.2 || equidistance > 25.0 {
+		return errors.New("equidistance must be between 0.2 and 25.0 meters (0.7 and 82.0 feet)")
+	}
+
+	// verify name of elevation attribute (e.g. Hoehe, ELEV)
+	if !contourAttributeNameRegexp.MatchString(attribute.name) || strings.EqualFold(attribute.name, "ID") {
+		return errors.New("attribute name must be 1-32 characters (letters, digits, underscore, not starting with digit, not 'ID')")
+	}
+
+	// verify precision (decimal places)
+	if contoursExportRequest.Attributes.Precision != nil {
+		if *contoursExportRequest.Attributes.Precision < 0 || *contoursExportRequest.Attributes.Precision > 3 {
+			return errors.New("precision must be between 0 and 3 decimal places")
+		}
+	}
+
+	// verify vertical offset
+	err := verifyVerticalOffset(contoursExportRequest.Attributes.VerticalOffset)
+	if err != nil {
+		return err
+	}
+
+	// verify upload
+	if contoursExportRequest.Attributes.Upload && getProgConfig().Export.S3.Endpoint == "" {
+		return errors.New("upload not supported (no storage configured)")
+	}
+
+	return nil
+}
+
+/*
+generateContoursGeoPackageForArea builds one GeoPackage with the contours of an area covered by many tiles.
+Same strategy as generateContourObjectForArea (mosaic, contours once, clip), but all intermediate files are
+written to the temp directory instead of memory (/vsimem), because the contours of several hundred tiles
+can easily exceed several hundred megabytes. The result is not cached.
+*/
+func generateContoursGeoPackageForArea(ctx context.Context, tiles []TileMetadata, zone int, clipWKT string, equidistance float64, isLonLat bool,
+	verticalOffset float64, attribute contourAttribute) ([]byte, error) {
+	// run operations in temp directory
+	tempDir, err := createTempDirectory("contours-export")
+	if err != nil {
+		return nil, fmt.Errorf("error [%w] at createTempDirectory()", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(tempDir)
+	}()
+	filenameVRT := filepath.Join(tempDir, "mosaic.vrt")
+	filenameUtmGeoJSON := filepath.Join(tempDir, "mosaic.utm.geojson")
+	filenameGeoPackage := filepath.Join(tempDir, "contours.gpkg")
+
+	// gdalbuildvrt
+	tilePaths := make([]string, 0, len(tiles))
+	for _, tile := range tiles {
+		tilePaths = append(tilePaths, tile.Path)
+	}
+	err = dtm.BuildVRT(ctx, tilePaths, filenameVRT, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error [%w] at dtm.BuildVRT()", err)
+	}
+
+	equidistanceString := fmt.Sprintf("%.2f", equidistance)
+	nameOutputLayer := fmt.Sprintf("Höhenlinien %s %s", equidistanceString, attribute.layerUnitName())
+
+	// gdal_contour (once for mosaic, lines are continuous across tile boundaries)
+	err = dtm.Contour(ctx, filenameVRT, filenameUtmGeoJSON, nameOutputLayer, attribute.dtmAttribute(), equidistance, verticalOffset)
+	if err != nil {
+		return nil, fmt.Errorf("error [%w] at dtm.Contour()", err)
+	}
+
+	// ogr2ogr: clip to area (clip geometry in source SRS) and convert to GeoPackage
+	switches := []string{"-f", "GPKG", "-clipsrc", clipWKT, "-nln", "contours"}
+	if isLonLat {
+		switches = append(switches, "-s_srs", fmt.Sprintf("EPSG:258%d", zone), "-t_srs", "EPSG:4326")
+	}
+	err = dtm.VectorTranslate(ctx, filenameUtmGeoJSON, filenameGeoPackage, switches)
+	if err != nil {
+		return nil, fmt.Errorf("error [%w] at dtm.VectorTranslate()", err)
+	}
+
+	// read result file
+	data, err := os.ReadFile(filenameGeoPackage)
+	if err != nil {
+		return nil, fmt.Errorf("error [%w] at os.ReadFile(), file: %s", err, filenameGeoPackage)
+	}
+
+	return data, nil
+}
+
+/*
+buildContoursExportResponse builds HTTP responses with specified status and body.
+*/
+func buildContoursExportResponse(writer http.ResponseWriter, request *http.Request, httpStatus int, contoursExportResponse ContoursExportResponse) {
+	// response metadata (versions, processing duration, cache hit)
+	contoursExportResponse.Meta = newResponseMeta(request.Context())
+
+	// encode (streaming) and send response
+	streamJSONResponse(writer, request, httpStatus, contoursExportResponse, false)
+}
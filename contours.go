@@ -1,18 +1,19 @@
 package main
 
 import (
-	"bytes"
-	"compress/gzip"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
-	"os"
-	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"sync/atomic"
+
+	"klaus/elevation/dtm-elevation-service/pkg/dtm"
 )
 
 /*
@@ -34,18 +35,14 @@ func contoursRequest(writer http.ResponseWriter, request *http.Request) {
 		// check specifically for the error returned by MaxBytesReader
 		var maxBytesErr *http.MaxBytesError
 		if errors.As(err, &maxBytesErr) {
-			slog.Warn("contours request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
-			contoursResponse.Attributes.Error.Code = "4000"
-			contoursResponse.Attributes.Error.Title = "request body too large"
-			contoursResponse.Attributes.Error.Detail = fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit)
-			buildContoursResponse(writer, http.StatusRequestEntityTooLarge, contoursResponse)
+			slog.WarnContext(request.Context(), "contours request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
+			contoursResponse.Attributes.Error = newErrorObject(EndpointContours, ReasonRequestBodyTooLarge, fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit))
+			buildContoursResponse(writer, request, http.StatusRequestEntityTooLarge, contoursResponse)
 		} else {
 			// handle other read errors
-			slog.Warn("contours request: error reading request body", "error", err, "ID", "unknown")
-			contoursResponse.Attributes.Error.Code = "4020"
-			contoursResponse.Attributes.Error.Title = "error reading request body"
-			contoursResponse.Attributes.Error.Detail = err.Error()
-			buildContoursResponse(writer, http.StatusBadRequest, contoursResponse)
+			slog.WarnContext(request.Context(), "contours request: error reading request body", "error", err, "ID", "unknown")
+			contoursResponse.Attributes.Error = newErrorObject(EndpointContours, ReasonReadingRequestBody, err.Error())
+			buildContoursResponse(writer, request, http.StatusBadRequest, contoursResponse)
 		}
 		return
 	}
@@ -54,14 +51,39 @@ func contoursRequest(writer http.ResponseWriter, request *http.Request) {
 	contoursRequest := ContoursRequest{}
 	err = json.Unmarshal(bodyData, &contoursRequest)
 	if err != nil {
-		slog.Warn("contours request: error unmarshaling request body", "error", err, "ID", "unknown")
-		contoursResponse.Attributes.Error.Code = "4040"
-		contoursResponse.Attributes.Error.Title = "error unmarshaling request body"
-		contoursResponse.Attributes.Error.Detail = err.Error()
-		buildContoursResponse(writer, http.StatusBadRequest, contoursResponse)
+		slog.WarnContext(request.Context(), "contours request: error unmarshaling request body", "error", err, "ID", "unknown")
+		contoursResponse.Attributes.Error = newErrorObject(EndpointContours, ReasonUnmarshalingRequestBody, err.Error())
+		buildContoursResponse(writer, request, http.StatusBadRequest, contoursResponse)
 		return
 	}
 
+	// resolve place name (geocoding) into lon/lat coordinates
+	if contoursRequest.Attributes.Place != "" {
+		place, err := geocodePlace(request.Context(), contoursRequest.Attributes.Place)
+		if err != nil {
+			slog.WarnContext(request.Context(), "contours request: error geocoding place", "error", err, "place", contoursRequest.Attributes.Place, "ID", contoursRequest.ID)
+			contoursResponse.ID = contoursRequest.ID
+			contoursResponse.Attributes.Place = contoursRequest.Attributes.Place
+			if errors.Is(err, ErrPlaceNotFound) {
+				contoursResponse.Attributes.Error = newErrorObject(EndpointContours, ReasonPlaceNotFound, err.Error())
+				buildContoursResponse(writer, request, http.StatusNotFound, contoursResponse)
+				return
+			}
+			if errors.Is(err, ErrGeocoderUnavailable) {
+				contoursResponse.Attributes.Error = newErrorObject(EndpointContours, ReasonGeocoderUnavailable, err.Error())
+				buildContoursResponse(writer, request, http.StatusServiceUnavailable, contoursResponse)
+				return
+			}
+			contoursResponse.Attributes.Error = newErrorObject(EndpointContours, ReasonVerifyingRequestData, err.Error())
+			buildContoursResponse(writer, request, http.StatusBadRequest, contoursResponse)
+			return
+		}
+		contoursRequest.Attributes.Zone = 0
+		contoursRequest.Attributes.Longitude = place.Longitude
+		contoursRequest.Attributes.Latitude = place.Latitude
+		contoursResponse.Attributes.PlaceName = place.DisplayName
+	}
+
 	// copy request parameters into response
 	contoursResponse.ID = contoursRequest.ID
 	contoursResponse.Attributes.Zone = contoursRequest.Attributes.Zone
@@ -69,16 +91,28 @@ func contoursRequest(writer http.ResponseWriter, request *http.Request) {
 	contoursResponse.Attributes.Northing = contoursRequest.Attributes.Northing
 	contoursResponse.Attributes.Longitude = contoursRequest.Attributes.Longitude
 	contoursResponse.Attributes.Latitude = contoursRequest.Attributes.Latitude
+	contoursResponse.Attributes.Place = contoursRequest.Attributes.Place
 	contoursResponse.Attributes.Equidistance = contoursRequest.Attributes.Equidistance
+	contoursResponse.Attributes.VerticalOffset = contoursRequest.Attributes.VerticalOffset
+	contoursResponse.Attributes.AttributeName = contoursRequest.Attributes.AttributeName
+	contoursResponse.Attributes.Precision = contoursRequest.Attributes.Precision
+	contoursResponse.Attributes.Unit = contoursRequest.Attributes.Unit
+	contoursResponse.Attributes.Area = contoursRequest.Attributes.Area
+	contoursResponse.Attributes.ClipPolygon = contoursRequest.Attributes.ClipPolygon
+	contoursResponse.Attributes.AdministrativeArea = contoursRequest.Attributes.AdministrativeArea
 
 	// verify request data
 	err = verifyContoursRequestData(request, contoursRequest)
 	if err != nil {
-		slog.Warn("contours request: error verifying request data", "error", err, "ID", contoursRequest.ID)
-		contoursResponse.Attributes.Error.Code = "4060"
-		contoursResponse.Attributes.Error.Title = "error verifying request data"
-		contoursResponse.Attributes.Error.Detail = err.Error()
-		buildContoursResponse(writer, http.StatusBadRequest, contoursResponse)
+		slog.WarnContext(request.Context(), "contours request: error verifying request data", "error", err, "ID", contoursRequest.ID)
+		contoursResponse.Attributes.Error = newErrorObject(EndpointContours, ReasonVerifyingRequestData, err.Error())
+		buildContoursResponse(writer, request, http.StatusBadRequest, contoursResponse)
+		return
+	}
+
+	// seamless contours for area (bounding box, polygon, clip polygon or administrative area) spanning several tiles
+	if contoursRequest.Attributes.Area != nil || contoursRequest.Attributes.ClipPolygon != nil || contoursRequest.Attributes.AdministrativeArea != "" {
+		contoursAreaRequest(writer, request, contoursRequest, contoursResponse)
 		return
 	}
 
@@ -100,12 +134,20 @@ func contoursRequest(writer http.ResponseWriter, request *http.Request) {
 		// get all tiles (metadata) for given UTM coordinates
 		tiles, err = getAllTilesUTM(zone, easting, northing)
 		if err != nil {
-			slog.Warn("contours request: error getting GeoTIFF tile for UTM coordinates", "error", err,
+			slog.WarnContext(request.Context(), "contours request: error getting GeoTIFF tile for UTM coordinates", "error", err,
 				"easting", easting, "northing", northing, "zone", zone, "ID", contoursRequest.ID)
-			contoursResponse.Attributes.Error.Code = "4080"
-			contoursResponse.Attributes.Error.Title = "getting GeoTIFF tile for UTM coordinates"
-			contoursResponse.Attributes.Error.Detail = err.Error()
-			buildContoursResponse(writer, http.StatusBadRequest, contoursResponse)
+			if errors.Is(err, ErrOutsideCoverage) {
+				contoursResponse.Attributes.Error = newErrorObject(EndpointContours, ReasonOutsideCoverage, describeOutsideCoverage(err))
+				buildContoursResponse(writer, request, http.StatusNotFound, contoursResponse)
+				return
+			}
+			if errors.Is(err, ErrSourceUnavailable) {
+				contoursResponse.Attributes.Error = newErrorObject(EndpointContours, ReasonSourceUnavailable, err.Error())
+				buildContoursResponse(writer, request, http.StatusServiceUnavailable, contoursResponse)
+				return
+			}
+			contoursResponse.Attributes.Error = newErrorObject(EndpointContours, ReasonGettingTileUTM, err.Error())
+			buildContoursResponse(writer, request, http.StatusBadRequest, contoursResponse)
 			return
 		}
 	} else {
@@ -118,34 +160,69 @@ func contoursRequest(writer http.ResponseWriter, request *http.Request) {
 		tiles, err = getAllTilesLonLat(longitude, latitude)
 		if err != nil {
 			err = fmt.Errorf("error [%w] getting tile for coordinates lon: %.8f, lat: %.8f", err, longitude, latitude)
-			slog.Warn("contours request: error getting GeoTIFF tile for lon/lat coordinates", "error", err,
+			slog.WarnContext(request.Context(), "contours request: error getting GeoTIFF tile for lon/lat coordinates", "error", err,
 				"longitude", longitude, "latitude", latitude, "ID", contoursRequest.ID)
-			contoursResponse.Attributes.Error.Code = "4100"
-			contoursResponse.Attributes.Error.Title = "getting GeoTIFF tile for lon/lat coordinates"
-			contoursResponse.Attributes.Error.Detail = err.Error()
-			buildContoursResponse(writer, http.StatusBadRequest, contoursResponse)
+			if errors.Is(err, ErrOutsideCoverage) {
+				contoursResponse.Attributes.Error = newErrorObject(EndpointContours, ReasonOutsideCoverage, describeOutsideCoverage(err))
+				buildContoursResponse(writer, request, http.StatusNotFound, contoursResponse)
+				return
+			}
+			if errors.Is(err, ErrSourceUnavailable) {
+				contoursResponse.Attributes.Error = newErrorObject(EndpointContours, ReasonSourceUnavailableLonLat, err.Error())
+				buildContoursResponse(writer, request, http.StatusServiceUnavailable, contoursResponse)
+				return
+			}
+			contoursResponse.Attributes.Error = newErrorObject(EndpointContours, ReasonGettingTileLonLat, err.Error())
+			buildContoursResponse(writer, request, http.StatusBadRequest, contoursResponse)
 			return
 		}
 	}
 
+	// conditional request: unchanged response (same parameters, same tiles) needs no processing
+	etag := buildETag("contours", contoursRequest, tiles)
+	if checkNotModified(writer, request, etag) {
+		return
+	}
+
 	// build contours for all existing tiles
 	equidistance := contoursRequest.Attributes.Equidistance
-	for _, tile := range tiles {
-		contour, err := generateContourObjectForTile(tile, equidistance, isLonLat)
-		if err != nil {
-			slog.Warn("contours request: error generating contours object for tile", "error", err, "ID", contoursRequest.ID)
-			contoursResponse.Attributes.Error.Code = "4120"
-			contoursResponse.Attributes.Error.Title = "error generating contours object for tile"
-			contoursResponse.Attributes.Error.Detail = err.Error()
-			buildContoursResponse(writer, http.StatusBadRequest, contoursResponse)
+	verticalOffset := contoursRequest.Attributes.VerticalOffset
+	attribute := newContourAttribute(contoursRequest.Attributes.AttributeName, contoursRequest.Attributes.Unit, contoursRequest.Attributes.Precision)
+	contours, tileErrors, err := generateObjectsForTiles(request.Context(), tiles, func(tile TileMetadata) (Contour, error) {
+		return generateContourObjectForTile(request.Context(), tile, equidistance, isLonLat, verticalOffset, attribute)
+	})
+	if err != nil {
+		slog.WarnContext(request.Context(), "contours request: error generating contours object for tile", "error", err, "ID", contoursRequest.ID)
+		if errors.Is(err, context.Canceled) {
+			// client disconnected, processing aborted, no response required
 			return
 		}
-		contoursResponse.Attributes.Contours = append(contoursResponse.Attributes.Contours, contour)
+		if errors.Is(err, ErrServerBusy) {
+			contoursResponse.Attributes.Error = newErrorObject(EndpointContours, ReasonServerBusy, err.Error())
+			writer.Header().Set("Retry-After", strconv.Itoa(getProgConfig().GDALJobQueue.RetryAfter))
+			buildContoursResponse(writer, request, http.StatusTooManyRequests, contoursResponse)
+			return
+		}
+		contoursResponse.Attributes.Error = newErrorObject(EndpointContours, ReasonGeneratingObject, err.Error())
+		buildContoursResponse(writer, request, http.StatusBadRequest, contoursResponse)
+		return
+	}
+	contoursResponse.Attributes.Contours = contours
+	contoursResponse.Attributes.TileErrors = tileErrors
+	if len(tileErrors) > 0 {
+		contoursResponse.Attributes.Warnings = append(contoursResponse.Attributes.Warnings,
+			fmt.Sprintf("%d of %d tiles could not be processed (see TileErrors)", len(tileErrors), len(tiles)))
+		slog.WarnContext(request.Context(), "contours request: partial success, tiles could not be processed", "tile errors", tileErrors, "ID", contoursRequest.ID)
 	}
+	addUsage(request.Context(), 0, len(contours))
 
 	// success response
 	contoursResponse.Attributes.IsError = false
-	buildContoursResponse(writer, http.StatusOK, contoursResponse)
+	if len(tileErrors) == 0 {
+		// partial responses are not cacheable
+		setETag(writer, etag)
+	}
+	buildContoursResponse(writer, request, http.StatusOK, contoursResponse)
 }
 
 /*
@@ -166,18 +243,6 @@ func verifyContoursRequestData(request *http.Request, contoursRequest ContoursRe
 		return fmt.Errorf("unexpected or missing HTTP header field Content-Type, value = [%s], expected 'application/json'", contentType)
 	}
 
-	// verify HTTP header
-	accept := request.Header.Get("Accept")
-	isAcceptValid := true
-	switch {
-	case strings.HasPrefix(strings.ToLower(accept), "application/json"):
-	default:
-		isAcceptValid = false
-	}
-	if !isAcceptValid {
-		return fmt.Errorf("unexpected or missing HTTP header field Accept, value = [%s], expected 'application/json'", accept)
-	}
-
 	// verify Type
 	if contoursRequest.Type != TypeContoursRequest {
 		return fmt.Errorf("unexpected request Type [%v]", contoursRequest.Type)
@@ -188,8 +253,22 @@ func verifyContoursRequestData(request *http.Request, contoursRequest ContoursRe
 		return errors.New("ID must be 0-1024 characters long")
 	}
 
-	// verify coordinates (either utm or lon/lat coordinates must be set)
-	if contoursRequest.Attributes.Zone == 0 && contoursRequest.Attributes.Longitude == 0 {
+	// verify area (bounding box, polygon or clip polygon, UTM coordinates if zone is set)
+	if contoursRequest.Attributes.Area != nil && contoursRequest.Attributes.ClipPolygon != nil {
+		return errors.New("either area or clip polygon can be set")
+	}
+	if contoursRequest.Attributes.Area != nil {
+		err := verifyContoursArea(contoursRequest.Attributes.Zone, contoursRequest.Attributes.Area)
+		if err != nil {
+			return err
+		}
+	} else if contoursRequest.Attributes.ClipPolygon != nil {
+		err := verifyClipPolygon(contoursRequest.Attributes.Zone, contoursRequest.Attributes.ClipPolygon)
+		if err != nil {
+			return err
+		}
+	} else if contoursRequest.Attributes.AdministrativeArea == "" && contoursRequest.Attributes.Zone == 0 && contoursRequest.Attributes.Longitude == 0 {
+		// verify coordinates (either utm or lon/lat coordinates must be set)
 		return errors.New("either utm or lon/lat coordinates must be set")
 	}
 
@@ -214,9 +293,36 @@ func verifyContoursRequestData(request *http.Request, contoursRequest ContoursRe
 		}
 	}
 
-	// verify equidistance
-	if contoursRequest.Attributes.Equidistance < 0.2 || contoursRequest.Attributes.Equidistance > 25.0 {
-		return errors.New("equidistance must be between 0.2 and 25.0 meters")
+	// verify unit of equidistance and elevation attribute
+	switch contoursRequest.Attributes.Unit {
+	case "", "m", "ft":
+	default:
+		return errors.New("unit must be 'm' or 'ft'")
+	}
+
+	// verify equidistance (in meters)
+	attribute := newContourAttribute(contoursRequest.Attributes.AttributeName, contoursRequest.Attributes.Unit, contoursRequest.Attributes.Precision)
+	equidistance := contoursRequest.Attributes.Equidistance * attribute.metersPerUnit()
+	if equidistance < 0.2 || equidistance > 25.0 {
+		return errors.New("equidistance must be between 0.2 and 25.0 meters (0.7 and 82.0 feet)")
+	}
+
+	// verify name of elevation attribute (e.g. Hoehe, ELEV)
+	if !contourAttributeNameRegexp.MatchString(attribute.name) || strings.EqualFold(attribute.name, "ID") {
+		return errors.New("attribute name must be 1-32 characters (letters, digits, underscore, not starting with digit, not 'ID')")
+	}
+
+	// verify precision (decimal places)
+	if contoursRequest.Attributes.Precision != nil {
+		if *contoursRequest.Attributes.Precision < 0 || *contoursRequest.Attributes.Precision > 3 {
+			return errors.New("precision must be between 0 and 3 decimal places")
+		}
+	}
+
+	// verify vertical offset
+	err := verifyVerticalOffset(contoursRequest.Attributes.VerticalOffset)
+	if err != nil {
+		return err
 	}
 
 	return nil
@@ -227,56 +333,66 @@ buildContoursResponse builds HTTP responses with specified status and body.
 It sets the Content-Type and Content-Length headers before writing the response body.
 This function is used to construct consistent HTTP responses throughout the application.
 */
-func buildContoursResponse(writer http.ResponseWriter, httpStatus int, contoursResponse ContoursResponse) {
-	// log limit length of body (e.g., the contours objects as part of the body can be very large)
-	maxBodyLength := 1024
-
-	// CORS: allow requests from any origin
-	writer.Header().Set("Access-Control-Allow-Origin", "*")
-	// CORS: allowed methods
-	writer.Header().Set("Access-Control-Allow-Methods", "POST")
-	// CORS: allowed headers
-	writer.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
-	// marshal response
-	body, err := json.MarshalIndent(contoursResponse, "", "  ")
-	if err != nil {
-		slog.Error("error marshaling point response", "error", err, "body length", len(body),
-			fmt.Sprintf("body (limited to first %d bytes)", maxBodyLength), body[:maxBodyLength])
+func buildContoursResponse(writer http.ResponseWriter, request *http.Request, httpStatus int, contoursResponse ContoursResponse) {
+	// response metadata (versions, processing duration, cache hit)
+	contoursResponse.Meta = newResponseMeta(request.Context())
 
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
+	// encode (streaming) and send response
+	streamJSONResponse(writer, request, httpStatus, contoursResponse, false)
+}
 
-	// gzip response body
-	var bytesBuffer bytes.Buffer
-	gz := gzip.NewWriter(&bytesBuffer)
+// contourAttributeNameRegexp defines valid names of the elevation attribute (e.g. Hoehe, ELEV)
+var contourAttributeNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,31}$`)
 
-	_, err = gz.Write(body)
-	if err != nil {
-		slog.Error("error [%v] at gz.Write()", "error", err)
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
+// contourAttribute represents the elevation attribute of contour lines (name, unit and rounding).
+type contourAttribute struct {
+	name      string // e.g. Hoehe, ELEV
+	unit      string // m or ft
+	precision int    // decimal places (-1 = not rounded)
+}
+
+/*
+newContourAttribute creates the elevation attribute of contour lines from request data (defaults: Hoehe, m, not rounded).
+*/
+func newContourAttribute(name string, unit string, precision *int) contourAttribute {
+	attribute := contourAttribute{name: name, unit: unit, precision: -1}
+	if attribute.name == "" {
+		attribute.name = "Hoehe"
+	}
+	if attribute.unit == "" {
+		attribute.unit = "m"
 	}
+	if precision != nil {
+		attribute.precision = *precision
+	}
+	return attribute
+}
 
-	err = gz.Close()
-	if err != nil {
-		slog.Error("error [%v] at gz.Close()", "error", err)
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
+/*
+metersPerUnit returns the length of the attribute unit in meters.
+*/
+func (attribute contourAttribute) metersPerUnit() float64 {
+	if attribute.unit == "ft" {
+		return 0.3048
 	}
+	return 1.0
+}
 
-	// set headers
-	writer.Header().Set("Content-Encoding", "gzip")
-	writer.Header().Set("Content-Type", JSONAPIMediaType)
-	writer.WriteHeader(httpStatus)
+/*
+dtmAttribute returns the elevation attribute for contour generation (see dtm.Contour).
+*/
+func (attribute contourAttribute) dtmAttribute() dtm.ContourAttribute {
+	return dtm.ContourAttribute{Name: attribute.name, MetersPerUnit: attribute.metersPerUnit(), Precision: attribute.precision}
+}
 
-	// send response
-	_, err = writer.Write(bytesBuffer.Bytes())
-	if err != nil {
-		slog.Error("error writing HTTP response body", "error", err, "body length", len(body),
-			fmt.Sprintf("body (limited to first %d bytes)", maxBodyLength), body[:maxBodyLength])
+/*
+layerUnitName returns the (german) name of the attribute unit for layer names.
+*/
+func (attribute contourAttribute) layerUnitName() string {
+	if attribute.unit == "ft" {
+		return "Fuß"
 	}
+	return "Meter"
 }
 
 /*
@@ -285,33 +401,35 @@ Strategy to avoid artefact:
 - generate contours in the source SRS
 - convert generated contours to the target SRS
 */
-func generateContourObjectForTile(tile TileMetadata, equidistance float64, isLonLat bool) (Contour, error) {
+func generateContourObjectForTile(ctx context.Context, tile TileMetadata, equidistance float64, isLonLat bool, verticalOffset float64,
+	attribute contourAttribute) (Contour, error) {
 	var contour Contour
 
-	// run operations in temp directory
-	tempDir, err := os.MkdirTemp("", "dtm-elevation-service-contours-")
-	if err != nil {
-		return contour, fmt.Errorf("error [%w] at os.MkdirTemp()", err)
+	// lookup response cache
+	cacheKey := buildResponseCacheKey("contours", tile.Index, tile.Actuality, equidistance, isLonLat, verticalOffset,
+		attribute.name, attribute.unit, attribute.precision)
+	cached, found := responseCache.Get(cacheKey)
+	recordCacheLookup(ctx, found)
+	if found {
+		return cached.(Contour), nil
 	}
-	defer func() {
-		_ = os.RemoveAll(tempDir)
-	}()
 
+	// run operations in memory (/vsimem)
+	vsimemPrefix := dtm.NewVSIMemPrefix("contours")
 	filenameTif := tile.Path
-	filenameUtmGeoJSON := filepath.Join(tempDir, tile.Index+".utm.geojson")
-	filenameLonLatGeoJSON := filepath.Join(tempDir, tile.Index+".lonlat.geojson")
+	filenameUtmGeoJSON := vsimemPrefix + tile.Index + ".utm.geojson"
+	filenameLonLatGeoJSON := vsimemPrefix + tile.Index + ".lonlat.geojson"
+	defer removeVSIMemFiles(filenameUtmGeoJSON, filenameLonLatGeoJSON)
 
 	equidistanceString := fmt.Sprintf("%.2f", equidistance)
-	nameOutputLayer := fmt.Sprintf("Höhenlinien %s Meter für Kachel %s", equidistanceString, tile.Index)
+	nameOutputLayer := fmt.Sprintf("Höhenlinien %s %s für Kachel %s", equidistanceString, attribute.layerUnitName(), tile.Index)
 
 	// gdal_contour
-	commandExitStatus, commandOutput, err := runCommand("gdal_contour", []string{"-f", "GeoJSON",
-		"-i", equidistanceString, "-nln", nameOutputLayer, "-a", "Hoehe", filenameTif, filenameUtmGeoJSON})
+	// e.g. gdal_contour -f GeoJSON -i 10.00 -nln "Höhenlinien ..." -a Hoehe dgm1_32_409_5790_1_nw_2024.tif 32_409_5790.utm.geojson
+	err := dtm.Contour(ctx, filenameTif, filenameUtmGeoJSON, nameOutputLayer, attribute.dtmAttribute(), equidistance, verticalOffset)
 	if err != nil {
-		return contour, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
+		return contour, fmt.Errorf("error [%w] at dtm.Contour()", err)
 	}
-	// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-	// fmt.Printf("commandOutput: %s\n", commandOutput)
 
 	// derive zone from tile index (e.g. 32_383_5802)
 	parts := strings.Split(tile.Index, "_")
@@ -328,30 +446,28 @@ func generateContourObjectForTile(tile TileMetadata, equidistance float64, isLon
 
 	if isLonLat {
 		// ogr2ogr
-		commandExitStatus, commandOutput, err = runCommand("ogr2ogr", []string{"-f", "GeoJSON",
-			"-s_srs", epsgCode, "-t_srs", "EPSG:4326", filenameLonLatGeoJSON, filenameUtmGeoJSON})
+		err = dtm.VectorTranslate(ctx, filenameUtmGeoJSON, filenameLonLatGeoJSON, []string{"-f", "GeoJSON",
+			"-s_srs", epsgCode, "-t_srs", "EPSG:4326"})
 		if err != nil {
-			return contour, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
-
+			return contour, fmt.Errorf("error [%w] at dtm.VectorTranslate()", err)
 		}
-		// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-		// fmt.Printf("commandOutput: %s\n", commandOutput)
 	}
 
 	// read result file
 	var data []byte
 	if isLonLat {
-		data, err = os.ReadFile(filenameLonLatGeoJSON)
+		data, err = dtm.ReadVSIMemFile(filenameLonLatGeoJSON)
 	} else {
-		data, err = os.ReadFile(filenameUtmGeoJSON)
+		data, err = dtm.ReadVSIMemFile(filenameUtmGeoJSON)
 	}
 	if err != nil {
-		return contour, fmt.Errorf("error [%w] at os.ReadFile()", err)
+		return contour, fmt.Errorf("error [%w] at dtm.ReadVSIMemFile()", err)
 	}
 
 	// set contour return structure
 	contour.Data = data
 	contour.DataFormat = "geojson"
+	contour.Filename = buildObjectFilename(tile.Index, "contours", strconv.FormatFloat(equidistance, 'f', -1, 64)+attribute.unit, tile.Actuality, contour.DataFormat)
 	contour.Actuality = tile.Actuality
 	contour.Origin = tile.Source
 	contour.TileIndex = tile.Index
@@ -360,45 +476,38 @@ func generateContourObjectForTile(tile TileMetadata, equidistance float64, isLon
 	attribution := "unknown"
 	resource, err := getElevationResource(tile.Source)
 	if err != nil {
-		slog.Error("contours request: error getting elevation resource", "error", err, "source", tile.Source)
+		slog.ErrorContext(ctx, "contours request: error getting elevation resource", "error", err, "source", tile.Source)
 	} else {
 		attribution = resource.Attribution
 	}
 	contour.Attribution = attribution
 
+	// add to response cache
+	responseCache.Add(cacheKey, contour, len(contour.Data))
+
 	return contour, nil
 }
 
 /*
 generateContourObjectForTile2 builds contour object for given tile index.
 */
-func generateContourObjectForTile2(tile TileMetadata, equidistance float64, isLonLat bool) (Contour, error) { //nolint:unused
+func generateContourObjectForTile2(ctx context.Context, tile TileMetadata, equidistance float64, isLonLat bool) (Contour, error) { //nolint:unused
 	var contour Contour
-	var commandExitStatus int
-	var commandOutput []byte
 	var err error
 
-	// run operations in temp directory
-	tempDir, err := os.MkdirTemp("", "dtm-elevation-service-contours-")
-	if err != nil {
-		return contour, fmt.Errorf("error [%w] at os.MkdirTemp()", err)
-	}
-	defer func() {
-		_ = os.RemoveAll(tempDir)
-	}()
-
+	// run operations in memory (/vsimem)
+	vsimemPrefix := dtm.NewVSIMemPrefix("contours")
 	filenameTif := tile.Path
-	filenameWgs84Tif := filepath.Join(tempDir, tile.Index+".wgs84.tif")
-	filenameGeoJSON := filepath.Join(tempDir, tile.Index+".geojson")
+	filenameWgs84Tif := vsimemPrefix + tile.Index + ".wgs84.tif"
+	filenameGeoJSON := vsimemPrefix + tile.Index + ".geojson"
+	defer removeVSIMemFiles(filenameWgs84Tif, filenameGeoJSON)
 
 	if isLonLat {
 		// reprojection with gdalwarp
-		commandExitStatus, commandOutput, err = runCommand("gdalwarp", []string{"-t_srs", "EPSG:4326", filenameTif, filenameWgs84Tif})
+		err = dtm.Warp(ctx, filenameTif, filenameWgs84Tif, []string{"-of", "GTiff", "-t_srs", "EPSG:4326"})
 		if err != nil {
-			return contour, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
+			return contour, fmt.Errorf("error [%w] at dtm.Warp()", err)
 		}
-		// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-		// fmt.Printf("commandOutput: %s\n", commandOutput)
 		filenameTif = filenameWgs84Tif
 	}
 
@@ -406,18 +515,15 @@ func generateContourObjectForTile2(tile TileMetadata, equidistance float64, isLo
 	nameOutputLayer := fmt.Sprintf("Höhenlinien %s Meter für Kachel %s", equidistanceString, tile.Index)
 
 	// gdal_contour (based on srs from tif file)
-	commandExitStatus, commandOutput, err = runCommand("gdal_contour", []string{"-f", "GeoJSON",
-		"-i", equidistanceString, "-nln", nameOutputLayer, "-a", "Hoehe", filenameTif, filenameGeoJSON})
+	err = dtm.Contour(ctx, filenameTif, filenameGeoJSON, nameOutputLayer, newContourAttribute("", "", nil).dtmAttribute(), equidistance, 0)
 	if err != nil {
-		return contour, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
+		return contour, fmt.Errorf("error [%w] at dtm.Contour()", err)
 	}
-	// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-	// fmt.Printf("commandOutput: %s\n", commandOutput)
 
 	// read result file
-	data, err := os.ReadFile(filenameGeoJSON)
+	data, err := dtm.ReadVSIMemFile(filenameGeoJSON)
 	if err != nil {
-		return contour, fmt.Errorf("error [%w] at os.ReadFile()", err)
+		return contour, fmt.Errorf("error [%w] at dtm.ReadVSIMemFile()", err)
 	}
 
 	// set contour return structure
@@ -430,7 +536,7 @@ func generateContourObjectForTile2(tile TileMetadata, equidistance float64, isLo
 	attribution := "unknown"
 	resource, err := getElevationResource(tile.Source)
 	if err != nil {
-		slog.Error("contours request: error getting elevation resource", "error", err, "source", tile.Source)
+		slog.ErrorContext(ctx, "contours request: error getting elevation resource", "error", err, "source", tile.Source)
 	} else {
 		attribution = resource.Attribution
 	}
@@ -1,22 +1,118 @@
 package main
 
-import "net/http"
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// CORSPolicy represents the CORS settings for an endpoint.
+type CORSPolicy struct {
+	Path           string   `yaml:"Path"`
+	AllowedOrigins []string `yaml:"AllowedOrigins"`
+	AllowedMethods []string `yaml:"AllowedMethods"`
+	AllowedHeaders []string `yaml:"AllowedHeaders"`
+	MaxAge         int      `yaml:"MaxAge"`
+}
+
+// CORS response headers exposed to browser clients
+var corsExposedHeaders = []string{"ETag", RequestIDHeader, "Retry-After"}
+
+/*
+getCORSPolicy returns the CORS policy for the path (endpoint specific policy or default policy).
+Unset values of an endpoint specific policy are taken from the default policy.
+A policy path ending with '/' applies to all paths below (e.g. /v1/jobs/ for /v1/jobs/{id}).
+*/
+func getCORSPolicy(path string) CORSPolicy {
+	config := getProgConfig()
+	policy := config.CORS.Default
+	for _, endpointPolicy := range config.CORS.Endpoints {
+		isPrefix := strings.HasSuffix(endpointPolicy.Path, "/") && strings.HasPrefix(path, endpointPolicy.Path)
+		if endpointPolicy.Path != path && !isPrefix {
+			continue
+		}
+		if len(endpointPolicy.AllowedOrigins) > 0 {
+			policy.AllowedOrigins = endpointPolicy.AllowedOrigins
+		}
+		if len(endpointPolicy.AllowedMethods) > 0 {
+			policy.AllowedMethods = endpointPolicy.AllowedMethods
+		}
+		if len(endpointPolicy.AllowedHeaders) > 0 {
+			policy.AllowedHeaders = endpointPolicy.AllowedHeaders
+		}
+		if endpointPolicy.MaxAge > 0 {
+			policy.MaxAge = endpointPolicy.MaxAge
+		}
+		break
+	}
+	return policy
+}
+
+/*
+setCORSOriginHeaders sets the 'Access-Control-Allow-Origin' header if the origin of the request is allowed.
+Returns false if the origin is not allowed.
+*/
+func setCORSOriginHeaders(writer http.ResponseWriter, request *http.Request, policy CORSPolicy) bool {
+	origin := request.Header.Get("Origin")
+	switch {
+	case slices.Contains(policy.AllowedOrigins, "*"):
+		writer.Header().Set("Access-Control-Allow-Origin", "*")
+	case origin != "" && slices.Contains(policy.AllowedOrigins, origin):
+		writer.Header().Set("Access-Control-Allow-Origin", origin)
+		writer.Header().Add("Vary", "Origin")
+	default:
+		return false
+	}
+	return true
+}
+
+/*
+corsMiddleware sets the CORS headers for all (non preflight) responses according to the configured policy.
+*/
+func corsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodOptions {
+			if setCORSOriginHeaders(writer, request, getCORSPolicy(request.URL.Path)) {
+				writer.Header().Set("Access-Control-Expose-Headers", strings.Join(corsExposedHeaders, ", "))
+			}
+		}
+		next.ServeHTTP(writer, request)
+	})
+}
 
 /*
 corsOptionsHandler handles CORS preflight (OPTIONS) requests.
 */
-func corsOptionsHandler(writer http.ResponseWriter, _ *http.Request) {
-	// set CORS headers for the preflight request
-	writer.Header().Set("Access-Control-Allow-Origin", "*")
+func corsOptionsHandler(writer http.ResponseWriter, request *http.Request) {
+	policy := getCORSPolicy(request.URL.Path)
+
+	// methods registered for the route (reflected in 'Allow' and 'Access-Control-Allow-Methods')
+	allowedMethods := getAllowedMethods(request)
+	writer.Header().Set("Allow", strings.Join(allowedMethods, ", "))
+
+	// set CORS headers for the preflight request (origin not allowed: no CORS headers)
+	if !setCORSOriginHeaders(writer, request, policy) {
+		writer.WriteHeader(http.StatusOK)
+		return
+	}
 
-	// allowed methods for the actual request
-	writer.Header().Set("Access-Control-Allow-Methods", "POST")
+	// allowed methods for the actual request (configured methods, restricted to the methods of the route)
+	corsMethods := allowedMethods
+	if len(policy.AllowedMethods) > 0 {
+		corsMethods = slices.DeleteFunc(slices.Clone(policy.AllowedMethods), func(method string) bool {
+			return !slices.Contains(allowedMethods, strings.ToUpper(method))
+		})
+	}
+	writer.Header().Set("Access-Control-Allow-Methods", strings.Join(corsMethods, ", "))
 
 	// allowed headers for the actual request
-	writer.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	writer.Header().Set("Access-Control-Allow-Headers", strings.Join(policy.AllowedHeaders, ", "))
 
-	// caching time for results of preflight request in seconds (86400 seconds = 24 hours)
-	writer.Header().Set("Access-Control-Max-Age", "86400")
+	// caching time for results of preflight request in seconds (e.g. 86400 seconds = 24 hours)
+	if policy.MaxAge > 0 {
+		writer.Header().Set("Access-Control-Max-Age", strconv.Itoa(policy.MaxAge))
+	}
 
 	// respond with 200 OK status for the preflight request
 	writer.WriteHeader(http.StatusOK)
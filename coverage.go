@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// maxCoverageSearchRadius is the max search radius (in km) for the nearest covered tile
+const maxCoverageSearchRadius = 25
+
+// ErrOutsideCoverage indicates that the requested coordinates are outside the data coverage (no tile).
+var ErrOutsideCoverage = errors.New("outside data coverage")
+
+// OutsideCoverageError represents a missing (primary) tile for UTM coordinates.
+type OutsideCoverageError struct {
+	Index    string // index of missing tile
+	Zone     int
+	Easting  float64
+	Northing float64
+}
+
+/*
+Error returns the error message (without nearest covered area, see describeOutsideCoverage()).
+*/
+func (e *OutsideCoverageError) Error() string {
+	return fmt.Sprintf("tile [%s] %v", e.Index, ErrOutsideCoverage)
+}
+
+/*
+Is reports whether the target is ErrOutsideCoverage (errors.Is support).
+*/
+func (e *OutsideCoverageError) Is(target error) bool {
+	return target == ErrOutsideCoverage
+}
+
+/*
+findNearestCoveredTile searches the nearest covered tile in the same UTM zone (rings of 1 km tiles around
+the given coordinates, up to max search radius). Returns the tile and the approx. distance in km.
+*/
+func findNearestCoveredTile(zone int, easting float64, northing float64) (TileMetadata, float64, bool) {
+	eastingPrefix := int(math.Floor(easting / 1000.0))
+	northingPrefix := int(math.Floor(northing / 1000.0))
+
+	repositoryMutex.RLock()
+	defer repositoryMutex.RUnlock()
+
+	for radius := 1; radius <= maxCoverageSearchRadius; radius++ {
+		var nearestTile TileMetadata
+		nearestDistance := math.MaxFloat64
+		for dx := -radius; dx <= radius; dx++ {
+			for dy := -radius; dy <= radius; dy++ {
+				// only tiles on the ring with current radius
+				if max(dx, -dx, dy, -dy) != radius {
+					continue
+				}
+				tile, found := Repository.Tile(fmt.Sprintf("%d_%d_%d", zone, eastingPrefix+dx, northingPrefix+dy))
+				if !found {
+					continue
+				}
+				distance := math.Hypot(float64(dx), float64(dy))
+				if distance < nearestDistance {
+					nearestDistance = distance
+					nearestTile = tile
+				}
+			}
+		}
+		if nearestTile.Index != "" {
+			return nearestTile, nearestDistance, true
+		}
+	}
+
+	return TileMetadata{}, 0, false
+}
+
+/*
+describeOutsideCoverage returns the error detail for an 'outside data coverage' error including the nearest
+covered area (state). The search is only done here (once per failed request), not at tile lookup.
+*/
+func describeOutsideCoverage(err error) string {
+	var coverageError *OutsideCoverageError
+	if !errors.As(err, &coverageError) {
+		return err.Error()
+	}
+
+	tile, distance, found := findNearestCoveredTile(coverageError.Zone, coverageError.Easting, coverageError.Northing)
+	if !found {
+		return fmt.Sprintf("%s, no covered area within %d km", err.Error(), maxCoverageSearchRadius)
+	}
+	area := tile.Source
+	resource, resErr := getElevationResource(tile.Source)
+	if resErr == nil {
+		area = fmt.Sprintf("%s (%s)", resource.Code, resource.Name)
+	}
+	return fmt.Sprintf("%s, nearest covered area: %s, tile [%s], approx. %.0f km", err.Error(), area, tile.Index, distance)
+}
+
+/*
+distanceToCoverage returns the nearest covered tile and the distance (in meters) between the coordinates of an
+'outside data coverage' error and the edge of this tile. The nearest tile is searched only once per missing
+tile (tileCache, key: index of missing tile), the distance is calculated for the exact coordinates.
+*/
+func distanceToCoverage(err error, tileCache map[string]TileMetadata) (TileMetadata, float64, bool) {
+	var coverageError *OutsideCoverageError
+	if !errors.As(err, &coverageError) {
+		return TileMetadata{}, 0, false
+	}
+
+	tile, cached := tileCache[coverageError.Index]
+	if !cached {
+		tile, _, _ = findNearestCoveredTile(coverageError.Zone, coverageError.Easting, coverageError.Northing)
+		tileCache[coverageError.Index] = tile
+	}
+	if tile.Index == "" {
+		return TileMetadata{}, 0, false
+	}
+
+	// tile square (1 km) from tile index (e.g. 32_497_5670)
+	var zone, eastingPrefix, northingPrefix int
+	_, scanErr := fmt.Sscanf(tile.Index, "%d_%d_%d", &zone, &eastingPrefix, &northingPrefix)
+	if scanErr != nil {
+		return TileMetadata{}, 0, false
+	}
+	minEasting := float64(eastingPrefix) * 1000.0
+	minNorthing := float64(northingPrefix) * 1000.0
+	dx := math.Max(0, math.Max(minEasting-coverageError.Easting, coverageError.Easting-(minEasting+1000.0)))
+	dy := math.Max(0, math.Max(minNorthing-coverageError.Northing, coverageError.Northing-(minNorthing+1000.0)))
+
+	return tile, math.Hypot(dx, dy), true
+}
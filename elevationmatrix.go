@@ -0,0 +1,440 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/airbusgeo/godal"
+
+	"klaus/elevation/dtm-elevation-service/pkg/dtm"
+)
+
+// max number of cells of an elevation matrix (e.g. 1000 x 1000)
+const maxElevationMatrixCells = 1000000
+
+// value of matrix cells without elevation data
+const elevationMatrixNoData = -9999.0
+
+/*
+elevationMatrixRequest handles 'elevation matrix request' from client: grid (N x M) of elevations for a bounding box
+at a requested resolution as compact array (e.g. for lightweight clients like microcontrollers or game engines).
+*/
+func elevationMatrixRequest(writer http.ResponseWriter, request *http.Request) {
+	var elevationMatrixResponse = ElevationMatrixResponse{Type: TypeElevationMatrixResponse, ID: "unknown"}
+	elevationMatrixResponse.Attributes.IsError = true
+
+	// statistics
+	atomic.AddUint64(&ElevationMatrixRequests, 1)
+
+	// limit overall request body size
+	request.Body = http.MaxBytesReader(writer, request.Body, MaxElevationMatrixRequestBodySize)
+
+	// read request
+	bodyData, err := io.ReadAll(request.Body)
+	if err != nil {
+		// check specifically for the error returned by MaxBytesReader
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			slog.WarnContext(request.Context(), "elevation matrix request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
+			elevationMatrixResponse.Attributes.Error = newErrorObject(EndpointElevationMatrix, ReasonRequestBodyTooLarge, fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit))
+			buildElevationMatrixResponse(writer, request, http.StatusRequestEntityTooLarge, elevationMatrixResponse)
+		} else {
+			// handle other read errors
+			slog.WarnContext(request.Context(), "elevation matrix request: error reading request body", "error", err, "ID", "unknown")
+			elevationMatrixResponse.Attributes.Error = newErrorObject(EndpointElevationMatrix, ReasonReadingRequestBody, err.Error())
+			buildElevationMatrixResponse(writer, request, http.StatusBadRequest, elevationMatrixResponse)
+		}
+		return
+	}
+
+	// unmarshal request
+	elevationMatrixRequest := ElevationMatrixRequest{}
+	err = json.Unmarshal(bodyData, &elevationMatrixRequest)
+	if err != nil {
+		slog.WarnContext(request.Context(), "elevation matrix request: error unmarshaling request body", "error", err, "ID", "unknown")
+		elevationMatrixResponse.Attributes.Error = newErrorObject(EndpointElevationMatrix, ReasonUnmarshalingRequestBody, err.Error())
+		buildElevationMatrixResponse(writer, request, http.StatusBadRequest, elevationMatrixResponse)
+		return
+	}
+
+	// set defaults
+	if elevationMatrixRequest.Attributes.Resolution == 0 {
+		elevationMatrixRequest.Attributes.Resolution = 10.0
+		elevationMatrixResponse.Attributes.Warnings = append(elevationMatrixResponse.Attributes.Warnings, "Resolution not set, default '10' meters applied")
+	}
+	if elevationMatrixRequest.Attributes.Resampling == "" {
+		elevationMatrixRequest.Attributes.Resampling = "average"
+	}
+	if elevationMatrixRequest.Attributes.Format == "" {
+		elevationMatrixRequest.Attributes.Format = "json"
+	}
+
+	// copy request parameters into response
+	elevationMatrixResponse.ID = elevationMatrixRequest.ID
+	elevationMatrixResponse.Attributes.Zone = elevationMatrixRequest.Attributes.Zone
+	elevationMatrixResponse.Attributes.MinX = elevationMatrixRequest.Attributes.MinX
+	elevationMatrixResponse.Attributes.MinY = elevationMatrixRequest.Attributes.MinY
+	elevationMatrixResponse.Attributes.MaxX = elevationMatrixRequest.Attributes.MaxX
+	elevationMatrixResponse.Attributes.MaxY = elevationMatrixRequest.Attributes.MaxY
+	elevationMatrixResponse.Attributes.Resolution = elevationMatrixRequest.Attributes.Resolution
+	elevationMatrixResponse.Attributes.Resampling = elevationMatrixRequest.Attributes.Resampling
+	elevationMatrixResponse.Attributes.Format = elevationMatrixRequest.Attributes.Format
+
+	// verify request data
+	err = verifyElevationMatrixRequestData(request, elevationMatrixRequest)
+	if err != nil {
+		slog.WarnContext(request.Context(), "elevation matrix request: error verifying request data", "error", err, "ID", elevationMatrixRequest.ID)
+		elevationMatrixResponse.Attributes.Error = newErrorObject(EndpointElevationMatrix, ReasonVerifyingRequestData, err.Error())
+		buildElevationMatrixResponse(writer, request, http.StatusBadRequest, elevationMatrixResponse)
+		return
+	}
+
+	// grid (aligned to resolution) in UTM coordinates
+	attributes := elevationMatrixRequest.Attributes
+	bbox := [4]float64{attributes.MinX, attributes.MinY, attributes.MaxX, attributes.MaxY}
+	zone, gridBounds, err := getElevationMatrixGridUTM(attributes.Zone, bbox, attributes.Resolution)
+	if err != nil {
+		slog.WarnContext(request.Context(), "elevation matrix request: error building grid", "error", err, "ID", elevationMatrixRequest.ID)
+		elevationMatrixResponse.Attributes.Error = newErrorObject(EndpointElevationMatrix, ReasonVerifyingRequestData, err.Error())
+		buildElevationMatrixResponse(writer, request, http.StatusBadRequest, elevationMatrixResponse)
+		return
+	}
+
+	// get all tiles (metadata) within grid
+	tiles, err := getAllTilesArea(zone, gridBounds)
+	if err != nil {
+		slog.WarnContext(request.Context(), "elevation matrix request: error getting GeoTIFF tiles for bounding box", "error", err, "zone", zone, "ID", elevationMatrixRequest.ID)
+		if errors.Is(err, ErrOutsideCoverage) {
+			elevationMatrixResponse.Attributes.Error = newErrorObject(EndpointElevationMatrix, ReasonOutsideCoverage, err.Error())
+			buildElevationMatrixResponse(writer, request, http.StatusNotFound, elevationMatrixResponse)
+			return
+		}
+		elevationMatrixResponse.Attributes.Error = newErrorObject(EndpointElevationMatrix, ReasonVerifyingRequestData, err.Error())
+		buildElevationMatrixResponse(writer, request, http.StatusBadRequest, elevationMatrixResponse)
+		return
+	}
+
+	// conditional request: unchanged response (same parameters, same tiles) needs no processing
+	etag := buildETag("elevationmatrix", elevationMatrixRequest, tiles)
+	if checkNotModified(writer, request, etag) {
+		return
+	}
+
+	// build elevation matrix (mosaic of all tiles, progress reported as one step per tile)
+	addJobTiles(request.Context(), len(tiles))
+	elevationMatrix, err := generateElevationMatrixObjectForArea(request.Context(), tiles, zone, gridBounds,
+		elevationMatrixRequest.Attributes.Resolution, elevationMatrixRequest.Attributes.Resampling, elevationMatrixRequest.Attributes.Format)
+	addJobTilesDone(request.Context(), len(tiles))
+	if err != nil {
+		slog.WarnContext(request.Context(), "elevation matrix request: error generating elevation matrix object", "error", err, "ID", elevationMatrixRequest.ID)
+		if errors.Is(err, context.Canceled) {
+			// client disconnected, processing aborted, no response required
+			return
+		}
+		if errors.Is(err, ErrServerBusy) {
+			elevationMatrixResponse.Attributes.Error = newErrorObject(EndpointElevationMatrix, ReasonServerBusy, err.Error())
+			writer.Header().Set("Retry-After", strconv.Itoa(getProgConfig().GDALJobQueue.RetryAfter))
+			buildElevationMatrixResponse(writer, request, http.StatusTooManyRequests, elevationMatrixResponse)
+			return
+		}
+		elevationMatrixResponse.Attributes.Error = newErrorObject(EndpointElevationMatrix, ReasonGeneratingObject, err.Error())
+		buildElevationMatrixResponse(writer, request, http.StatusBadRequest, elevationMatrixResponse)
+		return
+	}
+	elevationMatrixResponse.Attributes.ElevationMatrix = elevationMatrix
+	for _, tile := range tiles {
+		elevationMatrixResponse.Attributes.AreaTiles = append(elevationMatrixResponse.Attributes.AreaTiles, tile.Index)
+	}
+	addUsage(request.Context(), 0, len(tiles))
+
+	// success response
+	elevationMatrixResponse.Attributes.IsError = false
+	setETag(writer, etag)
+	buildElevationMatrixResponse(writer, request, http.StatusOK, elevationMatrixResponse)
+}
+
+/*
+verifyElevationMatrixRequestData verifies 'elevation matrix' request data.
+*/
+func verifyElevationMatrixRequestData(request *http.Request, elevationMatrixRequest ElevationMatrixRequest) error {
+	// verify HTTP header
+	contentType := request.Header.Get("Content-Type")
+	if !strings.HasPrefix(strings.ToLower(contentType), "application/json") {
+		return fmt.Errorf("unexpected or missing HTTP header field Content-Type, value = [%s], expected 'application/json'", contentType)
+	}
+
+	// verify Type
+	if elevationMatrixRequest.Type != TypeElevationMatrixRequest {
+		return fmt.Errorf("unexpected request Type [%v]", elevationMatrixRequest.Type)
+	}
+
+	// verify ID
+	if len(elevationMatrixRequest.ID) > 1024 {
+		return errors.New("ID must be 0-1024 characters long")
+	}
+
+	// verify bounding box (either utm or lon/lat coordinates)
+	attributes := elevationMatrixRequest.Attributes
+	bbox := [4]float64{attributes.MinX, attributes.MinY, attributes.MaxX, attributes.MaxY}
+	if attributes.Zone != 0 {
+		if attributes.Zone < 32 || attributes.Zone > 33 {
+			return errors.New("invalid zone for Germany")
+		}
+		for _, easting := range []float64{bbox[0], bbox[2]} {
+			if easting < 100000 || easting > 900000 {
+				return errors.New("invalid UTM coordinates for Germany")
+			}
+		}
+		for _, northing := range []float64{bbox[1], bbox[3]} {
+			if northing < 5200000 || northing > 6200000 {
+				return errors.New("invalid UTM coordinates for Germany")
+			}
+		}
+	} else {
+		for _, longitude := range []float64{bbox[0], bbox[2]} {
+			if longitude > 15.3 || longitude < 5.5 {
+				return errors.New("invalid longitude for Germany")
+			}
+		}
+		for _, latitude := range []float64{bbox[1], bbox[3]} {
+			if latitude > 55.3 || latitude < 47.0 {
+				return errors.New("invalid latitude for Germany")
+			}
+		}
+	}
+	if bbox[0] >= bbox[2] || bbox[1] >= bbox[3] {
+		return errors.New("invalid bounding box (min must be less than max)")
+	}
+
+	// verify Resolution
+	if attributes.Resolution < 1.0 || attributes.Resolution > 1000.0 {
+		return errors.New("resolution must be between 1.0 and 1000.0 meters")
+	}
+
+	// verify Resampling
+	if !slices.Contains([]string{"average", "nearest", "bilinear", "cubic"}, attributes.Resampling) {
+		return errors.New("unsupported resampling (not 'average', 'nearest', 'bilinear' or 'cubic')")
+	}
+
+	// verify Format
+	if !(attributes.Format == "json" || attributes.Format == "binary") {
+		return errors.New("unsupported format (not 'json' or 'binary')")
+	}
+
+	return nil
+}
+
+/*
+buildElevationMatrixResponse builds HTTP responses with specified status and body.
+*/
+func buildElevationMatrixResponse(writer http.ResponseWriter, request *http.Request, httpStatus int, elevationMatrixResponse ElevationMatrixResponse) {
+	// response metadata (versions, processing duration, cache hit)
+	elevationMatrixResponse.Meta = newResponseMeta(request.Context())
+
+	// encode (streaming) and send response
+	streamJSONResponse(writer, request, httpStatus, elevationMatrixResponse, false)
+}
+
+/*
+getElevationMatrixGridUTM returns the UTM zone and the bounds of the grid (aligned to resolution) covering the bounding box
+(minX, minY, maxX, maxY).
+For lon/lat input the zone is derived from the longitude of the bounding box center.
+*/
+func getElevationMatrixGridUTM(zone int, bbox [4]float64, resolution float64) (int, [4]float64, error) {
+	bounds := bbox
+	if zone == 0 {
+		// lon/lat input: zone 32 (6° - 12° E) or zone 33 (12° - 18° E)
+		zone = 32
+		if (bbox[0]+bbox[2])/2 >= 12.0 {
+			zone = 33
+		}
+		bounds = [4]float64{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+		for _, corner := range [][2]float64{{bbox[0], bbox[1]}, {bbox[2], bbox[1]}, {bbox[2], bbox[3]}, {bbox[0], bbox[3]}} {
+			easting, northing, err := transformLonLatToUTM(corner[0], corner[1], 25800+zone)
+			if err != nil {
+				return 0, bounds, fmt.Errorf("error [%w] at transformLonLatToUTM()", err)
+			}
+			bounds[0] = min(bounds[0], easting)
+			bounds[1] = min(bounds[1], northing)
+			bounds[2] = max(bounds[2], easting)
+			bounds[3] = max(bounds[3], northing)
+		}
+	}
+
+	// align grid to resolution (outer edges of cells)
+	gridBounds := [4]float64{
+		math.Floor(bounds[0]/resolution) * resolution,
+		math.Floor(bounds[1]/resolution) * resolution,
+		math.Ceil(bounds[2]/resolution) * resolution,
+		math.Ceil(bounds[3]/resolution) * resolution,
+	}
+
+	columns := int(math.Round((gridBounds[2] - gridBounds[0]) / resolution))
+	rows := int(math.Round((gridBounds[3] - gridBounds[1]) / resolution))
+	if columns*rows > maxElevationMatrixCells {
+		return 0, gridBounds, fmt.Errorf("matrix has %d x %d cells, max %d cells supported (increase resolution or reduce bounding box)", columns, rows, maxElevationMatrixCells)
+	}
+
+	return zone, gridBounds, nil
+}
+
+/*
+generateElevationMatrixObjectForArea builds the elevation matrix for a grid covered by several tiles:
+- build mosaic (VRT) of all tiles
+- crop and resample mosaic to grid (gdal_translate)
+- read all cells and encode them as array (json) or float32 little-endian (binary)
+*/
+func generateElevationMatrixObjectForArea(ctx context.Context, tiles []TileMetadata, zone int, gridBounds [4]float64,
+	resolution float64, resampling string, format string) (ElevationMatrix, error) {
+	var elevationMatrix ElevationMatrix
+
+	// area index (e.g. 32_497_5670-32_503_5675)
+	areaIndex := tiles[0].Index + "-" + tiles[len(tiles)-1].Index
+
+	// lookup response cache
+	cacheKeyParts := []any{"elevationmatrix", zone, gridBounds, resolution, resampling, format}
+	for _, tile := range tiles {
+		cacheKeyParts = append(cacheKeyParts, tile.Index, tile.Actuality)
+	}
+	cacheKey := buildResponseCacheKey(cacheKeyParts...)
+	cached, found := responseCache.Get(cacheKey)
+	recordCacheLookup(ctx, found)
+	if found {
+		return cached.(ElevationMatrix), nil
+	}
+
+	// run operations in memory (/vsimem)
+	vsimemPrefix := dtm.NewVSIMemPrefix("elevationmatrix")
+	filenameVRT := vsimemPrefix + "mosaic.vrt"
+	filenameGridTif := vsimemPrefix + "mosaic.grid.tif"
+	defer removeVSIMemFiles(filenameVRT, filenameGridTif)
+
+	// gdalbuildvrt (areas without tiles are nodata)
+	tilePaths := make([]string, 0, len(tiles))
+	for _, tile := range tiles {
+		tilePaths = append(tilePaths, tile.Path)
+	}
+	err := dtm.BuildVRT(ctx, tilePaths, filenameVRT, []string{"-vrtnodata", strconv.FormatFloat(elevationMatrixNoData, 'f', -1, 64)})
+	if err != nil {
+		return elevationMatrix, fmt.Errorf("error [%w] at dtm.BuildVRT()", err)
+	}
+
+	// gdal_translate: crop mosaic to grid and resample to resolution
+	err = dtm.Translate(ctx, filenameVRT, filenameGridTif, []string{"-of", "GTiff", "-ot", "Float32",
+		"-projwin", strconv.FormatFloat(gridBounds[0], 'f', -1, 64), strconv.FormatFloat(gridBounds[3], 'f', -1, 64),
+		strconv.FormatFloat(gridBounds[2], 'f', -1, 64), strconv.FormatFloat(gridBounds[1], 'f', -1, 64),
+		"-tr", strconv.FormatFloat(resolution, 'f', -1, 64), strconv.FormatFloat(resolution, 'f', -1, 64),
+		"-r", resampling})
+	if err != nil {
+		return elevationMatrix, fmt.Errorf("error [%w] at dtm.Translate()", err)
+	}
+
+	// read grid
+	dataset, err := godal.Open(filenameGridTif, godal.RasterOnly())
+	if err != nil {
+		return elevationMatrix, fmt.Errorf("error [%w] at godal.Open(), file: %s", err, filenameGridTif)
+	}
+	defer dataset.Close()
+
+	gt, err := dataset.GeoTransform()
+	if err != nil {
+		return elevationMatrix, fmt.Errorf("error [%w] at dataset.GeoTransform()", err)
+	}
+	band := dataset.Bands()[0]
+	noData, hasNoData := band.NoData()
+	structure := band.Structure()
+	values := make([]float32, structure.SizeX*structure.SizeY)
+	err = band.Read(0, 0, values, structure.SizeX, structure.SizeY)
+	if err != nil {
+		return elevationMatrix, fmt.Errorf("error [%w] at band.Read(), file: %s", err, filenameGridTif)
+	}
+
+	// normalize nodata cells, determine elevation range
+	minElevation := math.Inf(1)
+	maxElevation := math.Inf(-1)
+	for i, value := range values {
+		elevation := float64(value)
+		if math.IsNaN(elevation) || (hasNoData && value == float32(noData)) || elevation < -9998.9 {
+			values[i] = elevationMatrixNoData
+			continue
+		}
+		minElevation = min(minElevation, elevation)
+		maxElevation = max(maxElevation, elevation)
+	}
+	if math.IsInf(minElevation, 1) {
+		minElevation = elevationMatrixNoData
+		maxElevation = elevationMatrixNoData
+	}
+
+	// encode cells
+	switch format {
+	case "binary":
+		data := make([]byte, 4*len(values))
+		for i, value := range values {
+			binary.LittleEndian.PutUint32(data[4*i:], math.Float32bits(value))
+		}
+		elevationMatrix.Data = data
+		elevationMatrix.DataFormat = "float32le"
+	default:
+		elevationMatrix.Values = values
+		elevationMatrix.DataFormat = "json"
+	}
+
+	// distinct actualities, origins and attributions of all tiles
+	var actualities, origins, attributions []string
+	for _, tile := range tiles {
+		if !slices.Contains(actualities, tile.Actuality) {
+			actualities = append(actualities, tile.Actuality)
+		}
+		if slices.Contains(origins, tile.Source) {
+			continue
+		}
+		origins = append(origins, tile.Source)
+		attribution := "unknown"
+		resource, err := getElevationResource(tile.Source)
+		if err != nil {
+			slog.ErrorContext(ctx, "elevation matrix request: error getting elevation resource", "error", err, "source", tile.Source)
+		} else {
+			attribution = resource.Attribution
+		}
+		attributions = append(attributions, attribution)
+	}
+	slices.Sort(actualities)
+
+	// set elevation matrix return structure
+	elevationMatrix.Zone = zone
+	elevationMatrix.GridBounds = [4]float64{gt[0], gt[3] + float64(structure.SizeY)*gt[5], gt[0] + float64(structure.SizeX)*gt[1], gt[3]}
+	elevationMatrix.Columns = structure.SizeX
+	elevationMatrix.Rows = structure.SizeY
+	elevationMatrix.Resolution = resolution
+	elevationMatrix.NoDataValue = elevationMatrixNoData
+	elevationMatrix.MinElevation = minElevation
+	elevationMatrix.MaxElevation = maxElevation
+	elevationMatrix.Actuality = strings.Join(actualities, ", ")
+	elevationMatrix.Origin = strings.Join(origins, ", ")
+	elevationMatrix.Attribution = strings.Join(attributions, "; ")
+	elevationMatrix.TileIndex = areaIndex
+
+	// get provenance of source files (delivery file name and checksum)
+	sourceFiles, err := getSourceFiles(tiles...)
+	if err != nil {
+		slog.ErrorContext(ctx, "elevation matrix request: error getting source files", "error", err, "tile", areaIndex)
+	}
+	elevationMatrix.SourceFiles = sourceFiles
+
+	// add to response cache
+	responseCache.Add(cacheKey, elevationMatrix, 4*len(values))
+
+	return elevationMatrix, nil
+}
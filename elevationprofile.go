@@ -31,17 +31,13 @@ func elevationprofileRequest(writer http.ResponseWriter, request *http.Request)
 	if err != nil {
 		var maxBytesErr *http.MaxBytesError
 		if errors.As(err, &maxBytesErr) {
-			slog.Warn("elevationprofile request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
-			profileResponse.Attributes.Error.Code = "14000"
-			profileResponse.Attributes.Error.Title = "request body too large"
-			profileResponse.Attributes.Error.Detail = fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit)
-			buildElevationProfileResponse(writer, http.StatusRequestEntityTooLarge, profileResponse)
+			slog.WarnContext(request.Context(), "elevationprofile request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
+			profileResponse.Attributes.Error = newErrorObject(EndpointElevationProfile, ReasonRequestBodyTooLarge, fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit))
+			buildElevationProfileResponse(writer, request, http.StatusRequestEntityTooLarge, profileResponse)
 		} else {
-			slog.Warn("elevationprofile request: error reading request body", "error", err, "ID", "unknown")
-			profileResponse.Attributes.Error.Code = "14020"
-			profileResponse.Attributes.Error.Title = "error reading request body"
-			profileResponse.Attributes.Error.Detail = err.Error()
-			buildElevationProfileResponse(writer, http.StatusBadRequest, profileResponse)
+			slog.WarnContext(request.Context(), "elevationprofile request: error reading request body", "error", err, "ID", "unknown")
+			profileResponse.Attributes.Error = newErrorObject(EndpointElevationProfile, ReasonReadingRequestBody, err.Error())
+			buildElevationProfileResponse(writer, request, http.StatusBadRequest, profileResponse)
 		}
 		return
 	}
@@ -50,11 +46,9 @@ func elevationprofileRequest(writer http.ResponseWriter, request *http.Request)
 	profileRequest := ElevationProfileRequest{}
 	err = json.Unmarshal(bodyData, &profileRequest)
 	if err != nil {
-		slog.Warn("elevationprofile request: error unmarshaling request body", "error", err, "ID", "unknown")
-		profileResponse.Attributes.Error.Code = "14040"
-		profileResponse.Attributes.Error.Title = "error unmarshaling request body"
-		profileResponse.Attributes.Error.Detail = err.Error()
-		buildElevationProfileResponse(writer, http.StatusBadRequest, profileResponse)
+		slog.WarnContext(request.Context(), "elevationprofile request: error unmarshaling request body", "error", err, "ID", "unknown")
+		profileResponse.Attributes.Error = newErrorObject(EndpointElevationProfile, ReasonUnmarshalingRequestBody, err.Error())
+		buildElevationProfileResponse(writer, request, http.StatusBadRequest, profileResponse)
 		return
 	}
 
@@ -65,25 +59,39 @@ func elevationprofileRequest(writer http.ResponseWriter, request *http.Request)
 	profileResponse.Attributes.MaxTotalProfilePoints = profileRequest.Attributes.MaxTotalProfilePoints
 	profileResponse.Attributes.MinStepSize = profileRequest.Attributes.MinStepSize
 
+	// defaults
+	if profileRequest.Attributes.ChartWidth == 0 {
+		profileRequest.Attributes.ChartWidth = 800
+	}
+	if profileRequest.Attributes.ChartHeight == 0 {
+		profileRequest.Attributes.ChartHeight = 300
+	}
+
 	// verify request data
 	err = verifyElevationProfileRequestData(request, profileRequest)
 	if err != nil {
-		slog.Warn("elevationprofile request: error verifying request data", "error", err, "ID", profileRequest.ID)
-		profileResponse.Attributes.Error.Code = "14060"
-		profileResponse.Attributes.Error.Title = "error verifying request data"
-		profileResponse.Attributes.Error.Detail = err.Error()
-		buildElevationProfileResponse(writer, http.StatusBadRequest, profileResponse)
+		slog.WarnContext(request.Context(), "elevationprofile request: error verifying request data", "error", err, "ID", profileRequest.ID)
+		profileResponse.Attributes.Error = newErrorObject(EndpointElevationProfile, ReasonVerifyingRequestData, err.Error())
+		buildElevationProfileResponse(writer, request, http.StatusBadRequest, profileResponse)
 		return
 	}
 
 	// elevation profile calculation
 	profile, usedSources, err := calculateElevationProfile(profileRequest.Attributes.PointA, profileRequest.Attributes.PointB, profileRequest.Attributes.MaxTotalProfilePoints, profileRequest.Attributes.MinStepSize)
 	if err != nil {
-		slog.Error("elevationprofile request: error calculating profile", "error", err, "ID", profileRequest.ID)
-		profileResponse.Attributes.Error.Code = "14080"
-		profileResponse.Attributes.Error.Title = "error calculating elevation profile"
-		profileResponse.Attributes.Error.Detail = err.Error()
-		buildElevationProfileResponse(writer, http.StatusInternalServerError, profileResponse)
+		slog.ErrorContext(request.Context(), "elevationprofile request: error calculating profile", "error", err, "ID", profileRequest.ID)
+		if errors.Is(err, ErrOutsideCoverage) {
+			profileResponse.Attributes.Error = newErrorObject(EndpointElevationProfile, ReasonOutsideCoverage, describeOutsideCoverage(err))
+			buildElevationProfileResponse(writer, request, http.StatusNotFound, profileResponse)
+			return
+		}
+		if errors.Is(err, ErrSourceUnavailable) {
+			profileResponse.Attributes.Error = newErrorObject(EndpointElevationProfile, ReasonSourceUnavailable, err.Error())
+			buildElevationProfileResponse(writer, request, http.StatusServiceUnavailable, profileResponse)
+			return
+		}
+		profileResponse.Attributes.Error = newErrorObject(EndpointElevationProfile, ReasonCalculatingElevationProfile, err.Error())
+		buildElevationProfileResponse(writer, request, http.StatusInternalServerError, profileResponse)
 		return
 	}
 
@@ -99,11 +107,26 @@ func elevationprofileRequest(writer http.ResponseWriter, request *http.Request)
 		attributions = append(attributions, attr)
 	}
 
+	// render profile chart (e.g. for clients without plotting library)
+	if profileRequest.Attributes.ChartFormat != "" {
+		chart, err := renderProfileChart(profile, profileRequest.Attributes.ChartFormat, profileRequest.Attributes.ChartWidth, profileRequest.Attributes.ChartHeight)
+		if err != nil {
+			slog.WarnContext(request.Context(), "elevationprofile request: error rendering profile chart", "error", err, "ID", profileRequest.ID)
+			profileResponse.Attributes.Warnings = append(profileResponse.Attributes.Warnings, fmt.Sprintf("profile chart not rendered (%v)", err))
+		} else {
+			profileResponse.Attributes.Chart = &chart
+		}
+	}
+
 	// successful response
+	if len(profile) > 0 {
+		profileResponse.Attributes.Length2D = profile[len(profile)-1].Distance - profile[0].Distance
+		profileResponse.Attributes.SurfaceDistance = profile[len(profile)-1].SurfaceDistance
+	}
 	profileResponse.Attributes.Profile = profile
 	profileResponse.Attributes.Attributions = attributions
 	profileResponse.Attributes.IsError = false
-	buildElevationProfileResponse(writer, http.StatusOK, profileResponse)
+	buildElevationProfileResponse(writer, request, http.StatusOK, profileResponse)
 }
 
 /*
@@ -204,6 +227,14 @@ func calculateElevationProfile(pointA, pointB PointDefinition, maxTotalProfilePo
 			Attribution: fmt.Sprintf("%s, %s", tile.Source, tile.Actuality),
 		}
 
+		// distance along terrain surface (skipped points are bridged)
+		if len(profile) > 0 {
+			previous := profile[len(profile)-1]
+			horizontal := currentDistance - previous.Distance
+			vertical := elevation - previous.Elevation
+			profilePoint.SurfaceDistance = previous.SurfaceDistance + math.Sqrt(horizontal*horizontal+vertical*vertical)
+		}
+
 		// populate coordinates in the response based on the original request type
 		if isUTMRequest {
 			profilePoint.Easting = easting
@@ -237,9 +268,6 @@ func verifyElevationProfileRequestData(request *http.Request, profileRequest Ele
 	if !strings.HasPrefix(strings.ToLower(request.Header.Get("Content-Type")), "application/json") {
 		return fmt.Errorf("unexpected or missing HTTP header 'Content-Type', expected 'application/json'")
 	}
-	if !strings.HasPrefix(strings.ToLower(request.Header.Get("Accept")), "application/json") {
-		return fmt.Errorf("unexpected or missing HTTP header 'Accept', expected 'application/json'")
-	}
 
 	// verify Type and ID
 	if profileRequest.Type != TypeElevationProfileRequest {
@@ -277,6 +305,10 @@ func verifyElevationProfileRequestData(request *http.Request, profileRequest Ele
 	if attr.MinStepSize < 1.0 || attr.MinStepSize > 1000.0 {
 		return errors.New("MinStepSize must be between 1.0 and 1000.0 meters")
 	}
+	err := verifyProfileChartOptions(attr.ChartFormat, attr.ChartWidth, attr.ChartHeight)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -284,14 +316,13 @@ func verifyElevationProfileRequestData(request *http.Request, profileRequest Ele
 /*
 buildElevationProfileResponse builds HTTP responses.
 */
-func buildElevationProfileResponse(writer http.ResponseWriter, httpStatus int, profileResponse ElevationProfileResponse) {
-	writer.Header().Set("Access-Control-Allow-Origin", "*")
-	writer.Header().Set("Access-Control-Allow-Methods", "POST")
-	writer.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+func buildElevationProfileResponse(writer http.ResponseWriter, request *http.Request, httpStatus int, profileResponse ElevationProfileResponse) {
+	// response metadata (versions, processing duration, cache hit)
+	profileResponse.Meta = newResponseMeta(request.Context())
 
-	body, err := json.MarshalIndent(profileResponse, "", "  ")
+	body, err := marshalResponse(profileResponse)
 	if err != nil {
-		slog.Error("error marshaling elevationprofile response", "error", err)
+		slog.ErrorContext(request.Context(), "error marshaling elevationprofile response", "error", err)
 		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
@@ -300,6 +331,6 @@ func buildElevationProfileResponse(writer http.ResponseWriter, httpStatus int, p
 	writer.WriteHeader(httpStatus)
 	_, err = writer.Write(body)
 	if err != nil {
-		slog.Error("error writing HTTP response body", "error", err)
+		slog.ErrorContext(request.Context(), "error writing HTTP response body", "error", err)
 	}
 }
@@ -0,0 +1,384 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"klaus/elevation/dtm-elevation-service/pkg/dtm"
+)
+
+// number of vertices of the circle polygon (radius around center point)
+const elevationRangeCircleVertices = 72
+
+/*
+elevationRangeRequest handles 'elevation range request' from client: polygon(s) of terrain within an elevation band
+(e.g. 600 - 800 m) inside a radius around a center point (e.g. for hike planning or habitat mapping).
+*/
+func elevationRangeRequest(writer http.ResponseWriter, request *http.Request) {
+	var elevationRangeResponse = ElevationRangeResponse{Type: TypeElevationRangeResponse, ID: "unknown"}
+	elevationRangeResponse.Attributes.IsError = true
+
+	// statistics
+	atomic.AddUint64(&ElevationRangeRequests, 1)
+
+	// limit overall request body size
+	request.Body = http.MaxBytesReader(writer, request.Body, MaxElevationRangeRequestBodySize)
+
+	// read request
+	bodyData, err := io.ReadAll(request.Body)
+	if err != nil {
+		// check specifically for the error returned by MaxBytesReader
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			slog.WarnContext(request.Context(), "elevation range request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
+			elevationRangeResponse.Attributes.Error = newErrorObject(EndpointElevationRange, ReasonRequestBodyTooLarge, fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit))
+			buildElevationRangeResponse(writer, request, http.StatusRequestEntityTooLarge, elevationRangeResponse)
+		} else {
+			// handle other read errors
+			slog.WarnContext(request.Context(), "elevation range request: error reading request body", "error", err, "ID", "unknown")
+			elevationRangeResponse.Attributes.Error = newErrorObject(EndpointElevationRange, ReasonReadingRequestBody, err.Error())
+			buildElevationRangeResponse(writer, request, http.StatusBadRequest, elevationRangeResponse)
+		}
+		return
+	}
+
+	// unmarshal request
+	elevationRangeRequest := ElevationRangeRequest{}
+	err = json.Unmarshal(bodyData, &elevationRangeRequest)
+	if err != nil {
+		slog.WarnContext(request.Context(), "elevation range request: error unmarshaling request body", "error", err, "ID", "unknown")
+		elevationRangeResponse.Attributes.Error = newErrorObject(EndpointElevationRange, ReasonUnmarshalingRequestBody, err.Error())
+		buildElevationRangeResponse(writer, request, http.StatusBadRequest, elevationRangeResponse)
+		return
+	}
+
+	// resolve place name (geocoding) into lon/lat coordinates
+	if elevationRangeRequest.Attributes.Place != "" {
+		place, err := geocodePlace(request.Context(), elevationRangeRequest.Attributes.Place)
+		if err != nil {
+			slog.WarnContext(request.Context(), "elevation range request: error geocoding place", "error", err, "place", elevationRangeRequest.Attributes.Place, "ID", elevationRangeRequest.ID)
+			elevationRangeResponse.ID = elevationRangeRequest.ID
+			elevationRangeResponse.Attributes.Place = elevationRangeRequest.Attributes.Place
+			if errors.Is(err, ErrPlaceNotFound) {
+				elevationRangeResponse.Attributes.Error = newErrorObject(EndpointElevationRange, ReasonPlaceNotFound, err.Error())
+				buildElevationRangeResponse(writer, request, http.StatusNotFound, elevationRangeResponse)
+				return
+			}
+			if errors.Is(err, ErrGeocoderUnavailable) {
+				elevationRangeResponse.Attributes.Error = newErrorObject(EndpointElevationRange, ReasonGeocoderUnavailable, err.Error())
+				buildElevationRangeResponse(writer, request, http.StatusServiceUnavailable, elevationRangeResponse)
+				return
+			}
+			elevationRangeResponse.Attributes.Error = newErrorObject(EndpointElevationRange, ReasonVerifyingRequestData, err.Error())
+			buildElevationRangeResponse(writer, request, http.StatusBadRequest, elevationRangeResponse)
+			return
+		}
+		elevationRangeRequest.Attributes.Zone = 0
+		elevationRangeRequest.Attributes.Longitude = place.Longitude
+		elevationRangeRequest.Attributes.Latitude = place.Latitude
+		elevationRangeResponse.Attributes.PlaceName = place.DisplayName
+	}
+
+	// copy request parameters into response
+	elevationRangeResponse.ID = elevationRangeRequest.ID
+	elevationRangeResponse.Attributes.Zone = elevationRangeRequest.Attributes.Zone
+	elevationRangeResponse.Attributes.Easting = elevationRangeRequest.Attributes.Easting
+	elevationRangeResponse.Attributes.Northing = elevationRangeRequest.Attributes.Northing
+	elevationRangeResponse.Attributes.Longitude = elevationRangeRequest.Attributes.Longitude
+	elevationRangeResponse.Attributes.Latitude = elevationRangeRequest.Attributes.Latitude
+	elevationRangeResponse.Attributes.Place = elevationRangeRequest.Attributes.Place
+	elevationRangeResponse.Attributes.Radius = elevationRangeRequest.Attributes.Radius
+	elevationRangeResponse.Attributes.MinElevation = elevationRangeRequest.Attributes.MinElevation
+	elevationRangeResponse.Attributes.MaxElevation = elevationRangeRequest.Attributes.MaxElevation
+
+	// verify request data
+	err = verifyElevationRangeRequestData(request, elevationRangeRequest)
+	if err != nil {
+		slog.WarnContext(request.Context(), "elevation range request: error verifying request data", "error", err, "ID", elevationRangeRequest.ID)
+		elevationRangeResponse.Attributes.Error = newErrorObject(EndpointElevationRange, ReasonVerifyingRequestData, err.Error())
+		buildElevationRangeResponse(writer, request, http.StatusBadRequest, elevationRangeResponse)
+		return
+	}
+
+	// circle around center point in UTM coordinates
+	isLonLat := elevationRangeRequest.Attributes.Zone == 0
+	zone, ring, err := getCircleRingUTM(elevationRangeRequest.Attributes.Zone, elevationRangeRequest.Attributes.Easting, elevationRangeRequest.Attributes.Northing,
+		elevationRangeRequest.Attributes.Longitude, elevationRangeRequest.Attributes.Latitude, elevationRangeRequest.Attributes.Radius)
+	if err != nil {
+		slog.WarnContext(request.Context(), "elevation range request: error transforming center point to UTM", "error", err, "ID", elevationRangeRequest.ID)
+		elevationRangeResponse.Attributes.Error = newErrorObject(EndpointElevationRange, ReasonVerifyingRequestData, err.Error())
+		buildElevationRangeResponse(writer, request, http.StatusBadRequest, elevationRangeResponse)
+		return
+	}
+	clipWKT, bounds := buildAreaPolygonWKT(ring)
+
+	// get all tiles (metadata) within circle
+	tiles, err := getAllTilesArea(zone, bounds)
+	if err != nil {
+		slog.WarnContext(request.Context(), "elevation range request: error getting GeoTIFF tiles for circle", "error", err, "zone", zone, "ID", elevationRangeRequest.ID)
+		if errors.Is(err, ErrOutsideCoverage) {
+			elevationRangeResponse.Attributes.Error = newErrorObject(EndpointElevationRange, ReasonOutsideCoverage, err.Error())
+			buildElevationRangeResponse(writer, request, http.StatusNotFound, elevationRangeResponse)
+			return
+		}
+		elevationRangeResponse.Attributes.Error = newErrorObject(EndpointElevationRange, ReasonVerifyingRequestData, err.Error())
+		buildElevationRangeResponse(writer, request, http.StatusBadRequest, elevationRangeResponse)
+		return
+	}
+
+	// conditional request: unchanged response (same parameters, same tiles) needs no processing
+	etag := buildETag("elevationrange", elevationRangeRequest, tiles)
+	if checkNotModified(writer, request, etag) {
+		return
+	}
+
+	// build elevation range polygons (mosaic of all tiles, progress reported as one step per tile)
+	addJobTiles(request.Context(), len(tiles))
+	elevationRange, err := generateElevationRangeObjectForArea(request.Context(), tiles, zone, clipWKT, bounds,
+		elevationRangeRequest.Attributes.MinElevation, elevationRangeRequest.Attributes.MaxElevation, isLonLat)
+	addJobTilesDone(request.Context(), len(tiles))
+	if err != nil {
+		slog.WarnContext(request.Context(), "elevation range request: error generating elevation range object", "error", err, "ID", elevationRangeRequest.ID)
+		if errors.Is(err, context.Canceled) {
+			// client disconnected, processing aborted, no response required
+			return
+		}
+		if errors.Is(err, ErrServerBusy) {
+			elevationRangeResponse.Attributes.Error = newErrorObject(EndpointElevationRange, ReasonServerBusy, err.Error())
+			writer.Header().Set("Retry-After", strconv.Itoa(getProgConfig().GDALJobQueue.RetryAfter))
+			buildElevationRangeResponse(writer, request, http.StatusTooManyRequests, elevationRangeResponse)
+			return
+		}
+		elevationRangeResponse.Attributes.Error = newErrorObject(EndpointElevationRange, ReasonGeneratingObject, err.Error())
+		buildElevationRangeResponse(writer, request, http.StatusBadRequest, elevationRangeResponse)
+		return
+	}
+	elevationRangeResponse.Attributes.ElevationRange = elevationRange
+	for _, tile := range tiles {
+		elevationRangeResponse.Attributes.AreaTiles = append(elevationRangeResponse.Attributes.AreaTiles, tile.Index)
+	}
+	addUsage(request.Context(), 0, len(tiles))
+
+	// success response
+	elevationRangeResponse.Attributes.IsError = false
+	setETag(writer, etag)
+	buildElevationRangeResponse(writer, request, http.StatusOK, elevationRangeResponse)
+}
+
+/*
+verifyElevationRangeRequestData verifies 'elevation range' request data.
+*/
+func verifyElevationRangeRequestData(request *http.Request, elevationRangeRequest ElevationRangeRequest) error {
+	// verify HTTP header
+	contentType := request.Header.Get("Content-Type")
+	if !strings.HasPrefix(strings.ToLower(contentType), "application/json") {
+		return fmt.Errorf("unexpected or missing HTTP header field Content-Type, value = [%s], expected 'application/json'", contentType)
+	}
+
+	// verify Type
+	if elevationRangeRequest.Type != TypeElevationRangeRequest {
+		return fmt.Errorf("unexpected request Type [%v]", elevationRangeRequest.Type)
+	}
+
+	// verify ID
+	if len(elevationRangeRequest.ID) > 1024 {
+		return errors.New("ID must be 0-1024 characters long")
+	}
+
+	// verify coordinates (either utm or lon/lat coordinates must be set)
+	attributes := elevationRangeRequest.Attributes
+	if attributes.Zone != 0 {
+		if attributes.Zone < 32 || attributes.Zone > 33 {
+			return errors.New("invalid zone for Germany")
+		}
+		if attributes.Easting < 100000 || attributes.Easting > 900000 || attributes.Northing < 5200000 || attributes.Northing > 6200000 {
+			return errors.New("invalid UTM coordinates for Germany")
+		}
+	} else {
+		if attributes.Longitude > 15.3 || attributes.Longitude < 5.5 {
+			return errors.New("invalid longitude for Germany")
+		}
+		if attributes.Latitude > 55.3 || attributes.Latitude < 47.0 {
+			return errors.New("invalid latitude for Germany")
+		}
+	}
+
+	// verify radius (limited by max number of area tiles)
+	if attributes.Radius < 100.0 || attributes.Radius > 4000.0 {
+		return errors.New("radius must be between 100.0 and 4000.0 meters")
+	}
+
+	// verify elevation band (Germany: from -3.5 m to 2962 m)
+	if attributes.MinElevation < -100.0 || attributes.MaxElevation > 3000.0 {
+		return errors.New("elevation band must be between -100.0 and 3000.0 meters")
+	}
+	if attributes.MinElevation >= attributes.MaxElevation {
+		return errors.New("min elevation must be less than max elevation")
+	}
+
+	return nil
+}
+
+/*
+buildElevationRangeResponse builds HTTP responses with specified status and body.
+*/
+func buildElevationRangeResponse(writer http.ResponseWriter, request *http.Request, httpStatus int, elevationRangeResponse ElevationRangeResponse) {
+	// response metadata (versions, processing duration, cache hit)
+	elevationRangeResponse.Meta = newResponseMeta(request.Context())
+
+	// encode (streaming) and send response
+	streamJSONResponse(writer, request, httpStatus, elevationRangeResponse, false)
+}
+
+/*
+getCircleRingUTM returns the UTM zone and the (closed) ring of a circle around a center point in UTM coordinates.
+For lon/lat input the zone is derived from the longitude of the center point.
+*/
+func getCircleRingUTM(zone int, easting, northing, longitude, latitude, radius float64) (int, [][2]float64, error) {
+	if zone == 0 {
+		// lon/lat input: zone 32 (6° - 12° E) or zone 33 (12° - 18° E)
+		zone = 32
+		if longitude >= 12.0 {
+			zone = 33
+		}
+		var err error
+		easting, northing, err = transformLonLatToUTM(longitude, latitude, 25800+zone)
+		if err != nil {
+			return 0, nil, fmt.Errorf("error [%w] at transformLonLatToUTM()", err)
+		}
+	}
+
+	ring := make([][2]float64, 0, elevationRangeCircleVertices+1)
+	for i := range elevationRangeCircleVertices {
+		angle := 2 * math.Pi * float64(i) / elevationRangeCircleVertices
+		ring = append(ring, [2]float64{easting + radius*math.Cos(angle), northing + radius*math.Sin(angle)})
+	}
+	ring = append(ring, ring[0])
+
+	return zone, ring, nil
+}
+
+/*
+generateElevationRangeObjectForArea builds the polygons of terrain within the elevation band for an area (circle)
+covered by several tiles:
+- build mosaic (VRT) of all tiles and crop it to the bounding box of the area
+- generate contour polygons for the fixed levels min and max elevation
+- select polygons of the band between min and max elevation and clip them to the area (convert to target SRS)
+*/
+func generateElevationRangeObjectForArea(ctx context.Context, tiles []TileMetadata, zone int, clipWKT string, bounds [4]float64,
+	minElevation, maxElevation float64, isLonLat bool) (ElevationRange, error) {
+	var elevationRange ElevationRange
+
+	// area index (e.g. 32_497_5670-32_503_5675)
+	areaIndex := tiles[0].Index + "-" + tiles[len(tiles)-1].Index
+
+	// lookup response cache
+	cacheKeyParts := []any{"elevationrange", clipWKT, minElevation, maxElevation, isLonLat}
+	for _, tile := range tiles {
+		cacheKeyParts = append(cacheKeyParts, tile.Index, tile.Actuality)
+	}
+	cacheKey := buildResponseCacheKey(cacheKeyParts...)
+	cached, found := responseCache.Get(cacheKey)
+	recordCacheLookup(ctx, found)
+	if found {
+		return cached.(ElevationRange), nil
+	}
+
+	// run operations in memory (/vsimem)
+	vsimemPrefix := dtm.NewVSIMemPrefix("elevationrange")
+	filenameVRT := vsimemPrefix + "mosaic.vrt"
+	filenameCroppedTif := vsimemPrefix + "mosaic.cropped.tif"
+	filenameUtmGeoJSON := vsimemPrefix + "mosaic.utm.geojson"
+	filenameClippedGeoJSON := vsimemPrefix + "mosaic.clipped.geojson"
+	defer removeVSIMemFiles(filenameVRT, filenameCroppedTif, filenameUtmGeoJSON, filenameClippedGeoJSON)
+
+	// gdalbuildvrt
+	tilePaths := make([]string, 0, len(tiles))
+	for _, tile := range tiles {
+		tilePaths = append(tilePaths, tile.Path)
+	}
+	err := dtm.BuildVRT(ctx, tilePaths, filenameVRT, nil)
+	if err != nil {
+		return elevationRange, fmt.Errorf("error [%w] at dtm.BuildVRT()", err)
+	}
+
+	// gdal_translate: crop mosaic to bounding box of area
+	margin := 2.0
+	err = dtm.Translate(ctx, filenameVRT, filenameCroppedTif, []string{"-of", "GTiff", "-projwin",
+		strconv.FormatFloat(bounds[0]-margin, 'f', -1, 64), strconv.FormatFloat(bounds[3]+margin, 'f', -1, 64),
+		strconv.FormatFloat(bounds[2]+margin, 'f', -1, 64), strconv.FormatFloat(bounds[1]-margin, 'f', -1, 64)})
+	if err != nil {
+		return elevationRange, fmt.Errorf("error [%w] at dtm.Translate()", err)
+	}
+
+	// gdal_contour -p (polygons between fixed levels)
+	minString := strconv.FormatFloat(minElevation, 'f', -1, 64)
+	maxString := strconv.FormatFloat(maxElevation, 'f', -1, 64)
+	nameOutputLayer := fmt.Sprintf("Höhenbereich %s - %s Meter für Gebiet %s", minString, maxString, areaIndex)
+	err = dtm.ContourPolygons(ctx, filenameCroppedTif, filenameUtmGeoJSON, nameOutputLayer, "HoeheMin", "HoeheMax", minElevation, maxElevation)
+	if err != nil {
+		return elevationRange, fmt.Errorf("error [%w] at dtm.ContourPolygons()", err)
+	}
+
+	// ogr2ogr: select elevation band, clip to area (clip geometry in source SRS)
+	where := fmt.Sprintf("HoeheMin >= %.3f AND HoeheMax <= %.3f", minElevation-0.001, maxElevation+0.001)
+	switches := []string{"-f", "GeoJSON", "-where", where, "-clipsrc", clipWKT}
+	if isLonLat {
+		switches = append(switches, "-s_srs", fmt.Sprintf("EPSG:258%d", zone), "-t_srs", "EPSG:4326")
+	}
+	err = dtm.VectorTranslate(ctx, filenameUtmGeoJSON, filenameClippedGeoJSON, switches)
+	if err != nil {
+		return elevationRange, fmt.Errorf("error [%w] at dtm.VectorTranslate()", err)
+	}
+
+	// read result file
+	data, err := dtm.ReadVSIMemFile(filenameClippedGeoJSON)
+	if err != nil {
+		return elevationRange, fmt.Errorf("error [%w] at dtm.ReadVSIMemFile()", err)
+	}
+
+	// distinct actualities, origins and attributions of all tiles
+	var actualities, origins, attributions []string
+	for _, tile := range tiles {
+		if !slices.Contains(actualities, tile.Actuality) {
+			actualities = append(actualities, tile.Actuality)
+		}
+		if slices.Contains(origins, tile.Source) {
+			continue
+		}
+		origins = append(origins, tile.Source)
+		attribution := "unknown"
+		resource, err := getElevationResource(tile.Source)
+		if err != nil {
+			slog.ErrorContext(ctx, "elevation range request: error getting elevation resource", "error", err, "source", tile.Source)
+		} else {
+			attribution = resource.Attribution
+		}
+		attributions = append(attributions, attribution)
+	}
+	slices.Sort(actualities)
+
+	// set elevation range return structure
+	elevationRange.Data = data
+	elevationRange.DataFormat = "geojson"
+	elevationRange.Filename = buildObjectFilename(areaIndex, "elevationrange", minString+"-"+maxString+"m", actualities[len(actualities)-1], elevationRange.DataFormat)
+	elevationRange.Actuality = strings.Join(actualities, ", ")
+	elevationRange.Origin = strings.Join(origins, ", ")
+	elevationRange.Attribution = strings.Join(attributions, "; ")
+	elevationRange.TileIndex = areaIndex
+
+	// add to response cache
+	responseCache.Add(cacheKey, elevationRange, len(elevationRange.Data))
+
+	return elevationRange, nil
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// TypeErrorCodesResponse is the type of the error codes response
+const TypeErrorCodesResponse = "ErrorCodesResponse"
+
+/*
+Error codes consist of the endpoint prefix and the reason suffix (three digits), e.g.
+5130 = hillshade (5) + server busy (130). Codes and symbolic names are stable and must not be reused.
+*/
+
+// ErrorEndpoint represents an endpoint (error code prefix) and the reasons of errors it may return.
+type ErrorEndpoint struct {
+	Prefix  int
+	Name    string // symbolic name, e.g. HILLSHADE
+	Path    string
+	Object  string // name of the generated object (title of 'generating object' errors)
+	Reasons []*ErrorReason
+}
+
+// ErrorReason represents the reason of an error (error code suffix).
+type ErrorReason struct {
+	Suffix     int
+	Name       string // symbolic name, e.g. SERVER_BUSY
+	Title      string // may contain '%s' for the name of the generated object
+	HTTPStatus int
+	Meaning    string
+}
+
+// ErrorCodeDefinition represents a registered error code (element of error codes response).
+type ErrorCodeDefinition struct {
+	Code       string
+	Name       string // stable symbolic name, e.g. HILLSHADE_SERVER_BUSY
+	Endpoint   string
+	Title      string
+	HTTPStatus int
+	Meaning    string
+}
+
+// ErrorCodesResponse represents all registered error codes.
+type ErrorCodesResponse struct {
+	Type       string
+	ID         string
+	Attributes struct {
+		ErrorCodes []ErrorCodeDefinition
+	}
+}
+
+// error reasons (error code suffixes)
+var (
+	ReasonRequestBodyTooLarge = &ErrorReason{0, "REQUEST_BODY_TOO_LARGE", "request body too large", http.StatusRequestEntityTooLarge,
+		"The request body exceeds the size limit of the endpoint."}
+	ReasonReadingRequestBody = &ErrorReason{20, "READING_REQUEST_BODY", "error reading request body", http.StatusBadRequest,
+		"The request body could not be read (e.g. connection aborted)."}
+	ReasonUnmarshalingRequestBody = &ErrorReason{40, "UNMARSHALING_REQUEST_BODY", "error unmarshaling request body", http.StatusBadRequest,
+		"The request body is not valid JSON or does not match the request structure."}
+	ReasonParsingQueryParameters = &ErrorReason{50, "PARSING_QUERY_PARAMETERS", "error parsing query parameters", http.StatusBadRequest,
+		"The URL query parameters of a GET request are missing or invalid."}
+	ReasonVerifyingRequestData = &ErrorReason{60, "VERIFYING_REQUEST_DATA", "error verifying request data", http.StatusBadRequest,
+		"The request data is invalid (e.g. headers, type, coordinates outside Germany, unsupported parameters)."}
+	ReasonOutsideCoverage = &ErrorReason{70, "OUTSIDE_COVERAGE", "coordinates outside data coverage", http.StatusNotFound,
+		"No elevation data for the coordinates, the error detail contains the nearest covered area (state)."}
+	ReasonGettingElevation = &ErrorReason{80, "GETTING_ELEVATION", "error getting elevation", http.StatusBadRequest,
+		"No elevation available for the coordinates (e.g. no tile, nodata value)."}
+	ReasonParsingGPX = &ErrorReason{80, "PARSING_GPX", "error parsing GPX data", http.StatusBadRequest,
+		"The GPX data is not valid (base64 or XML)."}
+	ReasonCalculatingElevationProfile = &ErrorReason{80, "CALCULATING_ELEVATION_PROFILE", "error calculating elevation profile", http.StatusInternalServerError,
+		"The elevation profile could not be calculated."}
+	ReasonGettingTileUTM = &ErrorReason{80, "GETTING_TILE_UTM", "getting GeoTIFF tile for UTM coordinates", http.StatusBadRequest,
+		"No tile available for the UTM coordinates."}
+	ReasonSourceUnavailable = &ErrorReason{90, "SOURCE_UNAVAILABLE", "elevation source temporarily unavailable", http.StatusServiceUnavailable,
+		"The elevation source (state) of the tile is temporarily disabled, retry later."}
+	ReasonAddingElevationToGPX = &ErrorReason{100, "ADDING_ELEVATION_TO_GPX", "critical error adding elevation to GPX", http.StatusBadRequest,
+		"The elevations could not be added to the GPX data."}
+	ReasonAnalyzingGPX = &ErrorReason{100, "ANALYZING_GPX", "error analyzing GPX data", http.StatusBadRequest,
+		"The GPX data could not be analyzed."}
+	ReasonGettingTileLonLat = &ErrorReason{100, "GETTING_TILE_LONLAT", "getting GeoTIFF tile for lon/lat coordinates", http.StatusBadRequest,
+		"No tile available for the lon/lat coordinates."}
+	ReasonSourceUnavailableLonLat = &ErrorReason{110, "SOURCE_UNAVAILABLE_LONLAT", "elevation source temporarily unavailable", http.StatusServiceUnavailable,
+		"The elevation source (state) of the tile is temporarily disabled, retry later."}
+	ReasonCreatingGPX = &ErrorReason{120, "CREATING_GPX", "error creating GPX track", http.StatusInternalServerError,
+		"The resulting GPX data could not be created."}
+	ReasonGeneratingObject = &ErrorReason{120, "GENERATING_OBJECT", "error generating %s object for tile", http.StatusBadRequest,
+		"The requested object could not be generated for any of the tiles."}
+	ReasonServerBusy = &ErrorReason{130, "SERVER_BUSY", "server busy", http.StatusTooManyRequests,
+		"Too many concurrent processing jobs, retry after the time given in the Retry-After header."}
+	ReasonPlaceNotFound = &ErrorReason{140, "PLACE_NOT_FOUND", "place not found", http.StatusNotFound,
+		"The place name (attribute 'Place') could not be geocoded to coordinates in Germany."}
+	ReasonGeocoderUnavailable = &ErrorReason{150, "GEOCODER_UNAVAILABLE", "geocoder unavailable", http.StatusServiceUnavailable,
+		"The geocoder for place names is temporarily unavailable, retry later or use coordinates."}
+	ReasonAdministrativeAreaNotFound = &ErrorReason{160, "ADMINISTRATIVE_AREA_NOT_FOUND", "administrative area not found", http.StatusNotFound,
+		"The administrative area (attribute 'AdministrativeArea') is not contained in the configured boundaries."}
+	ReasonJobNotFound = &ErrorReason{170, "JOB_NOT_FOUND", "job not found", http.StatusNotFound,
+		"The job is unknown, expired (see result ttl) or deleted."}
+	ReasonJobNotCompleted = &ErrorReason{180, "JOB_NOT_COMPLETED", "job not completed", http.StatusConflict,
+		"The result of the job is not available (job queued, running or canceled), poll the job status."}
+	ReasonTooManyJobs = &ErrorReason{190, "TOO_MANY_JOBS", "too many jobs", http.StatusTooManyRequests,
+		"The maximum number of jobs is reached, retry later or delete finished jobs."}
+	ReasonUploadFailed = &ErrorReason{200, "UPLOAD_FAILED", "upload failed", http.StatusBadGateway,
+		"The archive could not be uploaded to the configured storage (S3 bucket), retry later."}
+	ReasonReferenceNotConfigured = &ErrorReason{210, "REFERENCE_NOT_CONFIGURED", "reference DEM not configured", http.StatusNotImplemented,
+		"No reference DEM (e.g. Copernicus GLO-30) is configured for comparisons."}
+	ReasonGettingReferenceElevation = &ErrorReason{220, "GETTING_REFERENCE_ELEVATION", "error getting reference elevation", http.StatusNotFound,
+		"No elevation of the reference DEM available for the coordinates (e.g. outside extent, nodata value)."}
+	ReasonColorTableNotFound = &ErrorReason{230, "COLOR_TABLE_NOT_FOUND", "color table not found", http.StatusNotFound,
+		"The named color table is unknown (not stored by the tenant or deleted)."}
+	ReasonTooManyColorTables = &ErrorReason{240, "TOO_MANY_COLOR_TABLES", "too many color tables", http.StatusConflict,
+		"The maximum number of color tables of the tenant is reached, delete unused color tables."}
+	ReasonInternalServerError = &ErrorReason{0, "INTERNAL_SERVER_ERROR", "internal server error", http.StatusInternalServerError,
+		"Unexpected error while processing the request, please report the request id."}
+)
+
+// reasons of all requests (reading and verifying)
+var requestReasons = []*ErrorReason{ReasonRequestBodyTooLarge, ReasonReadingRequestBody, ReasonUnmarshalingRequestBody, ReasonVerifyingRequestData}
+
+// reasons of tile based requests (place geocoding, tile lookup and object generation)
+var tileReasons = []*ErrorReason{ReasonOutsideCoverage, ReasonGettingTileUTM, ReasonSourceUnavailable, ReasonGettingTileLonLat, ReasonSourceUnavailableLonLat,
+	ReasonGeneratingObject, ReasonServerBusy, ReasonPlaceNotFound, ReasonGeocoderUnavailable}
+
+// error endpoints (error code prefixes)
+var (
+	EndpointPoint            = &ErrorEndpoint{1, "POINT", "/v1/point", "", concatReasons(requestReasons, ReasonParsingQueryParameters, ReasonOutsideCoverage, ReasonGettingElevation, ReasonSourceUnavailable, ReasonPlaceNotFound, ReasonGeocoderUnavailable)}
+	EndpointGPX              = &ErrorEndpoint{2, "GPX", "/v1/gpx", "", concatReasons(requestReasons, ReasonParsingGPX, ReasonAddingElevationToGPX, ReasonCreatingGPX)}
+	EndpointUTMPoint         = &ErrorEndpoint{3, "UTMPOINT", "/v1/utmpoint", "", concatReasons(requestReasons, ReasonParsingQueryParameters, ReasonOutsideCoverage, ReasonGettingElevation, ReasonSourceUnavailable)}
+	EndpointContours         = &ErrorEndpoint{4, "CONTOURS", "/v1/contours", "contours", concatReasons(requestReasons, concatReasons(tileReasons, ReasonAdministrativeAreaNotFound)...)}
+	EndpointHillshade        = &ErrorEndpoint{5, "HILLSHADE", "/v1/hillshade", "hillshade", concatReasons(requestReasons, concatReasons(tileReasons, ReasonAdministrativeAreaNotFound)...)}
+	EndpointSlope            = &ErrorEndpoint{6, "SLOPE", "/v1/slope", "slope", concatReasons(requestReasons, concatReasons(tileReasons, ReasonAdministrativeAreaNotFound)...)}
+	EndpointAspect           = &ErrorEndpoint{7, "ASPECT", "/v1/aspect", "aspect", concatReasons(requestReasons, tileReasons...)}
+	EndpointTPI              = &ErrorEndpoint{8, "TPI", "/v1/tpi", "tpi", concatReasons(requestReasons, tileReasons...)}
+	EndpointTRI              = &ErrorEndpoint{9, "TRI", "/v1/tri", "tri", concatReasons(requestReasons, tileReasons...)}
+	EndpointRoughness        = &ErrorEndpoint{10, "ROUGHNESS", "/v1/roughness", "roughness", concatReasons(requestReasons, tileReasons...)}
+	EndpointRawTIF           = &ErrorEndpoint{11, "RAWTIF", "/v1/rawtif", "rawtif", concatReasons(requestReasons, ReasonOutsideCoverage, ReasonGettingTileUTM, ReasonSourceUnavailable, ReasonGeneratingObject)}
+	EndpointColorRelief      = &ErrorEndpoint{12, "COLORRELIEF", "/v1/colorrelief", "colorRelief", concatReasons(requestReasons, tileReasons...)}
+	EndpointHistogram        = &ErrorEndpoint{13, "HISTOGRAM", "/v1/histogram", "histogram", concatReasons(requestReasons, tileReasons...)}
+	EndpointElevationProfile = &ErrorEndpoint{14, "ELEVATIONPROFILE", "/v1/elevationprofile", "", concatReasons(requestReasons, ReasonOutsideCoverage, ReasonCalculatingElevationProfile, ReasonSourceUnavailable)}
+	EndpointVisualize        = &ErrorEndpoint{15, "VISUALIZE", "/v1/visualize", "visualization", concatReasons(requestReasons, tileReasons...)}
+	EndpointGPXAnalyze       = &ErrorEndpoint{16, "GPXANALYZE", "/v1/gpxanalyze", "", concatReasons(requestReasons, ReasonParsingGPX, ReasonAnalyzingGPX)}
+	EndpointJobs             = &ErrorEndpoint{17, "JOBS", "/v1/jobs", "", concatReasons(requestReasons, ReasonJobNotFound, ReasonJobNotCompleted, ReasonTooManyJobs)}
+	EndpointExport           = &ErrorEndpoint{18, "EXPORT", "/v1/export", "export", concatReasons(requestReasons, concatReasons(tileReasons, ReasonUploadFailed)...)}
+	EndpointCompare          = &ErrorEndpoint{19, "COMPARE", "/v1/compare", "", concatReasons(requestReasons, ReasonOutsideCoverage, ReasonGettingElevation, ReasonSourceUnavailable, ReasonPlaceNotFound, ReasonGeocoderUnavailable, ReasonReferenceNotConfigured, ReasonGettingReferenceElevation)}
+	EndpointAspectRose       = &ErrorEndpoint{20, "ASPECTROSE", "/v1/aspectrose", "aspect rose", concatReasons(requestReasons, tileReasons...)}
+	EndpointElevationRange   = &ErrorEndpoint{21, "ELEVATIONRANGE", "/v1/elevationrange", "elevation range", concatReasons(requestReasons, tileReasons...)}
+	EndpointContourLine      = &ErrorEndpoint{22, "CONTOURLINE", "/v1/contourline", "contour line", concatReasons(requestReasons, ReasonOutsideCoverage, ReasonGettingElevation, ReasonSourceUnavailable, ReasonGeneratingObject, ReasonServerBusy, ReasonPlaceNotFound, ReasonGeocoderUnavailable)}
+	EndpointElevationMatrix  = &ErrorEndpoint{23, "ELEVATIONMATRIX", "/v1/elevationmatrix", "elevation matrix", concatReasons(requestReasons, tileReasons...)}
+	EndpointLegend           = &ErrorEndpoint{24, "LEGEND", "/v1/legend", "legend", concatReasons(requestReasons, ReasonGeneratingObject)}
+	EndpointColorTables      = &ErrorEndpoint{25, "COLORTABLES", "/v1/colortables", "", concatReasons(requestReasons, ReasonColorTableNotFound, ReasonTooManyColorTables)}
+	EndpointContoursExport   = &ErrorEndpoint{26, "CONTOURSEXPORT", "/v1/contoursexport", "contours export", concatReasons(requestReasons, concatReasons(tileReasons, ReasonAdministrativeAreaNotFound, ReasonUploadFailed)...)}
+	EndpointService          = &ErrorEndpoint{99, "SERVICE", "", "", []*ErrorReason{ReasonInternalServerError}}
+)
+
+// errorEndpoints lists all endpoints of the error code registry
+var errorEndpoints = []*ErrorEndpoint{EndpointPoint, EndpointGPX, EndpointUTMPoint, EndpointContours, EndpointHillshade, EndpointSlope,
+	EndpointAspect, EndpointTPI, EndpointTRI, EndpointRoughness, EndpointRawTIF, EndpointColorRelief, EndpointHistogram,
+	EndpointElevationProfile, EndpointVisualize, EndpointGPXAnalyze, EndpointJobs, EndpointExport, EndpointCompare, EndpointAspectRose,
+	EndpointElevationRange, EndpointContourLine, EndpointElevationMatrix, EndpointLegend, EndpointColorTables, EndpointContoursExport, EndpointService}
+
+/*
+concatReasons returns a new list with all given reasons.
+*/
+func concatReasons(reasons []*ErrorReason, moreReasons ...*ErrorReason) []*ErrorReason {
+	all := make([]*ErrorReason, 0, len(reasons)+len(moreReasons))
+	all = append(all, reasons...)
+	return append(all, moreReasons...)
+}
+
+/*
+errorCode returns the numeric error code for the endpoint and reason.
+*/
+func errorCode(endpoint *ErrorEndpoint, reason *ErrorReason) string {
+	return strconv.Itoa(endpoint.Prefix*1000 + reason.Suffix)
+}
+
+/*
+errorTitle returns the title of the error for the endpoint and reason.
+*/
+func errorTitle(endpoint *ErrorEndpoint, reason *ErrorReason) string {
+	if strings.Contains(reason.Title, "%s") {
+		return fmt.Sprintf(reason.Title, endpoint.Object)
+	}
+	return reason.Title
+}
+
+/*
+newErrorObject builds the error object (code, title, detail) for the endpoint and reason.
+*/
+func newErrorObject(endpoint *ErrorEndpoint, reason *ErrorReason, detail string) ErrorObject {
+	return ErrorObject{
+		Code:   errorCode(endpoint, reason),
+		Title:  errorTitle(endpoint, reason),
+		Detail: detail,
+	}
+}
+
+/*
+getErrorCodeDefinitions returns all registered error codes (in order of endpoints and reasons).
+*/
+func getErrorCodeDefinitions() []ErrorCodeDefinition {
+	var definitions []ErrorCodeDefinition
+	for _, endpoint := range errorEndpoints {
+		for _, reason := range endpoint.Reasons {
+			definitions = append(definitions, ErrorCodeDefinition{
+				Code:       errorCode(endpoint, reason),
+				Name:       endpoint.Name + "_" + reason.Name,
+				Endpoint:   endpoint.Path,
+				Title:      errorTitle(endpoint, reason),
+				HTTPStatus: reason.HTTPStatus,
+				Meaning:    reason.Meaning,
+			})
+		}
+	}
+	return definitions
+}
+
+/*
+errorCodesRequest handles 'error codes' request (discovery of all error codes).
+*/
+func errorCodesRequest(writer http.ResponseWriter, request *http.Request) {
+	errorCodesResponse := ErrorCodesResponse{Type: TypeErrorCodesResponse, ID: progVersion}
+	errorCodesResponse.Attributes.ErrorCodes = getErrorCodeDefinitions()
+	streamJSONResponse(writer, request, http.StatusOK, errorCodesResponse, false)
+}
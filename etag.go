@@ -0,0 +1,57 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+/*
+buildETag builds a deterministic ETag for a response from the request parameters and the
+tiles (index and actuality) involved. The program version is included since output may change with it.
+*/
+func buildETag(endpoint string, requestData any, tiles []TileMetadata) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s|%s|", progVersion, endpoint)
+	data, err := json.Marshal(requestData)
+	if err == nil {
+		hash.Write(data)
+	}
+	for _, tile := range tiles {
+		fmt.Fprintf(hash, "|%s|%s", tile.Index, tile.Actuality)
+	}
+	return `"` + hex.EncodeToString(hash.Sum(nil))[:32] + `"`
+}
+
+/*
+checkNotModified answers a conditional request (If-None-Match) with '304 Not Modified' if the ETag matches.
+Returns true if the response has been sent.
+*/
+func checkNotModified(writer http.ResponseWriter, request *http.Request, etag string) bool {
+	ifNoneMatch := request.Header.Get("If-None-Match")
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		candidate = strings.TrimPrefix(candidate, "W/")
+		if candidate == "*" || candidate == etag {
+			setETag(writer, etag)
+			writer.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+setETag sets the ETag header (exposed to CORS clients by corsMiddleware).
+*/
+func setETag(writer http.ResponseWriter, etag string) {
+	writer.Header().Set("ETag", etag)
+}
@@ -0,0 +1,457 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// exportProducts lists the products of bulk exports
+var exportProducts = []string{"rawtif", "hillshade", "contours", "inspire"}
+
+// exportFile represents a file of an export archive
+type exportFile struct {
+	Name       string // path in archive (e.g. hillshade/32_497_5670_hillshade_regular_2024.tif)
+	Data       []byte
+	Compressed bool // data already compressed (stored without deflate, see CompressedDataFormats)
+}
+
+/*
+exportRequest handles 'export request' (bulk export, available as asynchronous job only).
+The selected products are generated for all tiles (area or list of tiles) and delivered as one ZIP archive
+including a readme file with attributions. Optionally the archive is uploaded to the configured S3 bucket.
+*/
+func exportRequest(writer http.ResponseWriter, request *http.Request) {
+	var exportResponse = ExportResponse{Type: TypeExportResponse, ID: "unknown"}
+	exportResponse.Attributes.IsError = true
+
+	// statistics
+	atomic.AddUint64(&ExportRequests, 1)
+
+	// limit overall request body size
+	request.Body = http.MaxBytesReader(writer, request.Body, MaxExportRequestBodySize)
+
+	// read request
+	bodyData, err := io.ReadAll(request.Body)
+	if err != nil {
+		// check specifically for the error returned by MaxBytesReader
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			slog.WarnContext(request.Context(), "export request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
+			exportResponse.Attributes.Error = newErrorObject(EndpointExport, ReasonRequestBodyTooLarge, fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit))
+			buildExportResponse(writer, request, http.StatusRequestEntityTooLarge, exportResponse)
+		} else {
+			// handle other read errors
+			slog.WarnContext(request.Context(), "export request: error reading request body", "error", err, "ID", "unknown")
+			exportResponse.Attributes.Error = newErrorObject(EndpointExport, ReasonReadingRequestBody, err.Error())
+			buildExportResponse(writer, request, http.StatusBadRequest, exportResponse)
+		}
+		return
+	}
+
+	// unmarshal request
+	exportRequest := ExportRequest{}
+	err = json.Unmarshal(bodyData, &exportRequest)
+	if err != nil {
+		slog.WarnContext(request.Context(), "export request: error unmarshaling request body", "error", err, "ID", "unknown")
+		exportResponse.Attributes.Error = newErrorObject(EndpointExport, ReasonUnmarshalingRequestBody, err.Error())
+		buildExportResponse(writer, request, http.StatusBadRequest, exportResponse)
+		return
+	}
+
+	// copy request parameters into response
+	exportResponse.ID = exportRequest.ID
+	exportResponse.Attributes.Products = exportRequest.Attributes.Products
+	exportResponse.Attributes.Zone = exportRequest.Attributes.Zone
+	exportResponse.Attributes.Area = exportRequest.Attributes.Area
+	exportResponse.Attributes.Tiles = exportRequest.Attributes.Tiles
+
+	// verify request data
+	err = verifyExportRequestData(request, exportRequest)
+	if err != nil {
+		slog.WarnContext(request.Context(), "export request: error verifying request data", "error", err, "ID", exportRequest.ID)
+		exportResponse.Attributes.Error = newErrorObject(EndpointExport, ReasonVerifyingRequestData, err.Error())
+		buildExportResponse(writer, request, http.StatusBadRequest, exportResponse)
+		return
+	}
+
+	// get all tiles (metadata) for area or list of tiles
+	tiles, err := getExportTiles(exportRequest)
+	if err != nil {
+		slog.WarnContext(request.Context(), "export request: error getting GeoTIFF tiles", "error", err, "ID", exportRequest.ID)
+		if errors.Is(err, ErrOutsideCoverage) {
+			exportResponse.Attributes.Error = newErrorObject(EndpointExport, ReasonOutsideCoverage, err.Error())
+			buildExportResponse(writer, request, http.StatusNotFound, exportResponse)
+			return
+		}
+		exportResponse.Attributes.Error = newErrorObject(EndpointExport, ReasonVerifyingRequestData, err.Error())
+		buildExportResponse(writer, request, http.StatusBadRequest, exportResponse)
+		return
+	}
+
+	// build products for all tiles
+	equidistance := exportRequest.Attributes.Equidistance
+	if equidistance == 0 {
+		equidistance = 10.0
+	}
+	shadingVariant := strings.ToLower(exportRequest.Attributes.ShadingVariant)
+	if shadingVariant == "" {
+		shadingVariant = "regular"
+	}
+	isLonLat := exportRequest.Attributes.Zone == 0
+	tileFiles, tileErrors, err := generateObjectsForTiles(request.Context(), tiles, func(tile TileMetadata) ([]exportFile, error) {
+		return generateExportFilesForTile(request.Context(), tile, exportRequest.Attributes.Products, equidistance, shadingVariant, isLonLat)
+	})
+	if err != nil {
+		slog.WarnContext(request.Context(), "export request: error generating export files for tile", "error", err, "ID", exportRequest.ID)
+		if errors.Is(err, context.Canceled) {
+			// client disconnected (job deleted), processing aborted, no response required
+			return
+		}
+		if errors.Is(err, ErrServerBusy) {
+			exportResponse.Attributes.Error = newErrorObject(EndpointExport, ReasonServerBusy, err.Error())
+			writer.Header().Set("Retry-After", strconv.Itoa(getProgConfig().GDALJobQueue.RetryAfter))
+			buildExportResponse(writer, request, http.StatusTooManyRequests, exportResponse)
+			return
+		}
+		exportResponse.Attributes.Error = newErrorObject(EndpointExport, ReasonGeneratingObject, err.Error())
+		buildExportResponse(writer, request, http.StatusBadRequest, exportResponse)
+		return
+	}
+	exportResponse.Attributes.TileErrors = tileErrors
+	if len(tileErrors) > 0 {
+		exportResponse.Attributes.Warnings = append(exportResponse.Attributes.Warnings,
+			fmt.Sprintf("%d of %d tiles could not be processed (see TileErrors)", len(tileErrors), len(tiles)))
+		slog.WarnContext(request.Context(), "export request: partial success, tiles could not be processed", "tile errors", tileErrors, "ID", exportRequest.ID)
+	}
+
+	// exported tiles (without failed tiles)
+	var exportedTiles []TileMetadata
+	for _, tile := range tiles {
+		if !slices.ContainsFunc(tileErrors, func(tileError TileError) bool { return tileError.TileIndex == tile.Index }) {
+			exportedTiles = append(exportedTiles, tile)
+			exportResponse.Attributes.ExportedTiles = append(exportResponse.Attributes.ExportedTiles, tile.Index)
+		}
+	}
+
+	// build ZIP archive (products and readme)
+	now := time.Now().UTC()
+	var files []exportFile
+	for _, filesOfTile := range tileFiles {
+		files = append(files, filesOfTile...)
+	}
+	readme := buildExportReadme(exportRequest, exportedTiles, equidistance, shadingVariant, now)
+	files = append(files, exportFile{Name: "README.txt", Data: []byte(readme)})
+	if slices.Contains(exportRequest.Attributes.Products, "inspire") && len(exportedTiles) > 0 {
+		metadata, err := buildINSPIREMetadata(exportedTiles, now)
+		if err != nil {
+			slog.ErrorContext(request.Context(), "export request: error building INSPIRE metadata", "error", err, "ID", exportRequest.ID)
+			exportResponse.Attributes.Error = newErrorObject(EndpointService, ReasonInternalServerError, "")
+			buildExportResponse(writer, request, http.StatusInternalServerError, exportResponse)
+			return
+		}
+		files = append(files, exportFile{Name: "inspire/metadata.xml", Data: metadata})
+	}
+	archive, err := buildZIPArchive(files, now)
+	if err != nil {
+		slog.ErrorContext(request.Context(), "export request: error building ZIP archive", "error", err, "ID", exportRequest.ID)
+		exportResponse.Attributes.Error = newErrorObject(EndpointService, ReasonInternalServerError, "")
+		buildExportResponse(writer, request, http.StatusInternalServerError, exportResponse)
+		return
+	}
+	exportResponse.Attributes.Filename = "dtm-export-" + now.Format("20060102T150405Z") + ".zip"
+	exportResponse.Attributes.DataFormat = "zip"
+
+	// deliver archive (upload to S3 bucket or in response)
+	if exportRequest.Attributes.Upload {
+		key := exportResponse.Attributes.Filename
+		if requestID := getRequestID(request.Context()); requestID != "" {
+			key = requestID + "/" + key
+		}
+		downloadURL, err := uploadToS3(request.Context(), key, "application/zip", archive)
+		if err != nil {
+			slog.WarnContext(request.Context(), "export request: error uploading ZIP archive", "error", err, "ID", exportRequest.ID)
+			exportResponse.Attributes.Error = newErrorObject(EndpointExport, ReasonUploadFailed, err.Error())
+			buildExportResponse(writer, request, http.StatusBadGateway, exportResponse)
+			return
+		}
+		exportResponse.Attributes.DownloadURL = downloadURL
+	} else {
+		exportResponse.Attributes.Data = archive
+	}
+	addUsage(request.Context(), 0, len(exportedTiles)*len(exportRequest.Attributes.Products))
+
+	// success response
+	exportResponse.Attributes.IsError = false
+	buildExportResponse(writer, request, http.StatusOK, exportResponse)
+}
+
+/*
+verifyExportRequestData verifies 'export' request data.
+*/
+func verifyExportRequestData(request *http.Request, exportRequest ExportRequest) error {
+	// verify HTTP header
+	contentType := request.Header.Get("Content-Type")
+	if !strings.HasPrefix(strings.ToLower(contentType), "application/json") {
+		return fmt.Errorf("unexpected or missing HTTP header field Content-Type, value = [%s], expected 'application/json'", contentType)
+	}
+
+	// verify Type
+	if exportRequest.Type != TypeExportRequest {
+		return fmt.Errorf("unexpected request Type [%v]", exportRequest.Type)
+	}
+
+	// verify ID
+	if len(exportRequest.ID) > 1024 {
+		return errors.New("ID must be 0-1024 characters long")
+	}
+
+	// verify products (1-3 different products)
+	if len(exportRequest.Attributes.Products) < 1 || len(exportRequest.Attributes.Products) > len(exportProducts) {
+		return fmt.Errorf("number of products must be between 1 and %d", len(exportProducts))
+	}
+	for i, product := range exportRequest.Attributes.Products {
+		if !slices.Contains(exportProducts, product) {
+			return fmt.Errorf("unsupported product [%s] (not %s)", product, strings.Join(exportProducts, ", "))
+		}
+		if slices.Contains(exportRequest.Attributes.Products[:i], product) {
+			return fmt.Errorf("duplicate product [%s]", product)
+		}
+	}
+
+	// verify zone for Germany (Zone: 32 or 33)
+	if exportRequest.Attributes.Zone != 0 {
+		if exportRequest.Attributes.Zone < 32 || exportRequest.Attributes.Zone > 33 {
+			return errors.New("invalid zone for Germany")
+		}
+	}
+
+	// verify area or list of tiles (exactly one of them)
+	maxTiles := getProgConfig().Export.MaxTiles
+	switch {
+	case exportRequest.Attributes.Area != nil && len(exportRequest.Attributes.Tiles) > 0:
+		return errors.New("either area or tiles must be set, not both")
+	case exportRequest.Attributes.Area != nil:
+		err := verifyContoursArea(exportRequest.Attributes.Zone, exportRequest.Attributes.Area)
+		if err != nil {
+			return err
+		}
+	case len(exportRequest.Attributes.Tiles) > 0:
+		if len(exportRequest.Attributes.Tiles) > maxTiles {
+			return fmt.Errorf("number of tiles must be between 1 and %d", maxTiles)
+		}
+	default:
+		return errors.New("area or tiles must be set")
+	}
+
+	// verify equidistance (contours)
+	if exportRequest.Attributes.Equidistance != 0 {
+		if exportRequest.Attributes.Equidistance < 0.2 || exportRequest.Attributes.Equidistance > 25.0 {
+			return errors.New("equidistance must be between 0.2 and 25.0 meters")
+		}
+	}
+
+	// verify shading variant (hillshade)
+	switch strings.ToLower(exportRequest.Attributes.ShadingVariant) {
+	case "", "regular", "combined", "multidirectional", "igor", "textured":
+	default:
+		return errors.New("unsupported shading variant (not regular, combined, multidirectional, igor, textured)")
+	}
+
+	// verify upload
+	if exportRequest.Attributes.Upload && getProgConfig().Export.S3.Endpoint == "" {
+		return errors.New("upload not supported (no storage configured)")
+	}
+
+	return nil
+}
+
+/*
+getExportTiles gets metadata for all (primary) tiles of the export area or the list of tile indices.
+*/
+func getExportTiles(exportRequest ExportRequest) ([]TileMetadata, error) {
+	maxTiles := getProgConfig().Export.MaxTiles
+
+	if exportRequest.Attributes.Area != nil {
+		zone, ring, err := getContoursAreaRingUTM(exportRequest.Attributes.Zone, exportRequest.Attributes.Area)
+		if err != nil {
+			return nil, fmt.Errorf("error [%w] at getContoursAreaRingUTM()", err)
+		}
+		_, bounds := buildAreaPolygonWKT(ring)
+		tiles, err := getAllTilesArea(zone, bounds)
+		if err != nil {
+			return nil, err
+		}
+		if len(tiles) > maxTiles {
+			return nil, fmt.Errorf("area contains %d tiles, max %d tiles supported for export", len(tiles), maxTiles)
+		}
+		return tiles, nil
+	}
+
+	var tiles []TileMetadata
+	repositoryMutex.RLock()
+	defer repositoryMutex.RUnlock()
+	for _, index := range exportRequest.Attributes.Tiles {
+		tile, found := Repository.Tile(index)
+		if !found {
+			return nil, fmt.Errorf("tile [%s] not found: %w", index, ErrOutsideCoverage)
+		}
+		if slices.ContainsFunc(tiles, func(t TileMetadata) bool { return t.Index == tile.Index }) {
+			return nil, fmt.Errorf("duplicate tile [%s]", index)
+		}
+		tiles = append(tiles, tile)
+	}
+
+	return tiles, nil
+}
+
+/*
+generateExportFilesForTile builds the export files (products) for a tile.
+*/
+func generateExportFilesForTile(ctx context.Context, tile TileMetadata, products []string, equidistance float64, shadingVariant string, isLonLat bool) ([]exportFile, error) {
+	var files []exportFile
+
+	for _, product := range products {
+		switch product {
+		case "rawtif":
+			rawtif, err := generateRawTIFObjectForTile(tile)
+			if err != nil {
+				return nil, fmt.Errorf("error [%w] at generateRawTIFObjectForTile()", err)
+			}
+			files = append(files, exportFile{Name: "rawtif/" + rawtif.Filename, Data: rawtif.Data, Compressed: isCompressedDataFormat(rawtif.DataFormat)})
+		case "hillshade":
+			hillshade, err := generateHillshadeObjectForTile(ctx, tile, "geotiff", GeoTIFFOptions{}, "Horn", 1.0, 315, 45, shadingVariant, nil)
+			if err != nil {
+				return nil, fmt.Errorf("error [%w] at generateHillshadeObjectForTile()", err)
+			}
+			files = append(files, exportFile{Name: "hillshade/" + hillshade.Filename, Data: hillshade.Data, Compressed: isCompressedDataFormat(hillshade.DataFormat)})
+		case "contours":
+			contour, err := generateContourObjectForTile(ctx, tile, equidistance, isLonLat, 0, newContourAttribute("", "", nil))
+			if err != nil {
+				return nil, fmt.Errorf("error [%w] at generateContourObjectForTile()", err)
+			}
+			files = append(files, exportFile{Name: "contours/" + contour.Filename, Data: contour.Data, Compressed: isCompressedDataFormat(contour.DataFormat)})
+		case "inspire":
+			rawtif, err := generateRawTIFObjectForTile(tile)
+			if err != nil {
+				return nil, fmt.Errorf("error [%w] at generateRawTIFObjectForTile()", err)
+			}
+			coverage, err := buildINSPIRECoverageGML(tile, rawtif.Filename)
+			if err != nil {
+				return nil, fmt.Errorf("error [%w] at buildINSPIRECoverageGML()", err)
+			}
+			files = append(files, exportFile{Name: "inspire/" + rawtif.Filename, Data: rawtif.Data, Compressed: isCompressedDataFormat(rawtif.DataFormat)})
+			files = append(files, exportFile{Name: "inspire/" + tile.Index + "_el.gml", Data: coverage})
+		}
+	}
+
+	return files, nil
+}
+
+/*
+buildExportReadme builds the readme file of an export archive (contents, parameters, attributions).
+*/
+func buildExportReadme(exportRequest ExportRequest, tiles []TileMetadata, equidistance float64, shadingVariant string, now time.Time) string {
+	var readme strings.Builder
+
+	fmt.Fprintf(&readme, "Digital Terrain Model (DTM) Export\n")
+	fmt.Fprintf(&readme, "==================================\n\n")
+	fmt.Fprintf(&readme, "Created   : %s\n", now.Format(time.RFC3339))
+	fmt.Fprintf(&readme, "Service   : %s %s\n", progName, progVersion)
+	if exportRequest.ID != "" {
+		fmt.Fprintf(&readme, "Request ID: %s\n", exportRequest.ID)
+	}
+
+	fmt.Fprintf(&readme, "\nContents\n--------\n")
+	for _, product := range exportRequest.Attributes.Products {
+		switch product {
+		case "rawtif":
+			fmt.Fprintf(&readme, "rawtif/    digital terrain model, original tiles (GeoTIFF, 1 m resolution, elevations in meters)\n")
+		case "hillshade":
+			fmt.Fprintf(&readme, "hillshade/ hillshade (GeoTIFF, shading variant %s, Horn, azimuth 315°, altitude 45°)\n", shadingVariant)
+		case "contours":
+			srs := "UTM"
+			if exportRequest.Attributes.Zone == 0 {
+				srs = "WGS84 lon/lat"
+			}
+			fmt.Fprintf(&readme, "contours/  contour lines (GeoJSON, %s, equidistance %.2f m)\n", srs, equidistance)
+		case "inspire":
+			fmt.Fprintf(&readme, "inspire/   INSPIRE Elevation (ElevationGridCoverage GML per tile with GeoTIFF range set, ISO 19139 metadata.xml)\n")
+		}
+	}
+
+	fmt.Fprintf(&readme, "\nAttribution (required for any use of the data)\n----------------------------------------------\n")
+	var attributions []string
+	for _, tile := range tiles {
+		attribution := "unknown (source " + tile.Source + ")"
+		resource, err := getElevationResource(tile.Source)
+		if err == nil {
+			attribution = resource.Attribution
+		}
+		if !slices.Contains(attributions, attribution) {
+			attributions = append(attributions, attribution)
+		}
+	}
+	slices.Sort(attributions)
+	for _, attribution := range attributions {
+		fmt.Fprintf(&readme, "%s\n", attribution)
+	}
+
+	fmt.Fprintf(&readme, "\nTiles\n-----\n")
+	for _, tile := range tiles {
+		fmt.Fprintf(&readme, "%-16s source: %-6s actuality: %s\n", tile.Index, tile.Source, tile.Actuality)
+	}
+
+	return readme.String()
+}
+
+/*
+buildZIPArchive builds a ZIP archive from files (already compressed data is stored without deflate).
+*/
+func buildZIPArchive(files []exportFile, modified time.Time) ([]byte, error) {
+	var buffer bytes.Buffer
+	zipWriter := zip.NewWriter(&buffer)
+
+	for _, file := range files {
+		header := &zip.FileHeader{Name: file.Name, Method: zip.Deflate, Modified: modified}
+		if file.Compressed {
+			header.Method = zip.Store
+		}
+		fileWriter, err := zipWriter.CreateHeader(header)
+		if err != nil {
+			return nil, fmt.Errorf("error [%w] at zipWriter.CreateHeader(), file: %s", err, file.Name)
+		}
+		_, err = fileWriter.Write(file.Data)
+		if err != nil {
+			return nil, fmt.Errorf("error [%w] at fileWriter.Write(), file: %s", err, file.Name)
+		}
+	}
+
+	err := zipWriter.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error [%w] at zipWriter.Close()", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+/*
+buildExportResponse builds HTTP responses with specified status and body.
+*/
+func buildExportResponse(writer http.ResponseWriter, request *http.Request, httpStatus int, exportResponse ExportResponse) {
+	// response metadata (versions, processing duration, cache hit)
+	exportResponse.Meta = newResponseMeta(request.Context())
+
+	// encode (streaming) and send response (ZIP archive is already compressed)
+	streamJSONResponse(writer, request, httpStatus, exportResponse, len(exportResponse.Attributes.Data) > 0)
+}
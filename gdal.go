@@ -1,11 +1,15 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
 	"math"
 	"strings"
 
 	"github.com/airbusgeo/godal"
+
+	"klaus/elevation/dtm-elevation-service/pkg/dtm"
 )
 
 /*
@@ -15,25 +19,12 @@ func transformLonLatToUTM(lon, lat float64, targetEPSG int) (float64, float64, e
 	var x float64
 	var y float64
 
-	// define source: WGS84 (EPSG:4326)
-	sourceSRS, err := godal.NewSpatialRefFromEPSG(4326)
-	if err != nil {
-		return x, y, fmt.Errorf("error creating source SRS (EPSG:4326): %w", err)
-	}
-	defer sourceSRS.Close()
-
-	// define target: dynamically calculated UTM Zone (e.g. 32632 for Zone 32N)
-	targetSRS, err := godal.NewSpatialRefFromEPSG(targetEPSG)
-	if err != nil {
-		return x, y, fmt.Errorf("error creating target SRS (EPSG:%d): %w", targetEPSG, err)
-	}
-	defer targetSRS.Close()
-
-	transform, err := godal.NewTransform(sourceSRS, targetSRS)
+	// get (cached) transformation from WGS84 (EPSG:4326) to UTM zone (e.g. 25832)
+	transform, err := acquireTransform(4326, targetEPSG)
 	if err != nil {
-		return x, y, fmt.Errorf("error creating coordinate transformation from EPSG:4326 to EPSG:%d: %w", targetEPSG, err)
+		return x, y, fmt.Errorf("error [%w] at acquireTransform()", err)
 	}
-	defer transform.Close()
+	defer releaseTransform(transform)
 
 	// define transformation parameters (e.g., slices of coordinates)
 	xCoords := []float64{lon} // longitude in WGS84
@@ -70,26 +61,12 @@ func transformUTMToLonLat(easting, northing float64, zone int) (float64, float64
 	// EPSG code for the given UTM zone
 	sourceEPSG := 32600 + zone
 
-	// define the source coordinate system (UTM)
-	sourceSRS, err := godal.NewSpatialRefFromEPSG(sourceEPSG)
-	if err != nil {
-		return longitude, latitude, fmt.Errorf("error creating the source SRS (EPSG:%d): %w", sourceEPSG, err)
-	}
-	defer sourceSRS.Close()
-
-	// define the target coordinate system: WGS84 (EPSG:4326)
-	targetSRS, err := godal.NewSpatialRefFromEPSG(4326)
-	if err != nil {
-		return longitude, latitude, fmt.Errorf("error creating the target SRS (EPSG:4326): %w", err)
-	}
-	defer targetSRS.Close()
-
-	// create the transformation
-	transform, err := godal.NewTransform(sourceSRS, targetSRS)
+	// get (cached) transformation from UTM to WGS84 (EPSG:4326)
+	transform, err := acquireTransform(sourceEPSG, 4326)
 	if err != nil {
-		return longitude, latitude, fmt.Errorf("error creating the coordinate transformation from EPSG:%d to EPSG:4326: %w", sourceEPSG, err)
+		return longitude, latitude, fmt.Errorf("error [%w] at acquireTransform()", err)
 	}
-	defer transform.Close()
+	defer releaseTransform(transform)
 
 	// define the coordinates to be transformed
 	xCoords := []float64{easting}
@@ -117,162 +94,59 @@ func transformUTMToLonLat(easting, northing float64, zone int) (float64, float64
 }
 
 /*
-getElevationFromUTM retrieves the elevation value from a GeoTIFF DGM file for a given UTM coordinate.
-
-Input:
-  - xUTM, yUTM: The UTM coordinates (Easting, Northing).
-    These coordinates MUST be in the SAME Coordinate Reference System (CRS) as the provided GeoTIFF file.
-  - filename: Path to the GeoTIFF file containing elevation data (e.g., DGM1).
-
-Output:
-- elevation: The elevation value at the specified coordinates (typically in meters).
-- err: if
-  - the file cannot be opened
-  - the coordinates are outside the file's extent
-  - the coordinate system is rotated (not supported by this simple implementation),
-  - the pixel value is the NoData value
-  - or any other reading error occurs.
+getElevationFromUTM retrieves the elevation value from a GeoTIFF DGM file for a given UTM coordinate
+(see dtm.ElevationFromFile). If the tile cache is enabled, the elevation grid of recently used tiles is kept in memory.
 */
-func getElevationFromUTM(xUTM, yUTM float64, filename string) (elevation float64, err error) {
-	// check if file exists
-	if !FileExists(filename) {
-		err = fmt.Errorf("file [%s] does not exist", filename)
-		return
+func getElevationFromUTM(xUTM, yUTM float64, filename string) (float64, error) {
+	if tileCache != nil {
+		grid, err := getTileGrid(filename)
+		if err != nil {
+			return 0, fmt.Errorf("error [%w] at getTileGrid()", err)
+		}
+		return grid.elevation(xUTM, yUTM)
 	}
+	return dtm.ElevationFromFile(xUTM, yUTM, filename)
+}
 
-	// open the raster file in ReadOnly mode
-	dataset, err := godal.Open(filename)
-	if err != nil {
-		err = fmt.Errorf("error opening file [%s]: %w", filename, err)
-		return
+/*
+getGeoTransformFromFile retrieves the geotransform of a GeoTIFF DGM file (from tile cache if enabled).
+*/
+func getGeoTransformFromFile(filename string) ([6]float64, error) {
+	if tileCache != nil {
+		grid, found := tileCache.get(filename)
+		if found {
+			return grid.geoTransform, nil
+		}
 	}
-	defer dataset.Close()
 
-	// get geotransform parameters
-	gt, err := dataset.GeoTransform()
+	_, _, gt, err := getRasterGrid(filename)
 	if err != nil {
-		err = fmt.Errorf("error getting geotransform from [%s]: %w", filename, err)
-		return
+		return [6]float64{}, fmt.Errorf("error [%w] at getRasterGrid()", err)
 	}
-
-	// basic check for rotation / skewing (this implementation assumes a north-up image)
-	// gt[2] and gt[4] should be 0 for a standard non-rotated/non-skewed grid
-	if gt[2] != 0.0 || gt[4] != 0.0 {
-		err = fmt.Errorf("raster [%s] appears to be rotated or skewed (gt[2]=%f, gt[4]=%f)", filename, gt[2], gt[4])
-		return
-	}
-
-	// calculate pixel coordinates from UTM coordinates using the inverse geotransform
-	// For non-rotated images:
-	// xUTM = gt[0] + col * gt[1] + row * gt[2]  (gt[2] is 0)
-	// yUTM = gt[3] + col * gt[4] + row * gt[5]  (gt[4] is 0)
-	// --> col = (xUTM - gt[0]) / gt[1]
-	// --> row = (yUTM - gt[3]) / gt[5]
-	// Note: Pixel height gt[5] is usually negative.
-
 	if gt[1] == 0 || gt[5] == 0 {
-		err = fmt.Errorf("invalid geotransform: pixel width (gt[1]=%f) or height (gt[5]=%f) is zero", gt[1], gt[5])
-		return
+		return [6]float64{}, fmt.Errorf("invalid geotransform: pixel width (gt[1]=%f) or height (gt[5]=%f) is zero", gt[1], gt[5])
 	}
 
-	colF := (xUTM - gt[0]) / gt[1]
-	rowF := (yUTM - gt[3]) / gt[5]
-
-	// get raster size
-	structure := dataset.Structure()
-	rasterWidth := structure.SizeX
-	rasterHeight := structure.SizeY
-
-	// convert float pixel coordinates to integer indices (top-left corner of the pixel)
-	col := int(math.Floor(colF))
-	row := int(math.Floor(rowF))
-
-	// check if the calculated pixel coordinates are within the raster bounds
-	if col < 0 || col >= rasterWidth || row < 0 || row >= rasterHeight {
-		err = fmt.Errorf("coordinate (%.3f, %.3f) is outside the raster bounds [%s] (pixel %d, %d)", xUTM, yUTM, filename, col, row)
-		return
-	}
+	return gt, nil
+}
 
-	// get the first raster band (assuming elevation is in the first band)
-	bands := dataset.Bands()
-	if len(bands) == 0 {
-		err = fmt.Errorf("no raster bands found in file [%s]", filename)
-		return
-	}
-	band := bands[0]
-	bandStructure := band.Structure()
-
-	// read the single pixel value at (col, row); create a buffer of appropriate data type to hold the pixel value
-	var pixelValue float64 // use float64 for intermediate storage
-
-	switch bandStructure.DataType {
-	case godal.Byte:
-		buffer := make([]byte, 1)
-		if err = band.Read(col, row, buffer, 1, 1); err != nil {
-			err = fmt.Errorf("error reading pixel (%d, %d) as Byte: %w", col, row, err)
-			return
-		}
-		pixelValue = float64(buffer[0])
-	case godal.Int16:
-		buffer := make([]int16, 1)
-		if err = band.Read(col, row, buffer, 1, 1); err != nil {
-			err = fmt.Errorf("error reading pixel (%d, %d) as Int16: %w", col, row, err)
-			return
-		}
-		pixelValue = float64(buffer[0])
-	case godal.UInt16:
-		buffer := make([]uint16, 1)
-		if err = band.Read(col, row, buffer, 1, 1); err != nil {
-			err = fmt.Errorf("error reading pixel (%d, %d) as UInt16: %w", col, row, err)
-			return
-		}
-		pixelValue = float64(buffer[0])
-	case godal.Int32:
-		buffer := make([]int32, 1)
-		if err = band.Read(col, row, buffer, 1, 1); err != nil {
-			err = fmt.Errorf("error reading pixel (%d, %d) as Int32: %w", col, row, err)
-			return
-		}
-		pixelValue = float64(buffer[0])
-	case godal.UInt32:
-		buffer := make([]uint32, 1)
-		if err = band.Read(col, row, buffer, 1, 1); err != nil {
-			err = fmt.Errorf("error reading pixel (%d, %d) as UInt32: %w", col, row, err)
-			return
-		}
-		pixelValue = float64(buffer[0])
-	case godal.Float32:
-		buffer := make([]float32, 1)
-		if err = band.Read(col, row, buffer, 1, 1); err != nil {
-			err = fmt.Errorf("error reading pixel (%d, %d) as Float32: %w", col, row, err)
-			return
-		}
-		pixelValue = float64(buffer[0])
-	case godal.Float64:
-		buffer := make([]float64, 1)
-		if err = band.Read(col, row, buffer, 1, 1); err != nil {
-			err = fmt.Errorf("error reading pixel (%d, %d) as Float64: %w", col, row, err)
-			return
-		}
-		pixelValue = buffer[0]
-	default:
-		err = fmt.Errorf("unsupported data type '%s' for band 1 in file [%s]", bandStructure.DataType, filename)
-		return
+/*
+getRasterGrid returns the size (columns, rows) and the geotransformation of a raster file.
+*/
+func getRasterGrid(filename string) (int, int, [6]float64, error) {
+	dataset, err := godal.Open(filename)
+	if err != nil {
+		return 0, 0, [6]float64{}, fmt.Errorf("error [%w] at godal.Open(), file %s", err, filename)
 	}
+	defer dataset.Close()
 
-	// check if the read value is the NoData value
-	if nodata, ok := band.NoData(); ok {
-		// compare floating point numbers with a small tolerance if needed, but direct comparison often works for NoData values
-		if pixelValue == nodata {
-			err = fmt.Errorf("coordinate (%.3f, %.3f) corresponds to a NoData value (%.3f) in [%s]", xUTM, yUTM, nodata, filename)
-			return
-		}
+	gt, err := dataset.GeoTransform()
+	if err != nil {
+		return 0, 0, [6]float64{}, fmt.Errorf("error [%w] at dataset.GeoTransform()", err)
 	}
 
-	// assign the result to the return variable
-	elevation = pixelValue
-
-	return // return named results (elevation, err)
+	structure := dataset.Structure()
+	return structure.SizeX, structure.SizeY, gt, nil
 }
 
 /*
@@ -340,26 +214,12 @@ func calculateWGS84BoundingBox(tile TileMetadata) (WGS84BoundingBox, error) {
 		return latLonBBox, fmt.Errorf("UTM zone [%v] from tile [%v] not supported ", parts[0], tile.Index)
 	}
 
-	// create source Spatial Reference System (SRS) from the provided EPSG (some tiles [e.g. for Sachsen-Anhalt] do not have SRS metadata)
-	srcSRS, err := godal.NewSpatialRefFromEPSG(sourceEPSG)
-	if err != nil {
-		return latLonBBox, fmt.Errorf("error creating source SRS from EPSG:%d: %w", sourceEPSG, err)
-	}
-	defer srcSRS.Close()
-
-	// create target Spatial Reference System (WGS84, EPSG:4326)
-	tgtSRS, err := godal.NewSpatialRefFromEPSG(4326)
-	if err != nil {
-		return latLonBBox, fmt.Errorf("error [%s] at godal.NewSpatialRefFromEPSG(4326)", err)
-	}
-	defer tgtSRS.Close()
-
-	// create the coordinate transformation transformer [2]
-	transformer, err := godal.NewTransform(srcSRS, tgtSRS)
+	// get (cached) transformation from source SRS to WGS84 (some tiles [e.g. for Sachsen-Anhalt] do not have SRS metadata) [2]
+	transformer, err := acquireTransform(sourceEPSG, 4326)
 	if err != nil {
-		return latLonBBox, fmt.Errorf("error [%s] at godal.NewTransform()", err)
+		return latLonBBox, fmt.Errorf("error [%w] at acquireTransform()", err)
 	}
-	defer transformer.Close()
+	defer releaseTransform(transformer)
 
 	// transform the source corner coordinates to WGS84 (godal.TransformEx transforms the slices in-place)
 	latLonXCoords := make([]float64, 4) // will contain Lon
@@ -398,3 +258,108 @@ func calculateWGS84BoundingBox(tile TileMetadata) (WGS84BoundingBox, error) {
 
 	return latLonBBox, nil
 }
+
+/*
+removeVSIMemFiles removes in-memory (/vsimem) files (nonexistent files are ignored) and their provenance entries.
+*/
+func removeVSIMemFiles(filenames ...string) {
+	dtm.RemoveVSIMemFiles(filenames...)
+	for _, filename := range filenames {
+		virtualSourceTiles.Delete(filename)
+	}
+}
+
+/*
+verifyGeoTIFFOptions verifies the creation options of returned GeoTIFF files.
+*/
+func verifyGeoTIFFOptions(options GeoTIFFOptions) error {
+	switch strings.ToLower(options.Compression) {
+	case "", "none", "deflate", "zstd", "lzw":
+	default:
+		return errors.New("unsupported GeoTIFF compression (not none, deflate, zstd, lzw)")
+	}
+
+	// floating point predictor (3) is not supported, returned GeoTIFF files contain byte data (e.g. RGBA)
+	switch options.Predictor {
+	case 0, 1:
+	case 2:
+		if options.Compression == "" || strings.EqualFold(options.Compression, "none") {
+			return errors.New("GeoTIFF predictor requires compression (deflate, zstd, lzw)")
+		}
+	default:
+		return errors.New("unsupported GeoTIFF predictor (not 1, 2)")
+	}
+
+	return nil
+}
+
+/*
+buildGeoTIFFCreationOptions builds the GDAL creation options (-co) for GeoTIFF output files.
+*/
+func buildGeoTIFFCreationOptions(options GeoTIFFOptions) []string {
+	var switches []string
+	compression := strings.ToUpper(options.Compression)
+	if compression != "" && compression != "NONE" {
+		switches = append(switches, "-co", "COMPRESS="+compression)
+		if options.Predictor > 1 {
+			switches = append(switches, "-co", fmt.Sprintf("PREDICTOR=%d", options.Predictor))
+		}
+	}
+	if options.Tiled {
+		switches = append(switches, "-co", "TILED=YES")
+	}
+	return switches
+}
+
+// min GDAL version (GDALContourGenerateEx, utility library functions)
+const (
+	minGDALMajor    = 3
+	minGDALMinor    = 1
+	minGDALRevision = 0
+)
+
+// requiredGDALDrivers lists the GDAL drivers required by the endpoints (driver name: vector or raster)
+var requiredGDALDrivers = []struct {
+	name   string
+	vector bool
+	usage  string
+}{
+	{"GTiff", false, "all raster endpoints (input tiles, intermediate files)"},
+	{"PNG", false, "raster endpoints with output format 'png'"},
+	{"VRT", false, "contours for area (mosaic of tiles)"},
+	{"GeoJSON", true, "contours"},
+}
+
+/*
+checkGDALDependencies logs the version of the GDAL library and verifies the minimum version and the
+availability of all required drivers. The service refuses to start if a dependency is missing,
+rather than failing at request time.
+*/
+func checkGDALDependencies() error {
+	version := godal.Version()
+	slog.Info("GDAL library", "version", fmt.Sprintf("%d.%d.%d", version.Major(), version.Minor(), version.Revision()))
+
+	if !godal.CheckMinVersion(minGDALMajor, minGDALMinor, minGDALRevision) {
+		return fmt.Errorf("GDAL version %d.%d.%d found, min version %d.%d.%d required", version.Major(), version.Minor(),
+			version.Revision(), minGDALMajor, minGDALMinor, minGDALRevision)
+	}
+
+	missingDrivers := []string{}
+	for _, driver := range requiredGDALDrivers {
+		var found bool
+		if driver.vector {
+			_, found = godal.VectorDriver(godal.DriverName(driver.name))
+		} else {
+			_, found = godal.RasterDriver(godal.DriverName(driver.name))
+		}
+		if !found {
+			slog.Error("required GDAL driver not available", "driver", driver.name, "required for", driver.usage)
+			missingDrivers = append(missingDrivers, driver.name)
+		}
+	}
+	if len(missingDrivers) > 0 {
+		return fmt.Errorf("required GDAL drivers not available: %s", strings.Join(missingDrivers, ", "))
+	}
+
+	return nil
+}
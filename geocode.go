@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// supported geocoder providers
+const (
+	geocoderNominatim = "nominatim"
+	geocoderPhoton    = "photon"
+)
+
+// limits for geocoding
+const (
+	maxPlaceLength          = 256
+	maxGeocoderResponseSize = 1024 * 1024
+	maxGeocodedPlaces       = 4096
+)
+
+// geocoding errors
+var (
+	ErrPlaceNotFound       = errors.New("place not found")
+	ErrGeocoderUnavailable = errors.New("geocoder unavailable")
+)
+
+// GeocodedPlace represents the result of geocoding a place name.
+type GeocodedPlace struct {
+	Longitude   float64
+	Latitude    float64
+	DisplayName string
+	expires     time.Time
+}
+
+// geocodedPlaces caches geocoding results (public geocoders require caching and limit the request rate)
+var (
+	geocodedPlacesMutex sync.Mutex
+	geocodedPlaces      = make(map[string]GeocodedPlace)
+)
+
+// geocoderClient is the HTTP client for geocoder requests (timeout per request from configuration)
+var geocoderClient = &http.Client{}
+
+/*
+geocodePlace resolves a place name (e.g. "Feldberg") into lon/lat coordinates with the configured geocoder
+(Nominatim or Photon API). Results are restricted to Germany and cached.
+*/
+func geocodePlace(ctx context.Context, place string) (GeocodedPlace, error) {
+	config := getProgConfig().Geocoder
+
+	place = strings.TrimSpace(place)
+	if len(place) > maxPlaceLength {
+		return GeocodedPlace{}, fmt.Errorf("place must be 1-%d characters long", maxPlaceLength)
+	}
+	if config.Provider == "" {
+		return GeocodedPlace{}, errors.New("geocoding of place names not enabled")
+	}
+
+	// lookup cache
+	cacheKey := strings.ToLower(place)
+	geocodedPlacesMutex.Lock()
+	cached, found := geocodedPlaces[cacheKey]
+	geocodedPlacesMutex.Unlock()
+	if found && time.Now().Before(cached.expires) {
+		return cached, nil
+	}
+
+	// build geocoder request
+	query := url.Values{}
+	query.Set("q", place)
+	query.Set("limit", "1")
+	switch config.Provider {
+	case geocoderNominatim:
+		query.Set("format", "jsonv2")
+		query.Set("countrycodes", "de")
+	case geocoderPhoton:
+		// bounding box of Germany (minLon,minLat,maxLon,maxLat)
+		query.Set("bbox", "5.5,47.0,15.3,55.3")
+	default:
+		return GeocodedPlace{}, fmt.Errorf("unsupported geocoder provider [%s]", config.Provider)
+	}
+
+	timeout := time.Duration(config.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, config.URL+"?"+query.Encode(), nil)
+	if err != nil {
+		return GeocodedPlace{}, fmt.Errorf("error [%w] at http.NewRequestWithContext()", err)
+	}
+	request.Header.Set("Accept", "application/json")
+	userAgent := config.UserAgent
+	if userAgent == "" {
+		userAgent = progName + "/" + progVersion
+	}
+	request.Header.Set("User-Agent", userAgent)
+
+	response, err := geocoderClient.Do(request)
+	if err != nil {
+		return GeocodedPlace{}, fmt.Errorf("%w: error [%w] at client.Do()", ErrGeocoderUnavailable, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return GeocodedPlace{}, fmt.Errorf("%w: unexpected HTTP status [%d]", ErrGeocoderUnavailable, response.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(response.Body, maxGeocoderResponseSize))
+	if err != nil {
+		return GeocodedPlace{}, fmt.Errorf("%w: error [%w] at io.ReadAll()", ErrGeocoderUnavailable, err)
+	}
+
+	// parse geocoder response
+	var geocodedPlace GeocodedPlace
+	switch config.Provider {
+	case geocoderNominatim:
+		geocodedPlace, err = parseNominatimResponse(data)
+	case geocoderPhoton:
+		geocodedPlace, err = parsePhotonResponse(data)
+	}
+	if err != nil {
+		return GeocodedPlace{}, fmt.Errorf("place [%s]: %w", place, err)
+	}
+
+	// add to cache
+	cacheTTL := time.Duration(config.CacheTTL) * time.Second
+	if cacheTTL > 0 {
+		geocodedPlace.expires = time.Now().Add(cacheTTL)
+		geocodedPlacesMutex.Lock()
+		if len(geocodedPlaces) >= maxGeocodedPlaces {
+			clear(geocodedPlaces)
+		}
+		geocodedPlaces[cacheKey] = geocodedPlace
+		geocodedPlacesMutex.Unlock()
+	}
+
+	return geocodedPlace, nil
+}
+
+/*
+parseNominatimResponse parses the response of the Nominatim search API (format 'jsonv2').
+*/
+func parseNominatimResponse(data []byte) (GeocodedPlace, error) {
+	var geocodedPlace GeocodedPlace
+	var results []struct {
+		Lat         string `json:"lat"`
+		Lon         string `json:"lon"`
+		DisplayName string `json:"display_name"`
+	}
+	err := json.Unmarshal(data, &results)
+	if err != nil {
+		return geocodedPlace, fmt.Errorf("%w: error [%w] at json.Unmarshal()", ErrGeocoderUnavailable, err)
+	}
+	if len(results) == 0 {
+		return geocodedPlace, ErrPlaceNotFound
+	}
+
+	geocodedPlace.Longitude, err = strconv.ParseFloat(results[0].Lon, 64)
+	if err != nil {
+		return geocodedPlace, fmt.Errorf("%w: invalid longitude [%s]", ErrGeocoderUnavailable, results[0].Lon)
+	}
+	geocodedPlace.Latitude, err = strconv.ParseFloat(results[0].Lat, 64)
+	if err != nil {
+		return geocodedPlace, fmt.Errorf("%w: invalid latitude [%s]", ErrGeocoderUnavailable, results[0].Lat)
+	}
+	geocodedPlace.DisplayName = results[0].DisplayName
+
+	return geocodedPlace, nil
+}
+
+/*
+parsePhotonResponse parses the response of the Photon API (GeoJSON feature collection).
+*/
+func parsePhotonResponse(data []byte) (GeocodedPlace, error) {
+	var geocodedPlace GeocodedPlace
+	var featureCollection struct {
+		Features []struct {
+			Geometry struct {
+				Coordinates []float64 `json:"coordinates"`
+			} `json:"geometry"`
+			Properties struct {
+				Name    string `json:"name"`
+				City    string `json:"city"`
+				State   string `json:"state"`
+				Country string `json:"country"`
+			} `json:"properties"`
+		} `json:"features"`
+	}
+	err := json.Unmarshal(data, &featureCollection)
+	if err != nil {
+		return geocodedPlace, fmt.Errorf("%w: error [%w] at json.Unmarshal()", ErrGeocoderUnavailable, err)
+	}
+	if len(featureCollection.Features) == 0 {
+		return geocodedPlace, ErrPlaceNotFound
+	}
+
+	feature := featureCollection.Features[0]
+	if len(feature.Geometry.Coordinates) < 2 {
+		return geocodedPlace, fmt.Errorf("%w: invalid feature geometry", ErrGeocoderUnavailable)
+	}
+	geocodedPlace.Longitude = feature.Geometry.Coordinates[0]
+	geocodedPlace.Latitude = feature.Geometry.Coordinates[1]
+
+	// display name from (non-empty) name parts, e.g. "Feldberg, Baden-Württemberg, Deutschland"
+	nameParts := []string{}
+	for _, part := range []string{feature.Properties.Name, feature.Properties.City, feature.Properties.State, feature.Properties.Country} {
+		if part != "" {
+			nameParts = append(nameParts, part)
+		}
+	}
+	geocodedPlace.DisplayName = strings.Join(nameParts, ", ")
+
+	return geocodedPlace, nil
+}
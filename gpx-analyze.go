@@ -8,9 +8,11 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"math"
 	"net/http"
 	"strings"
 	"sync/atomic"
+	"time"
 
 	"github.com/tkrajina/gpxgo/gpx"
 )
@@ -34,18 +36,14 @@ func gpxAnalyzeRequest(writer http.ResponseWriter, request *http.Request) {
 		// check specifically for the error returned by MaxBytesReader
 		var maxBytesErr *http.MaxBytesError
 		if errors.As(err, &maxBytesErr) {
-			slog.Warn("gpx analyze request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
-			gpxAnalyzeResponse.Attributes.Error.Code = "8000"
-			gpxAnalyzeResponse.Attributes.Error.Title = "request body too large"
-			gpxAnalyzeResponse.Attributes.Error.Detail = fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit)
-			buildGpxAnalyzeResponse(writer, http.StatusRequestEntityTooLarge, gpxAnalyzeResponse)
+			slog.WarnContext(request.Context(), "gpx analyze request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
+			gpxAnalyzeResponse.Attributes.Error = newErrorObject(EndpointGPXAnalyze, ReasonRequestBodyTooLarge, fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit))
+			buildGpxAnalyzeResponse(writer, request, http.StatusRequestEntityTooLarge, gpxAnalyzeResponse)
 		} else {
 			// handle other read errors
-			slog.Warn("gpx analyze request: error reading request body", "error", err, "ID", "unknown")
-			gpxAnalyzeResponse.Attributes.Error.Code = "8020"
-			gpxAnalyzeResponse.Attributes.Error.Title = "error reading request body"
-			gpxAnalyzeResponse.Attributes.Error.Detail = err.Error()
-			buildGpxAnalyzeResponse(writer, http.StatusBadRequest, gpxAnalyzeResponse)
+			slog.WarnContext(request.Context(), "gpx analyze request: error reading request body", "error", err, "ID", "unknown")
+			gpxAnalyzeResponse.Attributes.Error = newErrorObject(EndpointGPXAnalyze, ReasonReadingRequestBody, err.Error())
+			buildGpxAnalyzeResponse(writer, request, http.StatusBadRequest, gpxAnalyzeResponse)
 		}
 		return
 	}
@@ -54,25 +52,22 @@ func gpxAnalyzeRequest(writer http.ResponseWriter, request *http.Request) {
 	gpxAnalyzeRequest := GPXAnalyzeRequest{}
 	err = json.Unmarshal(bodyData, &gpxAnalyzeRequest)
 	if err != nil {
-		slog.Warn("gpx analyze request: error unmarshaling request body", "error", err, "ID", "unknown")
-		gpxAnalyzeResponse.Attributes.Error.Code = "8040"
-		gpxAnalyzeResponse.Attributes.Error.Title = "error unmarshaling request body"
-		gpxAnalyzeResponse.Attributes.Error.Detail = err.Error()
-		buildGpxAnalyzeResponse(writer, http.StatusBadRequest, gpxAnalyzeResponse)
+		slog.WarnContext(request.Context(), "gpx analyze request: error unmarshaling request body", "error", err, "ID", "unknown")
+		gpxAnalyzeResponse.Attributes.Error = newErrorObject(EndpointGPXAnalyze, ReasonUnmarshalingRequestBody, err.Error())
+		buildGpxAnalyzeResponse(writer, request, http.StatusBadRequest, gpxAnalyzeResponse)
 		return
 	}
 
 	// copy request parameters into response
 	gpxAnalyzeResponse.ID = gpxAnalyzeRequest.ID
+	gpxAnalyzeResponse.Attributes.Mode = gpxAnalyzeRequest.Attributes.Mode
 
 	// verify request data
 	err = verifyGpxAnalyzeRequestData(request, gpxAnalyzeRequest)
 	if err != nil {
-		slog.Warn("gpx analyze request: error verifying request data", "error", err, "ID", gpxAnalyzeRequest.ID)
-		gpxAnalyzeResponse.Attributes.Error.Code = "8060"
-		gpxAnalyzeResponse.Attributes.Error.Title = "error verifying request data"
-		gpxAnalyzeResponse.Attributes.Error.Detail = err.Error()
-		buildGpxAnalyzeResponse(writer, http.StatusBadRequest, gpxAnalyzeResponse)
+		slog.WarnContext(request.Context(), "gpx analyze request: error verifying request data", "error", err, "ID", gpxAnalyzeRequest.ID)
+		gpxAnalyzeResponse.Attributes.Error = newErrorObject(EndpointGPXAnalyze, ReasonVerifyingRequestData, err.Error())
+		buildGpxAnalyzeResponse(writer, request, http.StatusBadRequest, gpxAnalyzeResponse)
 		return
 	}
 
@@ -80,29 +75,63 @@ func gpxAnalyzeRequest(writer http.ResponseWriter, request *http.Request) {
 	gpxBytes, _ := base64.StdEncoding.DecodeString(gpxAnalyzeRequest.Attributes.GPXData) // error already checked in verifyGpxAnalyzeRequestData()
 	gpxData, err := gpx.ParseBytes(gpxBytes)
 	if err != nil {
-		slog.Warn("gpx analyze request: error parsing GPX data", "error", err, "ID", gpxAnalyzeRequest.ID)
-		gpxAnalyzeResponse.Attributes.Error.Code = "8080"
-		gpxAnalyzeResponse.Attributes.Error.Title = "error parsing GPX data"
-		gpxAnalyzeResponse.Attributes.Error.Detail = err.Error()
-		buildGpxAnalyzeResponse(writer, http.StatusBadRequest, gpxAnalyzeResponse)
+		slog.WarnContext(request.Context(), "gpx analyze request: error parsing GPX data", "error", err, "ID", gpxAnalyzeRequest.ID)
+		gpxAnalyzeResponse.Attributes.Error = newErrorObject(EndpointGPXAnalyze, ReasonParsingGPX, err.Error())
+		buildGpxAnalyzeResponse(writer, request, http.StatusBadRequest, gpxAnalyzeResponse)
 		return
 	}
 
-	gpxAnalyzeResult, err := analyzeGpxData(gpxData)
+	// validation mode: compare original elevations with DGM elevations (track unchanged)
+	if strings.ToLower(gpxAnalyzeRequest.Attributes.Mode) == "validate" {
+		gpxValidationResult := validateGpxData(gpxData, gpxAnalyzeRequest.ID)
+		if gpxValidationResult.PointsWithoutElevation > 0 {
+			gpxAnalyzeResponse.Attributes.Warnings = append(gpxAnalyzeResponse.Attributes.Warnings,
+				fmt.Sprintf("%d of %d points without original elevation, not validated", gpxValidationResult.PointsWithoutElevation, gpxValidationResult.Points))
+		}
+		if gpxValidationResult.PointsWithoutDGM > 0 {
+			gpxAnalyzeResponse.Attributes.Warnings = append(gpxAnalyzeResponse.Attributes.Warnings,
+				fmt.Sprintf("%d of %d points without DGM elevation (outside coverage or nodata), not validated", gpxValidationResult.PointsWithoutDGM, gpxValidationResult.Points))
+		}
+		addUsage(request.Context(), gpxValidationResult.Points, 0)
+
+		// successful response
+		gpxAnalyzeResponse.Attributes.GPXData = base64.StdEncoding.EncodeToString(gpxBytes)
+		gpxAnalyzeResponse.Attributes.GpxValidationResult = gpxValidationResult
+		gpxAnalyzeResponse.Attributes.IsError = false
+		buildGpxAnalyzeResponse(writer, request, http.StatusOK, gpxAnalyzeResponse)
+		return
+	}
+
+	gpxAnalyzeResult, err := analyzeGpxData(gpxData, gpxAnalyzeRequest.ID, gpxAnalyzeRequest.Attributes.SunExposure)
 	if err != nil {
-		slog.Warn("gpx analyze request: error analyzing GPX data", "error", err, "ID", gpxAnalyzeRequest.ID)
-		gpxAnalyzeResponse.Attributes.Error.Code = "8100"
-		gpxAnalyzeResponse.Attributes.Error.Title = "error analyzing GPX data"
-		gpxAnalyzeResponse.Attributes.Error.Detail = err.Error()
-		buildGpxAnalyzeResponse(writer, http.StatusBadRequest, gpxAnalyzeResponse)
+		slog.WarnContext(request.Context(), "gpx analyze request: error analyzing GPX data", "error", err, "ID", gpxAnalyzeRequest.ID)
+		gpxAnalyzeResponse.Attributes.Error = newErrorObject(EndpointGPXAnalyze, ReasonAnalyzingGPX, err.Error())
+		buildGpxAnalyzeResponse(writer, request, http.StatusBadRequest, gpxAnalyzeResponse)
 		return
 	}
 
+	if gpxAnalyzeRequest.Attributes.SunExposure != nil {
+		sampledPoints := 0
+		for _, track := range gpxAnalyzeResult.Tracks {
+			for _, segment := range track.Segments {
+				sampledPoints += segment.SunExposure.SampledPoints
+			}
+		}
+		if sampledPoints == 0 && gpxAnalyzeResult.TotalPoints > 0 {
+			gpxAnalyzeResponse.Attributes.Warnings = append(gpxAnalyzeResponse.Attributes.Warnings,
+				"no track points with DGM data and time (timestamps or StartTime), sun exposure not calculated")
+		}
+	}
+	if gpxAnalyzeResult.Ruggedness == nil && gpxAnalyzeResult.TotalPoints > 0 {
+		gpxAnalyzeResponse.Attributes.Warnings = append(gpxAnalyzeResponse.Attributes.Warnings,
+			"no DGM data along tracks (outside coverage or nodata), ruggedness not calculated")
+	}
+
 	// successful response
 	gpxAnalyzeResponse.Attributes.GPXData = base64.StdEncoding.EncodeToString(gpxBytes)
 	gpxAnalyzeResponse.Attributes.GpxAnalyzeResult = *gpxAnalyzeResult
 	gpxAnalyzeResponse.Attributes.IsError = false
-	buildGpxAnalyzeResponse(writer, http.StatusOK, gpxAnalyzeResponse)
+	buildGpxAnalyzeResponse(writer, request, http.StatusOK, gpxAnalyzeResponse)
 }
 
 /*
@@ -123,18 +152,6 @@ func verifyGpxAnalyzeRequestData(request *http.Request, gpxAnalyzeRequest GPXAna
 		return fmt.Errorf("unexpected or missing HTTP header field Content-Type, value = [%s], expected 'application/json'", contentType)
 	}
 
-	// verify HTTP header
-	accept := request.Header.Get("Accept")
-	isAcceptValid := true
-	switch {
-	case strings.HasPrefix(strings.ToLower(accept), "application/json"):
-	default:
-		isAcceptValid = false
-	}
-	if !isAcceptValid {
-		return fmt.Errorf("unexpected or missing HTTP header field Accept, value = [%s], expected 'application/json'", accept)
-	}
-
 	// verify Type
 	if gpxAnalyzeRequest.Type != TypeGPXAnalyzeRequest {
 		return fmt.Errorf("unexpected request Type [%v]", gpxAnalyzeRequest.Type)
@@ -167,6 +184,30 @@ func verifyGpxAnalyzeRequestData(request *http.Request, gpxAnalyzeRequest GPXAna
 		return errors.New("GPXData does not contain expected 'gpx' root element")
 	}
 
+	// verify mode
+	switch strings.ToLower(gpxAnalyzeRequest.Attributes.Mode) {
+	case "", "analyze", "validate":
+	default:
+		return errors.New("unsupported mode (not analyze, validate)")
+	}
+
+	// verify sun exposure
+	sunExposure := gpxAnalyzeRequest.Attributes.SunExposure
+	if sunExposure != nil {
+		if strings.ToLower(gpxAnalyzeRequest.Attributes.Mode) == "validate" {
+			return errors.New("SunExposure is not supported in mode validate")
+		}
+		if sunExposure.StartTime != "" {
+			_, err = time.Parse(time.RFC3339, sunExposure.StartTime)
+			if err != nil {
+				return fmt.Errorf("SunExposure.StartTime is not a valid RFC 3339 time: %w", err)
+			}
+		}
+		if sunExposure.Speed < 0 || sunExposure.Speed > 100 {
+			return errors.New("SunExposure.Speed must be between 0 and 100 km/h")
+		}
+	}
+
 	return nil
 }
 
@@ -175,21 +216,17 @@ buildGpxAnalyzeResponse builds HTTP responses with specified status and body.
 It sets the Content-Type and Content-Length headers before writing the response body.
 This function is used to construct consistent HTTP responses throughout the application.
 */
-func buildGpxAnalyzeResponse(writer http.ResponseWriter, httpStatus int, gpxAnalyzeResponse GPXAnalyzeResponse) {
+func buildGpxAnalyzeResponse(writer http.ResponseWriter, request *http.Request, httpStatus int, gpxAnalyzeResponse GPXAnalyzeResponse) {
+	// response metadata (versions, processing duration, cache hit)
+	gpxAnalyzeResponse.Meta = newResponseMeta(request.Context())
+
 	// log limit length of body (e.g., the GPXData object as part of the body can be very large)
 	maxBodyLength := 1024
 
-	// CORS: allow requests from any origin
-	writer.Header().Set("Access-Control-Allow-Origin", "*")
-	// CORS: allowed methods
-	writer.Header().Set("Access-Control-Allow-Methods", "POST")
-	// CORS: allowed headers
-	writer.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
 	// marshal response
-	body, err := json.MarshalIndent(gpxAnalyzeResponse, "", "  ")
+	body, err := marshalResponse(gpxAnalyzeResponse)
 	if err != nil {
-		slog.Error("error marshaling gpx response", "error", err, "body length", len(body),
+		slog.ErrorContext(request.Context(), "error marshaling gpx response", "error", err, "body length", len(body),
 			fmt.Sprintf("body (limited to first %d bytes)", maxBodyLength), body[:maxBodyLength])
 		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
 		return
@@ -200,7 +237,7 @@ func buildGpxAnalyzeResponse(writer http.ResponseWriter, httpStatus int, gpxAnal
 	writer.WriteHeader(httpStatus)
 	_, err = writer.Write(body)
 	if err != nil {
-		slog.Error("error writing HTTP response body", "error", err, "body length", len(body),
+		slog.ErrorContext(request.Context(), "error writing HTTP response body", "error", err, "body length", len(body),
 			fmt.Sprintf("body (limited to first %d bytes)", maxBodyLength), body[:maxBodyLength])
 	}
 }
@@ -208,7 +245,7 @@ func buildGpxAnalyzeResponse(writer http.ResponseWriter, httpStatus int, gpxAnal
 /*
 analyzeGpxData analyzes GPX (file) data, calculates statistics, and returns them in a GpxAnlyzeResult structure.
 */
-func analyzeGpxData(gpxData *gpx.GPX) (*GpxAnalyzeResult, error) {
+func analyzeGpxData(gpxData *gpx.GPX, requestID string, sunExposureOptions *SunExposureOptions) (*GpxAnalyzeResult, error) {
 	result := &GpxAnalyzeResult{
 		Version:     gpxData.Version,
 		Name:        gpxData.Name,
@@ -219,6 +256,15 @@ func analyzeGpxData(gpxData *gpx.GPX) (*GpxAnalyzeResult, error) {
 		Tracks:      []GpxAnalyzeTrackResult{},
 	}
 
+	// terrain ruggedness along all tracks (DGM slope and TRI)
+	result.Ruggedness = calculateRuggedness(gpxData, requestID)
+
+	// time at track points for sun exposure (track timestamps or start time and speed)
+	var sunExposureClock sunExposureClock
+	if sunExposureOptions != nil {
+		sunExposureClock = newSunExposureClock(gpxData, *sunExposureOptions)
+	}
+
 	// process track data for all segments
 	for _, track := range gpxData.Tracks {
 		trackResult := GpxAnalyzeTrackResult{
@@ -244,6 +290,32 @@ func analyzeGpxData(gpxData *gpx.GPX) (*GpxAnalyzeResult, error) {
 			// calculate detailed point statistics
 			pointDetails := calculatePointDetails(segment.Points)
 
+			// distance along terrain surface (DGM elevations)
+			surfaceDistance, surfaceDistanceCoverage := calculateSurfaceDistance(segment.Points)
+
+			// sun exposure along segment (sunny, shaded, night)
+			var sunExposure *GpxSunExposure
+			if sunExposureOptions != nil {
+				sunExposure = calculateSunExposure(segment.Points, &sunExposureClock)
+			}
+
+			// detect anomalies (candidates for cleanup)
+			anomalies := detectAnomalies(segment.Points, pointDetails)
+
+			// classify segment into ascent, descent and flat sections
+			sections := calculateSections(segment.Points, pointDetails)
+			ascents, descents, flatSections := 0, 0, 0
+			for _, section := range sections {
+				switch section.Type {
+				case "ascent":
+					ascents++
+				case "descent":
+					descents++
+				default:
+					flatSections++
+				}
+			}
+
 			// populate segment result structure
 			segResult := GpxAnalyzeSegmentResult{
 				// General
@@ -253,6 +325,9 @@ func analyzeGpxData(gpxData *gpx.GPX) (*GpxAnalyzeResult, error) {
 				Points:    segment.GetTrackPointsNo(),
 				Length2D:  segment.Length2D(),
 				Length3D:  segment.Length3D(),
+				// Surface
+				SurfaceDistance:         surfaceDistance,
+				SurfaceDistanceCoverage: surfaceDistanceCoverage,
 				// Moving
 				MovingTime:      movingData.MovingTime,
 				StoppedTime:     movingData.StoppedTime,
@@ -268,6 +343,15 @@ func analyzeGpxData(gpxData *gpx.GPX) (*GpxAnalyzeResult, error) {
 				DownhillWMA:        upDownWMA.Downhill,
 				UphillUnfiltered:   gpxUphillUnfiltered,
 				DownhillUnfiltered: gpxDownhillUnfiltered,
+				// Sections
+				Ascents:      ascents,
+				Descents:     descents,
+				FlatSections: flatSections,
+				Sections:     sections,
+				// Anomalies
+				Anomalies: anomalies,
+				// Sun exposure
+				SunExposure: sunExposure,
 				// Details
 				PointDetails: pointDetails,
 			}
@@ -330,6 +414,202 @@ func calculatePointDetails(points []gpx.GPXPoint) []GpxAnalyzePointDetail {
 	return details
 }
 
+/*
+detectAnomalies detects anomalies of a segment:
+- jump: GPS jump, speed between two points exceeds 70 m/s (252 km/h)
+- gap: recording gap, time between two points exceeds 300 seconds
+- duplicate: consecutive points with identical position (grouped)
+- spike: elevation of a point deviates by more than 20 m from both neighbours in the same direction
+*/
+func detectAnomalies(points []gpx.GPXPoint, pointDetails []GpxAnalyzePointDetail) []GpxAnalyzeAnomaly {
+	const maxSpeed = 70.0 // m/s
+	const maxGap = 300.0  // seconds
+	const minSpike = 20.0 // meters
+
+	anomalies := []GpxAnalyzeAnomaly{}
+	duplicateStart := -1
+	for i := 1; i < len(points); i++ {
+		// jump and gap (timestamps required)
+		if !points[i-1].Timestamp.IsZero() && !points[i].Timestamp.IsZero() {
+			seconds := points[i].Timestamp.Sub(points[i-1].Timestamp).Seconds()
+			if seconds > 0 && pointDetails[i].Distance/seconds > maxSpeed {
+				speed := pointDetails[i].Distance / seconds
+				anomalies = append(anomalies, GpxAnalyzeAnomaly{Type: "jump", StartIndex: i - 1, EndIndex: i, Value: speed,
+					Description: fmt.Sprintf("GPS jump: %.0f m in %.0f s (%.0f km/h)", pointDetails[i].Distance, seconds, speed*3.6)})
+			}
+			if seconds > maxGap {
+				anomalies = append(anomalies, GpxAnalyzeAnomaly{Type: "gap", StartIndex: i - 1, EndIndex: i, Value: seconds,
+					Description: fmt.Sprintf("recording gap: %.0f s (%.0f m)", seconds, pointDetails[i].Distance)})
+			}
+		}
+
+		// duplicate (grouped run of identical positions)
+		isDuplicate := points[i].Latitude == points[i-1].Latitude && points[i].Longitude == points[i-1].Longitude
+		if isDuplicate && duplicateStart < 0 {
+			duplicateStart = i - 1
+		}
+		if duplicateStart >= 0 && (!isDuplicate || i == len(points)-1) {
+			end := i - 1
+			if isDuplicate {
+				end = i
+			}
+			count := end - duplicateStart + 1
+			anomalies = append(anomalies, GpxAnalyzeAnomaly{Type: "duplicate", StartIndex: duplicateStart, EndIndex: end, Value: float64(count),
+				Description: fmt.Sprintf("%d points with identical position", count)})
+			duplicateStart = -1
+		}
+
+		// spike (point between two neighbours)
+		if i < len(points)-1 && points[i].Elevation.NotNull() && points[i-1].Elevation.NotNull() && points[i+1].Elevation.NotNull() {
+			elevation := points[i].Elevation.Value()
+			toPrevious := elevation - points[i-1].Elevation.Value()
+			toNext := elevation - points[i+1].Elevation.Value()
+			if (toPrevious > minSpike && toNext > minSpike) || (toPrevious < -minSpike && toNext < -minSpike) {
+				height := toPrevious
+				if math.Abs(toNext) < math.Abs(toPrevious) {
+					height = toNext
+				}
+				anomalies = append(anomalies, GpxAnalyzeAnomaly{Type: "spike", StartIndex: i, EndIndex: i, Value: height,
+					Description: fmt.Sprintf("elevation spike: %.1f m relative to neighbours", height)})
+			}
+		}
+	}
+
+	return anomalies
+}
+
+/*
+calculateSections classifies a segment into sections of uniform trend (ascent, descent, flat):
+ 1. find turning points of the elevation profile (zigzag with hysteresis: a trend reverses only if
+    the elevation changes by at least 10 m in the opposite direction, smaller undulations are ignored)
+ 2. classify sections between turning points by average gradient (flat: less than 2 %)
+ 3. merge adjacent sections of the same type
+*/
+func calculateSections(points []gpx.GPXPoint, pointDetails []GpxAnalyzePointDetail) []GpxAnalyzeSection {
+	const hysteresis = 10.0  // meters
+	const flatGradient = 2.0 // percent
+
+	if len(points) < 2 {
+		return nil
+	}
+
+	// 1. turning points (zigzag with hysteresis)
+	turningPoints := []int{0}
+	direction := 0 // 1 = ascending, -1 = descending, 0 = undetermined
+	minIndex, maxIndex, extremeIndex := 0, 0, 0
+	for i := 1; i < len(points); i++ {
+		elevation := points[i].Elevation.Value()
+		switch direction {
+		case 0:
+			if elevation < points[minIndex].Elevation.Value() {
+				minIndex = i
+			}
+			if elevation > points[maxIndex].Elevation.Value() {
+				maxIndex = i
+			}
+			if elevation-points[minIndex].Elevation.Value() >= hysteresis {
+				if minIndex != 0 {
+					turningPoints = append(turningPoints, minIndex)
+				}
+				direction = 1
+				extremeIndex = i
+			} else if points[maxIndex].Elevation.Value()-elevation >= hysteresis {
+				if maxIndex != 0 {
+					turningPoints = append(turningPoints, maxIndex)
+				}
+				direction = -1
+				extremeIndex = i
+			}
+		case 1:
+			if elevation >= points[extremeIndex].Elevation.Value() {
+				extremeIndex = i
+			} else if points[extremeIndex].Elevation.Value()-elevation >= hysteresis {
+				turningPoints = append(turningPoints, extremeIndex)
+				direction = -1
+				extremeIndex = i
+			}
+		case -1:
+			if elevation <= points[extremeIndex].Elevation.Value() {
+				extremeIndex = i
+			} else if elevation-points[extremeIndex].Elevation.Value() >= hysteresis {
+				turningPoints = append(turningPoints, extremeIndex)
+				direction = 1
+				extremeIndex = i
+			}
+		}
+	}
+	if direction != 0 && extremeIndex != turningPoints[len(turningPoints)-1] {
+		turningPoints = append(turningPoints, extremeIndex)
+	}
+	if turningPoints[len(turningPoints)-1] != len(points)-1 {
+		turningPoints = append(turningPoints, len(points)-1)
+	}
+
+	// 2. classify sections between turning points, 3. merge adjacent sections of same type
+	var sections []GpxAnalyzeSection
+	for t := 1; t < len(turningPoints); t++ {
+		start := turningPoints[t-1]
+		end := turningPoints[t]
+		sectionType := classifySection(points, pointDetails, start, end, flatGradient)
+		if len(sections) > 0 && sections[len(sections)-1].Type == sectionType {
+			start = sections[len(sections)-1].StartIndex
+			sections = sections[:len(sections)-1]
+		}
+		sections = append(sections, buildSection(points, pointDetails, start, end, sectionType))
+	}
+
+	return sections
+}
+
+/*
+classifySection classifies the points from start to end index as ascent, descent or flat (by average gradient).
+*/
+func classifySection(points []gpx.GPXPoint, pointDetails []GpxAnalyzePointDetail, start, end int, flatGradient float64) string {
+	distance := 0.0
+	for i := start + 1; i <= end; i++ {
+		distance += pointDetails[i].Distance
+	}
+	if distance == 0 {
+		return "flat"
+	}
+	gradient := (points[end].Elevation.Value() - points[start].Elevation.Value()) / distance * 100
+	switch {
+	case gradient >= flatGradient:
+		return "ascent"
+	case gradient <= -flatGradient:
+		return "descent"
+	default:
+		return "flat"
+	}
+}
+
+/*
+buildSection calculates the statistics for the points from start to end index.
+*/
+func buildSection(points []gpx.GPXPoint, pointDetails []GpxAnalyzePointDetail, start, end int, sectionType string) GpxAnalyzeSection {
+	section := GpxAnalyzeSection{
+		Type:           sectionType,
+		StartIndex:     start,
+		EndIndex:       end,
+		StartElevation: points[start].Elevation.Value(),
+		EndElevation:   points[end].Elevation.Value(),
+	}
+	for i := start + 1; i <= end; i++ {
+		section.Distance += pointDetails[i].Distance
+	}
+	uphill, downhill := calculateUphillDownhill(points[start : end+1])
+	section.Uphill = uphill
+	section.Downhill = downhill
+	section.ElevationDifference = section.EndElevation - section.StartElevation
+	if section.Distance > 0 {
+		section.AverageGradient = section.ElevationDifference / section.Distance * 100
+	}
+	if !points[start].Timestamp.IsZero() && !points[end].Timestamp.IsZero() {
+		section.Duration = points[end].Timestamp.Sub(points[start].Timestamp).Seconds()
+	}
+	return section
+}
+
 /*
 calculateUphillDownhill calculates the total ascent and descent from a slice of GPX points.
 */
@@ -346,3 +626,300 @@ func calculateUphillDownhill(points []gpx.GPXPoint) (uphill, downhill float64) {
 	}
 	return uphill, downhill
 }
+
+/*
+calculateSurfaceDistance calculates the distance along the terrain surface of a segment (3D distance based on
+DGM elevations, independent of GPS elevation errors). Legs with a point without DGM elevation (outside coverage
+or nodata) count with their 2D distance. It returns the distance and the percentage of points with DGM elevation.
+*/
+func calculateSurfaceDistance(points []gpx.GPXPoint) (float64, float64) {
+	if len(points) == 0 {
+		return 0, 0
+	}
+
+	elevations := make([]float64, len(points))
+	hasElevation := make([]bool, len(points))
+	covered := 0
+	for i, point := range points {
+		elevation, _, err := getElevationForPoint(point.Longitude, point.Latitude)
+		if err != nil || elevation < -9998.9 {
+			continue
+		}
+		elevations[i] = elevation
+		hasElevation[i] = true
+		covered++
+	}
+
+	distance := 0.0
+	for i := 1; i < len(points); i++ {
+		horizontal := points[i].Distance2D(&points[i-1])
+		if !hasElevation[i] || !hasElevation[i-1] {
+			distance += horizontal
+			continue
+		}
+		vertical := elevations[i] - elevations[i-1]
+		distance += math.Sqrt(horizontal*horizontal + vertical*vertical)
+	}
+
+	return distance, 100.0 * float64(covered) / float64(len(points))
+}
+
+/*
+calculateRuggedness calculates the terrain ruggedness of a route from DGM values sampled along all tracks (points
+with a min distance of 10 m, max 5000 samples). For each sampled point slope (Horn) and TRI (Riley) are derived
+from the 3x3 cells around the point. The score (0 - 100) weights:
+- mean slope (40 points, 25 degrees and more = max)
+- share of steep points, slope >= 20 degrees (20 points, 50 percent and more = max)
+- mean TRI (25 points, 1.5 m and more = max)
+- share of rough points, TRI >= 1.5 m (15 points, 50 percent and more = max)
+Points outside coverage or without data are skipped. It returns nil if no point could be sampled.
+*/
+func calculateRuggedness(gpxData *gpx.GPX, requestID string) *GpxRuggedness {
+	const maxSamples = 5000
+	const steepSlope = 20.0
+	const roughTRI = 1.5
+
+	// sample interval (depending on length of all tracks)
+	length := 0.0
+	for _, track := range gpxData.Tracks {
+		for _, segment := range track.Segments {
+			length += segment.Length2D()
+		}
+	}
+	interval := max(10.0, length/maxSamples)
+
+	var samples, steepSamples, roughSamples int
+	var slopeSum, maxSlope, triSum, maxTRI float64
+	var grid *tileGrid
+	for _, track := range gpxData.Tracks {
+		for _, segment := range track.Segments {
+			distance := interval // first point of segment is always sampled
+			for i, point := range segment.Points {
+				if i > 0 {
+					distance += point.Distance2D(&segment.Points[i-1])
+				}
+				if distance < interval {
+					continue
+				}
+
+				window, _, _, _, ok := getTrackPointWindow(point.Longitude, point.Latitude, &grid)
+				if !ok {
+					continue
+				}
+				distance = 0
+
+				slope, tri := calculateSlopeTRI(window, math.Abs(grid.geoTransform[1]), math.Abs(grid.geoTransform[5]))
+				samples++
+				slopeSum += slope
+				maxSlope = max(maxSlope, slope)
+				triSum += tri
+				maxTRI = max(maxTRI, tri)
+				if slope >= steepSlope {
+					steepSamples++
+				}
+				if tri >= roughTRI {
+					roughSamples++
+				}
+			}
+		}
+	}
+	if samples == 0 {
+		slog.Debug("gpx analyze request: no DGM data for ruggedness along tracks", "requestID", requestID)
+		return nil
+	}
+
+	ruggedness := &GpxRuggedness{
+		SampledPoints:  samples,
+		SampleInterval: interval,
+		MeanSlope:      slopeSum / float64(samples),
+		MaxSlope:       maxSlope,
+		SteepShare:     100.0 * float64(steepSamples) / float64(samples),
+		MeanTRI:        triSum / float64(samples),
+		MaxTRI:         maxTRI,
+		RoughShare:     100.0 * float64(roughSamples) / float64(samples),
+	}
+	score := 40.0*min(ruggedness.MeanSlope/25.0, 1.0) + 20.0*min(ruggedness.SteepShare/50.0, 1.0) +
+		25.0*min(ruggedness.MeanTRI/1.5, 1.0) + 15.0*min(ruggedness.RoughShare/50.0, 1.0)
+	ruggedness.Score = math.Round(score*10.0) / 10.0
+
+	switch {
+	case ruggedness.Score < 20.0:
+		ruggedness.Rating = "easy"
+	case ruggedness.Score < 40.0:
+		ruggedness.Rating = "moderate"
+	case ruggedness.Score < 60.0:
+		ruggedness.Rating = "difficult"
+	case ruggedness.Score < 80.0:
+		ruggedness.Rating = "very difficult"
+	default:
+		ruggedness.Rating = "extreme"
+	}
+
+	return ruggedness
+}
+
+/*
+getTrackPointWindow returns the 3x3 DGM cells around a lon/lat point and the UTM coordinates (zone, easting, northing)
+of the point. The tile grid of the previous point is reused if the point is in the same tile (tracks mostly stay
+within a tile for many points), otherwise the tile variants (primary, secondary, tertiary) are tried.
+*/
+func getTrackPointWindow(longitude, latitude float64, grid **tileGrid) ([9]float64, int, float64, float64, bool) {
+	tile, zone, easting, northing, err := getTileUTM(longitude, latitude)
+	if err != nil {
+		return [9]float64{}, zone, easting, northing, false
+	}
+
+	for variant := 1; variant <= 3; variant++ {
+		if variant > 1 {
+			tile, err = getGeotiffTile(easting, northing, zone, variant)
+			if err != nil {
+				return [9]float64{}, zone, easting, northing, false
+			}
+		}
+		if *grid == nil || (*grid).path != tile.Path {
+			*grid, err = getTileGrid(tile.Path)
+			if err != nil {
+				return [9]float64{}, zone, easting, northing, false
+			}
+		}
+		window, ok := (*grid).window(easting, northing)
+		if ok {
+			return window, zone, easting, northing, true
+		}
+	}
+
+	return [9]float64{}, zone, easting, northing, false
+}
+
+/*
+calculateHornGradient calculates the gradient (Horn, weighted differences of the neighbor cells) from 3x3 cells
+(row-major, center at index 4). dzdx is the gradient to the east, dzdy the gradient to the south (row order).
+*/
+func calculateHornGradient(window [9]float64, cellWidth, cellHeight float64) (float64, float64) {
+	dzdx := ((window[2] + 2*window[5] + window[8]) - (window[0] + 2*window[3] + window[6])) / (8 * cellWidth)
+	dzdy := ((window[6] + 2*window[7] + window[8]) - (window[0] + 2*window[1] + window[2])) / (8 * cellHeight)
+	return dzdx, dzdy
+}
+
+/*
+calculateSlopeTRI calculates slope (Horn, degrees) and TRI (Riley, meters) from 3x3 cells (row-major, center at index 4).
+*/
+func calculateSlopeTRI(window [9]float64, cellWidth, cellHeight float64) (float64, float64) {
+	dzdx, dzdy := calculateHornGradient(window, cellWidth, cellHeight)
+	slope := math.Atan(math.Sqrt(dzdx*dzdx+dzdy*dzdy)) * 180.0 / math.Pi
+
+	// Riley: square root of the sum of squared differences between center and neighbor cells
+	sum := 0.0
+	for i, value := range window {
+		if i == 4 {
+			continue
+		}
+		sum += (value - window[4]) * (value - window[4])
+	}
+	tri := math.Sqrt(sum)
+
+	return slope, tri
+}
+
+/*
+validateGpxData compares the original elevations of all track points with DGM elevations and calculates
+per point and aggregated (per segment, overall) error statistics. The GPX data is not modified.
+Points without original elevation or without DGM elevation (outside coverage or nodata) are skipped.
+*/
+func validateGpxData(gpxData *gpx.GPX, requestID string) *GpxValidationResult {
+	result := &GpxValidationResult{Tracks: []GpxValidationTrackResult{}}
+	var total gpxValidationAccumulator
+
+	for i, track := range gpxData.Tracks {
+		trackResult := GpxValidationTrackResult{Name: track.Name, Segments: []GpxValidationSegmentResult{}}
+
+		for j, segment := range track.Segments {
+			var segmentAccumulator gpxValidationAccumulator
+			pointDetails := make([]GpxValidationPointDetail, 0, len(segment.Points))
+
+			for k, point := range segment.Points {
+				result.Points++
+				pointDetail := GpxValidationPointDetail{Latitude: point.Latitude, Longitude: point.Longitude}
+
+				if point.Elevation.NotNull() {
+					gpxElevation := point.Elevation.Value()
+					pointDetail.GPXElevation = &gpxElevation
+				} else {
+					result.PointsWithoutElevation++
+				}
+
+				dgmElevation, tile, err := getElevationForPoint(point.Longitude, point.Latitude)
+				if err != nil {
+					// log error for the specific point but continue processing others
+					slog.Debug("failed to get elevation for GPX point", "requestID", requestID, "track", i, "segment", j,
+						"index", k, "longitude", point.Longitude, "latitude", point.Latitude, "error", err)
+					result.PointsWithoutDGM++
+				} else {
+					pointDetail.DGMElevation = &dgmElevation
+					pointDetail.TileIndex = tile.Index
+				}
+
+				if pointDetail.GPXElevation != nil && pointDetail.DGMElevation != nil {
+					deviation := *pointDetail.GPXElevation - *pointDetail.DGMElevation
+					pointDetail.Deviation = &deviation
+					segmentAccumulator.add(deviation, point.Latitude, point.Longitude)
+					total.add(deviation, point.Latitude, point.Longitude)
+					result.ValidatedPoints++
+				}
+				pointDetails = append(pointDetails, pointDetail)
+			}
+
+			trackResult.Segments = append(trackResult.Segments, GpxValidationSegmentResult{
+				Statistics:   segmentAccumulator.statistics(),
+				PointDetails: pointDetails,
+			})
+		}
+		result.Tracks = append(result.Tracks, trackResult)
+	}
+	result.Statistics = total.statistics()
+
+	return result
+}
+
+// gpxValidationAccumulator accumulates deviations for error statistics.
+type gpxValidationAccumulator struct {
+	count                 int
+	sum                   float64
+	sumAbsolute           float64
+	sumSquared            float64
+	maxDeviation          float64
+	maxDeviationLatitude  float64
+	maxDeviationLongitude float64
+}
+
+/*
+add adds a deviation (at given position) to the accumulator.
+*/
+func (a *gpxValidationAccumulator) add(deviation, latitude, longitude float64) {
+	a.count++
+	a.sum += deviation
+	a.sumAbsolute += math.Abs(deviation)
+	a.sumSquared += deviation * deviation
+	if a.count == 1 || math.Abs(deviation) > math.Abs(a.maxDeviation) {
+		a.maxDeviation = deviation
+		a.maxDeviationLatitude = latitude
+		a.maxDeviationLongitude = longitude
+	}
+}
+
+/*
+statistics returns the error statistics of all accumulated deviations.
+*/
+func (a *gpxValidationAccumulator) statistics() GpxValidationStatistics {
+	statistics := GpxValidationStatistics{ValidatedPoints: a.count}
+	if a.count == 0 {
+		return statistics
+	}
+	statistics.Bias = a.sum / float64(a.count)
+	statistics.MeanAbsoluteError = a.sumAbsolute / float64(a.count)
+	statistics.RMSE = math.Sqrt(a.sumSquared / float64(a.count))
+	statistics.MaxDeviation = a.maxDeviation
+	statistics.MaxDeviationLatitude = a.maxDeviationLatitude
+	statistics.MaxDeviationLongitude = a.maxDeviationLongitude
+	return statistics
+}
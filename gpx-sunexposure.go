@@ -0,0 +1,231 @@
+package main
+
+import (
+	"math"
+	"time"
+
+	"github.com/tkrajina/gpxgo/gpx"
+)
+
+// sun exposure settings (min distance between sampled points, max length of horizon ray, observer height)
+const (
+	sunExposureSampleInterval = 25.0
+	sunExposureMaxRayLength   = 5000.0
+	sunExposureObserverHeight = 2.0
+	sunExposureMaxGrids       = 16
+)
+
+// sunExposureClock determines the time at track points (track timestamps or start time and speed).
+type sunExposureClock struct {
+	startTime      time.Time // requested start time (zero = timestamps of track points)
+	firstTimestamp time.Time // first timestamp of all tracks (zero = track without timestamps)
+	speed          float64   // meters per second
+	distance       float64   // distance since start (speed based time)
+}
+
+/*
+newSunExposureClock creates the clock for the sun exposure of all tracks.
+*/
+func newSunExposureClock(gpxData *gpx.GPX, options SunExposureOptions) sunExposureClock {
+	clock := sunExposureClock{speed: options.Speed / 3.6}
+	if clock.speed <= 0 {
+		clock.speed = 4.0 / 3.6
+	}
+	clock.startTime, _ = time.Parse(time.RFC3339, options.StartTime) // error already checked in verifyGpxAnalyzeRequestData()
+
+	for _, track := range gpxData.Tracks {
+		for _, segment := range track.Segments {
+			for _, point := range segment.Points {
+				if !point.Timestamp.IsZero() {
+					clock.firstTimestamp = point.Timestamp
+					return clock
+				}
+			}
+		}
+	}
+	return clock
+}
+
+/*
+at returns the time at a track point (after a leg of given distance). It returns false if the time is unknown.
+*/
+func (clock *sunExposureClock) at(point gpx.GPXPoint, legDistance float64) (time.Time, bool) {
+	clock.distance += legDistance
+
+	if clock.startTime.IsZero() {
+		return point.Timestamp, !point.Timestamp.IsZero()
+	}
+	if !clock.firstTimestamp.IsZero() && !point.Timestamp.IsZero() {
+		return clock.startTime.Add(point.Timestamp.Sub(clock.firstTimestamp)), true
+	}
+	return clock.startTime.Add(time.Duration(clock.distance / clock.speed * float64(time.Second))), true
+}
+
+/*
+calculateSunExposure calculates the sun exposure of a segment. Points are sampled with a min distance of 25 m,
+for each sampled point the exposure is determined from the sun position at the point time:
+- night: sun below horizon
+- shaded: slope faces away from sun (3x3 DGM cells) or terrain within 5 km rises above sun altitude (horizon ray)
+- sunny: otherwise
+Legs take the exposure of the last sampled point. Points without DGM data or without time are skipped.
+*/
+func calculateSunExposure(points []gpx.GPXPoint, clock *sunExposureClock) *GpxSunExposure {
+	exposure := &GpxSunExposure{Sections: []GpxSunExposureSection{}}
+
+	grids := make(map[string]*tileGrid)
+	var grid *tileGrid
+	current := ""
+	distance := sunExposureSampleInterval // first point of segment is always sampled
+	for i, point := range points {
+		legDistance := 0.0
+		if i > 0 {
+			legDistance = point.Distance2D(&points[i-1])
+		}
+		pointTime, hasTime := clock.at(point, legDistance)
+
+		// leg takes the exposure of the previous point
+		if i > 0 && current != "" {
+			switch current {
+			case "sunny":
+				exposure.SunnyDistance += legDistance
+			case "shaded":
+				exposure.ShadedDistance += legDistance
+			default:
+				exposure.NightDistance += legDistance
+			}
+			section := &exposure.Sections[len(exposure.Sections)-1]
+			section.EndIndex = i
+			section.Distance += legDistance
+			if hasTime {
+				section.EndTime = pointTime
+			}
+		}
+
+		distance += legDistance
+		if distance < sunExposureSampleInterval || !hasTime {
+			continue
+		}
+		window, zone, easting, northing, ok := getTrackPointWindow(point.Longitude, point.Latitude, &grid)
+		if !ok {
+			continue
+		}
+		distance = 0
+		exposure.SampledPoints++
+
+		pointExposure := getSunExposure(window, zone, easting, northing, point.Longitude, point.Latitude, pointTime, grid, grids)
+		if pointExposure != current {
+			exposure.Sections = append(exposure.Sections, GpxSunExposureSection{Exposure: pointExposure, StartIndex: i, EndIndex: i,
+				StartTime: pointTime, EndTime: pointTime})
+			current = pointExposure
+		}
+	}
+
+	total := exposure.SunnyDistance + exposure.ShadedDistance + exposure.NightDistance
+	if total > 0 {
+		exposure.SunnyShare = 100.0 * exposure.SunnyDistance / total
+	}
+
+	return exposure
+}
+
+/*
+getSunExposure determines the exposure (sunny, shaded, night) of a point with the 3x3 DGM cells around it.
+*/
+func getSunExposure(window [9]float64, zone int, easting, northing, longitude, latitude float64, pointTime time.Time,
+	grid *tileGrid, grids map[string]*tileGrid) string {
+	azimuth, altitude := calculateSunPosition(pointTime, longitude, latitude)
+	if altitude <= 0 {
+		return "night"
+	}
+
+	// self-shading: angle between surface normal and sun direction (east, north, up)
+	dzdx, dzdy := calculateHornGradient(window, math.Abs(grid.geoTransform[1]), math.Abs(grid.geoTransform[5]))
+	normal := [3]float64{-dzdx, dzdy, 1} // dzdy is gradient to the south (row order)
+	azimuthRad := azimuth * math.Pi / 180.0
+	altitudeRad := altitude * math.Pi / 180.0
+	sun := [3]float64{math.Sin(azimuthRad) * math.Cos(altitudeRad), math.Cos(azimuthRad) * math.Cos(altitudeRad), math.Sin(altitudeRad)}
+	if normal[0]*sun[0]+normal[1]*sun[1]+normal[2]*sun[2] <= 0 {
+		return "shaded"
+	}
+
+	// cast shadow: terrain along the ray towards the sun rises above the sun altitude
+	observer := window[4] + sunExposureObserverHeight
+	tanAltitude := math.Tan(altitudeRad)
+	for rayDistance := 10.0; rayDistance <= sunExposureMaxRayLength; rayDistance += max(10.0, rayDistance*0.02) {
+		// terrain higher than highest point in Germany cannot shade
+		if observer+rayDistance*tanAltitude > 3000.0 {
+			break
+		}
+		elevation, ok := getElevationForRay(zone, easting+math.Sin(azimuthRad)*rayDistance, northing+math.Cos(azimuthRad)*rayDistance, grids)
+		if !ok {
+			continue
+		}
+		if (elevation-observer)/rayDistance > tanAltitude {
+			return "shaded"
+		}
+	}
+
+	return "sunny"
+}
+
+/*
+getElevationForRay returns the elevation (primary tile) for UTM coordinates along a horizon ray. The tile grids are
+kept per segment (limited number), since neighboring rays mostly cross the same tiles.
+*/
+func getElevationForRay(zone int, easting, northing float64, grids map[string]*tileGrid) (float64, bool) {
+	tile, err := getGeotiffTile(easting, northing, zone, 1)
+	if err != nil {
+		return 0, false
+	}
+
+	grid, found := grids[tile.Path]
+	if !found {
+		if len(grids) >= sunExposureMaxGrids {
+			clear(grids)
+		}
+		grid, err = getTileGrid(tile.Path)
+		if err != nil {
+			return 0, false
+		}
+		grids[tile.Path] = grid
+	}
+
+	elevation, err := grid.elevation(easting, northing)
+	if err != nil || elevation < -9998.9 {
+		return 0, false
+	}
+	return elevation, true
+}
+
+/*
+calculateSunPosition calculates the position of the sun (azimuth clockwise from north, altitude above horizon, both
+in degrees) for a time and location (low precision formulas of the Astronomical Almanac, accuracy about 0.1 degrees).
+*/
+func calculateSunPosition(t time.Time, longitude, latitude float64) (float64, float64) {
+	const rad = math.Pi / 180.0
+
+	// days since J2000.0
+	n := float64(t.UTC().Unix())/86400.0 + 2440587.5 - 2451545.0
+
+	// ecliptic longitude of the sun
+	meanLongitude := math.Mod(280.460+0.9856474*n, 360.0)
+	meanAnomaly := math.Mod(357.528+0.9856003*n, 360.0) * rad
+	eclipticLongitude := (meanLongitude + 1.915*math.Sin(meanAnomaly) + 0.020*math.Sin(2*meanAnomaly)) * rad
+	obliquity := (23.439 - 0.0000004*n) * rad
+
+	// equatorial coordinates
+	rightAscension := math.Atan2(math.Cos(obliquity)*math.Sin(eclipticLongitude), math.Cos(eclipticLongitude))
+	declination := math.Asin(math.Sin(obliquity) * math.Sin(eclipticLongitude))
+
+	// local hour angle
+	siderealTime := math.Mod(280.46061837+360.98564736629*n, 360.0) + longitude
+	hourAngle := siderealTime*rad - rightAscension
+
+	// horizontal coordinates
+	latitudeRad := latitude * rad
+	altitude := math.Asin(math.Sin(latitudeRad)*math.Sin(declination) + math.Cos(latitudeRad)*math.Cos(declination)*math.Cos(hourAngle))
+	azimuth := math.Atan2(-math.Sin(hourAngle), math.Tan(declination)*math.Cos(latitudeRad)-math.Sin(latitudeRad)*math.Cos(hourAngle))
+
+	azimuthDegrees := math.Mod(azimuth/rad+360.0, 360.0)
+	return azimuthDegrees, altitude / rad
+}
@@ -35,18 +35,14 @@ func gpxRequest(writer http.ResponseWriter, request *http.Request) {
 		// check specifically for the error returned by MaxBytesReader
 		var maxBytesErr *http.MaxBytesError
 		if errors.As(err, &maxBytesErr) {
-			slog.Warn("gpx request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
-			gpxResponse.Attributes.Error.Code = "2000"
-			gpxResponse.Attributes.Error.Title = "request body too large"
-			gpxResponse.Attributes.Error.Detail = fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit)
-			buildGpxResponse(writer, http.StatusRequestEntityTooLarge, gpxResponse)
+			slog.WarnContext(request.Context(), "gpx request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
+			gpxResponse.Attributes.Error = newErrorObject(EndpointGPX, ReasonRequestBodyTooLarge, fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit))
+			buildGpxResponse(writer, request, http.StatusRequestEntityTooLarge, gpxResponse)
 		} else {
 			// handle other read errors
-			slog.Warn("gpx request: error reading request body", "error", err, "ID", "unknown")
-			gpxResponse.Attributes.Error.Code = "2020"
-			gpxResponse.Attributes.Error.Title = "error reading request body"
-			gpxResponse.Attributes.Error.Detail = err.Error()
-			buildGpxResponse(writer, http.StatusBadRequest, gpxResponse)
+			slog.WarnContext(request.Context(), "gpx request: error reading request body", "error", err, "ID", "unknown")
+			gpxResponse.Attributes.Error = newErrorObject(EndpointGPX, ReasonReadingRequestBody, err.Error())
+			buildGpxResponse(writer, request, http.StatusBadRequest, gpxResponse)
 		}
 		return
 	}
@@ -55,25 +51,24 @@ func gpxRequest(writer http.ResponseWriter, request *http.Request) {
 	gpxRequest := GPXRequest{}
 	err = json.Unmarshal(bodyData, &gpxRequest)
 	if err != nil {
-		slog.Warn("gpx request: error unmarshaling request body", "error", err, "ID", "unknown")
-		gpxResponse.Attributes.Error.Code = "2040"
-		gpxResponse.Attributes.Error.Title = "error unmarshaling request body"
-		gpxResponse.Attributes.Error.Detail = err.Error()
-		buildGpxResponse(writer, http.StatusBadRequest, gpxResponse)
+		slog.WarnContext(request.Context(), "gpx request: error unmarshaling request body", "error", err, "ID", "unknown")
+		gpxResponse.Attributes.Error = newErrorObject(EndpointGPX, ReasonUnmarshalingRequestBody, err.Error())
+		buildGpxResponse(writer, request, http.StatusBadRequest, gpxResponse)
 		return
 	}
 
 	// copy request parameters into response
 	gpxResponse.ID = gpxRequest.ID
+	gpxResponse.Attributes.WaypointsOnly = gpxRequest.Attributes.WaypointsOnly
+	gpxResponse.Attributes.RoutesOnly = gpxRequest.Attributes.RoutesOnly
+	gpxResponse.Attributes.TracksOnly = gpxRequest.Attributes.TracksOnly
 
 	// verify request data
 	err = verifyGpxRequestData(request, gpxRequest)
 	if err != nil {
-		slog.Warn("gpx request: error verifying request data", "error", err, "ID", gpxRequest.ID)
-		gpxResponse.Attributes.Error.Code = "2060"
-		gpxResponse.Attributes.Error.Title = "error verifying request data"
-		gpxResponse.Attributes.Error.Detail = err.Error()
-		buildGpxResponse(writer, http.StatusBadRequest, gpxResponse)
+		slog.WarnContext(request.Context(), "gpx request: error verifying request data", "error", err, "ID", gpxRequest.ID)
+		gpxResponse.Attributes.Error = newErrorObject(EndpointGPX, ReasonVerifyingRequestData, err.Error())
+		buildGpxResponse(writer, request, http.StatusBadRequest, gpxResponse)
 		return
 	}
 
@@ -81,44 +76,35 @@ func gpxRequest(writer http.ResponseWriter, request *http.Request) {
 	gpxBytes, _ := base64.StdEncoding.DecodeString(gpxRequest.Attributes.GPXData) // error already checked in verifyGpxRequestData()
 	gpxData, err := gpx.ParseBytes(gpxBytes)
 	if err != nil {
-		slog.Warn("gpx request: error parsing GPX data", "error", err, "ID", gpxRequest.ID)
-		gpxResponse.Attributes.Error.Code = "2080"
-		gpxResponse.Attributes.Error.Title = "error parsing GPX data"
-		gpxResponse.Attributes.Error.Detail = err.Error()
-		buildGpxResponse(writer, http.StatusBadRequest, gpxResponse)
+		slog.WarnContext(request.Context(), "gpx request: error parsing GPX data", "error", err, "ID", gpxRequest.ID)
+		gpxResponse.Attributes.Error = newErrorObject(EndpointGPX, ReasonParsingGPX, err.Error())
+		buildGpxResponse(writer, request, http.StatusBadRequest, gpxResponse)
 		return
 	}
 
-	// add elevation to all points (way, route, track)
+	// types of points to be corrected (default: all points, way, route, track)
+	pointTypes := allGPXPointTypes
+	switch {
+	case gpxRequest.Attributes.WaypointsOnly:
+		pointTypes = GPXPointTypes{Waypoints: true}
+	case gpxRequest.Attributes.RoutesOnly:
+		pointTypes = GPXPointTypes{Routes: true}
+	case gpxRequest.Attributes.TracksOnly:
+		pointTypes = GPXPointTypes{Tracks: true}
+	}
+
+	// add elevation to points (way, route, track)
 	start := time.Now()
-	processedGpxData, usedElevationSources, gpxPoints, dgmPoints, err := addElevationToGPX(gpxData, gpxRequest.ID) // pass ID for logging
+	processedGpxData, usedElevationSources, gpxPoints, dgmPoints, uncoveredPoints, err := addElevationToGPX(gpxData, gpxRequest.ID, pointTypes) // pass ID for logging
 	if err != nil {
-		slog.Error("gpx request: critical error during elevation processing", "error", err, "ID", gpxRequest.ID)
-		gpxResponse.Attributes.Error.Code = "2100"
-		gpxResponse.Attributes.Error.Title = "critical error adding elevation to GPX"
-		gpxResponse.Attributes.Error.Detail = err.Error()
-		buildGpxResponse(writer, http.StatusBadRequest, gpxResponse)
+		slog.ErrorContext(request.Context(), "gpx request: critical error during elevation processing", "error", err, "ID", gpxRequest.ID)
+		gpxResponse.Attributes.Error = newErrorObject(EndpointGPX, ReasonAddingElevationToGPX, err.Error())
+		buildGpxResponse(writer, request, http.StatusBadRequest, gpxResponse)
 		return
 	}
 	end := time.Now()
 	elapsed := end.Sub(start)
-	slog.Info("duration of gpx processing", "elapsed (ms)", int64(elapsed/time.Millisecond))
-
-	// add description
-	description := "Die Höhenangaben (ele) basieren auf DGM-Daten mit hoher Genauigkeit."
-	if processedGpxData.Description == "" {
-		processedGpxData.Description = description
-	} else {
-		processedGpxData.Description += " - " + description
-	}
-
-	// add creator
-	creator := "Höhenangaben von hoehendaten.de"
-	if processedGpxData.Creator == "" {
-		processedGpxData.Creator = creator
-	} else {
-		processedGpxData.Creator += " - " + creator
-	}
+	slog.InfoContext(request.Context(), "duration of gpx processing", "elapsed (ms)", int64(elapsed/time.Millisecond))
 
 	// collect unique source attributions from the used sources
 	uniqueAttributions := make(map[string]string)
@@ -135,35 +121,43 @@ func gpxRequest(writer http.ResponseWriter, request *http.Request) {
 		attributions = append(attributions, attribution)
 	}
 
-	// add attributions to GPX header
-	if processedGpxData.Copyright == "" {
-		processedGpxData.Copyright = strings.Join(attributions, ", ")
-	} else {
-		processedGpxData.Copyright += " " + strings.Join(attributions, ", ")
-	}
+	// add annotations (description, creator, attributions) to GPX header
+	annotation := getProgConfig().GPXAnnotation
+	replacer := strings.NewReplacer("{attributions}", strings.Join(attributions, ", "))
+	processedGpxData.Description = appendGPXAnnotation(processedGpxData.Description, replacer.Replace(annotation.Description), " - ")
+	processedGpxData.Creator = appendGPXAnnotation(processedGpxData.Creator, replacer.Replace(annotation.Creator), " - ")
+	processedGpxData.Copyright = appendGPXAnnotation(processedGpxData.Copyright, replacer.Replace(annotation.Copyright), " ")
 
 	// convert modified GPX data to XML
 	xmlBytes, err := processedGpxData.ToXml(gpx.ToXmlParams{Indent: true})
 	if err != nil {
-		slog.Error("gpx request: error creating GPX track", "error", err, "ID", gpxRequest.ID)
-		gpxResponse.Attributes.Error.Code = "2120"
-		gpxResponse.Attributes.Error.Title = "error creating GPX track"
-		gpxResponse.Attributes.Error.Detail = err.Error()
-		buildGpxResponse(writer, http.StatusInternalServerError, gpxResponse)
+		slog.ErrorContext(request.Context(), "gpx request: error creating GPX track", "error", err, "ID", gpxRequest.ID)
+		gpxResponse.Attributes.Error = newErrorObject(EndpointGPX, ReasonCreatingGPX, err.Error())
+		buildGpxResponse(writer, request, http.StatusInternalServerError, gpxResponse)
 		return
 	}
 
 	// statistics
 	atomic.AddUint64(&GPXPoints, uint64(gpxPoints))
 	atomic.AddUint64(&DGMPoints, uint64(dgmPoints))
+	addUsage(request.Context(), gpxPoints, 0)
 
 	// successful response
 	gpxResponse.Attributes.GPXData = base64.StdEncoding.EncodeToString(xmlBytes)
 	gpxResponse.Attributes.GPXPoints = gpxPoints
 	gpxResponse.Attributes.DGMPoints = dgmPoints
+	if dgmPoints < gpxPoints {
+		gpxResponse.Attributes.Warnings = append(gpxResponse.Attributes.Warnings,
+			fmt.Sprintf("%d of %d points without DGM elevation (outside coverage or nodata), original elevation kept", gpxPoints-dgmPoints, gpxPoints))
+	}
+	gpxResponse.Attributes.UncoveredPoints = uncoveredPoints
+	if len(uncoveredPoints) == maxUncoveredPoints {
+		gpxResponse.Attributes.Warnings = append(gpxResponse.Attributes.Warnings,
+			fmt.Sprintf("UncoveredPoints limited to first %d points", maxUncoveredPoints))
+	}
 	gpxResponse.Attributes.Attributions = attributions
 	gpxResponse.Attributes.IsError = false
-	buildGpxResponse(writer, http.StatusOK, gpxResponse)
+	buildGpxResponse(writer, request, http.StatusOK, gpxResponse)
 }
 
 /*
@@ -184,18 +178,6 @@ func verifyGpxRequestData(request *http.Request, gpxRequest GPXRequest) error {
 		return fmt.Errorf("unexpected or missing HTTP header field Content-Type, value = [%s], expected 'application/json'", contentType)
 	}
 
-	// verify HTTP header
-	accept := request.Header.Get("Accept")
-	isAcceptValid := true
-	switch {
-	case strings.HasPrefix(strings.ToLower(accept), "application/json"):
-	default:
-		isAcceptValid = false
-	}
-	if !isAcceptValid {
-		return fmt.Errorf("unexpected or missing HTTP header field Accept, value = [%s], expected 'application/json'", accept)
-	}
-
 	// verify Type
 	if gpxRequest.Type != TypeGPXRequest {
 		return fmt.Errorf("unexpected request Type [%v]", gpxRequest.Type)
@@ -228,6 +210,17 @@ func verifyGpxRequestData(request *http.Request, gpxRequest GPXRequest) error {
 		return errors.New("GPXData does not contain expected 'gpx' root element")
 	}
 
+	// verify point type options (mutually exclusive)
+	options := 0
+	for _, option := range []bool{gpxRequest.Attributes.WaypointsOnly, gpxRequest.Attributes.RoutesOnly, gpxRequest.Attributes.TracksOnly} {
+		if option {
+			options++
+		}
+	}
+	if options > 1 {
+		return errors.New("WaypointsOnly, RoutesOnly and TracksOnly are mutually exclusive")
+	}
+
 	return nil
 }
 
@@ -236,21 +229,17 @@ buildGpxResponse builds HTTP responses with specified status and body.
 It sets the Content-Type and Content-Length headers before writing the response body.
 This function is used to construct consistent HTTP responses throughout the application.
 */
-func buildGpxResponse(writer http.ResponseWriter, httpStatus int, gpxResponse GPXResponse) {
+func buildGpxResponse(writer http.ResponseWriter, request *http.Request, httpStatus int, gpxResponse GPXResponse) {
+	// response metadata (versions, processing duration, cache hit)
+	gpxResponse.Meta = newResponseMeta(request.Context())
+
 	// log limit length of body (e.g., the GPXData object as part of the body can be very large)
 	maxBodyLength := 1024
 
-	// CORS: allow requests from any origin
-	writer.Header().Set("Access-Control-Allow-Origin", "*")
-	// CORS: allowed methods
-	writer.Header().Set("Access-Control-Allow-Methods", "POST")
-	// CORS: allowed headers
-	writer.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
 	// marshal response
-	body, err := json.MarshalIndent(gpxResponse, "", "  ")
+	body, err := marshalResponse(gpxResponse)
 	if err != nil {
-		slog.Error("error marshaling gpx response", "error", err, "body length", len(body),
+		slog.ErrorContext(request.Context(), "error marshaling gpx response", "error", err, "body length", len(body),
 			fmt.Sprintf("body (limited to first %d bytes)", maxBodyLength), body[:maxBodyLength])
 		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
 		return
@@ -261,28 +250,53 @@ func buildGpxResponse(writer http.ResponseWriter, httpStatus int, gpxResponse GP
 	writer.WriteHeader(httpStatus)
 	_, err = writer.Write(body)
 	if err != nil {
-		slog.Error("error writing HTTP response body", "error", err, "body length", len(body),
+		slog.ErrorContext(request.Context(), "error writing HTTP response body", "error", err, "body length", len(body),
 			fmt.Sprintf("body (limited to first %d bytes)", maxBodyLength), body[:maxBodyLength])
 	}
 }
 
+// maxUncoveredPoints limits the number of reported points outside coverage (response size)
+const maxUncoveredPoints = 1000
+
 /*
-addElevationToGPX adds elevation to all GPX points using actual DTM data.
-It iterates through waypoints, route points, and track points, calculates
+appendGPXAnnotation appends an annotation to a GPX text (e.g. description, creator). An empty annotation
+(disabled by configuration) leaves the text unchanged.
+*/
+func appendGPXAnnotation(text string, annotation string, separator string) string {
+	if annotation == "" {
+		return text
+	}
+	if text == "" {
+		return annotation
+	}
+	return text + separator + annotation
+}
+
+/*
+addElevationToGPX adds elevation to GPX points using actual DTM data.
+It iterates through waypoints, route points, and track points (as selected by pointTypes,
+points of other types remain unchanged and are not counted), calculates
 their elevation using the available GeoTIFF tiles, and updates the GPX data.
 It collects metadata about the elevation sources used.
 If an error occurs for a specific point, it's logged, and that point is skipped.
 Note: A single tile caching adds complexity, but can improve the processing of
 large GPX files significantly.
 */
-func addElevationToGPX(gpxData *gpx.GPX, requestID string) (*gpx.GPX, []ElevationSource, int, int, error) {
+func addElevationToGPX(gpxData *gpx.GPX, requestID string, pointTypes GPXPointTypes) (*gpx.GPX, []ElevationSource, int, int, []UncoveredPoint, error) {
 	// map to collect unique elevation sources based on their code (e.g., "DE-NW")
 	usedSourcesMap := make(map[string]ElevationSource)
 
+	// points outside coverage (nearest covered tile searched once per missing tile)
+	var uncoveredPoints []UncoveredPoint
+	nearestTiles := make(map[string]TileMetadata)
+
 	// statistics
 	gpxPoints := 0
 	dgmPoints := 0
 
+	// annotation of points (template)
+	pointDescription := getProgConfig().GPXAnnotation.PointDescription
+
 	processPoint := func(point *gpx.GPXPoint, pointType string, index int) {
 		gpxPoints++
 		elevation, tile, err := getElevationForPoint(point.Longitude, point.Latitude)
@@ -290,6 +304,15 @@ func addElevationToGPX(gpxData *gpx.GPX, requestID string) (*gpx.GPX, []Elevatio
 			// log error for the specific point but continue processing others
 			slog.Warn("failed to get elevation for GPX point", "requestID", requestID, "pointType", pointType,
 				"index", index, "longitude", point.Longitude, "latitude", point.Latitude, "error", err)
+			if errors.Is(err, ErrOutsideCoverage) && len(uncoveredPoints) < maxUncoveredPoints {
+				uncoveredPoint := UncoveredPoint{PointType: pointType, Index: index, Longitude: point.Longitude, Latitude: point.Latitude, DistanceToCoverage: -1}
+				tile, distance, found := distanceToCoverage(err, nearestTiles)
+				if found {
+					uncoveredPoint.NearestTile = tile.Index
+					uncoveredPoint.DistanceToCoverage = distance
+				}
+				uncoveredPoints = append(uncoveredPoints, uncoveredPoint)
+			}
 			return
 		}
 
@@ -297,12 +320,9 @@ func addElevationToGPX(gpxData *gpx.GPX, requestID string) (*gpx.GPX, []Elevatio
 		point.Elevation.SetValue(elevation)
 		dgmPoints++
 
-		// describe source and actuality (e.g., "Elevation: DE-NW, 2021-06")
-		if point.Description == "" {
-			point.Description = fmt.Sprintf("ele: %s, %s", tile.Source, tile.Actuality)
-		} else {
-			point.Description += fmt.Sprintf(" ele: %s, %s", tile.Source, tile.Actuality)
-		}
+		// describe source and actuality (e.g., "ele: DE-NW, 2021-06")
+		pointAnnotation := strings.NewReplacer("{source}", tile.Source, "{actuality}", tile.Actuality).Replace(pointDescription)
+		point.Description = appendGPXAnnotation(point.Description, pointAnnotation, " ")
 
 		// get and store the source information if not already stored
 		_, exists := usedSourcesMap[tile.Source]
@@ -318,22 +338,28 @@ func addElevationToGPX(gpxData *gpx.GPX, requestID string) (*gpx.GPX, []Elevatio
 	}
 
 	// iterate over all waypoints
-	for i := range gpxData.Waypoints {
-		processPoint(&gpxData.Waypoints[i], "waypoint", i)
+	if pointTypes.Waypoints {
+		for i := range gpxData.Waypoints {
+			processPoint(&gpxData.Waypoints[i], "waypoint", i)
+		}
 	}
 
 	// iterate over all routes
-	for i := range gpxData.Routes {
-		for j := range gpxData.Routes[i].Points {
-			processPoint(&gpxData.Routes[i].Points[j], fmt.Sprintf("route %d point", i), j)
+	if pointTypes.Routes {
+		for i := range gpxData.Routes {
+			for j := range gpxData.Routes[i].Points {
+				processPoint(&gpxData.Routes[i].Points[j], fmt.Sprintf("route %d point", i), j)
+			}
 		}
 	}
 
 	// iterate over all tracks and segments
-	for i := range gpxData.Tracks {
-		for j := range gpxData.Tracks[i].Segments {
-			for k := range gpxData.Tracks[i].Segments[j].Points {
-				processPoint(&gpxData.Tracks[i].Segments[j].Points[k], fmt.Sprintf("track %d segment %d point", i, j), k)
+	if pointTypes.Tracks {
+		for i := range gpxData.Tracks {
+			for j := range gpxData.Tracks[i].Segments {
+				for k := range gpxData.Tracks[i].Segments[j].Points {
+					processPoint(&gpxData.Tracks[i].Segments[j].Points[k], fmt.Sprintf("track %d segment %d point", i, j), k)
+				}
 			}
 		}
 	}
@@ -344,5 +370,5 @@ func addElevationToGPX(gpxData *gpx.GPX, requestID string) (*gpx.GPX, []Elevatio
 		finalElevationSources = append(finalElevationSources, source)
 	}
 
-	return gpxData, finalElevationSources, gpxPoints, dgmPoints, nil
+	return gpxData, finalElevationSources, gpxPoints, dgmPoints, uncoveredPoints, nil
 }
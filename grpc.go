@@ -0,0 +1,632 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"klaus/elevation/dtm-elevation-service/proto/dtmv1"
+)
+
+/*
+The gRPC service (see proto/dtm-elevation-service.proto) is served on a separate listener and mirrors the REST API:
+each call is translated into a v1 request and processed by the v1 handler, including the middlewares of the public
+listener (authentication, IP filter, abuse protection, load shedding, worker pools, usage accounting). The v1 response
+is translated into the gRPC response (raster RPCs: one message per tile).
+*/
+
+// grpcForwardedMetadata lists the gRPC metadata forwarded as HTTP header fields to the v1 handler
+var grpcForwardedMetadata = []string{"authorization", "user-agent", strings.ToLower(RequestIDHeader)}
+
+// grpcElevationService implements the gRPC service over the v1 handlers.
+type grpcElevationService struct {
+	dtmv1.UnimplementedElevationServiceServer
+	handler http.Handler
+}
+
+/*
+newGRPCServer creates the gRPC server (TLS if tlsConfig is set, otherwise plaintext).
+*/
+func newGRPCServer(tlsConfig *tls.Config) *grpc.Server {
+	options := []grpc.ServerOption{grpc.MaxRecvMsgSize(MaxGpxRequestBodySize)}
+	if tlsConfig != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	server := grpc.NewServer(options...)
+
+	service := &grpcElevationService{
+		// middlewares of the public listener (without CORS, not relevant for gRPC clients)
+		handler: requestIDMiddleware(accessLogMiddleware(ipFilterMiddleware(abuseMiddleware(loadSheddingMiddleware(negotiationMiddleware(authMiddleware(workerPoolMiddleware(usageMiddleware(metaMiddleware(serviceMux)))))))))),
+	}
+	dtmv1.RegisterElevationServiceServer(server, service)
+
+	return server
+}
+
+/*
+shutdownGRPCServer stops the gRPC server gracefully (waits for pending calls until the context is done).
+*/
+func shutdownGRPCServer(ctx context.Context, server *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		server.Stop()
+	}
+}
+
+/*
+callEndpoint processes the v1 request by the v1 handler and decodes the v1 response. Returns the HTTP status of
+the v1 response, or a gRPC status error if the request was rejected without v1 response object (e.g. by authentication).
+*/
+func (s *grpcElevationService) callEndpoint(ctx context.Context, path string, v1Request any, v1Response any) (int, error) {
+	body, err := json.Marshal(v1Request)
+	if err != nil {
+		return 0, status.Errorf(codes.Internal, "error [%v] at json.Marshal()", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, path, bytes.NewReader(body))
+	if err != nil {
+		return 0, status.Errorf(codes.Internal, "error [%v] at http.NewRequestWithContext()", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, key := range grpcForwardedMetadata {
+		if values := md.Get(key); len(values) > 0 {
+			request.Header.Set(key, values[0])
+		}
+	}
+	if client, ok := peer.FromContext(ctx); ok {
+		request.RemoteAddr = client.Addr.String()
+	}
+
+	// process request with v1 handler
+	response := &v2BufferedWriter{header: make(http.Header)}
+	func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				atomic.AddUint64(&RecoveredPanics, 1)
+				slog.ErrorContext(ctx, "grpc: panic recovered", "path", path, "panic", fmt.Sprint(recovered))
+				response = &v2BufferedWriter{header: make(http.Header), status: http.StatusInternalServerError}
+			}
+		}()
+		s.handler.ServeHTTP(response, request)
+	}()
+
+	if ctx.Err() != nil {
+		return 0, status.FromContextError(ctx.Err()).Err()
+	}
+	if response.status == 0 {
+		response.status = http.StatusOK
+	}
+
+	// rejected by middleware (plain text response)
+	if !strings.HasPrefix(response.header.Get("Content-Type"), "application/json") {
+		message := strings.TrimSpace(response.body.String())
+		if message == "" {
+			message = http.StatusText(response.status)
+		}
+		return response.status, status.Error(grpcCodeFromHTTPStatus(response.status), message)
+	}
+
+	err = json.Unmarshal(response.body.Bytes(), v1Response)
+	if err != nil {
+		return response.status, status.Errorf(codes.Internal, "error [%v] at json.Unmarshal()", err)
+	}
+	return response.status, nil
+}
+
+/*
+grpcCodeFromHTTPStatus maps the HTTP status of a v1 response to a gRPC status code.
+*/
+func grpcCodeFromHTTPStatus(httpStatus int) codes.Code {
+	switch {
+	case httpStatus < 400:
+		return codes.OK
+	case httpStatus == http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case httpStatus == http.StatusForbidden:
+		return codes.PermissionDenied
+	case httpStatus == http.StatusNotFound:
+		return codes.NotFound
+	case httpStatus == http.StatusRequestEntityTooLarge, httpStatus == http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case httpStatus == http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case httpStatus == http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	case httpStatus < 500:
+		return codes.InvalidArgument
+	default:
+		return codes.Internal
+	}
+}
+
+/*
+grpcStatusError builds the gRPC status error of a failed v1 response.
+*/
+func grpcStatusError(httpStatus int, errorObject ErrorObject) error {
+	return status.Errorf(grpcCodeFromHTTPStatus(httpStatus), "%s: %s (%s)", errorObject.Code, errorObject.Title, errorObject.Detail)
+}
+
+/*
+newGRPCErrorObject converts a v1 error object (nil if the v1 response is not an error).
+*/
+func newGRPCErrorObject(isError bool, errorObject ErrorObject) *dtmv1.ErrorObject {
+	if !isError {
+		return nil
+	}
+	return &dtmv1.ErrorObject{Code: errorObject.Code, Title: errorObject.Title, Detail: errorObject.Detail}
+}
+
+// grpcLocation represents the location of a raster request in v1 attributes (UTM, lon/lat or place name).
+type grpcLocation struct {
+	Zone      int
+	Easting   float64
+	Northing  float64
+	Longitude float64
+	Latitude  float64
+	Place     string
+}
+
+/*
+newGRPCLocation converts the location of a raster request.
+*/
+func newGRPCLocation(location *dtmv1.Location) (grpcLocation, error) {
+	switch {
+	case location.GetUtm() != nil:
+		utm := location.GetUtm()
+		return grpcLocation{Zone: int(utm.GetZone()), Easting: utm.GetEasting(), Northing: utm.GetNorthing()}, nil
+	case location.GetLonLat() != nil:
+		lonLat := location.GetLonLat()
+		return grpcLocation{Longitude: lonLat.GetLongitude(), Latitude: lonLat.GetLatitude()}, nil
+	case location.GetPlace() != "":
+		return grpcLocation{Place: location.GetPlace()}, nil
+	}
+	return grpcLocation{}, status.Error(codes.InvalidArgument, "location (utm, lon_lat or place) must be set")
+}
+
+/*
+newGRPCSourceFiles converts the source files (provenance) of a tile.
+*/
+func newGRPCSourceFiles(sourceFiles []SourceFile) []*dtmv1.SourceFile {
+	var grpcSourceFiles []*dtmv1.SourceFile
+	for _, sourceFile := range sourceFiles {
+		grpcSourceFiles = append(grpcSourceFiles, &dtmv1.SourceFile{Filename: sourceFile.Filename, Sha256: sourceFile.SHA256})
+	}
+	return grpcSourceFiles
+}
+
+/*
+sendGRPCTileErrors sends one message per tile which could not be processed (partial success).
+*/
+func sendGRPCTileErrors(stream grpc.ServerStreamingServer[dtmv1.TileOutput], id string, tileErrors []TileError) error {
+	for _, tileError := range tileErrors {
+		output := &dtmv1.TileOutput{
+			Id:        id,
+			Origin:    tileError.Origin,
+			TileIndex: tileError.TileIndex,
+			Error:     &dtmv1.ErrorObject{Title: "tile not processed", Detail: tileError.Detail},
+		}
+		err := stream.Send(output)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+GetPoint returns the elevation for a point (lon/lat or place name), see POST /v1/point.
+*/
+func (s *grpcElevationService) GetPoint(ctx context.Context, grpcRequest *dtmv1.PointRequest) (*dtmv1.PointResponse, error) {
+	var pointRequest PointRequest
+	pointRequest.Type = TypePointRequest
+	pointRequest.ID = grpcRequest.GetId()
+	pointRequest.Attributes.Longitude = grpcRequest.GetLongitude()
+	pointRequest.Attributes.Latitude = grpcRequest.GetLatitude()
+	pointRequest.Attributes.Place = grpcRequest.GetPlace()
+
+	var pointResponse PointResponse
+	_, err := s.callEndpoint(ctx, EndpointPoint.Path, pointRequest, &pointResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := pointResponse.Attributes
+	return &dtmv1.PointResponse{
+		Id:          pointResponse.ID,
+		Elevation:   attributes.Elevation,
+		Actuality:   attributes.Actuality,
+		Origin:      attributes.Origin,
+		Attribution: attributes.Attribution,
+		TileIndex:   attributes.TileIndex,
+		PlaceName:   attributes.PlaceName,
+		Warnings:    attributes.Warnings,
+		Error:       newGRPCErrorObject(attributes.IsError, attributes.Error),
+	}, nil
+}
+
+/*
+GetUTMPoint returns the elevation for a UTM point, see POST /v1/utmpoint.
+*/
+func (s *grpcElevationService) GetUTMPoint(ctx context.Context, grpcRequest *dtmv1.UTMPointRequest) (*dtmv1.PointResponse, error) {
+	var utmPointRequest UTMPointRequest
+	utmPointRequest.Type = TypeUTMPointRequest
+	utmPointRequest.ID = grpcRequest.GetId()
+	utmPointRequest.Attributes.Zone = int(grpcRequest.GetZone())
+	utmPointRequest.Attributes.Easting = grpcRequest.GetEasting()
+	utmPointRequest.Attributes.Northing = grpcRequest.GetNorthing()
+
+	var utmPointResponse UTMPointResponse
+	_, err := s.callEndpoint(ctx, EndpointUTMPoint.Path, utmPointRequest, &utmPointResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := utmPointResponse.Attributes
+	return &dtmv1.PointResponse{
+		Id:          utmPointResponse.ID,
+		Elevation:   attributes.Elevation,
+		Actuality:   attributes.Actuality,
+		Origin:      attributes.Origin,
+		Attribution: attributes.Attribution,
+		TileIndex:   attributes.TileIndex,
+		Warnings:    attributes.Warnings,
+		Error:       newGRPCErrorObject(attributes.IsError, attributes.Error),
+	}, nil
+}
+
+/*
+StreamPoints returns the elevations for a batch of points (one response per request, same order).
+*/
+func (s *grpcElevationService) StreamPoints(stream grpc.BidiStreamingServer[dtmv1.PointRequest, dtmv1.PointResponse]) error {
+	for {
+		grpcRequest, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		grpcResponse, err := s.GetPoint(stream.Context(), grpcRequest)
+		if err != nil {
+			return err
+		}
+		err = stream.Send(grpcResponse)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+/*
+StreamUTMPoints returns the elevations for a batch of UTM points (one response per request, same order).
+*/
+func (s *grpcElevationService) StreamUTMPoints(stream grpc.BidiStreamingServer[dtmv1.UTMPointRequest, dtmv1.PointResponse]) error {
+	for {
+		grpcRequest, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		grpcResponse, err := s.GetUTMPoint(stream.Context(), grpcRequest)
+		if err != nil {
+			return err
+		}
+		err = stream.Send(grpcResponse)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+/*
+AddElevationToGPX returns the GPX data with elevations added, see POST /v1/gpx.
+*/
+func (s *grpcElevationService) AddElevationToGPX(ctx context.Context, grpcRequest *dtmv1.GPXRequest) (*dtmv1.GPXResponse, error) {
+	var gpxRequest GPXRequest
+	gpxRequest.Type = TypeGPXRequest
+	gpxRequest.ID = grpcRequest.GetId()
+	gpxRequest.Attributes.GPXData = base64.StdEncoding.EncodeToString(grpcRequest.GetGpxData())
+
+	var gpxResponse GPXResponse
+	_, err := s.callEndpoint(ctx, EndpointGPX.Path, gpxRequest, &gpxResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := gpxResponse.Attributes
+	gpxData, err := base64.StdEncoding.DecodeString(attributes.GPXData)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error [%v] at base64.StdEncoding.DecodeString()", err)
+	}
+	return &dtmv1.GPXResponse{
+		Id:                  gpxResponse.ID,
+		GpxData:             gpxData,
+		Points:              int32(attributes.GPXPoints),
+		PointsWithElevation: int32(attributes.DGMPoints),
+		Error:               newGRPCErrorObject(attributes.IsError, attributes.Error),
+	}, nil
+}
+
+/*
+GetRawTIF streams the raw GeoTIFF tiles (UTM location only), see POST /v1/rawtif.
+*/
+func (s *grpcElevationService) GetRawTIF(grpcRequest *dtmv1.TileRequest, stream grpc.ServerStreamingServer[dtmv1.TileOutput]) error {
+	if grpcRequest.GetLocation().GetUtm() == nil {
+		return status.Error(codes.InvalidArgument, "location must be utm for raw GeoTIFF tiles")
+	}
+	location, _ := newGRPCLocation(grpcRequest.GetLocation())
+
+	var rawTIFRequest RawTIFRequest
+	rawTIFRequest.Type = TypeRawTIFRequest
+	rawTIFRequest.ID = grpcRequest.GetId()
+	rawTIFRequest.Attributes.Zone = location.Zone
+	rawTIFRequest.Attributes.Easting = location.Easting
+	rawTIFRequest.Attributes.Northing = location.Northing
+
+	var rawTIFResponse RawTIFResponse
+	httpStatus, err := s.callEndpoint(stream.Context(), EndpointRawTIF.Path, rawTIFRequest, &rawTIFResponse)
+	if err != nil {
+		return err
+	}
+	if rawTIFResponse.Attributes.IsError {
+		return grpcStatusError(httpStatus, rawTIFResponse.Attributes.Error)
+	}
+
+	for _, rawTIF := range rawTIFResponse.Attributes.RawTIFs {
+		output := &dtmv1.TileOutput{
+			Id:          rawTIFResponse.ID,
+			Data:        rawTIF.Data,
+			DataFormat:  rawTIF.DataFormat,
+			Filename:    rawTIF.Filename,
+			Actuality:   rawTIF.Actuality,
+			Origin:      rawTIF.Origin,
+			Attribution: rawTIF.Attribution,
+			TileIndex:   rawTIF.TileIndex,
+			SourceFiles: newGRPCSourceFiles(rawTIF.SourceFiles),
+		}
+		err = stream.Send(output)
+		if err != nil {
+			return err
+		}
+	}
+	return sendGRPCTileErrors(stream, rawTIFResponse.ID, rawTIFResponse.Attributes.TileErrors)
+}
+
+/*
+GetHillshade streams the hillshade tiles (one message per tile and shading variant), see POST /v1/hillshade.
+*/
+func (s *grpcElevationService) GetHillshade(grpcRequest *dtmv1.HillshadeRequest, stream grpc.ServerStreamingServer[dtmv1.TileOutput]) error {
+	location, err := newGRPCLocation(grpcRequest.GetLocation())
+	if err != nil {
+		return err
+	}
+
+	var hillshadeRequest HillshadeRequest
+	hillshadeRequest.Type = TypeHillshadeRequest
+	hillshadeRequest.ID = grpcRequest.GetId()
+	hillshadeRequest.Attributes.Zone = location.Zone
+	hillshadeRequest.Attributes.Easting = location.Easting
+	hillshadeRequest.Attributes.Northing = location.Northing
+	hillshadeRequest.Attributes.Longitude = location.Longitude
+	hillshadeRequest.Attributes.Latitude = location.Latitude
+	hillshadeRequest.Attributes.Place = location.Place
+	hillshadeRequest.Attributes.GradientAlgorithm = grpcRequest.GetGradientAlgorithm()
+	hillshadeRequest.Attributes.VerticalExaggeration = VerticalExaggeration(grpcRequest.GetVerticalExaggeration())
+	hillshadeRequest.Attributes.AzimuthOfLight = uint(grpcRequest.GetAzimuthOfLight())
+	hillshadeRequest.Attributes.AltitudeOfLight = uint(grpcRequest.GetAltitudeOfLight())
+	hillshadeRequest.Attributes.ShadingVariant = grpcRequest.GetShadingVariants()
+	hillshadeRequest.Attributes.OutputFormat = grpcRequest.GetOutputFormat()
+	for _, lightSource := range grpcRequest.GetLightSources() {
+		hillshadeRequest.Attributes.LightSources = append(hillshadeRequest.Attributes.LightSources,
+			LightSource{Azimuth: lightSource.GetAzimuth(), Altitude: lightSource.GetAltitude(), Weight: lightSource.GetWeight()})
+	}
+
+	var hillshadeResponse HillshadeResponse
+	httpStatus, err := s.callEndpoint(stream.Context(), EndpointHillshade.Path, hillshadeRequest, &hillshadeResponse)
+	if err != nil {
+		return err
+	}
+	if hillshadeResponse.Attributes.IsError {
+		return grpcStatusError(httpStatus, hillshadeResponse.Attributes.Error)
+	}
+
+	for _, hillshade := range hillshadeResponse.Attributes.Hillshades {
+		output := &dtmv1.TileOutput{
+			Id:          hillshadeResponse.ID,
+			Data:        hillshade.Data,
+			DataFormat:  hillshade.DataFormat,
+			Filename:    hillshade.Filename,
+			Actuality:   hillshade.Actuality,
+			Origin:      hillshade.Origin,
+			Attribution: hillshade.Attribution,
+			TileIndex:   hillshade.TileIndex,
+			Variant:     hillshade.ShadingVariant,
+			SourceFiles: newGRPCSourceFiles(hillshade.SourceFiles),
+		}
+		err = stream.Send(output)
+		if err != nil {
+			return err
+		}
+	}
+	return sendGRPCTileErrors(stream, hillshadeResponse.ID, hillshadeResponse.Attributes.TileErrors)
+}
+
+/*
+GetSlope streams the slope tiles, see POST /v1/slope.
+*/
+func (s *grpcElevationService) GetSlope(grpcRequest *dtmv1.RasterRequest, stream grpc.ServerStreamingServer[dtmv1.TileOutput]) error {
+	location, err := newGRPCLocation(grpcRequest.GetLocation())
+	if err != nil {
+		return err
+	}
+
+	var slopeRequest SlopeRequest
+	slopeRequest.Type = TypeSlopeRequest
+	slopeRequest.ID = grpcRequest.GetId()
+	slopeRequest.Attributes.Zone = location.Zone
+	slopeRequest.Attributes.Easting = location.Easting
+	slopeRequest.Attributes.Northing = location.Northing
+	slopeRequest.Attributes.Longitude = location.Longitude
+	slopeRequest.Attributes.Latitude = location.Latitude
+	slopeRequest.Attributes.Place = location.Place
+	slopeRequest.Attributes.GradientAlgorithm = grpcRequest.GetGradientAlgorithm()
+	slopeRequest.Attributes.ColorTextFileContent = grpcRequest.GetColorTextFileContent()
+	slopeRequest.Attributes.ColorTableName = grpcRequest.GetColorTableName()
+	slopeRequest.Attributes.ColoringAlgorithm = grpcRequest.GetColoringAlgorithm()
+	slopeRequest.Attributes.OutputFormat = grpcRequest.GetOutputFormat()
+
+	var slopeResponse SlopeResponse
+	httpStatus, err := s.callEndpoint(stream.Context(), EndpointSlope.Path, slopeRequest, &slopeResponse)
+	if err != nil {
+		return err
+	}
+	if slopeResponse.Attributes.IsError {
+		return grpcStatusError(httpStatus, slopeResponse.Attributes.Error)
+	}
+
+	for _, slope := range slopeResponse.Attributes.Slopes {
+		output := &dtmv1.TileOutput{
+			Id:          slopeResponse.ID,
+			Data:        slope.Data,
+			DataFormat:  slope.DataFormat,
+			Filename:    slope.Filename,
+			Actuality:   slope.Actuality,
+			Origin:      slope.Origin,
+			Attribution: slope.Attribution,
+			TileIndex:   slope.TileIndex,
+			SourceFiles: newGRPCSourceFiles(slope.SourceFiles),
+		}
+		err = stream.Send(output)
+		if err != nil {
+			return err
+		}
+	}
+	return sendGRPCTileErrors(stream, slopeResponse.ID, slopeResponse.Attributes.TileErrors)
+}
+
+/*
+GetAspect streams the aspect tiles, see POST /v1/aspect.
+*/
+func (s *grpcElevationService) GetAspect(grpcRequest *dtmv1.RasterRequest, stream grpc.ServerStreamingServer[dtmv1.TileOutput]) error {
+	location, err := newGRPCLocation(grpcRequest.GetLocation())
+	if err != nil {
+		return err
+	}
+
+	var aspectRequest AspectRequest
+	aspectRequest.Type = TypeAspectRequest
+	aspectRequest.ID = grpcRequest.GetId()
+	aspectRequest.Attributes.Zone = location.Zone
+	aspectRequest.Attributes.Easting = location.Easting
+	aspectRequest.Attributes.Northing = location.Northing
+	aspectRequest.Attributes.Longitude = location.Longitude
+	aspectRequest.Attributes.Latitude = location.Latitude
+	aspectRequest.Attributes.Place = location.Place
+	aspectRequest.Attributes.GradientAlgorithm = grpcRequest.GetGradientAlgorithm()
+	aspectRequest.Attributes.ColorTextFileContent = grpcRequest.GetColorTextFileContent()
+	aspectRequest.Attributes.ColorTableName = grpcRequest.GetColorTableName()
+	aspectRequest.Attributes.ColoringAlgorithm = grpcRequest.GetColoringAlgorithm()
+	aspectRequest.Attributes.OutputFormat = grpcRequest.GetOutputFormat()
+
+	var aspectResponse AspectResponse
+	httpStatus, err := s.callEndpoint(stream.Context(), EndpointAspect.Path, aspectRequest, &aspectResponse)
+	if err != nil {
+		return err
+	}
+	if aspectResponse.Attributes.IsError {
+		return grpcStatusError(httpStatus, aspectResponse.Attributes.Error)
+	}
+
+	for _, aspect := range aspectResponse.Attributes.Aspects {
+		output := &dtmv1.TileOutput{
+			Id:          aspectResponse.ID,
+			Data:        aspect.Data,
+			DataFormat:  aspect.DataFormat,
+			Filename:    aspect.Filename,
+			Actuality:   aspect.Actuality,
+			Origin:      aspect.Origin,
+			Attribution: aspect.Attribution,
+			TileIndex:   aspect.TileIndex,
+			SourceFiles: newGRPCSourceFiles(aspect.SourceFiles),
+		}
+		err = stream.Send(output)
+		if err != nil {
+			return err
+		}
+	}
+	return sendGRPCTileErrors(stream, aspectResponse.ID, aspectResponse.Attributes.TileErrors)
+}
+
+/*
+GetContours streams the contour lines (one message per tile), see POST /v1/contours.
+*/
+func (s *grpcElevationService) GetContours(grpcRequest *dtmv1.ContoursRequest, stream grpc.ServerStreamingServer[dtmv1.TileOutput]) error {
+	location, err := newGRPCLocation(grpcRequest.GetLocation())
+	if err != nil {
+		return err
+	}
+
+	var contoursRequest ContoursRequest
+	contoursRequest.Type = TypeContoursRequest
+	contoursRequest.ID = grpcRequest.GetId()
+	contoursRequest.Attributes.Zone = location.Zone
+	contoursRequest.Attributes.Easting = location.Easting
+	contoursRequest.Attributes.Northing = location.Northing
+	contoursRequest.Attributes.Longitude = location.Longitude
+	contoursRequest.Attributes.Latitude = location.Latitude
+	contoursRequest.Attributes.Place = location.Place
+	contoursRequest.Attributes.Equidistance = grpcRequest.GetEquidistance()
+
+	var contoursResponse ContoursResponse
+	httpStatus, err := s.callEndpoint(stream.Context(), EndpointContours.Path, contoursRequest, &contoursResponse)
+	if err != nil {
+		return err
+	}
+	if contoursResponse.Attributes.IsError {
+		return grpcStatusError(httpStatus, contoursResponse.Attributes.Error)
+	}
+
+	for _, contour := range contoursResponse.Attributes.Contours {
+		output := &dtmv1.TileOutput{
+			Id:          contoursResponse.ID,
+			Data:        contour.Data,
+			DataFormat:  contour.DataFormat,
+			Filename:    contour.Filename,
+			Actuality:   contour.Actuality,
+			Origin:      contour.Origin,
+			Attribution: contour.Attribution,
+			TileIndex:   contour.TileIndex,
+		}
+		err = stream.Send(output)
+		if err != nil {
+			return err
+		}
+	}
+	return sendGRPCTileErrors(stream, contoursResponse.ID, contoursResponse.Attributes.TileErrors)
+}
@@ -1,18 +1,22 @@
 package main
 
 import (
-	"bytes"
-	"compress/gzip"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"math"
 	"net/http"
-	"os"
-	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"sync/atomic"
+
+	"github.com/airbusgeo/godal"
+
+	"klaus/elevation/dtm-elevation-service/pkg/dtm"
 )
 
 /*
@@ -34,18 +38,14 @@ func hillshadeRequest(writer http.ResponseWriter, request *http.Request) {
 		// check specifically for the error returned by MaxBytesReader
 		var maxBytesErr *http.MaxBytesError
 		if errors.As(err, &maxBytesErr) {
-			slog.Warn("hillshade request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
-			hillshadeResponse.Attributes.Error.Code = "5000"
-			hillshadeResponse.Attributes.Error.Title = "request body too large"
-			hillshadeResponse.Attributes.Error.Detail = fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit)
-			buildHillshadeResponse(writer, http.StatusRequestEntityTooLarge, hillshadeResponse)
+			slog.WarnContext(request.Context(), "hillshade request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
+			hillshadeResponse.Attributes.Error = newErrorObject(EndpointHillshade, ReasonRequestBodyTooLarge, fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit))
+			buildHillshadeResponse(writer, request, http.StatusRequestEntityTooLarge, hillshadeResponse)
 		} else {
 			// handle other read errors
-			slog.Warn("hillshade request: error reading request body", "error", err, "ID", "unknown")
-			hillshadeResponse.Attributes.Error.Code = "5020"
-			hillshadeResponse.Attributes.Error.Title = "error reading request body"
-			hillshadeResponse.Attributes.Error.Detail = err.Error()
-			buildHillshadeResponse(writer, http.StatusBadRequest, hillshadeResponse)
+			slog.WarnContext(request.Context(), "hillshade request: error reading request body", "error", err, "ID", "unknown")
+			hillshadeResponse.Attributes.Error = newErrorObject(EndpointHillshade, ReasonReadingRequestBody, err.Error())
+			buildHillshadeResponse(writer, request, http.StatusBadRequest, hillshadeResponse)
 		}
 		return
 	}
@@ -54,14 +54,39 @@ func hillshadeRequest(writer http.ResponseWriter, request *http.Request) {
 	hillshadeRequest := HillshadeRequest{}
 	err = json.Unmarshal(bodyData, &hillshadeRequest)
 	if err != nil {
-		slog.Warn("hillshade request: error unmarshaling request body", "error", err, "ID", "unknown")
-		hillshadeResponse.Attributes.Error.Code = "5040"
-		hillshadeResponse.Attributes.Error.Title = "error unmarshaling request body"
-		hillshadeResponse.Attributes.Error.Detail = err.Error()
-		buildHillshadeResponse(writer, http.StatusBadRequest, hillshadeResponse)
+		slog.WarnContext(request.Context(), "hillshade request: error unmarshaling request body", "error", err, "ID", "unknown")
+		hillshadeResponse.Attributes.Error = newErrorObject(EndpointHillshade, ReasonUnmarshalingRequestBody, err.Error())
+		buildHillshadeResponse(writer, request, http.StatusBadRequest, hillshadeResponse)
 		return
 	}
 
+	// resolve place name (geocoding) into lon/lat coordinates
+	if hillshadeRequest.Attributes.Place != "" {
+		place, err := geocodePlace(request.Context(), hillshadeRequest.Attributes.Place)
+		if err != nil {
+			slog.WarnContext(request.Context(), "hillshade request: error geocoding place", "error", err, "place", hillshadeRequest.Attributes.Place, "ID", hillshadeRequest.ID)
+			hillshadeResponse.ID = hillshadeRequest.ID
+			hillshadeResponse.Attributes.Place = hillshadeRequest.Attributes.Place
+			if errors.Is(err, ErrPlaceNotFound) {
+				hillshadeResponse.Attributes.Error = newErrorObject(EndpointHillshade, ReasonPlaceNotFound, err.Error())
+				buildHillshadeResponse(writer, request, http.StatusNotFound, hillshadeResponse)
+				return
+			}
+			if errors.Is(err, ErrGeocoderUnavailable) {
+				hillshadeResponse.Attributes.Error = newErrorObject(EndpointHillshade, ReasonGeocoderUnavailable, err.Error())
+				buildHillshadeResponse(writer, request, http.StatusServiceUnavailable, hillshadeResponse)
+				return
+			}
+			hillshadeResponse.Attributes.Error = newErrorObject(EndpointHillshade, ReasonVerifyingRequestData, err.Error())
+			buildHillshadeResponse(writer, request, http.StatusBadRequest, hillshadeResponse)
+			return
+		}
+		hillshadeRequest.Attributes.Zone = 0
+		hillshadeRequest.Attributes.Longitude = place.Longitude
+		hillshadeRequest.Attributes.Latitude = place.Latitude
+		hillshadeResponse.Attributes.PlaceName = place.DisplayName
+	}
+
 	// copy request parameters into response
 	hillshadeResponse.ID = hillshadeRequest.ID
 	hillshadeResponse.Attributes.Zone = hillshadeRequest.Attributes.Zone
@@ -69,22 +94,34 @@ func hillshadeRequest(writer http.ResponseWriter, request *http.Request) {
 	hillshadeResponse.Attributes.Northing = hillshadeRequest.Attributes.Northing
 	hillshadeResponse.Attributes.Longitude = hillshadeRequest.Attributes.Longitude
 	hillshadeResponse.Attributes.Latitude = hillshadeRequest.Attributes.Latitude
+	hillshadeResponse.Attributes.Place = hillshadeRequest.Attributes.Place
+	hillshadeResponse.Attributes.AdministrativeArea = hillshadeRequest.Attributes.AdministrativeArea
+	hillshadeResponse.Attributes.OutputFormat = hillshadeRequest.Attributes.OutputFormat
 	hillshadeResponse.Attributes.GradientAlgorithm = hillshadeRequest.Attributes.GradientAlgorithm
 	hillshadeResponse.Attributes.VerticalExaggeration = hillshadeRequest.Attributes.VerticalExaggeration
 	hillshadeResponse.Attributes.AzimuthOfLight = hillshadeRequest.Attributes.AzimuthOfLight
 	hillshadeResponse.Attributes.AltitudeOfLight = hillshadeRequest.Attributes.AltitudeOfLight
 	hillshadeResponse.Attributes.ShadingVariant = hillshadeRequest.Attributes.ShadingVariant
+	hillshadeResponse.Attributes.LightSources = hillshadeRequest.Attributes.LightSources
 
 	// verify request data
 	err = verifyHillshadeRequestData(request, hillshadeRequest)
 	if err != nil {
-		slog.Warn("hillshade request: error verifying request data", "error", err, "ID", hillshadeRequest.ID)
-		hillshadeResponse.Attributes.Error.Code = "5060"
-		hillshadeResponse.Attributes.Error.Title = "error verifying request data"
-		hillshadeResponse.Attributes.Error.Detail = err.Error()
-		buildHillshadeResponse(writer, http.StatusBadRequest, hillshadeResponse)
+		slog.WarnContext(request.Context(), "hillshade request: error verifying request data", "error", err, "ID", hillshadeRequest.ID)
+		hillshadeResponse.Attributes.Error = newErrorObject(EndpointHillshade, ReasonVerifyingRequestData, err.Error())
+		buildHillshadeResponse(writer, request, http.StatusBadRequest, hillshadeResponse)
 		return
 	}
+	if hillshadeRequest.Attributes.VerticalExaggeration == 0 {
+		hillshadeResponse.Attributes.Warnings = append(hillshadeResponse.Attributes.Warnings, "VerticalExaggeration 0.0 results in a uniform (flat) hillshade")
+	}
+	if slices.ContainsFunc(hillshadeRequest.Attributes.ShadingVariant, func(variant string) bool { return strings.ToLower(variant) == "multidirectional" }) {
+		hillshadeResponse.Attributes.Warnings = append(hillshadeResponse.Attributes.Warnings, "AzimuthOfLight ignored for shading variant 'multidirectional'")
+	}
+	isCustomVariant := slices.ContainsFunc(hillshadeRequest.Attributes.ShadingVariant, func(variant string) bool { return strings.ToLower(variant) == "custom" })
+	if len(hillshadeRequest.Attributes.LightSources) > 0 && !isCustomVariant {
+		hillshadeResponse.Attributes.Warnings = append(hillshadeResponse.Attributes.Warnings, "LightSources ignored without shading variant 'custom'")
+	}
 
 	zone := 0
 	easting := 0.0
@@ -95,7 +132,45 @@ func hillshadeRequest(writer http.ResponseWriter, request *http.Request) {
 	var outputFormat string
 
 	// determine type of coordinates
-	if hillshadeRequest.Attributes.Zone != 0 {
+	if hillshadeRequest.Attributes.AdministrativeArea != "" {
+		// input from administrative area (mosaic of all tiles clipped to boundary, UTM zone of area)
+		outputFormat = "png"
+		if hillshadeRequest.Attributes.Zone != 0 {
+			outputFormat = "geotiff"
+		}
+
+		// get (virtual) tile for administrative area
+		areaTile, vsimemFiles, err := getAdministrativeAreaTile(request.Context(), hillshadeRequest.Attributes.AdministrativeArea)
+		if err != nil {
+			slog.WarnContext(request.Context(), "hillshade request: error getting tile for administrative area", "error", err,
+				"administrative area", hillshadeRequest.Attributes.AdministrativeArea, "ID", hillshadeRequest.ID)
+			if errors.Is(err, context.Canceled) {
+				// client disconnected, processing aborted, no response required
+				return
+			}
+			if errors.Is(err, ErrAdministrativeAreaNotFound) {
+				hillshadeResponse.Attributes.Error = newErrorObject(EndpointHillshade, ReasonAdministrativeAreaNotFound, err.Error())
+				buildHillshadeResponse(writer, request, http.StatusNotFound, hillshadeResponse)
+				return
+			}
+			if errors.Is(err, ErrOutsideCoverage) {
+				hillshadeResponse.Attributes.Error = newErrorObject(EndpointHillshade, ReasonOutsideCoverage, err.Error())
+				buildHillshadeResponse(writer, request, http.StatusNotFound, hillshadeResponse)
+				return
+			}
+			if errors.Is(err, ErrServerBusy) {
+				hillshadeResponse.Attributes.Error = newErrorObject(EndpointHillshade, ReasonServerBusy, err.Error())
+				writer.Header().Set("Retry-After", strconv.Itoa(getProgConfig().GDALJobQueue.RetryAfter))
+				buildHillshadeResponse(writer, request, http.StatusTooManyRequests, hillshadeResponse)
+				return
+			}
+			hillshadeResponse.Attributes.Error = newErrorObject(EndpointHillshade, ReasonVerifyingRequestData, err.Error())
+			buildHillshadeResponse(writer, request, http.StatusBadRequest, hillshadeResponse)
+			return
+		}
+		defer removeVSIMemFiles(vsimemFiles...)
+		tiles = []TileMetadata{areaTile}
+	} else if hillshadeRequest.Attributes.Zone != 0 {
 		// input from UTM coordinates
 		zone = hillshadeRequest.Attributes.Zone
 		easting = hillshadeRequest.Attributes.Easting
@@ -105,12 +180,20 @@ func hillshadeRequest(writer http.ResponseWriter, request *http.Request) {
 		// get all tiles (metadata) for given UTM coordinates
 		tiles, err = getAllTilesUTM(zone, easting, northing)
 		if err != nil {
-			slog.Warn("hillshade request: error getting GeoTIFF tile for UTM coordinates", "error", err,
+			slog.WarnContext(request.Context(), "hillshade request: error getting GeoTIFF tile for UTM coordinates", "error", err,
 				"easting", easting, "northing", northing, "zone", zone, "ID", hillshadeRequest.ID)
-			hillshadeResponse.Attributes.Error.Code = "5080"
-			hillshadeResponse.Attributes.Error.Title = "getting GeoTIFF tile for UTM coordinates"
-			hillshadeResponse.Attributes.Error.Detail = err.Error()
-			buildHillshadeResponse(writer, http.StatusBadRequest, hillshadeResponse)
+			if errors.Is(err, ErrOutsideCoverage) {
+				hillshadeResponse.Attributes.Error = newErrorObject(EndpointHillshade, ReasonOutsideCoverage, describeOutsideCoverage(err))
+				buildHillshadeResponse(writer, request, http.StatusNotFound, hillshadeResponse)
+				return
+			}
+			if errors.Is(err, ErrSourceUnavailable) {
+				hillshadeResponse.Attributes.Error = newErrorObject(EndpointHillshade, ReasonSourceUnavailable, err.Error())
+				buildHillshadeResponse(writer, request, http.StatusServiceUnavailable, hillshadeResponse)
+				return
+			}
+			hillshadeResponse.Attributes.Error = newErrorObject(EndpointHillshade, ReasonGettingTileUTM, err.Error())
+			buildHillshadeResponse(writer, request, http.StatusBadRequest, hillshadeResponse)
 			return
 		}
 	} else {
@@ -123,38 +206,100 @@ func hillshadeRequest(writer http.ResponseWriter, request *http.Request) {
 		tiles, err = getAllTilesLonLat(longitude, latitude)
 		if err != nil {
 			err = fmt.Errorf("error [%w] getting tile for coordinates lon: %.8f, lat: %.8f", err, longitude, latitude)
-			slog.Warn("hillshade request: error getting GeoTIFF tile for lon/lat coordinates", "error", err,
+			slog.WarnContext(request.Context(), "hillshade request: error getting GeoTIFF tile for lon/lat coordinates", "error", err,
 				"longitude", longitude, "latitude", latitude, "ID", hillshadeRequest.ID)
-			hillshadeResponse.Attributes.Error.Code = "5100"
-			hillshadeResponse.Attributes.Error.Title = "getting GeoTIFF tile for lon/lat coordinates"
-			hillshadeResponse.Attributes.Error.Detail = err.Error()
-			buildHillshadeResponse(writer, http.StatusBadRequest, hillshadeResponse)
+			if errors.Is(err, ErrOutsideCoverage) {
+				hillshadeResponse.Attributes.Error = newErrorObject(EndpointHillshade, ReasonOutsideCoverage, describeOutsideCoverage(err))
+				buildHillshadeResponse(writer, request, http.StatusNotFound, hillshadeResponse)
+				return
+			}
+			if errors.Is(err, ErrSourceUnavailable) {
+				hillshadeResponse.Attributes.Error = newErrorObject(EndpointHillshade, ReasonSourceUnavailableLonLat, err.Error())
+				buildHillshadeResponse(writer, request, http.StatusServiceUnavailable, hillshadeResponse)
+				return
+			}
+			hillshadeResponse.Attributes.Error = newErrorObject(EndpointHillshade, ReasonGettingTileLonLat, err.Error())
+			buildHillshadeResponse(writer, request, http.StatusBadRequest, hillshadeResponse)
 			return
 		}
 	}
 
+	// explicit output format (overrides default for type of coordinates)
+	if hillshadeRequest.Attributes.OutputFormat != "" {
+		outputFormat = strings.ToLower(hillshadeRequest.Attributes.OutputFormat)
+	}
+
+	// conditional request: unchanged response (same parameters, same tiles) needs no processing
+	etag := buildETag("hillshade", hillshadeRequest, tiles)
+	if checkNotModified(writer, request, etag) {
+		return
+	}
+
 	// build hillshade for all existing tiles
 	gradientAlgorithm := hillshadeRequest.Attributes.GradientAlgorithm
 	verticalExaggeration := hillshadeRequest.Attributes.VerticalExaggeration
 	azimuthOfLight := hillshadeRequest.Attributes.AzimuthOfLight
 	altitudeOfLight := hillshadeRequest.Attributes.AltitudeOfLight
-	shadingVariant := hillshadeRequest.Attributes.ShadingVariant
-	for _, tile := range tiles {
-		hillshade, err := generateHillshadeObjectForTile(tile, outputFormat, gradientAlgorithm, verticalExaggeration, azimuthOfLight, altitudeOfLight, shadingVariant)
-		if err != nil {
-			slog.Warn("hillshade request: error generating hillshade object for tile", "error", err, "ID", hillshadeRequest.ID)
-			hillshadeResponse.Attributes.Error.Code = "5120"
-			hillshadeResponse.Attributes.Error.Title = "error generating hillshade object for tile"
-			hillshadeResponse.Attributes.Error.Detail = err.Error()
-			buildHillshadeResponse(writer, http.StatusBadRequest, hillshadeResponse)
+	shadingVariants := hillshadeRequest.Attributes.ShadingVariant
+	lightSources := hillshadeRequest.Attributes.LightSources
+	tileHillshades, tileErrors, err := generateObjectsForTiles(request.Context(), tiles, func(tile TileMetadata) ([]Hillshade, error) {
+		// vertical exaggeration derived from relief of tile
+		tileVerticalExaggeration := float64(verticalExaggeration)
+		if verticalExaggeration == VerticalExaggerationAuto {
+			var err error
+			tileVerticalExaggeration, err = deriveVerticalExaggeration(request.Context(), tile)
+			if err != nil {
+				return nil, fmt.Errorf("error [%w] at deriveVerticalExaggeration()", err)
+			}
+		}
+
+		// all shading variants for the tile (tile resolved once)
+		var variantHillshades []Hillshade
+		for _, shadingVariant := range shadingVariants {
+			hillshade, err := generateHillshadeObjectForTile(request.Context(), tile, outputFormat, hillshadeRequest.Attributes.GeoTIFFOptions, gradientAlgorithm, tileVerticalExaggeration, azimuthOfLight, altitudeOfLight, shadingVariant, lightSources)
+			if err != nil {
+				return nil, fmt.Errorf("error [%w] at generateHillshadeObjectForTile(), shading variant: %s", err, shadingVariant)
+			}
+			variantHillshades = append(variantHillshades, hillshade)
+		}
+		return variantHillshades, nil
+	})
+	if err != nil {
+		slog.WarnContext(request.Context(), "hillshade request: error generating hillshade object for tile", "error", err, "ID", hillshadeRequest.ID)
+		if errors.Is(err, context.Canceled) {
+			// client disconnected, processing aborted, no response required
+			return
+		}
+		if errors.Is(err, ErrServerBusy) {
+			hillshadeResponse.Attributes.Error = newErrorObject(EndpointHillshade, ReasonServerBusy, err.Error())
+			writer.Header().Set("Retry-After", strconv.Itoa(getProgConfig().GDALJobQueue.RetryAfter))
+			buildHillshadeResponse(writer, request, http.StatusTooManyRequests, hillshadeResponse)
 			return
 		}
-		hillshadeResponse.Attributes.Hillshades = append(hillshadeResponse.Attributes.Hillshades, hillshade)
+		hillshadeResponse.Attributes.Error = newErrorObject(EndpointHillshade, ReasonGeneratingObject, err.Error())
+		buildHillshadeResponse(writer, request, http.StatusBadRequest, hillshadeResponse)
+		return
+	}
+	var hillshades []Hillshade
+	for _, variantHillshades := range tileHillshades {
+		hillshades = append(hillshades, variantHillshades...)
+	}
+	hillshadeResponse.Attributes.Hillshades = hillshades
+	hillshadeResponse.Attributes.TileErrors = tileErrors
+	if len(tileErrors) > 0 {
+		hillshadeResponse.Attributes.Warnings = append(hillshadeResponse.Attributes.Warnings,
+			fmt.Sprintf("%d of %d tiles could not be processed (see TileErrors)", len(tileErrors), len(tiles)))
+		slog.WarnContext(request.Context(), "hillshade request: partial success, tiles could not be processed", "tile errors", tileErrors, "ID", hillshadeRequest.ID)
 	}
+	addUsage(request.Context(), 0, len(hillshades))
 
 	// success response
 	hillshadeResponse.Attributes.IsError = false
-	buildHillshadeResponse(writer, http.StatusOK, hillshadeResponse)
+	if len(tileErrors) == 0 {
+		// partial responses are not cacheable
+		setETag(writer, etag)
+	}
+	buildHillshadeResponse(writer, request, http.StatusOK, hillshadeResponse)
 }
 
 /*
@@ -175,18 +320,6 @@ func verifyHillshadeRequestData(request *http.Request, hillshadeRequest Hillshad
 		return fmt.Errorf("unexpected or missing HTTP header field Content-Type, value = [%s], expected 'application/json'", contentType)
 	}
 
-	// verify HTTP header
-	accept := request.Header.Get("Accept")
-	isAcceptValid := true
-	switch {
-	case strings.HasPrefix(strings.ToLower(accept), "application/json"):
-	default:
-		isAcceptValid = false
-	}
-	if !isAcceptValid {
-		return fmt.Errorf("unexpected or missing HTTP header field Accept, value = [%s], expected 'application/json'", accept)
-	}
-
 	// verify Type
 	if hillshadeRequest.Type != TypeHillshadeRequest {
 		return fmt.Errorf("unexpected request Type [%v]", hillshadeRequest.Type)
@@ -198,7 +331,7 @@ func verifyHillshadeRequestData(request *http.Request, hillshadeRequest Hillshad
 	}
 
 	// verify coordinates (either utm or lon/lat coordinates must be set)
-	if hillshadeRequest.Attributes.Zone == 0 && hillshadeRequest.Attributes.Longitude == 0 {
+	if hillshadeRequest.Attributes.AdministrativeArea == "" && hillshadeRequest.Attributes.Zone == 0 && hillshadeRequest.Attributes.Longitude == 0 {
 		return errors.New("either utm or lon/lat coordinates must be set")
 	}
 
@@ -229,8 +362,10 @@ func verifyHillshadeRequestData(request *http.Request, hillshadeRequest Hillshad
 	}
 
 	// verify vertical exaggeration
-	if hillshadeRequest.Attributes.VerticalExaggeration < 0.0 || hillshadeRequest.Attributes.VerticalExaggeration > 100.0 {
-		return errors.New("vertical exaggeration must be between 0.0 and 100.0")
+	if hillshadeRequest.Attributes.VerticalExaggeration != VerticalExaggerationAuto {
+		if hillshadeRequest.Attributes.VerticalExaggeration < 0.0 || hillshadeRequest.Attributes.VerticalExaggeration > 100.0 {
+			return errors.New("vertical exaggeration must be between 0.0 and 100.0 or \"auto\"")
+		}
 	}
 
 	// verify azimuth of light source
@@ -243,14 +378,59 @@ func verifyHillshadeRequestData(request *http.Request, hillshadeRequest Hillshad
 		return errors.New("altitude of light source must be between 0 and 90")
 	}
 
-	// verify shading variant
-	switch strings.ToLower(hillshadeRequest.Attributes.ShadingVariant) {
-	case "regular":
-	case "combined":
-	case "multidirectional":
-	case "igor":
+	// verify shading variants (1-4 different variants)
+	if len(hillshadeRequest.Attributes.ShadingVariant) < 1 || len(hillshadeRequest.Attributes.ShadingVariant) > 4 {
+		return errors.New("number of shading variants must be between 1 and 4")
+	}
+	seenVariants := make(map[string]bool)
+	for _, shadingVariant := range hillshadeRequest.Attributes.ShadingVariant {
+		switch strings.ToLower(shadingVariant) {
+		case "regular":
+		case "combined":
+		case "multidirectional":
+		case "igor":
+		case "textured":
+		case "custom":
+		default:
+			return errors.New("unsupported shading variant (not regular, combined, multidirectional, igor, textured, custom)")
+		}
+		if seenVariants[strings.ToLower(shadingVariant)] {
+			return fmt.Errorf("duplicate shading variant [%s]", shadingVariant)
+		}
+		seenVariants[strings.ToLower(shadingVariant)] = true
+	}
+
+	// verify light sources (required for shading variant 'custom')
+	if seenVariants["custom"] {
+		if len(hillshadeRequest.Attributes.LightSources) < 1 || len(hillshadeRequest.Attributes.LightSources) > 8 {
+			return errors.New("number of light sources (shading variant 'custom') must be between 1 and 8")
+		}
+		for i, lightSource := range hillshadeRequest.Attributes.LightSources {
+			if lightSource.Azimuth < 0.0 || lightSource.Azimuth > 360.0 {
+				return fmt.Errorf("azimuth of light source %d must be between 0.0 and 360.0", i+1)
+			}
+			if lightSource.Altitude < 0.0 || lightSource.Altitude > 90.0 {
+				return fmt.Errorf("altitude of light source %d must be between 0.0 and 90.0", i+1)
+			}
+			if lightSource.Weight <= 0.0 || lightSource.Weight > 100.0 {
+				return fmt.Errorf("weight of light source %d must be greater than 0.0 and at most 100.0", i+1)
+			}
+		}
+	}
+
+	// verify output format
+	switch strings.ToLower(hillshadeRequest.Attributes.OutputFormat) {
+	case "":
+	case "geotiff":
+	case "png":
 	default:
-		return errors.New("unsupported shading variant (not regular, combined, multidirectional, igor)")
+		return errors.New("unsupported output format (not geotiff, png)")
+	}
+
+	// verify GeoTIFF creation options
+	err := verifyGeoTIFFOptions(hillshadeRequest.Attributes.GeoTIFFOptions)
+	if err != nil {
+		return err
 	}
 
 	return nil
@@ -261,56 +441,15 @@ buildHillshadeResponse builds HTTP responses with specified status and body.
 It sets the Content-Type and Content-Length headers before writing the response body.
 This function is used to construct consistent HTTP responses throughout the application.
 */
-func buildHillshadeResponse(writer http.ResponseWriter, httpStatus int, hillshadeResponse HillshadeResponse) {
-	// log limit length of body (hillshade objects as part of the body can be very large)
-	maxBodyLength := 1024
-
-	// CORS: allow requests from any origin
-	writer.Header().Set("Access-Control-Allow-Origin", "*")
-	// CORS: allowed methods
-	writer.Header().Set("Access-Control-Allow-Methods", "POST")
-	// CORS: allowed headers
-	writer.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
-	// marshal response
-	body, err := json.MarshalIndent(hillshadeResponse, "", "  ")
-	if err != nil {
-		slog.Error("error marshaling point response", "error", err, "body length", len(body),
-			fmt.Sprintf("body (limited to first %d bytes)", maxBodyLength), body[:maxBodyLength])
-
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
+func buildHillshadeResponse(writer http.ResponseWriter, request *http.Request, httpStatus int, hillshadeResponse HillshadeResponse) {
+	// response metadata (versions, processing duration, cache hit)
+	hillshadeResponse.Meta = newResponseMeta(request.Context())
 
-	// gzip response body
-	var bytesBuffer bytes.Buffer
-	gz := gzip.NewWriter(&bytesBuffer)
+	// skip compression if payload is already compressed (e.g. PNG)
+	skipCompression := len(hillshadeResponse.Attributes.Hillshades) > 0 && isCompressedDataFormat(hillshadeResponse.Attributes.Hillshades[0].DataFormat)
 
-	_, err = gz.Write(body)
-	if err != nil {
-		slog.Error("error [%v] at gz.Write()", "error", err)
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-
-	err = gz.Close()
-	if err != nil {
-		slog.Error("error [%v] at gz.Close()", "error", err)
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-
-	// set headers
-	writer.Header().Set("Content-Encoding", "gzip")
-	writer.Header().Set("Content-Type", JSONAPIMediaType)
-	writer.WriteHeader(httpStatus)
-
-	// send response
-	_, err = writer.Write(bytesBuffer.Bytes())
-	if err != nil {
-		slog.Error("error writing HTTP response body", "error", err, "body length", len(body),
-			fmt.Sprintf("body (limited to first %d bytes)", maxBodyLength), body[:maxBodyLength])
-	}
+	// encode (streaming) and send response
+	streamJSONResponse(writer, request, httpStatus, hillshadeResponse, skipCompression)
 }
 
 /*
@@ -327,29 +466,30 @@ PNG in webmercator projection with bounding box in wgs84 coordinates:
     gdal_translate -of PNG 32_409_5790.hillshade.webmercator.tif 32_409_5790.hillshade.webmercator.png
  4. get bounding box (in wgs84) for webmercator tif (georeference for webmercator png)
 */
-func generateHillshadeObjectForTile(tile TileMetadata, outputFormat string, gradientAlgorithm string,
-	verticalExaggeration float64, azimuthOfLight uint, altitudeOfLight uint, shadingVariant string) (Hillshade, error) {
+func generateHillshadeObjectForTile(ctx context.Context, tile TileMetadata, outputFormat string, geotiffOptions GeoTIFFOptions, gradientAlgorithm string,
+	verticalExaggeration float64, azimuthOfLight uint, altitudeOfLight uint, shadingVariant string, lightSources []LightSource) (Hillshade, error) {
 	var hillshade Hillshade
 	var boundingBox WGS84BoundingBox
 
-	// run operations in temp directory
-	tempDir, err := os.MkdirTemp("", "dtm-elevation-service-hillshade-")
-	if err != nil {
-		return hillshade, fmt.Errorf("error [%w] at os.MkdirTemp()", err)
+	// lookup response cache
+	cacheKey := buildResponseCacheKey("hillshade", tile.Index, tile.Actuality, outputFormat, geotiffOptions, gradientAlgorithm,
+		verticalExaggeration, azimuthOfLight, altitudeOfLight, shadingVariant, lightSources)
+	cached, found := responseCache.Get(cacheKey)
+	recordCacheLookup(ctx, found)
+	if found {
+		return cached.(Hillshade), nil
 	}
-	defer func() {
-		_ = os.RemoveAll(tempDir)
-	}()
 
+	// run operations in memory (/vsimem)
+	vsimemPrefix := dtm.NewVSIMemPrefix("hillshade")
 	inputGeoTIFF := tile.Path
-	hillshadeUTMGeoTIFF := filepath.Join(tempDir, tile.Index+".hillshade.utm.tif")
-	hillshadeWebmercatorGeoTIFF := filepath.Join(tempDir, tile.Index+".hillshade.webmercator.tif")
-	hillshadeWebmercatorPNG := filepath.Join(tempDir, tile.Index+".hillshade.webmercator.png")
+	hillshadeUTMGeoTIFF := vsimemPrefix + tile.Index + ".hillshade.utm.tif"
+	hillshadeWebmercatorGeoTIFF := vsimemPrefix + tile.Index + ".hillshade.webmercator.tif"
+	hillshadeWebmercatorPNG := vsimemPrefix + tile.Index + ".hillshade.webmercator.png"
+	defer removeVSIMemFiles(hillshadeUTMGeoTIFF, hillshadeWebmercatorGeoTIFF, hillshadeWebmercatorPNG)
 
 	// build options
-	options := []string{"hillshade",
-		inputGeoTIFF,
-		hillshadeUTMGeoTIFF,
+	options := []string{"-of", "GTiff",
 		"-compute_edges",
 		"-z", fmt.Sprintf("%f", verticalExaggeration),
 		"-alg", gradientAlgorithm,
@@ -376,45 +516,71 @@ func generateHillshadeObjectForTile(tile TileMetadata, outputFormat string, grad
 		options = append(options, "-az", fmt.Sprintf("%d", azimuthOfLight))
 		options = append(options, "-"+shadingVariant)
 
+	case "textured":
+		// regular hillshade, tone adjusted by slope and curvature (see below)
+		options = append(options, "-az", fmt.Sprintf("%d", azimuthOfLight))
+		options = append(options, "-alt", fmt.Sprintf("%d", altitudeOfLight))
+
+	case "custom":
+		// -az and -alt options per light source (see below)
+		if len(lightSources) == 0 {
+			return hillshade, errors.New("shading variant 'custom' requires light sources")
+		}
+
 	default:
 		return hillshade, fmt.Errorf("unsupported shading variant [%s]", shadingVariant)
 	}
 
+	// creation options apply to returned GeoTIFF only (intermediate file for PNG)
+	if strings.EqualFold(outputFormat, "geotiff") {
+		options = append(options, buildGeoTIFFCreationOptions(geotiffOptions)...)
+	}
+
 	// 1. calculate hillshade on original source data
 	// e.g. gdaldem hillshade dgm1_32_409_5790_1_nw_2024.tif 32_409_5790.hillshade.utm.tif -compute_edges -z 1.0 -az 315 -alt 45 -alg Horn
-	commandExitStatus, commandOutput, err := runCommand("gdaldem", options)
-	if err != nil {
-		return hillshade, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
+	var err error
+	if shadingVariant == "custom" {
+		err = generateCustomHillshade(ctx, inputGeoTIFF, hillshadeUTMGeoTIFF, vsimemPrefix+tile.Index, options, lightSources)
+		if err != nil {
+			return hillshade, fmt.Errorf("error [%w] at generateCustomHillshade()", err)
+		}
+	} else {
+		err = dtm.Dem(ctx, "hillshade", inputGeoTIFF, "", hillshadeUTMGeoTIFF, options)
+		if err != nil {
+			return hillshade, fmt.Errorf("error [%w] at dtm.Dem()", err)
+		}
+	}
+
+	// 1a. adjust tone of hillshade by slope and curvature (textured hillshade)
+	if shadingVariant == "textured" {
+		err = applyTexturedShading(ctx, inputGeoTIFF, hillshadeUTMGeoTIFF, verticalExaggeration)
+		if err != nil {
+			return hillshade, fmt.Errorf("error [%w] at applyTexturedShading()", err)
+		}
 	}
-	// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-	// fmt.Printf("commandOutput: %s\n", commandOutput)
 
 	var data []byte
 	switch strings.ToLower(outputFormat) {
 	case "geotiff":
-		data, err = os.ReadFile(hillshadeUTMGeoTIFF)
+		data, err = dtm.ReadVSIMemFile(hillshadeUTMGeoTIFF)
 		if err != nil {
-			return hillshade, fmt.Errorf("error [%w] at os.ReadFile()", err)
+			return hillshade, fmt.Errorf("error [%w] at dtm.ReadVSIMemFile()", err)
 		}
 
 	case "png":
 		// 2. reproject from EPSG:25832/EPSG:25833 to EPSG:3857 (Webmercator)
 		// e.g. gdalwarp -t_srs EPSG:3857 32_409_5790.hillshade.utm.tif 32_409_5790.hillshade.webmercator.tif
-		commandExitStatus, commandOutput, err = runCommand("gdalwarp", []string{"-t_srs", "EPSG:3857", hillshadeUTMGeoTIFF, hillshadeWebmercatorGeoTIFF})
+		err = dtm.Warp(ctx, hillshadeUTMGeoTIFF, hillshadeWebmercatorGeoTIFF, []string{"-of", "GTiff", "-t_srs", "EPSG:3857"})
 		if err != nil {
-			return hillshade, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
+			return hillshade, fmt.Errorf("error [%w] at dtm.Warp()", err)
 		}
-		// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-		// fmt.Printf("commandOutput: %s\n", commandOutput)
 
 		// 3. convert webmercator tif to png
 		// e.g. gdal_translate -of PNG 32_409_5790.hillshade.webmercator.tif 32_409_5790.hillshade.webmercator.png
-		commandExitStatus, commandOutput, err = runCommand("gdal_translate", []string{"-of", "PNG", hillshadeWebmercatorGeoTIFF, hillshadeWebmercatorPNG})
+		err = dtm.Translate(ctx, hillshadeWebmercatorGeoTIFF, hillshadeWebmercatorPNG, []string{"-of", "PNG"})
 		if err != nil {
-			return hillshade, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
+			return hillshade, fmt.Errorf("error [%w] at dtm.Translate()", err)
 		}
-		// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-		// fmt.Printf("commandOutput: %s\n", commandOutput)
 
 		// 4. get bounding box (in wgs84) for webmercator tif (georeference of webmercator png )
 		boundingBox, err = calculateWGS84BoundingBox(tile)
@@ -422,9 +588,9 @@ func generateHillshadeObjectForTile(tile TileMetadata, outputFormat string, grad
 			return hillshade, fmt.Errorf("error [%w] at calculateWGS84BoundingBox(), file: %s", err, tile.Path)
 		}
 
-		data, err = os.ReadFile(hillshadeWebmercatorPNG)
+		data, err = dtm.ReadVSIMemFile(hillshadeWebmercatorPNG)
 		if err != nil {
-			return hillshade, fmt.Errorf("error [%w] at os.ReadFile()", err)
+			return hillshade, fmt.Errorf("error [%w] at dtm.ReadVSIMemFile()", err)
 		}
 
 	default:
@@ -434,6 +600,9 @@ func generateHillshadeObjectForTile(tile TileMetadata, outputFormat string, grad
 	// set hillshade return structure
 	hillshade.Data = data
 	hillshade.DataFormat = outputFormat
+	hillshade.Filename = buildObjectFilename(tile.Index, "hillshade", shadingVariant, tile.Actuality, outputFormat)
+	hillshade.ShadingVariant = shadingVariant
+	hillshade.VerticalExaggeration = verticalExaggeration
 	hillshade.Actuality = tile.Actuality
 	hillshade.Origin = tile.Source
 	hillshade.TileIndex = tile.Index
@@ -443,11 +612,350 @@ func generateHillshadeObjectForTile(tile TileMetadata, outputFormat string, grad
 	attribution := "unknown"
 	resource, err := getElevationResource(tile.Source)
 	if err != nil {
-		slog.Error("hillshade request: error getting elevation resource", "error", err, "source", tile.Source)
+		slog.ErrorContext(ctx, "hillshade request: error getting elevation resource", "error", err, "source", tile.Source)
 	} else {
 		attribution = resource.Attribution
 	}
 	hillshade.Attribution = attribution
 
+	// get provenance of source files (delivery file name and checksum)
+	sourceFiles, err := getSourceFiles(tile)
+	if err != nil {
+		slog.ErrorContext(ctx, "hillshade request: error getting source files", "error", err, "tile", tile.Index)
+	}
+	hillshade.SourceFiles = sourceFiles
+
+	// add to response cache
+	responseCache.Add(cacheKey, hillshade, len(hillshade.Data))
+
 	return hillshade, nil
 }
+
+/*
+deriveVerticalExaggeration derives a suitable vertical exaggeration (z-factor) from the relief of a tile:
+the mean gradient (rise over run) of the tile is scaled to a target gradient of 0.15 (about 8.5 degrees),
+so that flatland tiles (e.g. Münsterland) are shaded with the contrast of hilly terrain.
+The z-factor is limited to 1.0 - 20.0 (never flattened) and rounded to steps of 0.5 (stable cache keys).
+*/
+func deriveVerticalExaggeration(ctx context.Context, tile TileMetadata) (float64, error) {
+	const targetGradient = 0.15
+	const minVerticalExaggeration = 1.0
+	const maxVerticalExaggeration = 20.0
+
+	// lookup response cache
+	cacheKey := buildResponseCacheKey("autoverticalexaggeration", tile.Index, tile.Actuality)
+	cached, found := responseCache.Get(cacheKey)
+	if found {
+		return cached.(float64), nil
+	}
+
+	// limit concurrent GDAL processing
+	err := acquireGDALJobSlot(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer releaseGDALJobSlot(ctx)
+
+	dataset, err := godal.Open(tile.Path, godal.RasterOnly())
+	if err != nil {
+		return 0, fmt.Errorf("error [%w] at godal.Open(), file: %s", err, tile.Path)
+	}
+	defer dataset.Close()
+
+	gt, err := dataset.GeoTransform()
+	if err != nil {
+		return 0, fmt.Errorf("error [%w] at dataset.GeoTransform(), file: %s", err, tile.Path)
+	}
+	pixelWidth := math.Abs(gt[1])
+	pixelHeight := math.Abs(gt[5])
+	if pixelWidth == 0 || pixelHeight == 0 {
+		return 0, fmt.Errorf("invalid pixel size, file: %s", tile.Path)
+	}
+
+	// mean gradient (forward differences to right and lower neighbour)
+	band := dataset.Bands()[0]
+	noData, hasNoData := band.NoData()
+	structure := dataset.Structure()
+	previousRow := make([]float32, structure.SizeX)
+	currentRow := make([]float32, structure.SizeX)
+	sumGradient := 0.0
+	count := 0
+	for row := range structure.SizeY {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		err = band.Read(0, row, currentRow, structure.SizeX, 1)
+		if err != nil {
+			return 0, fmt.Errorf("error [%w] at band.Read(), file: %s, row: %d", err, tile.Path, row)
+		}
+		if row > 0 {
+			for column := 0; column < structure.SizeX-1; column++ {
+				z := float64(previousRow[column])
+				right := float64(previousRow[column+1])
+				below := float64(currentRow[column])
+				if hasNoData && (z == noData || right == noData || below == noData) {
+					continue
+				}
+				sumGradient += math.Hypot((right-z)/pixelWidth, (below-z)/pixelHeight)
+				count++
+			}
+		}
+		previousRow, currentRow = currentRow, previousRow
+	}
+
+	// z-factor (no valid data: neutral, perfectly flat: maximum)
+	verticalExaggeration := minVerticalExaggeration
+	if count > 0 {
+		verticalExaggeration = maxVerticalExaggeration
+		meanGradient := sumGradient / float64(count)
+		if meanGradient > 0 {
+			verticalExaggeration = math.Max(minVerticalExaggeration, math.Min(maxVerticalExaggeration, targetGradient/meanGradient))
+		}
+	}
+	verticalExaggeration = math.Round(verticalExaggeration*2) / 2
+
+	// add to response cache
+	responseCache.Add(cacheKey, verticalExaggeration, 8)
+
+	return verticalExaggeration, nil
+}
+
+/*
+applyTexturedShading adjusts the tone of a regular hillshade by slope and curvature of the terrain (textured hillshade).
+Each pixel is a weighted blend of three tones:
+- hillshade (60 %): illumination by the light source
+- slope (20 %): steep terrain darker (cosine of slope)
+- curvature (20 %): concave terrain (e.g. hollow ways, dune valleys) darker, convex terrain (e.g. dune crests) brighter
+Curvature is the Laplacian of the (exaggerated) elevations, compressed by tanh into the range -1 to 1.
+The hillshade file is overwritten with the adjusted tones.
+*/
+func applyTexturedShading(ctx context.Context, elevationFile, hillshadeFile string, verticalExaggeration float64) error {
+	const hillshadeWeight = 0.6
+	const slopeWeight = 0.2
+	const curvatureWeight = 0.2
+	const curvatureScale = 5.0 // 1/m, Laplacian of 0.2 (e.g. hollow way 1 m deep, 5 m wide) gives tanh(1)
+
+	// limit concurrent GDAL processing
+	err := acquireGDALJobSlot(ctx)
+	if err != nil {
+		return err
+	}
+	defer releaseGDALJobSlot(ctx)
+
+	elevationDataset, err := godal.Open(elevationFile, godal.RasterOnly())
+	if err != nil {
+		return fmt.Errorf("error [%w] at godal.Open(), file: %s", err, elevationFile)
+	}
+	defer elevationDataset.Close()
+
+	hillshadeDataset, err := godal.Open(hillshadeFile, godal.RasterOnly(), godal.Update())
+	if err != nil {
+		return fmt.Errorf("error [%w] at godal.Open(), file: %s", err, hillshadeFile)
+	}
+	defer hillshadeDataset.Close()
+
+	gt, err := elevationDataset.GeoTransform()
+	if err != nil {
+		return fmt.Errorf("error [%w] at dataset.GeoTransform(), file: %s", err, elevationFile)
+	}
+	pixelWidth := math.Abs(gt[1])
+	pixelHeight := math.Abs(gt[5])
+	if pixelWidth == 0 || pixelHeight == 0 {
+		return fmt.Errorf("invalid pixel size, file: %s", elevationFile)
+	}
+
+	structure := elevationDataset.Structure()
+	hillshadeStructure := hillshadeDataset.Structure()
+	if structure.SizeX != hillshadeStructure.SizeX || structure.SizeY != hillshadeStructure.SizeY {
+		return fmt.Errorf("raster size of hillshade (%dx%d) differs from elevation raster (%dx%d)",
+			hillshadeStructure.SizeX, hillshadeStructure.SizeY, structure.SizeX, structure.SizeY)
+	}
+	elevationBand := elevationDataset.Bands()[0]
+	hillshadeBand := hillshadeDataset.Bands()[0]
+	noData, hasNoData := elevationBand.NoData()
+
+	// rolling window of three elevation rows (above, current, below; replicated at raster edges)
+	readRow := func(row int, buffer []float32) error {
+		row = max(0, min(structure.SizeY-1, row))
+		err := elevationBand.Read(0, row, buffer, structure.SizeX, 1)
+		if err != nil {
+			return fmt.Errorf("error [%w] at band.Read(), file: %s, row: %d", err, elevationFile, row)
+		}
+		return nil
+	}
+	above := make([]float32, structure.SizeX)
+	current := make([]float32, structure.SizeX)
+	below := make([]float32, structure.SizeX)
+	if err = readRow(0, current); err != nil {
+		return err
+	}
+	copy(above, current)
+	tones := make([]uint8, structure.SizeX)
+
+	for row := range structure.SizeY {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err = readRow(row+1, below); err != nil {
+			return err
+		}
+		err = hillshadeBand.Read(0, row, tones, structure.SizeX, 1)
+		if err != nil {
+			return fmt.Errorf("error [%w] at band.Read(), file: %s, row: %d", err, hillshadeFile, row)
+		}
+
+		for column := range structure.SizeX {
+			// 0 = nodata (gdaldem hillshade)
+			if tones[column] == 0 {
+				continue
+			}
+			left := max(0, column-1)
+			right := min(structure.SizeX-1, column+1)
+			z := float64(current[column])
+			zLeft := float64(current[left])
+			zRight := float64(current[right])
+			zAbove := float64(above[column])
+			zBelow := float64(below[column])
+			if hasNoData && (z == noData || zLeft == noData || zRight == noData || zAbove == noData || zBelow == noData) {
+				continue
+			}
+
+			// slope (central differences, exaggerated elevations)
+			dzdx := (zRight - zLeft) * verticalExaggeration / (float64(right-left) * pixelWidth)
+			dzdy := (zBelow - zAbove) * verticalExaggeration / (2 * pixelHeight)
+			slopeTone := math.Cos(math.Atan(math.Hypot(dzdx, dzdy)))
+
+			// curvature (Laplacian, positive = concave)
+			laplacian := ((zLeft+zRight-2*z)/(pixelWidth*pixelWidth) + (zAbove+zBelow-2*z)/(pixelHeight*pixelHeight)) * verticalExaggeration
+			curvatureTone := 0.5 - 0.5*math.Tanh(curvatureScale*laplacian)
+
+			tone := hillshadeWeight*float64(tones[column]) + 255*(slopeWeight*slopeTone+curvatureWeight*curvatureTone)
+			tones[column] = uint8(math.Max(1, math.Min(255, math.Round(tone))))
+		}
+
+		err = hillshadeBand.Write(0, row, tones, structure.SizeX, 1)
+		if err != nil {
+			return fmt.Errorf("error [%w] at band.Write(), file: %s, row: %d", err, hillshadeFile, row)
+		}
+		above, current, below = current, below, above
+	}
+
+	// flush adjusted tones
+	err = hillshadeDataset.Close()
+	if err != nil {
+		return fmt.Errorf("error [%w] at dataset.Close(), file: %s", err, hillshadeFile)
+	}
+
+	return nil
+}
+
+/*
+generateCustomHillshade calculates a hillshade lit by several light sources (shading variant 'custom'):
+ 1. calculate one regular hillshade per light source
+    gdaldem hillshade dgm1_32_409_5790_1_nw_2024.tif 32_409_5790.hillshade.light1.tif -compute_edges -z 1.0 -az 315 -alt 45 -alg Horn
+ 2. blend hillshades pixel by pixel (weighted mean, weights normalized over all light sources)
+
+The first hillshade is calculated into the output file (incl. creation options) and overwritten with the blended values.
+*/
+func generateCustomHillshade(ctx context.Context, inputFile, outputFile, vsimemPrefix string, options []string, lightSources []LightSource) error {
+	// 1. calculate one hillshade per light source
+	lightFiles := make([]string, 0, len(lightSources))
+	for i, lightSource := range lightSources {
+		lightFile := outputFile
+		if i > 0 {
+			lightFile = fmt.Sprintf("%s.hillshade.light%d.tif", vsimemPrefix, i+1)
+			defer removeVSIMemFiles(lightFile)
+		}
+		lightOptions := slices.Clone(options)
+		lightOptions = append(lightOptions, "-az", strconv.FormatFloat(lightSource.Azimuth, 'f', -1, 64))
+		lightOptions = append(lightOptions, "-alt", strconv.FormatFloat(lightSource.Altitude, 'f', -1, 64))
+		err := dtm.Dem(ctx, "hillshade", inputFile, "", lightFile, lightOptions)
+		if err != nil {
+			return fmt.Errorf("error [%w] at dtm.Dem(), light source: %d", err, i+1)
+		}
+		lightFiles = append(lightFiles, lightFile)
+	}
+
+	// single light source equals regular hillshade
+	if len(lightFiles) == 1 {
+		return nil
+	}
+
+	// 2. blend hillshades (weighted mean)
+	err := acquireGDALJobSlot(ctx)
+	if err != nil {
+		return err
+	}
+	defer releaseGDALJobSlot(ctx)
+
+	totalWeight := 0.0
+	for _, lightSource := range lightSources {
+		totalWeight += lightSource.Weight
+	}
+
+	var datasets []*godal.Dataset
+	defer func() {
+		for _, dataset := range datasets {
+			dataset.Close()
+		}
+	}()
+	for i, lightFile := range lightFiles {
+		openOptions := []godal.OpenOption{godal.RasterOnly()}
+		if i == 0 {
+			openOptions = append(openOptions, godal.Update())
+		}
+		dataset, err := godal.Open(lightFile, openOptions...)
+		if err != nil {
+			return fmt.Errorf("error [%w] at godal.Open(), file: %s", err, lightFile)
+		}
+		datasets = append(datasets, dataset)
+	}
+
+	structure := datasets[0].Structure()
+	rowValues := make([][]uint8, len(datasets))
+	for i := range datasets {
+		rowValues[i] = make([]uint8, structure.SizeX)
+	}
+	blended := make([]uint8, structure.SizeX)
+	for row := range structure.SizeY {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		for i, dataset := range datasets {
+			err = dataset.Bands()[0].Read(0, row, rowValues[i], structure.SizeX, 1)
+			if err != nil {
+				return fmt.Errorf("error [%w] at band.Read(), file: %s, row: %d", err, lightFiles[i], row)
+			}
+		}
+		for column := range structure.SizeX {
+			sum := 0.0
+			isNoData := false
+			for i, lightSource := range lightSources {
+				// 0 = nodata (gdaldem hillshade)
+				if rowValues[i][column] == 0 {
+					isNoData = true
+					break
+				}
+				sum += lightSource.Weight * float64(rowValues[i][column])
+			}
+			if isNoData {
+				blended[column] = 0
+				continue
+			}
+			blended[column] = uint8(math.Max(1, math.Min(255, math.Round(sum/totalWeight))))
+		}
+		err = datasets[0].Bands()[0].Write(0, row, blended, structure.SizeX, 1)
+		if err != nil {
+			return fmt.Errorf("error [%w] at band.Write(), file: %s, row: %d", err, outputFile, row)
+		}
+	}
+
+	// flush blended values (close output dataset)
+	err = datasets[0].Close()
+	datasets = datasets[1:]
+	if err != nil {
+		return fmt.Errorf("error [%w] at dataset.Close(), file: %s", err, outputFile)
+	}
+
+	return nil
+}
@@ -1,9 +1,7 @@
 package main
 
 import (
-	"bufio" // Added import for bufio.NewScanner
-	"bytes"
-	"compress/gzip"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,17 +9,32 @@ import (
 	"log/slog"
 	"math"
 	"net/http"
-	"os"
-	"path/filepath"
+	"slices"
 	"sort" // Added import
 	"strconv"
 	"strings"
 	"sync/atomic"
+
+	"github.com/airbusgeo/godal"
+
+	"klaus/elevation/dtm-elevation-service/pkg/dtm"
 )
 
 // Define the sentinel value to be excluded from histogram binning.
 const noValueSentinel = -9999.0
 
+// default percentiles of histogram statistic
+var defaultHistogramPercentiles = []float64{5, 25, 50, 75, 95}
+
+// elevation band presets (band width in meters) for elevation histograms
+var elevationBandPresets = map[string]float64{
+	"10m":  10,
+	"25m":  25,
+	"50m":  50,
+	"100m": 100,
+	"250m": 250,
+}
+
 /*
 histogramRequest handles 'colorrelief request' from client.
 */
@@ -41,18 +54,14 @@ func histogramRequest(writer http.ResponseWriter, request *http.Request) {
 		// check specifically for the error returned by MaxBytesReader
 		var maxBytesErr *http.MaxBytesError
 		if errors.As(err, &maxBytesErr) {
-			slog.Warn("histogram request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
-			histogramResponse.Attributes.Error.Code = "13000"
-			histogramResponse.Attributes.Error.Title = "request body too large"
-			histogramResponse.Attributes.Error.Detail = fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit)
-			buildHistogramResponse(writer, http.StatusRequestEntityTooLarge, histogramResponse)
+			slog.WarnContext(request.Context(), "histogram request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
+			histogramResponse.Attributes.Error = newErrorObject(EndpointHistogram, ReasonRequestBodyTooLarge, fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit))
+			buildHistogramResponse(writer, request, http.StatusRequestEntityTooLarge, histogramResponse)
 		} else {
 			// handle other read errors
-			slog.Warn("histogram request: error reading request body", "error", err, "ID", "unknown")
-			histogramResponse.Attributes.Error.Code = "13020"
-			histogramResponse.Attributes.Error.Title = "error reading request body"
-			histogramResponse.Attributes.Error.Detail = err.Error()
-			buildHistogramResponse(writer, http.StatusBadRequest, histogramResponse)
+			slog.WarnContext(request.Context(), "histogram request: error reading request body", "error", err, "ID", "unknown")
+			histogramResponse.Attributes.Error = newErrorObject(EndpointHistogram, ReasonReadingRequestBody, err.Error())
+			buildHistogramResponse(writer, request, http.StatusBadRequest, histogramResponse)
 		}
 		return
 	}
@@ -61,14 +70,39 @@ func histogramRequest(writer http.ResponseWriter, request *http.Request) {
 	histogramRequest := HistogramRequest{}
 	err = json.Unmarshal(bodyData, &histogramRequest)
 	if err != nil {
-		slog.Warn("histogram request: error unmarshaling request body", "error", err, "ID", "unknown")
-		histogramResponse.Attributes.Error.Code = "13040"
-		histogramResponse.Attributes.Error.Title = "error unmarshaling request body"
-		histogramResponse.Attributes.Error.Detail = err.Error()
-		buildHistogramResponse(writer, http.StatusBadRequest, histogramResponse)
+		slog.WarnContext(request.Context(), "histogram request: error unmarshaling request body", "error", err, "ID", "unknown")
+		histogramResponse.Attributes.Error = newErrorObject(EndpointHistogram, ReasonUnmarshalingRequestBody, err.Error())
+		buildHistogramResponse(writer, request, http.StatusBadRequest, histogramResponse)
 		return
 	}
 
+	// resolve place name (geocoding) into lon/lat coordinates
+	if histogramRequest.Attributes.Place != "" {
+		place, err := geocodePlace(request.Context(), histogramRequest.Attributes.Place)
+		if err != nil {
+			slog.WarnContext(request.Context(), "histogram request: error geocoding place", "error", err, "place", histogramRequest.Attributes.Place, "ID", histogramRequest.ID)
+			histogramResponse.ID = histogramRequest.ID
+			histogramResponse.Attributes.Place = histogramRequest.Attributes.Place
+			if errors.Is(err, ErrPlaceNotFound) {
+				histogramResponse.Attributes.Error = newErrorObject(EndpointHistogram, ReasonPlaceNotFound, err.Error())
+				buildHistogramResponse(writer, request, http.StatusNotFound, histogramResponse)
+				return
+			}
+			if errors.Is(err, ErrGeocoderUnavailable) {
+				histogramResponse.Attributes.Error = newErrorObject(EndpointHistogram, ReasonGeocoderUnavailable, err.Error())
+				buildHistogramResponse(writer, request, http.StatusServiceUnavailable, histogramResponse)
+				return
+			}
+			histogramResponse.Attributes.Error = newErrorObject(EndpointHistogram, ReasonVerifyingRequestData, err.Error())
+			buildHistogramResponse(writer, request, http.StatusBadRequest, histogramResponse)
+			return
+		}
+		histogramRequest.Attributes.Zone = 0
+		histogramRequest.Attributes.Longitude = place.Longitude
+		histogramRequest.Attributes.Latitude = place.Latitude
+		histogramResponse.Attributes.PlaceName = place.DisplayName
+	}
+
 	// copy request parameters into response
 	histogramResponse.ID = histogramRequest.ID
 	histogramResponse.Attributes.Zone = histogramRequest.Attributes.Zone
@@ -76,21 +110,27 @@ func histogramRequest(writer http.ResponseWriter, request *http.Request) {
 	histogramResponse.Attributes.Northing = histogramRequest.Attributes.Northing
 	histogramResponse.Attributes.Longitude = histogramRequest.Attributes.Longitude
 	histogramResponse.Attributes.Latitude = histogramRequest.Attributes.Latitude
+	histogramResponse.Attributes.Place = histogramRequest.Attributes.Place
 	histogramResponse.Attributes.TypeOfVisualization = histogramRequest.Attributes.TypeOfVisualization
 	histogramResponse.Attributes.GradientAlgorithm = histogramRequest.Attributes.GradientAlgorithm
 	histogramResponse.Attributes.TypeOfHistogram = histogramRequest.Attributes.TypeOfHistogram
 	histogramResponse.Attributes.NumberOfBins = histogramRequest.Attributes.NumberOfBins
 	histogramResponse.Attributes.MinValue = histogramRequest.Attributes.MinValue
 	histogramResponse.Attributes.MaxValue = histogramRequest.Attributes.MaxValue
+	histogramResponse.Attributes.ElevationBands = histogramRequest.Attributes.ElevationBands
+
+	// default percentiles
+	if len(histogramRequest.Attributes.Percentiles) == 0 {
+		histogramRequest.Attributes.Percentiles = defaultHistogramPercentiles
+	}
+	histogramResponse.Attributes.Percentiles = histogramRequest.Attributes.Percentiles
 
 	// verify request data
 	err = verifyHistogramRequestData(request, histogramRequest)
 	if err != nil {
-		slog.Warn("histogram request: error verifying request data", "error", err, "ID", histogramRequest.ID)
-		histogramResponse.Attributes.Error.Code = "13060"
-		histogramResponse.Attributes.Error.Title = "error verifying request data"
-		histogramResponse.Attributes.Error.Detail = err.Error()
-		buildHistogramResponse(writer, http.StatusBadRequest, histogramResponse)
+		slog.WarnContext(request.Context(), "histogram request: error verifying request data", "error", err, "ID", histogramRequest.ID)
+		histogramResponse.Attributes.Error = newErrorObject(EndpointHistogram, ReasonVerifyingRequestData, err.Error())
+		buildHistogramResponse(writer, request, http.StatusBadRequest, histogramResponse)
 		return
 	}
 
@@ -111,12 +151,20 @@ func histogramRequest(writer http.ResponseWriter, request *http.Request) {
 		// get all tiles (metadata) for given UTM coordinates
 		tiles, err = getAllTilesUTM(zone, easting, northing)
 		if err != nil {
-			slog.Warn("histogram request: error getting GeoTIFF tile for UTM coordinates", "error", err,
+			slog.WarnContext(request.Context(), "histogram request: error getting GeoTIFF tile for UTM coordinates", "error", err,
 				"easting", easting, "northing", northing, "zone", zone, "ID", histogramRequest.ID)
-			histogramResponse.Attributes.Error.Code = "13080"
-			histogramResponse.Attributes.Error.Title = "getting GeoTIFF tile for UTM coordinates"
-			histogramResponse.Attributes.Error.Detail = err.Error()
-			buildHistogramResponse(writer, http.StatusBadRequest, histogramResponse)
+			if errors.Is(err, ErrOutsideCoverage) {
+				histogramResponse.Attributes.Error = newErrorObject(EndpointHistogram, ReasonOutsideCoverage, describeOutsideCoverage(err))
+				buildHistogramResponse(writer, request, http.StatusNotFound, histogramResponse)
+				return
+			}
+			if errors.Is(err, ErrSourceUnavailable) {
+				histogramResponse.Attributes.Error = newErrorObject(EndpointHistogram, ReasonSourceUnavailable, err.Error())
+				buildHistogramResponse(writer, request, http.StatusServiceUnavailable, histogramResponse)
+				return
+			}
+			histogramResponse.Attributes.Error = newErrorObject(EndpointHistogram, ReasonGettingTileUTM, err.Error())
+			buildHistogramResponse(writer, request, http.StatusBadRequest, histogramResponse)
 			return
 		}
 	} else {
@@ -128,38 +176,72 @@ func histogramRequest(writer http.ResponseWriter, request *http.Request) {
 		tiles, err = getAllTilesLonLat(longitude, latitude)
 		if err != nil {
 			err = fmt.Errorf("error [%w] getting tile for coordinates lon: %.8f, lat: %.8f", err, longitude, latitude)
-			slog.Warn("histogram request: error getting GeoTIFF tile for lon/lat coordinates", "error", err,
+			slog.WarnContext(request.Context(), "histogram request: error getting GeoTIFF tile for lon/lat coordinates", "error", err,
 				"longitude", longitude, "latitude", latitude, "ID", histogramRequest.ID)
-			histogramResponse.Attributes.Error.Code = "13100"
-			histogramResponse.Attributes.Error.Title = "getting GeoTIFF tile for lon/lat coordinates"
-			histogramResponse.Attributes.Error.Detail = err.Error()
-			buildHistogramResponse(writer, http.StatusBadRequest, histogramResponse)
+			if errors.Is(err, ErrOutsideCoverage) {
+				histogramResponse.Attributes.Error = newErrorObject(EndpointHistogram, ReasonOutsideCoverage, describeOutsideCoverage(err))
+				buildHistogramResponse(writer, request, http.StatusNotFound, histogramResponse)
+				return
+			}
+			if errors.Is(err, ErrSourceUnavailable) {
+				histogramResponse.Attributes.Error = newErrorObject(EndpointHistogram, ReasonSourceUnavailableLonLat, err.Error())
+				buildHistogramResponse(writer, request, http.StatusServiceUnavailable, histogramResponse)
+				return
+			}
+			histogramResponse.Attributes.Error = newErrorObject(EndpointHistogram, ReasonGettingTileLonLat, err.Error())
+			buildHistogramResponse(writer, request, http.StatusBadRequest, histogramResponse)
 			return
 		}
 	}
 
+	// conditional request: unchanged response (same parameters, same tiles) needs no processing
+	etag := buildETag("histogram", histogramRequest, tiles)
+	if checkNotModified(writer, request, etag) {
+		return
+	}
+
 	// build histogram for all existing tiles
-	for _, tile := range tiles {
-		histogram, err := generateHistogramObjectForTile(tile, histogramRequest.Attributes.TypeOfVisualization,
+	histograms, tileErrors, err := generateObjectsForTiles(request.Context(), tiles, func(tile TileMetadata) (Histogram, error) {
+		return generateHistogramObjectForTile(request.Context(), tile, histogramRequest.Attributes.TypeOfVisualization,
 			histogramRequest.Attributes.GradientAlgorithm, histogramRequest.Attributes.TypeOfHistogram,
-			histogramRequest.Attributes.NumberOfBins, histogramRequest.Attributes.MinValue, histogramRequest.Attributes.MaxValue)
-		if err != nil {
-			slog.Warn("histogram request: error generating histogram object for tile", "error", err, "ID", histogramRequest.ID)
-			// The error code from generateHistogramObjectForTile should be propagated or remapped
-			// If the error originates from processHistogramData, it already has an error message.
-			// Let's ensure the full detail is passed.
-			histogramResponse.Attributes.Error.Code = "13120"
-			histogramResponse.Attributes.Error.Title = "error generating histogram object for tile"
-			histogramResponse.Attributes.Error.Detail = err.Error() // Use the detailed error from generateHistogramObjectForTile
-			buildHistogramResponse(writer, http.StatusBadRequest, histogramResponse)
+			histogramRequest.Attributes.NumberOfBins, histogramRequest.Attributes.MinValue, histogramRequest.Attributes.MaxValue,
+			histogramRequest.Attributes.ElevationBands, histogramRequest.Attributes.Percentiles)
+	})
+	if err != nil {
+		slog.WarnContext(request.Context(), "histogram request: error generating histogram object for tile", "error", err, "ID", histogramRequest.ID)
+		// The error code from generateHistogramObjectForTile should be propagated or remapped
+		// If the error originates from processHistogramData, it already has an error message.
+		// Let's ensure the full detail is passed.
+		if errors.Is(err, context.Canceled) {
+			// client disconnected, processing aborted, no response required
+			return
+		}
+		if errors.Is(err, ErrServerBusy) {
+			histogramResponse.Attributes.Error = newErrorObject(EndpointHistogram, ReasonServerBusy, err.Error())
+			writer.Header().Set("Retry-After", strconv.Itoa(getProgConfig().GDALJobQueue.RetryAfter))
+			buildHistogramResponse(writer, request, http.StatusTooManyRequests, histogramResponse)
 			return
 		}
-		histogramResponse.Attributes.Histograms = append(histogramResponse.Attributes.Histograms, histogram)
+		histogramResponse.Attributes.Error = newErrorObject(EndpointHistogram, ReasonGeneratingObject, err.Error()) // detailed error from generateHistogramObjectForTile
+		buildHistogramResponse(writer, request, http.StatusBadRequest, histogramResponse)
+		return
+	}
+	histogramResponse.Attributes.Histograms = histograms
+	histogramResponse.Attributes.TileErrors = tileErrors
+	if len(tileErrors) > 0 {
+		histogramResponse.Attributes.Warnings = append(histogramResponse.Attributes.Warnings,
+			fmt.Sprintf("%d of %d tiles could not be processed (see TileErrors)", len(tileErrors), len(tiles)))
+		slog.WarnContext(request.Context(), "histogram request: partial success, tiles could not be processed", "tile errors", tileErrors, "ID", histogramRequest.ID)
 	}
+	addUsage(request.Context(), 0, len(histograms))
 
 	// success response
 	histogramResponse.Attributes.IsError = false
-	buildHistogramResponse(writer, http.StatusOK, histogramResponse)
+	if len(tileErrors) == 0 {
+		// partial responses are not cacheable
+		setETag(writer, etag)
+	}
+	buildHistogramResponse(writer, request, http.StatusOK, histogramResponse)
 }
 
 /*
@@ -180,18 +262,6 @@ func verifyHistogramRequestData(request *http.Request, histogramRequest Histogra
 		return fmt.Errorf("unexpected or missing HTTP header field Content-Type, value = [%s], expected 'application/json'", contentType)
 	}
 
-	// verify HTTP header
-	accept := request.Header.Get("Accept")
-	isAcceptValid := true
-	switch {
-	case strings.HasPrefix(strings.ToLower(accept), "application/json"):
-	default:
-		isAcceptValid = false
-	}
-	if !isAcceptValid {
-		return fmt.Errorf("unexpected or missing HTTP header field Accept, value = [%s], expected 'application/json'", accept)
-	}
-
 	// verify Type
 	if histogramRequest.Type != TypeHistogramRequest {
 		return fmt.Errorf("unexpected request Type [%v]", histogramRequest.Type)
@@ -237,17 +307,40 @@ func verifyHistogramRequestData(request *http.Request, histogramRequest Histogra
 	case "roughness":
 	case "tri":
 	case "tpi":
+	case "hillshade":
 	default:
-		return errors.New("type of visualization not supported (valid: rawtif, slope, aspect, roughness, tri, tpi)")
+		return errors.New("type of visualization not supported (valid: rawtif, slope, aspect, roughness, tri, tpi, hillshade)")
 	}
 
 	// verify gradient algorithm
-	if histogramRequest.Attributes.TypeOfVisualization == "slope" || histogramRequest.Attributes.TypeOfVisualization == "aspec" {
+	switch histogramRequest.Attributes.TypeOfVisualization {
+	case "slope", "aspect", "hillshade":
 		if !(histogramRequest.Attributes.GradientAlgorithm == "Horn" || histogramRequest.Attributes.GradientAlgorithm == "ZevenbergenThorne") {
 			return errors.New("unsupported gradient algorithm (not Horn or ZevenbergenThorne)")
 		}
 	}
 
+	// verify percentiles
+	if len(histogramRequest.Attributes.Percentiles) > 20 {
+		return errors.New("number of percentiles exceeds 20")
+	}
+	for _, percentile := range histogramRequest.Attributes.Percentiles {
+		if percentile < 0 || percentile > 100 {
+			return fmt.Errorf("percentile [%v] not between 0 and 100", percentile)
+		}
+	}
+
+	// verify elevation bands (preset replaces number of bins, minimum and maximum value)
+	if histogramRequest.Attributes.ElevationBands != "" {
+		if histogramRequest.Attributes.TypeOfVisualization != "rawtif" {
+			return errors.New("elevation bands only supported for type of visualization rawtif")
+		}
+		if _, found := elevationBandPresets[strings.ToLower(histogramRequest.Attributes.ElevationBands)]; !found {
+			return errors.New("elevation bands preset not supported (valid: 10m, 25m, 50m, 100m, 250m)")
+		}
+		return nil
+	}
+
 	// verify type of histogram
 	histogramRequest.Attributes.TypeOfHistogram = strings.ToLower(histogramRequest.Attributes.TypeOfHistogram)
 	switch histogramRequest.Attributes.TypeOfHistogram {
@@ -286,136 +379,101 @@ buildHistogramResponse builds HTTP responses with specified status and body.
 It sets the Content-Type and Content-Length headers before writing the response body.
 This function is used to construct consistent HTTP responses throughout the application.
 */
-func buildHistogramResponse(writer http.ResponseWriter, httpStatus int, histogramResponse HistogramResponse) {
-	// log limit length of body (e.g., the histogram objects as part of the body can be very large)
-	maxBodyLength := 1024
-
-	// CORS: allow requests from any origin
-	writer.Header().Set("Access-Control-Allow-Origin", "*")
-	// CORS: allowed methods
-	writer.Header().Set("Access-Control-Allow-Methods", "POST")
-	// CORS: allowed headers
-	writer.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
-	// marshal response
-	body, err := json.MarshalIndent(histogramResponse, "", "  ")
-	if err != nil {
-		slog.Error("error marshaling point response", "error", err, "body length", len(body),
-			fmt.Sprintf("body (limited to first %d bytes)", maxBodyLength), body[:maxBodyLength])
+func buildHistogramResponse(writer http.ResponseWriter, request *http.Request, httpStatus int, histogramResponse HistogramResponse) {
+	// response metadata (versions, processing duration, cache hit)
+	histogramResponse.Meta = newResponseMeta(request.Context())
 
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-
-	// gzip response body
-	var bytesBuffer bytes.Buffer
-	gz := gzip.NewWriter(&bytesBuffer)
-
-	_, err = gz.Write(body)
-	if err != nil {
-		slog.Error("error at gz.Write()", "error", err)
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-
-	err = gz.Close()
-	if err != nil {
-		slog.Error("error at gz.Close()", "error", err)
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-
-	// set headers
-	writer.Header().Set("Content-Encoding", "gzip")
-	writer.Header().Set("Content-Type", JSONAPIMediaType)
-	writer.WriteHeader(httpStatus)
-
-	// send response
-	_, err = writer.Write(bytesBuffer.Bytes())
-	if err != nil {
-		slog.Error("error writing HTTP response body", "error", err, "body length", len(body),
-			fmt.Sprintf("body (limited to first %d bytes)", maxBodyLength), body[:maxBodyLength])
-	}
+	// encode (streaming) and send response
+	streamJSONResponse(writer, request, httpStatus, histogramResponse, false)
 }
 
 /*
 generateHistogramObjectForTile builds histogram object for given tile index.
 */
-func generateHistogramObjectForTile(tile TileMetadata, typeOfVisualization string, gradientAlgorithm string,
-	typeOfHistogram string, numberOfBins int, minValue string, maxValue string) (Histogram, error) {
+func generateHistogramObjectForTile(ctx context.Context, tile TileMetadata, typeOfVisualization string, gradientAlgorithm string,
+	typeOfHistogram string, numberOfBins int, minValue string, maxValue string, elevationBands string, percentiles []float64) (Histogram, error) {
 	var histogram Histogram
 
-	var commandExitStatus int
-	var commandOutput []byte
-	var err error
-
-	// run operations in temp directory
-	tempDir, err := os.MkdirTemp("", "dtm-elevation-service-histogram-")
-	if err != nil {
-		return histogram, fmt.Errorf("error [%w] at os.MkdirTemp()", err)
+	// lookup response cache
+	cacheKey := buildResponseCacheKey("histogram", tile.Index, tile.Actuality, typeOfVisualization, gradientAlgorithm,
+		typeOfHistogram, numberOfBins, minValue, maxValue, elevationBands, percentiles)
+	cached, found := responseCache.Get(cacheKey)
+	recordCacheLookup(ctx, found)
+	if found {
+		return cached.(Histogram), nil
 	}
-	defer func() {
-		_ = os.RemoveAll(tempDir)
-	}()
 
+	var err error
+
+	// run operations in memory (/vsimem)
+	vsimemPrefix := dtm.NewVSIMemPrefix("histogram")
 	inputGeoTIFF := tile.Path
-	histogramVisualization := filepath.Join(tempDir, tile.Index+".visualization")
-	histogramVisualizationXYZ := filepath.Join(tempDir, tile.Index+".visualization.xyz")
+	histogramVisualization := vsimemPrefix + tile.Index + ".visualization.tif"
+	defer removeVSIMemFiles(histogramVisualization)
 
 	// build visulization
 	switch strings.ToLower(typeOfVisualization) {
 	case "rawtif":
-		// For rawtif, the visualization is the input GeoTIFF itself, but we still need an XYZ for histogram
+		// for rawtif, the visualization is the input GeoTIFF itself
 		histogramVisualization = inputGeoTIFF
 
 	case "slope":
-		commandExitStatus, commandOutput, err = runCommand("gdaldem", []string{"slope", inputGeoTIFF, histogramVisualization, "-alg", gradientAlgorithm, "-compute_edges"})
+		err = dtm.Dem(ctx, "slope", inputGeoTIFF, "", histogramVisualization, []string{"-of", "GTiff", "-alg", gradientAlgorithm, "-compute_edges"})
 		if err != nil {
-			return histogram, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
+			return histogram, fmt.Errorf("error [%w] at dtm.Dem()", err)
 		}
 
 	case "aspect":
-		commandExitStatus, commandOutput, err = runCommand("gdaldem", []string{"aspect", inputGeoTIFF, histogramVisualization, "-alg", gradientAlgorithm, "-compute_edges"})
+		err = dtm.Dem(ctx, "aspect", inputGeoTIFF, "", histogramVisualization, []string{"-of", "GTiff", "-alg", gradientAlgorithm, "-compute_edges"})
 		if err != nil {
-			return histogram, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
+			return histogram, fmt.Errorf("error [%w] at dtm.Dem()", err)
 		}
 
 	case "roughness":
-		commandExitStatus, commandOutput, err = runCommand("gdaldem", []string{"roughness", inputGeoTIFF, histogramVisualization, "-compute_edges"})
+		err = dtm.Dem(ctx, "roughness", inputGeoTIFF, "", histogramVisualization, []string{"-of", "GTiff", "-compute_edges"})
 		if err != nil {
-			return histogram, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
+			return histogram, fmt.Errorf("error [%w] at dtm.Dem()", err)
 		}
 
 	case "tri":
-		commandExitStatus, commandOutput, err = runCommand("gdaldem", []string{"TRI", inputGeoTIFF, histogramVisualization, "-alg", "Riley", "-compute_edges"})
+		err = dtm.Dem(ctx, "TRI", inputGeoTIFF, "", histogramVisualization, []string{"-of", "GTiff", "-alg", "Riley", "-compute_edges"})
 		if err != nil {
-			return histogram, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
+			return histogram, fmt.Errorf("error [%w] at dtm.Dem()", err)
 		}
 
 	case "tpi":
-		commandExitStatus, commandOutput, err = runCommand("gdaldem", []string{"TPI", inputGeoTIFF, histogramVisualization, "-compute_edges"})
+		err = dtm.Dem(ctx, "TPI", inputGeoTIFF, "", histogramVisualization, []string{"-of", "GTiff", "-compute_edges"})
+		if err != nil {
+			return histogram, fmt.Errorf("error [%w] at dtm.Dem()", err)
+		}
+
+	case "hillshade":
+		// gray values (0-255) of standard hillshade (light from north-west, 45 degrees altitude)
+		err = dtm.Dem(ctx, "hillshade", inputGeoTIFF, "", histogramVisualization, []string{"-of", "GTiff", "-alg", gradientAlgorithm, "-compute_edges"})
 		if err != nil {
-			return histogram, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
+			return histogram, fmt.Errorf("error [%w] at dtm.Dem()", err)
 		}
 
 	default:
 		return histogram, fmt.Errorf("unsupported type of visualization [%s]", typeOfVisualization)
 	}
 
-	// build XYZ (text) file from visualization
-	// e.g. gdal_translate -co DECIMAL_PRECISION=5 -of XYZ 32_497_5670_tri.utm.tif 32_497_5670_tri.utm.xyz
-	commandExitStatus, commandOutput, err = runCommand("gdal_translate", []string{"-co", "DECIMAL_PRECISION=5", "-of", "XYZ", histogramVisualization, histogramVisualizationXYZ})
-	if err != nil {
-		return histogram, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
-	}
-
-	// Collect data for histogram
-	allNonSentinelValues, noValueCount, totalParsedValues, err := collectAllNonSentinelValues(histogramVisualizationXYZ)
+	// collect data for histogram (direct raster band reads)
+	allNonSentinelValues, noValueCount, totalParsedValues, err := collectRasterBandValues(histogramVisualization)
 	if err != nil {
-		return histogram, fmt.Errorf("error collecting data for histogram from '%s': %w", histogramVisualizationXYZ, err)
+		return histogram, fmt.Errorf("error collecting data for histogram from '%s': %w", histogramVisualization, err)
 	}
 	if totalParsedValues == 0 && noValueCount == 0 {
-		return histogram, errors.New("no valid numeric data found in file for histogram calculation")
+		return histogram, errors.New("no data found in raster for histogram calculation")
+	}
+
+	// elevation bands: equal-width bins aligned to multiples of band width
+	if elevationBands != "" {
+		numberOfBins, minValue, maxValue, err = getElevationBands(allNonSentinelValues, elevationBandPresets[strings.ToLower(elevationBands)])
+		if err != nil {
+			return histogram, fmt.Errorf("error [%w] at getElevationBands()", err)
+		}
+		typeOfHistogram = "standard"
 	}
 
 	// calculate histogram
@@ -425,6 +483,9 @@ func generateHistogramObjectForTile(tile TileMetadata, typeOfVisualization strin
 		return histogram, fmt.Errorf("error processing histogram data: %w", err)
 	}
 
+	// percentiles of all values
+	statistic.Percentiles = calculatePercentiles(allNonSentinelValues, percentiles)
+
 	// set histogram return structure
 	histogram.Statistic = statistic
 	histogram.Entries = entries
@@ -436,58 +497,105 @@ func generateHistogramObjectForTile(tile TileMetadata, typeOfVisualization strin
 	attribution := "unknown"
 	resource, err := getElevationResource(tile.Source)
 	if err != nil {
-		slog.Error("histogram request: error getting elevation resource", "error", err, "source", tile.Source)
+		slog.ErrorContext(ctx, "histogram request: error getting elevation resource", "error", err, "source", tile.Source)
 	} else {
 		attribution = resource.Attribution
 	}
 	histogram.Attribution = attribution
 
+	// add to response cache
+	responseCache.Add(cacheKey, histogram, 1024+64*len(histogram.Entries))
+
 	return histogram, nil
 }
 
 /*
-collectAllNonSentinelValues reads the specified file and collects all  float64 values found in the third space-separated
-column of each line, excluding the 'noValueSentinel'. It also counts total parsed values and sentinels.
+collectRasterBandValues reads the first band of the raster file (e.g. in-memory file) block by block and collects
+all values, excluding no-data values (no-data value of band or 'noValueSentinel'). It also counts total values
+and no-data values.
 */
-func collectAllNonSentinelValues(filePath string) (values []float64, noValueCount int, totalProcessedValues int, err error) {
-	file, err := os.Open(filePath)
+func collectRasterBandValues(filePath string) (values []float64, noValueCount int, totalProcessedValues int, err error) {
+	dataset, err := godal.Open(filePath, godal.RasterOnly())
 	if err != nil {
-		return nil, 0, 0, fmt.Errorf("failed to open file '%s': %w", filePath, err)
-	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	values = make([]float64, 0)
-
-	for scanner.Scan() {
-		line := scanner.Text()
-		parts := strings.Fields(line)
-
-		if len(parts) < 3 {
-			// Skip lines that do not have at least three columns.
-			continue
+		return nil, 0, 0, fmt.Errorf("error [%w] at godal.Open(), file: %s", err, filePath)
+	}
+	defer dataset.Close()
+
+	bands := dataset.Bands()
+	if len(bands) == 0 {
+		return nil, 0, 0, fmt.Errorf("no raster band in file '%s'", filePath)
+	}
+	band := bands[0]
+	noData, hasNoData := band.NoData()
+	structure := band.Structure()
+	blockSizeX := max(structure.BlockSizeX, 1)
+	blockSizeY := max(structure.BlockSizeY, 1)
+
+	values = make([]float64, 0, structure.SizeX*structure.SizeY)
+	buffer := make([]float64, blockSizeX*blockSizeY)
+
+	// read raster block by block (native block size, clipped at right and bottom edge)
+	for y := 0; y < structure.SizeY; y += blockSizeY {
+		height := min(blockSizeY, structure.SizeY-y)
+		for x := 0; x < structure.SizeX; x += blockSizeX {
+			width := min(blockSizeX, structure.SizeX-x)
+			err = band.Read(x, y, buffer, width, height)
+			if err != nil {
+				return nil, 0, 0, fmt.Errorf("error [%w] at band.Read(), file: %s, x: %d, y: %d", err, filePath, x, y)
+			}
+			for _, val := range buffer[:width*height] {
+				totalProcessedValues++
+				if val == noValueSentinel || math.IsNaN(val) || (hasNoData && val == noData) {
+					noValueCount++
+					continue
+				}
+				values = append(values, val)
+			}
 		}
+	}
 
-		val, parseErr := strconv.ParseFloat(parts[2], 64)
-		if parseErr != nil {
-			slog.Warn("histogram calculation: could not parse float from line", "line", line, "column", parts[2], "error", parseErr)
-			continue
-		}
+	return values, noValueCount, totalProcessedValues, nil
+}
 
-		totalProcessedValues++
+/*
+calculatePercentiles calculates the percentiles (0-100) of the values (linear interpolation between closest ranks).
+*/
+func calculatePercentiles(values []float64, percentiles []float64) []HistogramPercentile {
+	if len(values) == 0 || len(percentiles) == 0 {
+		return nil
+	}
+	sortedValues := slices.Clone(values)
+	slices.Sort(sortedValues)
+
+	result := make([]HistogramPercentile, 0, len(percentiles))
+	for _, percentile := range percentiles {
+		rank := percentile / 100 * float64(len(sortedValues)-1)
+		lower := int(math.Floor(rank))
+		upper := int(math.Ceil(rank))
+		value := sortedValues[lower] + (sortedValues[upper]-sortedValues[lower])*(rank-float64(lower))
+		result = append(result, HistogramPercentile{Percentile: percentile, Value: value})
+	}
+	return result
+}
 
-		if val == noValueSentinel {
-			noValueCount++
-		} else {
-			values = append(values, val)
-		}
+/*
+getElevationBands determines number of bins, minimum and maximum value for elevation bands of given width.
+*/
+func getElevationBands(values []float64, bandWidth float64) (int, string, string, error) {
+	minVal, maxVal, err := findMinMaxFromValues(values)
+	if err != nil {
+		return 0, "", "", err
 	}
-
-	if err = scanner.Err(); err != nil {
-		return nil, 0, 0, fmt.Errorf("error reading file '%s' during data collection: %w", filePath, err)
+	lowerBound := math.Floor(minVal/bandWidth) * bandWidth
+	upperBound := math.Ceil(maxVal/bandWidth) * bandWidth
+	if upperBound <= lowerBound {
+		upperBound = lowerBound + bandWidth
 	}
-
-	return values, noValueCount, totalProcessedValues, nil
+	numberOfBins := int(math.Round((upperBound - lowerBound) / bandWidth))
+	if numberOfBins > 999 {
+		return 0, "", "", fmt.Errorf("number of elevation bands (%d) exceeds 999", numberOfBins)
+	}
+	return numberOfBins, strconv.FormatFloat(lowerBound, 'f', -1, 64), strconv.FormatFloat(upperBound, 'f', -1, 64), nil
 }
 
 /*
@@ -782,14 +890,17 @@ func processHistogramData(allNonSentinelValues []float64, noValueCount int, tota
 	}
 
 	currentLowerBound := effectiveMinVal
+	cumulativeCount := 0
 	for i := 0; i < numberOfBins; i++ {
 		entry := HistogramEntry{
 			LowerBound: currentLowerBound,
 			UpperBound: binUpperBounds[i], // this will be adjusted for the last bin explicitly
 			BinCount:   tempBinCounts[i],
 		}
+		cumulativeCount += tempBinCounts[i]
 		if totalBinnedCount > 0 {
 			entry.BinPercent = (float64(tempBinCounts[i]) / float64(totalBinnedCount)) * 100
+			entry.CumulativePercent = (float64(cumulativeCount) / float64(totalBinnedCount)) * 100
 		}
 		entries = append(entries, entry)
 		currentLowerBound = binUpperBounds[i] // lower bound for the next bin is current upper bound
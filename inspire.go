@@ -0,0 +1,329 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+/*
+INSPIRE Elevation (EL) export: each tile is delivered as ElevationGridCoverage (GML 3.2 coverage description,
+application schema el-cov 4.0) referencing the original GeoTIFF as range set (file reference). The archive
+additionally contains an ISO 19139 metadata record (INSPIRE metadata for the data set).
+*/
+
+// INSPIRE namespaces and references
+const (
+	inspireElevationCoverageSchema = "http://inspire.ec.europa.eu/schemas/el-cov/4.0 https://inspire.ec.europa.eu/schemas/el-cov/4.0/ElevationGridCoverage.xsd"
+	inspireDataSpecification       = "INSPIRE Data Specification on Elevation – Technical Guidelines"
+	inspireRegulation              = "VERORDNUNG (EG) Nr. 1089/2010 DER KOMMISSION vom 23. November 2010 zur Durchführung der Richtlinie 2007/2/EG " +
+		"des Europäischen Parlaments und des Rates hinsichtlich der Interoperabilität von Geodatensätzen und -diensten"
+	inspireVerticalCRS = "http://www.opengis.net/def/crs/EPSG/0/7837" // DHHN2016 height
+)
+
+/*
+xmlEscape escapes text for XML content and attribute values.
+*/
+func xmlEscape(text string) string {
+	var builder strings.Builder
+	_ = xml.EscapeText(&builder, []byte(text))
+	return builder.String()
+}
+
+/*
+buildINSPIRECoverageGML builds the INSPIRE ElevationGridCoverage (GML) for a tile. The range set references
+the GeoTIFF file (file name relative to the GML file).
+*/
+func buildINSPIRECoverageGML(tile TileMetadata, tifFilename string) ([]byte, error) {
+	sizeX, sizeY, gt, err := getRasterGrid(tile.Path)
+	if err != nil {
+		return nil, fmt.Errorf("error [%w] at getRasterGrid()", err)
+	}
+	if gt[2] != 0 || gt[4] != 0 {
+		return nil, fmt.Errorf("raster [%s] is rotated or skewed", tile.Path)
+	}
+	zone, _, _, err := parseTileIndex(tile.Index)
+	if err != nil {
+		return nil, fmt.Errorf("error [%w] at parseTileIndex()", err)
+	}
+	horizontalCRS := fmt.Sprintf("http://www.opengis.net/def/crs/EPSG/0/%d", 25800+zone)
+	config := getProgConfig().Export.INSPIRE
+	id := "EL." + tile.Index
+
+	// extent of grid (outer edges of pixels) and origin (center of upper-left pixel)
+	minX := gt[0]
+	maxX := gt[0] + float64(sizeX)*gt[1]
+	maxY := gt[3]
+	minY := gt[3] + float64(sizeY)*gt[5]
+	originX := gt[0] + gt[1]/2
+	originY := gt[3] + gt[5]/2
+
+	var gml strings.Builder
+	fmt.Fprintf(&gml, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	fmt.Fprintf(&gml, "<el-cov:ElevationGridCoverage gml:id=\"%s\"\n", xmlEscape(id))
+	fmt.Fprintf(&gml, "  xmlns:el-cov=\"http://inspire.ec.europa.eu/schemas/el-cov/4.0\"\n")
+	fmt.Fprintf(&gml, "  xmlns:el-bas=\"http://inspire.ec.europa.eu/schemas/el-bas/4.0\"\n")
+	fmt.Fprintf(&gml, "  xmlns:base=\"http://inspire.ec.europa.eu/schemas/base/3.3\"\n")
+	fmt.Fprintf(&gml, "  xmlns:gml=\"http://www.opengis.net/gml/3.2\"\n")
+	fmt.Fprintf(&gml, "  xmlns:gmlcov=\"http://www.opengis.net/gmlcov/1.0\"\n")
+	fmt.Fprintf(&gml, "  xmlns:swe=\"http://www.opengis.net/swe/2.0\"\n")
+	fmt.Fprintf(&gml, "  xmlns:gmd=\"http://www.isotc211.org/2005/gmd\"\n")
+	fmt.Fprintf(&gml, "  xmlns:xlink=\"http://www.w3.org/1999/xlink\"\n")
+	fmt.Fprintf(&gml, "  xmlns:xsi=\"http://www.w3.org/2001/XMLSchema-instance\"\n")
+	fmt.Fprintf(&gml, "  xsi:schemaLocation=\"%s\">\n", inspireElevationCoverageSchema)
+	fmt.Fprintf(&gml, "  <gml:boundedBy>\n")
+	fmt.Fprintf(&gml, "    <gml:Envelope srsName=\"%s\">\n", horizontalCRS)
+	fmt.Fprintf(&gml, "      <gml:lowerCorner>%.3f %.3f</gml:lowerCorner>\n", minX, minY)
+	fmt.Fprintf(&gml, "      <gml:upperCorner>%.3f %.3f</gml:upperCorner>\n", maxX, maxY)
+	fmt.Fprintf(&gml, "    </gml:Envelope>\n")
+	fmt.Fprintf(&gml, "  </gml:boundedBy>\n")
+	fmt.Fprintf(&gml, "  <gml:domainSet>\n")
+	fmt.Fprintf(&gml, "    <gml:RectifiedGrid gml:id=\"%s.grid\" dimension=\"2\" srsName=\"%s\">\n", xmlEscape(id), horizontalCRS)
+	fmt.Fprintf(&gml, "      <gml:limits>\n")
+	fmt.Fprintf(&gml, "        <gml:GridEnvelope>\n")
+	fmt.Fprintf(&gml, "          <gml:low>0 0</gml:low>\n")
+	fmt.Fprintf(&gml, "          <gml:high>%d %d</gml:high>\n", sizeX-1, sizeY-1)
+	fmt.Fprintf(&gml, "        </gml:GridEnvelope>\n")
+	fmt.Fprintf(&gml, "      </gml:limits>\n")
+	fmt.Fprintf(&gml, "      <gml:axisLabels>E N</gml:axisLabels>\n")
+	fmt.Fprintf(&gml, "      <gml:origin>\n")
+	fmt.Fprintf(&gml, "        <gml:Point gml:id=\"%s.origin\" srsName=\"%s\">\n", xmlEscape(id), horizontalCRS)
+	fmt.Fprintf(&gml, "          <gml:pos>%.3f %.3f</gml:pos>\n", originX, originY)
+	fmt.Fprintf(&gml, "        </gml:Point>\n")
+	fmt.Fprintf(&gml, "      </gml:origin>\n")
+	fmt.Fprintf(&gml, "      <gml:offsetVector srsName=\"%s\">%g 0</gml:offsetVector>\n", horizontalCRS, gt[1])
+	fmt.Fprintf(&gml, "      <gml:offsetVector srsName=\"%s\">0 %g</gml:offsetVector>\n", horizontalCRS, gt[5])
+	fmt.Fprintf(&gml, "    </gml:RectifiedGrid>\n")
+	fmt.Fprintf(&gml, "  </gml:domainSet>\n")
+	fmt.Fprintf(&gml, "  <gml:rangeSet>\n")
+	fmt.Fprintf(&gml, "    <gml:File>\n")
+	fmt.Fprintf(&gml, "      <gml:rangeParameters/>\n")
+	fmt.Fprintf(&gml, "      <gml:fileName>%s</gml:fileName>\n", xmlEscape(tifFilename))
+	fmt.Fprintf(&gml, "      <gml:fileStructure>Record Interleaved</gml:fileStructure>\n")
+	fmt.Fprintf(&gml, "      <gml:mimeType>image/tiff</gml:mimeType>\n")
+	fmt.Fprintf(&gml, "    </gml:File>\n")
+	fmt.Fprintf(&gml, "  </gml:rangeSet>\n")
+	fmt.Fprintf(&gml, "  <gmlcov:rangeType>\n")
+	fmt.Fprintf(&gml, "    <swe:DataRecord>\n")
+	fmt.Fprintf(&gml, "      <swe:field name=\"elevation\">\n")
+	fmt.Fprintf(&gml, "        <swe:Quantity definition=\"http://inspire.ec.europa.eu/enumeration/ElevationPropertyTypeValue/height\">\n")
+	fmt.Fprintf(&gml, "          <swe:nilValues>\n")
+	fmt.Fprintf(&gml, "            <swe:NilValues>\n")
+	fmt.Fprintf(&gml, "              <swe:nilValue reason=\"http://www.opengis.net/def/nil/OGC/0/missing\">-9999</swe:nilValue>\n")
+	fmt.Fprintf(&gml, "            </swe:NilValues>\n")
+	fmt.Fprintf(&gml, "          </swe:nilValues>\n")
+	fmt.Fprintf(&gml, "          <swe:uom code=\"m\"/>\n")
+	fmt.Fprintf(&gml, "        </swe:Quantity>\n")
+	fmt.Fprintf(&gml, "      </swe:field>\n")
+	fmt.Fprintf(&gml, "    </swe:DataRecord>\n")
+	fmt.Fprintf(&gml, "  </gmlcov:rangeType>\n")
+	fmt.Fprintf(&gml, "  <el-cov:beginLifespanVersion>%s</el-cov:beginLifespanVersion>\n", inspireLifespanVersion(tile.Actuality))
+	fmt.Fprintf(&gml, "  <el-cov:domainExtent>\n")
+	fmt.Fprintf(&gml, "    <gmd:EX_Extent>\n")
+	fmt.Fprintf(&gml, "      <gmd:description><gco:CharacterString xmlns:gco=\"http://www.isotc211.org/2005/gco\">tile %s</gco:CharacterString></gmd:description>\n", xmlEscape(tile.Index))
+	fmt.Fprintf(&gml, "    </gmd:EX_Extent>\n")
+	fmt.Fprintf(&gml, "  </el-cov:domainExtent>\n")
+	fmt.Fprintf(&gml, "  <el-cov:inspireId>\n")
+	fmt.Fprintf(&gml, "    <base:Identifier>\n")
+	fmt.Fprintf(&gml, "      <base:localId>%s</base:localId>\n", xmlEscape(id))
+	fmt.Fprintf(&gml, "      <base:namespace>%s</base:namespace>\n", xmlEscape(config.Namespace))
+	fmt.Fprintf(&gml, "    </base:Identifier>\n")
+	fmt.Fprintf(&gml, "  </el-cov:inspireId>\n")
+	fmt.Fprintf(&gml, "  <el-cov:propertyType>height</el-cov:propertyType>\n")
+	fmt.Fprintf(&gml, "  <el-cov:surfaceType>DTM</el-cov:surfaceType>\n")
+	fmt.Fprintf(&gml, "  <el-cov:contributingElevationGridCoverage xsi:nil=\"true\"/>\n")
+	fmt.Fprintf(&gml, "  <el-cov:verticalCRS xlink:href=\"%s\"/>\n", inspireVerticalCRS)
+	fmt.Fprintf(&gml, "</el-cov:ElevationGridCoverage>\n")
+
+	return []byte(gml.String()), nil
+}
+
+/*
+inspireLifespanVersion returns the begin of the lifespan version (xs:dateTime) for the actuality of a tile.
+*/
+func inspireLifespanVersion(actuality string) string {
+	date, err := time.Parse("2006-01-02", actuality)
+	if err != nil {
+		return "1970-01-01T00:00:00Z"
+	}
+	return date.Format(time.RFC3339)
+}
+
+/*
+buildINSPIREMetadata builds the ISO 19139 metadata record (INSPIRE metadata) for the exported tiles.
+*/
+func buildINSPIREMetadata(tiles []TileMetadata, now time.Time) ([]byte, error) {
+	if len(tiles) == 0 {
+		return nil, fmt.Errorf("no tiles")
+	}
+	config := getProgConfig().Export.INSPIRE
+	if config.Organisation == "" {
+		config.Organisation = "unknown"
+	}
+	if config.Email == "" {
+		config.Email = "unknown"
+	}
+
+	// geographic extent, temporal extent, reference systems and attributions of all tiles
+	box := WGS84BoundingBox{MinLon: math.Inf(1), MinLat: math.Inf(1), MaxLon: math.Inf(-1), MaxLat: math.Inf(-1)}
+	var oldest, newest string
+	var zones []int
+	var attributions []string
+	for _, tile := range tiles {
+		tileBox, err := calculateWGS84BoundingBox(tile)
+		if err != nil {
+			return nil, fmt.Errorf("error [%w] at calculateWGS84BoundingBox(), tile: %s", err, tile.Index)
+		}
+		box.MinLon = math.Min(box.MinLon, tileBox.MinLon)
+		box.MinLat = math.Min(box.MinLat, tileBox.MinLat)
+		box.MaxLon = math.Max(box.MaxLon, tileBox.MaxLon)
+		box.MaxLat = math.Max(box.MaxLat, tileBox.MaxLat)
+		if oldest == "" || tile.Actuality < oldest {
+			oldest = tile.Actuality
+		}
+		if tile.Actuality > newest {
+			newest = tile.Actuality
+		}
+		zone, _, _, err := parseTileIndex(tile.Index)
+		if err == nil && !slices.Contains(zones, zone) {
+			zones = append(zones, zone)
+		}
+		attribution := "unknown (source " + tile.Source + ")"
+		if resource, err := getElevationResource(tile.Source); err == nil {
+			attribution = resource.Attribution
+		}
+		if !slices.Contains(attributions, attribution) {
+			attributions = append(attributions, attribution)
+		}
+	}
+	slices.Sort(zones)
+	slices.Sort(attributions)
+
+	identifier := make([]byte, 16)
+	_, err := rand.Read(identifier)
+	if err != nil {
+		return nil, fmt.Errorf("error [%w] at rand.Read()", err)
+	}
+
+	characterString := func(text string) string {
+		return "<gco:CharacterString>" + xmlEscape(text) + "</gco:CharacterString>"
+	}
+	date := now.Format("2006-01-02")
+
+	var md strings.Builder
+	fmt.Fprintf(&md, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	fmt.Fprintf(&md, "<gmd:MD_Metadata xmlns:gmd=\"http://www.isotc211.org/2005/gmd\" xmlns:gco=\"http://www.isotc211.org/2005/gco\"\n")
+	fmt.Fprintf(&md, "  xmlns:gml=\"http://www.opengis.net/gml/3.2\" xmlns:gmx=\"http://www.isotc211.org/2005/gmx\" xmlns:xlink=\"http://www.w3.org/1999/xlink\">\n")
+	fmt.Fprintf(&md, "  <gmd:fileIdentifier>%s</gmd:fileIdentifier>\n", characterString(hex.EncodeToString(identifier)))
+	fmt.Fprintf(&md, "  <gmd:language><gmd:LanguageCode codeList=\"http://www.loc.gov/standards/iso639-2/\" codeListValue=\"ger\">ger</gmd:LanguageCode></gmd:language>\n")
+	fmt.Fprintf(&md, "  <gmd:characterSet><gmd:MD_CharacterSetCode codeList=\"http://standards.iso.org/iso/19139/resources/gmxCodelists.xml#MD_CharacterSetCode\" codeListValue=\"utf8\">utf8</gmd:MD_CharacterSetCode></gmd:characterSet>\n")
+	fmt.Fprintf(&md, "  <gmd:hierarchyLevel><gmd:MD_ScopeCode codeList=\"http://standards.iso.org/iso/19139/resources/gmxCodelists.xml#MD_ScopeCode\" codeListValue=\"dataset\">dataset</gmd:MD_ScopeCode></gmd:hierarchyLevel>\n")
+	writeINSPIREContact(&md, "contact", config.Organisation, config.Email, "pointOfContact", characterString)
+	fmt.Fprintf(&md, "  <gmd:dateStamp><gco:Date>%s</gco:Date></gmd:dateStamp>\n", date)
+	fmt.Fprintf(&md, "  <gmd:metadataStandardName>%s</gmd:metadataStandardName>\n", characterString("ISO 19115:2003/19139"))
+	fmt.Fprintf(&md, "  <gmd:metadataStandardVersion>%s</gmd:metadataStandardVersion>\n", characterString("1.0"))
+	for _, zone := range zones {
+		fmt.Fprintf(&md, "  <gmd:referenceSystemInfo><gmd:MD_ReferenceSystem><gmd:referenceSystemIdentifier><gmd:RS_Identifier>\n")
+		fmt.Fprintf(&md, "    <gmd:code><gmx:Anchor xlink:href=\"http://www.opengis.net/def/crs/EPSG/0/%d\">EPSG:%d</gmx:Anchor></gmd:code>\n", 25800+zone, 25800+zone)
+		fmt.Fprintf(&md, "  </gmd:RS_Identifier></gmd:referenceSystemIdentifier></gmd:MD_ReferenceSystem></gmd:referenceSystemInfo>\n")
+	}
+	fmt.Fprintf(&md, "  <gmd:referenceSystemInfo><gmd:MD_ReferenceSystem><gmd:referenceSystemIdentifier><gmd:RS_Identifier>\n")
+	fmt.Fprintf(&md, "    <gmd:code><gmx:Anchor xlink:href=\"%s\">EPSG:7837 (DHHN2016)</gmx:Anchor></gmd:code>\n", inspireVerticalCRS)
+	fmt.Fprintf(&md, "  </gmd:RS_Identifier></gmd:referenceSystemIdentifier></gmd:MD_ReferenceSystem></gmd:referenceSystemInfo>\n")
+
+	// identification
+	fmt.Fprintf(&md, "  <gmd:identificationInfo>\n")
+	fmt.Fprintf(&md, "    <gmd:MD_DataIdentification>\n")
+	fmt.Fprintf(&md, "      <gmd:citation><gmd:CI_Citation>\n")
+	fmt.Fprintf(&md, "        <gmd:title>%s</gmd:title>\n", characterString(fmt.Sprintf("Digitales Geländemodell 1 m (DGM1), Export %d Kacheln", len(tiles))))
+	fmt.Fprintf(&md, "        <gmd:date><gmd:CI_Date><gmd:date><gco:Date>%s</gco:Date></gmd:date>\n", date)
+	fmt.Fprintf(&md, "          <gmd:dateType><gmd:CI_DateTypeCode codeList=\"http://standards.iso.org/iso/19139/resources/gmxCodelists.xml#CI_DateTypeCode\" codeListValue=\"creation\">creation</gmd:CI_DateTypeCode></gmd:dateType>\n")
+	fmt.Fprintf(&md, "        </gmd:CI_Date></gmd:date>\n")
+	fmt.Fprintf(&md, "        <gmd:identifier><gmd:RS_Identifier><gmd:code>%s</gmd:code><gmd:codeSpace>%s</gmd:codeSpace></gmd:RS_Identifier></gmd:identifier>\n",
+		characterString("EL."+hex.EncodeToString(identifier)), characterString(config.Namespace))
+	fmt.Fprintf(&md, "      </gmd:CI_Citation></gmd:citation>\n")
+	fmt.Fprintf(&md, "      <gmd:abstract>%s</gmd:abstract>\n", characterString(
+		"Digitales Geländemodell (Bodenoberfläche) mit 1 m Gitterweite aus Airborne Laserscanning der Vermessungsverwaltungen der Länder, "+
+			"bereitgestellt als INSPIRE ElevationGridCoverage (GML Coverage mit GeoTIFF Wertebereich)."))
+	writeINSPIREContact(&md, "pointOfContact", config.Organisation, config.Email, "pointOfContact", characterString)
+	fmt.Fprintf(&md, "      <gmd:descriptiveKeywords><gmd:MD_Keywords>\n")
+	fmt.Fprintf(&md, "        <gmd:keyword><gmx:Anchor xlink:href=\"http://inspire.ec.europa.eu/theme/el\">Höhe</gmx:Anchor></gmd:keyword>\n")
+	fmt.Fprintf(&md, "        <gmd:thesaurusName><gmd:CI_Citation><gmd:title>%s</gmd:title>\n", characterString("GEMET - INSPIRE themes, version 1.0"))
+	fmt.Fprintf(&md, "          <gmd:date><gmd:CI_Date><gmd:date><gco:Date>2008-06-01</gco:Date></gmd:date>\n")
+	fmt.Fprintf(&md, "            <gmd:dateType><gmd:CI_DateTypeCode codeList=\"http://standards.iso.org/iso/19139/resources/gmxCodelists.xml#CI_DateTypeCode\" codeListValue=\"publication\">publication</gmd:CI_DateTypeCode></gmd:dateType>\n")
+	fmt.Fprintf(&md, "          </gmd:CI_Date></gmd:date>\n")
+	fmt.Fprintf(&md, "        </gmd:CI_Citation></gmd:thesaurusName>\n")
+	fmt.Fprintf(&md, "      </gmd:MD_Keywords></gmd:descriptiveKeywords>\n")
+	fmt.Fprintf(&md, "      <gmd:resourceConstraints><gmd:MD_LegalConstraints>\n")
+	fmt.Fprintf(&md, "        <gmd:accessConstraints><gmd:MD_RestrictionCode codeList=\"http://standards.iso.org/iso/19139/resources/gmxCodelists.xml#MD_RestrictionCode\" codeListValue=\"otherRestrictions\">otherRestrictions</gmd:MD_RestrictionCode></gmd:accessConstraints>\n")
+	fmt.Fprintf(&md, "        <gmd:otherConstraints><gmx:Anchor xlink:href=\"http://inspire.ec.europa.eu/metadata-codelist/LimitationsOnPublicAccess/noLimitations\">Es gelten keine Zugriffsbeschränkungen</gmx:Anchor></gmd:otherConstraints>\n")
+	fmt.Fprintf(&md, "      </gmd:MD_LegalConstraints></gmd:resourceConstraints>\n")
+	fmt.Fprintf(&md, "      <gmd:resourceConstraints><gmd:MD_LegalConstraints>\n")
+	fmt.Fprintf(&md, "        <gmd:useConstraints><gmd:MD_RestrictionCode codeList=\"http://standards.iso.org/iso/19139/resources/gmxCodelists.xml#MD_RestrictionCode\" codeListValue=\"otherRestrictions\">otherRestrictions</gmd:MD_RestrictionCode></gmd:useConstraints>\n")
+	for _, attribution := range attributions {
+		fmt.Fprintf(&md, "        <gmd:otherConstraints>%s</gmd:otherConstraints>\n", characterString("Quellenvermerk: "+attribution))
+	}
+	fmt.Fprintf(&md, "      </gmd:MD_LegalConstraints></gmd:resourceConstraints>\n")
+	fmt.Fprintf(&md, "      <gmd:spatialRepresentationType><gmd:MD_SpatialRepresentationTypeCode codeList=\"http://standards.iso.org/iso/19139/resources/gmxCodelists.xml#MD_SpatialRepresentationTypeCode\" codeListValue=\"grid\">grid</gmd:MD_SpatialRepresentationTypeCode></gmd:spatialRepresentationType>\n")
+	fmt.Fprintf(&md, "      <gmd:spatialResolution><gmd:MD_Resolution><gmd:distance><gco:Distance uom=\"m\">1</gco:Distance></gmd:distance></gmd:MD_Resolution></gmd:spatialResolution>\n")
+	fmt.Fprintf(&md, "      <gmd:language><gmd:LanguageCode codeList=\"http://www.loc.gov/standards/iso639-2/\" codeListValue=\"ger\">ger</gmd:LanguageCode></gmd:language>\n")
+	fmt.Fprintf(&md, "      <gmd:topicCategory><gmd:MD_TopicCategoryCode>elevation</gmd:MD_TopicCategoryCode></gmd:topicCategory>\n")
+	fmt.Fprintf(&md, "      <gmd:extent><gmd:EX_Extent>\n")
+	fmt.Fprintf(&md, "        <gmd:geographicElement><gmd:EX_GeographicBoundingBox>\n")
+	fmt.Fprintf(&md, "          <gmd:westBoundLongitude><gco:Decimal>%.6f</gco:Decimal></gmd:westBoundLongitude>\n", box.MinLon)
+	fmt.Fprintf(&md, "          <gmd:eastBoundLongitude><gco:Decimal>%.6f</gco:Decimal></gmd:eastBoundLongitude>\n", box.MaxLon)
+	fmt.Fprintf(&md, "          <gmd:southBoundLatitude><gco:Decimal>%.6f</gco:Decimal></gmd:southBoundLatitude>\n", box.MinLat)
+	fmt.Fprintf(&md, "          <gmd:northBoundLatitude><gco:Decimal>%.6f</gco:Decimal></gmd:northBoundLatitude>\n", box.MaxLat)
+	fmt.Fprintf(&md, "        </gmd:EX_GeographicBoundingBox></gmd:geographicElement>\n")
+	fmt.Fprintf(&md, "        <gmd:temporalElement><gmd:EX_TemporalExtent><gmd:extent>\n")
+	fmt.Fprintf(&md, "          <gml:TimePeriod gml:id=\"actuality\"><gml:beginPosition>%s</gml:beginPosition><gml:endPosition>%s</gml:endPosition></gml:TimePeriod>\n",
+		xmlEscape(oldest), xmlEscape(newest))
+	fmt.Fprintf(&md, "        </gmd:extent></gmd:EX_TemporalExtent></gmd:temporalElement>\n")
+	fmt.Fprintf(&md, "      </gmd:EX_Extent></gmd:extent>\n")
+	fmt.Fprintf(&md, "    </gmd:MD_DataIdentification>\n")
+	fmt.Fprintf(&md, "  </gmd:identificationInfo>\n")
+
+	// distribution
+	fmt.Fprintf(&md, "  <gmd:distributionInfo><gmd:MD_Distribution>\n")
+	fmt.Fprintf(&md, "    <gmd:distributionFormat><gmd:MD_Format><gmd:name>%s</gmd:name><gmd:version>%s</gmd:version></gmd:MD_Format></gmd:distributionFormat>\n",
+		characterString("GML"), characterString("3.2.1"))
+	fmt.Fprintf(&md, "    <gmd:distributionFormat><gmd:MD_Format><gmd:name>%s</gmd:name><gmd:version>%s</gmd:version></gmd:MD_Format></gmd:distributionFormat>\n",
+		characterString("GeoTIFF"), characterString("1.0"))
+	fmt.Fprintf(&md, "  </gmd:MD_Distribution></gmd:distributionInfo>\n")
+
+	// data quality (conformity not evaluated, lineage)
+	fmt.Fprintf(&md, "  <gmd:dataQualityInfo><gmd:DQ_DataQuality>\n")
+	fmt.Fprintf(&md, "    <gmd:scope><gmd:DQ_Scope><gmd:level><gmd:MD_ScopeCode codeList=\"http://standards.iso.org/iso/19139/resources/gmxCodelists.xml#MD_ScopeCode\" codeListValue=\"dataset\">dataset</gmd:MD_ScopeCode></gmd:level></gmd:DQ_Scope></gmd:scope>\n")
+	fmt.Fprintf(&md, "    <gmd:report><gmd:DQ_DomainConsistency><gmd:result><gmd:DQ_ConformanceResult>\n")
+	fmt.Fprintf(&md, "      <gmd:specification><gmd:CI_Citation><gmd:title>%s</gmd:title>\n", characterString(inspireRegulation))
+	fmt.Fprintf(&md, "        <gmd:date><gmd:CI_Date><gmd:date><gco:Date>2010-12-08</gco:Date></gmd:date>\n")
+	fmt.Fprintf(&md, "          <gmd:dateType><gmd:CI_DateTypeCode codeList=\"http://standards.iso.org/iso/19139/resources/gmxCodelists.xml#CI_DateTypeCode\" codeListValue=\"publication\">publication</gmd:CI_DateTypeCode></gmd:dateType>\n")
+	fmt.Fprintf(&md, "        </gmd:CI_Date></gmd:date>\n")
+	fmt.Fprintf(&md, "      </gmd:CI_Citation></gmd:specification>\n")
+	fmt.Fprintf(&md, "      <gmd:explanation>%s</gmd:explanation>\n", characterString("Struktur gemäß "+inspireDataSpecification+" (ElevationGridCoverage), Konformität nicht geprüft"))
+	fmt.Fprintf(&md, "      <gmd:pass gco:nilReason=\"unknown\"/>\n")
+	fmt.Fprintf(&md, "    </gmd:DQ_ConformanceResult></gmd:result></gmd:DQ_DomainConsistency></gmd:report>\n")
+	fmt.Fprintf(&md, "    <gmd:lineage><gmd:LI_Lineage><gmd:statement>%s</gmd:statement></gmd:LI_Lineage></gmd:lineage>\n", characterString(
+		fmt.Sprintf("DGM1 Kacheln (1 km x 1 km) der Länder, Befliegung %s bis %s, unverändert exportiert durch %s %s.", oldest, newest, progName, progVersion)))
+	fmt.Fprintf(&md, "  </gmd:DQ_DataQuality></gmd:dataQualityInfo>\n")
+	fmt.Fprintf(&md, "</gmd:MD_Metadata>\n")
+
+	return []byte(md.String()), nil
+}
+
+/*
+writeINSPIREContact writes a responsible party (organisation, email, role) as metadata element.
+*/
+func writeINSPIREContact(md *strings.Builder, element string, organisation string, email string, role string, characterString func(string) string) {
+	fmt.Fprintf(md, "  <gmd:%s><gmd:CI_ResponsibleParty>\n", element)
+	fmt.Fprintf(md, "    <gmd:organisationName>%s</gmd:organisationName>\n", characterString(organisation))
+	fmt.Fprintf(md, "    <gmd:contactInfo><gmd:CI_Contact><gmd:address><gmd:CI_Address><gmd:electronicMailAddress>%s</gmd:electronicMailAddress></gmd:CI_Address></gmd:address></gmd:CI_Contact></gmd:contactInfo>\n",
+		characterString(email))
+	fmt.Fprintf(md, "    <gmd:role><gmd:CI_RoleCode codeList=\"http://standards.iso.org/iso/19139/resources/gmxCodelists.xml#CI_RoleCode\" codeListValue=\"%s\">%s</gmd:CI_RoleCode></gmd:role>\n", role, role)
+	fmt.Fprintf(md, "  </gmd:CI_ResponsibleParty></gmd:%s>\n", element)
+}
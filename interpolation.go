@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+/*
+getBilinearElevationForUTMPoint retrieves the bilinear interpolated elevation for a UTM coordinate. The elevation
+is interpolated between the centers of the four surrounding grid cells (cells may belong to neighboring tiles).
+Cells without elevation data are ignored (weights of the remaining cells are normalized). The returned tile is
+the tile containing the coordinate.
+*/
+func getBilinearElevationForUTMPoint(zone int, easting, northing float64) (float64, TileMetadata, []InterpolationCell, []string, error) {
+	var warnings []string
+
+	// elevation (nearest) and tile for coordinate
+	nearestElevation, tile, err := getElevationForUTMPoint(zone, easting, northing)
+	if err != nil {
+		return nearestElevation, tile, nil, warnings, err
+	}
+
+	gt, err := getGeoTransformFromFile(tile.Path)
+	if err != nil {
+		return nearestElevation, tile, nil, warnings, fmt.Errorf("error [%w] at getGeoTransformFromFile()", err)
+	}
+
+	// fractional cell position relative to cell centers
+	colF := (easting-gt[0])/gt[1] - 0.5
+	rowF := (northing-gt[3])/gt[5] - 0.5
+	col := math.Floor(colF)
+	row := math.Floor(rowF)
+	dx := colF - col
+	dy := rowF - row
+
+	cells := []InterpolationCell{
+		{Easting: gt[0] + (col+0.5)*gt[1], Northing: gt[3] + (row+0.5)*gt[5], Weight: (1 - dx) * (1 - dy)},
+		{Easting: gt[0] + (col+1.5)*gt[1], Northing: gt[3] + (row+0.5)*gt[5], Weight: dx * (1 - dy)},
+		{Easting: gt[0] + (col+0.5)*gt[1], Northing: gt[3] + (row+1.5)*gt[5], Weight: (1 - dx) * dy},
+		{Easting: gt[0] + (col+1.5)*gt[1], Northing: gt[3] + (row+1.5)*gt[5], Weight: dx * dy},
+	}
+
+	var contributingCells []InterpolationCell
+	weightSum := 0.0
+	for _, cell := range cells {
+		elevation, cellTile, err := getElevationForUTMPoint(zone, cell.Easting, cell.Northing)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("cell at easting %.3f, northing %.3f ignored (no elevation data)", cell.Easting, cell.Northing))
+			continue
+		}
+		cell.Elevation = elevation
+		cell.TileIndex = cellTile.Index
+		contributingCells = append(contributingCells, cell)
+		weightSum += cell.Weight
+	}
+
+	// no contributing cell with weight (e.g. coordinate on cell center without data), fallback to nearest elevation
+	if weightSum <= 0 {
+		warnings = append(warnings, "bilinear interpolation not possible, elevation of nearest cell applied")
+		return nearestElevation, tile, nil, warnings, nil
+	}
+
+	elevation := 0.0
+	for i := range contributingCells {
+		contributingCells[i].Weight /= weightSum
+		elevation += contributingCells[i].Weight * contributingCells[i].Elevation
+	}
+
+	return elevation, tile, contributingCells, warnings, nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"time"
+
+	"klaus/elevation/dtm-elevation-service/pkg/dtm"
+)
+
+// ErrServerBusy indicates that no GDAL processing slot became available within the max queue wait time.
+var ErrServerBusy = errors.New("server busy, too many concurrent processing jobs")
+
+// gdalJobQueue limits the number of concurrent GDAL processing jobs (all endpoint classes)
+var gdalJobQueue *workerPool
+
+/*
+initGDALJobQueue initializes the GDAL job queue. maxParallelJobs <= 0 defaults to the number of CPUs.
+*/
+func initGDALJobQueue(maxParallelJobs int, maxQueueWait time.Duration) {
+	if maxParallelJobs <= 0 {
+		maxParallelJobs = runtime.NumCPU()
+	}
+	gdalJobQueue = newWorkerPool(maxParallelJobs, maxQueueWait, &GDALJobsQueued, &GDALJobsRejected)
+	dtm.SetJobLimiter(gdalJobLimiter{})
+}
+
+/*
+acquireGDALJobSlot waits for a free processing slot of the endpoint class (if limited, see workerPoolMiddleware)
+and for a free GDAL processing slot. Returns ErrServerBusy if no slot became available within the max queue
+wait time, or the context error if the request was canceled.
+*/
+func acquireGDALJobSlot(ctx context.Context) error {
+	classPool := getContextWorkerPool(ctx)
+	err := classPool.acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = gdalJobQueue.acquire(ctx)
+	if err != nil {
+		classPool.release()
+		return err
+	}
+	return nil
+}
+
+/*
+releaseGDALJobSlot releases the GDAL processing slot and the processing slot of the endpoint class.
+*/
+func releaseGDALJobSlot(ctx context.Context) {
+	gdalJobQueue.release()
+	getContextWorkerPool(ctx).release()
+}
+
+// gdalJobLimiter limits the GDAL processing jobs of package dtm by the GDAL job queue and the endpoint class pools.
+type gdalJobLimiter struct{}
+
+/*
+Acquire waits for a free GDAL processing slot (see acquireGDALJobSlot).
+*/
+func (gdalJobLimiter) Acquire(ctx context.Context) error {
+	return acquireGDALJobSlot(ctx)
+}
+
+/*
+Release releases the GDAL processing slot (see releaseGDALJobSlot).
+*/
+func (gdalJobLimiter) Release(ctx context.Context) {
+	releaseGDALJobSlot(ctx)
+}
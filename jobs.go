@@ -0,0 +1,524 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// job states
+const (
+	jobStatusQueued    = "queued"
+	jobStatusRunning   = "running"
+	jobStatusCompleted = "completed"
+	jobStatusFailed    = "failed"
+	jobStatusCanceled  = "canceled"
+)
+
+// Job represents an asynchronous job (request for an endpoint processed in background).
+type Job struct {
+	mutex        sync.Mutex
+	id           string
+	tenant       string // only the tenant who submitted the job can access it
+	endpoint     string
+	status       string
+	created      time.Time
+	started      time.Time
+	finished     time.Time
+	resultStatus int
+	resultHeader http.Header
+	result       []byte
+	cancel       context.CancelFunc
+
+	callbackURL    string // optional: called when job is completed or failed
+	callbackSecret string // shared secret for signing callbacks (never returned)
+	callbackState  string // pending, delivered, failed
+
+	tilesTotal int           // number of tiles to process (progress)
+	tilesDone  int           // number of processed tiles (progress)
+	changed    chan struct{} // closed on change of job state (progress, status)
+}
+
+// jobs holds all asynchronous jobs (key: job id)
+var (
+	jobsMutex sync.Mutex
+	jobs      = make(map[string]*Job)
+)
+
+// jobSlots limits the number of concurrently running jobs
+var jobSlots chan struct{}
+
+/*
+initJobs initializes the asynchronous job subsystem (max number of concurrently running jobs).
+*/
+func initJobs(maxParallelJobs int) {
+	if maxParallelJobs <= 0 {
+		maxParallelJobs = 2
+	}
+	jobSlots = make(chan struct{}, maxParallelJobs)
+}
+
+// jobOnlyEndpoints lists endpoints which are available as job only (no route, e.g. bulk export)
+var jobOnlyEndpoints = []V2Endpoint{
+	{"", []string{http.MethodPost}, EndpointExport, TypeExportRequest, MaxExportRequestBodySize, exportRequest},
+	{"", []string{http.MethodPost}, EndpointContoursExport, TypeContoursExportRequest, MaxContoursExportRequestBodySize, contoursExportRequest},
+}
+
+/*
+getJobEndpoint returns the endpoint (v1 handler, see v2Endpoints and jobOnlyEndpoints) for a job.
+Only POST endpoints can be processed as job.
+*/
+func getJobEndpoint(path string) (V2Endpoint, bool) {
+	endpoints := slices.Concat(v2Endpoints, jobOnlyEndpoints)
+	index := slices.IndexFunc(endpoints, func(endpoint V2Endpoint) bool {
+		return endpoint.Endpoint.Path == path && slices.Contains(endpoint.Methods, http.MethodPost)
+	})
+	if index < 0 {
+		return V2Endpoint{}, false
+	}
+	return endpoints[index], true
+}
+
+/*
+newJobID returns a random job id (not guessable, 128 bit).
+*/
+func newJobID() (string, error) {
+	data := make([]byte, 16)
+	_, err := rand.Read(data)
+	if err != nil {
+		return "", fmt.Errorf("error [%w] at rand.Read()", err)
+	}
+	return hex.EncodeToString(data), nil
+}
+
+/*
+jobSubmitRequest handles 'job request' from client: the job is queued and the job state is returned immediately
+(202 Accepted, 'Location' header refers to the job state).
+*/
+func jobSubmitRequest(writer http.ResponseWriter, request *http.Request) {
+	var jobResponse = JobResponse{Type: TypeJobResponse, ID: "unknown"}
+	jobResponse.Attributes.IsError = true
+
+	// statistics
+	atomic.AddUint64(&JobRequests, 1)
+
+	// limit overall request body size
+	request.Body = http.MaxBytesReader(writer, request.Body, MaxJobRequestBodySize)
+
+	// read request
+	bodyData, err := io.ReadAll(request.Body)
+	if err != nil {
+		// check specifically for the error returned by MaxBytesReader
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			slog.WarnContext(request.Context(), "job request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
+			jobResponse.Attributes.Error = newErrorObject(EndpointJobs, ReasonRequestBodyTooLarge, fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit))
+			buildJobResponse(writer, request, http.StatusRequestEntityTooLarge, jobResponse)
+		} else {
+			// handle other read errors
+			slog.WarnContext(request.Context(), "job request: error reading request body", "error", err, "ID", "unknown")
+			jobResponse.Attributes.Error = newErrorObject(EndpointJobs, ReasonReadingRequestBody, err.Error())
+			buildJobResponse(writer, request, http.StatusBadRequest, jobResponse)
+		}
+		return
+	}
+
+	// unmarshal request
+	jobRequest := JobRequest{}
+	err = json.Unmarshal(bodyData, &jobRequest)
+	if err != nil {
+		slog.WarnContext(request.Context(), "job request: error unmarshaling request body", "error", err, "ID", "unknown")
+		jobResponse.Attributes.Error = newErrorObject(EndpointJobs, ReasonUnmarshalingRequestBody, err.Error())
+		buildJobResponse(writer, request, http.StatusBadRequest, jobResponse)
+		return
+	}
+
+	// copy request parameters into response
+	jobResponse.ID = jobRequest.ID
+	jobResponse.Attributes.Endpoint = jobRequest.Attributes.Endpoint
+
+	// verify request data
+	endpoint, err := verifyJobRequestData(request, jobRequest)
+	if err != nil {
+		slog.WarnContext(request.Context(), "job request: error verifying request data", "error", err, "ID", jobRequest.ID)
+		jobResponse.Attributes.Error = newErrorObject(EndpointJobs, ReasonVerifyingRequestData, err.Error())
+		buildJobResponse(writer, request, http.StatusBadRequest, jobResponse)
+		return
+	}
+
+	// authorization of the target endpoint (authMiddleware only checks the rules of /v1/jobs)
+	if jwtValidator != nil {
+		requiredScopes, public := getRequiredScopes(endpoint.Endpoint.Path)
+		if !public {
+			claims, authenticated := getJWTClaims(request.Context())
+			if !authenticated {
+				atomic.AddUint64(&AuthenticationFailures, 1)
+				slog.WarnContext(request.Context(), "job request: authentication failed: missing bearer token", "endpoint", endpoint.Endpoint.Path, "ID", jobRequest.ID)
+				writeAuthError(writer, http.StatusUnauthorized, `Bearer`, "missing bearer token")
+				return
+			}
+			if !hasRequiredScopes(claims.Scopes, requiredScopes) {
+				atomic.AddUint64(&AuthorizationFailures, 1)
+				slog.WarnContext(request.Context(), "job request: authorization failed: insufficient scope", "subject", claims.Subject,
+					"endpoint", endpoint.Endpoint.Path, "required scopes", requiredScopes, "ID", jobRequest.ID)
+				writeAuthError(writer, http.StatusForbidden,
+					fmt.Sprintf(`Bearer error="insufficient_scope", scope="%s"`, strings.Join(requiredScopes, " ")), "insufficient scope")
+				return
+			}
+		}
+	}
+
+	jobID, err := newJobID()
+	if err != nil {
+		slog.ErrorContext(request.Context(), "job request: error creating job id", "error", err, "ID", jobRequest.ID)
+		jobResponse.Attributes.Error = newErrorObject(EndpointService, ReasonInternalServerError, "")
+		buildJobResponse(writer, request, http.StatusInternalServerError, jobResponse)
+		return
+	}
+
+	// job context: detached from client connection (request id, tenant, etc. are preserved)
+	jobContext, cancel := context.WithCancel(context.WithoutCancel(request.Context()))
+	job := &Job{
+		id:       jobID,
+		tenant:   getTenant(request.Context()),
+		endpoint: endpoint.Endpoint.Path,
+		status:   jobStatusQueued,
+		created:  time.Now(),
+		cancel:   cancel,
+
+		callbackURL:    jobRequest.Attributes.CallbackURL,
+		callbackSecret: jobRequest.Attributes.CallbackSecret,
+	}
+
+	// register job (limited number of jobs)
+	jobsMutex.Lock()
+	removeExpiredJobs()
+	if jobCount := len(jobs); jobCount >= getProgConfig().Jobs.MaxJobs {
+		jobsMutex.Unlock()
+		cancel()
+		slog.WarnContext(request.Context(), "job request: too many jobs", "jobs", jobCount, "ID", jobRequest.ID)
+		jobResponse.Attributes.Error = newErrorObject(EndpointJobs, ReasonTooManyJobs, fmt.Sprintf("max %d jobs", getProgConfig().Jobs.MaxJobs))
+		buildJobResponse(writer, request, http.StatusTooManyRequests, jobResponse)
+		return
+	}
+	jobs[jobID] = job
+	jobsMutex.Unlock()
+
+	// progress of tile processing is reported to job
+	jobContext = withJobProgress(jobContext, job)
+
+	// GDAL processing jobs are limited by the worker pool of the endpoint class
+	jobContext = withWorkerPool(jobContext, endpoint.Endpoint)
+
+	// build request for endpoint
+	endpointRequest, err := http.NewRequestWithContext(jobContext, http.MethodPost, endpoint.Endpoint.Path, bytes.NewReader(jobRequest.Attributes.Request))
+	if err != nil {
+		slog.ErrorContext(request.Context(), "job request: error building endpoint request", "error", err, "ID", jobRequest.ID)
+		job.finish(jobStatusFailed, http.StatusInternalServerError, nil, nil)
+		jobResponse.Attributes.Error = newErrorObject(EndpointService, ReasonInternalServerError, "")
+		buildJobResponse(writer, request, http.StatusInternalServerError, jobResponse)
+		return
+	}
+	endpointRequest.Header.Set("Content-Type", "application/json")
+	endpointRequest.Header.Set("Accept", "application/json")
+	endpointRequest.RemoteAddr = request.RemoteAddr
+	for _, name := range []string{"X-Forwarded-For", "Forwarded"} {
+		// client identification behind trusted reverse proxies (see getClientIP)
+		for _, value := range request.Header.Values(name) {
+			endpointRequest.Header.Add(name, value)
+		}
+	}
+
+	go runJob(jobContext, job, endpoint, endpointRequest)
+	slog.InfoContext(request.Context(), "job request: job queued", "job", jobID, "endpoint", endpoint.Endpoint.Path, "ID", jobRequest.ID)
+
+	// accepted response
+	job.fillResponse(&jobResponse)
+	jobResponse.Attributes.IsError = false
+	writer.Header().Set("Location", "/v1/jobs/"+jobID)
+	buildJobResponse(writer, request, http.StatusAccepted, jobResponse)
+}
+
+/*
+runJob processes a job in background (waits for a free job slot).
+*/
+func runJob(ctx context.Context, job *Job, endpoint V2Endpoint, endpointRequest *http.Request) {
+	defer job.cancel()
+
+	// wait for free job slot (or cancellation)
+	select {
+	case jobSlots <- struct{}{}:
+		defer func() { <-jobSlots }()
+	case <-ctx.Done():
+		job.finish(jobStatusCanceled, 0, nil, nil)
+		return
+	}
+
+	job.mutex.Lock()
+	job.status = jobStatusRunning
+	job.started = time.Now()
+	job.mutex.Unlock()
+	job.notifyChange()
+
+	// process request with endpoint handler (usage accounting and metadata as for synchronous requests)
+	response := &v2BufferedWriter{header: make(http.Header)}
+	func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				atomic.AddUint64(&RecoveredPanics, 1)
+				slog.ErrorContext(ctx, "job: panic recovered", "job", job.id, "panic", fmt.Sprint(recovered))
+				response = &v2BufferedWriter{header: make(http.Header), status: http.StatusInternalServerError}
+			}
+		}()
+		usageMiddleware(metaMiddleware(endpoint.Handler)).ServeHTTP(response, endpointRequest)
+	}()
+
+	status := jobStatusCompleted
+	switch {
+	case ctx.Err() != nil:
+		status = jobStatusCanceled
+	case response.status >= 400:
+		status = jobStatusFailed
+	}
+	job.finish(status, response.status, response.header, response.body.Bytes())
+	slog.InfoContext(ctx, "job: job finished", "job", job.id, "status", status, "result status", response.status)
+
+	// notify client (callback runs in background, job slot is released)
+	if job.callbackURL != "" && (status == jobStatusCompleted || status == jobStatusFailed) {
+		job.setCallbackState(callbackPending)
+		go notifyJobCallback(context.WithoutCancel(ctx), job)
+	}
+}
+
+/*
+finish sets the final state of a job.
+*/
+func (job *Job) finish(status string, resultStatus int, resultHeader http.Header, result []byte) {
+	job.mutex.Lock()
+	job.status = status
+	job.finished = time.Now()
+	job.resultStatus = resultStatus
+	job.resultHeader = resultHeader
+	job.result = result
+	job.mutex.Unlock()
+	job.notifyChange()
+}
+
+/*
+fillResponse copies the job state into the job response.
+*/
+func (job *Job) fillResponse(jobResponse *JobResponse) {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+	formatTime := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(time.RFC3339)
+	}
+	jobResponse.Attributes.JobID = job.id
+	jobResponse.Attributes.Endpoint = job.endpoint
+	jobResponse.Attributes.Status = job.status
+	jobResponse.Attributes.Created = formatTime(job.created)
+	jobResponse.Attributes.Started = formatTime(job.started)
+	jobResponse.Attributes.Finished = formatTime(job.finished)
+	jobResponse.Attributes.ResultStatus = job.resultStatus
+	if job.status == jobStatusCompleted || job.status == jobStatusFailed {
+		jobResponse.Attributes.ResultURL = "/v1/jobs/" + job.id + "/result"
+	}
+	jobResponse.Attributes.TilesTotal = job.tilesTotal
+	jobResponse.Attributes.TilesDone = job.tilesDone
+	if job.tilesTotal > 0 {
+		jobResponse.Attributes.Progress = fmt.Sprintf("%d/%d tiles processed", job.tilesDone, job.tilesTotal)
+	}
+	jobResponse.Attributes.CallbackURL = job.callbackURL
+	jobResponse.Attributes.Callback = job.callbackState
+}
+
+/*
+removeExpiredJobs removes finished jobs older than the result ttl (caller holds jobsMutex).
+*/
+func removeExpiredJobs() {
+	ttl := time.Duration(getProgConfig().Jobs.ResultTTL) * time.Second
+	for id, job := range jobs {
+		job.mutex.Lock()
+		expired := !job.finished.IsZero() && time.Since(job.finished) > ttl
+		job.mutex.Unlock()
+		if expired {
+			delete(jobs, id)
+		}
+	}
+}
+
+/*
+getJob returns the job for the id of the request path (only jobs of the tenant of the request).
+*/
+func getJob(request *http.Request) (*Job, error) {
+	id := request.PathValue("id")
+
+	jobsMutex.Lock()
+	defer jobsMutex.Unlock()
+	removeExpiredJobs()
+	job, found := jobs[id]
+	if !found || job.tenant != getTenant(request.Context()) {
+		return nil, fmt.Errorf("job [%s] not found (unknown, expired or deleted)", id)
+	}
+	return job, nil
+}
+
+/*
+jobStatusRequest handles 'job status request' (GET /v1/jobs/{id}) from client.
+*/
+func jobStatusRequest(writer http.ResponseWriter, request *http.Request) {
+	var jobResponse = JobResponse{Type: TypeJobResponse, ID: request.PathValue("id")}
+	jobResponse.Attributes.IsError = true
+
+	job, err := getJob(request)
+	if err != nil {
+		slog.WarnContext(request.Context(), "job status request: error getting job", "error", err)
+		jobResponse.Attributes.Error = newErrorObject(EndpointJobs, ReasonJobNotFound, err.Error())
+		buildJobResponse(writer, request, http.StatusNotFound, jobResponse)
+		return
+	}
+
+	job.fillResponse(&jobResponse)
+	jobResponse.Attributes.IsError = false
+	buildJobResponse(writer, request, http.StatusOK, jobResponse)
+}
+
+/*
+jobResultRequest handles 'job result request' (GET /v1/jobs/{id}/result) from client.
+The result is the unchanged response of the endpoint (HTTP status and body).
+*/
+func jobResultRequest(writer http.ResponseWriter, request *http.Request) {
+	var jobResponse = JobResponse{Type: TypeJobResponse, ID: request.PathValue("id")}
+	jobResponse.Attributes.IsError = true
+
+	job, err := getJob(request)
+	if err != nil {
+		slog.WarnContext(request.Context(), "job result request: error getting job", "error", err)
+		jobResponse.Attributes.Error = newErrorObject(EndpointJobs, ReasonJobNotFound, err.Error())
+		buildJobResponse(writer, request, http.StatusNotFound, jobResponse)
+		return
+	}
+
+	job.mutex.Lock()
+	status := job.status
+	resultStatus := job.resultStatus
+	resultHeader := job.resultHeader
+	result := job.result
+	job.mutex.Unlock()
+
+	if status != jobStatusCompleted && status != jobStatusFailed {
+		job.fillResponse(&jobResponse)
+		jobResponse.Attributes.Error = newErrorObject(EndpointJobs, ReasonJobNotCompleted, fmt.Sprintf("job status [%s]", status))
+		buildJobResponse(writer, request, http.StatusConflict, jobResponse)
+		return
+	}
+
+	for _, name := range []string{"Content-Type", "Content-Encoding", "ETag"} {
+		if value := resultHeader.Get(name); value != "" {
+			writer.Header().Set(name, value)
+		}
+	}
+	if resultStatus == 0 {
+		// handler wrote no response (status not recorded)
+		resultStatus = http.StatusOK
+	}
+	writer.WriteHeader(resultStatus)
+	_, err = writer.Write(result)
+	if err != nil {
+		slog.WarnContext(request.Context(), "job result request: error writing result", "error", err, "job", job.id)
+	}
+}
+
+/*
+jobDeleteRequest handles 'job delete request' (DELETE /v1/jobs/{id}) from client.
+Queued or running jobs are canceled, the job and its result are removed.
+*/
+func jobDeleteRequest(writer http.ResponseWriter, request *http.Request) {
+	var jobResponse = JobResponse{Type: TypeJobResponse, ID: request.PathValue("id")}
+	jobResponse.Attributes.IsError = true
+
+	job, err := getJob(request)
+	if err != nil {
+		slog.WarnContext(request.Context(), "job delete request: error getting job", "error", err)
+		jobResponse.Attributes.Error = newErrorObject(EndpointJobs, ReasonJobNotFound, err.Error())
+		buildJobResponse(writer, request, http.StatusNotFound, jobResponse)
+		return
+	}
+
+	job.cancel()
+	jobsMutex.Lock()
+	delete(jobs, job.id)
+	jobsMutex.Unlock()
+
+	job.fillResponse(&jobResponse)
+	jobResponse.Attributes.IsError = false
+	buildJobResponse(writer, request, http.StatusOK, jobResponse)
+}
+
+/*
+verifyJobRequestData verifies 'job' request data and returns the endpoint of the job.
+*/
+func verifyJobRequestData(request *http.Request, jobRequest JobRequest) (V2Endpoint, error) {
+	// verify HTTP header
+	contentType := request.Header.Get("Content-Type")
+	if !strings.HasPrefix(strings.ToLower(contentType), "application/json") {
+		return V2Endpoint{}, fmt.Errorf("unexpected or missing HTTP header field Content-Type, value = [%s], expected 'application/json'", contentType)
+	}
+
+	// verify Type
+	if jobRequest.Type != TypeJobRequest {
+		return V2Endpoint{}, fmt.Errorf("unexpected request Type [%v]", jobRequest.Type)
+	}
+
+	// verify ID
+	if len(jobRequest.ID) > 1024 {
+		return V2Endpoint{}, errors.New("ID must be 0-1024 characters long")
+	}
+
+	// verify endpoint
+	endpoint, found := getJobEndpoint(jobRequest.Attributes.Endpoint)
+	if !found {
+		return V2Endpoint{}, fmt.Errorf("unsupported endpoint [%s] for job", jobRequest.Attributes.Endpoint)
+	}
+
+	// verify request (processed by endpoint)
+	if len(jobRequest.Attributes.Request) == 0 {
+		return V2Endpoint{}, errors.New("request for endpoint must be set")
+	}
+
+	// verify callback (optional)
+	if jobRequest.Attributes.CallbackURL != "" {
+		err := verifyCallback(jobRequest.Attributes.CallbackURL, jobRequest.Attributes.CallbackSecret)
+		if err != nil {
+			return V2Endpoint{}, err
+		}
+	}
+
+	return endpoint, nil
+}
+
+/*
+buildJobResponse builds HTTP responses with specified status and body.
+*/
+func buildJobResponse(writer http.ResponseWriter, request *http.Request, httpStatus int, jobResponse JobResponse) {
+	// response metadata (versions, processing duration, cache hit)
+	jobResponse.Meta = newResponseMeta(request.Context())
+
+	// encode (streaming) and send response
+	streamJSONResponse(writer, request, httpStatus, jobResponse, false)
+}
@@ -0,0 +1,592 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// legend margins (pixels, space for labels at both ends of the color bar)
+const (
+	legendMargin      = 10
+	legendBarMaxWidth = 24
+	legendLabelGap    = 14 // min distance between labels (overlapping labels are skipped)
+)
+
+// legendColorNames are the color names supported by gdaldem color-relief (instead of R G B values)
+var legendColorNames = map[string]color.RGBA{
+	"white":   {255, 255, 255, 255},
+	"black":   {0, 0, 0, 255},
+	"red":     {255, 0, 0, 255},
+	"green":   {0, 255, 0, 255},
+	"blue":    {0, 0, 255, 255},
+	"yellow":  {255, 255, 0, 255},
+	"magenta": {255, 0, 255, 255},
+	"fuchsia": {255, 0, 255, 255},
+	"cyan":    {0, 255, 255, 255},
+	"aqua":    {0, 255, 255, 255},
+	"grey":    {190, 190, 190, 255},
+	"gray":    {190, 190, 190, 255},
+	"orange":  {255, 165, 0, 255},
+	"brown":   {165, 42, 42, 255},
+	"purple":  {160, 32, 240, 255},
+	"violet":  {238, 130, 238, 255},
+	"indigo":  {75, 0, 130, 255},
+}
+
+/*
+legendRequest handles 'legend request' from client: renders the legend (color bar with labels) for a color text file
+or a named color ramp, so web clients can show legends consistent with the color mapping of the service.
+*/
+func legendRequest(writer http.ResponseWriter, request *http.Request) {
+	var legendResponse = LegendResponse{Type: TypeLegendResponse, ID: "unknown"}
+	legendResponse.Attributes.IsError = true
+
+	// statistics
+	atomic.AddUint64(&LegendRequests, 1)
+
+	// limit overall request body size
+	request.Body = http.MaxBytesReader(writer, request.Body, MaxLegendRequestBodySize)
+
+	// read request
+	bodyData, err := io.ReadAll(request.Body)
+	if err != nil {
+		// check specifically for the error returned by MaxBytesReader
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			slog.WarnContext(request.Context(), "legend request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
+			legendResponse.Attributes.Error = newErrorObject(EndpointLegend, ReasonRequestBodyTooLarge, fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit))
+			buildLegendResponse(writer, request, http.StatusRequestEntityTooLarge, legendResponse)
+		} else {
+			// handle other read errors
+			slog.WarnContext(request.Context(), "legend request: error reading request body", "error", err, "ID", "unknown")
+			legendResponse.Attributes.Error = newErrorObject(EndpointLegend, ReasonReadingRequestBody, err.Error())
+			buildLegendResponse(writer, request, http.StatusBadRequest, legendResponse)
+		}
+		return
+	}
+
+	// unmarshal request
+	legendRequest := LegendRequest{}
+	err = json.Unmarshal(bodyData, &legendRequest)
+	if err != nil {
+		slog.WarnContext(request.Context(), "legend request: error unmarshaling request body", "error", err, "ID", "unknown")
+		legendResponse.Attributes.Error = newErrorObject(EndpointLegend, ReasonUnmarshalingRequestBody, err.Error())
+		buildLegendResponse(writer, request, http.StatusBadRequest, legendResponse)
+		return
+	}
+
+	// set defaults
+	if legendRequest.Attributes.ColoringAlgorithm == "" {
+		legendRequest.Attributes.ColoringAlgorithm = "interpolation"
+		legendResponse.Attributes.Warnings = append(legendResponse.Attributes.Warnings, "ColoringAlgorithm not set, default 'interpolation' applied")
+	}
+	if legendRequest.Attributes.Orientation == "" {
+		legendRequest.Attributes.Orientation = "vertical"
+	}
+	if legendRequest.Attributes.OutputFormat == "" {
+		legendRequest.Attributes.OutputFormat = "png"
+	}
+	if legendRequest.Attributes.Width == 0 {
+		legendRequest.Attributes.Width = 100
+		if legendRequest.Attributes.Orientation == "horizontal" {
+			legendRequest.Attributes.Width = 400
+		}
+	}
+	if legendRequest.Attributes.Height == 0 {
+		legendRequest.Attributes.Height = 300
+		if legendRequest.Attributes.Orientation == "horizontal" {
+			legendRequest.Attributes.Height = 70
+		}
+	}
+
+	// named color table (stored server-side) instead of color text file content
+	if legendRequest.Attributes.ColorTableName != "" {
+		legendRequest.Attributes.ColorTextFileContent, err = resolveColorTable(request.Context(), legendRequest.Attributes.ColorTableName, legendRequest.Attributes.ColorTextFileContent)
+		if err != nil {
+			slog.WarnContext(request.Context(), "legend request: error resolving color table", "error", err, "name", legendRequest.Attributes.ColorTableName, "ID", legendRequest.ID)
+			legendResponse.ID = legendRequest.ID
+			legendResponse.Attributes.ColorTableName = legendRequest.Attributes.ColorTableName
+			legendResponse.Attributes.Error = newErrorObject(EndpointLegend, ReasonVerifyingRequestData, err.Error())
+			buildLegendResponse(writer, request, http.StatusBadRequest, legendResponse)
+			return
+		}
+	}
+
+	// copy request parameters into response
+	legendResponse.ID = legendRequest.ID
+	legendResponse.Attributes.ColorTextFileContent = legendRequest.Attributes.ColorTextFileContent
+	legendResponse.Attributes.ColorTableName = legendRequest.Attributes.ColorTableName
+	legendResponse.Attributes.ColorRamp = legendRequest.Attributes.ColorRamp
+	legendResponse.Attributes.Minimum = legendRequest.Attributes.Minimum
+	legendResponse.Attributes.Maximum = legendRequest.Attributes.Maximum
+	legendResponse.Attributes.ColoringAlgorithm = legendRequest.Attributes.ColoringAlgorithm
+	legendResponse.Attributes.Unit = legendRequest.Attributes.Unit
+	legendResponse.Attributes.Orientation = legendRequest.Attributes.Orientation
+	legendResponse.Attributes.OutputFormat = legendRequest.Attributes.OutputFormat
+
+	// verify request data
+	err = verifyLegendRequestData(request, legendRequest)
+	if err != nil {
+		slog.WarnContext(request.Context(), "legend request: error verifying request data", "error", err, "ID", legendRequest.ID)
+		legendResponse.Attributes.Error = newErrorObject(EndpointLegend, ReasonVerifyingRequestData, err.Error())
+		buildLegendResponse(writer, request, http.StatusBadRequest, legendResponse)
+		return
+	}
+
+	// conditional request: unchanged response (same parameters) needs no processing
+	etag := buildETag("legend", legendRequest, nil)
+	if checkNotModified(writer, request, etag) {
+		return
+	}
+
+	// color text file content (named color ramp stretched between minimum and maximum)
+	attributes := legendRequest.Attributes
+	colorTextFileContent := attributes.ColorTextFileContent
+	if attributes.ColorRamp != "" {
+		colorTextFileContent, err = buildStretchedColorTextFileContent(strings.ToLower(attributes.ColorRamp), attributes.Minimum, attributes.Maximum)
+		if err != nil {
+			slog.WarnContext(request.Context(), "legend request: error building color text file for color ramp", "error", err, "ID", legendRequest.ID)
+			legendResponse.Attributes.Error = newErrorObject(EndpointLegend, ReasonVerifyingRequestData, err.Error())
+			buildLegendResponse(writer, request, http.StatusBadRequest, legendResponse)
+			return
+		}
+	}
+
+	// render legend
+	legend, err := renderLegend(colorTextFileContent, attributes.Minimum, attributes.Maximum, attributes.ColoringAlgorithm, attributes.Unit,
+		attributes.Orientation, attributes.OutputFormat, attributes.Width, attributes.Height)
+	if err != nil {
+		slog.WarnContext(request.Context(), "legend request: error generating legend", "error", err, "ID", legendRequest.ID)
+		legendResponse.Attributes.Error = newErrorObject(EndpointLegend, ReasonGeneratingObject, err.Error())
+		buildLegendResponse(writer, request, http.StatusBadRequest, legendResponse)
+		return
+	}
+	legendResponse.Attributes.Legend = legend
+
+	// success response
+	legendResponse.Attributes.IsError = false
+	setETag(writer, etag)
+	buildLegendResponse(writer, request, http.StatusOK, legendResponse)
+}
+
+/*
+verifyLegendRequestData verifies 'legend' request data.
+*/
+func verifyLegendRequestData(request *http.Request, legendRequest LegendRequest) error {
+	// verify HTTP header
+	contentType := request.Header.Get("Content-Type")
+	if !strings.HasPrefix(strings.ToLower(contentType), "application/json") {
+		return fmt.Errorf("unexpected or missing HTTP header field Content-Type, value = [%s], expected 'application/json'", contentType)
+	}
+
+	// verify Type
+	if legendRequest.Type != TypeLegendRequest {
+		return fmt.Errorf("unexpected request Type [%v]", legendRequest.Type)
+	}
+
+	// verify ID
+	if len(legendRequest.ID) > 1024 {
+		return errors.New("ID must be 0-1024 characters long")
+	}
+
+	// verify color mapping (either color text file or named color ramp)
+	attributes := legendRequest.Attributes
+	if attributes.ColorRamp != "" {
+		if len(attributes.ColorTextFileContent) > 0 {
+			return errors.New("either ColorTextFileContent or ColorRamp can be set")
+		}
+		if _, found := colorRamps[strings.ToLower(attributes.ColorRamp)]; !found {
+			return fmt.Errorf("unsupported color ramp (valid: %s)", strings.Join(getColorRampNames(), ", "))
+		}
+		if attributes.Minimum >= attributes.Maximum {
+			return errors.New("color ramp requires value range (Minimum must be less than Maximum)")
+		}
+	} else {
+		err := verifyColorTextFileContent(attributes.ColorTextFileContent)
+		if err != nil {
+			return err
+		}
+	}
+
+	// verify ColoringAlgorithm
+	if !(attributes.ColoringAlgorithm == "interpolation" || attributes.ColoringAlgorithm == "rounding") {
+		return errors.New("unsupported coloring algorithm (not 'interpolation' or 'rounding')")
+	}
+
+	// verify Unit
+	if utf8.RuneCountInString(attributes.Unit) > 8 {
+		return errors.New("unit must be 0-8 characters long")
+	}
+
+	// verify Orientation
+	if !(attributes.Orientation == "vertical" || attributes.Orientation == "horizontal") {
+		return errors.New("unsupported orientation (not 'vertical' or 'horizontal')")
+	}
+
+	// verify OutputFormat
+	if !(attributes.OutputFormat == "png" || attributes.OutputFormat == "svg") {
+		return errors.New("unsupported output format (not 'png' or 'svg')")
+	}
+
+	// verify size
+	if attributes.Width < 50 || attributes.Width > 2000 {
+		return errors.New("width must be between 50 and 2000 pixels")
+	}
+	if attributes.Height < 50 || attributes.Height > 2000 {
+		return errors.New("height must be between 50 and 2000 pixels")
+	}
+
+	return nil
+}
+
+/*
+buildLegendResponse builds HTTP responses with specified status and body.
+*/
+func buildLegendResponse(writer http.ResponseWriter, request *http.Request, httpStatus int, legendResponse LegendResponse) {
+	// response metadata (versions, processing duration, cache hit)
+	legendResponse.Meta = newResponseMeta(request.Context())
+
+	// encode (streaming) and send response
+	streamJSONResponse(writer, request, httpStatus, legendResponse, false)
+}
+
+/*
+parseLegendEntries parses the color entries of a color text file (gdaldem color-relief format: value R G B [A] or
+value colorname, separated by space, tab, comma or colon). Percentage values are resolved to the value range
+(minimum, maximum). Comments, empty lines and nodata entries (nv) are skipped. The entries are sorted by value.
+*/
+func parseLegendEntries(colorTextFileContent []string, minimum float64, maximum float64) ([]LegendEntry, error) {
+	var entries []LegendEntry
+
+	for _, line := range colorTextFileContent {
+		fields := strings.FieldsFunc(line, func(r rune) bool {
+			return r == ' ' || r == '\t' || r == ',' || r == ':'
+		})
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") || strings.EqualFold(fields[0], "nv") {
+			continue
+		}
+
+		// value (absolute or percentage of value range)
+		var entry LegendEntry
+		var err error
+		if percentage, found := strings.CutSuffix(fields[0], "%"); found {
+			if minimum >= maximum {
+				return nil, fmt.Errorf("percentage entry [%s] requires value range (Minimum must be less than Maximum)", line)
+			}
+			entry.Value, err = strconv.ParseFloat(percentage, 64)
+			entry.Value = minimum + entry.Value/100.0*(maximum-minimum)
+		} else {
+			entry.Value, err = strconv.ParseFloat(fields[0], 64)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value in color entry [%s]", line)
+		}
+
+		// color (R G B [A] or color name)
+		switch len(fields) {
+		case 2:
+			namedColor, found := legendColorNames[strings.ToLower(fields[1])]
+			if !found {
+				return nil, fmt.Errorf("unknown color name in color entry [%s]", line)
+			}
+			entry.Red, entry.Green, entry.Blue, entry.Alpha = namedColor.R, namedColor.G, namedColor.B, namedColor.A
+		case 4, 5:
+			components := []uint8{0, 0, 0, 255}
+			for i, field := range fields[1:] {
+				component, err := strconv.ParseUint(field, 10, 8)
+				if err != nil {
+					return nil, fmt.Errorf("invalid color component in color entry [%s]", line)
+				}
+				components[i] = uint8(component)
+			}
+			entry.Red, entry.Green, entry.Blue, entry.Alpha = components[0], components[1], components[2], components[3]
+		default:
+			return nil, fmt.Errorf("invalid color entry [%s] (expected: value R G B [A] or value colorname)", line)
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(entries) == 0 {
+		return nil, errors.New("no color entries in color text file")
+	}
+	slices.SortStableFunc(entries, func(a, b LegendEntry) int {
+		return compareFloat(a.Value, b.Value)
+	})
+
+	return entries, nil
+}
+
+/*
+compareFloat compares two float values (for sorting).
+*/
+func compareFloat(a float64, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+/*
+legendColor returns the color for a value like gdaldem color-relief: linear interpolation between the surrounding
+entries (interpolation) or color of the nearest entry (rounding).
+*/
+func legendColor(entries []LegendEntry, value float64, coloringAlgorithm string) color.RGBA {
+	toRGBA := func(entry LegendEntry) color.RGBA {
+		return color.RGBA{entry.Red, entry.Green, entry.Blue, entry.Alpha}
+	}
+
+	if value <= entries[0].Value {
+		return toRGBA(entries[0])
+	}
+	for i := 1; i < len(entries); i++ {
+		lower, upper := entries[i-1], entries[i]
+		if value > upper.Value {
+			continue
+		}
+		if coloringAlgorithm == "rounding" {
+			if value-lower.Value < upper.Value-value {
+				return toRGBA(lower)
+			}
+			return toRGBA(upper)
+		}
+		ratio := (value - lower.Value) / (upper.Value - lower.Value)
+		mix := func(a, b uint8) uint8 {
+			return uint8(math.Round(float64(a) + ratio*(float64(b)-float64(a))))
+		}
+		return color.RGBA{mix(lower.Red, upper.Red), mix(lower.Green, upper.Green), mix(lower.Blue, upper.Blue), mix(lower.Alpha, upper.Alpha)}
+	}
+	return toRGBA(entries[len(entries)-1])
+}
+
+/*
+legendLabel formats the label of a legend entry (value rounded to 2 decimal places, unit appended).
+*/
+func legendLabel(value float64, unit string) string {
+	return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64) + unit
+}
+
+// legendLayout represents the geometry of a legend (color bar and labels).
+type legendLayout struct {
+	width, height int
+	horizontal    bool
+	minimum       float64
+	maximum       float64
+	barStart      float64 // pixel position of minimum (x for horizontal, y for vertical)
+	barEnd        float64 // pixel position of maximum
+	barOffset     int     // pixel position of bar across its length (y for horizontal, x for vertical)
+	barWidth      int     // thickness of bar
+}
+
+/*
+position returns the pixel position of a value along the color bar.
+*/
+func (layout legendLayout) position(value float64) float64 {
+	if layout.maximum <= layout.minimum {
+		return (layout.barStart + layout.barEnd) / 2
+	}
+	return layout.barStart + (value-layout.minimum)/(layout.maximum-layout.minimum)*(layout.barEnd-layout.barStart)
+}
+
+/*
+renderLegend renders the legend (color bar with labels at the color entries) for a color text file as PNG or SVG.
+Vertical legends show the highest value at the top.
+*/
+func renderLegend(colorTextFileContent []string, minimum float64, maximum float64, coloringAlgorithm string, unit string,
+	orientation string, outputFormat string, width int, height int) (Legend, error) {
+	legend := Legend{DataFormat: outputFormat, Width: width, Height: height}
+
+	entries, err := parseLegendEntries(colorTextFileContent, minimum, maximum)
+	if err != nil {
+		return legend, err
+	}
+	legend.Entries = entries
+
+	layout := legendLayout{width: width, height: height, horizontal: orientation == "horizontal",
+		minimum: entries[0].Value, maximum: entries[len(entries)-1].Value}
+	if layout.horizontal {
+		layout.barWidth = min(legendBarMaxWidth, height/3)
+		layout.barOffset = legendMargin
+		layout.barStart, layout.barEnd = float64(legendMargin*2), float64(width-legendMargin*2)
+	} else {
+		layout.barWidth = min(legendBarMaxWidth, width/3)
+		layout.barOffset = legendMargin
+		layout.barStart, layout.barEnd = float64(height-legendMargin), float64(legendMargin)
+	}
+
+	// labels (skip labels overlapping the previous label)
+	var labelEntries []LegendEntry
+	lastPosition := math.Inf(-1)
+	for _, entry := range entries {
+		position := layout.position(entry.Value)
+		if math.Abs(position-lastPosition) < legendLabelGap {
+			continue
+		}
+		labelEntries = append(labelEntries, entry)
+		lastPosition = position
+	}
+
+	switch outputFormat {
+	case "svg":
+		legend.Data = renderLegendSVG(entries, labelEntries, layout, coloringAlgorithm, unit)
+	case "png":
+		legend.Data, err = renderLegendPNG(entries, labelEntries, layout, coloringAlgorithm, unit)
+		if err != nil {
+			return legend, fmt.Errorf("error [%w] at renderLegendPNG()", err)
+		}
+	default:
+		return legend, fmt.Errorf("unsupported output format [%s]", outputFormat)
+	}
+
+	return legend, nil
+}
+
+/*
+renderLegendSVG renders the legend as SVG document (color bar as gradient or as color steps).
+*/
+func renderLegendSVG(entries []LegendEntry, labelEntries []LegendEntry, layout legendLayout, coloringAlgorithm string, unit string) []byte {
+	var svg strings.Builder
+	rgb := func(c color.RGBA) string { return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B) }
+	opacity := func(c color.RGBA) string { return strconv.FormatFloat(float64(c.A)/255.0, 'f', 3, 64) }
+
+	fmt.Fprintf(&svg, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" font-family=\"sans-serif\" font-size=\"11\">\n",
+		layout.width, layout.height, layout.width, layout.height)
+	fmt.Fprintf(&svg, "<rect width=\"100%%\" height=\"100%%\" fill=\"%s\"/>\n", rgb(profileChartColorBackground))
+
+	// color bar (from minimum to maximum)
+	barLength := math.Abs(layout.barEnd - layout.barStart)
+	barRect := func(from float64, to float64) string {
+		start, end := math.Min(from, to), math.Max(from, to)
+		if layout.horizontal {
+			return fmt.Sprintf("x=\"%.1f\" y=\"%d\" width=\"%.1f\" height=\"%d\"", start, layout.barOffset, end-start, layout.barWidth)
+		}
+		return fmt.Sprintf("x=\"%d\" y=\"%.1f\" width=\"%d\" height=\"%.1f\"", layout.barOffset, start, layout.barWidth, end-start)
+	}
+	if coloringAlgorithm == "rounding" || len(entries) == 1 || barLength == 0 {
+		// color steps (boundaries halfway between entries)
+		for i, entry := range entries {
+			from, to := layout.barStart, layout.barEnd
+			if i > 0 {
+				from = layout.position((entries[i-1].Value + entry.Value) / 2)
+			}
+			if i < len(entries)-1 {
+				to = layout.position((entry.Value + entries[i+1].Value) / 2)
+			}
+			c := legendColor(entries, entry.Value, coloringAlgorithm)
+			fmt.Fprintf(&svg, "<rect %s fill=\"%s\" fill-opacity=\"%s\"/>\n", barRect(from, to), rgb(c), opacity(c))
+		}
+	} else {
+		// gradient (stops at entries)
+		x2, y1 := "100%", "0%"
+		if !layout.horizontal {
+			x2, y1 = "0%", "100%"
+		}
+		fmt.Fprintf(&svg, "<defs><linearGradient id=\"legend\" x1=\"0%%\" y1=\"%s\" x2=\"%s\" y2=\"0%%\">\n", y1, x2)
+		for _, entry := range entries {
+			c := legendColor(entries, entry.Value, coloringAlgorithm)
+			offset := (entry.Value - layout.minimum) / (layout.maximum - layout.minimum) * 100
+			fmt.Fprintf(&svg, "<stop offset=\"%.2f%%\" stop-color=\"%s\" stop-opacity=\"%s\"/>\n", offset, rgb(c), opacity(c))
+		}
+		fmt.Fprintf(&svg, "</linearGradient></defs>\n")
+		fmt.Fprintf(&svg, "<rect %s fill=\"url(#legend)\"/>\n", barRect(layout.barStart, layout.barEnd))
+	}
+	fmt.Fprintf(&svg, "<rect %s fill=\"none\" stroke=\"%s\"/>\n", barRect(layout.barStart, layout.barEnd), rgb(profileChartColorAxis))
+
+	// ticks and labels
+	for _, entry := range labelEntries {
+		position := layout.position(entry.Value)
+		label := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(legendLabel(entry.Value, unit))
+		if layout.horizontal {
+			bottom := layout.barOffset + layout.barWidth
+			fmt.Fprintf(&svg, "<line x1=\"%.1f\" y1=\"%d\" x2=\"%.1f\" y2=\"%d\" stroke=\"%s\"/>\n", position, bottom, position, bottom+4, rgb(profileChartColorAxis))
+			fmt.Fprintf(&svg, "<text x=\"%.1f\" y=\"%d\" text-anchor=\"middle\" fill=\"%s\">%s</text>\n", position, bottom+16, rgb(profileChartColorAxis), label)
+		} else {
+			right := layout.barOffset + layout.barWidth
+			fmt.Fprintf(&svg, "<line x1=\"%d\" y1=\"%.1f\" x2=\"%d\" y2=\"%.1f\" stroke=\"%s\"/>\n", right, position, right+4, position, rgb(profileChartColorAxis))
+			fmt.Fprintf(&svg, "<text x=\"%d\" y=\"%.1f\" dominant-baseline=\"middle\" fill=\"%s\">%s</text>\n", right+6, position, rgb(profileChartColorAxis), label)
+		}
+	}
+	fmt.Fprintf(&svg, "</svg>\n")
+
+	return []byte(svg.String())
+}
+
+/*
+renderLegendPNG renders the legend as PNG image (colors blended onto background, labels with built-in bitmap font).
+*/
+func renderLegendPNG(entries []LegendEntry, labelEntries []LegendEntry, layout legendLayout, coloringAlgorithm string, unit string) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, layout.width, layout.height))
+	background := profileChartColorBackground
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = background.R, background.G, background.B, 255
+	}
+
+	// color bar (one line per pixel along the bar)
+	start, end := int(math.Round(math.Min(layout.barStart, layout.barEnd))), int(math.Round(math.Max(layout.barStart, layout.barEnd)))
+	for pixel := start; pixel <= end; pixel++ {
+		value := layout.minimum
+		if layout.barEnd != layout.barStart {
+			value = layout.minimum + (float64(pixel)-layout.barStart)/(layout.barEnd-layout.barStart)*(layout.maximum-layout.minimum)
+		}
+		c := legendColor(entries, value, coloringAlgorithm)
+		blend := func(component uint8, backgroundComponent uint8) uint8 {
+			return uint8(math.Round((float64(component)*float64(c.A) + float64(backgroundComponent)*float64(255-c.A)) / 255.0))
+		}
+		blended := color.RGBA{blend(c.R, background.R), blend(c.G, background.G), blend(c.B, background.B), 255}
+		if layout.horizontal {
+			drawLine(img, pixel, layout.barOffset, pixel, layout.barOffset+layout.barWidth-1, blended)
+		} else {
+			drawLine(img, layout.barOffset, pixel, layout.barOffset+layout.barWidth-1, pixel, blended)
+		}
+	}
+
+	// frame, ticks and labels
+	if layout.horizontal {
+		top, bottom := layout.barOffset, layout.barOffset+layout.barWidth
+		drawLine(img, start, top, end, top, profileChartColorAxis)
+		drawLine(img, start, bottom, end, bottom, profileChartColorAxis)
+		drawLine(img, start, top, start, bottom, profileChartColorAxis)
+		drawLine(img, end, top, end, bottom, profileChartColorAxis)
+		for _, entry := range labelEntries {
+			x := int(math.Round(layout.position(entry.Value)))
+			drawLine(img, x, bottom, x, bottom+4, profileChartColorAxis)
+			label := legendLabel(entry.Value, unit)
+			labelX := min(max(0, x-textWidth(label)/2), layout.width-textWidth(label))
+			drawText(img, labelX, bottom+8, label, profileChartColorAxis)
+		}
+	} else {
+		left, right := layout.barOffset, layout.barOffset+layout.barWidth
+		drawLine(img, left, start, right, start, profileChartColorAxis)
+		drawLine(img, left, end, right, end, profileChartColorAxis)
+		drawLine(img, left, start, left, end, profileChartColorAxis)
+		drawLine(img, right, start, right, end, profileChartColorAxis)
+		for _, entry := range labelEntries {
+			y := int(math.Round(layout.position(entry.Value)))
+			drawLine(img, right, y, right+4, y, profileChartColorAxis)
+			drawText(img, right+7, y-3, legendLabel(entry.Value, unit), profileChartColorAxis)
+		}
+	}
+
+	var buffer bytes.Buffer
+	err := png.Encode(&buffer, img)
+	if err != nil {
+		return nil, fmt.Errorf("error [%w] at png.Encode()", err)
+	}
+	return buffer.Bytes(), nil
+}
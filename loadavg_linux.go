@@ -0,0 +1,18 @@
+package main
+
+import (
+	"syscall"
+)
+
+/*
+loadAverage returns the 1-minute load average of the system.
+*/
+func loadAverage() (float64, bool) {
+	var info syscall.Sysinfo_t
+	err := syscall.Sysinfo(&info)
+	if err != nil {
+		return 0, false
+	}
+	// fixed-point value (SI_LOAD_SHIFT = 16)
+	return float64(info.Loads[0]) / 65536.0, true
+}
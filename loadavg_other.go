@@ -0,0 +1,10 @@
+//go:build !linux
+
+package main
+
+/*
+loadAverage returns false (load average is only determined on linux).
+*/
+func loadAverage() (float64, bool) {
+	return 0, false
+}
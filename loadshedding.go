@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime"
+	"slices"
+	"strconv"
+	"sync/atomic"
+)
+
+// loadSheddingEndpoints lists the expensive endpoints (GDAL raster processing) rejected under load
+var loadSheddingEndpoints = []*ErrorEndpoint{EndpointContours, EndpointHillshade, EndpointSlope, EndpointAspect, EndpointTPI,
+	EndpointTRI, EndpointRoughness, EndpointColorRelief, EndpointHistogram, EndpointVisualize, EndpointAspectRose,
+	EndpointElevationRange, EndpointContourLine, EndpointElevationMatrix}
+
+/*
+isLoadSheddingPath reports whether the request path (v1 or v2) belongs to an expensive endpoint.
+*/
+func isLoadSheddingPath(path string) bool {
+	endpoint := getEndpointByPath(path)
+	return endpoint != nil && slices.Contains(loadSheddingEndpoints, endpoint)
+}
+
+/*
+getOverloadReason checks the configured thresholds (load average per CPU, GDAL jobs waiting for a free slot)
+and returns the reason of an overload (empty if not overloaded).
+*/
+func getOverloadReason() string {
+	config := getProgConfig().LoadShedding
+
+	if config.MaxQueuedJobs > 0 {
+		waiting := gdalJobQueue.queueDepth()
+		if waiting >= int64(config.MaxQueuedJobs) {
+			return fmt.Sprintf("%d processing jobs waiting (threshold %d)", waiting, config.MaxQueuedJobs)
+		}
+	}
+
+	if config.MaxLoadPerCPU > 0 {
+		load, ok := loadAverage()
+		if ok {
+			loadPerCPU := load / float64(runtime.NumCPU())
+			if loadPerCPU >= config.MaxLoadPerCPU {
+				return fmt.Sprintf("load per CPU %.2f (threshold %.2f)", loadPerCPU, config.MaxLoadPerCPU)
+			}
+		}
+	}
+
+	return ""
+}
+
+/*
+loadSheddingMiddleware rejects requests for expensive raster endpoints with '503 Service Unavailable' (and Retry-After)
+while the server is overloaded. Cheap lookups (e.g. point, gpx) are still served, which protects interactive users.
+*/
+func loadSheddingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		config := getProgConfig().LoadShedding
+		if !config.Enabled || request.Method == http.MethodOptions || !isLoadSheddingPath(request.URL.Path) {
+			next.ServeHTTP(writer, request)
+			return
+		}
+
+		reason := getOverloadReason()
+		if reason == "" {
+			next.ServeHTTP(writer, request)
+			return
+		}
+
+		atomic.AddUint64(&LoadSheddingRejections, 1)
+		slog.DebugContext(request.Context(), "request rejected by load shedding", "reason", reason, "path", request.URL.Path)
+		writer.Header().Set("Retry-After", strconv.Itoa(config.RetryAfter))
+		writer.Header().Set("Content-Type", TextPlainMediaType)
+		writer.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(writer, "server overloaded (%s), expensive requests are temporarily rejected, retry after %d seconds", reason, config.RetryAfter)
+	})
+}
@@ -22,6 +22,7 @@ Releases:
 - v1.7.2 - 2025-08-30: error handling improved
 - v1.7.3 - 2025-08-31: robustness in calculateWGS84BoundingBox() improved
 - v1.8.9 - 2025-09-04: added: elevation profile
+- v1.9.0 - 2026-10-16: added: TLS modes none/acme, gRPC API, API v2, async jobs, JWT auth, config reload, admin listener, pkg/dtm
 
 Author:
 - Klaus Tockloth
@@ -33,9 +34,14 @@ Contact:
 - klaus.tockloth@googlemail.com
 
 Remarks:
-- Usage 'point' API : see script 'query-elevation-point.sh'
-- Usage 'gpx' API : see script 'query-elevation-gpx.sh'
+- Usage 'point' API : dtm-elevation-service point --lon 7.6261 --lat 51.9607 (or --local with local tile repository)
+- Usage 'gpx' API : dtm-elevation-service gpx track.gpx (writes track-dtm.gpx)
+- Other APIs : dtm-elevation-service request --endpoint /v1/hillshade --dir out request.json (saves objects with data)
+- API description (OpenAPI 3.1): GET /openapi.json
+- Capability discovery (endpoints, output formats, coverage, limits, data actuality): GET /v1/capabilities
+- Interactive web UI (demos, QA of new tile deliveries): GET /ui/ (if enabled by configuration)
 - Single Tile Caching adds complexity but can improve the processing of large GPX files.
+- Benchmark mode (e.g. regression checks): dtm-elevation-service -bench https://localhost:14444 -bench-insecure
 
 TODOs:
 - Validieren: Datenbezogene Fehler nur im Debug-Modus loggen.
@@ -53,28 +59,30 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"strings"
-	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/airbusgeo/godal"
+	"google.golang.org/grpc"
 	"gopkg.in/natefinch/lumberjack.v2"
-	"gopkg.in/yaml.v3"
 )
 
 // general program info
 var (
 	progName      = strings.TrimSuffix(filepath.Base(os.Args[0]), filepath.Ext(filepath.Base(os.Args[0])))
-	progVersion   = "v1.8.0"
-	progDate      = "2025-09-04"
+	progVersion   = "v1.9.0"
+	progDate      = "2026-10-16"
 	progPurpose   = "dtm elevation service"
 	progInfo      = "Service for determining elevation information based on accurate DTM (Digital Terrain Model) data."
 	progCopyright = "© 2025 | Klaus Tockloth"
@@ -82,19 +90,156 @@ var (
 
 // ProgConfig defines program configuration
 type ProgConfig struct {
-	ListenAddress       string   `yaml:"ListenAddress"`
-	ServerCertificate   string   `yaml:"ServerCertificate"`
-	ServerKey           string   `yaml:"ServerKey"`
-	TrustedIssuers      []string `yaml:"TrustedIssuers"`
+	ListenAddress     string   `yaml:"ListenAddress"`
+	ServerCertificate string   `yaml:"ServerCertificate"`
+	ServerKey         string   `yaml:"ServerKey"`
+	TLSMode           string   `yaml:"TLSMode"`
+	TrustedIssuers    []string `yaml:"TrustedIssuers"`
+	CORS              struct {
+		Default   CORSPolicy   `yaml:"Default"`
+		Endpoints []CORSPolicy `yaml:"Endpoints"`
+	} `yaml:"CORS"`
+	ACME struct {
+		CacheDirectory    string   `yaml:"CacheDirectory"`
+		Hosts             []string `yaml:"Hosts"`
+		Email             string   `yaml:"Email"`
+		HTTPListenAddress string   `yaml:"HTTPListenAddress"`
+	} `yaml:"ACME"`
+	Authentication struct {
+		Audience     string   `yaml:"Audience"`
+		JWKSCacheTTL int      `yaml:"JWKSCacheTTL"`
+		PublicPaths  []string `yaml:"PublicPaths"`
+		Rules        []struct {
+			Path   string   `yaml:"Path"`
+			Scopes []string `yaml:"Scopes"`
+		} `yaml:"Rules"`
+	} `yaml:"Authentication"`
 	ShutdownGracePeriod int      `yaml:"ShutdownGracePeriod"`
 	LogDirectory        string   `yaml:"LogDirectory"`
 	LogLevel            string   `yaml:"LogLevel"`
-	TileRepositories    []string `yaml:"TileRepositories"`
+	AccessLog           bool     `yaml:"AccessLog"`
+	TrustedProxies      []string `yaml:"TrustedProxies"`
+	IPFilter            struct {
+		AllowedNetworks []string `yaml:"AllowedNetworks"`
+		DeniedNetworks  []string `yaml:"DeniedNetworks"`
+	} `yaml:"IPFilter"`
+	AbuseProtection struct {
+		Enabled        bool `yaml:"Enabled"`
+		Window         int  `yaml:"Window"`
+		MaxViolations  int  `yaml:"MaxViolations"`
+		BanDuration    int  `yaml:"BanDuration"`
+		MaxBanDuration int  `yaml:"MaxBanDuration"`
+	} `yaml:"AbuseProtection"`
+	LoadShedding struct {
+		Enabled       bool    `yaml:"Enabled"`
+		MaxLoadPerCPU float64 `yaml:"MaxLoadPerCPU"`
+		MaxQueuedJobs int     `yaml:"MaxQueuedJobs"`
+		RetryAfter    int     `yaml:"RetryAfter"`
+	} `yaml:"LoadShedding"`
+	WorkerPools struct {
+		Points   WorkerPoolConfig `yaml:"Points"`
+		GPX      WorkerPoolConfig `yaml:"GPX"`
+		Raster   WorkerPoolConfig `yaml:"Raster"`
+		Contours WorkerPoolConfig `yaml:"Contours"`
+	} `yaml:"WorkerPools"`
+	Accounting struct {
+		Enabled   bool   `yaml:"Enabled"`
+		Directory string `yaml:"Directory"`
+	} `yaml:"Accounting"`
+	TileRepositories      []string `yaml:"TileRepositories"`
+	DisabledSources       []string `yaml:"DisabledSources"`
+	IndentJSON            bool     `yaml:"IndentJSON"`
+	CompressedDataFormats []string `yaml:"CompressedDataFormats"`
+	TempDirectory         string   `yaml:"TempDirectory"`
+	TempMinFreeSpace      int      `yaml:"TempMinFreeSpace"`
+	ResponseCache         struct {
+		MaxEntries int `yaml:"MaxEntries"`
+		MaxSize    int `yaml:"MaxSize"`
+		TTL        int `yaml:"TTL"`
+	} `yaml:"ResponseCache"`
+	TileCache struct {
+		MaxTiles int `yaml:"MaxTiles"`
+		TTL      int `yaml:"TTL"`
+	} `yaml:"TileCache"`
+	GDALJobQueue struct {
+		MaxParallelJobs int `yaml:"MaxParallelJobs"`
+		MaxQueueWait    int `yaml:"MaxQueueWait"`
+		RetryAfter      int `yaml:"RetryAfter"`
+	} `yaml:"GDALJobQueue"`
+	Geocoder struct {
+		Provider  string `yaml:"Provider"`
+		URL       string `yaml:"URL"`
+		UserAgent string `yaml:"UserAgent"`
+		Timeout   int    `yaml:"Timeout"`
+		CacheTTL  int    `yaml:"CacheTTL"`
+	} `yaml:"Geocoder"`
+	GPXAnnotation struct {
+		Description      string `yaml:"Description"`
+		Creator          string `yaml:"Creator"`
+		Copyright        string `yaml:"Copyright"`
+		PointDescription string `yaml:"PointDescription"`
+	} `yaml:"GPXAnnotation"`
+	AdministrativeAreas struct {
+		GeoPackage    string `yaml:"GeoPackage"`
+		Layer         string `yaml:"Layer"`
+		NameAttribute string `yaml:"NameAttribute"`
+		KeyAttribute  string `yaml:"KeyAttribute"`
+	} `yaml:"AdministrativeAreas"`
+	ReferenceDEM struct {
+		Name        string `yaml:"Name"`
+		Path        string `yaml:"Path"`
+		Attribution string `yaml:"Attribution"`
+	} `yaml:"ReferenceDEM"`
+	WaterMask struct {
+		GeoPackage string `yaml:"GeoPackage"`
+		Layer      string `yaml:"Layer"`
+	} `yaml:"WaterMask"`
+	Jobs struct {
+		MaxParallelJobs       int  `yaml:"MaxParallelJobs"`
+		MaxJobs               int  `yaml:"MaxJobs"`
+		ResultTTL             int  `yaml:"ResultTTL"`
+		AllowPrivateCallbacks bool `yaml:"AllowPrivateCallbacks"`
+	} `yaml:"Jobs"`
+	ColorTables struct {
+		Directory          string `yaml:"Directory"`
+		MaxTablesPerTenant int    `yaml:"MaxTablesPerTenant"`
+	} `yaml:"ColorTables"`
+	Export struct {
+		MaxTiles         int `yaml:"MaxTiles"`
+		MaxContoursTiles int `yaml:"MaxContoursTiles"`
+		S3               struct {
+			Endpoint        string `yaml:"Endpoint"`
+			Region          string `yaml:"Region"`
+			Bucket          string `yaml:"Bucket"`
+			Prefix          string `yaml:"Prefix"`
+			AccessKeyID     string `yaml:"AccessKeyID"`
+			SecretAccessKey string `yaml:"SecretAccessKey"`
+			URLExpires      int    `yaml:"URLExpires"`
+		} `yaml:"S3"`
+		INSPIRE struct {
+			Namespace    string `yaml:"Namespace"`
+			Organisation string `yaml:"Organisation"`
+			Email        string `yaml:"Email"`
+		} `yaml:"INSPIRE"`
+	} `yaml:"Export"`
+	WebUI struct {
+		Enabled bool `yaml:"Enabled"`
+	} `yaml:"WebUI"`
+	Admin struct {
+		ListenAddress   string   `yaml:"ListenAddress"`
+		AllowedNetworks []string `yaml:"AllowedNetworks"`
+	} `yaml:"Admin"`
+	GRPC struct {
+		ListenAddress string `yaml:"ListenAddress"`
+	} `yaml:"GRPC"`
 }
 
-// progConfig represents program configuration
+// progConfig represents program configuration (at startup, see getProgConfig() for current configuration)
 var progConfig ProgConfig
 
+// logLevel represents the current log level (changeable at runtime)
+var logLevel = new(slog.LevelVar)
+
 // statistics
 var (
 	PointRequests            uint64
@@ -114,26 +259,75 @@ var (
 	ColorReliefRequests      uint64
 	HistogramRequests        uint64
 	ElevationProfileRequests uint64
+	VisualizeRequests        uint64
+	ResponseCacheHits        uint64
+	ResponseCacheMisses      uint64
+	TileCacheHits            uint64
+	TileCacheMisses          uint64
+	GDALJobsQueued           uint64
+	GDALJobsRejected         uint64
+	WorkerPoolQueued         uint64
+	WorkerPoolRejections     uint64
+	AuthenticationFailures   uint64
+	AuthorizationFailures    uint64
+	IPFilterRejections       uint64
+	RecoveredPanics          uint64
+	BannedRequests           uint64
+	ClientBans               uint64
+	LoadSheddingRejections   uint64
+	PartialResponses         uint64
+	JobRequests              uint64
+	ExportRequests           uint64
+	CompareRequests          uint64
+	AspectRoseRequests       uint64
+	ElevationRangeRequests   uint64
+	ContourLineRequests      uint64
+	ElevationMatrixRequests  uint64
+	LegendRequests           uint64
+	ColorTableRequests       uint64
+	ContoursExportRequests   uint64
 )
 
 /*
 main starts this program.
 */
 func main() {
-	// load program configuration
-	progConfigFile := progName + ".yaml"
-	source, err := os.ReadFile(progConfigFile)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "configuration file not found, file = [%s]\n", progConfigFile)
-		fmt.Fprintf(os.Stderr, "error [%v] at os.ReadFile()\n", err)
-		os.Exit(1)
+	// command line client mode (subcommands, e.g. 'point --lon 7.6 --lat 51.9')
+	if len(os.Args) > 1 && isCLICommand(os.Args[1]) {
+		os.Exit(runCLI(os.Args[1], os.Args[2:]))
 	}
-	err = yaml.Unmarshal(source, &progConfig)
+
+	// command line options (benchmark mode)
+	benchURL := flag.String("bench", "", "benchmark mode: base URL of running instance (e.g. https://localhost:14444)")
+	benchWorkload := flag.String("bench-workload", "mixed", "benchmark workload: point, gpx, hillshade, mixed")
+	benchRequests := flag.Int("bench-requests", 300, "benchmark: total number of requests")
+	benchConcurrency := flag.Int("bench-concurrency", 4, "benchmark: number of concurrent requests")
+	benchInsecure := flag.Bool("bench-insecure", false, "benchmark: skip TLS certificate verification")
+	benchSeed := flag.Int64("bench-seed", 1, "benchmark: seed for synthetic workload (reproducible runs)")
+	benchBBox := flag.String("bench-bbox", "7.0,50.5,9.0,52.0", "benchmark: area for synthetic requests (minLon,minLat,maxLon,maxLat)")
+	flag.Parse()
+
+	if *benchURL != "" {
+		bbox, err := parseBenchBoundingBox(*benchBBox)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error [%v] at parseBenchBoundingBox()\n", err)
+			os.Exit(1)
+		}
+		os.Exit(runBenchmark(BenchConfig{BaseURL: *benchURL, Workload: *benchWorkload, Requests: *benchRequests,
+			Concurrency: *benchConcurrency, Insecure: *benchInsecure, Seed: *benchSeed, BoundingBox: bbox}))
+	}
+
+	// load program configuration
+	progConfigFile := getProgConfigFile()
+	var err error
+	var envOverrides []string
+	progConfig, envOverrides, err = loadProgConfig(progConfigFile)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "configuration file invalid, file = [%s]\n", progConfigFile)
-		fmt.Fprintf(os.Stderr, "error [%v] at yaml.Unmarshal()\n", err)
+		fmt.Fprintf(os.Stderr, "configuration file not found or invalid, file = [%s]\n", progConfigFile)
+		fmt.Fprintf(os.Stderr, "error [%v] at loadProgConfig()\n", err)
 		os.Exit(1)
 	}
+	activeProgConfig.Store(&progConfig)
 
 	// logging: replacer for logging objects
 	replacer := func(_ []string, a slog.Attr) slog.Attr {
@@ -158,22 +352,25 @@ func main() {
 	}
 
 	// log level
-	logLevel := new(slog.LevelVar)
 	logLevel.Set(parseLogLevel(progConfig.LogLevel))
 
-	// define logger
-	logger := slog.New(slog.NewJSONHandler(lumberjackLogger, &slog.HandlerOptions{
+	// define logger (request id from context is added to log entries)
+	logger := slog.New(contextLogHandler{slog.NewJSONHandler(lumberjackLogger, &slog.HandlerOptions{
 		Level:     logLevel,
-		AddSource: true, ReplaceAttr: replacer}).WithAttrs([]slog.Attr{slog.String("prog", progName)}))
+		AddSource: true, ReplaceAttr: replacer})}.WithAttrs([]slog.Attr{slog.String("prog", progName)}))
 	slog.SetDefault(logger)
 
 	// log program start
 	slog.Info(progPurpose+" startet", "name", progName, "version", progVersion, "date", progDate, "info", progInfo, "copyright", progCopyright, "command line", os.Args)
 	jsonData, _ := json.MarshalIndent(progConfig, "", "  ") // encode to JSON for readability
 	slog.Info("content of configuration file", "configuration file", progConfigFile, "content", string(jsonData))
+	if len(envOverrides) > 0 {
+		slog.Info("settings overridden by environment variables", "variables", envOverrides)
+	}
 
 	// build global tile repository
-	err = buildRepository()
+	sdNotifyState("STATUS=building tile repository")
+	err = buildRepository(progConfig.TileRepositories, progConfig.DisabledSources)
 	if err != nil {
 		slog.Error("error building global tile repository", "error", err)
 		os.Exit(1)
@@ -186,62 +383,190 @@ func main() {
 		os.Exit(1)
 	}
 
+	// prepare temp directory (remove orphaned temp directories from previous runs)
+	err = initTempDirectory()
+	if err != nil {
+		slog.Error("error initializing temp directory", "error", err)
+		os.Exit(1)
+	}
+	sweepOrphanedTempDirectories()
+
 	// initialize GDAL, register all known GDAL drivers
 	godal.RegisterAll()
 
-	// define routes
-	http.HandleFunc("POST /v1/point", pointRequest)
-	http.HandleFunc("OPTIONS /v1/point", corsOptionsHandler)
+	// check GDAL dependencies (version, drivers)
+	err = checkGDALDependencies()
+	if err != nil {
+		slog.Error("error checking GDAL dependencies", "error", err)
+		os.Exit(1)
+	}
+
+	// create response cache (max size in megabytes, ttl in seconds)
+	responseCache = newResponseCache(progConfig.ResponseCache.MaxEntries, progConfig.ResponseCache.MaxSize*1024*1024,
+		time.Duration(progConfig.ResponseCache.TTL)*time.Second)
+
+	// create tile cache for point queries (ttl in seconds)
+	tileCache = newTileCache(progConfig.TileCache.MaxTiles, time.Duration(progConfig.TileCache.TTL)*time.Second)
+
+	// initialize GDAL job queue (max queue wait in seconds)
+	initGDALJobQueue(progConfig.GDALJobQueue.MaxParallelJobs, time.Duration(progConfig.GDALJobQueue.MaxQueueWait)*time.Second)
+
+	// initialize worker pools (concurrency limits per endpoint class)
+	initWorkerPools()
+
+	// initialize asynchronous jobs
+	initJobs(progConfig.Jobs.MaxParallelJobs)
+
+	// load named color tables (persisted in color tables directory)
+	err = initColorTables()
+	if err != nil {
+		slog.Error("error loading color tables", "error", err)
+		os.Exit(1)
+	}
+
+	// generate OpenAPI document (derived from request and response types)
+	err = initOpenAPIDocument()
+	if err != nil {
+		slog.Error("error generating OpenAPI document", "error", err)
+		os.Exit(1)
+	}
+
+	// IP allow and deny lists
+	filter, err := newIPFilter(progConfig.IPFilter.AllowedNetworks, progConfig.IPFilter.DeniedNetworks)
+	if err != nil {
+		slog.Error("error initializing IP filter", "error", err)
+		os.Exit(1)
+	}
+	ipFilter.Store(filter)
+
+	// trusted reverse proxies (client IP from 'X-Forwarded-For' or 'Forwarded')
+	err = setTrustedProxies(progConfig.TrustedProxies)
+	if err != nil {
+		slog.Error("error initializing trusted proxies", "error", err)
+		os.Exit(1)
+	}
+
+	// JWT authentication (only if trusted issuers are configured, JWKS cache ttl in seconds)
+	if len(progConfig.TrustedIssuers) > 0 {
+		jwtValidator = newJWTValidator(progConfig.TrustedIssuers, progConfig.Authentication.Audience,
+			time.Duration(progConfig.Authentication.JWKSCacheTTL)*time.Second)
+	}
+
+	// define routes (own multiplexer, not http.DefaultServeMux, e.g. net/http/pprof registers there)
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/point", pointRequest)
+	mux.HandleFunc("GET /v1/point", pointRequest)
+	mux.HandleFunc("OPTIONS /v1/point", corsOptionsHandler)
+
+	mux.HandleFunc("POST /v1/utmpoint", utmPointRequest)
+	mux.HandleFunc("GET /v1/utmpoint", utmPointRequest)
+	mux.HandleFunc("OPTIONS /v1/utmpoint", corsOptionsHandler)
 
-	http.HandleFunc("POST /v1/utmpoint", utmPointRequest)
-	http.HandleFunc("OPTIONS /v1/utmpoint", corsOptionsHandler)
+	mux.HandleFunc("POST /v1/gpx", gpxRequest)
+	mux.HandleFunc("OPTIONS /v1/gpx", corsOptionsHandler)
 
-	http.HandleFunc("POST /v1/gpx", gpxRequest)
-	http.HandleFunc("OPTIONS /v1/gpx", corsOptionsHandler)
+	mux.HandleFunc("POST /v1/gpxanalyze", gpxAnalyzeRequest)
+	mux.HandleFunc("OPTIONS /v1/gpxanalyze", corsOptionsHandler)
 
-	http.HandleFunc("POST /v1/gpxanalyze", gpxAnalyzeRequest)
-	http.HandleFunc("OPTIONS /v1/gpxanalyze", corsOptionsHandler)
+	mux.HandleFunc("POST /v1/contours", contoursRequest)
+	mux.HandleFunc("OPTIONS /v1/contours", corsOptionsHandler)
 
-	http.HandleFunc("POST /v1/contours", contoursRequest)
-	http.HandleFunc("OPTIONS /v1/contours", corsOptionsHandler)
+	mux.HandleFunc("POST /v1/hillshade", hillshadeRequest)
+	mux.HandleFunc("OPTIONS /v1/hillshade", corsOptionsHandler)
 
-	http.HandleFunc("POST /v1/hillshade", hillshadeRequest)
-	http.HandleFunc("OPTIONS /v1/hillshade", corsOptionsHandler)
+	mux.HandleFunc("POST /v1/slope", slopeRequest)
+	mux.HandleFunc("OPTIONS /v1/slope", corsOptionsHandler)
 
-	http.HandleFunc("POST /v1/slope", slopeRequest)
-	http.HandleFunc("OPTIONS /v1/slope", corsOptionsHandler)
+	mux.HandleFunc("POST /v1/aspect", aspectRequest)
+	mux.HandleFunc("OPTIONS /v1/aspect", corsOptionsHandler)
 
-	http.HandleFunc("POST /v1/aspect", aspectRequest)
-	http.HandleFunc("OPTIONS /v1/aspect", corsOptionsHandler)
+	mux.HandleFunc("POST /v1/tpi", tpiRequest)
+	mux.HandleFunc("OPTIONS /v1/tpi", corsOptionsHandler)
 
-	http.HandleFunc("POST /v1/tpi", tpiRequest)
-	http.HandleFunc("OPTIONS /v1/tpi", corsOptionsHandler)
+	mux.HandleFunc("POST /v1/tri", triRequest)
+	mux.HandleFunc("OPTIONS /v1/tri", corsOptionsHandler)
 
-	http.HandleFunc("POST /v1/tri", triRequest)
-	http.HandleFunc("OPTIONS /v1/tri", corsOptionsHandler)
+	mux.HandleFunc("POST /v1/roughness", roughnessRequest)
+	mux.HandleFunc("OPTIONS /v1/roughness", corsOptionsHandler)
 
-	http.HandleFunc("POST /v1/roughness", roughnessRequest)
-	http.HandleFunc("OPTIONS /v1/roughness", corsOptionsHandler)
+	mux.HandleFunc("POST /v1/rawtif", rawtifRequest)
+	mux.HandleFunc("OPTIONS /v1/rawtif", corsOptionsHandler)
 
-	http.HandleFunc("POST /v1/rawtif", rawtifRequest)
-	http.HandleFunc("OPTIONS /v1/rawtif", corsOptionsHandler)
+	mux.HandleFunc("POST /v1/colorrelief", colorReliefRequest)
+	mux.HandleFunc("OPTIONS /v1/colorrelief", corsOptionsHandler)
 
-	http.HandleFunc("POST /v1/colorrelief", colorReliefRequest)
-	http.HandleFunc("OPTIONS /v1/colorrelief", corsOptionsHandler)
+	mux.HandleFunc("POST /v1/histogram", histogramRequest)
+	mux.HandleFunc("OPTIONS /v1/histogram", corsOptionsHandler)
 
-	http.HandleFunc("POST /v1/histogram", histogramRequest)
-	http.HandleFunc("OPTIONS /v1/histogram", corsOptionsHandler)
+	mux.HandleFunc("POST /v1/elevationprofile", elevationprofileRequest)
+	mux.HandleFunc("OPTIONS /v1/elevationprofile", corsOptionsHandler)
 
-	http.HandleFunc("POST /v1/elevationprofile", elevationprofileRequest)
-	http.HandleFunc("OPTIONS /v1/elevationprofile", corsOptionsHandler)
+	mux.HandleFunc("POST /v1/visualize", visualizeRequest)
+	mux.HandleFunc("OPTIONS /v1/visualize", corsOptionsHandler)
+
+	mux.HandleFunc("POST /v1/compare", compareRequest)
+	mux.HandleFunc("OPTIONS /v1/compare", corsOptionsHandler)
+
+	mux.HandleFunc("POST /v1/aspectrose", aspectRoseRequest)
+	mux.HandleFunc("OPTIONS /v1/aspectrose", corsOptionsHandler)
+	mux.HandleFunc("POST /v1/elevationrange", elevationRangeRequest)
+	mux.HandleFunc("OPTIONS /v1/elevationrange", corsOptionsHandler)
+	mux.HandleFunc("POST /v1/contourline", contourLineRequest)
+	mux.HandleFunc("OPTIONS /v1/contourline", corsOptionsHandler)
+	mux.HandleFunc("POST /v1/elevationmatrix", elevationMatrixRequest)
+	mux.HandleFunc("OPTIONS /v1/elevationmatrix", corsOptionsHandler)
+	mux.HandleFunc("POST /v1/legend", legendRequest)
+	mux.HandleFunc("OPTIONS /v1/legend", corsOptionsHandler)
+
+	// API v2 (JSON:API documents, based on v1 handlers)
+	for _, endpoint := range v2Endpoints {
+		for _, method := range endpoint.Methods {
+			mux.HandleFunc(method+" "+endpoint.Path, v2Handler(endpoint))
+		}
+		mux.HandleFunc("OPTIONS "+endpoint.Path, corsOptionsHandler)
+	}
+
+	// asynchronous jobs (heavy requests, e.g. large areas)
+	mux.HandleFunc("POST /v1/jobs", jobSubmitRequest)
+	mux.HandleFunc("OPTIONS /v1/jobs", corsOptionsHandler)
+	mux.HandleFunc("GET /v1/jobs/{id}", jobStatusRequest)
+	mux.HandleFunc("DELETE /v1/jobs/{id}", jobDeleteRequest)
+	mux.HandleFunc("OPTIONS /v1/jobs/{id}", corsOptionsHandler)
+	mux.HandleFunc("GET /v1/jobs/{id}/result", jobResultRequest)
+	mux.HandleFunc("OPTIONS /v1/jobs/{id}/result", corsOptionsHandler)
+	mux.HandleFunc("GET /v1/jobs/{id}/events", jobEventsRequest)
+	mux.HandleFunc("OPTIONS /v1/jobs/{id}/events", corsOptionsHandler)
+
+	mux.HandleFunc("POST /v1/colortables/{name}", colorTableStoreRequest)
+	mux.HandleFunc("GET /v1/colortables/{name}", colorTableGetRequest)
+	mux.HandleFunc("DELETE /v1/colortables/{name}", colorTableDeleteRequest)
+	mux.HandleFunc("OPTIONS /v1/colortables/{name}", corsOptionsHandler)
+
+	mux.HandleFunc("GET /v1/errors", errorCodesRequest)
+	mux.HandleFunc("OPTIONS /v1/errors", corsOptionsHandler)
+
+	mux.HandleFunc("GET /v1/capabilities", capabilitiesRequest)
+	mux.HandleFunc("OPTIONS /v1/capabilities", corsOptionsHandler)
+
+	mux.HandleFunc("GET /openapi.json", openAPIRequest)
+	if progConfig.Admin.ListenAddress == "" {
+		// statistics on public listener only if admin listener is disabled
+		mux.HandleFunc("GET /v1/stats", statisticsRequest)
+	}
+	mux.HandleFunc("OPTIONS /openapi.json", corsOptionsHandler)
+
+	// interactive web UI (static files, uses the public API)
+	mux.Handle("GET /ui/", webUIHandler())
+	mux.Handle("GET /ui", http.RedirectHandler("/ui/", http.StatusMovedPermanently))
 
 	// handle unsupported routes or methods
-	http.HandleFunc("/", unsupportedRequest)
+	mux.HandleFunc("/", unsupportedRequest)
+	serviceMux = mux
 
 	// define service
 	DtmElevationService := &http.Server{
 		Addr:              progConfig.ListenAddress,
-		Handler:           nil,
+		Handler:           requestIDMiddleware(accessLogMiddleware(recoveryMiddleware(ipFilterMiddleware(abuseMiddleware(corsMiddleware(loadSheddingMiddleware(negotiationMiddleware(authMiddleware(workerPoolMiddleware(usageMiddleware(metaMiddleware(mux)))))))))))),
 		ReadHeaderTimeout: 10 * time.Second,
 		ReadTimeout:       120 * time.Second,
 		WriteTimeout:      180 * time.Second,
@@ -254,18 +579,116 @@ func main() {
 		hostname = "unknown"
 	}
 
+	// TLS mode: 'file' (certificate files, reloaded after renewal), 'acme' (certificates obtained and renewed
+	// automatically, e.g. from Let's Encrypt) or 'none' (plain HTTP, e.g. behind reverse proxy)
+	var acmeChallengeService *http.Server
+	tlsMode := strings.ToLower(progConfig.TLSMode)
+	switch tlsMode {
+	case "", "file":
+		tlsMode = "file"
+		certificateReloader, err := newCertificateReloader(progConfig.ServerCertificate, progConfig.ServerKey)
+		if err != nil {
+			slog.Error("error loading server certificate", "error", err)
+			os.Exit(1)
+		}
+		DtmElevationService.TLSConfig = &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: certificateReloader.GetCertificate,
+		}
+	case "acme":
+		acmeManager, err := newACMEManager()
+		if err != nil {
+			slog.Error("error creating ACME certificate manager", "error", err)
+			os.Exit(1)
+		}
+		DtmElevationService.TLSConfig = acmeManager.TLSConfig()
+		DtmElevationService.TLSConfig.MinVersion = tls.VersionTLS12
+
+		// HTTP-01 challenge listener (other plain HTTP requests are redirected to HTTPS)
+		acmeChallengeService = &http.Server{
+			Addr:              progConfig.ACME.HTTPListenAddress,
+			Handler:           acmeManager.HTTPHandler(nil),
+			ReadHeaderTimeout: 10 * time.Second,
+			IdleTimeout:       30 * time.Second,
+		}
+		go func() {
+			slog.Info("ACME HTTP-01 challenge service listening for requests", "ListenAddress", progConfig.ACME.HTTPListenAddress, "hosts", progConfig.ACME.Hosts)
+			err := acmeChallengeService.ListenAndServe()
+			if err != nil && err != http.ErrServerClosed {
+				slog.Error("error at acmeChallengeService.ListenAndServe()", "error", err)
+			}
+		}()
+	case "none":
+		slog.Warn("TLS disabled, serving plain HTTP (only recommended behind a TLS terminating reverse proxy)")
+	default:
+		slog.Error("invalid TLS mode (expected: file, acme, none)", "TLSMode", progConfig.TLSMode)
+		os.Exit(1)
+	}
+
+	// bind listener (before notifying systemd about readiness)
+	listener, err := net.Listen("tcp", progConfig.ListenAddress)
+	if err != nil {
+		slog.Error("error at net.Listen()", "error", err, "ListenAddress", progConfig.ListenAddress)
+		os.Exit(1)
+	}
+
 	// create service
 	go func() {
-		slog.Info("dtm elevation service listening for requests", "ListenAddress", progConfig.ListenAddress, "hostname", hostname)
-		err := DtmElevationService.ListenAndServeTLS(progConfig.ServerCertificate, progConfig.ServerKey)
+		slog.Info("dtm elevation service listening for requests", "ListenAddress", progConfig.ListenAddress, "hostname", hostname, "TLSMode", tlsMode)
+		var err error
+		if tlsMode == "none" {
+			err = DtmElevationService.Serve(listener)
+		} else {
+			// certificate provided by TLSConfig.GetCertificate
+			err = DtmElevationService.ServeTLS(listener, "", "")
+		}
 		if err != nil {
 			if err != http.ErrServerClosed {
-				slog.Error("error at DtmElevationService.ListenAndServe()", "error", err)
+				slog.Error("error at DtmElevationService.Serve()", "error", err)
 				os.Exit(1)
 			}
 		}
 	}()
 
+	// create gRPC service (backend-to-backend integrations) on separate listener, same TLS mode as public listener
+	var grpcService *grpc.Server
+	if progConfig.GRPC.ListenAddress != "" {
+		grpcListener, err := net.Listen("tcp", progConfig.GRPC.ListenAddress)
+		if err != nil {
+			slog.Error("error at net.Listen()", "error", err, "ListenAddress", progConfig.GRPC.ListenAddress)
+			os.Exit(1)
+		}
+		grpcService = newGRPCServer(DtmElevationService.TLSConfig)
+		go func() {
+			slog.Info("grpc service listening for requests", "ListenAddress", progConfig.GRPC.ListenAddress, "TLSMode", tlsMode)
+			err := grpcService.Serve(grpcListener)
+			if err != nil {
+				slog.Error("error at grpcService.Serve()", "error", err)
+			}
+		}()
+	}
+
+	// create admin service (observability and administration endpoints) on separate listener
+	var adminService *http.Server
+	if progConfig.Admin.ListenAddress != "" {
+		adminService, err = newAdminServer()
+		if err != nil {
+			slog.Error("error creating admin service", "error", err)
+			os.Exit(1)
+		}
+		go func() {
+			slog.Info("admin service listening for requests", "ListenAddress", progConfig.Admin.ListenAddress)
+			err := adminService.ListenAndServe()
+			if err != nil && err != http.ErrServerClosed {
+				slog.Error("error at adminService.ListenAndServe()", "error", err)
+			}
+		}()
+	}
+
+	// notify systemd (Type=notify or Type=notify-reload) and start watchdog (WatchdogSec=)
+	sdNotifyState("READY=1\nSTATUS=serving requests")
+	startSDWatchdog()
+
 	// start rotate trigger (checks, if log rotate is required)
 	rotateTrigger := time.Tick(time.Second * 60)
 
@@ -274,9 +697,13 @@ func main() {
 	signal.Notify(shutdownTrigger, syscall.SIGINT)  // kill -SIGINT pid -> interrupt
 	signal.Notify(shutdownTrigger, syscall.SIGTERM) // kill -SIGTERM pid -> terminated
 
+	// subscribe to reload signal (kill -SIGHUP pid -> reload configuration)
+	reloadTrigger := make(chan os.Signal, 1)
+	signal.Notify(reloadTrigger, syscall.SIGHUP)
+
 ForeverLoop:
 	for {
-		// wait for log rotate or shutdown trigger
+		// wait for log rotate, reload or shutdown trigger
 		select {
 		case <-rotateTrigger:
 			logrotateCurrentYearDay := time.Now().UTC().YearDay()
@@ -289,6 +716,13 @@ ForeverLoop:
 				logrotateStartYearDay = logrotateCurrentYearDay
 				logStatistics()
 			}
+			usageAccounting.save()
+		case <-reloadTrigger:
+			slog.Info("signal received, reloading configuration", "signal", syscall.SIGHUP)
+			err := reloadProgConfig(progConfigFile)
+			if err != nil {
+				slog.Error("error reloading configuration, current configuration remains active", "error", err)
+			}
 		case sig := <-shutdownTrigger:
 			// initiate shutdown
 			slog.Info("signal received, shutting down elevation service", "signal", sig)
@@ -296,6 +730,8 @@ ForeverLoop:
 		}
 	}
 
+	sdNotifyState("STOPPING=1\nSTATUS=shutting down")
+
 	// shutdown grace period (wait max n seconds before halting)
 	gracePeriod := time.Duration(progConfig.ShutdownGracePeriod) * time.Second
 
@@ -306,74 +742,37 @@ ForeverLoop:
 	if err != nil {
 		slog.Error("fatal error at DtmElevationService.Shutdown()", "error", err)
 	}
+	if adminService != nil {
+		_ = adminService.Shutdown(ctx)
+	}
+	if grpcService != nil {
+		shutdownGRPCServer(ctx, grpcService)
+	}
+	if acmeChallengeService != nil {
+		_ = acmeChallengeService.Shutdown(ctx)
+	}
 
 	// log program end
 	logStatistics()
+	usageAccounting.save()
 	slog.Info("service gracefully shut down")
 }
 
 /*
-logStatistics logs statistics.
+logStatistics logs statistics (counters are reset after logging).
 */
 func logStatistics() {
-	// read statistics
-	currentPointRequests := atomic.LoadUint64(&PointRequests)
-	currentUTMPointRequests := atomic.LoadUint64(&UTMPointRequests)
-	currentGPXRequests := atomic.LoadUint64(&GPXRequests)
-	currentGPXAnalyzeRequests := atomic.LoadUint64(&GPXAnalyzeRequests)
-	currentGPXPoints := atomic.LoadUint64(&GPXPoints)
-	currentDGMPoints := atomic.LoadUint64(&DGMPoints)
-	currentContoursRequests := atomic.LoadUint64(&ContoursRequests)
-	currentHillshadeRequests := atomic.LoadUint64(&HillshadeRequests)
-	currentSlopeRequests := atomic.LoadUint64(&SlopeRequests)
-	currentAspectRequests := atomic.LoadUint64(&AspectRequests)
-	currentTPIRequests := atomic.LoadUint64(&TPIRequests)
-	currentTRIRequests := atomic.LoadUint64(&TRIRequests)
-	currentRoughnessRequests := atomic.LoadUint64(&RoughnessRequests)
-	currentRawTIFRequests := atomic.LoadUint64(&RawTIFRequests)
-	currentColorReliefRequests := atomic.LoadUint64(&ColorReliefRequests)
-	currentHistogramRequests := atomic.LoadUint64(&HistogramRequests)
-	currentElevationProfileRequests := atomic.LoadUint64(&ElevationProfileRequests)
-
-	// reset statistics
-	atomic.StoreUint64(&PointRequests, 0)
-	atomic.StoreUint64(&UTMPointRequests, 0)
-	atomic.StoreUint64(&GPXRequests, 0)
-	atomic.StoreUint64(&GPXAnalyzeRequests, 0)
-	atomic.StoreUint64(&GPXPoints, 0)
-	atomic.StoreUint64(&DGMPoints, 0)
-	atomic.StoreUint64(&ContoursRequests, 0)
-	atomic.StoreUint64(&HillshadeRequests, 0)
-	atomic.StoreUint64(&SlopeRequests, 0)
-	atomic.StoreUint64(&AspectRequests, 0)
-	atomic.StoreUint64(&TPIRequests, 0)
-	atomic.StoreUint64(&TRIRequests, 0)
-	atomic.StoreUint64(&RoughnessRequests, 0)
-	atomic.StoreUint64(&RawTIFRequests, 0)
-	atomic.StoreUint64(&ColorReliefRequests, 0)
-	atomic.StoreUint64(&HistogramRequests, 0)
-	atomic.StoreUint64(&ElevationProfileRequests, 0)
+	// read and reset statistics
+	counters := resetStatistics()
 
 	// log statistics
-	slog.Info("load statistics",
-		"PointRequests", currentPointRequests,
-		"UTMPointRequests", currentUTMPointRequests,
-		"GPXRequests", currentGPXRequests,
-		"GPXAnalyzeRequests", currentGPXAnalyzeRequests,
-		"GPXPoints", currentGPXPoints,
-		"DGMPoints", currentDGMPoints,
-		"ContoursRequests", currentContoursRequests,
-		"HillshadeRequests", currentHillshadeRequests,
-		"SlopeRequests", currentSlopeRequests,
-		"AspectRequests", currentAspectRequests,
-		"TPIRequests", currentTPIRequests,
-		"TRIRequests", currentTRIRequests,
-		"RoughnessRequests", currentRoughnessRequests,
-		"RawTIFRequests", currentRawTIFRequests,
-		"ColorReliefRequests", currentColorReliefRequests,
-		"HistogramRequests", currentHistogramRequests,
-		"ElevationProfileRequests", currentElevationProfileRequests,
-	)
+	args := make([]any, 0, 2*len(counters)+4)
+	for _, counter := range counters {
+		args = append(args, counter.Name, counter.Value)
+	}
+	responseCacheEntries, responseCacheBytes := responseCache.Len()
+	args = append(args, "ResponseCacheEntries", responseCacheEntries, "ResponseCacheBytes", responseCacheBytes)
+	slog.Info("load statistics", args...)
 }
 
 /*
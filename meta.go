@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/airbusgeo/godal"
+)
+
+// ResponseMeta represents metadata of a response (e.g. for debugging performance and reproducibility issues).
+type ResponseMeta struct {
+	ServiceVersion     string
+	GDALVersion        string
+	ProcessingDuration int64 // milliseconds
+	CacheHit           bool  // all generated objects taken from response cache
+}
+
+// requestMeta represents the processing state of a single request (start time, response cache lookups).
+type requestMeta struct {
+	start        time.Time
+	cacheLookups atomic.Int64
+	cacheHits    atomic.Int64
+}
+
+type requestMetaKey struct{}
+
+// runtime version of GDAL library (determined once)
+var gdalVersion = sync.OnceValue(func() string {
+	version := godal.Version()
+	return fmt.Sprintf("%d.%d.%d", version.Major(), version.Minor(), version.Revision())
+})
+
+/*
+metaMiddleware records the start of request processing (base for the processing duration in response metadata).
+*/
+func metaMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		meta := &requestMeta{start: time.Now()}
+		next.ServeHTTP(writer, request.WithContext(context.WithValue(request.Context(), requestMetaKey{}, meta)))
+	})
+}
+
+/*
+recordCacheLookup records a response cache lookup (hit or miss) for the current request.
+*/
+func recordCacheLookup(ctx context.Context, hit bool) {
+	meta, ok := ctx.Value(requestMetaKey{}).(*requestMeta)
+	if !ok {
+		return
+	}
+	meta.cacheLookups.Add(1)
+	if hit {
+		meta.cacheHits.Add(1)
+	}
+}
+
+/*
+newResponseMeta returns the metadata for the response of the current request.
+*/
+func newResponseMeta(ctx context.Context) ResponseMeta {
+	responseMeta := ResponseMeta{
+		ServiceVersion: progVersion,
+		GDALVersion:    gdalVersion(),
+	}
+	meta, ok := ctx.Value(requestMetaKey{}).(*requestMeta)
+	if ok {
+		responseMeta.ProcessingDuration = time.Since(meta.start).Milliseconds()
+		lookups := meta.cacheLookups.Load()
+		responseMeta.CacheHit = lookups > 0 && meta.cacheHits.Load() == lookups
+	}
+	return responseMeta
+}
@@ -0,0 +1,366 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// RequestIDHeader is the HTTP header used to accept and echo the request ID
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength limits the length of client provided request IDs
+const maxRequestIDLength = 128
+
+// requestIDKey is the context key for the request ID
+type requestIDKey struct{}
+
+/*
+requestIDMiddleware accepts the request ID provided by the client (X-Request-ID) or generates a new one.
+The request ID is stored in the request context (included in all log entries) and echoed in the response.
+*/
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requestID := request.Header.Get(RequestIDHeader)
+		if !isValidRequestID(requestID) {
+			requestID = newRequestID()
+		}
+
+		writer.Header().Set(RequestIDHeader, requestID)
+		ctx := context.WithValue(request.Context(), requestIDKey{}, requestID)
+		next.ServeHTTP(writer, request.WithContext(ctx))
+	})
+}
+
+/*
+isValidRequestID reports whether a client provided request ID is acceptable (not empty, limited length,
+printable ASCII only, e.g. to prevent log injection).
+*/
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(requestID); i++ {
+		if requestID[i] < 0x21 || requestID[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+newRequestID generates a random request ID (32 hex characters).
+*/
+func newRequestID() string {
+	buffer := make([]byte, 16)
+	_, _ = rand.Read(buffer)
+	return hex.EncodeToString(buffer)
+}
+
+/*
+getRequestID returns the request ID from the context (empty if not set).
+*/
+func getRequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// contextLogHandler adds the request ID from the context to each log record
+type contextLogHandler struct {
+	slog.Handler
+}
+
+/*
+Handle adds the request ID (if present in context) to the record and passes it to the wrapped handler.
+*/
+func (h contextLogHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := getRequestID(ctx); requestID != "" {
+		record.AddAttrs(slog.String("request id", requestID))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+/*
+WithAttrs returns a new wrapped handler with the given attributes.
+*/
+func (h contextLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextLogHandler{h.Handler.WithAttrs(attrs)}
+}
+
+/*
+WithGroup returns a new wrapped handler with the given group.
+*/
+func (h contextLogHandler) WithGroup(name string) slog.Handler {
+	return contextLogHandler{h.Handler.WithGroup(name)}
+}
+
+// responseRecorder records status code and body size of a response
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	size   int64
+}
+
+/*
+WriteHeader records the status code and passes it to the wrapped writer.
+*/
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+/*
+Write records the body size and passes the data to the wrapped writer.
+*/
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(data)
+	r.size += int64(n)
+	return n, err
+}
+
+/*
+Unwrap returns the wrapped writer (used by http.ResponseController).
+*/
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+/*
+accessLogMiddleware writes one log entry per request (method, path, status, size, duration, client ip, request id).
+*/
+func accessLogMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if !getProgConfig().AccessLog {
+			next.ServeHTTP(writer, request)
+			return
+		}
+
+		start := time.Now()
+		recorder := &responseRecorder{ResponseWriter: writer}
+		next.ServeHTTP(recorder, request)
+		if recorder.status == 0 {
+			recorder.status = http.StatusOK
+		}
+
+		// request id is added by log handler (from context)
+		slog.InfoContext(request.Context(), "access",
+			"method", request.Method,
+			"path", request.URL.Path,
+			"status", recorder.status,
+			"size", recorder.size,
+			"duration", time.Since(start).Milliseconds(),
+			"client ip", getClientIP(request),
+			"user agent", request.UserAgent())
+	})
+}
+
+// trustedProxies contains the networks of trusted reverse proxies (replaced as a whole on configuration reload)
+var trustedProxies atomic.Pointer[[]*net.IPNet]
+
+/*
+setTrustedProxies sets the networks of trusted reverse proxies (networks in CIDR notation or single IP addresses).
+*/
+func setTrustedProxies(networks []string) error {
+	proxies, err := parseNetworks(networks)
+	if err != nil {
+		return fmt.Errorf("error [%w] at parseNetworks(), trusted proxies", err)
+	}
+	trustedProxies.Store(&proxies)
+	return nil
+}
+
+/*
+isTrustedProxy checks if the address (host:port or IP) belongs to a trusted reverse proxy.
+*/
+func isTrustedProxy(address string) bool {
+	proxies := trustedProxies.Load()
+	if proxies == nil || len(*proxies) == 0 {
+		return false
+	}
+	return isIPInNetworks(address, *proxies)
+}
+
+/*
+getClientIP returns the IP address of the client. The address is taken from the header fields 'X-Forwarded-For'
+or 'Forwarded' only if the request was received from a trusted reverse proxy (see configuration 'TrustedProxies'):
+the right-most address not belonging to a trusted proxy is the client. Otherwise the remote address is used.
+*/
+func getClientIP(request *http.Request) string {
+	host, _, err := net.SplitHostPort(request.RemoteAddr)
+	if err != nil {
+		host = request.RemoteAddr
+	}
+	if !isTrustedProxy(host) {
+		return host
+	}
+
+	forwarded := getForwardedAddresses(request.Header)
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		if net.ParseIP(forwarded[i]) == nil {
+			// invalid (or obfuscated) address, the hops before are not trustworthy
+			break
+		}
+		if !isTrustedProxy(forwarded[i]) {
+			return forwarded[i]
+		}
+	}
+	return host
+}
+
+/*
+getForwardedAddresses returns the client addresses of the proxy chain (left = origin) from 'X-Forwarded-For'
+or, if not present, from 'Forwarded' (RFC 7239, parameter 'for').
+*/
+func getForwardedAddresses(header http.Header) []string {
+	var addresses []string
+
+	xForwardedFor := header.Values("X-Forwarded-For")
+	if len(xForwardedFor) > 0 {
+		for _, value := range xForwardedFor {
+			for _, address := range strings.Split(value, ",") {
+				addresses = append(addresses, strings.TrimSpace(address))
+			}
+		}
+		return addresses
+	}
+
+	for _, value := range header.Values("Forwarded") {
+		for _, element := range strings.Split(value, ",") {
+			for _, pair := range strings.Split(element, ";") {
+				name, address, found := strings.Cut(strings.TrimSpace(pair), "=")
+				if !found || !strings.EqualFold(name, "for") {
+					continue
+				}
+				// e.g. for=192.0.2.43, for="[2001:db8:cafe::17]:4711"
+				address = strings.Trim(address, `"`)
+				if host, _, err := net.SplitHostPort(address); err == nil {
+					address = host
+				}
+				addresses = append(addresses, strings.Trim(address, "[]"))
+			}
+		}
+	}
+	return addresses
+}
+
+// IPFilter represents the parsed allow and deny lists for client IP addresses.
+type IPFilter struct {
+	allowedNetworks []*net.IPNet
+	deniedNetworks  []*net.IPNet
+}
+
+// ipFilter is the active IP filter (replaced as a whole on configuration reload)
+var ipFilter atomic.Pointer[IPFilter]
+
+/*
+newIPFilter parses the allow and deny lists (networks in CIDR notation or single IP addresses).
+*/
+func newIPFilter(allowedNetworks []string, deniedNetworks []string) (*IPFilter, error) {
+	allowed, err := parseNetworks(allowedNetworks)
+	if err != nil {
+		return nil, fmt.Errorf("error [%w] at parseNetworks(), allowed networks", err)
+	}
+	denied, err := parseNetworks(deniedNetworks)
+	if err != nil {
+		return nil, fmt.Errorf("error [%w] at parseNetworks(), denied networks", err)
+	}
+	return &IPFilter{allowedNetworks: allowed, deniedNetworks: denied}, nil
+}
+
+/*
+isAllowed checks the client IP address against the deny list (first) and the allow list (empty = all allowed).
+*/
+func (f *IPFilter) isAllowed(clientIP string) bool {
+	if isIPInNetworks(clientIP, f.deniedNetworks) {
+		return false
+	}
+	if len(f.allowedNetworks) == 0 {
+		return true
+	}
+	return isIPInNetworks(clientIP, f.allowedNetworks)
+}
+
+/*
+ipFilterMiddleware rejects requests from denied (or not allowed) client IP addresses before processing.
+*/
+func ipFilterMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		filter := ipFilter.Load()
+		clientIP := getClientIP(request)
+		if filter != nil && !filter.isAllowed(clientIP) {
+			atomic.AddUint64(&IPFilterRejections, 1)
+			slog.WarnContext(request.Context(), "request rejected by IP filter", "client ip", clientIP, "path", request.URL.Path)
+			writer.Header().Set("Content-Type", TextPlainMediaType)
+			writer.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(writer, "access denied")
+			return
+		}
+		next.ServeHTTP(writer, request)
+	})
+}
+
+// TypeErrorResponse is the type of generic error responses (e.g. after a panic)
+const TypeErrorResponse = "ErrorResponse"
+
+// ErrorResponse represents a generic error response (not bound to an endpoint).
+type ErrorResponse struct {
+	Type       string
+	ID         string
+	Attributes struct {
+		IsError   bool
+		Error     ErrorObject
+		RequestID string
+	}
+}
+
+/*
+recoveryMiddleware converts panics in handlers into '500 Internal Server Error' JSON responses
+(with request ID) and logs the stack trace, instead of killing the connection.
+*/
+func recoveryMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		recorder := &responseRecorder{ResponseWriter: writer}
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				// deliberate abort of response
+				panic(recovered)
+			}
+
+			atomic.AddUint64(&RecoveredPanics, 1)
+			requestID := getRequestID(request.Context())
+			slog.ErrorContext(request.Context(), "panic recovered in request handler", "panic", fmt.Sprint(recovered),
+				"method", request.Method, "path", request.URL.Path, "stack", string(debug.Stack()))
+
+			// response already (partially) sent
+			if recorder.status != 0 {
+				panic(http.ErrAbortHandler)
+			}
+
+			errorResponse := ErrorResponse{Type: TypeErrorResponse}
+			errorResponse.Attributes.IsError = true
+			errorResponse.Attributes.Error = newErrorObject(EndpointService, ReasonInternalServerError,
+				"unexpected error while processing request, please report request id: "+requestID)
+			errorResponse.Attributes.RequestID = requestID
+			writer.Header().Del("Content-Encoding")
+			streamJSONResponse(writer, request, http.StatusInternalServerError, errorResponse, false)
+		}()
+		next.ServeHTTP(recorder, request)
+	})
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// OpenAPIEndpoint describes one API endpoint for the OpenAPI document.
+type OpenAPIEndpoint struct {
+	Path     string
+	Summary  string
+	Request  any
+	Response any
+}
+
+// openAPIEndpoints lists all endpoints (request and response types) published in the OpenAPI document
+var openAPIEndpoints = []OpenAPIEndpoint{
+	{"/v1/point", "Elevation for WGS84 coordinate", PointRequest{}, PointResponse{}},
+	{"/v1/utmpoint", "Elevation for UTM coordinate", UTMPointRequest{}, UTMPointResponse{}},
+	{"/v1/gpx", "Elevations for all points of a GPX file", GPXRequest{}, GPXResponse{}},
+	{"/v1/gpxanalyze", "Analysis of a GPX file", GPXAnalyzeRequest{}, GPXAnalyzeResponse{}},
+	{"/v1/contours", "Contour lines for tile or area", ContoursRequest{}, ContoursResponse{}},
+	{"/v1/hillshade", "Hillshade for tile", HillshadeRequest{}, HillshadeResponse{}},
+	{"/v1/slope", "Slope for tile", SlopeRequest{}, SlopeResponse{}},
+	{"/v1/aspect", "Aspect for tile", AspectRequest{}, AspectResponse{}},
+	{"/v1/tpi", "Topographic Position Index for tile", TPIRequest{}, TPIResponse{}},
+	{"/v1/tri", "Terrain Ruggedness Index for tile", TRIRequest{}, TRIResponse{}},
+	{"/v1/roughness", "Roughness for tile", RoughnessRequest{}, RoughnessResponse{}},
+	{"/v1/rawtif", "Raw GeoTIFF for tile", RawTIFRequest{}, RawTIFResponse{}},
+	{"/v1/colorrelief", "Color relief for tile", ColorReliefRequest{}, ColorReliefResponse{}},
+	{"/v1/histogram", "Elevation histogram for tile", HistogramRequest{}, HistogramResponse{}},
+	{"/v1/elevationprofile", "Elevation profile between two points", ElevationProfileRequest{}, ElevationProfileResponse{}},
+	{"/v1/visualize", "Visualization (slope, aspect, tri, tpi, roughness, hillshade, color relief) for tile", VisualizeRequest{}, VisualizeResponse{}},
+	{"/v1/compare", "Elevation of DGM1 and reference DEM (e.g. Copernicus GLO-30) for WGS84 coordinate", CompareRequest{}, CompareResponse{}},
+	{"/v1/aspectrose", "Aspect rose and slope distribution for polygon (across tiles)", AspectRoseRequest{}, AspectRoseResponse{}},
+	{"/v1/elevationrange", "Terrain polygons within elevation band around center point (across tiles)", ElevationRangeRequest{}, ElevationRangeResponse{}},
+	{"/v1/contourline", "Contour line passing through point within radius (across tiles)", ContourLineRequest{}, ContourLineResponse{}},
+	{"/v1/elevationmatrix", "Grid (matrix) of elevations for bounding box at requested resolution (across tiles)", ElevationMatrixRequest{}, ElevationMatrixResponse{}},
+	{"/v1/legend", "Legend (color bar with labels) for color text file or named color ramp", LegendRequest{}, LegendResponse{}},
+	{"/v1/jobs", "Asynchronous job (request for another endpoint, e.g. large areas)", JobRequest{}, JobResponse{}},
+}
+
+// openAPIDocument holds the serialized OpenAPI document (generated once at startup)
+var openAPIDocument []byte
+
+/*
+initOpenAPIDocument generates and serializes the OpenAPI document for all endpoints.
+*/
+func initOpenAPIDocument() error {
+	document := buildOpenAPIDocument(openAPIEndpoints)
+
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error [%w] at json.MarshalIndent()", err)
+	}
+	openAPIDocument = data
+
+	return nil
+}
+
+/*
+buildOpenAPIDocument builds an OpenAPI 3.1 document. The schemas are derived from the Go request and
+response types via reflection, so the document always matches the current implementation.
+*/
+func buildOpenAPIDocument(endpoints []OpenAPIEndpoint) map[string]any {
+	schemas := make(map[string]any)
+	paths := make(map[string]any)
+
+	for _, endpoint := range endpoints {
+		requestSchema := buildOpenAPISchema(reflect.TypeOf(endpoint.Request), schemas)
+		responseSchema := buildOpenAPISchema(reflect.TypeOf(endpoint.Response), schemas)
+		jsonContent := func(schema map[string]any) map[string]any {
+			return map[string]any{"application/json": map[string]any{"schema": schema}}
+		}
+		operationID := strings.TrimPrefix(endpoint.Path, "/v1/")
+
+		paths[endpoint.Path] = map[string]any{
+			"post": map[string]any{
+				"operationId": operationID,
+				"summary":     endpoint.Summary,
+				"requestBody": map[string]any{
+					"required": true,
+					"content":  jsonContent(requestSchema),
+				},
+				"responses": map[string]any{
+					"200": map[string]any{"description": "successful response", "content": jsonContent(responseSchema)},
+					"304": map[string]any{"description": "not modified (If-None-Match)"},
+					"400": map[string]any{"description": "invalid request (see Attributes.Error)", "content": jsonContent(responseSchema)},
+					"413": map[string]any{"description": "request body too large", "content": jsonContent(responseSchema)},
+					"429": map[string]any{"description": "server busy (see Retry-After)", "content": jsonContent(responseSchema)},
+					"500": map[string]any{"description": "internal error (see Attributes.Error)", "content": jsonContent(responseSchema)},
+					"503": map[string]any{"description": "elevation source unavailable", "content": jsonContent(responseSchema)},
+				},
+			},
+		}
+	}
+
+	return map[string]any{
+		"openapi": "3.1.0",
+		"info": map[string]any{
+			"title":       "DTM Elevation Service",
+			"description": "Service for determining elevation information based on accurate DTM (Digital Terrain Model) data.",
+			"version":     progVersion,
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": schemas,
+		},
+	}
+}
+
+/*
+buildOpenAPISchema returns the JSON schema for a Go type. Named struct types are added to the
+component schemas and referenced, anonymous struct types are inlined.
+*/
+func buildOpenAPISchema(goType reflect.Type, schemas map[string]any) map[string]any {
+	// special types
+	switch goType {
+	case reflect.TypeOf(time.Time{}):
+		return map[string]any{"type": "string", "format": "date-time"}
+	case reflect.TypeOf([]byte{}):
+		return map[string]any{"type": "string", "contentEncoding": "base64"}
+	case reflect.TypeOf(VerticalExaggeration(0)):
+		return map[string]any{"oneOf": []any{map[string]any{"type": "number", "format": "double"}, map[string]any{"type": "string", "enum": []any{"auto"}}}}
+	}
+
+	switch goType.Kind() {
+	case reflect.Pointer:
+		schema := buildOpenAPISchema(goType.Elem(), schemas)
+		return map[string]any{"oneOf": []any{schema, map[string]any{"type": "null"}}}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]any{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Float32:
+		return map[string]any{"type": "number", "format": "float"}
+	case reflect.Float64:
+		return map[string]any{"type": "number", "format": "double"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": buildOpenAPISchema(goType.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": buildOpenAPISchema(goType.Elem(), schemas)}
+	case reflect.Struct:
+		if goType.Name() == "" {
+			return buildOpenAPIObjectSchema(goType, schemas)
+		}
+		reference := map[string]any{"$ref": "#/components/schemas/" + goType.Name()}
+		if _, found := schemas[goType.Name()]; !found {
+			// placeholder prevents endless recursion for self-referencing types
+			schemas[goType.Name()] = map[string]any{}
+			schemas[goType.Name()] = buildOpenAPIObjectSchema(goType, schemas)
+		}
+		return reference
+	default:
+		// e.g. interface types
+		return map[string]any{}
+	}
+}
+
+/*
+buildOpenAPIObjectSchema returns the JSON object schema for a Go struct type (respecting json tags).
+*/
+func buildOpenAPIObjectSchema(goType reflect.Type, schemas map[string]any) map[string]any {
+	properties := make(map[string]any)
+	for i := 0; i < goType.NumField(); i++ {
+		field := goType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		tagName, _, _ := strings.Cut(tag, ",")
+		if tagName != "" {
+			name = tagName
+		}
+		properties[name] = buildOpenAPISchema(field.Type, schemas)
+	}
+	return map[string]any{"type": "object", "properties": properties}
+}
+
+/*
+openAPIRequest serves the OpenAPI document (e.g. for client SDK generation or interactive try-outs).
+*/
+func openAPIRequest(writer http.ResponseWriter, _ *http.Request) {
+	writer.Header().Set("Content-Type", JSONAPIMediaType)
+	writer.WriteHeader(http.StatusOK)
+	_, err := writer.Write(openAPIDocument)
+	if err != nil {
+		slog.Error("error writing OpenAPI document", "error", err)
+	}
+}
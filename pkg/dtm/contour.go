@@ -0,0 +1,414 @@
+package dtm
+
+/*
+#cgo pkg-config: gdal
+#include <stdlib.h>
+#include "gdal.h"
+#include "gdal_alg.h"
+#include "ogr_api.h"
+#include "cpl_conv.h"
+#include "cpl_error.h"
+#include "cpl_string.h"
+
+// contourProgress aborts contour generation if the cancel flag is set.
+static int CPL_STDCALL contourProgress(double complete, const char *message, void *cancelFlag) {
+	(void)complete;
+	(void)message;
+	return *(volatile int *)cancelFlag == 0;
+}
+
+// contourTransformElevations transforms the elevation attribute of all contour lines: value = (value + offset) * scale,
+// rounded to the given number of decimal places (precision < 0 = not rounded).
+// Returns NULL on success, otherwise an error message (to be freed with VSIFree).
+static char *contourTransformElevations(const char *dstPath, const char *attributeName, double offset, double scale, int precision) {
+	CPLErrorReset();
+
+	GDALDatasetH dstDS = GDALOpenEx(dstPath, GDAL_OF_VECTOR | GDAL_OF_UPDATE, NULL, NULL, NULL);
+	if (dstDS == NULL) {
+		return CPLStrdup(CPLGetLastErrorMsg());
+	}
+	OGRLayerH layer = GDALDatasetGetLayer(dstDS, 0);
+	int fieldIndex = OGR_FD_GetFieldIndex(OGR_L_GetLayerDefn(layer), attributeName);
+	if (fieldIndex < 0) {
+		GDALClose(dstDS);
+		return CPLStrdup("elevation attribute not found");
+	}
+
+	double factor = 1.0;
+	for (int i = 0; i < precision; i++) {
+		factor *= 10.0;
+	}
+
+	OGRFeatureH feature;
+	OGR_L_ResetReading(layer);
+	while ((feature = OGR_L_GetNextFeature(layer)) != NULL) {
+		double value = (OGR_F_GetFieldAsDouble(feature, fieldIndex) + offset) * scale;
+		if (precision >= 0) {
+			value = (double)(long long)(value * factor + (value < 0 ? -0.5 : 0.5)) / factor;
+		}
+		OGR_F_SetFieldDouble(feature, fieldIndex, value);
+		OGRErr err = OGR_L_SetFeature(layer, feature);
+		OGR_F_Destroy(feature);
+		if (err != OGRERR_NONE) {
+			GDALClose(dstDS);
+			return CPLStrdup(CPLGetLastErrorMsg());
+		}
+	}
+
+	GDALClose(dstDS);
+	return NULL;
+}
+
+// contourGenerate mimics 'gdal_contour -f GeoJSON -i interval -off base -nln layerName -a attributeName'.
+// Returns NULL on success, otherwise an error message (to be freed with VSIFree).
+static char *contourGenerate(const char *srcPath, const char *dstPath, const char *layerName,
+	const char *attributeName, double interval, double base, int *cancelFlag) {
+	CPLErrorReset();
+
+	GDALDatasetH srcDS = GDALOpenEx(srcPath, GDAL_OF_RASTER | GDAL_OF_READONLY, NULL, NULL, NULL);
+	if (srcDS == NULL) {
+		return CPLStrdup(CPLGetLastErrorMsg());
+	}
+	GDALRasterBandH band = GDALGetRasterBand(srcDS, 1);
+
+	GDALDriverH driver = GDALGetDriverByName("GeoJSON");
+	if (driver == NULL) {
+		GDALClose(srcDS);
+		return CPLStrdup("GeoJSON driver not available");
+	}
+	GDALDatasetH dstDS = GDALCreate(driver, dstPath, 0, 0, 0, GDT_Unknown, NULL);
+	if (dstDS == NULL) {
+		GDALClose(srcDS);
+		return CPLStrdup(CPLGetLastErrorMsg());
+	}
+
+	OGRLayerH layer = GDALDatasetCreateLayer(dstDS, layerName, GDALGetSpatialRef(srcDS), wkbLineString, NULL);
+	if (layer == NULL) {
+		GDALClose(dstDS);
+		GDALClose(srcDS);
+		return CPLStrdup(CPLGetLastErrorMsg());
+	}
+
+	OGRFieldDefnH field = OGR_Fld_Create("ID", OFTInteger);
+	OGR_Fld_SetWidth(field, 8);
+	OGR_L_CreateField(layer, field, FALSE);
+	OGR_Fld_Destroy(field);
+
+	field = OGR_Fld_Create(attributeName, OFTReal);
+	OGR_Fld_SetWidth(field, 12);
+	OGR_Fld_SetPrecision(field, 3);
+	OGR_L_CreateField(layer, field, FALSE);
+	OGR_Fld_Destroy(field);
+
+	char **options = NULL;
+	options = CSLAddString(options, CPLSPrintf("LEVEL_INTERVAL=%.17g", interval));
+	options = CSLAddString(options, CPLSPrintf("LEVEL_BASE=%.17g", base));
+	options = CSLAddString(options, "ID_FIELD=0");
+	options = CSLAddString(options, "ELEV_FIELD=1");
+	int hasNoData = FALSE;
+	double noData = GDALGetRasterNoDataValue(band, &hasNoData);
+	if (hasNoData) {
+		options = CSLAddString(options, CPLSPrintf("NODATA=%.17g", noData));
+	}
+
+	CPLErr err = GDALContourGenerateEx(band, layer, options, contourProgress, cancelFlag);
+	CSLDestroy(options);
+
+	GDALClose(dstDS);
+	GDALClose(srcDS);
+
+	if (err != CE_None) {
+		return CPLStrdup(CPLGetLastErrorMsg());
+	}
+	return NULL;
+}
+
+// contourPolygonGenerate mimics 'gdal_contour -f GeoJSON -p -fl minLevel maxLevel -nln layerName -amin minName -amax maxName'.
+// Returns NULL on success, otherwise an error message (to be freed with VSIFree).
+static char *contourPolygonGenerate(const char *srcPath, const char *dstPath, const char *layerName,
+	const char *minAttributeName, const char *maxAttributeName, double minLevel, double maxLevel, int *cancelFlag) {
+	CPLErrorReset();
+
+	GDALDatasetH srcDS = GDALOpenEx(srcPath, GDAL_OF_RASTER | GDAL_OF_READONLY, NULL, NULL, NULL);
+	if (srcDS == NULL) {
+		return CPLStrdup(CPLGetLastErrorMsg());
+	}
+	GDALRasterBandH band = GDALGetRasterBand(srcDS, 1);
+
+	GDALDriverH driver = GDALGetDriverByName("GeoJSON");
+	if (driver == NULL) {
+		GDALClose(srcDS);
+		return CPLStrdup("GeoJSON driver not available");
+	}
+	GDALDatasetH dstDS = GDALCreate(driver, dstPath, 0, 0, 0, GDT_Unknown, NULL);
+	if (dstDS == NULL) {
+		GDALClose(srcDS);
+		return CPLStrdup(CPLGetLastErrorMsg());
+	}
+
+	OGRLayerH layer = GDALDatasetCreateLayer(dstDS, layerName, GDALGetSpatialRef(srcDS), wkbMultiPolygon, NULL);
+	if (layer == NULL) {
+		GDALClose(dstDS);
+		GDALClose(srcDS);
+		return CPLStrdup(CPLGetLastErrorMsg());
+	}
+
+	OGRFieldDefnH field = OGR_Fld_Create("ID", OFTInteger);
+	OGR_Fld_SetWidth(field, 8);
+	OGR_L_CreateField(layer, field, FALSE);
+	OGR_Fld_Destroy(field);
+
+	field = OGR_Fld_Create(minAttributeName, OFTReal);
+	OGR_Fld_SetWidth(field, 12);
+	OGR_Fld_SetPrecision(field, 3);
+	OGR_L_CreateField(layer, field, FALSE);
+	OGR_Fld_Destroy(field);
+
+	field = OGR_Fld_Create(maxAttributeName, OFTReal);
+	OGR_Fld_SetWidth(field, 12);
+	OGR_Fld_SetPrecision(field, 3);
+	OGR_L_CreateField(layer, field, FALSE);
+	OGR_Fld_Destroy(field);
+
+	char **options = NULL;
+	options = CSLAddString(options, CPLSPrintf("FIXED_LEVELS=%.17g,%.17g", minLevel, maxLevel));
+	options = CSLAddString(options, "POLYGONIZE=YES");
+	options = CSLAddString(options, "ID_FIELD=0");
+	options = CSLAddString(options, "ELEV_FIELD_MIN=1");
+	options = CSLAddString(options, "ELEV_FIELD_MAX=2");
+	int hasNoData = FALSE;
+	double noData = GDALGetRasterNoDataValue(band, &hasNoData);
+	if (hasNoData) {
+		options = CSLAddString(options, CPLSPrintf("NODATA=%.17g", noData));
+	}
+
+	CPLErr err = GDALContourGenerateEx(band, layer, options, contourProgress, cancelFlag);
+	CSLDestroy(options);
+
+	GDALClose(dstDS);
+	GDALClose(srcDS);
+
+	if (err != CE_None) {
+		return CPLStrdup(CPLGetLastErrorMsg());
+	}
+	return NULL;
+}
+*/
+import "C"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+// ContourAttribute represents the elevation attribute of contour lines (name, unit and rounding).
+type ContourAttribute struct {
+	Name          string  // e.g. Hoehe, ELEV
+	MetersPerUnit float64 // length of the attribute unit in meters (e.g. 0.3048 for ft)
+	Precision     int     // decimal places (-1 = not rounded)
+}
+
+/*
+Contour generates contour lines (GeoJSON) in-process, equivalent to:
+gdal_contour -f GeoJSON -i interval -nln layerName -a attributeName inputFile outputFile
+Note: godal does not wrap GDALContourGenerateEx(), therefore the GDAL C API is called directly.
+The generation is aborted if the context is canceled (e.g. client disconnected).
+The vertical offset (e.g. -312.5 for heights relative to a local reference level of 312.5 m) is added to the
+elevations before contouring: contour levels are multiples of interval in shifted heights, the elevation attribute
+contains shifted heights.
+The interval and the elevation attribute are given in the unit of the attribute (e.g. feet), the vertical offset
+is always given in meters.
+*/
+func Contour(ctx context.Context, inputFile, outputFile, layerName string, attribute ContourAttribute, interval float64, verticalOffset float64) error {
+	// limit concurrent GDAL processing
+	err := acquireJobSlot(ctx)
+	if err != nil {
+		return err
+	}
+	defer releaseJobSlot(ctx)
+
+	cInputFile := C.CString(inputFile)
+	defer C.free(unsafe.Pointer(cInputFile))
+	cOutputFile := C.CString(outputFile)
+	defer C.free(unsafe.Pointer(cOutputFile))
+	cLayerName := C.CString(layerName)
+	defer C.free(unsafe.Pointer(cLayerName))
+	cAttributeName := C.CString(attribute.Name)
+	defer C.free(unsafe.Pointer(cAttributeName))
+
+	// cancel flag (C memory) is set when context is canceled, checked by progress callback
+	cancelFlag := (*C.int)(C.calloc(1, C.size_t(unsafe.Sizeof(C.int(0)))))
+	defer C.free(unsafe.Pointer(cancelFlag))
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		select {
+		case <-ctx.Done():
+			*cancelFlag = 1
+		case <-done:
+		}
+	}()
+
+	// contour levels in meters (elevations of raster), unit defaults to meters
+	metersPerUnit := attribute.MetersPerUnit
+	if metersPerUnit == 0 {
+		metersPerUnit = 1.0
+	}
+	errorMessage := C.contourGenerate(cInputFile, cOutputFile, cLayerName, cAttributeName, C.double(interval*metersPerUnit),
+		C.double(-verticalOffset), cancelFlag)
+	close(done)
+	<-finished
+	if ctx.Err() != nil {
+		if errorMessage != nil {
+			C.VSIFree(unsafe.Pointer(errorMessage))
+		}
+		return ctx.Err()
+	}
+	if errorMessage != nil {
+		defer C.VSIFree(unsafe.Pointer(errorMessage))
+		message := C.GoString(errorMessage)
+		if message == "" {
+			message = "unknown error"
+		}
+		return fmt.Errorf("error [%w] at contourGenerate(), file: %s", errors.New(message), inputFile)
+	}
+
+	// shift elevation attribute by vertical offset, convert to unit and round
+	if verticalOffset != 0 || metersPerUnit != 1.0 || attribute.Precision >= 0 {
+		errorMessage = C.contourTransformElevations(cOutputFile, cAttributeName, C.double(verticalOffset), C.double(1.0/metersPerUnit),
+			C.int(attribute.Precision))
+		if errorMessage != nil {
+			defer C.VSIFree(unsafe.Pointer(errorMessage))
+			message := C.GoString(errorMessage)
+			if message == "" {
+				message = "unknown error"
+			}
+			return fmt.Errorf("error [%w] at contourTransformElevations(), file: %s", errors.New(message), outputFile)
+		}
+	}
+
+	return nil
+}
+
+/*
+ContourPolygons generates contour polygons (GeoJSON) for the elevation bands separated by minLevel and maxLevel
+in-process, equivalent to:
+gdal_contour -f GeoJSON -p -fl minLevel maxLevel -nln layerName -amin minAttributeName -amax maxAttributeName inputFile outputFile
+The generation is aborted if the context is canceled (e.g. client disconnected).
+*/
+func ContourPolygons(ctx context.Context, inputFile, outputFile, layerName, minAttributeName, maxAttributeName string, minLevel, maxLevel float64) error {
+	// limit concurrent GDAL processing
+	err := acquireJobSlot(ctx)
+	if err != nil {
+		return err
+	}
+	defer releaseJobSlot(ctx)
+
+	cInputFile := C.CString(inputFile)
+	defer C.free(unsafe.Pointer(cInputFile))
+	cOutputFile := C.CString(outputFile)
+	defer C.free(unsafe.Pointer(cOutputFile))
+	cLayerName := C.CString(layerName)
+	defer C.free(unsafe.Pointer(cLayerName))
+	cMinAttributeName := C.CString(minAttributeName)
+	defer C.free(unsafe.Pointer(cMinAttributeName))
+	cMaxAttributeName := C.CString(maxAttributeName)
+	defer C.free(unsafe.Pointer(cMaxAttributeName))
+
+	// cancel flag (C memory) is set when context is canceled, checked by progress callback
+	cancelFlag := (*C.int)(C.calloc(1, C.size_t(unsafe.Sizeof(C.int(0)))))
+	defer C.free(unsafe.Pointer(cancelFlag))
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		select {
+		case <-ctx.Done():
+			*cancelFlag = 1
+		case <-done:
+		}
+	}()
+
+	errorMessage := C.contourPolygonGenerate(cInputFile, cOutputFile, cLayerName, cMinAttributeName, cMaxAttributeName,
+		C.double(minLevel), C.double(maxLevel), cancelFlag)
+	close(done)
+	<-finished
+	if ctx.Err() != nil {
+		if errorMessage != nil {
+			C.VSIFree(unsafe.Pointer(errorMessage))
+		}
+		return ctx.Err()
+	}
+	if errorMessage != nil {
+		defer C.VSIFree(unsafe.Pointer(errorMessage))
+		message := C.GoString(errorMessage)
+		if message == "" {
+			message = "unknown error"
+		}
+		return fmt.Errorf("error [%w] at contourPolygonGenerate(), file: %s", errors.New(message), inputFile)
+	}
+
+	return nil
+}
+
+/*
+ContourLevel generates the contour lines (GeoJSON) of one single elevation level in-process, equivalent to:
+gdal_contour -f GeoJSON -fl level -nln layerName -a attributeName inputFile outputFile
+Note: A single level is generated as level base with an interval exceeding all elevations (no further levels).
+The generation is aborted if the context is canceled (e.g. client disconnected).
+*/
+func ContourLevel(ctx context.Context, inputFile, outputFile, layerName, attributeName string, level float64) error {
+	// limit concurrent GDAL processing
+	err := acquireJobSlot(ctx)
+	if err != nil {
+		return err
+	}
+	defer releaseJobSlot(ctx)
+
+	cInputFile := C.CString(inputFile)
+	defer C.free(unsafe.Pointer(cInputFile))
+	cOutputFile := C.CString(outputFile)
+	defer C.free(unsafe.Pointer(cOutputFile))
+	cLayerName := C.CString(layerName)
+	defer C.free(unsafe.Pointer(cLayerName))
+	cAttributeName := C.CString(attributeName)
+	defer C.free(unsafe.Pointer(cAttributeName))
+
+	// cancel flag (C memory) is set when context is canceled, checked by progress callback
+	cancelFlag := (*C.int)(C.calloc(1, C.size_t(unsafe.Sizeof(C.int(0)))))
+	defer C.free(unsafe.Pointer(cancelFlag))
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		select {
+		case <-ctx.Done():
+			*cancelFlag = 1
+		case <-done:
+		}
+	}()
+
+	// interval (100 km) exceeds range of terrestrial elevations, only 'level' is generated
+	errorMessage := C.contourGenerate(cInputFile, cOutputFile, cLayerName, cAttributeName, C.double(100000.0), C.double(level), cancelFlag)
+	close(done)
+	<-finished
+	if ctx.Err() != nil {
+		if errorMessage != nil {
+			C.VSIFree(unsafe.Pointer(errorMessage))
+		}
+		return ctx.Err()
+	}
+	if errorMessage != nil {
+		defer C.VSIFree(unsafe.Pointer(errorMessage))
+		message := C.GoString(errorMessage)
+		if message == "" {
+			message = "unknown error"
+		}
+		return fmt.Errorf("error [%w] at contourGenerate(), file: %s", errors.New(message), inputFile)
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+/*
+Package dtm provides the core of the DTM elevation service as importable library: repository of
+GeoTIFF tiles (digital terrain model, 1 m grid, UTM zones 32 and 33), tile lookup and elevation
+sampling. Other Go programs can embed it without running the HTTP service.
+
+Example:
+
+	repository, _, err := dtm.BuildRepository([]string{"/data/dgm1-nw.json"}, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	elevation, tile, err := repository.ElevationUTM(32, 469815.0, 5678042.0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%.2f m (tile %s, source %s)\n", elevation, tile.Index, tile.Source)
+
+Product generation runs in-process with the GDAL library: processing primitives (Dem, Warp, Translate, BuildVRT,
+Rasterize, VectorTranslate), contour generation (Contour, ContourPolygons, ContourLevel) and colored terrain
+products per tile (GenerateProduct, e.g. slope, aspect, TPI, TRI, roughness). Concurrent processing can be limited
+with SetJobLimiter.
+
+The HTTP service (package main) is a layer on top of this package. It adds request handling, response cache,
+attribution, multi-tile areas, water masking and products that depend on further service data (e.g. hillshade
+variants, color relief with hillshade blending).
+*/
+package dtm
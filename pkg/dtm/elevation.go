@@ -0,0 +1,198 @@
+package dtm
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/airbusgeo/godal"
+)
+
+// ErrTileNotFound is returned if no tile exists for the coordinates (outside data coverage).
+var ErrTileNotFound = errors.New("tile not found")
+
+/*
+ElevationFromFile retrieves the elevation value from a GeoTIFF DGM file for a given UTM coordinate.
+
+Input:
+  - xUTM, yUTM: The UTM coordinates (Easting, Northing).
+    These coordinates MUST be in the SAME Coordinate Reference System (CRS) as the provided GeoTIFF file.
+  - filename: Path to the GeoTIFF file containing elevation data (e.g., DGM1).
+
+Output:
+- elevation: The elevation value at the specified coordinates (typically in meters).
+- err: if
+  - the file cannot be opened
+  - the coordinates are outside the file's extent
+  - the coordinate system is rotated (not supported by this simple implementation),
+  - the pixel value is the NoData value
+  - or any other reading error occurs.
+*/
+func ElevationFromFile(xUTM, yUTM float64, filename string) (elevation float64, err error) {
+	// check if file exists
+	if info, statErr := os.Stat(filename); statErr != nil || info.IsDir() {
+		err = fmt.Errorf("file [%s] does not exist", filename)
+		return
+	}
+
+	// open the raster file in ReadOnly mode
+	dataset, err := godal.Open(filename)
+	if err != nil {
+		err = fmt.Errorf("error opening file [%s]: %w", filename, err)
+		return
+	}
+	defer dataset.Close()
+
+	// get geotransform parameters
+	gt, err := dataset.GeoTransform()
+	if err != nil {
+		err = fmt.Errorf("error getting geotransform from [%s]: %w", filename, err)
+		return
+	}
+
+	// basic check for rotation / skewing (this implementation assumes a north-up image)
+	// gt[2] and gt[4] should be 0 for a standard non-rotated/non-skewed grid
+	if gt[2] != 0.0 || gt[4] != 0.0 {
+		err = fmt.Errorf("raster [%s] appears to be rotated or skewed (gt[2]=%f, gt[4]=%f)", filename, gt[2], gt[4])
+		return
+	}
+
+	// calculate pixel coordinates from UTM coordinates using the inverse geotransform
+	// For non-rotated images:
+	// xUTM = gt[0] + col * gt[1] + row * gt[2]  (gt[2] is 0)
+	// yUTM = gt[3] + col * gt[4] + row * gt[5]  (gt[4] is 0)
+	// --> col = (xUTM - gt[0]) / gt[1]
+	// --> row = (yUTM - gt[3]) / gt[5]
+	// Note: Pixel height gt[5] is usually negative.
+
+	if gt[1] == 0 || gt[5] == 0 {
+		err = fmt.Errorf("invalid geotransform: pixel width (gt[1]=%f) or height (gt[5]=%f) is zero", gt[1], gt[5])
+		return
+	}
+
+	colF := (xUTM - gt[0]) / gt[1]
+	rowF := (yUTM - gt[3]) / gt[5]
+
+	// get raster size
+	structure := dataset.Structure()
+	rasterWidth := structure.SizeX
+	rasterHeight := structure.SizeY
+
+	// convert float pixel coordinates to integer indices (top-left corner of the pixel)
+	col := int(math.Floor(colF))
+	row := int(math.Floor(rowF))
+
+	// check if the calculated pixel coordinates are within the raster bounds
+	if col < 0 || col >= rasterWidth || row < 0 || row >= rasterHeight {
+		err = fmt.Errorf("coordinate (%.3f, %.3f) is outside the raster bounds [%s] (pixel %d, %d)", xUTM, yUTM, filename, col, row)
+		return
+	}
+
+	// get the first raster band (assuming elevation is in the first band)
+	bands := dataset.Bands()
+	if len(bands) == 0 {
+		err = fmt.Errorf("no raster bands found in file [%s]", filename)
+		return
+	}
+	band := bands[0]
+	bandStructure := band.Structure()
+
+	// read the single pixel value at (col, row); create a buffer of appropriate data type to hold the pixel value
+	var pixelValue float64 // use float64 for intermediate storage
+
+	switch bandStructure.DataType {
+	case godal.Byte:
+		buffer := make([]byte, 1)
+		if err = band.Read(col, row, buffer, 1, 1); err != nil {
+			err = fmt.Errorf("error reading pixel (%d, %d) as Byte: %w", col, row, err)
+			return
+		}
+		pixelValue = float64(buffer[0])
+	case godal.Int16:
+		buffer := make([]int16, 1)
+		if err = band.Read(col, row, buffer, 1, 1); err != nil {
+			err = fmt.Errorf("error reading pixel (%d, %d) as Int16: %w", col, row, err)
+			return
+		}
+		pixelValue = float64(buffer[0])
+	case godal.UInt16:
+		buffer := make([]uint16, 1)
+		if err = band.Read(col, row, buffer, 1, 1); err != nil {
+			err = fmt.Errorf("error reading pixel (%d, %d) as UInt16: %w", col, row, err)
+			return
+		}
+		pixelValue = float64(buffer[0])
+	case godal.Int32:
+		buffer := make([]int32, 1)
+		if err = band.Read(col, row, buffer, 1, 1); err != nil {
+			err = fmt.Errorf("error reading pixel (%d, %d) as Int32: %w", col, row, err)
+			return
+		}
+		pixelValue = float64(buffer[0])
+	case godal.UInt32:
+		buffer := make([]uint32, 1)
+		if err = band.Read(col, row, buffer, 1, 1); err != nil {
+			err = fmt.Errorf("error reading pixel (%d, %d) as UInt32: %w", col, row, err)
+			return
+		}
+		pixelValue = float64(buffer[0])
+	case godal.Float32:
+		buffer := make([]float32, 1)
+		if err = band.Read(col, row, buffer, 1, 1); err != nil {
+			err = fmt.Errorf("error reading pixel (%d, %d) as Float32: %w", col, row, err)
+			return
+		}
+		pixelValue = float64(buffer[0])
+	case godal.Float64:
+		buffer := make([]float64, 1)
+		if err = band.Read(col, row, buffer, 1, 1); err != nil {
+			err = fmt.Errorf("error reading pixel (%d, %d) as Float64: %w", col, row, err)
+			return
+		}
+		pixelValue = buffer[0]
+	default:
+		err = fmt.Errorf("unsupported data type '%s' for band 1 in file [%s]", bandStructure.DataType, filename)
+		return
+	}
+
+	// check if the read value is the NoData value
+	if nodata, ok := band.NoData(); ok {
+		// compare floating point numbers with a small tolerance if needed, but direct comparison often works for NoData values
+		if pixelValue == nodata {
+			err = fmt.Errorf("coordinate (%.3f, %.3f) corresponds to a NoData value (%.3f) in [%s]", xUTM, yUTM, nodata, filename)
+			return
+		}
+	}
+
+	// assign the result to the return variable
+	elevation = pixelValue
+
+	return // return named results (elevation, err)
+}
+
+/*
+ElevationUTM retrieves the elevation for UTM coordinates (zone 32 or 33). If the primary tile has no data
+(-9999, e.g. beyond the border of a federal state), the secondary and tertiary tiles are used.
+*/
+func (r *Repository) ElevationUTM(zone int, easting float64, northing float64) (float64, TileMetadata, error) {
+	tiles := r.TilesUTM(zone, easting, northing)
+	if len(tiles) == 0 {
+		return 0, TileMetadata{}, fmt.Errorf("%w: %s", ErrTileNotFound, TileIndex(zone, easting, northing, 1))
+	}
+
+	var err error
+	for _, tile := range tiles {
+		var elevation float64
+		elevation, err = ElevationFromFile(easting, northing, tile.Path)
+		if err != nil {
+			continue
+		}
+		// -9999.0 = no data
+		if elevation > -9998.9 {
+			return elevation, tile, nil
+		}
+		err = fmt.Errorf("no data for UTM easting: %.3f, northing: %.3f, zone: %d", easting, northing, zone)
+	}
+	return 0, tiles[0], err
+}
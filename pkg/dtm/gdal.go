@@ -0,0 +1,266 @@
+package dtm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	"github.com/airbusgeo/godal"
+)
+
+// JobLimiter limits the number of concurrent GDAL processing jobs (e.g. job queue of a service).
+type JobLimiter interface {
+	Acquire(ctx context.Context) error // waits for a free processing slot
+	Release(ctx context.Context)       // releases the processing slot
+}
+
+// jobLimiter limits all GDAL processing jobs of this package (nil = not limited)
+var jobLimiter JobLimiter
+
+/*
+SetJobLimiter sets the limiter for all GDAL processing jobs of this package (nil = not limited).
+Must be called before the first processing job.
+*/
+func SetJobLimiter(limiter JobLimiter) {
+	jobLimiter = limiter
+}
+
+/*
+acquireJobSlot waits for a free processing slot (if a limiter is set).
+*/
+func acquireJobSlot(ctx context.Context) error {
+	if jobLimiter == nil {
+		return ctx.Err()
+	}
+	return jobLimiter.Acquire(ctx)
+}
+
+/*
+releaseJobSlot releases the processing slot (if a limiter is set).
+*/
+func releaseJobSlot(ctx context.Context) {
+	if jobLimiter != nil {
+		jobLimiter.Release(ctx)
+	}
+}
+
+// vsimemCounter makes in-memory (/vsimem) file names unique across concurrent requests
+var vsimemCounter uint64
+
+/*
+NewVSIMemPrefix returns a unique prefix for in-memory (/vsimem) files of one processing run.
+*/
+func NewVSIMemPrefix(name string) string {
+	return fmt.Sprintf("/vsimem/dtm-elevation-service-%s-%d-", name, atomic.AddUint64(&vsimemCounter, 1))
+}
+
+/*
+RemoveVSIMemFiles removes in-memory (/vsimem) files (nonexistent files are ignored).
+PAM sidecar files (e.g. *.png.aux.xml), written by GDAL for formats without embedded metadata, are removed as well.
+*/
+func RemoveVSIMemFiles(filenames ...string) {
+	for _, filename := range filenames {
+		_ = godal.VSIUnlink(filename)
+		_ = godal.VSIUnlink(filename + ".aux.xml")
+	}
+}
+
+/*
+ReadVSIMemFile reads the content of an in-memory (/vsimem) file.
+*/
+func ReadVSIMemFile(filename string) ([]byte, error) {
+	vsiFile, err := godal.VSIOpen(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error [%w] at godal.VSIOpen(), file: %s", err, filename)
+	}
+	defer vsiFile.Close()
+
+	data, err := io.ReadAll(vsiFile)
+	if err != nil {
+		return nil, fmt.Errorf("error [%w] at io.ReadAll(), file: %s", err, filename)
+	}
+
+	return data, nil
+}
+
+/*
+Dem runs a 'gdaldem' processing (e.g. hillshade, slope, color-relief) in-process.
+The colorTextFile must only be set for processing mode 'color-relief'.
+*/
+func Dem(ctx context.Context, processingMode, inputFile, colorTextFile, outputFile string, switches []string) error {
+	// limit concurrent GDAL processing
+	err := acquireJobSlot(ctx)
+	if err != nil {
+		return err
+	}
+	defer releaseJobSlot(ctx)
+
+	dataset, err := godal.Open(inputFile, godal.RasterOnly())
+	if err != nil {
+		return fmt.Errorf("error [%w] at godal.Open(), file: %s", err, inputFile)
+	}
+	defer dataset.Close()
+
+	result, err := dataset.Dem(outputFile, processingMode, colorTextFile, switches)
+	if err != nil {
+		return fmt.Errorf("error [%w] at dataset.Dem(), mode: %s, file: %s", err, processingMode, inputFile)
+	}
+
+	err = result.Close()
+	if err != nil {
+		return fmt.Errorf("error [%w] at result.Close(), file: %s", err, outputFile)
+	}
+
+	return nil
+}
+
+/*
+Warp runs a 'gdalwarp' reprojection in-process.
+*/
+func Warp(ctx context.Context, inputFile, outputFile string, switches []string) error {
+	// limit concurrent GDAL processing
+	err := acquireJobSlot(ctx)
+	if err != nil {
+		return err
+	}
+	defer releaseJobSlot(ctx)
+
+	dataset, err := godal.Open(inputFile, godal.RasterOnly())
+	if err != nil {
+		return fmt.Errorf("error [%w] at godal.Open(), file: %s", err, inputFile)
+	}
+	defer dataset.Close()
+
+	result, err := dataset.Warp(outputFile, switches)
+	if err != nil {
+		return fmt.Errorf("error [%w] at dataset.Warp(), file: %s", err, inputFile)
+	}
+
+	err = result.Close()
+	if err != nil {
+		return fmt.Errorf("error [%w] at result.Close(), file: %s", err, outputFile)
+	}
+
+	return nil
+}
+
+/*
+Translate runs a 'gdal_translate' conversion in-process.
+*/
+func Translate(ctx context.Context, inputFile, outputFile string, switches []string) error {
+	// limit concurrent GDAL processing
+	err := acquireJobSlot(ctx)
+	if err != nil {
+		return err
+	}
+	defer releaseJobSlot(ctx)
+
+	dataset, err := godal.Open(inputFile, godal.RasterOnly())
+	if err != nil {
+		return fmt.Errorf("error [%w] at godal.Open(), file: %s", err, inputFile)
+	}
+	defer dataset.Close()
+
+	result, err := dataset.Translate(outputFile, switches)
+	if err != nil {
+		return fmt.Errorf("error [%w] at dataset.Translate(), file: %s", err, inputFile)
+	}
+
+	err = result.Close()
+	if err != nil {
+		return fmt.Errorf("error [%w] at result.Close(), file: %s", err, outputFile)
+	}
+
+	return nil
+}
+
+/*
+BuildVRT runs a 'gdalbuildvrt' mosaic creation in-process.
+*/
+func BuildVRT(ctx context.Context, inputFiles []string, outputFile string, switches []string) error {
+	// limit concurrent GDAL processing
+	err := acquireJobSlot(ctx)
+	if err != nil {
+		return err
+	}
+	defer releaseJobSlot(ctx)
+
+	result, err := godal.BuildVRT(outputFile, inputFiles, switches)
+	if err != nil {
+		return fmt.Errorf("error [%w] at godal.BuildVRT(), file: %s", err, outputFile)
+	}
+
+	err = result.Close()
+	if err != nil {
+		return fmt.Errorf("error [%w] at result.Close(), file: %s", err, outputFile)
+	}
+
+	return nil
+}
+
+/*
+Rasterize runs a 'gdal_rasterize' burn of vector features into an existing raster file (updated in place).
+*/
+func Rasterize(ctx context.Context, vectorFile, rasterFile string, switches []string) error {
+	// limit concurrent GDAL processing
+	err := acquireJobSlot(ctx)
+	if err != nil {
+		return err
+	}
+	defer releaseJobSlot(ctx)
+
+	vectorDataset, err := godal.Open(vectorFile, godal.VectorOnly())
+	if err != nil {
+		return fmt.Errorf("error [%w] at godal.Open(), file: %s", err, vectorFile)
+	}
+	defer vectorDataset.Close()
+
+	rasterDataset, err := godal.Open(rasterFile, godal.RasterOnly(), godal.Update())
+	if err != nil {
+		return fmt.Errorf("error [%w] at godal.Open(), file: %s", err, rasterFile)
+	}
+
+	err = rasterDataset.RasterizeInto(vectorDataset, switches)
+	if err != nil {
+		_ = rasterDataset.Close()
+		return fmt.Errorf("error [%w] at dataset.RasterizeInto(), file: %s", err, rasterFile)
+	}
+
+	err = rasterDataset.Close()
+	if err != nil {
+		return fmt.Errorf("error [%w] at dataset.Close(), file: %s", err, rasterFile)
+	}
+
+	return nil
+}
+
+/*
+VectorTranslate runs an 'ogr2ogr' conversion in-process.
+*/
+func VectorTranslate(ctx context.Context, inputFile, outputFile string, switches []string) error {
+	// limit concurrent GDAL processing
+	err := acquireJobSlot(ctx)
+	if err != nil {
+		return err
+	}
+	defer releaseJobSlot(ctx)
+
+	dataset, err := godal.Open(inputFile, godal.VectorOnly())
+	if err != nil {
+		return fmt.Errorf("error [%w] at godal.Open(), file: %s", err, inputFile)
+	}
+	defer dataset.Close()
+
+	result, err := dataset.VectorTranslate(outputFile, switches)
+	if err != nil {
+		return fmt.Errorf("error [%w] at dataset.VectorTranslate(), file: %s", err, inputFile)
+	}
+
+	err = result.Close()
+	if err != nil {
+		return fmt.Errorf("error [%w] at result.Close(), file: %s", err, outputFile)
+	}
+
+	return nil
+}
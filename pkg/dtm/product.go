@@ -0,0 +1,84 @@
+package dtm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ProductOptions describes the generation of a colored terrain product (e.g. slope) from an elevation tile.
+type ProductOptions struct {
+	Name            string                                           // product name (e.g. slope), part of in-memory file names
+	Mode            string                                           // 'gdaldem' processing mode (e.g. slope, aspect, TPI, TRI, roughness)
+	ModeSwitches    []string                                         // 'gdaldem' switches of processing mode (e.g. -alg Horn -compute_edges)
+	ColorTextFile   string                                           // color text file for 'gdaldem color-relief'
+	NearestColor    bool                                             // nearest color entry instead of interpolated colors
+	OutputFormat    string                                           // geotiff (UTM) or png (Web Mercator)
+	CreationOptions []string                                         // GeoTIFF creation options (e.g. -co COMPRESS=DEFLATE)
+	PostProcess     func(ctx context.Context, filename string) error // optional, applied to native product (e.g. water masking)
+}
+
+/*
+GenerateProduct generates a colored terrain product (e.g. slope) for one elevation tile and returns the content of
+the product file. GeoTIFF products keep the UTM projection of the tile, PNG products are reprojected to Web Mercator
+(EPSG:3857). All intermediate files are processed in memory (/vsimem).
+*/
+func GenerateProduct(ctx context.Context, inputFile string, options ProductOptions) ([]byte, error) {
+	vsimemPrefix := NewVSIMemPrefix(options.Name)
+	utmGeoTIFF := vsimemPrefix + options.Name + ".utm.tif"
+	colorUTMGeoTIFF := vsimemPrefix + options.Name + ".color.utm.tif"
+	webmercatorGeoTIFF := vsimemPrefix + options.Name + ".webmercator.tif"
+	colorWebmercatorPNG := vsimemPrefix + options.Name + ".color.webmercator.png"
+	defer RemoveVSIMemFiles(utmGeoTIFF, colorUTMGeoTIFF, webmercatorGeoTIFF, colorWebmercatorPNG)
+
+	// 1. create native product with 'gdaldem'
+	// e.g. gdaldem slope dgm1_32_497_5670_1_he.tif 32_497_5670_hangneigung.utm.tif -alg Horn -compute_edges
+	err := Dem(ctx, options.Mode, inputFile, "", utmGeoTIFF, append([]string{"-of", "GTiff"}, options.ModeSwitches...))
+	if err != nil {
+		return nil, fmt.Errorf("error [%w] at Dem()", err)
+	}
+
+	// 1a. post-process native product (e.g. set water bodies to nodata)
+	if options.PostProcess != nil {
+		err = options.PostProcess(ctx, utmGeoTIFF)
+		if err != nil {
+			return nil, fmt.Errorf("error [%w] at PostProcess()", err)
+		}
+	}
+
+	var colorSwitches []string
+	if options.NearestColor {
+		colorSwitches = append(colorSwitches, "-nearest_color_entry")
+	}
+
+	switch strings.ToLower(options.OutputFormat) {
+	case "geotiff":
+		// 2. colorize product with 'gdaldem color-relief'
+		switches := append([]string{"-of", "GTiff", "-alpha"}, options.CreationOptions...)
+		err = Dem(ctx, "color-relief", utmGeoTIFF, options.ColorTextFile, colorUTMGeoTIFF, append(switches, colorSwitches...))
+		if err != nil {
+			return nil, fmt.Errorf("error [%w] at Dem()", err)
+		}
+		return ReadVSIMemFile(colorUTMGeoTIFF)
+
+	case "png":
+		// 2. convert UTM (EPSG:25832/EPSG:25833) to Webmercator (EPSG:3857) with 'gdalwarp'
+		// e.g. gdalwarp -t_srs EPSG:3857 32_497_5670_hangneigung.utm.tif 32_497_5670_hangneigung.webmercator.tif
+		err = Warp(ctx, utmGeoTIFF, webmercatorGeoTIFF, []string{"-of", "GTiff", "-t_srs", "EPSG:3857"})
+		if err != nil {
+			return nil, fmt.Errorf("error [%w] at Warp()", err)
+		}
+
+		// 3. colorize product with 'gdaldem color-relief' (creates PNG file)
+		// e.g. gdaldem color-relief 32_497_5670_hangneigung.webmercator.tif slope-colors.txt 32_497_5670_hangneigung.webmercator.png -alpha
+		switches := []string{"-of", "PNG", "-alpha"}
+		err = Dem(ctx, "color-relief", webmercatorGeoTIFF, options.ColorTextFile, colorWebmercatorPNG, append(switches, colorSwitches...))
+		if err != nil {
+			return nil, fmt.Errorf("error [%w] at Dem()", err)
+		}
+		return ReadVSIMemFile(colorWebmercatorPNG)
+
+	default:
+		return nil, fmt.Errorf("unsupported format [%s]", options.OutputFormat)
+	}
+}
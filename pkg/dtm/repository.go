@@ -0,0 +1,196 @@
+package dtm
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strings"
+)
+
+// TileMetadata represents meta data about a tile.
+type TileMetadata struct {
+	Index     string // (hash) index of tile (e.g. 32_383_5802)
+	Path      string // path and file name (e.g. /Downloads/dgm1_32_383_5802_1_ni_2017.tif)
+	Source    string // source of tile (e.g. DE-NI)
+	Actuality string // actuality of Airborne Laser Scanning (ALS) (e.g. 2017-04-19)
+}
+
+// Repository represents repository for all tiles (readonly after build, safe for concurrent use).
+type Repository struct {
+	tiles         map[string]TileMetadata // enabled tiles (primary, secondary '_2', tertiary '_3')
+	disabledTiles map[string]TileMetadata // tiles of disabled sources
+}
+
+// RepositoryStats represents the number of tiles in a repository.
+type RepositoryStats struct {
+	PrimaryTiles   int
+	SecondaryTiles int
+	TertiaryTiles  int
+	DisabledTiles  int
+}
+
+/*
+BuildRepository builds a repository from state repositories (JSON files with tile meta data).
+Each federal state provides a complete set of tiles for its territory.
+At the border between two federal states, the tiles exist in duplicate.
+Example: "32_410_5812"
+Tile for NW: dgm1_32_410_5812_1_nw_2024.tif -> index '32_410_5812'
+Tile for NI: dgm1_32_410_5812_1_ni_2017.tif -> index '32_410_5812_2'
+We need both tiles, measurements beyond the boundary can be designated as -9999 (no data).
+Also possible for a tile: state, neighbor 1, neighbor 2
+Tiles of disabled sources (e.g. DE-NW) are kept separately (see DisabledTile).
+*/
+func BuildRepository(stateRepositories []string, disabledSources []string) (*Repository, RepositoryStats, error) {
+	var entries []TileMetadata
+
+	for _, stateRepository := range stateRepositories {
+		stateTileMetadata := []TileMetadata{}
+		data, err := os.ReadFile(stateRepository)
+		if err != nil {
+			return nil, RepositoryStats{}, fmt.Errorf("building tile repository: error [%w] at os.ReadFile()", err)
+		}
+		err = json.Unmarshal(data, &stateTileMetadata)
+		if err != nil {
+			return nil, RepositoryStats{}, fmt.Errorf("building tile repository: error [%w] at json.Unmarshal(), file: %s", err, stateRepository)
+		}
+		entries = append(entries, stateTileMetadata...)
+	}
+
+	repository, stats := NewRepository(entries, disabledSources)
+	return repository, stats, nil
+}
+
+/*
+NewRepository builds a repository from tile meta data (duplicate indices become secondary and tertiary tiles).
+*/
+func NewRepository(entries []TileMetadata, disabledSources []string) (*Repository, RepositoryStats) {
+	var stats RepositoryStats
+
+	// Germany has estimated 360.000 entries
+	repository := &Repository{
+		tiles:         make(map[string]TileMetadata, max(len(entries), 1024)),
+		disabledTiles: make(map[string]TileMetadata),
+	}
+
+	for _, entry := range entries {
+		// check if source is disabled
+		if isSourceDisabled(entry.Source, disabledSources) {
+			repository.disabledTiles[entry.Index] = entry
+			stats.DisabledTiles++
+			continue
+		}
+		// check if primary entry already exists
+		_, primaryExists := repository.tiles[entry.Index]
+		if !primaryExists {
+			repository.tiles[entry.Index] = entry
+			stats.PrimaryTiles++
+			continue
+		}
+		// check if secondary entry already exists
+		index := entry.Index + "_2"
+		_, secondaryExists := repository.tiles[index]
+		if !secondaryExists {
+			repository.tiles[index] = entry
+			stats.SecondaryTiles++
+			continue
+		}
+		// add entry as tertiary entry
+		index = entry.Index + "_3"
+		repository.tiles[index] = entry
+		stats.TertiaryTiles++
+	}
+
+	return repository, stats
+}
+
+/*
+isSourceDisabled checks if given source (e.g. DE-NW) is disabled.
+*/
+func isSourceDisabled(source string, disabledSources []string) bool {
+	for _, disabledSource := range disabledSources {
+		if strings.EqualFold(disabledSource, source) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Tile returns the tile for an index (e.g. 32_383_5802, 32_383_5802_2).
+*/
+func (r *Repository) Tile(index string) (TileMetadata, bool) {
+	if r == nil {
+		return TileMetadata{}, false
+	}
+	tile, found := r.tiles[index]
+	return tile, found
+}
+
+/*
+DisabledTile returns the tile of a disabled source for an index.
+*/
+func (r *Repository) DisabledTile(index string) (TileMetadata, bool) {
+	if r == nil {
+		return TileMetadata{}, false
+	}
+	tile, found := r.disabledTiles[index]
+	return tile, found
+}
+
+/*
+Len returns the number of (enabled) tiles.
+*/
+func (r *Repository) Len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.tiles)
+}
+
+/*
+Indices returns the indices of all (enabled) tiles in sorted order.
+*/
+func (r *Repository) Indices() []string {
+	if r == nil {
+		return nil
+	}
+	indices := make([]string, 0, len(r.tiles))
+	for index := range r.tiles {
+		indices = append(indices, index)
+	}
+	slices.Sort(indices)
+	return indices
+}
+
+/*
+TileIndex returns the index of the tile containing the UTM coordinates (1000 x 1000 m grid).
+Tile variants:
+1 = primary tile (from state)
+2 = secondary tile (from state neighbor 1)
+3 = tertiary tile (from state neighbor 2)
+*/
+func TileIndex(zone int, easting float64, northing float64, tileVariant int) string {
+	eastingPrefix := int(math.Floor(easting / 1000.0))
+	northingPrefix := int(math.Floor(northing / 1000.0))
+	if tileVariant == 1 {
+		return fmt.Sprintf("%d_%d_%d", zone, eastingPrefix, northingPrefix)
+	}
+	return fmt.Sprintf("%d_%d_%d_%d", zone, eastingPrefix, northingPrefix, tileVariant)
+}
+
+/*
+TilesUTM returns all tiles (primary, secondary, tertiary) containing the UTM coordinates.
+*/
+func (r *Repository) TilesUTM(zone int, easting float64, northing float64) []TileMetadata {
+	var tiles []TileMetadata
+	for tileVariant := 1; tileVariant <= 3; tileVariant++ {
+		tile, found := r.Tile(TileIndex(zone, easting, northing, tileVariant))
+		if !found {
+			break
+		}
+		tiles = append(tiles, tile)
+	}
+	return tiles
+}
@@ -7,10 +7,26 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"sync/atomic"
 )
 
+// point response modes
+const (
+	pointResponseModeFull    = "full"
+	pointResponseModeCompact = "compact"
+	pointResponseModeText    = "text"
+)
+
+// PointCompactResponse represents the minimal point response (compact mode, e.g. for embedded devices).
+type PointCompactResponse struct {
+	Elevation   float64
+	Attribution string       `json:",omitempty"`
+	Error       *ErrorObject `json:",omitempty"`
+}
+
 /*
 pointRequest handles 'point request' from client.
 */
@@ -22,67 +38,108 @@ func pointRequest(writer http.ResponseWriter, request *http.Request) {
 	// statistics
 	atomic.AddUint64(&PointRequests, 1)
 
-	// limit overall request body size
-	request.Body = http.MaxBytesReader(writer, request.Body, MaxPointRequestBodySize)
+	var pointRequest PointRequest
+	var err error
+	if request.Method == http.MethodGet {
+		// simple query with URL parameters (e.g. from browsers, spreadsheets, monitoring probes)
+		pointRequest, err = parsePointQuery(request.URL.Query())
+		if err != nil {
+			slog.WarnContext(request.Context(), "point request: error parsing query parameters", "error", err, "ID", "unknown")
+			pointResponse.Attributes.Error = newErrorObject(EndpointPoint, ReasonParsingQueryParameters, err.Error())
+			buildPointResponse(writer, request, http.StatusBadRequest, pointResponse)
+			return
+		}
+	} else {
+		// limit overall request body size
+		request.Body = http.MaxBytesReader(writer, request.Body, MaxPointRequestBodySize)
 
-	// read request
-	bodyData, err := io.ReadAll(request.Body)
-	if err != nil {
-		// check specifically for the error returned by MaxBytesReader
-		var maxBytesErr *http.MaxBytesError
-		if errors.As(err, &maxBytesErr) {
-			slog.Warn("point request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
-			pointResponse.Attributes.Error.Code = "1000"
-			pointResponse.Attributes.Error.Title = "request body too large"
-			pointResponse.Attributes.Error.Detail = fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit)
-			buildPointResponse(writer, http.StatusRequestEntityTooLarge, pointResponse)
-		} else {
-			// handle other read errors
-			slog.Warn("point request: error reading request body", "error", err, "ID", "unknown")
-			pointResponse.Attributes.Error.Code = "1020"
-			pointResponse.Attributes.Error.Title = "error reading request body"
-			pointResponse.Attributes.Error.Detail = err.Error()
-			buildPointResponse(writer, http.StatusBadRequest, pointResponse)
+		// read request
+		bodyData, err := io.ReadAll(request.Body)
+		if err != nil {
+			// check specifically for the error returned by MaxBytesReader
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				slog.WarnContext(request.Context(), "point request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
+				pointResponse.Attributes.Error = newErrorObject(EndpointPoint, ReasonRequestBodyTooLarge, fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit))
+				buildPointResponse(writer, request, http.StatusRequestEntityTooLarge, pointResponse)
+			} else {
+				// handle other read errors
+				slog.WarnContext(request.Context(), "point request: error reading request body", "error", err, "ID", "unknown")
+				pointResponse.Attributes.Error = newErrorObject(EndpointPoint, ReasonReadingRequestBody, err.Error())
+				buildPointResponse(writer, request, http.StatusBadRequest, pointResponse)
+			}
+			return
+		}
+
+		// unmarshal request
+		err = json.Unmarshal(bodyData, &pointRequest)
+		if err != nil {
+			slog.WarnContext(request.Context(), "point request: error unmarshaling request body", "error", err, "ID", "unknown")
+			pointResponse.Attributes.Error = newErrorObject(EndpointPoint, ReasonUnmarshalingRequestBody, err.Error())
+			buildPointResponse(writer, request, http.StatusBadRequest, pointResponse)
+			return
 		}
-		return
 	}
 
-	// unmarshal request
-	pointRequest := PointRequest{}
-	err = json.Unmarshal(bodyData, &pointRequest)
-	if err != nil {
-		slog.Warn("point request: error unmarshaling request body", "error", err, "ID", "unknown")
-		pointResponse.Attributes.Error.Code = "1040"
-		pointResponse.Attributes.Error.Title = "error unmarshaling request body"
-		pointResponse.Attributes.Error.Detail = err.Error()
-		buildPointResponse(writer, http.StatusBadRequest, pointResponse)
-		return
+	// resolve place name (geocoding) into lon/lat coordinates
+	if pointRequest.Attributes.Place != "" {
+		place, err := geocodePlace(request.Context(), pointRequest.Attributes.Place)
+		if err != nil {
+			slog.WarnContext(request.Context(), "point request: error geocoding place", "error", err, "place", pointRequest.Attributes.Place, "ID", pointRequest.ID)
+			pointResponse.ID = pointRequest.ID
+			pointResponse.Attributes.Place = pointRequest.Attributes.Place
+			if errors.Is(err, ErrPlaceNotFound) {
+				pointResponse.Attributes.Error = newErrorObject(EndpointPoint, ReasonPlaceNotFound, err.Error())
+				buildPointResponse(writer, request, http.StatusNotFound, pointResponse)
+				return
+			}
+			if errors.Is(err, ErrGeocoderUnavailable) {
+				pointResponse.Attributes.Error = newErrorObject(EndpointPoint, ReasonGeocoderUnavailable, err.Error())
+				buildPointResponse(writer, request, http.StatusServiceUnavailable, pointResponse)
+				return
+			}
+			pointResponse.Attributes.Error = newErrorObject(EndpointPoint, ReasonVerifyingRequestData, err.Error())
+			buildPointResponse(writer, request, http.StatusBadRequest, pointResponse)
+			return
+		}
+		pointRequest.Attributes.Longitude = place.Longitude
+		pointRequest.Attributes.Latitude = place.Latitude
+		pointResponse.Attributes.PlaceName = place.DisplayName
 	}
 
 	// copy request parameters into response
 	pointResponse.ID = pointRequest.ID
 	pointResponse.Attributes.Latitude = pointRequest.Attributes.Latitude
+	pointResponse.Attributes.Place = pointRequest.Attributes.Place
 	pointResponse.Attributes.Longitude = pointRequest.Attributes.Longitude
+	pointResponse.Attributes.VerticalOffset = pointRequest.Attributes.VerticalOffset
+	pointResponse.Attributes.ReferencePoint = pointRequest.Attributes.ReferencePoint
 
 	// verify request data
 	err = verifyPointRequestData(request, pointRequest)
 	if err != nil {
-		slog.Warn("point request: error verifying request data", "error", err, "ID", pointRequest.ID)
-		pointResponse.Attributes.Error.Code = "1060"
-		pointResponse.Attributes.Error.Title = "error verifying request data"
-		pointResponse.Attributes.Error.Detail = err.Error()
-		buildPointResponse(writer, http.StatusBadRequest, pointResponse)
+		slog.WarnContext(request.Context(), "point request: error verifying request data", "error", err, "ID", pointRequest.ID)
+		pointResponse.Attributes.Error = newErrorObject(EndpointPoint, ReasonVerifyingRequestData, err.Error())
+		buildPointResponse(writer, request, http.StatusBadRequest, pointResponse)
 		return
 	}
 
 	// get elevation
 	elevation, tile, err := getElevationForPoint(pointRequest.Attributes.Longitude, pointRequest.Attributes.Latitude)
 	if err != nil {
-		slog.Debug("point request: error getting elevation for point", "error", err, "ID", pointRequest.ID)
-		pointResponse.Attributes.Error.Code = "1080"
-		pointResponse.Attributes.Error.Title = "error getting elevation"
-		pointResponse.Attributes.Error.Detail = err.Error()
-		buildPointResponse(writer, http.StatusBadRequest, pointResponse)
+		slog.DebugContext(request.Context(), "point request: error getting elevation for point", "error", err, "ID", pointRequest.ID)
+		if errors.Is(err, ErrOutsideCoverage) {
+			pointResponse.Attributes.Error = newErrorObject(EndpointPoint, ReasonOutsideCoverage, describeOutsideCoverage(err))
+			buildPointResponse(writer, request, http.StatusNotFound, pointResponse)
+			return
+		}
+		if errors.Is(err, ErrSourceUnavailable) {
+			pointResponse.Attributes.Error = newErrorObject(EndpointPoint, ReasonSourceUnavailable, err.Error())
+			buildPointResponse(writer, request, http.StatusServiceUnavailable, pointResponse)
+			return
+		}
+		pointResponse.Attributes.Error = newErrorObject(EndpointPoint, ReasonGettingElevation, err.Error())
+		buildPointResponse(writer, request, http.StatusBadRequest, pointResponse)
 		return
 	}
 
@@ -91,50 +148,119 @@ func pointRequest(writer http.ResponseWriter, request *http.Request) {
 	origin := "unknown"
 	resource, err := getElevationResource(tile.Source)
 	if err != nil {
-		slog.Error("point request: error getting elevation resource", "error", err, "source", tile.Source, "ID", pointRequest.ID)
+		slog.ErrorContext(request.Context(), "point request: error getting elevation resource", "error", err, "source", tile.Source, "ID", pointRequest.ID)
 	} else {
 		attribution = resource.Attribution
 		origin = resource.Code
 	}
 
-	// success response
-	pointResponse.Attributes.Elevation = elevation
+	// relative mode: elevation difference to reference point (e.g. summit relative to current position)
+	if pointRequest.Attributes.ReferencePoint != nil {
+		reference := pointRequest.Attributes.ReferencePoint
+		referenceElevation, referenceTile, err := getElevationForPoint(reference.Longitude, reference.Latitude)
+		if err != nil {
+			slog.DebugContext(request.Context(), "point request: error getting elevation for reference point", "error", err, "ID", pointRequest.ID)
+			if errors.Is(err, ErrOutsideCoverage) {
+				pointResponse.Attributes.Error = newErrorObject(EndpointPoint, ReasonOutsideCoverage, "reference point: "+describeOutsideCoverage(err))
+				buildPointResponse(writer, request, http.StatusNotFound, pointResponse)
+				return
+			}
+			if errors.Is(err, ErrSourceUnavailable) {
+				pointResponse.Attributes.Error = newErrorObject(EndpointPoint, ReasonSourceUnavailable, "reference point: "+err.Error())
+				buildPointResponse(writer, request, http.StatusServiceUnavailable, pointResponse)
+				return
+			}
+			pointResponse.Attributes.Error = newErrorObject(EndpointPoint, ReasonGettingElevation, "reference point: "+err.Error())
+			buildPointResponse(writer, request, http.StatusBadRequest, pointResponse)
+			return
+		}
+		difference := elevation - referenceElevation
+		referenceElevation += pointRequest.Attributes.VerticalOffset
+		pointResponse.Attributes.ReferenceElevation = &referenceElevation
+		pointResponse.Attributes.ReferenceTileIndex = referenceTile.Index
+		pointResponse.Attributes.ElevationDifference = &difference
+		if referenceTile.Source != tile.Source {
+			pointResponse.Attributes.Warnings = append(pointResponse.Attributes.Warnings,
+				fmt.Sprintf("point and reference point are based on different sources (%s, %s)", tile.Source, referenceTile.Source))
+		}
+	}
+
+	// success response (elevation relative to custom datum)
+	pointResponse.Attributes.Elevation = elevation + pointRequest.Attributes.VerticalOffset
 	pointResponse.Attributes.Actuality = tile.Actuality
 	pointResponse.Attributes.Origin = origin
 	pointResponse.Attributes.Attribution = attribution
 	pointResponse.Attributes.TileIndex = tile.Index
 	pointResponse.Attributes.IsError = false
-	buildPointResponse(writer, http.StatusOK, pointResponse)
+	buildPointResponse(writer, request, http.StatusOK, pointResponse)
 }
 
 /*
-verifyPointRequestData verifies 'point' request data.
-It performs several checks on the request data to ensure its validity.
+parsePointQuery builds point request from URL query parameters (lon, lat or place, offset, reflon, reflat, id).
 */
-func verifyPointRequestData(request *http.Request, pointRequest PointRequest) error {
-	// verify HTTP header
-	contentType := request.Header.Get("Content-Type")
-	isContentTypeValid := true
-	switch {
-	case strings.HasPrefix(strings.ToLower(contentType), "application/json"):
-		// potentially check charset=utf-8 specifically if required
-	default:
-		isContentTypeValid = false
+func parsePointQuery(query url.Values) (PointRequest, error) {
+	pointRequest := PointRequest{Type: TypePointRequest, ID: query.Get("id")}
+
+	// optional vertical offset (custom datum)
+	if query.Has("offset") {
+		verticalOffset, err := strconv.ParseFloat(query.Get("offset"), 64)
+		if err != nil {
+			return pointRequest, fmt.Errorf("invalid query parameter 'offset' (%w)", err)
+		}
+		pointRequest.Attributes.VerticalOffset = verticalOffset
 	}
-	if !isContentTypeValid {
-		return fmt.Errorf("unexpected or missing HTTP header field Content-Type, value = [%s], expected 'application/json'", contentType)
+
+	// optional reference point (relative elevation)
+	if query.Has("reflon") || query.Has("reflat") {
+		referenceLongitude, err := strconv.ParseFloat(query.Get("reflon"), 64)
+		if err != nil {
+			return pointRequest, fmt.Errorf("invalid or missing query parameter 'reflon' (%w)", err)
+		}
+		referenceLatitude, err := strconv.ParseFloat(query.Get("reflat"), 64)
+		if err != nil {
+			return pointRequest, fmt.Errorf("invalid or missing query parameter 'reflat' (%w)", err)
+		}
+		pointRequest.Attributes.ReferencePoint = &ReferencePoint{Longitude: referenceLongitude, Latitude: referenceLatitude}
 	}
 
-	// verify HTTP header
-	accept := request.Header.Get("Accept")
-	isAcceptValid := true
-	switch {
-	case strings.HasPrefix(strings.ToLower(accept), "application/json"):
-	default:
-		isAcceptValid = false
+	// place name instead of coordinates (geocoded)
+	if query.Has("place") {
+		pointRequest.Attributes.Place = query.Get("place")
+		return pointRequest, nil
 	}
-	if !isAcceptValid {
-		return fmt.Errorf("unexpected or missing HTTP header field Accept, value = [%s], expected 'application/json'", accept)
+
+	longitude, err := strconv.ParseFloat(query.Get("lon"), 64)
+	if err != nil {
+		return pointRequest, fmt.Errorf("invalid or missing query parameter 'lon' (%w)", err)
+	}
+	latitude, err := strconv.ParseFloat(query.Get("lat"), 64)
+	if err != nil {
+		return pointRequest, fmt.Errorf("invalid or missing query parameter 'lat' (%w)", err)
+	}
+	pointRequest.Attributes.Longitude = longitude
+	pointRequest.Attributes.Latitude = latitude
+
+	return pointRequest, nil
+}
+
+/*
+verifyPointRequestData verifies 'point' request data.
+It performs several checks on the request data to ensure its validity.
+*/
+func verifyPointRequestData(request *http.Request, pointRequest PointRequest) error {
+	// verify HTTP headers (JSON request body only)
+	if request.Method == http.MethodPost {
+		contentType := request.Header.Get("Content-Type")
+		isContentTypeValid := true
+		switch {
+		case strings.HasPrefix(strings.ToLower(contentType), "application/json"):
+			// potentially check charset=utf-8 specifically if required
+		default:
+			isContentTypeValid = false
+		}
+		if !isContentTypeValid {
+			return fmt.Errorf("unexpected or missing HTTP header field Content-Type, value = [%s], expected 'application/json'", contentType)
+		}
 	}
 
 	// verify Type
@@ -157,6 +283,22 @@ func verifyPointRequestData(request *http.Request, pointRequest PointRequest) er
 		return errors.New("invalid longitude for Germany")
 	}
 
+	// verify Attributes.VerticalOffset
+	err := verifyVerticalOffset(pointRequest.Attributes.VerticalOffset)
+	if err != nil {
+		return err
+	}
+
+	// verify Attributes.ReferencePoint for Germany
+	if reference := pointRequest.Attributes.ReferencePoint; reference != nil {
+		if reference.Latitude > 55.3 || reference.Latitude < 47.0 {
+			return errors.New("invalid reference point latitude for Germany")
+		}
+		if reference.Longitude > 15.3 || reference.Longitude < 5.5 {
+			return errors.New("invalid reference point longitude for Germany")
+		}
+	}
+
 	return nil
 }
 
@@ -165,21 +307,38 @@ buildPointResponse builds HTTP responses with specified status and body.
 It sets the Content-Type and Content-Length headers before writing the response body.
 This function is used to construct consistent HTTP responses throughout the application.
 */
-func buildPointResponse(writer http.ResponseWriter, httpStatus int, pointResponse PointResponse) {
+func buildPointResponse(writer http.ResponseWriter, request *http.Request, httpStatus int, pointResponse PointResponse) {
+	// response metadata (versions, processing duration, cache hit)
+	pointResponse.Meta = newResponseMeta(request.Context())
+
 	// log limit length of body (we don't expect large bodies)
 	maxBodyLength := 1024
 
-	// CORS: allow requests from any origin
-	writer.Header().Set("Access-Control-Allow-Origin", "*")
-	// CORS: allowed methods
-	writer.Header().Set("Access-Control-Allow-Methods", "POST")
-	// CORS: allowed headers
-	writer.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	// plain text response (elevation value only)
+	responseMode := getPointResponseMode(request)
+	if responseMode == pointResponseModeText {
+		writer.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		writer.WriteHeader(httpStatus)
+		if pointResponse.Attributes.IsError {
+			fmt.Fprintf(writer, "error %s: %s\n", pointResponse.Attributes.Error.Code, pointResponse.Attributes.Error.Title)
+			return
+		}
+		fmt.Fprintf(writer, "%s\n", strconv.FormatFloat(pointResponse.Attributes.Elevation, 'f', -1, 64))
+		return
+	}
 
 	// marshal response
-	body, err := json.MarshalIndent(pointResponse, "", "  ")
+	var response any = pointResponse
+	if responseMode == pointResponseModeCompact {
+		compactResponse := PointCompactResponse{Elevation: pointResponse.Attributes.Elevation, Attribution: pointResponse.Attributes.Attribution}
+		if pointResponse.Attributes.IsError {
+			compactResponse.Error = &pointResponse.Attributes.Error
+		}
+		response = compactResponse
+	}
+	body, err := marshalResponse(response)
 	if err != nil {
-		slog.Error("error marshaling point response", "error", err, "body length", len(body),
+		slog.ErrorContext(request.Context(), "error marshaling point response", "error", err, "body length", len(body),
 			fmt.Sprintf("body (limited to first %d bytes)", maxBodyLength), body[:maxBodyLength])
 
 		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
@@ -191,7 +350,26 @@ func buildPointResponse(writer http.ResponseWriter, httpStatus int, pointRespons
 	writer.WriteHeader(httpStatus)
 	_, err = writer.Write(body)
 	if err != nil {
-		slog.Error("error writing HTTP response body", "error", err, "body length", len(body),
+		slog.ErrorContext(request.Context(), "error writing HTTP response body", "error", err, "body length", len(body),
 			fmt.Sprintf("body (limited to first %d bytes)", maxBodyLength), body[:maxBodyLength])
 	}
 }
+
+/*
+getPointResponseMode determines the response mode of a point request: query parameter 'format'
+(full, compact, text) or Accept header (text/plain).
+*/
+func getPointResponseMode(request *http.Request) string {
+	switch strings.ToLower(request.URL.Query().Get("format")) {
+	case pointResponseModeCompact:
+		return pointResponseModeCompact
+	case pointResponseModeText:
+		return pointResponseModeText
+	case pointResponseModeFull:
+		return pointResponseModeFull
+	}
+	if strings.HasPrefix(strings.ToLower(request.Header.Get("Accept")), "text/plain") {
+		return pointResponseModeText
+	}
+	return pointResponseModeFull
+}
@@ -0,0 +1,364 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// profile chart margins (pixels, space for axis labels)
+const (
+	profileChartMarginLeft   = 56
+	profileChartMarginRight  = 24
+	profileChartMarginTop    = 20
+	profileChartMarginBottom = 36
+)
+
+// profile chart colors
+var (
+	profileChartColorBackground = color.RGBA{255, 255, 255, 255}
+	profileChartColorGrid       = color.RGBA{221, 221, 221, 255}
+	profileChartColorAxis       = color.RGBA{68, 68, 68, 255}
+	profileChartColorFill       = color.RGBA{198, 219, 239, 255}
+	profileChartColorLine       = color.RGBA{33, 102, 172, 255}
+)
+
+// profileChartGlyphs is a 5x7 bitmap font for axis labels (digits, sign, decimal point, units, also used for legends)
+var profileChartGlyphs = map[rune][7]uint8{
+	'0': {0b01110, 0b10001, 0b10011, 0b10101, 0b11001, 0b10001, 0b01110},
+	'1': {0b00100, 0b01100, 0b00100, 0b00100, 0b00100, 0b00100, 0b01110},
+	'2': {0b01110, 0b10001, 0b00001, 0b00010, 0b00100, 0b01000, 0b11111},
+	'3': {0b11111, 0b00010, 0b00100, 0b00010, 0b00001, 0b10001, 0b01110},
+	'4': {0b00010, 0b00110, 0b01010, 0b10010, 0b11111, 0b00010, 0b00010},
+	'5': {0b11111, 0b10000, 0b11110, 0b00001, 0b00001, 0b10001, 0b01110},
+	'6': {0b00110, 0b01000, 0b10000, 0b11110, 0b10001, 0b10001, 0b01110},
+	'7': {0b11111, 0b00001, 0b00010, 0b00100, 0b01000, 0b01000, 0b01000},
+	'8': {0b01110, 0b10001, 0b10001, 0b01110, 0b10001, 0b10001, 0b01110},
+	'9': {0b01110, 0b10001, 0b10001, 0b01111, 0b00001, 0b00010, 0b01100},
+	'.': {0b00000, 0b00000, 0b00000, 0b00000, 0b00000, 0b01100, 0b01100},
+	'-': {0b00000, 0b00000, 0b00000, 0b11111, 0b00000, 0b00000, 0b00000},
+	'k': {0b10000, 0b10000, 0b10010, 0b10100, 0b11000, 0b10100, 0b10010},
+	'm': {0b00000, 0b00000, 0b11010, 0b10101, 0b10101, 0b10001, 0b10001},
+	'°': {0b01100, 0b10010, 0b10010, 0b01100, 0b00000, 0b00000, 0b00000},
+	'%': {0b11000, 0b11001, 0b00010, 0b00100, 0b01000, 0b10011, 0b00011},
+	' ': {},
+}
+
+// profileChartLayout represents the scales and ticks of a profile chart.
+type profileChartLayout struct {
+	width, height    int
+	maxDistance      float64 // meters
+	minElevation     float64 // meters (lower bound of y axis)
+	maxElevation     float64 // meters (upper bound of y axis)
+	distanceTicks    []float64
+	elevationTicks   []float64
+	distanceDivisor  float64 // 1 (m) or 1000 (km)
+	distanceUnitText string
+}
+
+/*
+verifyProfileChartOptions verifies the output format and size of an elevation profile chart.
+*/
+func verifyProfileChartOptions(outputFormat string, width int, height int) error {
+	switch strings.ToLower(outputFormat) {
+	case "", "svg", "png":
+	default:
+		return errors.New("unsupported chart format (not svg, png)")
+	}
+	if outputFormat == "" {
+		return nil
+	}
+	if width != 0 && (width < 200 || width > 2000) {
+		return errors.New("ChartWidth must be between 200 and 2000 pixels")
+	}
+	if height != 0 && (height < 100 || height > 1000) {
+		return errors.New("ChartHeight must be between 100 and 1000 pixels")
+	}
+	return nil
+}
+
+/*
+renderProfileChart renders the elevation profile as chart (svg or png) with labeled axes (distance, elevation).
+*/
+func renderProfileChart(profile []ProfilePoint, outputFormat string, width int, height int) (ProfileChart, error) {
+	chart := ProfileChart{DataFormat: strings.ToLower(outputFormat), Width: width, Height: height}
+	if len(profile) < 2 {
+		return chart, errors.New("profile must contain at least 2 points")
+	}
+
+	layout := buildProfileChartLayout(profile, width, height)
+	switch chart.DataFormat {
+	case "svg":
+		chart.Data = renderProfileChartSVG(profile, layout)
+	case "png":
+		data, err := renderProfileChartPNG(profile, layout)
+		if err != nil {
+			return chart, fmt.Errorf("error [%w] at renderProfileChartPNG()", err)
+		}
+		chart.Data = data
+	default:
+		return chart, fmt.Errorf("unsupported chart format [%s]", outputFormat)
+	}
+
+	return chart, nil
+}
+
+/*
+buildProfileChartLayout determines axis ranges and 'nice' ticks (1, 2, 5 x 10^n) for the profile.
+*/
+func buildProfileChartLayout(profile []ProfilePoint, width int, height int) profileChartLayout {
+	layout := profileChartLayout{width: width, height: height, distanceDivisor: 1, distanceUnitText: "m"}
+
+	minElevation, maxElevation := math.Inf(1), math.Inf(-1)
+	for _, point := range profile {
+		minElevation = min(minElevation, point.Elevation)
+		maxElevation = max(maxElevation, point.Elevation)
+	}
+	layout.maxDistance = profile[len(profile)-1].Distance
+	if layout.maxDistance >= 2000 {
+		layout.distanceDivisor = 1000
+		layout.distanceUnitText = "km"
+	}
+
+	// elevation axis: at least 10 m range, bounds on tick positions
+	if maxElevation-minElevation < 10 {
+		center := (maxElevation + minElevation) / 2
+		minElevation, maxElevation = center-5, center+5
+	}
+	elevationStep := niceTickStep((maxElevation-minElevation)/5, 1)
+	layout.minElevation = math.Floor(minElevation/elevationStep) * elevationStep
+	layout.maxElevation = math.Ceil(maxElevation/elevationStep) * elevationStep
+	for tick := layout.minElevation; tick <= layout.maxElevation+elevationStep/2; tick += elevationStep {
+		layout.elevationTicks = append(layout.elevationTicks, tick)
+	}
+
+	// distance axis
+	distanceStep := niceTickStep(layout.maxDistance/float64(max(2, (width-profileChartMarginLeft-profileChartMarginRight)/100)), 1)
+	for tick := 0.0; tick <= layout.maxDistance+distanceStep/1000; tick += distanceStep {
+		layout.distanceTicks = append(layout.distanceTicks, tick)
+	}
+
+	return layout
+}
+
+/*
+niceTickStep returns the smallest 'nice' step (1, 2, 5 x 10^n, at least minStep) not less than rough step.
+*/
+func niceTickStep(roughStep float64, minStep float64) float64 {
+	if roughStep <= minStep {
+		return minStep
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(roughStep)))
+	for _, factor := range []float64{1, 2, 5, 10} {
+		if factor*magnitude >= roughStep {
+			return factor * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+/*
+x returns the horizontal pixel position of a distance.
+*/
+func (layout profileChartLayout) x(distance float64) float64 {
+	plotWidth := float64(layout.width - profileChartMarginLeft - profileChartMarginRight)
+	return float64(profileChartMarginLeft) + distance/layout.maxDistance*plotWidth
+}
+
+/*
+y returns the vertical pixel position of an elevation.
+*/
+func (layout profileChartLayout) y(elevation float64) float64 {
+	plotHeight := float64(layout.height - profileChartMarginTop - profileChartMarginBottom)
+	return float64(profileChartMarginTop) + (layout.maxElevation-elevation)/(layout.maxElevation-layout.minElevation)*plotHeight
+}
+
+/*
+distanceLabel formats a distance tick label (m or km).
+*/
+func (layout profileChartLayout) distanceLabel(distance float64) string {
+	return strconv.FormatFloat(distance/layout.distanceDivisor, 'f', -1, 64)
+}
+
+/*
+renderProfileChartSVG renders the profile chart as SVG document.
+*/
+func renderProfileChartSVG(profile []ProfilePoint, layout profileChartLayout) []byte {
+	var svg strings.Builder
+	rgb := func(c color.RGBA) string { return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B) }
+	left, right := layout.x(0), layout.x(layout.maxDistance)
+	top, bottom := layout.y(layout.maxElevation), layout.y(layout.minElevation)
+
+	fmt.Fprintf(&svg, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" font-family=\"sans-serif\" font-size=\"11\">\n",
+		layout.width, layout.height, layout.width, layout.height)
+	fmt.Fprintf(&svg, "<rect width=\"100%%\" height=\"100%%\" fill=\"%s\"/>\n", rgb(profileChartColorBackground))
+
+	// grid and tick labels
+	for _, tick := range layout.elevationTicks {
+		y := layout.y(tick)
+		fmt.Fprintf(&svg, "<line x1=\"%.1f\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\" stroke=\"%s\"/>\n", left, y, right, y, rgb(profileChartColorGrid))
+		fmt.Fprintf(&svg, "<text x=\"%.1f\" y=\"%.1f\" text-anchor=\"end\" dominant-baseline=\"middle\" fill=\"%s\">%s</text>\n",
+			left-6, y, rgb(profileChartColorAxis), strconv.FormatFloat(tick, 'f', -1, 64))
+	}
+	for _, tick := range layout.distanceTicks {
+		x := layout.x(tick)
+		fmt.Fprintf(&svg, "<line x1=\"%.1f\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\" stroke=\"%s\"/>\n", x, top, x, bottom, rgb(profileChartColorGrid))
+		fmt.Fprintf(&svg, "<text x=\"%.1f\" y=\"%.1f\" text-anchor=\"middle\" fill=\"%s\">%s</text>\n",
+			x, bottom+16, rgb(profileChartColorAxis), layout.distanceLabel(tick))
+	}
+
+	// profile area and line
+	var points strings.Builder
+	for _, point := range profile {
+		fmt.Fprintf(&points, "%.1f,%.1f ", layout.x(point.Distance), layout.y(point.Elevation))
+	}
+	line := strings.TrimSpace(points.String())
+	fmt.Fprintf(&svg, "<polygon points=\"%.1f,%.1f %s %.1f,%.1f\" fill=\"%s\"/>\n",
+		layout.x(profile[0].Distance), bottom, line, layout.x(profile[len(profile)-1].Distance), bottom, rgb(profileChartColorFill))
+	fmt.Fprintf(&svg, "<polyline points=\"%s\" fill=\"none\" stroke=\"%s\" stroke-width=\"2\" stroke-linejoin=\"round\"/>\n", line, rgb(profileChartColorLine))
+
+	// axes and axis titles
+	fmt.Fprintf(&svg, "<polyline points=\"%.1f,%.1f %.1f,%.1f %.1f,%.1f\" fill=\"none\" stroke=\"%s\"/>\n", left, top, left, bottom, right, bottom, rgb(profileChartColorAxis))
+	fmt.Fprintf(&svg, "<text x=\"%.1f\" y=\"%.1f\" text-anchor=\"end\" fill=\"%s\">Höhe [m]</text>\n", left-6, top-8, rgb(profileChartColorAxis))
+	fmt.Fprintf(&svg, "<text x=\"%.1f\" y=\"%.1f\" text-anchor=\"end\" fill=\"%s\">Distanz [%s]</text>\n", right, bottom+32, rgb(profileChartColorAxis), layout.distanceUnitText)
+	fmt.Fprintf(&svg, "</svg>\n")
+
+	return []byte(svg.String())
+}
+
+/*
+renderProfileChartPNG renders the profile chart as PNG image (labels with built-in bitmap font).
+*/
+func renderProfileChartPNG(profile []ProfilePoint, layout profileChartLayout) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, layout.width, layout.height))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = profileChartColorBackground.R, profileChartColorBackground.G, profileChartColorBackground.B, 255
+	}
+	left, right := int(layout.x(0)), int(layout.x(layout.maxDistance))
+	top, bottom := int(layout.y(layout.maxElevation)), int(layout.y(layout.minElevation))
+
+	// grid and tick labels
+	for _, tick := range layout.elevationTicks {
+		y := int(math.Round(layout.y(tick)))
+		drawLine(img, left, y, right, y, profileChartColorGrid)
+		label := strconv.FormatFloat(tick, 'f', -1, 64)
+		drawText(img, left-6-textWidth(label), y-3, label, profileChartColorAxis)
+	}
+	for _, tick := range layout.distanceTicks {
+		x := int(math.Round(layout.x(tick)))
+		drawLine(img, x, top, x, bottom, profileChartColorGrid)
+		label := layout.distanceLabel(tick)
+		drawText(img, x-textWidth(label)/2, bottom+8, label, profileChartColorAxis)
+	}
+
+	// profile area (linear interpolation per pixel column)
+	index := 0
+	for x := left; x <= right; x++ {
+		distance := (float64(x) - layout.x(0)) / (layout.x(layout.maxDistance) - layout.x(0)) * layout.maxDistance
+		for index < len(profile)-2 && profile[index+1].Distance < distance {
+			index++
+		}
+		a, b := profile[index], profile[index+1]
+		if distance < profile[0].Distance || distance > profile[len(profile)-1].Distance {
+			continue
+		}
+		elevation := a.Elevation
+		if b.Distance > a.Distance {
+			elevation += (b.Elevation - a.Elevation) * (distance - a.Distance) / (b.Distance - a.Distance)
+		}
+		drawLine(img, x, int(math.Round(layout.y(elevation))), x, bottom, profileChartColorFill)
+	}
+
+	// profile line (2 pixels wide)
+	for i := 1; i < len(profile); i++ {
+		x1, y1 := int(math.Round(layout.x(profile[i-1].Distance))), int(math.Round(layout.y(profile[i-1].Elevation)))
+		x2, y2 := int(math.Round(layout.x(profile[i].Distance))), int(math.Round(layout.y(profile[i].Elevation)))
+		drawLine(img, x1, y1, x2, y2, profileChartColorLine)
+		drawLine(img, x1, y1+1, x2, y2+1, profileChartColorLine)
+	}
+
+	// axes and units
+	drawLine(img, left, top, left, bottom, profileChartColorAxis)
+	drawLine(img, left, bottom, right, bottom, profileChartColorAxis)
+	drawText(img, left-6-textWidth("m"), top-14, "m", profileChartColorAxis)
+	drawText(img, right-textWidth(layout.distanceUnitText), bottom+22, layout.distanceUnitText, profileChartColorAxis)
+
+	var buffer bytes.Buffer
+	err := png.Encode(&buffer, img)
+	if err != nil {
+		return nil, fmt.Errorf("error [%w] at png.Encode()", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+/*
+drawLine draws a line (Bresenham) clipped to the image bounds.
+*/
+func drawLine(img *image.RGBA, x1, y1, x2, y2 int, c color.RGBA) {
+	dx, dy := abs(x2-x1), -abs(y2-y1)
+	sx, sy := 1, 1
+	if x1 > x2 {
+		sx = -1
+	}
+	if y1 > y2 {
+		sy = -1
+	}
+	err := dx + dy
+	for {
+		if image.Pt(x1, y1).In(img.Rect) {
+			img.SetRGBA(x1, y1, c)
+		}
+		if x1 == x2 && y1 == y2 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x1 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y1 += sy
+		}
+	}
+}
+
+/*
+drawText draws text with the built-in 5x7 bitmap font (top left corner at x, y, unknown characters are skipped).
+*/
+func drawText(img *image.RGBA, x, y int, text string, c color.RGBA) {
+	for _, character := range text {
+		glyph := profileChartGlyphs[character]
+		for row, bits := range glyph {
+			for column := range 5 {
+				if bits&(1<<(4-column)) != 0 && image.Pt(x+column, y+row).In(img.Rect) {
+					img.SetRGBA(x+column, y+row, c)
+				}
+			}
+		}
+		x += 6
+	}
+}
+
+/*
+textWidth returns the width of text (pixels) drawn with the built-in bitmap font.
+*/
+func textWidth(text string) int {
+	return 6*len([]rune(text)) - 1
+}
+
+/*
+abs returns the absolute value of an integer.
+*/
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
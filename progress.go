@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// interval of keep-alive comments in event streams (prevents proxies from closing idle connections)
+const jobEventsKeepAliveInterval = 15 * time.Second
+
+// jobProgressKey is the context key for the job whose progress is reported
+type jobProgressKey struct{}
+
+/*
+withJobProgress returns a context reporting the progress of tile processing to the job.
+*/
+func withJobProgress(ctx context.Context, job *Job) context.Context {
+	return context.WithValue(ctx, jobProgressKey{}, job)
+}
+
+/*
+addJobTiles adds the number of tiles to process to the progress of the job (no-op outside of jobs).
+*/
+func addJobTiles(ctx context.Context, tiles int) {
+	job, ok := ctx.Value(jobProgressKey{}).(*Job)
+	if !ok {
+		return
+	}
+	job.mutex.Lock()
+	job.tilesTotal += tiles
+	job.mutex.Unlock()
+	job.notifyChange()
+}
+
+/*
+addJobTilesDone adds the number of processed tiles to the progress of the job (no-op outside of jobs).
+*/
+func addJobTilesDone(ctx context.Context, tiles int) {
+	job, ok := ctx.Value(jobProgressKey{}).(*Job)
+	if !ok {
+		return
+	}
+	job.mutex.Lock()
+	job.tilesDone += tiles
+	job.mutex.Unlock()
+	job.notifyChange()
+}
+
+/*
+notifyChange wakes up all listeners waiting for a change of the job state (progress, status).
+*/
+func (job *Job) notifyChange() {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+	if job.changed != nil {
+		close(job.changed)
+	}
+	job.changed = make(chan struct{})
+}
+
+/*
+waitChange returns a channel which is closed on the next change of the job state.
+*/
+func (job *Job) waitChange() <-chan struct{} {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+	if job.changed == nil {
+		job.changed = make(chan struct{})
+	}
+	return job.changed
+}
+
+/*
+jobEventsRequest handles 'job events request' (GET /v1/jobs/{id}/events) from client.
+The job state (incl. tile progress, e.g. "14/60 tiles processed") is streamed as server-sent events
+(event 'progress' on each change, event 'done' with the final state). The stream ends when the job is done.
+*/
+func jobEventsRequest(writer http.ResponseWriter, request *http.Request) {
+	var jobResponse = JobResponse{Type: TypeJobResponse, ID: request.PathValue("id")}
+	jobResponse.Attributes.IsError = true
+
+	job, err := getJob(request)
+	if err != nil {
+		slog.WarnContext(request.Context(), "job events request: error getting job", "error", err)
+		jobResponse.Attributes.Error = newErrorObject(EndpointJobs, ReasonJobNotFound, err.Error())
+		buildJobResponse(writer, request, http.StatusNotFound, jobResponse)
+		return
+	}
+
+	// event stream runs until job is done (not limited by server write timeout)
+	controller := http.NewResponseController(writer)
+	err = controller.SetWriteDeadline(time.Time{})
+	if err != nil {
+		slog.WarnContext(request.Context(), "job events request: error disabling write deadline", "error", err)
+	}
+
+	writer.Header().Set("Content-Type", "text/event-stream")
+	writer.Header().Set("Cache-Control", "no-cache")
+	writer.Header().Set("X-Accel-Buffering", "no")
+	writer.WriteHeader(http.StatusOK)
+
+	keepAlive := time.NewTicker(jobEventsKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		// subscribe before reading state (no change is lost)
+		changed := job.waitChange()
+
+		jobResponse = JobResponse{Type: TypeJobResponse, ID: request.PathValue("id")}
+		job.fillResponse(&jobResponse)
+		jobResponse.Meta = newResponseMeta(request.Context())
+		done := jobResponse.Attributes.Status != jobStatusQueued && jobResponse.Attributes.Status != jobStatusRunning
+		event := "progress"
+		if done {
+			event = "done"
+		}
+		err = writeServerSentEvent(writer, event, jobResponse)
+		if err == nil {
+			err = controller.Flush()
+		}
+		if err != nil {
+			slog.WarnContext(request.Context(), "job events request: error writing event", "error", err, "job", job.id)
+			return
+		}
+		if done {
+			return
+		}
+
+		// wait for change of job state (or keep-alive, client disconnect)
+	wait:
+		for {
+			select {
+			case <-changed:
+				break wait
+			case <-keepAlive.C:
+				_, err = fmt.Fprint(writer, ": keep-alive\n\n")
+				if err == nil {
+					err = controller.Flush()
+				}
+				if err != nil {
+					return
+				}
+			case <-request.Context().Done():
+				return
+			}
+		}
+	}
+}
+
+/*
+writeServerSentEvent writes one server-sent event (data as single line JSON).
+*/
+func writeServerSentEvent(writer http.ResponseWriter, event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("error [%w] at json.Marshal()", err)
+	}
+	_, err = fmt.Fprintf(writer, "event: %s\ndata: %s\n\n", event, payload)
+	if err != nil {
+		return fmt.Errorf("error [%w] at fmt.Fprintf()", err)
+	}
+	return nil
+}
@@ -0,0 +1,1442 @@
+// gRPC service definition of the DTM elevation service (backend-to-backend integrations).
+//
+// The service mirrors the REST API (point, GPX and raster endpoints). Requests and responses
+// correspond to the JSON objects of the REST API (see /openapi.json), error codes are the
+// codes of the error code registry (/v1/errors).
+//
+// The server is implemented in grpc.go (listener: config 'GRPC.ListenAddress'). Code generation:
+//   protoc --go_out=. --go_opt=module=klaus/elevation/dtm-elevation-service \
+//          --go-grpc_out=. --go-grpc_opt=module=klaus/elevation/dtm-elevation-service \
+//          proto/dtm-elevation-service.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.28.3
+// source: proto/dtm-elevation-service.proto
+
+package dtmv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ErrorObject represents error details (see error code registry).
+type ErrorObject struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Detail        string                 `protobuf:"bytes,3,opt,name=detail,proto3" json:"detail,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ErrorObject) Reset() {
+	*x = ErrorObject{}
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ErrorObject) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorObject) ProtoMessage() {}
+
+func (x *ErrorObject) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorObject.ProtoReflect.Descriptor instead.
+func (*ErrorObject) Descriptor() ([]byte, []int) {
+	return file_proto_dtm_elevation_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ErrorObject) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *ErrorObject) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *ErrorObject) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+// Location represents a location by UTM coordinates (zone 32 or 33), lon/lat coordinates or place name.
+type Location struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Location:
+	//
+	//	*Location_Utm
+	//	*Location_LonLat
+	//	*Location_Place
+	Location      isLocation_Location `protobuf_oneof:"location"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Location) Reset() {
+	*x = Location{}
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Location) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Location) ProtoMessage() {}
+
+func (x *Location) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Location.ProtoReflect.Descriptor instead.
+func (*Location) Descriptor() ([]byte, []int) {
+	return file_proto_dtm_elevation_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Location) GetLocation() isLocation_Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *Location) GetUtm() *UTMCoordinates {
+	if x != nil {
+		if x, ok := x.Location.(*Location_Utm); ok {
+			return x.Utm
+		}
+	}
+	return nil
+}
+
+func (x *Location) GetLonLat() *LonLatCoordinates {
+	if x != nil {
+		if x, ok := x.Location.(*Location_LonLat); ok {
+			return x.LonLat
+		}
+	}
+	return nil
+}
+
+func (x *Location) GetPlace() string {
+	if x != nil {
+		if x, ok := x.Location.(*Location_Place); ok {
+			return x.Place
+		}
+	}
+	return ""
+}
+
+type isLocation_Location interface {
+	isLocation_Location()
+}
+
+type Location_Utm struct {
+	Utm *UTMCoordinates `protobuf:"bytes,1,opt,name=utm,proto3,oneof"`
+}
+
+type Location_LonLat struct {
+	LonLat *LonLatCoordinates `protobuf:"bytes,2,opt,name=lon_lat,json=lonLat,proto3,oneof"`
+}
+
+type Location_Place struct {
+	Place string `protobuf:"bytes,3,opt,name=place,proto3,oneof"` // place name (e.g. "Feldberg"), geocoded into lon/lat coordinates
+}
+
+func (*Location_Utm) isLocation_Location() {}
+
+func (*Location_LonLat) isLocation_Location() {}
+
+func (*Location_Place) isLocation_Location() {}
+
+type UTMCoordinates struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Zone          int32                  `protobuf:"varint,1,opt,name=zone,proto3" json:"zone,omitempty"`
+	Easting       float64                `protobuf:"fixed64,2,opt,name=easting,proto3" json:"easting,omitempty"`
+	Northing      float64                `protobuf:"fixed64,3,opt,name=northing,proto3" json:"northing,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UTMCoordinates) Reset() {
+	*x = UTMCoordinates{}
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UTMCoordinates) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UTMCoordinates) ProtoMessage() {}
+
+func (x *UTMCoordinates) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UTMCoordinates.ProtoReflect.Descriptor instead.
+func (*UTMCoordinates) Descriptor() ([]byte, []int) {
+	return file_proto_dtm_elevation_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UTMCoordinates) GetZone() int32 {
+	if x != nil {
+		return x.Zone
+	}
+	return 0
+}
+
+func (x *UTMCoordinates) GetEasting() float64 {
+	if x != nil {
+		return x.Easting
+	}
+	return 0
+}
+
+func (x *UTMCoordinates) GetNorthing() float64 {
+	if x != nil {
+		return x.Northing
+	}
+	return 0
+}
+
+type LonLatCoordinates struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Longitude     float64                `protobuf:"fixed64,1,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	Latitude      float64                `protobuf:"fixed64,2,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LonLatCoordinates) Reset() {
+	*x = LonLatCoordinates{}
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LonLatCoordinates) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LonLatCoordinates) ProtoMessage() {}
+
+func (x *LonLatCoordinates) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LonLatCoordinates.ProtoReflect.Descriptor instead.
+func (*LonLatCoordinates) Descriptor() ([]byte, []int) {
+	return file_proto_dtm_elevation_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *LonLatCoordinates) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+func (x *LonLatCoordinates) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+type PointRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Longitude     float64                `protobuf:"fixed64,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	Latitude      float64                `protobuf:"fixed64,3,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Place         string                 `protobuf:"bytes,4,opt,name=place,proto3" json:"place,omitempty"` // optional: place name instead of coordinates
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PointRequest) Reset() {
+	*x = PointRequest{}
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PointRequest) ProtoMessage() {}
+
+func (x *PointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PointRequest.ProtoReflect.Descriptor instead.
+func (*PointRequest) Descriptor() ([]byte, []int) {
+	return file_proto_dtm_elevation_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PointRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PointRequest) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+func (x *PointRequest) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *PointRequest) GetPlace() string {
+	if x != nil {
+		return x.Place
+	}
+	return ""
+}
+
+type UTMPointRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Zone          int32                  `protobuf:"varint,2,opt,name=zone,proto3" json:"zone,omitempty"`
+	Easting       float64                `protobuf:"fixed64,3,opt,name=easting,proto3" json:"easting,omitempty"`
+	Northing      float64                `protobuf:"fixed64,4,opt,name=northing,proto3" json:"northing,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UTMPointRequest) Reset() {
+	*x = UTMPointRequest{}
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UTMPointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UTMPointRequest) ProtoMessage() {}
+
+func (x *UTMPointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UTMPointRequest.ProtoReflect.Descriptor instead.
+func (*UTMPointRequest) Descriptor() ([]byte, []int) {
+	return file_proto_dtm_elevation_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UTMPointRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UTMPointRequest) GetZone() int32 {
+	if x != nil {
+		return x.Zone
+	}
+	return 0
+}
+
+func (x *UTMPointRequest) GetEasting() float64 {
+	if x != nil {
+		return x.Easting
+	}
+	return 0
+}
+
+func (x *UTMPointRequest) GetNorthing() float64 {
+	if x != nil {
+		return x.Northing
+	}
+	return 0
+}
+
+type PointResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Elevation     float64                `protobuf:"fixed64,2,opt,name=elevation,proto3" json:"elevation,omitempty"`
+	Actuality     string                 `protobuf:"bytes,3,opt,name=actuality,proto3" json:"actuality,omitempty"`
+	Origin        string                 `protobuf:"bytes,4,opt,name=origin,proto3" json:"origin,omitempty"`
+	Attribution   string                 `protobuf:"bytes,5,opt,name=attribution,proto3" json:"attribution,omitempty"`
+	TileIndex     string                 `protobuf:"bytes,6,opt,name=tile_index,json=tileIndex,proto3" json:"tile_index,omitempty"`
+	PlaceName     string                 `protobuf:"bytes,7,opt,name=place_name,json=placeName,proto3" json:"place_name,omitempty"`
+	Warnings      []string               `protobuf:"bytes,8,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	Error         *ErrorObject           `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"` // set if elevation could not be determined
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PointResponse) Reset() {
+	*x = PointResponse{}
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PointResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PointResponse) ProtoMessage() {}
+
+func (x *PointResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PointResponse.ProtoReflect.Descriptor instead.
+func (*PointResponse) Descriptor() ([]byte, []int) {
+	return file_proto_dtm_elevation_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PointResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PointResponse) GetElevation() float64 {
+	if x != nil {
+		return x.Elevation
+	}
+	return 0
+}
+
+func (x *PointResponse) GetActuality() string {
+	if x != nil {
+		return x.Actuality
+	}
+	return ""
+}
+
+func (x *PointResponse) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *PointResponse) GetAttribution() string {
+	if x != nil {
+		return x.Attribution
+	}
+	return ""
+}
+
+func (x *PointResponse) GetTileIndex() string {
+	if x != nil {
+		return x.TileIndex
+	}
+	return ""
+}
+
+func (x *PointResponse) GetPlaceName() string {
+	if x != nil {
+		return x.PlaceName
+	}
+	return ""
+}
+
+func (x *PointResponse) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+func (x *PointResponse) GetError() *ErrorObject {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type GPXRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	GpxData       []byte                 `protobuf:"bytes,2,opt,name=gpx_data,json=gpxData,proto3" json:"gpx_data,omitempty"` // GPX XML (not base64 encoded)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GPXRequest) Reset() {
+	*x = GPXRequest{}
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GPXRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GPXRequest) ProtoMessage() {}
+
+func (x *GPXRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GPXRequest.ProtoReflect.Descriptor instead.
+func (*GPXRequest) Descriptor() ([]byte, []int) {
+	return file_proto_dtm_elevation_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GPXRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GPXRequest) GetGpxData() []byte {
+	if x != nil {
+		return x.GpxData
+	}
+	return nil
+}
+
+type GPXResponse struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Id                  string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	GpxData             []byte                 `protobuf:"bytes,2,opt,name=gpx_data,json=gpxData,proto3" json:"gpx_data,omitempty"`
+	Points              int32                  `protobuf:"varint,3,opt,name=points,proto3" json:"points,omitempty"`
+	PointsWithElevation int32                  `protobuf:"varint,4,opt,name=points_with_elevation,json=pointsWithElevation,proto3" json:"points_with_elevation,omitempty"`
+	Error               *ErrorObject           `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *GPXResponse) Reset() {
+	*x = GPXResponse{}
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GPXResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GPXResponse) ProtoMessage() {}
+
+func (x *GPXResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GPXResponse.ProtoReflect.Descriptor instead.
+func (*GPXResponse) Descriptor() ([]byte, []int) {
+	return file_proto_dtm_elevation_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GPXResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GPXResponse) GetGpxData() []byte {
+	if x != nil {
+		return x.GpxData
+	}
+	return nil
+}
+
+func (x *GPXResponse) GetPoints() int32 {
+	if x != nil {
+		return x.Points
+	}
+	return 0
+}
+
+func (x *GPXResponse) GetPointsWithElevation() int32 {
+	if x != nil {
+		return x.PointsWithElevation
+	}
+	return 0
+}
+
+func (x *GPXResponse) GetError() *ErrorObject {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type TileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Location      *Location              `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TileRequest) Reset() {
+	*x = TileRequest{}
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TileRequest) ProtoMessage() {}
+
+func (x *TileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TileRequest.ProtoReflect.Descriptor instead.
+func (*TileRequest) Descriptor() ([]byte, []int) {
+	return file_proto_dtm_elevation_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *TileRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TileRequest) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+type RasterRequest struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Id                   string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Location             *Location              `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+	GradientAlgorithm    string                 `protobuf:"bytes,3,opt,name=gradient_algorithm,json=gradientAlgorithm,proto3" json:"gradient_algorithm,omitempty"` // Horn, ZevenbergenThorne
+	ColoringAlgorithm    string                 `protobuf:"bytes,4,opt,name=coloring_algorithm,json=coloringAlgorithm,proto3" json:"coloring_algorithm,omitempty"`
+	OutputFormat         string                 `protobuf:"bytes,5,opt,name=output_format,json=outputFormat,proto3" json:"output_format,omitempty"`                             // geotiff, png
+	ColorTextFileContent []string               `protobuf:"bytes,6,rep,name=color_text_file_content,json=colorTextFileContent,proto3" json:"color_text_file_content,omitempty"` // color text file (gdaldem color-relief format)
+	ColorTableName       string                 `protobuf:"bytes,7,opt,name=color_table_name,json=colorTableName,proto3" json:"color_table_name,omitempty"`                     // named color table instead of color text file content
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *RasterRequest) Reset() {
+	*x = RasterRequest{}
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RasterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RasterRequest) ProtoMessage() {}
+
+func (x *RasterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RasterRequest.ProtoReflect.Descriptor instead.
+func (*RasterRequest) Descriptor() ([]byte, []int) {
+	return file_proto_dtm_elevation_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RasterRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RasterRequest) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *RasterRequest) GetGradientAlgorithm() string {
+	if x != nil {
+		return x.GradientAlgorithm
+	}
+	return ""
+}
+
+func (x *RasterRequest) GetColoringAlgorithm() string {
+	if x != nil {
+		return x.ColoringAlgorithm
+	}
+	return ""
+}
+
+func (x *RasterRequest) GetOutputFormat() string {
+	if x != nil {
+		return x.OutputFormat
+	}
+	return ""
+}
+
+func (x *RasterRequest) GetColorTextFileContent() []string {
+	if x != nil {
+		return x.ColorTextFileContent
+	}
+	return nil
+}
+
+func (x *RasterRequest) GetColorTableName() string {
+	if x != nil {
+		return x.ColorTableName
+	}
+	return ""
+}
+
+type HillshadeRequest struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Id                   string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Location             *Location              `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+	GradientAlgorithm    string                 `protobuf:"bytes,3,opt,name=gradient_algorithm,json=gradientAlgorithm,proto3" json:"gradient_algorithm,omitempty"` // Horn, ZevenbergenThorne
+	VerticalExaggeration float64                `protobuf:"fixed64,4,opt,name=vertical_exaggeration,json=verticalExaggeration,proto3" json:"vertical_exaggeration,omitempty"`
+	AzimuthOfLight       uint32                 `protobuf:"varint,5,opt,name=azimuth_of_light,json=azimuthOfLight,proto3" json:"azimuth_of_light,omitempty"`
+	AltitudeOfLight      uint32                 `protobuf:"varint,6,opt,name=altitude_of_light,json=altitudeOfLight,proto3" json:"altitude_of_light,omitempty"`
+	ShadingVariants      []string               `protobuf:"bytes,7,rep,name=shading_variants,json=shadingVariants,proto3" json:"shading_variants,omitempty"` // regular, combined, multidirectional, igor, textured, custom
+	OutputFormat         string                 `protobuf:"bytes,8,opt,name=output_format,json=outputFormat,proto3" json:"output_format,omitempty"`          // geotiff, png
+	LightSources         []*LightSource         `protobuf:"bytes,9,rep,name=light_sources,json=lightSources,proto3" json:"light_sources,omitempty"`          // light sources for shading variant 'custom' (blended by weight)
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *HillshadeRequest) Reset() {
+	*x = HillshadeRequest{}
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HillshadeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HillshadeRequest) ProtoMessage() {}
+
+func (x *HillshadeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HillshadeRequest.ProtoReflect.Descriptor instead.
+func (*HillshadeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_dtm_elevation_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *HillshadeRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *HillshadeRequest) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *HillshadeRequest) GetGradientAlgorithm() string {
+	if x != nil {
+		return x.GradientAlgorithm
+	}
+	return ""
+}
+
+func (x *HillshadeRequest) GetVerticalExaggeration() float64 {
+	if x != nil {
+		return x.VerticalExaggeration
+	}
+	return 0
+}
+
+func (x *HillshadeRequest) GetAzimuthOfLight() uint32 {
+	if x != nil {
+		return x.AzimuthOfLight
+	}
+	return 0
+}
+
+func (x *HillshadeRequest) GetAltitudeOfLight() uint32 {
+	if x != nil {
+		return x.AltitudeOfLight
+	}
+	return 0
+}
+
+func (x *HillshadeRequest) GetShadingVariants() []string {
+	if x != nil {
+		return x.ShadingVariants
+	}
+	return nil
+}
+
+func (x *HillshadeRequest) GetOutputFormat() string {
+	if x != nil {
+		return x.OutputFormat
+	}
+	return ""
+}
+
+func (x *HillshadeRequest) GetLightSources() []*LightSource {
+	if x != nil {
+		return x.LightSources
+	}
+	return nil
+}
+
+// LightSource represents one light source of a custom (blended) hillshade.
+type LightSource struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Azimuth       float64                `protobuf:"fixed64,1,opt,name=azimuth,proto3" json:"azimuth,omitempty"`   // degrees (0 - 360)
+	Altitude      float64                `protobuf:"fixed64,2,opt,name=altitude,proto3" json:"altitude,omitempty"` // degrees (0 - 90)
+	Weight        float64                `protobuf:"fixed64,3,opt,name=weight,proto3" json:"weight,omitempty"`     // relative weight
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LightSource) Reset() {
+	*x = LightSource{}
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LightSource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LightSource) ProtoMessage() {}
+
+func (x *LightSource) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LightSource.ProtoReflect.Descriptor instead.
+func (*LightSource) Descriptor() ([]byte, []int) {
+	return file_proto_dtm_elevation_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *LightSource) GetAzimuth() float64 {
+	if x != nil {
+		return x.Azimuth
+	}
+	return 0
+}
+
+func (x *LightSource) GetAltitude() float64 {
+	if x != nil {
+		return x.Altitude
+	}
+	return 0
+}
+
+func (x *LightSource) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+type ContoursRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Location      *Location              `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+	Equidistance  float64                `protobuf:"fixed64,3,opt,name=equidistance,proto3" json:"equidistance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ContoursRequest) Reset() {
+	*x = ContoursRequest{}
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContoursRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContoursRequest) ProtoMessage() {}
+
+func (x *ContoursRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContoursRequest.ProtoReflect.Descriptor instead.
+func (*ContoursRequest) Descriptor() ([]byte, []int) {
+	return file_proto_dtm_elevation_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ContoursRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ContoursRequest) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *ContoursRequest) GetEquidistance() float64 {
+	if x != nil {
+		return x.Equidistance
+	}
+	return 0
+}
+
+// TileOutput represents the product of one tile (one message per tile of the response).
+type TileOutput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	DataFormat    string                 `protobuf:"bytes,3,opt,name=data_format,json=dataFormat,proto3" json:"data_format,omitempty"`
+	Filename      string                 `protobuf:"bytes,4,opt,name=filename,proto3" json:"filename,omitempty"`
+	Actuality     string                 `protobuf:"bytes,5,opt,name=actuality,proto3" json:"actuality,omitempty"`
+	Origin        string                 `protobuf:"bytes,6,opt,name=origin,proto3" json:"origin,omitempty"`
+	Attribution   string                 `protobuf:"bytes,7,opt,name=attribution,proto3" json:"attribution,omitempty"`
+	TileIndex     string                 `protobuf:"bytes,8,opt,name=tile_index,json=tileIndex,proto3" json:"tile_index,omitempty"`
+	Variant       string                 `protobuf:"bytes,9,opt,name=variant,proto3" json:"variant,omitempty"`                             // e.g. shading variant
+	Error         *ErrorObject           `protobuf:"bytes,10,opt,name=error,proto3" json:"error,omitempty"`                                // set if the tile could not be processed (partial success)
+	SourceFiles   []*SourceFile          `protobuf:"bytes,11,rep,name=source_files,json=sourceFiles,proto3" json:"source_files,omitempty"` // source GeoTIFF files (provenance)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TileOutput) Reset() {
+	*x = TileOutput{}
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TileOutput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TileOutput) ProtoMessage() {}
+
+func (x *TileOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TileOutput.ProtoReflect.Descriptor instead.
+func (*TileOutput) Descriptor() ([]byte, []int) {
+	return file_proto_dtm_elevation_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *TileOutput) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TileOutput) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *TileOutput) GetDataFormat() string {
+	if x != nil {
+		return x.DataFormat
+	}
+	return ""
+}
+
+func (x *TileOutput) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *TileOutput) GetActuality() string {
+	if x != nil {
+		return x.Actuality
+	}
+	return ""
+}
+
+func (x *TileOutput) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *TileOutput) GetAttribution() string {
+	if x != nil {
+		return x.Attribution
+	}
+	return ""
+}
+
+func (x *TileOutput) GetTileIndex() string {
+	if x != nil {
+		return x.TileIndex
+	}
+	return ""
+}
+
+func (x *TileOutput) GetVariant() string {
+	if x != nil {
+		return x.Variant
+	}
+	return ""
+}
+
+func (x *TileOutput) GetError() *ErrorObject {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *TileOutput) GetSourceFiles() []*SourceFile {
+	if x != nil {
+		return x.SourceFiles
+	}
+	return nil
+}
+
+// SourceFile represents a source GeoTIFF file (delivery file name and SHA-256 checksum).
+type SourceFile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filename      string                 `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	Sha256        string                 `protobuf:"bytes,2,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SourceFile) Reset() {
+	*x = SourceFile{}
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SourceFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SourceFile) ProtoMessage() {}
+
+func (x *SourceFile) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_dtm_elevation_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SourceFile.ProtoReflect.Descriptor instead.
+func (*SourceFile) Descriptor() ([]byte, []int) {
+	return file_proto_dtm_elevation_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SourceFile) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *SourceFile) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+var File_proto_dtm_elevation_service_proto protoreflect.FileDescriptor
+
+const file_proto_dtm_elevation_service_proto_rawDesc = "" +
+	"\n" +
+	"!proto/dtm-elevation-service.proto\x12\x10dtm.elevation.v1\"O\n" +
+	"\vErrorObject\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x16\n" +
+	"\x06detail\x18\x03 \x01(\tR\x06detail\"\xa4\x01\n" +
+	"\bLocation\x124\n" +
+	"\x03utm\x18\x01 \x01(\v2 .dtm.elevation.v1.UTMCoordinatesH\x00R\x03utm\x12>\n" +
+	"\alon_lat\x18\x02 \x01(\v2#.dtm.elevation.v1.LonLatCoordinatesH\x00R\x06lonLat\x12\x16\n" +
+	"\x05place\x18\x03 \x01(\tH\x00R\x05placeB\n" +
+	"\n" +
+	"\blocation\"Z\n" +
+	"\x0eUTMCoordinates\x12\x12\n" +
+	"\x04zone\x18\x01 \x01(\x05R\x04zone\x12\x18\n" +
+	"\aeasting\x18\x02 \x01(\x01R\aeasting\x12\x1a\n" +
+	"\bnorthing\x18\x03 \x01(\x01R\bnorthing\"M\n" +
+	"\x11LonLatCoordinates\x12\x1c\n" +
+	"\tlongitude\x18\x01 \x01(\x01R\tlongitude\x12\x1a\n" +
+	"\blatitude\x18\x02 \x01(\x01R\blatitude\"n\n" +
+	"\fPointRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1c\n" +
+	"\tlongitude\x18\x02 \x01(\x01R\tlongitude\x12\x1a\n" +
+	"\blatitude\x18\x03 \x01(\x01R\blatitude\x12\x14\n" +
+	"\x05place\x18\x04 \x01(\tR\x05place\"k\n" +
+	"\x0fUTMPointRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04zone\x18\x02 \x01(\x05R\x04zone\x12\x18\n" +
+	"\aeasting\x18\x03 \x01(\x01R\aeasting\x12\x1a\n" +
+	"\bnorthing\x18\x04 \x01(\x01R\bnorthing\"\xa4\x02\n" +
+	"\rPointResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1c\n" +
+	"\televation\x18\x02 \x01(\x01R\televation\x12\x1c\n" +
+	"\tactuality\x18\x03 \x01(\tR\tactuality\x12\x16\n" +
+	"\x06origin\x18\x04 \x01(\tR\x06origin\x12 \n" +
+	"\vattribution\x18\x05 \x01(\tR\vattribution\x12\x1d\n" +
+	"\n" +
+	"tile_index\x18\x06 \x01(\tR\ttileIndex\x12\x1d\n" +
+	"\n" +
+	"place_name\x18\a \x01(\tR\tplaceName\x12\x1a\n" +
+	"\bwarnings\x18\b \x03(\tR\bwarnings\x123\n" +
+	"\x05error\x18\t \x01(\v2\x1d.dtm.elevation.v1.ErrorObjectR\x05error\"7\n" +
+	"\n" +
+	"GPXRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bgpx_data\x18\x02 \x01(\fR\agpxData\"\xb9\x01\n" +
+	"\vGPXResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bgpx_data\x18\x02 \x01(\fR\agpxData\x12\x16\n" +
+	"\x06points\x18\x03 \x01(\x05R\x06points\x122\n" +
+	"\x15points_with_elevation\x18\x04 \x01(\x05R\x13pointsWithElevation\x123\n" +
+	"\x05error\x18\x05 \x01(\v2\x1d.dtm.elevation.v1.ErrorObjectR\x05error\"U\n" +
+	"\vTileRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x126\n" +
+	"\blocation\x18\x02 \x01(\v2\x1a.dtm.elevation.v1.LocationR\blocation\"\xbb\x02\n" +
+	"\rRasterRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x126\n" +
+	"\blocation\x18\x02 \x01(\v2\x1a.dtm.elevation.v1.LocationR\blocation\x12-\n" +
+	"\x12gradient_algorithm\x18\x03 \x01(\tR\x11gradientAlgorithm\x12-\n" +
+	"\x12coloring_algorithm\x18\x04 \x01(\tR\x11coloringAlgorithm\x12#\n" +
+	"\routput_format\x18\x05 \x01(\tR\foutputFormat\x125\n" +
+	"\x17color_text_file_content\x18\x06 \x03(\tR\x14colorTextFileContent\x12(\n" +
+	"\x10color_table_name\x18\a \x01(\tR\x0ecolorTableName\"\xa8\x03\n" +
+	"\x10HillshadeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x126\n" +
+	"\blocation\x18\x02 \x01(\v2\x1a.dtm.elevation.v1.LocationR\blocation\x12-\n" +
+	"\x12gradient_algorithm\x18\x03 \x01(\tR\x11gradientAlgorithm\x123\n" +
+	"\x15vertical_exaggeration\x18\x04 \x01(\x01R\x14verticalExaggeration\x12(\n" +
+	"\x10azimuth_of_light\x18\x05 \x01(\rR\x0eazimuthOfLight\x12*\n" +
+	"\x11altitude_of_light\x18\x06 \x01(\rR\x0faltitudeOfLight\x12)\n" +
+	"\x10shading_variants\x18\a \x03(\tR\x0fshadingVariants\x12#\n" +
+	"\routput_format\x18\b \x01(\tR\foutputFormat\x12B\n" +
+	"\rlight_sources\x18\t \x03(\v2\x1d.dtm.elevation.v1.LightSourceR\flightSources\"[\n" +
+	"\vLightSource\x12\x18\n" +
+	"\aazimuth\x18\x01 \x01(\x01R\aazimuth\x12\x1a\n" +
+	"\baltitude\x18\x02 \x01(\x01R\baltitude\x12\x16\n" +
+	"\x06weight\x18\x03 \x01(\x01R\x06weight\"}\n" +
+	"\x0fContoursRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x126\n" +
+	"\blocation\x18\x02 \x01(\v2\x1a.dtm.elevation.v1.LocationR\blocation\x12\"\n" +
+	"\fequidistance\x18\x03 \x01(\x01R\fequidistance\"\xf4\x02\n" +
+	"\n" +
+	"TileOutput\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\x12\x1f\n" +
+	"\vdata_format\x18\x03 \x01(\tR\n" +
+	"dataFormat\x12\x1a\n" +
+	"\bfilename\x18\x04 \x01(\tR\bfilename\x12\x1c\n" +
+	"\tactuality\x18\x05 \x01(\tR\tactuality\x12\x16\n" +
+	"\x06origin\x18\x06 \x01(\tR\x06origin\x12 \n" +
+	"\vattribution\x18\a \x01(\tR\vattribution\x12\x1d\n" +
+	"\n" +
+	"tile_index\x18\b \x01(\tR\ttileIndex\x12\x18\n" +
+	"\avariant\x18\t \x01(\tR\avariant\x123\n" +
+	"\x05error\x18\n" +
+	" \x01(\v2\x1d.dtm.elevation.v1.ErrorObjectR\x05error\x12?\n" +
+	"\fsource_files\x18\v \x03(\v2\x1c.dtm.elevation.v1.SourceFileR\vsourceFiles\"@\n" +
+	"\n" +
+	"SourceFile\x12\x1a\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\x12\x16\n" +
+	"\x06sha256\x18\x02 \x01(\tR\x06sha2562\xc1\x06\n" +
+	"\x10ElevationService\x12K\n" +
+	"\bGetPoint\x12\x1e.dtm.elevation.v1.PointRequest\x1a\x1f.dtm.elevation.v1.PointResponse\x12Q\n" +
+	"\vGetUTMPoint\x12!.dtm.elevation.v1.UTMPointRequest\x1a\x1f.dtm.elevation.v1.PointResponse\x12S\n" +
+	"\fStreamPoints\x12\x1e.dtm.elevation.v1.PointRequest\x1a\x1f.dtm.elevation.v1.PointResponse(\x010\x01\x12Y\n" +
+	"\x0fStreamUTMPoints\x12!.dtm.elevation.v1.UTMPointRequest\x1a\x1f.dtm.elevation.v1.PointResponse(\x010\x01\x12P\n" +
+	"\x11AddElevationToGPX\x12\x1c.dtm.elevation.v1.GPXRequest\x1a\x1d.dtm.elevation.v1.GPXResponse\x12J\n" +
+	"\tGetRawTIF\x12\x1d.dtm.elevation.v1.TileRequest\x1a\x1c.dtm.elevation.v1.TileOutput0\x01\x12R\n" +
+	"\fGetHillshade\x12\".dtm.elevation.v1.HillshadeRequest\x1a\x1c.dtm.elevation.v1.TileOutput0\x01\x12K\n" +
+	"\bGetSlope\x12\x1f.dtm.elevation.v1.RasterRequest\x1a\x1c.dtm.elevation.v1.TileOutput0\x01\x12L\n" +
+	"\tGetAspect\x12\x1f.dtm.elevation.v1.RasterRequest\x1a\x1c.dtm.elevation.v1.TileOutput0\x01\x12P\n" +
+	"\vGetContours\x12!.dtm.elevation.v1.ContoursRequest\x1a\x1c.dtm.elevation.v1.TileOutput0\x01B3Z1klaus/elevation/dtm-elevation-service/proto/dtmv1b\x06proto3"
+
+var (
+	file_proto_dtm_elevation_service_proto_rawDescOnce sync.Once
+	file_proto_dtm_elevation_service_proto_rawDescData []byte
+)
+
+func file_proto_dtm_elevation_service_proto_rawDescGZIP() []byte {
+	file_proto_dtm_elevation_service_proto_rawDescOnce.Do(func() {
+		file_proto_dtm_elevation_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_dtm_elevation_service_proto_rawDesc), len(file_proto_dtm_elevation_service_proto_rawDesc)))
+	})
+	return file_proto_dtm_elevation_service_proto_rawDescData
+}
+
+var file_proto_dtm_elevation_service_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_proto_dtm_elevation_service_proto_goTypes = []any{
+	(*ErrorObject)(nil),       // 0: dtm.elevation.v1.ErrorObject
+	(*Location)(nil),          // 1: dtm.elevation.v1.Location
+	(*UTMCoordinates)(nil),    // 2: dtm.elevation.v1.UTMCoordinates
+	(*LonLatCoordinates)(nil), // 3: dtm.elevation.v1.LonLatCoordinates
+	(*PointRequest)(nil),      // 4: dtm.elevation.v1.PointRequest
+	(*UTMPointRequest)(nil),   // 5: dtm.elevation.v1.UTMPointRequest
+	(*PointResponse)(nil),     // 6: dtm.elevation.v1.PointResponse
+	(*GPXRequest)(nil),        // 7: dtm.elevation.v1.GPXRequest
+	(*GPXResponse)(nil),       // 8: dtm.elevation.v1.GPXResponse
+	(*TileRequest)(nil),       // 9: dtm.elevation.v1.TileRequest
+	(*RasterRequest)(nil),     // 10: dtm.elevation.v1.RasterRequest
+	(*HillshadeRequest)(nil),  // 11: dtm.elevation.v1.HillshadeRequest
+	(*LightSource)(nil),       // 12: dtm.elevation.v1.LightSource
+	(*ContoursRequest)(nil),   // 13: dtm.elevation.v1.ContoursRequest
+	(*TileOutput)(nil),        // 14: dtm.elevation.v1.TileOutput
+	(*SourceFile)(nil),        // 15: dtm.elevation.v1.SourceFile
+}
+var file_proto_dtm_elevation_service_proto_depIdxs = []int32{
+	2,  // 0: dtm.elevation.v1.Location.utm:type_name -> dtm.elevation.v1.UTMCoordinates
+	3,  // 1: dtm.elevation.v1.Location.lon_lat:type_name -> dtm.elevation.v1.LonLatCoordinates
+	0,  // 2: dtm.elevation.v1.PointResponse.error:type_name -> dtm.elevation.v1.ErrorObject
+	0,  // 3: dtm.elevation.v1.GPXResponse.error:type_name -> dtm.elevation.v1.ErrorObject
+	1,  // 4: dtm.elevation.v1.TileRequest.location:type_name -> dtm.elevation.v1.Location
+	1,  // 5: dtm.elevation.v1.RasterRequest.location:type_name -> dtm.elevation.v1.Location
+	1,  // 6: dtm.elevation.v1.HillshadeRequest.location:type_name -> dtm.elevation.v1.Location
+	12, // 7: dtm.elevation.v1.HillshadeRequest.light_sources:type_name -> dtm.elevation.v1.LightSource
+	1,  // 8: dtm.elevation.v1.ContoursRequest.location:type_name -> dtm.elevation.v1.Location
+	0,  // 9: dtm.elevation.v1.TileOutput.error:type_name -> dtm.elevation.v1.ErrorObject
+	15, // 10: dtm.elevation.v1.TileOutput.source_files:type_name -> dtm.elevation.v1.SourceFile
+	4,  // 11: dtm.elevation.v1.ElevationService.GetPoint:input_type -> dtm.elevation.v1.PointRequest
+	5,  // 12: dtm.elevation.v1.ElevationService.GetUTMPoint:input_type -> dtm.elevation.v1.UTMPointRequest
+	4,  // 13: dtm.elevation.v1.ElevationService.StreamPoints:input_type -> dtm.elevation.v1.PointRequest
+	5,  // 14: dtm.elevation.v1.ElevationService.StreamUTMPoints:input_type -> dtm.elevation.v1.UTMPointRequest
+	7,  // 15: dtm.elevation.v1.ElevationService.AddElevationToGPX:input_type -> dtm.elevation.v1.GPXRequest
+	9,  // 16: dtm.elevation.v1.ElevationService.GetRawTIF:input_type -> dtm.elevation.v1.TileRequest
+	11, // 17: dtm.elevation.v1.ElevationService.GetHillshade:input_type -> dtm.elevation.v1.HillshadeRequest
+	10, // 18: dtm.elevation.v1.ElevationService.GetSlope:input_type -> dtm.elevation.v1.RasterRequest
+	10, // 19: dtm.elevation.v1.ElevationService.GetAspect:input_type -> dtm.elevation.v1.RasterRequest
+	13, // 20: dtm.elevation.v1.ElevationService.GetContours:input_type -> dtm.elevation.v1.ContoursRequest
+	6,  // 21: dtm.elevation.v1.ElevationService.GetPoint:output_type -> dtm.elevation.v1.PointResponse
+	6,  // 22: dtm.elevation.v1.ElevationService.GetUTMPoint:output_type -> dtm.elevation.v1.PointResponse
+	6,  // 23: dtm.elevation.v1.ElevationService.StreamPoints:output_type -> dtm.elevation.v1.PointResponse
+	6,  // 24: dtm.elevation.v1.ElevationService.StreamUTMPoints:output_type -> dtm.elevation.v1.PointResponse
+	8,  // 25: dtm.elevation.v1.ElevationService.AddElevationToGPX:output_type -> dtm.elevation.v1.GPXResponse
+	14, // 26: dtm.elevation.v1.ElevationService.GetRawTIF:output_type -> dtm.elevation.v1.TileOutput
+	14, // 27: dtm.elevation.v1.ElevationService.GetHillshade:output_type -> dtm.elevation.v1.TileOutput
+	14, // 28: dtm.elevation.v1.ElevationService.GetSlope:output_type -> dtm.elevation.v1.TileOutput
+	14, // 29: dtm.elevation.v1.ElevationService.GetAspect:output_type -> dtm.elevation.v1.TileOutput
+	14, // 30: dtm.elevation.v1.ElevationService.GetContours:output_type -> dtm.elevation.v1.TileOutput
+	21, // [21:31] is the sub-list for method output_type
+	11, // [11:21] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_proto_dtm_elevation_service_proto_init() }
+func file_proto_dtm_elevation_service_proto_init() {
+	if File_proto_dtm_elevation_service_proto != nil {
+		return
+	}
+	file_proto_dtm_elevation_service_proto_msgTypes[1].OneofWrappers = []any{
+		(*Location_Utm)(nil),
+		(*Location_LonLat)(nil),
+		(*Location_Place)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_dtm_elevation_service_proto_rawDesc), len(file_proto_dtm_elevation_service_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   16,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_dtm_elevation_service_proto_goTypes,
+		DependencyIndexes: file_proto_dtm_elevation_service_proto_depIdxs,
+		MessageInfos:      file_proto_dtm_elevation_service_proto_msgTypes,
+	}.Build()
+	File_proto_dtm_elevation_service_proto = out.File
+	file_proto_dtm_elevation_service_proto_goTypes = nil
+	file_proto_dtm_elevation_service_proto_depIdxs = nil
+}
@@ -0,0 +1,498 @@
+// gRPC service definition of the DTM elevation service (backend-to-backend integrations).
+//
+// The service mirrors the REST API (point, GPX and raster endpoints). Requests and responses
+// correspond to the JSON objects of the REST API (see /openapi.json), error codes are the
+// codes of the error code registry (/v1/errors).
+//
+// The server is implemented in grpc.go (listener: config 'GRPC.ListenAddress'). Code generation:
+//   protoc --go_out=. --go_opt=module=klaus/elevation/dtm-elevation-service \
+//          --go-grpc_out=. --go-grpc_opt=module=klaus/elevation/dtm-elevation-service \
+//          proto/dtm-elevation-service.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             v5.28.3
+// source: proto/dtm-elevation-service.proto
+
+package dtmv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ElevationService_GetPoint_FullMethodName          = "/dtm.elevation.v1.ElevationService/GetPoint"
+	ElevationService_GetUTMPoint_FullMethodName       = "/dtm.elevation.v1.ElevationService/GetUTMPoint"
+	ElevationService_StreamPoints_FullMethodName      = "/dtm.elevation.v1.ElevationService/StreamPoints"
+	ElevationService_StreamUTMPoints_FullMethodName   = "/dtm.elevation.v1.ElevationService/StreamUTMPoints"
+	ElevationService_AddElevationToGPX_FullMethodName = "/dtm.elevation.v1.ElevationService/AddElevationToGPX"
+	ElevationService_GetRawTIF_FullMethodName         = "/dtm.elevation.v1.ElevationService/GetRawTIF"
+	ElevationService_GetHillshade_FullMethodName      = "/dtm.elevation.v1.ElevationService/GetHillshade"
+	ElevationService_GetSlope_FullMethodName          = "/dtm.elevation.v1.ElevationService/GetSlope"
+	ElevationService_GetAspect_FullMethodName         = "/dtm.elevation.v1.ElevationService/GetAspect"
+	ElevationService_GetContours_FullMethodName       = "/dtm.elevation.v1.ElevationService/GetContours"
+)
+
+// ElevationServiceClient is the client API for ElevationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ElevationServiceClient interface {
+	// elevation for point (lon/lat or place name), corresponds to POST /v1/point
+	GetPoint(ctx context.Context, in *PointRequest, opts ...grpc.CallOption) (*PointResponse, error)
+	// elevation for UTM point, corresponds to POST /v1/utmpoint
+	GetUTMPoint(ctx context.Context, in *UTMPointRequest, opts ...grpc.CallOption) (*PointResponse, error)
+	// elevations for batch of points (bidirectional stream, one response per request, same order)
+	StreamPoints(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[PointRequest, PointResponse], error)
+	// elevations for batch of UTM points (bidirectional stream, one response per request, same order)
+	StreamUTMPoints(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[UTMPointRequest, PointResponse], error)
+	// GPX with elevations added, corresponds to POST /v1/gpx
+	AddElevationToGPX(ctx context.Context, in *GPXRequest, opts ...grpc.CallOption) (*GPXResponse, error)
+	// raw GeoTIFF tiles, corresponds to POST /v1/rawtif (one message per tile)
+	GetRawTIF(ctx context.Context, in *TileRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TileOutput], error)
+	// hillshade tiles, corresponds to POST /v1/hillshade (one message per tile and shading variant)
+	GetHillshade(ctx context.Context, in *HillshadeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TileOutput], error)
+	// slope tiles, corresponds to POST /v1/slope (one message per tile)
+	GetSlope(ctx context.Context, in *RasterRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TileOutput], error)
+	// aspect tiles, corresponds to POST /v1/aspect (one message per tile)
+	GetAspect(ctx context.Context, in *RasterRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TileOutput], error)
+	// contour lines, corresponds to POST /v1/contours (one message per tile)
+	GetContours(ctx context.Context, in *ContoursRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TileOutput], error)
+}
+
+type elevationServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewElevationServiceClient(cc grpc.ClientConnInterface) ElevationServiceClient {
+	return &elevationServiceClient{cc}
+}
+
+func (c *elevationServiceClient) GetPoint(ctx context.Context, in *PointRequest, opts ...grpc.CallOption) (*PointResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PointResponse)
+	err := c.cc.Invoke(ctx, ElevationService_GetPoint_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *elevationServiceClient) GetUTMPoint(ctx context.Context, in *UTMPointRequest, opts ...grpc.CallOption) (*PointResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PointResponse)
+	err := c.cc.Invoke(ctx, ElevationService_GetUTMPoint_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *elevationServiceClient) StreamPoints(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[PointRequest, PointResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ElevationService_ServiceDesc.Streams[0], ElevationService_StreamPoints_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[PointRequest, PointResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ElevationService_StreamPointsClient = grpc.BidiStreamingClient[PointRequest, PointResponse]
+
+func (c *elevationServiceClient) StreamUTMPoints(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[UTMPointRequest, PointResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ElevationService_ServiceDesc.Streams[1], ElevationService_StreamUTMPoints_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UTMPointRequest, PointResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ElevationService_StreamUTMPointsClient = grpc.BidiStreamingClient[UTMPointRequest, PointResponse]
+
+func (c *elevationServiceClient) AddElevationToGPX(ctx context.Context, in *GPXRequest, opts ...grpc.CallOption) (*GPXResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GPXResponse)
+	err := c.cc.Invoke(ctx, ElevationService_AddElevationToGPX_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *elevationServiceClient) GetRawTIF(ctx context.Context, in *TileRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TileOutput], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ElevationService_ServiceDesc.Streams[2], ElevationService_GetRawTIF_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[TileRequest, TileOutput]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ElevationService_GetRawTIFClient = grpc.ServerStreamingClient[TileOutput]
+
+func (c *elevationServiceClient) GetHillshade(ctx context.Context, in *HillshadeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TileOutput], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ElevationService_ServiceDesc.Streams[3], ElevationService_GetHillshade_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[HillshadeRequest, TileOutput]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ElevationService_GetHillshadeClient = grpc.ServerStreamingClient[TileOutput]
+
+func (c *elevationServiceClient) GetSlope(ctx context.Context, in *RasterRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TileOutput], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ElevationService_ServiceDesc.Streams[4], ElevationService_GetSlope_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[RasterRequest, TileOutput]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ElevationService_GetSlopeClient = grpc.ServerStreamingClient[TileOutput]
+
+func (c *elevationServiceClient) GetAspect(ctx context.Context, in *RasterRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TileOutput], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ElevationService_ServiceDesc.Streams[5], ElevationService_GetAspect_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[RasterRequest, TileOutput]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ElevationService_GetAspectClient = grpc.ServerStreamingClient[TileOutput]
+
+func (c *elevationServiceClient) GetContours(ctx context.Context, in *ContoursRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TileOutput], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ElevationService_ServiceDesc.Streams[6], ElevationService_GetContours_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ContoursRequest, TileOutput]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ElevationService_GetContoursClient = grpc.ServerStreamingClient[TileOutput]
+
+// ElevationServiceServer is the server API for ElevationService service.
+// All implementations must embed UnimplementedElevationServiceServer
+// for forward compatibility.
+type ElevationServiceServer interface {
+	// elevation for point (lon/lat or place name), corresponds to POST /v1/point
+	GetPoint(context.Context, *PointRequest) (*PointResponse, error)
+	// elevation for UTM point, corresponds to POST /v1/utmpoint
+	GetUTMPoint(context.Context, *UTMPointRequest) (*PointResponse, error)
+	// elevations for batch of points (bidirectional stream, one response per request, same order)
+	StreamPoints(grpc.BidiStreamingServer[PointRequest, PointResponse]) error
+	// elevations for batch of UTM points (bidirectional stream, one response per request, same order)
+	StreamUTMPoints(grpc.BidiStreamingServer[UTMPointRequest, PointResponse]) error
+	// GPX with elevations added, corresponds to POST /v1/gpx
+	AddElevationToGPX(context.Context, *GPXRequest) (*GPXResponse, error)
+	// raw GeoTIFF tiles, corresponds to POST /v1/rawtif (one message per tile)
+	GetRawTIF(*TileRequest, grpc.ServerStreamingServer[TileOutput]) error
+	// hillshade tiles, corresponds to POST /v1/hillshade (one message per tile and shading variant)
+	GetHillshade(*HillshadeRequest, grpc.ServerStreamingServer[TileOutput]) error
+	// slope tiles, corresponds to POST /v1/slope (one message per tile)
+	GetSlope(*RasterRequest, grpc.ServerStreamingServer[TileOutput]) error
+	// aspect tiles, corresponds to POST /v1/aspect (one message per tile)
+	GetAspect(*RasterRequest, grpc.ServerStreamingServer[TileOutput]) error
+	// contour lines, corresponds to POST /v1/contours (one message per tile)
+	GetContours(*ContoursRequest, grpc.ServerStreamingServer[TileOutput]) error
+	mustEmbedUnimplementedElevationServiceServer()
+}
+
+// UnimplementedElevationServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedElevationServiceServer struct{}
+
+func (UnimplementedElevationServiceServer) GetPoint(context.Context, *PointRequest) (*PointResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetPoint not implemented")
+}
+func (UnimplementedElevationServiceServer) GetUTMPoint(context.Context, *UTMPointRequest) (*PointResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetUTMPoint not implemented")
+}
+func (UnimplementedElevationServiceServer) StreamPoints(grpc.BidiStreamingServer[PointRequest, PointResponse]) error {
+	return status.Error(codes.Unimplemented, "method StreamPoints not implemented")
+}
+func (UnimplementedElevationServiceServer) StreamUTMPoints(grpc.BidiStreamingServer[UTMPointRequest, PointResponse]) error {
+	return status.Error(codes.Unimplemented, "method StreamUTMPoints not implemented")
+}
+func (UnimplementedElevationServiceServer) AddElevationToGPX(context.Context, *GPXRequest) (*GPXResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AddElevationToGPX not implemented")
+}
+func (UnimplementedElevationServiceServer) GetRawTIF(*TileRequest, grpc.ServerStreamingServer[TileOutput]) error {
+	return status.Error(codes.Unimplemented, "method GetRawTIF not implemented")
+}
+func (UnimplementedElevationServiceServer) GetHillshade(*HillshadeRequest, grpc.ServerStreamingServer[TileOutput]) error {
+	return status.Error(codes.Unimplemented, "method GetHillshade not implemented")
+}
+func (UnimplementedElevationServiceServer) GetSlope(*RasterRequest, grpc.ServerStreamingServer[TileOutput]) error {
+	return status.Error(codes.Unimplemented, "method GetSlope not implemented")
+}
+func (UnimplementedElevationServiceServer) GetAspect(*RasterRequest, grpc.ServerStreamingServer[TileOutput]) error {
+	return status.Error(codes.Unimplemented, "method GetAspect not implemented")
+}
+func (UnimplementedElevationServiceServer) GetContours(*ContoursRequest, grpc.ServerStreamingServer[TileOutput]) error {
+	return status.Error(codes.Unimplemented, "method GetContours not implemented")
+}
+func (UnimplementedElevationServiceServer) mustEmbedUnimplementedElevationServiceServer() {}
+func (UnimplementedElevationServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeElevationServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ElevationServiceServer will
+// result in compilation errors.
+type UnsafeElevationServiceServer interface {
+	mustEmbedUnimplementedElevationServiceServer()
+}
+
+func RegisterElevationServiceServer(s grpc.ServiceRegistrar, srv ElevationServiceServer) {
+	// If the following call panics, it indicates UnimplementedElevationServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ElevationService_ServiceDesc, srv)
+}
+
+func _ElevationService_GetPoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ElevationServiceServer).GetPoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ElevationService_GetPoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ElevationServiceServer).GetPoint(ctx, req.(*PointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ElevationService_GetUTMPoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UTMPointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ElevationServiceServer).GetUTMPoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ElevationService_GetUTMPoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ElevationServiceServer).GetUTMPoint(ctx, req.(*UTMPointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ElevationService_StreamPoints_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ElevationServiceServer).StreamPoints(&grpc.GenericServerStream[PointRequest, PointResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ElevationService_StreamPointsServer = grpc.BidiStreamingServer[PointRequest, PointResponse]
+
+func _ElevationService_StreamUTMPoints_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ElevationServiceServer).StreamUTMPoints(&grpc.GenericServerStream[UTMPointRequest, PointResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ElevationService_StreamUTMPointsServer = grpc.BidiStreamingServer[UTMPointRequest, PointResponse]
+
+func _ElevationService_AddElevationToGPX_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GPXRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ElevationServiceServer).AddElevationToGPX(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ElevationService_AddElevationToGPX_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ElevationServiceServer).AddElevationToGPX(ctx, req.(*GPXRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ElevationService_GetRawTIF_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TileRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ElevationServiceServer).GetRawTIF(m, &grpc.GenericServerStream[TileRequest, TileOutput]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ElevationService_GetRawTIFServer = grpc.ServerStreamingServer[TileOutput]
+
+func _ElevationService_GetHillshade_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(HillshadeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ElevationServiceServer).GetHillshade(m, &grpc.GenericServerStream[HillshadeRequest, TileOutput]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ElevationService_GetHillshadeServer = grpc.ServerStreamingServer[TileOutput]
+
+func _ElevationService_GetSlope_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RasterRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ElevationServiceServer).GetSlope(m, &grpc.GenericServerStream[RasterRequest, TileOutput]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ElevationService_GetSlopeServer = grpc.ServerStreamingServer[TileOutput]
+
+func _ElevationService_GetAspect_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RasterRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ElevationServiceServer).GetAspect(m, &grpc.GenericServerStream[RasterRequest, TileOutput]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ElevationService_GetAspectServer = grpc.ServerStreamingServer[TileOutput]
+
+func _ElevationService_GetContours_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ContoursRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ElevationServiceServer).GetContours(m, &grpc.GenericServerStream[ContoursRequest, TileOutput]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ElevationService_GetContoursServer = grpc.ServerStreamingServer[TileOutput]
+
+// ElevationService_ServiceDesc is the grpc.ServiceDesc for ElevationService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ElevationService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dtm.elevation.v1.ElevationService",
+	HandlerType: (*ElevationServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPoint",
+			Handler:    _ElevationService_GetPoint_Handler,
+		},
+		{
+			MethodName: "GetUTMPoint",
+			Handler:    _ElevationService_GetUTMPoint_Handler,
+		},
+		{
+			MethodName: "AddElevationToGPX",
+			Handler:    _ElevationService_AddElevationToGPX_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamPoints",
+			Handler:       _ElevationService_StreamPoints_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "StreamUTMPoints",
+			Handler:       _ElevationService_StreamUTMPoints_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "GetRawTIF",
+			Handler:       _ElevationService_GetRawTIF_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetHillshade",
+			Handler:       _ElevationService_GetHillshade_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetSlope",
+			Handler:       _ElevationService_GetSlope_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetAspect",
+			Handler:       _ElevationService_GetAspect_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetContours",
+			Handler:       _ElevationService_GetContours_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/dtm-elevation-service.proto",
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sourceChecksum represents the cached checksum of a source GeoTIFF file (valid as long as size and modification time match).
+type sourceChecksum struct {
+	size    int64
+	modTime time.Time
+	sha256  string
+}
+
+// sourceChecksums caches the checksums of source GeoTIFF files (few bytes per tile, hashing a tile reads the whole file)
+var (
+	sourceChecksums      = make(map[string]sourceChecksum)
+	sourceChecksumsMutex sync.Mutex
+)
+
+// virtualSourceTiles maps virtual tiles (in-memory mosaics, e.g. administrative areas) to their source tiles
+var virtualSourceTiles sync.Map
+
+/*
+registerVirtualSourceTiles registers the source tiles of a virtual tile (in-memory file). The registration is
+removed together with the in-memory file (see removeVSIMemFiles).
+*/
+func registerVirtualSourceTiles(path string, tiles []TileMetadata) {
+	virtualSourceTiles.Store(path, tiles)
+}
+
+/*
+getSourceFiles returns the provenance (delivery file name and SHA-256 checksum) of the source GeoTIFF files of
+the given tiles. Virtual tiles are resolved to their source tiles.
+*/
+func getSourceFiles(tiles ...TileMetadata) ([]SourceFile, error) {
+	sourceFiles := []SourceFile{}
+
+	for _, tile := range tiles {
+		if strings.HasPrefix(tile.Path, "/vsimem/") {
+			value, found := virtualSourceTiles.Load(tile.Path)
+			if !found {
+				return sourceFiles, fmt.Errorf("source tiles of virtual tile [%s] unknown", tile.Index)
+			}
+			virtualSourceFiles, err := getSourceFiles(value.([]TileMetadata)...)
+			if err != nil {
+				return sourceFiles, err
+			}
+			sourceFiles = append(sourceFiles, virtualSourceFiles...)
+			continue
+		}
+
+		checksum, err := getSourceChecksum(tile.Path)
+		if err != nil {
+			return sourceFiles, fmt.Errorf("error [%w] at getSourceChecksum()", err)
+		}
+		sourceFiles = append(sourceFiles, SourceFile{Filename: filepath.Base(tile.Path), SHA256: checksum})
+	}
+
+	return sourceFiles, nil
+}
+
+/*
+getSourceChecksum returns the SHA-256 checksum (hex) of a source GeoTIFF file (from checksum cache if file unchanged).
+*/
+func getSourceChecksum(filename string) (string, error) {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return "", fmt.Errorf("error [%w] at os.Stat(), file: %s", err, filename)
+	}
+
+	sourceChecksumsMutex.Lock()
+	cached, found := sourceChecksums[filename]
+	sourceChecksumsMutex.Unlock()
+	if found && cached.size == info.Size() && cached.modTime.Equal(info.ModTime()) {
+		return cached.sha256, nil
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return "", fmt.Errorf("error [%w] at os.Open(), file: %s", err, filename)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", fmt.Errorf("error [%w] at io.Copy(), file: %s", err, filename)
+	}
+	checksum := hex.EncodeToString(hash.Sum(nil))
+
+	sourceChecksumsMutex.Lock()
+	sourceChecksums[filename] = sourceChecksum{size: info.Size(), modTime: info.ModTime(), sha256: checksum}
+	sourceChecksumsMutex.Unlock()
+
+	return checksum, nil
+}
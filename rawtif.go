@@ -1,19 +1,23 @@
 package main
 
 import (
-	"bytes"
-	"compress/gzip"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"math"
 	"net/http"
 	"os"
+	"slices"
 	"strings"
 	"sync/atomic"
 )
 
+// max number of gaps in nodata statistics (largest gaps)
+const maxNoDataGaps = 100
+
 /*
 rawtifRequest handles 'rawtif request' from client.
 */
@@ -33,18 +37,14 @@ func rawtifRequest(writer http.ResponseWriter, request *http.Request) {
 		// check specifically for the error returned by MaxBytesReader
 		var maxBytesErr *http.MaxBytesError
 		if errors.As(err, &maxBytesErr) {
-			slog.Warn("rawtif request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
-			rawtifResponse.Attributes.Error.Code = "11000"
-			rawtifResponse.Attributes.Error.Title = "request body too large"
-			rawtifResponse.Attributes.Error.Detail = fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit)
-			buildRawTIFResponse(writer, http.StatusRequestEntityTooLarge, rawtifResponse)
+			slog.WarnContext(request.Context(), "rawtif request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
+			rawtifResponse.Attributes.Error = newErrorObject(EndpointRawTIF, ReasonRequestBodyTooLarge, fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit))
+			buildRawTIFResponse(writer, request, http.StatusRequestEntityTooLarge, rawtifResponse)
 		} else {
 			// handle other read errors
-			slog.Warn("rawtif request: error reading request body", "error", err, "ID", "unknown")
-			rawtifResponse.Attributes.Error.Code = "11020"
-			rawtifResponse.Attributes.Error.Title = "error reading request body"
-			rawtifResponse.Attributes.Error.Detail = err.Error()
-			buildRawTIFResponse(writer, http.StatusBadRequest, rawtifResponse)
+			slog.WarnContext(request.Context(), "rawtif request: error reading request body", "error", err, "ID", "unknown")
+			rawtifResponse.Attributes.Error = newErrorObject(EndpointRawTIF, ReasonReadingRequestBody, err.Error())
+			buildRawTIFResponse(writer, request, http.StatusBadRequest, rawtifResponse)
 		}
 		return
 	}
@@ -53,11 +53,9 @@ func rawtifRequest(writer http.ResponseWriter, request *http.Request) {
 	rawtifRequest := RawTIFRequest{}
 	err = json.Unmarshal(bodyData, &rawtifRequest)
 	if err != nil {
-		slog.Warn("rawtif request: error unmarshaling request body", "error", err, "ID", "unknown")
-		rawtifResponse.Attributes.Error.Code = "11040"
-		rawtifResponse.Attributes.Error.Title = "error unmarshaling request body"
-		rawtifResponse.Attributes.Error.Detail = err.Error()
-		buildRawTIFResponse(writer, http.StatusBadRequest, rawtifResponse)
+		slog.WarnContext(request.Context(), "rawtif request: error unmarshaling request body", "error", err, "ID", "unknown")
+		rawtifResponse.Attributes.Error = newErrorObject(EndpointRawTIF, ReasonUnmarshalingRequestBody, err.Error())
+		buildRawTIFResponse(writer, request, http.StatusBadRequest, rawtifResponse)
 		return
 	}
 
@@ -70,11 +68,9 @@ func rawtifRequest(writer http.ResponseWriter, request *http.Request) {
 	// verify request data
 	err = verifyRawTIFRequestData(request, rawtifRequest)
 	if err != nil {
-		slog.Warn("rawtif request: error verifying request data", "error", err, "ID", rawtifRequest.ID)
-		rawtifResponse.Attributes.Error.Code = "11060"
-		rawtifResponse.Attributes.Error.Title = "error verifying request data"
-		rawtifResponse.Attributes.Error.Detail = err.Error()
-		buildRawTIFResponse(writer, http.StatusBadRequest, rawtifResponse)
+		slog.WarnContext(request.Context(), "rawtif request: error verifying request data", "error", err, "ID", rawtifRequest.ID)
+		rawtifResponse.Attributes.Error = newErrorObject(EndpointRawTIF, ReasonVerifyingRequestData, err.Error())
+		buildRawTIFResponse(writer, request, http.StatusBadRequest, rawtifResponse)
 		return
 	}
 
@@ -91,32 +87,66 @@ func rawtifRequest(writer http.ResponseWriter, request *http.Request) {
 	// get all tiles (metadata) for given UTM coordinates
 	tiles, err = getAllTilesUTM(zone, easting, northing)
 	if err != nil {
-		slog.Warn("rawtif request: error getting GeoTIFF tile for UTM coordinates", "error", err,
+		slog.WarnContext(request.Context(), "rawtif request: error getting GeoTIFF tile for UTM coordinates", "error", err,
 			"easting", easting, "northing", northing, "zone", zone, "ID", rawtifRequest.ID)
-		rawtifResponse.Attributes.Error.Code = "11080"
-		rawtifResponse.Attributes.Error.Title = "getting GeoTIFF tile for UTM coordinates"
-		rawtifResponse.Attributes.Error.Detail = err.Error()
-		buildRawTIFResponse(writer, http.StatusBadRequest, rawtifResponse)
+		if errors.Is(err, ErrOutsideCoverage) {
+			rawtifResponse.Attributes.Error = newErrorObject(EndpointRawTIF, ReasonOutsideCoverage, describeOutsideCoverage(err))
+			buildRawTIFResponse(writer, request, http.StatusNotFound, rawtifResponse)
+			return
+		}
+		if errors.Is(err, ErrSourceUnavailable) {
+			rawtifResponse.Attributes.Error = newErrorObject(EndpointRawTIF, ReasonSourceUnavailable, err.Error())
+			buildRawTIFResponse(writer, request, http.StatusServiceUnavailable, rawtifResponse)
+			return
+		}
+		rawtifResponse.Attributes.Error = newErrorObject(EndpointRawTIF, ReasonGettingTileUTM, err.Error())
+		buildRawTIFResponse(writer, request, http.StatusBadRequest, rawtifResponse)
+		return
+	}
+
+	// conditional request: unchanged response (same parameters, same tiles) needs no processing
+	etag := buildETag("rawtif", rawtifRequest, tiles)
+	if checkNotModified(writer, request, etag) {
 		return
 	}
 
 	// build rawtif for all existing tiles
-	for _, tile := range tiles {
+	rawtifs, tileErrors, err := generateObjectsForTiles(request.Context(), tiles, func(tile TileMetadata) (RawTIF, error) {
 		rawtif, err := generateRawTIFObjectForTile(tile)
 		if err != nil {
-			slog.Warn("rawtif request: error generating rawtif object for tile", "error", err, "ID", rawtifRequest.ID)
-			rawtifResponse.Attributes.Error.Code = "11120"
-			rawtifResponse.Attributes.Error.Title = "error generating rawtif object for tile"
-			rawtifResponse.Attributes.Error.Detail = err.Error()
-			buildRawTIFResponse(writer, http.StatusBadRequest, rawtifResponse)
-			return
+			return rawtif, err
+		}
+
+		// nodata coverage (users know before opening the file whether the tile is partially empty)
+		noDataStatistics, err := calculateNoDataStatistics(request.Context(), tile)
+		if err != nil {
+			return rawtif, fmt.Errorf("error [%w] at calculateNoDataStatistics()", err)
 		}
-		rawtifResponse.Attributes.RawTIFs = append(rawtifResponse.Attributes.RawTIFs, rawtif)
+		rawtif.NoDataStatistics = &noDataStatistics
+		return rawtif, nil
+	})
+	if err != nil {
+		slog.WarnContext(request.Context(), "rawtif request: error generating rawtif object for tile", "error", err, "ID", rawtifRequest.ID)
+		rawtifResponse.Attributes.Error = newErrorObject(EndpointRawTIF, ReasonGeneratingObject, err.Error())
+		buildRawTIFResponse(writer, request, http.StatusBadRequest, rawtifResponse)
+		return
 	}
+	rawtifResponse.Attributes.RawTIFs = rawtifs
+	rawtifResponse.Attributes.TileErrors = tileErrors
+	if len(tileErrors) > 0 {
+		rawtifResponse.Attributes.Warnings = append(rawtifResponse.Attributes.Warnings,
+			fmt.Sprintf("%d of %d tiles could not be processed (see TileErrors)", len(tileErrors), len(tiles)))
+		slog.WarnContext(request.Context(), "rawtif request: partial success, tiles could not be processed", "tile errors", tileErrors, "ID", rawtifRequest.ID)
+	}
+	addUsage(request.Context(), 0, len(rawtifs))
 
 	// success response
 	rawtifResponse.Attributes.IsError = false
-	buildRawTIFResponse(writer, http.StatusOK, rawtifResponse)
+	if len(tileErrors) == 0 {
+		// partial responses are not cacheable
+		setETag(writer, etag)
+	}
+	buildRawTIFResponse(writer, request, http.StatusOK, rawtifResponse)
 }
 
 /*
@@ -137,18 +167,6 @@ func verifyRawTIFRequestData(request *http.Request, rawtifRequest RawTIFRequest)
 		return fmt.Errorf("unexpected or missing HTTP header field Content-Type, value = [%s], expected 'application/json'", contentType)
 	}
 
-	// verify HTTP header
-	accept := request.Header.Get("Accept")
-	isAcceptValid := true
-	switch {
-	case strings.HasPrefix(strings.ToLower(accept), "application/json"):
-	default:
-		isAcceptValid = false
-	}
-	if !isAcceptValid {
-		return fmt.Errorf("unexpected or missing HTTP header field Accept, value = [%s], expected 'application/json'", accept)
-	}
-
 	// verify Type
 	if rawtifRequest.Type != TypeRawTIFRequest {
 		return fmt.Errorf("unexpected request Type [%v]", rawtifRequest.Type)
@@ -174,56 +192,15 @@ buildRawTIFResponse builds HTTP responses with specified status and body.
 It sets the Content-Type and Content-Length headers before writing the response body.
 This function is used to construct consistent HTTP responses throughout the application.
 */
-func buildRawTIFResponse(writer http.ResponseWriter, httpStatus int, rawtifResponse RawTIFResponse) {
-	// log limit length of body (e.g., the rawtif objects as part of the body can be very large)
-	maxBodyLength := 1024
-
-	// CORS: allow requests from any origin
-	writer.Header().Set("Access-Control-Allow-Origin", "*")
-	// CORS: allowed methods
-	writer.Header().Set("Access-Control-Allow-Methods", "POST")
-	// CORS: allowed headers
-	writer.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
-	// marshal response
-	body, err := json.MarshalIndent(rawtifResponse, "", "  ")
-	if err != nil {
-		slog.Error("error marshaling point response", "error", err, "body length", len(body),
-			fmt.Sprintf("body (limited to first %d bytes)", maxBodyLength), body[:maxBodyLength])
+func buildRawTIFResponse(writer http.ResponseWriter, request *http.Request, httpStatus int, rawtifResponse RawTIFResponse) {
+	// response metadata (versions, processing duration, cache hit)
+	rawtifResponse.Meta = newResponseMeta(request.Context())
 
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-
-	// gzip response body
-	var bytesBuffer bytes.Buffer
-	gz := gzip.NewWriter(&bytesBuffer)
+	// skip compression if payload is already compressed (e.g. PNG)
+	skipCompression := len(rawtifResponse.Attributes.RawTIFs) > 0 && isCompressedDataFormat(rawtifResponse.Attributes.RawTIFs[0].DataFormat)
 
-	_, err = gz.Write(body)
-	if err != nil {
-		slog.Error("error [%v] at gz.Write()", "error", err)
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-
-	err = gz.Close()
-	if err != nil {
-		slog.Error("error [%v] at gz.Close()", "error", err)
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-
-	// set headers
-	writer.Header().Set("Content-Encoding", "gzip")
-	writer.Header().Set("Content-Type", JSONAPIMediaType)
-	writer.WriteHeader(httpStatus)
-
-	// send response
-	_, err = writer.Write(bytesBuffer.Bytes())
-	if err != nil {
-		slog.Error("error writing HTTP response body", "error", err, "body length", len(body),
-			fmt.Sprintf("body (limited to first %d bytes)", maxBodyLength), body[:maxBodyLength])
-	}
+	// encode (streaming) and send response
+	streamJSONResponse(writer, request, httpStatus, rawtifResponse, skipCompression)
 }
 
 /*
@@ -241,6 +218,7 @@ func generateRawTIFObjectForTile(tile TileMetadata) (RawTIF, error) {
 	// set RawTIF return structure
 	rawtif.Data = data
 	rawtif.DataFormat = "GeoTIFF"
+	rawtif.Filename = buildObjectFilename(tile.Index, "dtm", "", tile.Actuality, rawtif.DataFormat)
 	rawtif.Actuality = tile.Actuality
 	rawtif.Origin = tile.Source
 	rawtif.TileIndex = tile.Index
@@ -255,5 +233,99 @@ func generateRawTIFObjectForTile(tile TileMetadata) (RawTIF, error) {
 	}
 	rawtif.Attribution = attribution
 
+	// get provenance of source files (delivery file name and checksum)
+	sourceFiles, err := getSourceFiles(tile)
+	if err != nil {
+		slog.Error("rawtif request: error getting source files", "error", err, "tile", tile.Index)
+	}
+	rawtif.SourceFiles = sourceFiles
+
 	return rawtif, nil
 }
+
+/*
+calculateNoDataStatistics calculates the nodata coverage of a tile: share of nodata cells and gaps (connected nodata
+cells, 4-neighborhood) with bounding box. Only the largest gaps are returned.
+*/
+func calculateNoDataStatistics(ctx context.Context, tile TileMetadata) (NoDataStatistics, error) {
+	var statistics NoDataStatistics
+
+	// lookup response cache
+	cacheKey := buildResponseCacheKey("rawtif-nodata", tile.Index, tile.Actuality)
+	cached, found := responseCache.Get(cacheKey)
+	recordCacheLookup(ctx, found)
+	if found {
+		return cached.(NoDataStatistics), nil
+	}
+
+	grid, err := getTileGrid(tile.Path)
+	if err != nil {
+		return statistics, fmt.Errorf("error [%w] at getTileGrid()", err)
+	}
+
+	isNoData := func(index int) bool {
+		value := grid.values[index]
+		return math.IsNaN(float64(value)) || (grid.hasNoData && float64(value) == float64(float32(grid.noData))) || value < -9998.9
+	}
+
+	statistics.Cells = grid.sizeX * grid.sizeY
+	visited := make([]bool, statistics.Cells)
+	var stack []int
+	for start := range statistics.Cells {
+		if visited[start] || !isNoData(start) {
+			continue
+		}
+
+		// flood fill of gap
+		minColumn, minRow, maxColumn, maxRow := grid.sizeX, grid.sizeY, -1, -1
+		cells := 0
+		visited[start] = true
+		stack = append(stack[:0], start)
+		for len(stack) > 0 {
+			index := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			cells++
+			column, row := index%grid.sizeX, index/grid.sizeX
+			minColumn, minRow = min(minColumn, column), min(minRow, row)
+			maxColumn, maxRow = max(maxColumn, column), max(maxRow, row)
+
+			for _, neighbor := range [4][2]int{{column - 1, row}, {column + 1, row}, {column, row - 1}, {column, row + 1}} {
+				if neighbor[0] < 0 || neighbor[0] >= grid.sizeX || neighbor[1] < 0 || neighbor[1] >= grid.sizeY {
+					continue
+				}
+				neighborIndex := neighbor[1]*grid.sizeX + neighbor[0]
+				if !visited[neighborIndex] && isNoData(neighborIndex) {
+					visited[neighborIndex] = true
+					stack = append(stack, neighborIndex)
+				}
+			}
+		}
+
+		// bounding box of gap (north-up grid, gt[5] is negative)
+		gt := grid.geoTransform
+		statistics.NoDataCells += cells
+		statistics.GapCount++
+		statistics.Gaps = append(statistics.Gaps, NoDataGap{
+			Cells:       cells,
+			MinEasting:  gt[0] + float64(minColumn)*gt[1],
+			MaxEasting:  gt[0] + float64(maxColumn+1)*gt[1],
+			MinNorthing: gt[3] + float64(maxRow+1)*gt[5],
+			MaxNorthing: gt[3] + float64(minRow)*gt[5],
+			AtBorder:    minColumn == 0 || minRow == 0 || maxColumn == grid.sizeX-1 || maxRow == grid.sizeY-1,
+		})
+	}
+	if statistics.Cells > 0 {
+		statistics.NoDataShare = 100.0 * float64(statistics.NoDataCells) / float64(statistics.Cells)
+	}
+
+	// largest gaps only
+	slices.SortStableFunc(statistics.Gaps, func(a, b NoDataGap) int { return b.Cells - a.Cells })
+	if len(statistics.Gaps) > maxNoDataGaps {
+		statistics.Gaps = statistics.Gaps[:maxNoDataGaps]
+	}
+
+	// add to response cache
+	responseCache.Add(cacheKey, statistics, 64*len(statistics.Gaps))
+
+	return statistics, nil
+}
@@ -2,85 +2,42 @@ package main
 
 import (
 	"encoding/csv"
-	"encoding/json"
 	"fmt"
 	"log/slog"
 	"os"
-	"sort"
+	"sync"
+
+	"klaus/elevation/dtm-elevation-service/pkg/dtm"
 )
 
 // TileMetadata represents meta data about a tile.
-type TileMetadata struct {
-	Index     string // (hash) index of tile (e.g. 32_383_5802)
-	Path      string // path and file name (e.g. /Downloads/dgm1_32_383_5802_1_ni_2017.tif)
-	Source    string // source of tile (e.g. DE-NI)
-	Actuality string // actuality of Airborne Laser Scanning (ALS) (e.g. 2017-04-19)
-}
+type TileMetadata = dtm.TileMetadata
 
-// Repository represents repository for all tiles (readonly after initialization).
-var Repository map[string]TileMetadata
+// Repository represents repository for all tiles incl. tiles of disabled sources (readonly after build, replaced as a whole on reload).
+var Repository *dtm.Repository
+
+// repositoryMutex guards replacing the repository (configuration reload)
+var repositoryMutex sync.RWMutex
 
 /*
-buildRepository builds global repository with all tile meta data.
-Each federal state provides a complete set of tiles for its territory.
-At the border between two federal states, the tiles exist in duplicate.
-Example: "32_410_5812"
-Tile for NW: dgm1_32_410_5812_1_nw_2024.tif -> index '32_410_5812'
-Tile for NI: dgm1_32_410_5812_1_ni_2017.tif -> index '32_410_5812_2'
-We need both tiles, measurements beyond the boundary can be designated as -9999 (no data).
-Also possible for a tile: state, neighbor 1, neighbor 2
+buildRepository builds global repository with all tile meta data (see dtm.BuildRepository).
+Tiles of disabled sources (see configuration 'DisabledSources') are kept separately.
+The global repository is replaced only if the build was successful.
 */
-func buildRepository() error {
-	// initialize global tile repository map (Germany has estimated 360.000 entries)
-	Repository = make(map[string]TileMetadata, 256*1024)
-
-	stateRepositories := progConfig.TileRepositories
-
-	// iterate over state repositories
-	numberOfPrimaryTiles := 0
-	numberOfSecondaryTiles := 0
-	numberOfTertiaryTiles := 0
-	for _, stateRepository := range stateRepositories {
-		// read state repository
-		stateTileMetadata := []TileMetadata{}
-		data, err := os.ReadFile(stateRepository)
-		if err != nil {
-			return fmt.Errorf("building global tile repository: error [%w] at os.ReadFile()", err)
-		}
-
-		err = json.Unmarshal(data, &stateTileMetadata)
-		if err != nil {
-			return fmt.Errorf("building global tile repository: error [%w] at json.Unmarshal()", err)
-		}
-
-		slog.Info("processing state repository tile meta data", "repository", stateRepository, "entries", len(stateTileMetadata))
-
-		// build global repository map
-		for _, entry := range stateTileMetadata {
-			// check if primary entry already exists
-			_, primaryExists := Repository[entry.Index]
-			if !primaryExists {
-				Repository[entry.Index] = entry
-				numberOfPrimaryTiles++
-				continue
-			}
-			// check if secondary entry already exists
-			index := entry.Index + "_2"
-			_, secondaryExists := Repository[index]
-			if !secondaryExists {
-				Repository[index] = entry
-				numberOfSecondaryTiles++
-				continue
-			}
-			// add entry as tertiary entry
-			index = entry.Index + "_3"
-			Repository[index] = entry
-			numberOfTertiaryTiles++
-		}
+func buildRepository(stateRepositories []string, disabledSources []string) error {
+	repository, stats, err := dtm.BuildRepository(stateRepositories, disabledSources)
+	if err != nil {
+		return fmt.Errorf("building global tile repository: error [%w] at dtm.BuildRepository()", err)
 	}
 
-	slog.Info("global tile repository successfully build", "entries", len(Repository), "primary tiles", numberOfPrimaryTiles,
-		"secondary tiles", numberOfSecondaryTiles, "tertiary tiles", numberOfTertiaryTiles)
+	// replace global repository
+	repositoryMutex.Lock()
+	Repository = repository
+	repositoryMutex.Unlock()
+
+	slog.Info("global tile repository successfully build", "state repositories", stateRepositories, "entries", repository.Len(),
+		"primary tiles", stats.PrimaryTiles, "secondary tiles", stats.SecondaryTiles, "tertiary tiles", stats.TertiaryTiles,
+		"disabled tiles", stats.DisabledTiles, "disabled sources", disabledSources)
 
 	return nil
 }
@@ -89,14 +46,8 @@ func buildRepository() error {
 saveRepository saves repository as sorted csv file.
 */
 func saveRepository() error {
-	// extract keys (Index) from map
-	keys := make([]string, 0, len(Repository))
-	for k := range Repository {
-		keys = append(keys, k)
-	}
-
-	// sort keys
-	sort.Strings(keys)
+	// sorted keys (Index)
+	keys := Repository.Indices()
 
 	// open csv file
 	filename := "repository.csv"
@@ -119,7 +70,7 @@ func saveRepository() error {
 
 	// iterate over sorted keys
 	for _, key := range keys {
-		metadata, ok := Repository[key]
+		metadata, ok := Repository.Tile(key)
 		if !ok {
 			return fmt.Errorf("warning: key [%s] not found during writing", key)
 		}
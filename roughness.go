@@ -1,8 +1,7 @@
 package main
 
 import (
-	"bytes"
-	"compress/gzip"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,8 +10,11 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync/atomic"
+
+	"klaus/elevation/dtm-elevation-service/pkg/dtm"
 )
 
 /*
@@ -34,18 +36,14 @@ func roughnessRequest(writer http.ResponseWriter, request *http.Request) {
 		// check specifically for the error returned by MaxBytesReader
 		var maxBytesErr *http.MaxBytesError
 		if errors.As(err, &maxBytesErr) {
-			slog.Warn("roughness request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
-			roughnessResponse.Attributes.Error.Code = "10000"
-			roughnessResponse.Attributes.Error.Title = "request body too large"
-			roughnessResponse.Attributes.Error.Detail = fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit)
-			buildRoughnessResponse(writer, http.StatusRequestEntityTooLarge, roughnessResponse)
+			slog.WarnContext(request.Context(), "roughness request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
+			roughnessResponse.Attributes.Error = newErrorObject(EndpointRoughness, ReasonRequestBodyTooLarge, fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit))
+			buildRoughnessResponse(writer, request, http.StatusRequestEntityTooLarge, roughnessResponse)
 		} else {
 			// handle other read errors
-			slog.Warn("roughness request: error reading request body", "error", err, "ID", "unknown")
-			roughnessResponse.Attributes.Error.Code = "10020"
-			roughnessResponse.Attributes.Error.Title = "error reading request body"
-			roughnessResponse.Attributes.Error.Detail = err.Error()
-			buildRoughnessResponse(writer, http.StatusBadRequest, roughnessResponse)
+			slog.WarnContext(request.Context(), "roughness request: error reading request body", "error", err, "ID", "unknown")
+			roughnessResponse.Attributes.Error = newErrorObject(EndpointRoughness, ReasonReadingRequestBody, err.Error())
+			buildRoughnessResponse(writer, request, http.StatusBadRequest, roughnessResponse)
 		}
 		return
 	}
@@ -54,25 +52,61 @@ func roughnessRequest(writer http.ResponseWriter, request *http.Request) {
 	roughnessRequest := RoughnessRequest{}
 	err = json.Unmarshal(bodyData, &roughnessRequest)
 	if err != nil {
-		slog.Warn("roughness request: error unmarshaling request body", "error", err, "ID", "unknown")
-		roughnessResponse.Attributes.Error.Code = "10040"
-		roughnessResponse.Attributes.Error.Title = "error unmarshaling request body"
-		roughnessResponse.Attributes.Error.Detail = err.Error()
-		buildRoughnessResponse(writer, http.StatusBadRequest, roughnessResponse)
+		slog.WarnContext(request.Context(), "roughness request: error unmarshaling request body", "error", err, "ID", "unknown")
+		roughnessResponse.Attributes.Error = newErrorObject(EndpointRoughness, ReasonUnmarshalingRequestBody, err.Error())
+		buildRoughnessResponse(writer, request, http.StatusBadRequest, roughnessResponse)
 		return
 	}
 
 	// verify request data
 	err = verifyRoughnessRequestData(request, roughnessRequest)
 	if err != nil {
-		slog.Warn("roughness request: error verifying request data", "error", err, "ID", roughnessRequest.ID)
-		roughnessResponse.Attributes.Error.Code = "10060"
-		roughnessResponse.Attributes.Error.Title = "error verifying request data"
-		roughnessResponse.Attributes.Error.Detail = err.Error()
-		buildRoughnessResponse(writer, http.StatusBadRequest, roughnessResponse)
+		slog.WarnContext(request.Context(), "roughness request: error verifying request data", "error", err, "ID", roughnessRequest.ID)
+		roughnessResponse.Attributes.Error = newErrorObject(EndpointRoughness, ReasonVerifyingRequestData, err.Error())
+		buildRoughnessResponse(writer, request, http.StatusBadRequest, roughnessResponse)
 		return
 	}
 
+	// resolve place name (geocoding) into lon/lat coordinates
+	if roughnessRequest.Attributes.Place != "" {
+		place, err := geocodePlace(request.Context(), roughnessRequest.Attributes.Place)
+		if err != nil {
+			slog.WarnContext(request.Context(), "roughness request: error geocoding place", "error", err, "place", roughnessRequest.Attributes.Place, "ID", roughnessRequest.ID)
+			roughnessResponse.ID = roughnessRequest.ID
+			roughnessResponse.Attributes.Place = roughnessRequest.Attributes.Place
+			if errors.Is(err, ErrPlaceNotFound) {
+				roughnessResponse.Attributes.Error = newErrorObject(EndpointRoughness, ReasonPlaceNotFound, err.Error())
+				buildRoughnessResponse(writer, request, http.StatusNotFound, roughnessResponse)
+				return
+			}
+			if errors.Is(err, ErrGeocoderUnavailable) {
+				roughnessResponse.Attributes.Error = newErrorObject(EndpointRoughness, ReasonGeocoderUnavailable, err.Error())
+				buildRoughnessResponse(writer, request, http.StatusServiceUnavailable, roughnessResponse)
+				return
+			}
+			roughnessResponse.Attributes.Error = newErrorObject(EndpointRoughness, ReasonVerifyingRequestData, err.Error())
+			buildRoughnessResponse(writer, request, http.StatusBadRequest, roughnessResponse)
+			return
+		}
+		roughnessRequest.Attributes.Zone = 0
+		roughnessRequest.Attributes.Longitude = place.Longitude
+		roughnessRequest.Attributes.Latitude = place.Latitude
+		roughnessResponse.Attributes.PlaceName = place.DisplayName
+	}
+
+	// named color table (stored server-side) instead of color text file content
+	if roughnessRequest.Attributes.ColorTableName != "" {
+		roughnessRequest.Attributes.ColorTextFileContent, err = resolveColorTable(request.Context(), roughnessRequest.Attributes.ColorTableName, roughnessRequest.Attributes.ColorTextFileContent)
+		if err != nil {
+			slog.WarnContext(request.Context(), "roughness request: error resolving color table", "error", err, "name", roughnessRequest.Attributes.ColorTableName, "ID", roughnessRequest.ID)
+			roughnessResponse.ID = roughnessRequest.ID
+			roughnessResponse.Attributes.ColorTableName = roughnessRequest.Attributes.ColorTableName
+			roughnessResponse.Attributes.Error = newErrorObject(EndpointRoughness, ReasonVerifyingRequestData, err.Error())
+			buildRoughnessResponse(writer, request, http.StatusBadRequest, roughnessResponse)
+			return
+		}
+	}
+
 	// copy request parameters into response
 	roughnessResponse.ID = roughnessRequest.ID
 	roughnessResponse.Attributes.Zone = roughnessRequest.Attributes.Zone
@@ -80,8 +114,14 @@ func roughnessRequest(writer http.ResponseWriter, request *http.Request) {
 	roughnessResponse.Attributes.Northing = roughnessRequest.Attributes.Northing
 	roughnessResponse.Attributes.Longitude = roughnessRequest.Attributes.Longitude
 	roughnessResponse.Attributes.Latitude = roughnessRequest.Attributes.Latitude
+	roughnessResponse.Attributes.Place = roughnessRequest.Attributes.Place
+	roughnessResponse.Attributes.OutputFormat = roughnessRequest.Attributes.OutputFormat
 	roughnessResponse.Attributes.ColorTextFileContent = roughnessRequest.Attributes.ColorTextFileContent
+	roughnessResponse.Attributes.ColorTableName = roughnessRequest.Attributes.ColorTableName
 	roughnessResponse.Attributes.ColoringAlgorithm = roughnessRequest.Attributes.ColoringAlgorithm
+	if roughnessRequest.Attributes.ColoringAlgorithm == "" {
+		roughnessResponse.Attributes.Warnings = append(roughnessResponse.Attributes.Warnings, "ColoringAlgorithm not set, default 'interpolation' applied")
+	}
 
 	zone := 0
 	easting := 0.0
@@ -102,12 +142,20 @@ func roughnessRequest(writer http.ResponseWriter, request *http.Request) {
 		// get all tiles (metadata) for given UTM coordinates
 		tiles, err = getAllTilesUTM(zone, easting, northing)
 		if err != nil {
-			slog.Warn("roughness request: error getting GeoTIFF tile for UTM coordinates", "error", err,
+			slog.WarnContext(request.Context(), "roughness request: error getting GeoTIFF tile for UTM coordinates", "error", err,
 				"easting", easting, "northing", northing, "zone", zone, "ID", roughnessRequest.ID)
-			roughnessResponse.Attributes.Error.Code = "10080"
-			roughnessResponse.Attributes.Error.Title = "getting GeoTIFF tile for UTM coordinates"
-			roughnessResponse.Attributes.Error.Detail = err.Error()
-			buildRoughnessResponse(writer, http.StatusBadRequest, roughnessResponse)
+			if errors.Is(err, ErrOutsideCoverage) {
+				roughnessResponse.Attributes.Error = newErrorObject(EndpointRoughness, ReasonOutsideCoverage, describeOutsideCoverage(err))
+				buildRoughnessResponse(writer, request, http.StatusNotFound, roughnessResponse)
+				return
+			}
+			if errors.Is(err, ErrSourceUnavailable) {
+				roughnessResponse.Attributes.Error = newErrorObject(EndpointRoughness, ReasonSourceUnavailable, err.Error())
+				buildRoughnessResponse(writer, request, http.StatusServiceUnavailable, roughnessResponse)
+				return
+			}
+			roughnessResponse.Attributes.Error = newErrorObject(EndpointRoughness, ReasonGettingTileUTM, err.Error())
+			buildRoughnessResponse(writer, request, http.StatusBadRequest, roughnessResponse)
 			return
 		}
 	} else {
@@ -120,33 +168,74 @@ func roughnessRequest(writer http.ResponseWriter, request *http.Request) {
 		tiles, err = getAllTilesLonLat(longitude, latitude)
 		if err != nil {
 			err = fmt.Errorf("error [%w] getting tile for coordinates lon: %.8f, lat: %.8f", err, longitude, latitude)
-			slog.Warn("roughness request: error getting GeoTIFF tile for lon/lat coordinates", "error", err,
+			slog.WarnContext(request.Context(), "roughness request: error getting GeoTIFF tile for lon/lat coordinates", "error", err,
 				"longitude", longitude, "latitude", latitude, "ID", roughnessRequest.ID)
-			roughnessResponse.Attributes.Error.Code = "10100"
-			roughnessResponse.Attributes.Error.Title = "getting GeoTIFF tile for lon/lat coordinates"
-			roughnessResponse.Attributes.Error.Detail = err.Error()
-			buildRoughnessResponse(writer, http.StatusBadRequest, roughnessResponse)
+			if errors.Is(err, ErrOutsideCoverage) {
+				roughnessResponse.Attributes.Error = newErrorObject(EndpointRoughness, ReasonOutsideCoverage, describeOutsideCoverage(err))
+				buildRoughnessResponse(writer, request, http.StatusNotFound, roughnessResponse)
+				return
+			}
+			if errors.Is(err, ErrSourceUnavailable) {
+				roughnessResponse.Attributes.Error = newErrorObject(EndpointRoughness, ReasonSourceUnavailableLonLat, err.Error())
+				buildRoughnessResponse(writer, request, http.StatusServiceUnavailable, roughnessResponse)
+				return
+			}
+			roughnessResponse.Attributes.Error = newErrorObject(EndpointRoughness, ReasonGettingTileLonLat, err.Error())
+			buildRoughnessResponse(writer, request, http.StatusBadRequest, roughnessResponse)
 			return
 		}
 	}
 
+	// explicit output format (overrides default for type of coordinates)
+	if roughnessRequest.Attributes.OutputFormat != "" {
+		outputFormat = strings.ToLower(roughnessRequest.Attributes.OutputFormat)
+	}
+
+	// conditional request: unchanged response (same parameters, same tiles) needs no processing
+	etag := buildETag("roughness", roughnessRequest, tiles)
+	if checkNotModified(writer, request, etag) {
+		return
+	}
+
+	// color of nodata areas (transparent, fill, preserve)
+	colorTextFileContent := applyNoDataHandling(roughnessRequest.Attributes.ColorTextFileContent, roughnessRequest.Attributes.NoDataHandling, roughnessRequest.Attributes.NoDataColor)
+
 	// build roughness for all existing tiles
-	for _, tile := range tiles {
-		roughness, err := generateRoughnessObjectForTile(tile, outputFormat, roughnessRequest.Attributes.ColorTextFileContent, roughnessRequest.Attributes.ColoringAlgorithm)
-		if err != nil {
-			slog.Warn("roughness request: error generating roughness object for tile", "error", err, "ID", roughnessRequest.ID)
-			roughnessResponse.Attributes.Error.Code = "10120"
-			roughnessResponse.Attributes.Error.Title = "error generating roughness object for tile"
-			roughnessResponse.Attributes.Error.Detail = err.Error()
-			buildRoughnessResponse(writer, http.StatusBadRequest, roughnessResponse)
+	roughnesses, tileErrors, err := generateObjectsForTiles(request.Context(), tiles, func(tile TileMetadata) (Roughness, error) {
+		return generateRoughnessObjectForTile(request.Context(), tile, outputFormat, roughnessRequest.Attributes.GeoTIFFOptions, colorTextFileContent, roughnessRequest.Attributes.ColoringAlgorithm, roughnessRequest.Attributes.MaskWater)
+	})
+	if err != nil {
+		slog.WarnContext(request.Context(), "roughness request: error generating roughness object for tile", "error", err, "ID", roughnessRequest.ID)
+		if errors.Is(err, context.Canceled) {
+			// client disconnected, processing aborted, no response required
+			return
+		}
+		if errors.Is(err, ErrServerBusy) {
+			roughnessResponse.Attributes.Error = newErrorObject(EndpointRoughness, ReasonServerBusy, err.Error())
+			writer.Header().Set("Retry-After", strconv.Itoa(getProgConfig().GDALJobQueue.RetryAfter))
+			buildRoughnessResponse(writer, request, http.StatusTooManyRequests, roughnessResponse)
 			return
 		}
-		roughnessResponse.Attributes.Roughnesses = append(roughnessResponse.Attributes.Roughnesses, roughness)
+		roughnessResponse.Attributes.Error = newErrorObject(EndpointRoughness, ReasonGeneratingObject, err.Error())
+		buildRoughnessResponse(writer, request, http.StatusBadRequest, roughnessResponse)
+		return
+	}
+	roughnessResponse.Attributes.Roughnesses = roughnesses
+	roughnessResponse.Attributes.TileErrors = tileErrors
+	if len(tileErrors) > 0 {
+		roughnessResponse.Attributes.Warnings = append(roughnessResponse.Attributes.Warnings,
+			fmt.Sprintf("%d of %d tiles could not be processed (see TileErrors)", len(tileErrors), len(tiles)))
+		slog.WarnContext(request.Context(), "roughness request: partial success, tiles could not be processed", "tile errors", tileErrors, "ID", roughnessRequest.ID)
 	}
+	addUsage(request.Context(), 0, len(roughnesses))
 
 	// success response
 	roughnessResponse.Attributes.IsError = false
-	buildRoughnessResponse(writer, http.StatusOK, roughnessResponse)
+	if len(tileErrors) == 0 {
+		// partial responses are not cacheable
+		setETag(writer, etag)
+	}
+	buildRoughnessResponse(writer, request, http.StatusOK, roughnessResponse)
 }
 
 /*
@@ -167,18 +256,6 @@ func verifyRoughnessRequestData(request *http.Request, roughnessRequest Roughnes
 		return fmt.Errorf("unexpected or missing HTTP header field Content-Type, value = [%s], expected 'application/json'", contentType)
 	}
 
-	// verify HTTP header
-	accept := request.Header.Get("Accept")
-	isAcceptValid := true
-	switch {
-	case strings.HasPrefix(strings.ToLower(accept), "application/json"):
-	default:
-		isAcceptValid = false
-	}
-	if !isAcceptValid {
-		return fmt.Errorf("unexpected or missing HTTP header field Accept, value = [%s], expected 'application/json'", accept)
-	}
-
 	// verify Type
 	if roughnessRequest.Type != TypeRoughnessRequest {
 		return fmt.Errorf("unexpected request Type [%v]", roughnessRequest.Type)
@@ -228,77 +305,71 @@ func verifyRoughnessRequestData(request *http.Request, roughnessRequest Roughnes
 		}
 	}
 
-	return nil
-}
+	// verify output format
+	switch strings.ToLower(roughnessRequest.Attributes.OutputFormat) {
+	case "":
+	case "geotiff":
+	case "png":
+	default:
+		return errors.New("unsupported output format (not geotiff, png)")
+	}
 
-/*
-buildRoughnessResponse builds HTTP responses with specified status and body.
-It sets the Content-Type and Content-Length headers before writing the response body.
-This function is used to construct consistent HTTP responses throughout the application.
-*/
-func buildRoughnessResponse(writer http.ResponseWriter, httpStatus int, roughnessResponse RoughnessResponse) {
-	// log limit length of body (e.g., the roughness objects as part of the body can be very large)
-	maxBodyLength := 1024
-
-	// CORS: allow requests from any origin
-	writer.Header().Set("Access-Control-Allow-Origin", "*")
-	// CORS: allowed methods
-	writer.Header().Set("Access-Control-Allow-Methods", "POST")
-	// CORS: allowed headers
-	writer.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
-	// marshal response
-	body, err := json.MarshalIndent(roughnessResponse, "", "  ")
+	// verify GeoTIFF creation options
+	err = verifyGeoTIFFOptions(roughnessRequest.Attributes.GeoTIFFOptions)
 	if err != nil {
-		slog.Error("error marshaling point response", "error", err, "body length", len(body),
-			fmt.Sprintf("body (limited to first %d bytes)", maxBodyLength), body[:maxBodyLength])
-
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
+		return err
 	}
 
-	// gzip response body
-	var bytesBuffer bytes.Buffer
-	gz := gzip.NewWriter(&bytesBuffer)
-
-	_, err = gz.Write(body)
+	// verify nodata handling
+	err = verifyNoDataHandling(roughnessRequest.Attributes.NoDataHandling, roughnessRequest.Attributes.NoDataColor)
 	if err != nil {
-		slog.Error("error [%v] at gz.Write()", "error", err)
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
+		return err
 	}
 
-	err = gz.Close()
+	// verify water masking
+	err = verifyMaskWater(roughnessRequest.Attributes.MaskWater)
 	if err != nil {
-		slog.Error("error [%v] at gz.Close()", "error", err)
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
+		return err
 	}
 
-	// set headers
-	writer.Header().Set("Content-Encoding", "gzip")
-	writer.Header().Set("Content-Type", JSONAPIMediaType)
-	writer.WriteHeader(httpStatus)
+	return nil
+}
 
-	// send response
-	_, err = writer.Write(bytesBuffer.Bytes())
-	if err != nil {
-		slog.Error("error writing HTTP response body", "error", err, "body length", len(body),
-			fmt.Sprintf("body (limited to first %d bytes)", maxBodyLength), body[:maxBodyLength])
-	}
+/*
+buildRoughnessResponse builds HTTP responses with specified status and body.
+It sets the Content-Type and Content-Length headers before writing the response body.
+This function is used to construct consistent HTTP responses throughout the application.
+*/
+func buildRoughnessResponse(writer http.ResponseWriter, request *http.Request, httpStatus int, roughnessResponse RoughnessResponse) {
+	// response metadata (versions, processing duration, cache hit)
+	roughnessResponse.Meta = newResponseMeta(request.Context())
+
+	// skip compression if payload is already compressed (e.g. PNG)
+	skipCompression := len(roughnessResponse.Attributes.Roughnesses) > 0 && isCompressedDataFormat(roughnessResponse.Attributes.Roughnesses[0].DataFormat)
+
+	// encode (streaming) and send response
+	streamJSONResponse(writer, request, httpStatus, roughnessResponse, skipCompression)
 }
 
 /*
 generateRoughnessObjectForTile builds roughness object for given tile index.
 */
-func generateRoughnessObjectForTile(tile TileMetadata, outputFormat string, colorTextFileContent []string, coloringAlgorithm string) (Roughness, error) {
+func generateRoughnessObjectForTile(ctx context.Context, tile TileMetadata, outputFormat string, geotiffOptions GeoTIFFOptions, colorTextFileContent []string, coloringAlgorithm string, maskWater bool) (Roughness, error) {
 	var roughness Roughness
 	var boundingBox WGS84BoundingBox
 
-	// run operations in temp directory
-	tempDir, err := os.MkdirTemp("", "dtm-elevation-service-roughness-")
+	// lookup response cache
+	cacheKey := buildResponseCacheKey("roughness", tile.Index, tile.Actuality, outputFormat, geotiffOptions, colorTextFileContent, coloringAlgorithm, maskWater)
+	cached, found := responseCache.Get(cacheKey)
+	recordCacheLookup(ctx, found)
+	if found {
+		return cached.(Roughness), nil
+	}
+
+	// run operations in temp directory (color text file) and in memory (/vsimem)
+	tempDir, err := createTempDirectory("roughness")
 	if err != nil {
-		return roughness, fmt.Errorf("error [%w] at os.MkdirTemp()", err)
+		return roughness, fmt.Errorf("error [%w] at createTempDirectory()", err)
 	}
 	defer func() {
 		_ = os.RemoveAll(tempDir)
@@ -311,80 +382,33 @@ func generateRoughnessObjectForTile(tile TileMetadata, outputFormat string, colo
 		return roughness, fmt.Errorf("error [%w] creating 'color-text-file'", err)
 	}
 
-	inputGeoTIFF := tile.Path
-	roughnessUTMGeoTIFF := filepath.Join(tempDir, tile.Index+".roughnessutm.tif")
-	roughnessColorUTMGeoTIFF := filepath.Join(tempDir, tile.Index+".roughnesscolor.utm.tif")
-	roughnessWebmercatorGeoTIFF := filepath.Join(tempDir, tile.Index+".roughnesswebmercator.tif")
-	roughnessColorWebmercatoPNG := filepath.Join(tempDir, tile.Index+".roughnesscolor.webmercator.png")
-
-	// 1. create native Roughness with 'gdaldem roughness'
-	commandExitStatus, commandOutput, err := runCommand("gdaldem", []string{"roughness", inputGeoTIFF, roughnessUTMGeoTIFF, "-compute_edges"})
+	// create colored roughness (GeoTIFF in UTM or PNG in Webmercator) with 'gdaldem roughness' and 'gdaldem color-relief'
+	data, err := dtm.GenerateProduct(ctx, tile.Path, dtm.ProductOptions{
+		Name:            "roughness",
+		Mode:            "roughness",
+		ModeSwitches:    []string{"-compute_edges"},
+		ColorTextFile:   colorTextFile,
+		NearestColor:    coloringAlgorithm == "rounding",
+		OutputFormat:    outputFormat,
+		CreationOptions: buildGeoTIFFCreationOptions(geotiffOptions),
+		PostProcess:     waterMasking(maskWater),
+	})
 	if err != nil {
-		return roughness, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
+		return roughness, fmt.Errorf("error [%w] at dtm.GenerateProduct()", err)
 	}
-	// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-	// fmt.Printf("commandOutput: %s\n", commandOutput)
 
-	var data []byte
-	switch strings.ToLower(outputFormat) {
-	case "geotiff":
-		// 2. colorize roughness with 'gdaldem color-relief'
-		options := []string{"color-relief", roughnessUTMGeoTIFF, colorTextFile, roughnessColorUTMGeoTIFF, "-alpha"}
-		if coloringAlgorithm == "rounding" {
-			options = append(options, "-nearest_color_entry")
-		}
-		commandExitStatus, commandOutput, err = runCommand("gdaldem", options)
-		if err != nil {
-			return roughness, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
-		}
-		// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-		// fmt.Printf("commandOutput: %s\n", commandOutput)
-
-		data, err = os.ReadFile(roughnessColorUTMGeoTIFF)
-		if err != nil {
-			return roughness, fmt.Errorf("error [%w] at os.ReadFile()", err)
-		}
-
-	case "png":
-		// 2. convert UTM (EPSG:25832/EPSG:25833) to Webmercator (EPSG:3857) with 'gdalwarp'
-		commandExitStatus, commandOutput, err = runCommand("gdalwarp", []string{"-t_srs", "EPSG:3857", roughnessUTMGeoTIFF, roughnessWebmercatorGeoTIFF})
-		if err != nil {
-			return roughness, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
-		}
-		// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-		// fmt.Printf("commandOutput: %s\n", commandOutput)
-
-		// 3. colorize roughness with 'gdaldem color-relief' (creates PNG file)
-		options := []string{"color-relief", roughnessWebmercatorGeoTIFF, colorTextFile, roughnessColorWebmercatoPNG, "-alpha"}
-		if coloringAlgorithm == "rounding" {
-			options = append(options, "-nearest_color_entry")
-		}
-		commandExitStatus, commandOutput, err = runCommand("gdaldem", options)
-		if err != nil {
-			return roughness, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
-		}
-		// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-		// fmt.Printf("commandOutput: %s\n", commandOutput)
-
-		// 4. get bounding box (in wgs84) for webmercator tif (georeference of webmercator png )
+	// get bounding box (in wgs84) for webmercator png (georeference of webmercator png)
+	if strings.EqualFold(outputFormat, "png") {
 		boundingBox, err = calculateWGS84BoundingBox(tile)
 		if err != nil {
 			return roughness, fmt.Errorf("error [%w] at calculateWGS84BoundingBox(), file: %s", err, tile.Path)
 		}
-
-		// read result file
-		data, err = os.ReadFile(roughnessColorWebmercatoPNG)
-		if err != nil {
-			return roughness, fmt.Errorf("error [%w] at os.ReadFile()", err)
-		}
-
-	default:
-		return roughness, fmt.Errorf("unsupported format [%s]", outputFormat)
 	}
 
 	// set contour return structure
 	roughness.Data = data
 	roughness.DataFormat = outputFormat
+	roughness.Filename = buildObjectFilename(tile.Index, "roughness", "", tile.Actuality, outputFormat)
 	roughness.Actuality = tile.Actuality
 	roughness.Origin = tile.Source
 	roughness.TileIndex = tile.Index
@@ -394,11 +418,21 @@ func generateRoughnessObjectForTile(tile TileMetadata, outputFormat string, colo
 	attribution := "unknown"
 	resource, err := getElevationResource(tile.Source)
 	if err != nil {
-		slog.Error("roughness request: error getting elevation resource", "error", err, "source", tile.Source)
+		slog.ErrorContext(ctx, "roughness request: error getting elevation resource", "error", err, "source", tile.Source)
 	} else {
 		attribution = resource.Attribution
 	}
 	roughness.Attribution = attribution
 
+	// get provenance of source files (delivery file name and checksum)
+	sourceFiles, err := getSourceFiles(tile)
+	if err != nil {
+		slog.ErrorContext(ctx, "roughness request: error getting source files", "error", err, "tile", tile.Index)
+	}
+	roughness.SourceFiles = sourceFiles
+
+	// add to response cache
+	responseCache.Add(cacheKey, roughness, len(roughness.Data))
+
 	return roughness, nil
 }
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// s3Client is the HTTP client for uploads to S3 compatible storage
+var s3Client = &http.Client{Timeout: 5 * time.Minute}
+
+/*
+uploadToS3 uploads data to the configured S3 bucket (path-style request, AWS signature version 4)
+and returns a presigned download URL.
+*/
+func uploadToS3(ctx context.Context, key string, contentType string, data []byte) (string, error) {
+	config := getProgConfig().Export.S3
+	if config.Endpoint == "" || config.Bucket == "" {
+		return "", fmt.Errorf("upload to S3 not configured")
+	}
+
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return "", fmt.Errorf("error [%w] at url.Parse(), endpoint: %s", err, config.Endpoint)
+	}
+	objectPath := "/" + config.Bucket + "/" + config.Prefix + key
+	now := time.Now().UTC()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint.Scheme+"://"+endpoint.Host+awsURIEncode(objectPath, false), bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("error [%w] at http.NewRequestWithContext()", err)
+	}
+	payloadHash := sha256Hex(data)
+	request.Header.Set("Content-Type", contentType)
+	request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	request.Header.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+
+	// signature (signed headers: host, x-amz-content-sha256, x-amz-date)
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "host:" + endpoint.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + now.Format("20060102T150405Z") + "\n"
+	canonicalRequest := strings.Join([]string{http.MethodPut, awsURIEncode(objectPath, false), "", canonicalHeaders, signedHeaders, payloadHash}, "\n")
+	scope, signature := signS3Request(config.Region, config.SecretAccessKey, now, canonicalRequest)
+	request.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		config.AccessKeyID, scope, signedHeaders, signature))
+
+	response, err := s3Client.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("error [%w] at client.Do()", err)
+	}
+	defer response.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(response.Body, 4096))
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected HTTP status [%d] at S3 upload, response: %s", response.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return presignS3URL(endpoint, objectPath, now), nil
+}
+
+/*
+presignS3URL builds a presigned GET URL for an object (validity from configuration, max 7 days).
+*/
+func presignS3URL(endpoint *url.URL, objectPath string, now time.Time) string {
+	config := getProgConfig().Export.S3
+	expires := min(max(config.URLExpires, 1), 604800)
+	scope := now.Format("20060102") + "/" + config.Region + "/s3/aws4_request"
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", config.AccessKeyID+"/"+scope)
+	query.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	query.Set("X-Amz-Expires", strconv.Itoa(expires))
+	query.Set("X-Amz-SignedHeaders", "host")
+	// AWS requires '%20' for spaces (url.Values encodes '+')
+	canonicalQuery := strings.ReplaceAll(query.Encode(), "+", "%20")
+
+	canonicalRequest := strings.Join([]string{http.MethodGet, awsURIEncode(objectPath, false), canonicalQuery,
+		"host:" + endpoint.Host + "\n", "host", "UNSIGNED-PAYLOAD"}, "\n")
+	_, signature := signS3Request(config.Region, config.SecretAccessKey, now, canonicalRequest)
+
+	return endpoint.Scheme + "://" + endpoint.Host + awsURIEncode(objectPath, false) + "?" + canonicalQuery + "&X-Amz-Signature=" + signature
+}
+
+/*
+signS3Request returns the credential scope and the signature (AWS signature version 4) of a canonical request.
+*/
+func signS3Request(region string, secretAccessKey string, now time.Time, canonicalRequest string) (string, string) {
+	date := now.Format("20060102")
+	scope := date + "/" + region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", now.Format("20060102T150405Z"), scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+
+	return scope, hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+/*
+awsURIEncode encodes a string as required by AWS signature version 4 (unreserved characters are kept).
+*/
+func awsURIEncode(value string, encodeSlash bool) string {
+	var builder strings.Builder
+	for _, b := range []byte(value) {
+		switch {
+		case b >= 'A' && b <= 'Z', b >= 'a' && b <= 'z', b >= '0' && b <= '9', b == '-', b == '_', b == '.', b == '~':
+			builder.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			builder.WriteByte(b)
+		default:
+			fmt.Fprintf(&builder, "%%%02X", b)
+		}
+	}
+	return builder.String()
+}
+
+/*
+hmacSHA256 returns the HMAC-SHA256 of data.
+*/
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+/*
+sha256Hex returns the SHA-256 hash of data (hex encoded).
+*/
+func sha256Hex(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
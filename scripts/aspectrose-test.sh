@@ -0,0 +1,31 @@
+#!/bin/bash
+#
+# Hangexposition (Expositionsrose) und Hangneigungsverteilung für ein Polygon (lon/lat), z.B. Weinberg
+
+postdata=$(cat <<EOF
+{
+  "Type": "AspectRoseRequest",
+  "ID": "Weinberg Bernkasteler Doctor (Mosel)",
+  "Attributes": {
+      "Polygon": [
+        [7.0745, 49.9175],
+        [7.0815, 49.9175],
+        [7.0815, 49.9215],
+        [7.0745, 49.9215]
+      ],
+      "Sectors": 8,
+      "FlatSlope": 2.0
+  }
+}
+EOF
+)
+
+echo "postdata = $postdata"
+
+curl \
+--silent \
+--include \
+--header "Content-Type: application/json" \
+--header "Accept: application/json" \
+--data "$postdata" \
+https://api.hoehendaten.de:14444/v1/aspectrose
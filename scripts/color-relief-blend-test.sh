@@ -0,0 +1,36 @@
+#!/bin/bash
+#
+# Abfrage ColorRelief mit eingerechneter Schummerung (Hillshade) für eine Kachel mit 1000x1000 Meter,
+# Ergebnis ist eine fertige Reliefkarte (Farbstufen und Schummerung in einem Bild).
+
+postdata=$(cat <<EOF
+{
+  "Type": "ColorReliefRequest",
+  "ID": "Hegekopf, Edersee, Hessen",
+  "Attributes": {
+    "Longitude": 8.964229,
+    "Latitude": 51.185913,
+    "ColorRamp": "hypsometric",
+    "ColoringAlgorithm": "interpolation",
+    "BlendHillshade": {
+      "Opacity": 0.6,
+      "VerticalExaggeration": 2.0,
+      "AzimuthOfLight": 315,
+      "AltitudeOfLight": 45
+    }
+  }
+}
+EOF
+)
+
+echo "postdata = $postdata"
+
+curl \
+--silent \
+--include \
+--compressed \
+--header "Content-Type: application/json" \
+--header "Accept: application/json" \
+--header "Accept-Encoding: gzip" \
+--data "$postdata" \
+https://api.hoehendaten.de:14444/v1/colorrelief
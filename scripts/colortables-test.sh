@@ -0,0 +1,70 @@
+#!/bin/bash
+#
+# Benannte Farbtabelle serverseitig speichern (POST /v1/colortables/{name}) und in Raster-Abfragen
+# über 'ColorTableName' referenzieren (statt 'ColorTextFileContent' bei jeder Abfrage zu senden).
+# Zugriff: mit Bearer-Token (Authentifizierung aktiv) oder aus den Admin-Netzwerken.
+# Abrufen: GET /v1/colortables/{name}, Löschen: DELETE /v1/colortables/{name}
+
+postdata=$(cat <<EOF2
+{
+  "Type": "ColorTableRequest",
+  "ID": "Farbtabelle Hangneigung",
+  "Attributes": {
+    "Description": "Hangneigung in Grad (grün bis schwarz)",
+    "ColorTextFileContent": [
+      "0 0 100 0 255",
+      "5 0 200 0 255",
+      "10 100 255 0 255",
+      "20 200 200 0 255",
+      "30 255 150 0 255",
+      "40 255 100 0 255",
+      "45 255 0 0 255",
+      "60 150 0 0 255",
+      "90 0 0 0 255",
+      "nv 0 0 0 0"
+    ]
+  }
+}
+EOF2
+)
+
+echo "postdata = $postdata"
+
+curl \
+--silent \
+--include \
+--compressed \
+--header "Content-Type: application/json" \
+--header "Accept: application/json" \
+--header "Accept-Encoding: gzip" \
+--data "$postdata" \
+https://api.hoehendaten.de:14444/v1/colortables/hangneigung
+
+# Hangneigung mit gespeicherter Farbtabelle
+postdata=$(cat <<EOF2
+{
+  "Type": "SlopeRequest",
+  "ID": "Hegekopf, Edersee, Hessen",
+  "Attributes": {
+    "Zone": 32,
+    "Easting": 497500.0,
+    "Northing": 5670500.0,
+    "GradientAlgorithm": "ZevenbergenThorne",
+    "ColorTableName": "hangneigung",
+    "ColoringAlgorithm": "interpolation"
+  }
+}
+EOF2
+)
+
+echo "postdata = $postdata"
+
+curl \
+--silent \
+--include \
+--compressed \
+--header "Content-Type: application/json" \
+--header "Accept: application/json" \
+--header "Accept-Encoding: gzip" \
+--data "$postdata" \
+https://api.hoehendaten.de:14444/v1/slope
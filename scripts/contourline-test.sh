@@ -0,0 +1,26 @@
+#!/bin/bash
+#
+# Höhenlinie durch einen Punkt (lon/lat) im Umkreis (1500 m), z.B. Wandern auf gleicher Höhe
+
+postdata=$(cat <<EOF
+{
+  "Type": "ContourLineRequest",
+  "ID": "Höhenlinie Zugspitzbahn (Eibsee)",
+  "Attributes": {
+      "Longitude": 10.9850,
+      "Latitude": 47.4560,
+      "Radius": 1500.0
+  }
+}
+EOF
+)
+
+echo "postdata = $postdata"
+
+curl \
+--silent \
+--include \
+--header "Content-Type: application/json" \
+--header "Accept: application/json" \
+--data "$postdata" \
+https://api.hoehendaten.de:14444/v1/contourline
@@ -0,0 +1,54 @@
+#!/bin/bash
+#
+# Abfrage der Höhenlinien für ein Gebiet über mehrere Kacheln (nahtlos, ohne doppelte Linien an Kachelgrenzen).
+
+# Gebiet durch Bounding-Box in lon/lat-Koordinaten referenziert.
+# Ergebnis: eine GeoJSON-Datei mit Höhenlinien in lon/lat-Koordinaten.
+postdataLonLat=$(cat <<EOF
+{
+  "Type": "ContoursRequest",
+  "ID": "Langenberg (Rothaargebirge, höchster Berg in NRW)",
+  "Attributes": {
+    "Zone": 0,
+    "Equidistance": 5.0,
+    "Area": {
+      "MinX": 8.54,
+      "MinY": 51.265,
+      "MaxX": 8.58,
+      "MaxY": 51.29
+    }
+  }
+}
+EOF
+)
+
+# oder
+
+# Gebiet durch Polygon in UTM-Koordinaten referenziert.
+# Ergebnis: eine GeoJSON-Datei mit Höhenlinien in UTM-Koordinaten.
+postdataUTM=$(cat <<EOF
+{
+  "Type": "ContoursRequest",
+  "ID": "GPS-Referenzpunkt Hannover",
+  "Attributes": {
+    "Zone": 32,
+    "Equidistance": 1.0,
+    "Area": {
+      "Polygon": [[549500, 5801500], [552500, 5801500], [551500, 5803500], [549500, 5803000]]
+    }
+  }
+}
+EOF
+)
+
+echo "postdata = $postdataLonLat"
+
+curl \
+--silent \
+--include \
+--compressed \
+--header "Content-Type: application/json" \
+--header "Accept: application/json" \
+--header "Accept-Encoding: gzip" \
+--data "$postdataLonLat" \
+https://api.hoehendaten.de:14444/v1/contours
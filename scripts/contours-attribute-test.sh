@@ -0,0 +1,32 @@
+#!/bin/bash
+#
+# Höhenlinien für eine Kachel (lon/lat) mit Höhenattribut 'ELEV' in Fuß (Äquidistanz 20 ft, ganzzahlig gerundet),
+# z.B. für Darstellungsregeln, die das Attribut 'ELEV' erwarten
+
+postdata=$(cat <<EOF
+{
+  "Type": "ContoursRequest",
+  "ID": "Langenberg (Rothaargebirge, höchster Berg in NRW)",
+  "Attributes": {
+    "Longitude": 8.558333,
+    "Latitude": 51.276389,
+    "Equidistance": 20.0,
+    "AttributeName": "ELEV",
+    "Precision": 0,
+    "Unit": "ft"
+  }
+}
+EOF
+)
+
+echo "postdata = $postdata"
+
+curl \
+--silent \
+--include \
+--compressed \
+--header "Content-Type: application/json" \
+--header "Accept: application/json" \
+--header "Accept-Encoding: gzip" \
+--data "$postdata" \
+https://api.hoehendaten.de:14444/v1/contours
@@ -0,0 +1,39 @@
+#!/bin/bash
+#
+# Abfrage der Höhenlinien innerhalb eines Polygons (GeoJSON), z.B. Grundstücksgrenze oder Untersuchungsgebiet.
+# Ergebnis: eine GeoJSON-Datei mit Höhenlinien (nur innerhalb des Polygons) in lon/lat-Koordinaten.
+
+postdata=$(cat <<EOF
+{
+  "Type": "ContoursRequest",
+  "ID": "Untersuchungsgebiet Langenberg (Rothaargebirge)",
+  "Attributes": {
+    "Equidistance": 2.0,
+    "ClipPolygon": {
+      "type": "Polygon",
+      "coordinates": [
+        [
+          [8.5520, 51.2720],
+          [8.5650, 51.2720],
+          [8.5650, 51.2810],
+          [8.5520, 51.2810],
+          [8.5520, 51.2720]
+        ]
+      ]
+    }
+  }
+}
+EOF
+)
+
+echo "postdata = $postdata"
+
+curl \
+--silent \
+--include \
+--compressed \
+--header "Content-Type: application/json" \
+--header "Accept: application/json" \
+--header "Accept-Encoding: gzip" \
+--data "$postdata" \
+https://api.hoehendaten.de:14444/v1/contours
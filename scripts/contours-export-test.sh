@@ -0,0 +1,42 @@
+#!/bin/bash
+#
+# Höhenlinien-Export für ein großes Gebiet (asynchroner Job): Höhenlinien eines ganzen Landkreises als eine GeoPackage-Datei.
+# Die Linien werden in einem Durchgang für das Mosaik aller Kacheln erzeugt (durchgehend über Kachelgrenzen hinweg).
+# 1. Job anlegen (Endpoint /v1/contoursexport)
+# 2. Status abfragen: GET /v1/jobs/{JobID}
+# 3. Ergebnis abrufen: GET /v1/jobs/{JobID}/result (ContoursExportResponse, GeoPackage in 'Data' (base64) oder 'DownloadURL')
+#    GeoPackage speichern: ... | jq -r '.Attributes.Data' | base64 --decode > hoehenlinien.gpkg
+
+postdata=$(cat <<EOF
+{
+  "Type": "JobRequest",
+  "ID": "Höhenlinien Kreis Olpe",
+  "Attributes": {
+    "Endpoint": "/v1/contoursexport",
+    "Request": {
+      "Type": "ContoursExportRequest",
+      "ID": "Kreis Olpe, Nordrhein-Westfalen",
+      "Attributes": {
+        "Zone": 32,
+        "AdministrativeArea": "Olpe",
+        "Equidistance": 10.0,
+        "Precision": 0,
+        "Upload": false
+      }
+    }
+  }
+}
+EOF
+)
+
+echo "postdata = $postdata"
+
+curl \
+--silent \
+--include \
+--compressed \
+--header "Content-Type: application/json" \
+--header "Accept: application/json" \
+--header "Accept-Encoding: gzip" \
+--data "$postdata" \
+https://api.hoehendaten.de:14444/v1/jobs
@@ -0,0 +1,30 @@
+#!/bin/bash
+#
+# Höhenmatrix (Raster mit 10 m Auflösung) für ein Rechteck (lon/lat) als JSON-Array
+
+postdata=$(cat <<EOF
+{
+  "Type": "ElevationMatrixRequest",
+  "ID": "Höhenmatrix Brocken (Harz)",
+  "Attributes": {
+      "MinX": 10.6050,
+      "MinY": 51.7900,
+      "MaxX": 10.6250,
+      "MaxY": 51.8060,
+      "Resolution": 10.0,
+      "Resampling": "average",
+      "Format": "json"
+  }
+}
+EOF
+)
+
+echo "postdata = $postdata"
+
+curl \
+--silent \
+--include \
+--header "Content-Type: application/json" \
+--header "Accept: application/json" \
+--data "$postdata" \
+https://api.hoehendaten.de:14444/v1/elevationmatrix
@@ -0,0 +1,28 @@
+#!/bin/bash
+#
+# Gelände innerhalb eines Höhenbereichs (1300 - 1400 m) im Umkreis (2000 m) um einen Punkt (lon/lat)
+
+postdata=$(cat <<EOF
+{
+  "Type": "ElevationRangeRequest",
+  "ID": "Höhenbereich Feldberg (Schwarzwald)",
+  "Attributes": {
+      "Longitude": 8.0043,
+      "Latitude": 47.8740,
+      "Radius": 2000.0,
+      "MinElevation": 1300.0,
+      "MaxElevation": 1400.0
+  }
+}
+EOF
+)
+
+echo "postdata = $postdata"
+
+curl \
+--silent \
+--include \
+--header "Content-Type: application/json" \
+--header "Accept: application/json" \
+--data "$postdata" \
+https://api.hoehendaten.de:14444/v1/elevationrange
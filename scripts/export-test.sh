@@ -0,0 +1,42 @@
+#!/bin/bash
+#
+# Bulk-Export (asynchroner Job): DGM-Kacheln, Schummerung und Höhenlinien als ZIP-Archiv.
+# 1. Job anlegen (Endpoint /v1/export)
+# 2. Status abfragen: GET /v1/jobs/{JobID}
+# 3. Ergebnis abrufen: GET /v1/jobs/{JobID}/result (ExportResponse, ZIP-Archiv in 'Data' (base64) oder 'DownloadURL')
+#    Archiv speichern: ... | jq -r '.Attributes.Data' | base64 --decode > export.zip
+
+postdata=$(cat <<EOF
+{
+  "Type": "JobRequest",
+  "ID": "Export Langenberg",
+  "Attributes": {
+    "Endpoint": "/v1/export",
+    "Request": {
+      "Type": "ExportRequest",
+      "ID": "Langenberg (Rothaargebirge, höchster Berg in NRW)",
+      "Attributes": {
+        "Products": ["rawtif", "hillshade", "contours"],
+        "Zone": 32,
+        "Tiles": ["32_469_5678", "32_470_5678"],
+        "Equidistance": 5.0,
+        "ShadingVariant": "igor",
+        "Upload": false
+      }
+    }
+  }
+}
+EOF
+)
+
+echo "postdata = $postdata"
+
+curl \
+--silent \
+--include \
+--compressed \
+--header "Content-Type: application/json" \
+--header "Accept: application/json" \
+--header "Accept-Encoding: gzip" \
+--data "$postdata" \
+https://api.hoehendaten.de:14444/v1/jobs
@@ -0,0 +1,48 @@
+#!/bin/bash
+#
+# Asynchroner Job: Höhenlinien für ein Gebiet (Verarbeitung im Hintergrund).
+# 1. Job anlegen (Antwort: JobID, Status 'queued')
+# 2. Status abfragen: GET /v1/jobs/{JobID}
+# 3. Ergebnis abrufen (Status 'completed'): GET /v1/jobs/{JobID}/result
+# Fortschritt (Server-Sent Events, z.B. "14/60 tiles processed"):
+#   curl --no-buffer --header "Accept: text/event-stream" https://.../v1/jobs/{JobID}/events
+
+postdata=$(cat <<EOF
+{
+  "Type": "JobRequest",
+  "ID": "Höhenlinien Langenberg",
+  "Attributes": {
+    "Endpoint": "/v1/contours",
+    "Request": {
+      "Type": "ContoursRequest",
+      "ID": "Langenberg (Rothaargebirge, höchster Berg in NRW)",
+      "Attributes": {
+        "Zone": 0,
+        "Equidistance": 5.0,
+        "Area": {
+          "MinX": 8.50,
+          "MinY": 51.24,
+          "MaxX": 8.62,
+          "MaxY": 51.31
+        }
+      }
+    }
+  }
+}
+EOF
+)
+
+echo "postdata = $postdata"
+
+curl \
+--silent \
+--include \
+--compressed \
+--header "Content-Type: application/json" \
+--header "Accept: application/json" \
+--header "Accept-Encoding: gzip" \
+--data "$postdata" \
+https://api.hoehendaten.de:14444/v1/jobs
+
+# curl --silent --include https://api.hoehendaten.de:14444/v1/jobs/<JobID>
+# curl --silent --compressed --header "Accept-Encoding: gzip" https://api.hoehendaten.de:14444/v1/jobs/<JobID>/result
@@ -0,0 +1,38 @@
+#!/bin/bash
+#
+# Legende (Farbbalken mit Beschriftung) für eine Farbtabelle, hier die Farbtabelle für Hangneigung (Slope) in Grad.
+# Ergebnis: ein PNG-Bild (vertikal, 100 x 300 Pixel) mit den Farbeinträgen der Farbtabelle.
+
+postdata=$(cat <<EOF2
+{
+  "Type": "LegendRequest",
+  "ID": "Legende Hangneigung",
+  "Attributes": {
+    "ColorTextFileContent": [
+      "0 255 255 255",
+      "10 255 255 0",
+      "20 255 165 0",
+      "30 255 0 0",
+      "45 128 0 128",
+      "90 0 0 0"
+    ],
+    "ColoringAlgorithm": "interpolation",
+    "Unit": "°",
+    "Orientation": "vertical",
+    "OutputFormat": "png"
+  }
+}
+EOF2
+)
+
+echo "postdata = $postdata"
+
+curl \
+--silent \
+--include \
+--compressed \
+--header "Content-Type: application/json" \
+--header "Accept: application/json" \
+--header "Accept-Encoding: gzip" \
+--data "$postdata" \
+https://api.hoehendaten.de:14444/v1/legend
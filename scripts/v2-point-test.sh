@@ -0,0 +1,27 @@
+#!/bin/bash
+#
+# Abfrage der Höhendaten für einen lon/lat Punkt (API v2, JSON:API)
+
+postdata=$(cat <<EOF
+{
+  "data": {
+    "type": "pointRequest",
+    "id": "Langenberg (Rothaargebirge, höchster Berg in NRW)",
+    "attributes": {
+      "Longitude": 8.558333,
+      "Latitude": 51.276389
+    }
+  }
+}
+EOF
+)
+
+echo "postdata = $postdata"
+
+curl \
+--silent \
+--include \
+--header "Content-Type: application/vnd.api+json" \
+--header "Accept: application/vnd.api+json" \
+--data "$postdata" \
+https://api.hoehendaten.de:14444/v2/point
@@ -0,0 +1,42 @@
+#!/bin/bash
+#
+# Abfrage einer Visualisierung (hier: Hangneigung) für eine Kachel mit 1000x1000 Meter.
+# Unterstützte Visualisierungen: slope, aspect, tri, tpi, roughness, hillshade, colorrelief
+
+# Kachel durch UTM-Koordinaten referenziert.
+postdata=$(cat <<EOT
+{
+  "Type": "VisualizeRequest",
+  "ID": "Hegekopf, Edersee, Hessen",
+  "Attributes": {
+    "Zone": 32,
+    "Easting": 497500.0,
+    "Northing": 5670500.0,
+    "Longitude": 0.0,
+    "Latitude": 0.0,
+    "TypeOfVisualization": "slope",
+    "GradientAlgorithm": "ZevenbergenThorne",
+    "ColorTextFileContent": [
+      "0 0 100 0 255",
+      "10 100 255 0 255",
+      "30 255 150 0 255",
+      "90 0 0 0 255",
+      "nv 0 0 0 0"
+    ],
+    "ColoringAlgorithm": "interpolation"
+  }
+}
+EOT
+)
+
+echo "postdata = $postdata"
+
+curl \
+--silent \
+--include \
+--compressed \
+--header "Content-Type: application/json" \
+--header "Accept: application/json" \
+--header "Accept-Encoding: gzip" \
+--data "$postdata" \
+https://api.hoehendaten.de:14444/v1/visualize
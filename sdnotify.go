@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+/*
+sdNotify sends a state notification to systemd (sd_notify protocol, e.g. 'READY=1').
+Does nothing if the service is not supervised by systemd (NOTIFY_SOCKET not set).
+*/
+func sdNotify(state string) error {
+	socketName := os.Getenv("NOTIFY_SOCKET")
+	if socketName == "" {
+		return nil
+	}
+
+	// abstract socket namespace
+	if socketName[0] == '@' {
+		socketName = "\x00" + socketName[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socketName, Net: "unixgram"})
+	if err != nil {
+		return fmt.Errorf("error [%w] at net.DialUnix(), socket: %s", err, socketName)
+	}
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(state))
+	if err != nil {
+		return fmt.Errorf("error [%w] at conn.Write()", err)
+	}
+	return nil
+}
+
+/*
+sdNotifyState sends a state notification to systemd and logs errors.
+*/
+func sdNotifyState(state string) {
+	err := sdNotify(state)
+	if err != nil {
+		slog.Warn("error notifying systemd", "error", err, "state", state)
+	}
+}
+
+/*
+sdNotifyReloading notifies systemd about the start of a configuration reload (Type=notify-reload).
+*/
+func sdNotifyReloading() {
+	sdNotifyState("RELOADING=1\nMONOTONIC_USEC=" + strconv.FormatInt(monotonicMicroseconds(), 10))
+}
+
+/*
+startSDWatchdog sends keep-alive notifications to the systemd watchdog (WatchdogSec= in unit file)
+at half the configured watchdog interval.
+*/
+func startSDWatchdog() {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return
+	}
+
+	interval := time.Duration(usec) * time.Microsecond / 2
+	slog.Info("systemd watchdog enabled", "interval", interval)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			sdNotifyState("WATCHDOG=1")
+		}
+	}()
+}
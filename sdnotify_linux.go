@@ -0,0 +1,16 @@
+package main
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+/*
+monotonicMicroseconds returns the CLOCK_MONOTONIC time in microseconds (as expected by systemd).
+*/
+func monotonicMicroseconds() int64 {
+	var ts syscall.Timespec
+	const clockMonotonic = 1
+	_, _, _ = syscall.Syscall(syscall.SYS_CLOCK_GETTIME, clockMonotonic, uintptr(unsafe.Pointer(&ts)), 0)
+	return int64(ts.Sec)*1000000 + int64(ts.Nsec)/1000
+}
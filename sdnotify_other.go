@@ -0,0 +1,10 @@
+//go:build !linux
+
+package main
+
+/*
+monotonicMicroseconds returns 0 (systemd is only available on linux).
+*/
+func monotonicMicroseconds() int64 {
+	return 0
+}
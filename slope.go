@@ -1,8 +1,7 @@
 package main
 
 import (
-	"bytes"
-	"compress/gzip"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,8 +10,11 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync/atomic"
+
+	"klaus/elevation/dtm-elevation-service/pkg/dtm"
 )
 
 /*
@@ -34,18 +36,14 @@ func slopeRequest(writer http.ResponseWriter, request *http.Request) {
 		// check specifically for the error returned by MaxBytesReader
 		var maxBytesErr *http.MaxBytesError
 		if errors.As(err, &maxBytesErr) {
-			slog.Warn("slope request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
-			slopeResponse.Attributes.Error.Code = "6000"
-			slopeResponse.Attributes.Error.Title = "request body too large"
-			slopeResponse.Attributes.Error.Detail = fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit)
-			buildSlopeResponse(writer, http.StatusRequestEntityTooLarge, slopeResponse)
+			slog.WarnContext(request.Context(), "slope request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
+			slopeResponse.Attributes.Error = newErrorObject(EndpointSlope, ReasonRequestBodyTooLarge, fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit))
+			buildSlopeResponse(writer, request, http.StatusRequestEntityTooLarge, slopeResponse)
 		} else {
 			// handle other read errors
-			slog.Warn("slope request: error reading request body", "error", err, "ID", "unknown")
-			slopeResponse.Attributes.Error.Code = "6020"
-			slopeResponse.Attributes.Error.Title = "error reading request body"
-			slopeResponse.Attributes.Error.Detail = err.Error()
-			buildSlopeResponse(writer, http.StatusBadRequest, slopeResponse)
+			slog.WarnContext(request.Context(), "slope request: error reading request body", "error", err, "ID", "unknown")
+			slopeResponse.Attributes.Error = newErrorObject(EndpointSlope, ReasonReadingRequestBody, err.Error())
+			buildSlopeResponse(writer, request, http.StatusBadRequest, slopeResponse)
 		}
 		return
 	}
@@ -54,14 +52,52 @@ func slopeRequest(writer http.ResponseWriter, request *http.Request) {
 	slopeRequest := SlopeRequest{}
 	err = json.Unmarshal(bodyData, &slopeRequest)
 	if err != nil {
-		slog.Warn("slope request: error unmarshaling request body", "error", err, "ID", "unknown")
-		slopeResponse.Attributes.Error.Code = "6040"
-		slopeResponse.Attributes.Error.Title = "error unmarshaling request body"
-		slopeResponse.Attributes.Error.Detail = err.Error()
-		buildSlopeResponse(writer, http.StatusBadRequest, slopeResponse)
+		slog.WarnContext(request.Context(), "slope request: error unmarshaling request body", "error", err, "ID", "unknown")
+		slopeResponse.Attributes.Error = newErrorObject(EndpointSlope, ReasonUnmarshalingRequestBody, err.Error())
+		buildSlopeResponse(writer, request, http.StatusBadRequest, slopeResponse)
 		return
 	}
 
+	// resolve place name (geocoding) into lon/lat coordinates
+	if slopeRequest.Attributes.Place != "" {
+		place, err := geocodePlace(request.Context(), slopeRequest.Attributes.Place)
+		if err != nil {
+			slog.WarnContext(request.Context(), "slope request: error geocoding place", "error", err, "place", slopeRequest.Attributes.Place, "ID", slopeRequest.ID)
+			slopeResponse.ID = slopeRequest.ID
+			slopeResponse.Attributes.Place = slopeRequest.Attributes.Place
+			if errors.Is(err, ErrPlaceNotFound) {
+				slopeResponse.Attributes.Error = newErrorObject(EndpointSlope, ReasonPlaceNotFound, err.Error())
+				buildSlopeResponse(writer, request, http.StatusNotFound, slopeResponse)
+				return
+			}
+			if errors.Is(err, ErrGeocoderUnavailable) {
+				slopeResponse.Attributes.Error = newErrorObject(EndpointSlope, ReasonGeocoderUnavailable, err.Error())
+				buildSlopeResponse(writer, request, http.StatusServiceUnavailable, slopeResponse)
+				return
+			}
+			slopeResponse.Attributes.Error = newErrorObject(EndpointSlope, ReasonVerifyingRequestData, err.Error())
+			buildSlopeResponse(writer, request, http.StatusBadRequest, slopeResponse)
+			return
+		}
+		slopeRequest.Attributes.Zone = 0
+		slopeRequest.Attributes.Longitude = place.Longitude
+		slopeRequest.Attributes.Latitude = place.Latitude
+		slopeResponse.Attributes.PlaceName = place.DisplayName
+	}
+
+	// named color table (stored server-side) instead of color text file content
+	if slopeRequest.Attributes.ColorTableName != "" {
+		slopeRequest.Attributes.ColorTextFileContent, err = resolveColorTable(request.Context(), slopeRequest.Attributes.ColorTableName, slopeRequest.Attributes.ColorTextFileContent)
+		if err != nil {
+			slog.WarnContext(request.Context(), "slope request: error resolving color table", "error", err, "name", slopeRequest.Attributes.ColorTableName, "ID", slopeRequest.ID)
+			slopeResponse.ID = slopeRequest.ID
+			slopeResponse.Attributes.ColorTableName = slopeRequest.Attributes.ColorTableName
+			slopeResponse.Attributes.Error = newErrorObject(EndpointSlope, ReasonVerifyingRequestData, err.Error())
+			buildSlopeResponse(writer, request, http.StatusBadRequest, slopeResponse)
+			return
+		}
+	}
+
 	// copy request parameters into response
 	slopeResponse.ID = slopeRequest.ID
 	slopeResponse.Attributes.Zone = slopeRequest.Attributes.Zone
@@ -69,18 +105,23 @@ func slopeRequest(writer http.ResponseWriter, request *http.Request) {
 	slopeResponse.Attributes.Northing = slopeRequest.Attributes.Northing
 	slopeResponse.Attributes.Longitude = slopeRequest.Attributes.Longitude
 	slopeResponse.Attributes.Latitude = slopeRequest.Attributes.Latitude
+	slopeResponse.Attributes.Place = slopeRequest.Attributes.Place
+	slopeResponse.Attributes.AdministrativeArea = slopeRequest.Attributes.AdministrativeArea
+	slopeResponse.Attributes.OutputFormat = slopeRequest.Attributes.OutputFormat
 	slopeResponse.Attributes.GradientAlgorithm = slopeRequest.Attributes.GradientAlgorithm
 	slopeResponse.Attributes.ColorTextFileContent = slopeRequest.Attributes.ColorTextFileContent
+	slopeResponse.Attributes.ColorTableName = slopeRequest.Attributes.ColorTableName
 	slopeResponse.Attributes.ColoringAlgorithm = slopeRequest.Attributes.ColoringAlgorithm
+	if slopeRequest.Attributes.ColoringAlgorithm == "" {
+		slopeResponse.Attributes.Warnings = append(slopeResponse.Attributes.Warnings, "ColoringAlgorithm not set, default 'interpolation' applied")
+	}
 
 	// verify request data
 	err = verifySlopeRequestData(request, slopeRequest)
 	if err != nil {
-		slog.Warn("slope request: error verifying request data", "error", err, "ID", slopeRequest.ID)
-		slopeResponse.Attributes.Error.Code = "6060"
-		slopeResponse.Attributes.Error.Title = "error verifying request data"
-		slopeResponse.Attributes.Error.Detail = err.Error()
-		buildSlopeResponse(writer, http.StatusBadRequest, slopeResponse)
+		slog.WarnContext(request.Context(), "slope request: error verifying request data", "error", err, "ID", slopeRequest.ID)
+		slopeResponse.Attributes.Error = newErrorObject(EndpointSlope, ReasonVerifyingRequestData, err.Error())
+		buildSlopeResponse(writer, request, http.StatusBadRequest, slopeResponse)
 		return
 	}
 
@@ -93,7 +134,45 @@ func slopeRequest(writer http.ResponseWriter, request *http.Request) {
 	var outputFormat string
 
 	// determine type of coordinates
-	if slopeRequest.Attributes.Zone != 0 {
+	if slopeRequest.Attributes.AdministrativeArea != "" {
+		// input from administrative area (mosaic of all tiles clipped to boundary, UTM zone of area)
+		outputFormat = "png"
+		if slopeRequest.Attributes.Zone != 0 {
+			outputFormat = "geotiff"
+		}
+
+		// get (virtual) tile for administrative area
+		areaTile, vsimemFiles, err := getAdministrativeAreaTile(request.Context(), slopeRequest.Attributes.AdministrativeArea)
+		if err != nil {
+			slog.WarnContext(request.Context(), "slope request: error getting tile for administrative area", "error", err,
+				"administrative area", slopeRequest.Attributes.AdministrativeArea, "ID", slopeRequest.ID)
+			if errors.Is(err, context.Canceled) {
+				// client disconnected, processing aborted, no response required
+				return
+			}
+			if errors.Is(err, ErrAdministrativeAreaNotFound) {
+				slopeResponse.Attributes.Error = newErrorObject(EndpointSlope, ReasonAdministrativeAreaNotFound, err.Error())
+				buildSlopeResponse(writer, request, http.StatusNotFound, slopeResponse)
+				return
+			}
+			if errors.Is(err, ErrOutsideCoverage) {
+				slopeResponse.Attributes.Error = newErrorObject(EndpointSlope, ReasonOutsideCoverage, err.Error())
+				buildSlopeResponse(writer, request, http.StatusNotFound, slopeResponse)
+				return
+			}
+			if errors.Is(err, ErrServerBusy) {
+				slopeResponse.Attributes.Error = newErrorObject(EndpointSlope, ReasonServerBusy, err.Error())
+				writer.Header().Set("Retry-After", strconv.Itoa(getProgConfig().GDALJobQueue.RetryAfter))
+				buildSlopeResponse(writer, request, http.StatusTooManyRequests, slopeResponse)
+				return
+			}
+			slopeResponse.Attributes.Error = newErrorObject(EndpointSlope, ReasonVerifyingRequestData, err.Error())
+			buildSlopeResponse(writer, request, http.StatusBadRequest, slopeResponse)
+			return
+		}
+		defer removeVSIMemFiles(vsimemFiles...)
+		tiles = []TileMetadata{areaTile}
+	} else if slopeRequest.Attributes.Zone != 0 {
 		// input from UTM coordinates
 		zone = slopeRequest.Attributes.Zone
 		easting = slopeRequest.Attributes.Easting
@@ -103,12 +182,20 @@ func slopeRequest(writer http.ResponseWriter, request *http.Request) {
 		// get all tiles (metadata) for given UTM coordinates
 		tiles, err = getAllTilesUTM(zone, easting, northing)
 		if err != nil {
-			slog.Warn("slope request: error getting GeoTIFF tile for UTM coordinates", "error", err,
+			slog.WarnContext(request.Context(), "slope request: error getting GeoTIFF tile for UTM coordinates", "error", err,
 				"easting", easting, "northing", northing, "zone", zone, "ID", slopeRequest.ID)
-			slopeResponse.Attributes.Error.Code = "6080"
-			slopeResponse.Attributes.Error.Title = "getting GeoTIFF tile for UTM coordinates"
-			slopeResponse.Attributes.Error.Detail = err.Error()
-			buildSlopeResponse(writer, http.StatusBadRequest, slopeResponse)
+			if errors.Is(err, ErrOutsideCoverage) {
+				slopeResponse.Attributes.Error = newErrorObject(EndpointSlope, ReasonOutsideCoverage, describeOutsideCoverage(err))
+				buildSlopeResponse(writer, request, http.StatusNotFound, slopeResponse)
+				return
+			}
+			if errors.Is(err, ErrSourceUnavailable) {
+				slopeResponse.Attributes.Error = newErrorObject(EndpointSlope, ReasonSourceUnavailable, err.Error())
+				buildSlopeResponse(writer, request, http.StatusServiceUnavailable, slopeResponse)
+				return
+			}
+			slopeResponse.Attributes.Error = newErrorObject(EndpointSlope, ReasonGettingTileUTM, err.Error())
+			buildSlopeResponse(writer, request, http.StatusBadRequest, slopeResponse)
 			return
 		}
 	} else {
@@ -121,33 +208,74 @@ func slopeRequest(writer http.ResponseWriter, request *http.Request) {
 		tiles, err = getAllTilesLonLat(longitude, latitude)
 		if err != nil {
 			err = fmt.Errorf("error [%w] getting tile for coordinates lon: %.8f, lat: %.8f", err, longitude, latitude)
-			slog.Warn("slope request: error getting GeoTIFF tile for lon/lat coordinates", "error", err,
+			slog.WarnContext(request.Context(), "slope request: error getting GeoTIFF tile for lon/lat coordinates", "error", err,
 				"longitude", longitude, "latitude", latitude, "ID", slopeRequest.ID)
-			slopeResponse.Attributes.Error.Code = "6100"
-			slopeResponse.Attributes.Error.Title = "getting GeoTIFF tile for lon/lat coordinates"
-			slopeResponse.Attributes.Error.Detail = err.Error()
-			buildSlopeResponse(writer, http.StatusBadRequest, slopeResponse)
+			if errors.Is(err, ErrOutsideCoverage) {
+				slopeResponse.Attributes.Error = newErrorObject(EndpointSlope, ReasonOutsideCoverage, describeOutsideCoverage(err))
+				buildSlopeResponse(writer, request, http.StatusNotFound, slopeResponse)
+				return
+			}
+			if errors.Is(err, ErrSourceUnavailable) {
+				slopeResponse.Attributes.Error = newErrorObject(EndpointSlope, ReasonSourceUnavailableLonLat, err.Error())
+				buildSlopeResponse(writer, request, http.StatusServiceUnavailable, slopeResponse)
+				return
+			}
+			slopeResponse.Attributes.Error = newErrorObject(EndpointSlope, ReasonGettingTileLonLat, err.Error())
+			buildSlopeResponse(writer, request, http.StatusBadRequest, slopeResponse)
 			return
 		}
 	}
 
+	// explicit output format (overrides default for type of coordinates)
+	if slopeRequest.Attributes.OutputFormat != "" {
+		outputFormat = strings.ToLower(slopeRequest.Attributes.OutputFormat)
+	}
+
+	// conditional request: unchanged response (same parameters, same tiles) needs no processing
+	etag := buildETag("slope", slopeRequest, tiles)
+	if checkNotModified(writer, request, etag) {
+		return
+	}
+
+	// color of nodata areas (transparent, fill, preserve)
+	colorTextFileContent := applyNoDataHandling(slopeRequest.Attributes.ColorTextFileContent, slopeRequest.Attributes.NoDataHandling, slopeRequest.Attributes.NoDataColor)
+
 	// build slope for all existing tiles
-	for _, tile := range tiles {
-		slope, err := generateSlopeObjectForTile(tile, outputFormat, slopeRequest.Attributes.GradientAlgorithm, slopeRequest.Attributes.ColorTextFileContent, slopeRequest.Attributes.ColoringAlgorithm)
-		if err != nil {
-			slog.Warn("slope request: error generating slope object for tile", "error", err, "ID", slopeRequest.ID)
-			slopeResponse.Attributes.Error.Code = "6120"
-			slopeResponse.Attributes.Error.Title = "error generating slope object for tile"
-			slopeResponse.Attributes.Error.Detail = err.Error()
-			buildSlopeResponse(writer, http.StatusBadRequest, slopeResponse)
+	slopes, tileErrors, err := generateObjectsForTiles(request.Context(), tiles, func(tile TileMetadata) (Slope, error) {
+		return generateSlopeObjectForTile(request.Context(), tile, outputFormat, slopeRequest.Attributes.GeoTIFFOptions, slopeRequest.Attributes.GradientAlgorithm, colorTextFileContent, slopeRequest.Attributes.ColoringAlgorithm, slopeRequest.Attributes.MaskWater)
+	})
+	if err != nil {
+		slog.WarnContext(request.Context(), "slope request: error generating slope object for tile", "error", err, "ID", slopeRequest.ID)
+		if errors.Is(err, context.Canceled) {
+			// client disconnected, processing aborted, no response required
+			return
+		}
+		if errors.Is(err, ErrServerBusy) {
+			slopeResponse.Attributes.Error = newErrorObject(EndpointSlope, ReasonServerBusy, err.Error())
+			writer.Header().Set("Retry-After", strconv.Itoa(getProgConfig().GDALJobQueue.RetryAfter))
+			buildSlopeResponse(writer, request, http.StatusTooManyRequests, slopeResponse)
 			return
 		}
-		slopeResponse.Attributes.Slopes = append(slopeResponse.Attributes.Slopes, slope)
+		slopeResponse.Attributes.Error = newErrorObject(EndpointSlope, ReasonGeneratingObject, err.Error())
+		buildSlopeResponse(writer, request, http.StatusBadRequest, slopeResponse)
+		return
+	}
+	slopeResponse.Attributes.Slopes = slopes
+	slopeResponse.Attributes.TileErrors = tileErrors
+	if len(tileErrors) > 0 {
+		slopeResponse.Attributes.Warnings = append(slopeResponse.Attributes.Warnings,
+			fmt.Sprintf("%d of %d tiles could not be processed (see TileErrors)", len(tileErrors), len(tiles)))
+		slog.WarnContext(request.Context(), "slope request: partial success, tiles could not be processed", "tile errors", tileErrors, "ID", slopeRequest.ID)
 	}
+	addUsage(request.Context(), 0, len(slopes))
 
 	// success response
 	slopeResponse.Attributes.IsError = false
-	buildSlopeResponse(writer, http.StatusOK, slopeResponse)
+	if len(tileErrors) == 0 {
+		// partial responses are not cacheable
+		setETag(writer, etag)
+	}
+	buildSlopeResponse(writer, request, http.StatusOK, slopeResponse)
 }
 
 /*
@@ -168,18 +296,6 @@ func verifySlopeRequestData(request *http.Request, slopeRequest SlopeRequest) er
 		return fmt.Errorf("unexpected or missing HTTP header field Content-Type, value = [%s], expected 'application/json'", contentType)
 	}
 
-	// verify HTTP header
-	accept := request.Header.Get("Accept")
-	isAcceptValid := true
-	switch {
-	case strings.HasPrefix(strings.ToLower(accept), "application/json"):
-	default:
-		isAcceptValid = false
-	}
-	if !isAcceptValid {
-		return fmt.Errorf("unexpected or missing HTTP header field Accept, value = [%s], expected 'application/json'", accept)
-	}
-
 	// verify Type
 	if slopeRequest.Type != TypeSlopeRequest {
 		return fmt.Errorf("unexpected request Type [%v]", slopeRequest.Type)
@@ -191,7 +307,7 @@ func verifySlopeRequestData(request *http.Request, slopeRequest SlopeRequest) er
 	}
 
 	// verify coordinates (either utm or lon/lat coordinates must be set)
-	if slopeRequest.Attributes.Zone == 0 && slopeRequest.Attributes.Longitude == 0 {
+	if slopeRequest.Attributes.AdministrativeArea == "" && slopeRequest.Attributes.Zone == 0 && slopeRequest.Attributes.Longitude == 0 {
 		return errors.New("either utm or lon/lat coordinates must be set")
 	}
 
@@ -234,77 +350,71 @@ func verifySlopeRequestData(request *http.Request, slopeRequest SlopeRequest) er
 		}
 	}
 
-	return nil
-}
+	// verify output format
+	switch strings.ToLower(slopeRequest.Attributes.OutputFormat) {
+	case "":
+	case "geotiff":
+	case "png":
+	default:
+		return errors.New("unsupported output format (not geotiff, png)")
+	}
 
-/*
-buildSlopeResponse builds HTTP responses with specified status and body.
-It sets the Content-Type and Content-Length headers before writing the response body.
-This function is used to construct consistent HTTP responses throughout the application.
-*/
-func buildSlopeResponse(writer http.ResponseWriter, httpStatus int, slopeResponse SlopeResponse) {
-	// log limit length of body (e.g., the slope objects as part of the body can be very large)
-	maxBodyLength := 1024
-
-	// CORS: allow requests from any origin
-	writer.Header().Set("Access-Control-Allow-Origin", "*")
-	// CORS: allowed methods
-	writer.Header().Set("Access-Control-Allow-Methods", "POST")
-	// CORS: allowed headers
-	writer.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
-	// marshal response
-	body, err := json.MarshalIndent(slopeResponse, "", "  ")
+	// verify GeoTIFF creation options
+	err = verifyGeoTIFFOptions(slopeRequest.Attributes.GeoTIFFOptions)
 	if err != nil {
-		slog.Error("error marshaling point response", "error", err, "body length", len(body),
-			fmt.Sprintf("body (limited to first %d bytes)", maxBodyLength), body[:maxBodyLength])
-
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
+		return err
 	}
 
-	// gzip response body
-	var bytesBuffer bytes.Buffer
-	gz := gzip.NewWriter(&bytesBuffer)
-
-	_, err = gz.Write(body)
+	// verify nodata handling
+	err = verifyNoDataHandling(slopeRequest.Attributes.NoDataHandling, slopeRequest.Attributes.NoDataColor)
 	if err != nil {
-		slog.Error("error [%v] at gz.Write()", "error", err)
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
+		return err
 	}
 
-	err = gz.Close()
+	// verify water masking
+	err = verifyMaskWater(slopeRequest.Attributes.MaskWater)
 	if err != nil {
-		slog.Error("error [%v] at gz.Close()", "error", err)
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
+		return err
 	}
 
-	// set headers
-	writer.Header().Set("Content-Encoding", "gzip")
-	writer.Header().Set("Content-Type", JSONAPIMediaType)
-	writer.WriteHeader(httpStatus)
+	return nil
+}
 
-	// send response
-	_, err = writer.Write(bytesBuffer.Bytes())
-	if err != nil {
-		slog.Error("error writing HTTP response body", "error", err, "body length", len(body),
-			fmt.Sprintf("body (limited to first %d bytes)", maxBodyLength), body[:maxBodyLength])
-	}
+/*
+buildSlopeResponse builds HTTP responses with specified status and body.
+It sets the Content-Type and Content-Length headers before writing the response body.
+This function is used to construct consistent HTTP responses throughout the application.
+*/
+func buildSlopeResponse(writer http.ResponseWriter, request *http.Request, httpStatus int, slopeResponse SlopeResponse) {
+	// response metadata (versions, processing duration, cache hit)
+	slopeResponse.Meta = newResponseMeta(request.Context())
+
+	// skip compression if payload is already compressed (e.g. PNG)
+	skipCompression := len(slopeResponse.Attributes.Slopes) > 0 && isCompressedDataFormat(slopeResponse.Attributes.Slopes[0].DataFormat)
+
+	// encode (streaming) and send response
+	streamJSONResponse(writer, request, httpStatus, slopeResponse, skipCompression)
 }
 
 /*
 generateSlopeObjectForTile builds slope object for given tile index.
 */
-func generateSlopeObjectForTile(tile TileMetadata, outputFormat string, gradientAlgorithm string, colorTextFileContent []string, coloringAlgorithm string) (Slope, error) {
+func generateSlopeObjectForTile(ctx context.Context, tile TileMetadata, outputFormat string, geotiffOptions GeoTIFFOptions, gradientAlgorithm string, colorTextFileContent []string, coloringAlgorithm string, maskWater bool) (Slope, error) {
 	var slope Slope
 	var boundingBox WGS84BoundingBox
 
-	// run operations in temp directory
-	tempDir, err := os.MkdirTemp("", "dtm-elevation-service-slope-")
+	// lookup response cache
+	cacheKey := buildResponseCacheKey("slope", tile.Index, tile.Actuality, outputFormat, geotiffOptions, gradientAlgorithm, colorTextFileContent, coloringAlgorithm, maskWater)
+	cached, found := responseCache.Get(cacheKey)
+	recordCacheLookup(ctx, found)
+	if found {
+		return cached.(Slope), nil
+	}
+
+	// run operations in temp directory (color text file) and in memory (/vsimem)
+	tempDir, err := createTempDirectory("slope")
 	if err != nil {
-		return slope, fmt.Errorf("error [%w] at os.MkdirTemp()", err)
+		return slope, fmt.Errorf("error [%w] at createTempDirectory()", err)
 	}
 	defer func() {
 		_ = os.RemoveAll(tempDir)
@@ -317,83 +427,34 @@ func generateSlopeObjectForTile(tile TileMetadata, outputFormat string, gradient
 		return slope, fmt.Errorf("error [%w] creating 'color-text-file'", err)
 	}
 
-	inputGeoTIFF := tile.Path
-	slopeUTMGeoTIFF := filepath.Join(tempDir, tile.Index+".slope.utm.tif")
-	slopeColorUTMGeoTIFF := filepath.Join(tempDir, tile.Index+".slope.color.utm.tif")
-	slopeWebmercatorGeoTIFF := filepath.Join(tempDir, tile.Index+".slope.webmercator.tif")
-	slopeColorWebmercatoPNG := filepath.Join(tempDir, tile.Index+".slope.color.webmercator.png")
-
-	// 1. create native slope with 'gdaldem slope'
+	// create colored slope (GeoTIFF in UTM or PNG in Webmercator) with 'gdaldem slope' and 'gdaldem color-relief'
 	// e.g. gdaldem slope dgm1_32_497_5670_1_he.tif 32_497_5670_hangneigung.utm.tif -alg Horn -compute_edges
-	commandExitStatus, commandOutput, err := runCommand("gdaldem", []string{"slope", inputGeoTIFF, slopeUTMGeoTIFF, "-alg", gradientAlgorithm, "-compute_edges"})
+	data, err := dtm.GenerateProduct(ctx, tile.Path, dtm.ProductOptions{
+		Name:            "slope",
+		Mode:            "slope",
+		ModeSwitches:    []string{"-alg", gradientAlgorithm, "-compute_edges"},
+		ColorTextFile:   colorTextFile,
+		NearestColor:    coloringAlgorithm == "rounding",
+		OutputFormat:    outputFormat,
+		CreationOptions: buildGeoTIFFCreationOptions(geotiffOptions),
+		PostProcess:     waterMasking(maskWater),
+	})
 	if err != nil {
-		return slope, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
+		return slope, fmt.Errorf("error [%w] at dtm.GenerateProduct()", err)
 	}
-	// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-	// fmt.Printf("commandOutput: %s\n", commandOutput)
-
-	var data []byte
-	switch strings.ToLower(outputFormat) {
-	case "geotiff":
-		// 2. colorize slope with 'gdaldem color-relief'
-		options := []string{"color-relief", slopeUTMGeoTIFF, colorTextFile, slopeColorUTMGeoTIFF, "-alpha"}
-		if coloringAlgorithm == "rounding" {
-			options = append(options, "-nearest_color_entry")
-		}
-		commandExitStatus, commandOutput, err = runCommand("gdaldem", options)
-		if err != nil {
-			return slope, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
-		}
-		// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-		// fmt.Printf("commandOutput: %s\n", commandOutput)
-
-		data, err = os.ReadFile(slopeColorUTMGeoTIFF)
-		if err != nil {
-			return slope, fmt.Errorf("error [%w] at os.ReadFile()", err)
-		}
 
-	case "png":
-		// 2. convert UTM (EPSG:25832/EPSG:25833) to Webmercator (EPSG:3857) with 'gdalwarp'
-		// e.g. gdalwarp -t_srs EPSG:3857 32_497_5670_hangneigung.utm.tif 32_497_5670_hangneigung.webmercator.tif
-		commandExitStatus, commandOutput, err = runCommand("gdalwarp", []string{"-t_srs", "EPSG:3857", slopeUTMGeoTIFF, slopeWebmercatorGeoTIFF})
-		if err != nil {
-			return slope, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
-		}
-		// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-		// fmt.Printf("commandOutput: %s\n", commandOutput)
-
-		// 3. colorize slope with 'gdaldem color-relief' (creates PNG file)
-		// e.g. gdaldem color-relief 32_497_5670_hangneigung.webmercator.tif slope-colors.txt 32_497_5670_hangneigung.webmercator.png -alpha
-		options := []string{"color-relief", slopeWebmercatorGeoTIFF, colorTextFile, slopeColorWebmercatoPNG, "-alpha"}
-		if coloringAlgorithm == "rounding" {
-			options = append(options, "-nearest_color_entry")
-		}
-		commandExitStatus, commandOutput, err = runCommand("gdaldem", options)
-		if err != nil {
-			return slope, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
-		}
-		// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-		// fmt.Printf("commandOutput: %s\n", commandOutput)
-
-		// 4. get bounding box (in wgs84) for webmercator tif (georeference of webmercator png )
+	// get bounding box (in wgs84) for webmercator png (georeference of webmercator png)
+	if strings.EqualFold(outputFormat, "png") {
 		boundingBox, err = calculateWGS84BoundingBox(tile)
 		if err != nil {
 			return slope, fmt.Errorf("error [%w] at calculateWGS84BoundingBox(), file: %s", err, tile.Path)
 		}
-
-		// read result file
-		data, err = os.ReadFile(slopeColorWebmercatoPNG)
-		if err != nil {
-			return slope, fmt.Errorf("error [%w] at os.ReadFile()", err)
-		}
-
-	default:
-		return slope, fmt.Errorf("unsupported format [%s]", outputFormat)
 	}
 
 	// set slope return structure
 	slope.Data = data
 	slope.DataFormat = outputFormat
+	slope.Filename = buildObjectFilename(tile.Index, "slope", gradientAlgorithm, tile.Actuality, outputFormat)
 	slope.Actuality = tile.Actuality
 	slope.Origin = tile.Source
 	slope.TileIndex = tile.Index
@@ -403,11 +464,21 @@ func generateSlopeObjectForTile(tile TileMetadata, outputFormat string, gradient
 	attribution := "unknown"
 	resource, err := getElevationResource(tile.Source)
 	if err != nil {
-		slog.Error("slope request: error getting elevation resource", "error", err, "source", tile.Source)
+		slog.ErrorContext(ctx, "slope request: error getting elevation resource", "error", err, "source", tile.Source)
 	} else {
 		attribution = resource.Attribution
 	}
 	slope.Attribution = attribution
 
+	// get provenance of source files (delivery file name and checksum)
+	sourceFiles, err := getSourceFiles(tile)
+	if err != nil {
+		slog.ErrorContext(ctx, "slope request: error getting source files", "error", err, "tile", tile.Index)
+	}
+	slope.SourceFiles = sourceFiles
+
+	// add to response cache
+	responseCache.Add(cacheKey, slope, len(slope.Data))
+
 	return slope, nil
 }
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TypeStatisticsResponse is the type of the statistics response
+const TypeStatisticsResponse = "StatisticsResponse"
+
+// StatisticsCounter represents the name and value of a statistics counter.
+type StatisticsCounter struct {
+	Name  string
+	Value uint64
+}
+
+// statisticsCounters lists all statistics counters (in order of logging)
+var statisticsCounters = []struct {
+	name    string
+	counter *uint64
+}{
+	{"PointRequests", &PointRequests},
+	{"UTMPointRequests", &UTMPointRequests},
+	{"GPXRequests", &GPXRequests},
+	{"GPXAnalyzeRequests", &GPXAnalyzeRequests},
+	{"GPXPoints", &GPXPoints},
+	{"DGMPoints", &DGMPoints},
+	{"ContoursRequests", &ContoursRequests},
+	{"HillshadeRequests", &HillshadeRequests},
+	{"SlopeRequests", &SlopeRequests},
+	{"AspectRequests", &AspectRequests},
+	{"TPIRequests", &TPIRequests},
+	{"TRIRequests", &TRIRequests},
+	{"RoughnessRequests", &RoughnessRequests},
+	{"RawTIFRequests", &RawTIFRequests},
+	{"ColorReliefRequests", &ColorReliefRequests},
+	{"HistogramRequests", &HistogramRequests},
+	{"ElevationProfileRequests", &ElevationProfileRequests},
+	{"VisualizeRequests", &VisualizeRequests},
+	{"JobRequests", &JobRequests},
+	{"ExportRequests", &ExportRequests},
+	{"CompareRequests", &CompareRequests},
+	{"AspectRoseRequests", &AspectRoseRequests},
+	{"ElevationRangeRequests", &ElevationRangeRequests},
+	{"ContourLineRequests", &ContourLineRequests},
+	{"ElevationMatrixRequests", &ElevationMatrixRequests},
+	{"LegendRequests", &LegendRequests},
+	{"ColorTableRequests", &ColorTableRequests},
+	{"ContoursExportRequests", &ContoursExportRequests},
+	{"ResponseCacheHits", &ResponseCacheHits},
+	{"ResponseCacheMisses", &ResponseCacheMisses},
+	{"TileCacheHits", &TileCacheHits},
+	{"TileCacheMisses", &TileCacheMisses},
+	{"GDALJobsQueued", &GDALJobsQueued},
+	{"GDALJobsRejected", &GDALJobsRejected},
+	{"WorkerPoolQueued", &WorkerPoolQueued},
+	{"WorkerPoolRejections", &WorkerPoolRejections},
+	{"AuthenticationFailures", &AuthenticationFailures},
+	{"AuthorizationFailures", &AuthorizationFailures},
+	{"IPFilterRejections", &IPFilterRejections},
+	{"RecoveredPanics", &RecoveredPanics},
+	{"BannedRequests", &BannedRequests},
+	{"ClientBans", &ClientBans},
+	{"LoadSheddingRejections", &LoadSheddingRejections},
+	{"PartialResponses", &PartialResponses},
+}
+
+// statistics totals (since program start) and start of current statistics period
+var (
+	statisticsMutex       sync.Mutex
+	statisticsTotals      = make(map[string]uint64)
+	statisticsPeriodStart = time.Now()
+	programStartTime      = time.Now()
+)
+
+// StatisticsResponse represents the statistics of this service.
+type StatisticsResponse struct {
+	Type       string
+	ID         string
+	Attributes struct {
+		Version       string
+		StartTime     string
+		Uptime        int64 // in seconds
+		PeriodStart   string
+		CurrentPeriod map[string]uint64 // counters since start of current period (reset daily)
+		SinceStart    map[string]uint64 // counters since program start
+		ResponseCache struct {
+			Entries int
+			Bytes   int
+		}
+		TileCache struct {
+			Tiles int
+		}
+	}
+}
+
+/*
+resetStatistics reads and resets (atomically) all statistics counters. The values are added to the totals
+since program start, and a new statistics period begins.
+*/
+func resetStatistics() []StatisticsCounter {
+	statisticsMutex.Lock()
+	defer statisticsMutex.Unlock()
+
+	counters := make([]StatisticsCounter, 0, len(statisticsCounters))
+	for _, statisticsCounter := range statisticsCounters {
+		value := atomic.SwapUint64(statisticsCounter.counter, 0)
+		statisticsTotals[statisticsCounter.name] += value
+		counters = append(counters, StatisticsCounter{Name: statisticsCounter.name, Value: value})
+	}
+	statisticsPeriodStart = time.Now()
+
+	return counters
+}
+
+/*
+statisticsRequest handles 'statistics' request (counters, uptime, cache). The endpoint is protected by
+the authentication rules (scope 'dtm:stats' required by default); if authentication is disabled, only the
+admin networks have access (client IP behind trusted reverse proxies, see getClientIP).
+*/
+func statisticsRequest(writer http.ResponseWriter, request *http.Request) {
+	if jwtValidator == nil {
+		allowedNetworks, err := parseNetworks(getProgConfig().Admin.AllowedNetworks)
+		clientIP := getClientIP(request)
+		if err != nil || !isIPInNetworks(clientIP, allowedNetworks) {
+			slog.WarnContext(request.Context(), "statistics request: access denied", "client ip", clientIP)
+			http.Error(writer, "Forbidden", http.StatusForbidden)
+			return
+		}
+	}
+
+	statisticsResponse := StatisticsResponse{Type: TypeStatisticsResponse}
+	statisticsResponse.Attributes.Version = progVersion
+	statisticsResponse.Attributes.StartTime = programStartTime.Format(time.RFC3339)
+	statisticsResponse.Attributes.Uptime = int64(time.Since(programStartTime).Seconds())
+	statisticsResponse.Attributes.CurrentPeriod = make(map[string]uint64, len(statisticsCounters))
+	statisticsResponse.Attributes.SinceStart = make(map[string]uint64, len(statisticsCounters))
+
+	statisticsMutex.Lock()
+	statisticsResponse.Attributes.PeriodStart = statisticsPeriodStart.Format(time.RFC3339)
+	for _, statisticsCounter := range statisticsCounters {
+		value := atomic.LoadUint64(statisticsCounter.counter)
+		statisticsResponse.Attributes.CurrentPeriod[statisticsCounter.name] = value
+		statisticsResponse.Attributes.SinceStart[statisticsCounter.name] = statisticsTotals[statisticsCounter.name] + value
+	}
+	statisticsMutex.Unlock()
+
+	statisticsResponse.Attributes.ResponseCache.Entries, statisticsResponse.Attributes.ResponseCache.Bytes = responseCache.Len()
+	statisticsResponse.Attributes.TileCache.Tiles = tileCache.Len()
+
+	// statistics must not be cached
+	writer.Header().Set("Cache-Control", "no-store")
+	streamJSONResponse(writer, request, http.StatusOK, statisticsResponse, false)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// tempDirPrefix is the common prefix of all temporary directories created by this service
+const tempDirPrefix = "dtm-elevation-service-"
+
+/*
+getTempDirectory returns the configured base directory for temporary files (default: system temp directory).
+*/
+func getTempDirectory() string {
+	if tempDirectory := getProgConfig().TempDirectory; tempDirectory != "" {
+		return tempDirectory
+	}
+	return os.TempDir()
+}
+
+/*
+initTempDirectory verifies the base directory for temporary files and points GDAL to this directory.
+*/
+func initTempDirectory() error {
+	tempDirectory := getTempDirectory()
+
+	fileInfo, err := os.Stat(tempDirectory)
+	if err != nil {
+		return fmt.Errorf("error [%w] at os.Stat(), temp directory: %s", err, tempDirectory)
+	}
+	if !fileInfo.IsDir() {
+		return fmt.Errorf("temp directory [%s] is not a directory", tempDirectory)
+	}
+
+	// GDAL uses CPL_TMPDIR for its own temporary files
+	err = os.Setenv("CPL_TMPDIR", tempDirectory)
+	if err != nil {
+		return fmt.Errorf("error [%w] at os.Setenv(), CPL_TMPDIR: %s", err, tempDirectory)
+	}
+
+	return nil
+}
+
+/*
+sweepOrphanedTempDirectories removes temporary directories left behind by a previous (crashed) run.
+*/
+func sweepOrphanedTempDirectories() {
+	tempDirectory := getTempDirectory()
+
+	entries, err := os.ReadDir(tempDirectory)
+	if err != nil {
+		slog.Error("error reading temp directory", "error", err, "directory", tempDirectory)
+		return
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), tempDirPrefix) {
+			continue
+		}
+		orphan := filepath.Join(tempDirectory, entry.Name())
+		err = os.RemoveAll(orphan)
+		if err != nil {
+			slog.Warn("error removing orphaned temp directory", "error", err, "directory", orphan)
+			continue
+		}
+		removed++
+	}
+
+	slog.Info("orphaned temp directories removed", "count", removed, "temp directory", tempDirectory)
+}
+
+/*
+createTempDirectory creates a new temporary directory (e.g. for 'color-text-file') in the configured
+base directory. It fails if the free disk space falls below the configured minimum.
+*/
+func createTempDirectory(name string) (string, error) {
+	tempDirectory := getTempDirectory()
+	minFreeSpace := getProgConfig().TempMinFreeSpace
+
+	// disk-usage guard
+	if minFreeSpace > 0 {
+		var stat syscall.Statfs_t
+		err := syscall.Statfs(tempDirectory, &stat)
+		if err != nil {
+			return "", fmt.Errorf("error [%w] at syscall.Statfs(), temp directory: %s", err, tempDirectory)
+		}
+		freeMegabytes := stat.Bavail * uint64(stat.Bsize) / (1024 * 1024)
+		if freeMegabytes < uint64(minFreeSpace) {
+			return "", fmt.Errorf("insufficient free space in temp directory [%s]: %d MB available, %d MB required",
+				tempDirectory, freeMegabytes, minFreeSpace)
+		}
+	}
+
+	dir, err := os.MkdirTemp(tempDirectory, tempDirPrefix+name+"-")
+	if err != nil {
+		return "", fmt.Errorf("error [%w] at os.MkdirTemp()", err)
+	}
+
+	return dir, nil
+}
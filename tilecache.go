@@ -0,0 +1,213 @@
+package main
+
+import (
+	"container/list"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/airbusgeo/godal"
+)
+
+// TileCache is an in-memory LRU cache (with TTL) for the elevation grids of recently used tiles. It is shared
+// across point requests (point, utmpoint), since interactive map users typically click repeatedly in the same
+// square kilometer (no GeoTIFF file access for subsequent points within a cached tile).
+type TileCache struct {
+	mutex    sync.Mutex
+	maxTiles int
+	ttl      time.Duration
+	lru      *list.List
+	items    map[string]*list.Element
+}
+
+// tileGrid represents the elevation grid (first band) of a tile.
+type tileGrid struct {
+	path         string
+	geoTransform [6]float64
+	sizeX        int
+	sizeY        int
+	values       []float32
+	noData       float64
+	hasNoData    bool
+	expires      time.Time
+}
+
+// tileCache is the global tile cache (nil if disabled)
+var tileCache *TileCache
+
+/*
+newTileCache creates a new tile cache. A cache with maxTiles <= 0 is disabled (nil).
+*/
+func newTileCache(maxTiles int, ttl time.Duration) *TileCache {
+	if maxTiles <= 0 {
+		return nil
+	}
+	return &TileCache{
+		maxTiles: maxTiles,
+		ttl:      ttl,
+		lru:      list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+/*
+get returns the cached elevation grid for a tile file (if present and not expired).
+*/
+func (cache *TileCache) get(path string) (*tileGrid, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	element, found := cache.items[path]
+	if !found {
+		atomic.AddUint64(&TileCacheMisses, 1)
+		return nil, false
+	}
+	grid := element.Value.(*tileGrid)
+	if cache.ttl > 0 && time.Now().After(grid.expires) {
+		cache.lru.Remove(element)
+		delete(cache.items, path)
+		atomic.AddUint64(&TileCacheMisses, 1)
+		return nil, false
+	}
+
+	cache.lru.MoveToFront(element)
+	atomic.AddUint64(&TileCacheHits, 1)
+	return grid, true
+}
+
+/*
+add adds (or replaces) the elevation grid of a tile file and evicts least recently used grids.
+*/
+func (cache *TileCache) add(grid *tileGrid) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if element, found := cache.items[grid.path]; found {
+		cache.lru.Remove(element)
+	}
+	grid.expires = time.Now().Add(cache.ttl)
+	cache.items[grid.path] = cache.lru.PushFront(grid)
+
+	for cache.lru.Len() > cache.maxTiles {
+		element := cache.lru.Back()
+		cache.lru.Remove(element)
+		delete(cache.items, element.Value.(*tileGrid).path)
+	}
+}
+
+/*
+Len returns the number of cached tiles.
+*/
+func (cache *TileCache) Len() int {
+	if cache == nil {
+		return 0
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	return cache.lru.Len()
+}
+
+/*
+getTileGrid returns the elevation grid of a tile file (from tile cache if enabled).
+*/
+func getTileGrid(filename string) (*tileGrid, error) {
+	if tileCache == nil {
+		return loadTileGrid(filename)
+	}
+
+	grid, found := tileCache.get(filename)
+	if !found {
+		var err error
+		grid, err = loadTileGrid(filename)
+		if err != nil {
+			return nil, fmt.Errorf("error [%w] at loadTileGrid()", err)
+		}
+		tileCache.add(grid)
+	}
+	return grid, nil
+}
+
+/*
+loadTileGrid reads the elevation grid (first band) of a tile file.
+*/
+func loadTileGrid(filename string) (*tileGrid, error) {
+	dataset, err := godal.Open(filename, godal.RasterOnly())
+	if err != nil {
+		return nil, fmt.Errorf("error [%w] at godal.Open(), file: %s", err, filename)
+	}
+	defer dataset.Close()
+
+	gt, err := dataset.GeoTransform()
+	if err != nil {
+		return nil, fmt.Errorf("error [%w] at dataset.GeoTransform(), file: %s", err, filename)
+	}
+
+	// only north-up images are supported (gt[2] and gt[4] = 0)
+	if gt[2] != 0.0 || gt[4] != 0.0 {
+		return nil, fmt.Errorf("raster [%s] appears to be rotated or skewed (gt[2]=%f, gt[4]=%f)", filename, gt[2], gt[4])
+	}
+	if gt[1] == 0 || gt[5] == 0 {
+		return nil, fmt.Errorf("invalid geotransform: pixel width (gt[1]=%f) or height (gt[5]=%f) is zero", gt[1], gt[5])
+	}
+
+	bands := dataset.Bands()
+	if len(bands) == 0 {
+		return nil, fmt.Errorf("no raster bands found in file [%s]", filename)
+	}
+	structure := dataset.Structure()
+	grid := &tileGrid{path: filename, geoTransform: gt, sizeX: structure.SizeX, sizeY: structure.SizeY,
+		values: make([]float32, structure.SizeX*structure.SizeY)}
+	err = bands[0].Read(0, 0, grid.values, structure.SizeX, structure.SizeY)
+	if err != nil {
+		return nil, fmt.Errorf("error [%w] at band.Read(), file: %s", err, filename)
+	}
+	grid.noData, grid.hasNoData = bands[0].NoData()
+
+	return grid, nil
+}
+
+/*
+elevation returns the elevation for UTM coordinates (same semantics as dtm.ElevationFromFile()).
+*/
+func (grid *tileGrid) elevation(xUTM, yUTM float64) (float64, error) {
+	col := int(math.Floor((xUTM - grid.geoTransform[0]) / grid.geoTransform[1]))
+	row := int(math.Floor((yUTM - grid.geoTransform[3]) / grid.geoTransform[5]))
+	if col < 0 || col >= grid.sizeX || row < 0 || row >= grid.sizeY {
+		return 0, fmt.Errorf("coordinate (%.3f, %.3f) is outside the raster bounds [%s] (pixel %d, %d)", xUTM, yUTM, grid.path, col, row)
+	}
+
+	elevation := float64(grid.values[row*grid.sizeX+col])
+	if grid.hasNoData && elevation == float64(float32(grid.noData)) {
+		return 0, fmt.Errorf("coordinate (%.3f, %.3f) corresponds to a NoData value (%.3f) in [%s]", xUTM, yUTM, grid.noData, grid.path)
+	}
+
+	return elevation, nil
+}
+
+/*
+window returns the elevations of the 3x3 cells around UTM coordinates (row-major, center cell at index 4).
+It returns false if a cell is outside the grid or without data.
+*/
+func (grid *tileGrid) window(xUTM, yUTM float64) ([9]float64, bool) {
+	var window [9]float64
+
+	col := int(math.Floor((xUTM - grid.geoTransform[0]) / grid.geoTransform[1]))
+	row := int(math.Floor((yUTM - grid.geoTransform[3]) / grid.geoTransform[5]))
+	if col < 1 || col >= grid.sizeX-1 || row < 1 || row >= grid.sizeY-1 {
+		return window, false
+	}
+
+	for i := range 9 {
+		value := grid.values[(row+i/3-1)*grid.sizeX+col+i%3-1]
+		if (grid.hasNoData && float64(value) == float64(float32(grid.noData))) || value < -9998.9 {
+			return window, false
+		}
+		window[i] = float64(value)
+	}
+
+	return window, true
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// certificateCheckInterval is the min interval between checks for renewed certificate files
+const certificateCheckInterval = 60 * time.Second
+
+// CertificateReloader provides the server certificate and reloads it when the certificate files change
+// (e.g. renewed by an external ACME client like certbot), without restarting the service.
+type CertificateReloader struct {
+	mutex       sync.Mutex
+	certFile    string
+	keyFile     string
+	certificate *tls.Certificate
+	modTime     time.Time
+	lastCheck   time.Time
+}
+
+/*
+newCertificateReloader loads the certificate and key files.
+*/
+func newCertificateReloader(certFile string, keyFile string) (*CertificateReloader, error) {
+	reloader := &CertificateReloader{certFile: certFile, keyFile: keyFile}
+	err := reloader.load()
+	if err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
+
+/*
+load (re)loads the certificate and key files.
+*/
+func (r *CertificateReloader) load() error {
+	fileInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return fmt.Errorf("error [%w] at os.Stat(), file: %s", err, r.certFile)
+	}
+	certificate, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("error [%w] at tls.LoadX509KeyPair(), files: %s, %s", err, r.certFile, r.keyFile)
+	}
+	r.certificate = &certificate
+	r.modTime = fileInfo.ModTime()
+	r.lastCheck = time.Now()
+	return nil
+}
+
+/*
+GetCertificate returns the current certificate (tls.Config callback). Renewed certificate files are
+loaded on the fly; if loading fails, the previous certificate is used further on.
+*/
+func (r *CertificateReloader) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if time.Since(r.lastCheck) < certificateCheckInterval {
+		return r.certificate, nil
+	}
+	r.lastCheck = time.Now()
+
+	fileInfo, err := os.Stat(r.certFile)
+	if err != nil || fileInfo.ModTime().Equal(r.modTime) {
+		return r.certificate, nil
+	}
+	err = r.load()
+	if err != nil {
+		slog.Error("error reloading server certificate, using previous certificate", "error", err)
+		return r.certificate, nil
+	}
+	slog.Info("server certificate reloaded", "certificate", r.certFile)
+
+	return r.certificate, nil
+}
+
+/*
+newACMEManager creates the ACME certificate manager (TLSMode 'acme'). Certificates are obtained and renewed
+automatically for the whitelisted hosts and stored in the cache directory (survives restarts, avoids rate limits).
+*/
+func newACMEManager() (*autocert.Manager, error) {
+	config := progConfig.ACME
+	if len(config.Hosts) == 0 {
+		return nil, errors.New("ACME.Hosts must contain at least one host name")
+	}
+	if config.CacheDirectory == "" {
+		return nil, errors.New("ACME.CacheDirectory must be set")
+	}
+	err := os.MkdirAll(config.CacheDirectory, 0o700)
+	if err != nil {
+		return nil, fmt.Errorf("error [%w] at os.MkdirAll(), directory: %s", err, config.CacheDirectory)
+	}
+
+	manager := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		Cache:      autocert.DirCache(config.CacheDirectory),
+		HostPolicy: autocert.HostWhitelist(config.Hosts...),
+		Email:      config.Email,
+	}
+	return manager, nil
+}
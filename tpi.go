@@ -1,8 +1,7 @@
 package main
 
 import (
-	"bytes"
-	"compress/gzip"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,8 +10,11 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync/atomic"
+
+	"klaus/elevation/dtm-elevation-service/pkg/dtm"
 )
 
 /*
@@ -34,18 +36,14 @@ func tpiRequest(writer http.ResponseWriter, request *http.Request) {
 		// check specifically for the error returned by MaxBytesReader
 		var maxBytesErr *http.MaxBytesError
 		if errors.As(err, &maxBytesErr) {
-			slog.Warn("tpi request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
-			tpiResponse.Attributes.Error.Code = "8000"
-			tpiResponse.Attributes.Error.Title = "request body too large"
-			tpiResponse.Attributes.Error.Detail = fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit)
-			buildTPIResponse(writer, http.StatusRequestEntityTooLarge, tpiResponse)
+			slog.WarnContext(request.Context(), "tpi request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
+			tpiResponse.Attributes.Error = newErrorObject(EndpointTPI, ReasonRequestBodyTooLarge, fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit))
+			buildTPIResponse(writer, request, http.StatusRequestEntityTooLarge, tpiResponse)
 		} else {
 			// handle other read errors
-			slog.Warn("tpi request: error reading request body", "error", err, "ID", "unknown")
-			tpiResponse.Attributes.Error.Code = "8020"
-			tpiResponse.Attributes.Error.Title = "error reading request body"
-			tpiResponse.Attributes.Error.Detail = err.Error()
-			buildTPIResponse(writer, http.StatusBadRequest, tpiResponse)
+			slog.WarnContext(request.Context(), "tpi request: error reading request body", "error", err, "ID", "unknown")
+			tpiResponse.Attributes.Error = newErrorObject(EndpointTPI, ReasonReadingRequestBody, err.Error())
+			buildTPIResponse(writer, request, http.StatusBadRequest, tpiResponse)
 		}
 		return
 	}
@@ -54,14 +52,52 @@ func tpiRequest(writer http.ResponseWriter, request *http.Request) {
 	tpiRequest := TPIRequest{}
 	err = json.Unmarshal(bodyData, &tpiRequest)
 	if err != nil {
-		slog.Warn("tpi request: error unmarshaling request body", "error", err, "ID", "unknown")
-		tpiResponse.Attributes.Error.Code = "8040"
-		tpiResponse.Attributes.Error.Title = "error unmarshaling request body"
-		tpiResponse.Attributes.Error.Detail = err.Error()
-		buildTPIResponse(writer, http.StatusBadRequest, tpiResponse)
+		slog.WarnContext(request.Context(), "tpi request: error unmarshaling request body", "error", err, "ID", "unknown")
+		tpiResponse.Attributes.Error = newErrorObject(EndpointTPI, ReasonUnmarshalingRequestBody, err.Error())
+		buildTPIResponse(writer, request, http.StatusBadRequest, tpiResponse)
 		return
 	}
 
+	// resolve place name (geocoding) into lon/lat coordinates
+	if tpiRequest.Attributes.Place != "" {
+		place, err := geocodePlace(request.Context(), tpiRequest.Attributes.Place)
+		if err != nil {
+			slog.WarnContext(request.Context(), "tpi request: error geocoding place", "error", err, "place", tpiRequest.Attributes.Place, "ID", tpiRequest.ID)
+			tpiResponse.ID = tpiRequest.ID
+			tpiResponse.Attributes.Place = tpiRequest.Attributes.Place
+			if errors.Is(err, ErrPlaceNotFound) {
+				tpiResponse.Attributes.Error = newErrorObject(EndpointTPI, ReasonPlaceNotFound, err.Error())
+				buildTPIResponse(writer, request, http.StatusNotFound, tpiResponse)
+				return
+			}
+			if errors.Is(err, ErrGeocoderUnavailable) {
+				tpiResponse.Attributes.Error = newErrorObject(EndpointTPI, ReasonGeocoderUnavailable, err.Error())
+				buildTPIResponse(writer, request, http.StatusServiceUnavailable, tpiResponse)
+				return
+			}
+			tpiResponse.Attributes.Error = newErrorObject(EndpointTPI, ReasonVerifyingRequestData, err.Error())
+			buildTPIResponse(writer, request, http.StatusBadRequest, tpiResponse)
+			return
+		}
+		tpiRequest.Attributes.Zone = 0
+		tpiRequest.Attributes.Longitude = place.Longitude
+		tpiRequest.Attributes.Latitude = place.Latitude
+		tpiResponse.Attributes.PlaceName = place.DisplayName
+	}
+
+	// named color table (stored server-side) instead of color text file content
+	if tpiRequest.Attributes.ColorTableName != "" {
+		tpiRequest.Attributes.ColorTextFileContent, err = resolveColorTable(request.Context(), tpiRequest.Attributes.ColorTableName, tpiRequest.Attributes.ColorTextFileContent)
+		if err != nil {
+			slog.WarnContext(request.Context(), "tpi request: error resolving color table", "error", err, "name", tpiRequest.Attributes.ColorTableName, "ID", tpiRequest.ID)
+			tpiResponse.ID = tpiRequest.ID
+			tpiResponse.Attributes.ColorTableName = tpiRequest.Attributes.ColorTableName
+			tpiResponse.Attributes.Error = newErrorObject(EndpointTPI, ReasonVerifyingRequestData, err.Error())
+			buildTPIResponse(writer, request, http.StatusBadRequest, tpiResponse)
+			return
+		}
+	}
+
 	// copy request parameters into response
 	tpiResponse.ID = tpiRequest.ID
 	tpiResponse.Attributes.Zone = tpiRequest.Attributes.Zone
@@ -69,17 +105,21 @@ func tpiRequest(writer http.ResponseWriter, request *http.Request) {
 	tpiResponse.Attributes.Northing = tpiRequest.Attributes.Northing
 	tpiResponse.Attributes.Longitude = tpiRequest.Attributes.Longitude
 	tpiResponse.Attributes.Latitude = tpiRequest.Attributes.Latitude
+	tpiResponse.Attributes.Place = tpiRequest.Attributes.Place
+	tpiResponse.Attributes.OutputFormat = tpiRequest.Attributes.OutputFormat
 	tpiResponse.Attributes.ColorTextFileContent = tpiRequest.Attributes.ColorTextFileContent
+	tpiResponse.Attributes.ColorTableName = tpiRequest.Attributes.ColorTableName
 	tpiResponse.Attributes.ColoringAlgorithm = tpiRequest.Attributes.ColoringAlgorithm
+	if tpiRequest.Attributes.ColoringAlgorithm == "" {
+		tpiResponse.Attributes.Warnings = append(tpiResponse.Attributes.Warnings, "ColoringAlgorithm not set, default 'interpolation' applied")
+	}
 
 	// verify request data
 	err = verifyTPIRequestData(request, tpiRequest)
 	if err != nil {
-		slog.Warn("tpi request: error verifying request data", "error", err, "ID", tpiRequest.ID)
-		tpiResponse.Attributes.Error.Code = "8060"
-		tpiResponse.Attributes.Error.Title = "error verifying request data"
-		tpiResponse.Attributes.Error.Detail = err.Error()
-		buildTPIResponse(writer, http.StatusBadRequest, tpiResponse)
+		slog.WarnContext(request.Context(), "tpi request: error verifying request data", "error", err, "ID", tpiRequest.ID)
+		tpiResponse.Attributes.Error = newErrorObject(EndpointTPI, ReasonVerifyingRequestData, err.Error())
+		buildTPIResponse(writer, request, http.StatusBadRequest, tpiResponse)
 		return
 	}
 
@@ -102,12 +142,20 @@ func tpiRequest(writer http.ResponseWriter, request *http.Request) {
 		// get all tiles (metadata) for given UTM coordinates
 		tiles, err = getAllTilesUTM(zone, easting, northing)
 		if err != nil {
-			slog.Warn("tpi request: error getting GeoTIFF tile for UTM coordinates", "error", err,
+			slog.WarnContext(request.Context(), "tpi request: error getting GeoTIFF tile for UTM coordinates", "error", err,
 				"easting", easting, "northing", northing, "zone", zone, "ID", tpiRequest.ID)
-			tpiResponse.Attributes.Error.Code = "8080"
-			tpiResponse.Attributes.Error.Title = "getting GeoTIFF tile for UTM coordinates"
-			tpiResponse.Attributes.Error.Detail = err.Error()
-			buildTPIResponse(writer, http.StatusBadRequest, tpiResponse)
+			if errors.Is(err, ErrOutsideCoverage) {
+				tpiResponse.Attributes.Error = newErrorObject(EndpointTPI, ReasonOutsideCoverage, describeOutsideCoverage(err))
+				buildTPIResponse(writer, request, http.StatusNotFound, tpiResponse)
+				return
+			}
+			if errors.Is(err, ErrSourceUnavailable) {
+				tpiResponse.Attributes.Error = newErrorObject(EndpointTPI, ReasonSourceUnavailable, err.Error())
+				buildTPIResponse(writer, request, http.StatusServiceUnavailable, tpiResponse)
+				return
+			}
+			tpiResponse.Attributes.Error = newErrorObject(EndpointTPI, ReasonGettingTileUTM, err.Error())
+			buildTPIResponse(writer, request, http.StatusBadRequest, tpiResponse)
 			return
 		}
 	} else {
@@ -120,33 +168,74 @@ func tpiRequest(writer http.ResponseWriter, request *http.Request) {
 		tiles, err = getAllTilesLonLat(longitude, latitude)
 		if err != nil {
 			err = fmt.Errorf("error [%w] getting tile for coordinates lon: %.8f, lat: %.8f", err, longitude, latitude)
-			slog.Warn("tpi request: error getting GeoTIFF tile for lon/lat coordinates", "error", err,
+			slog.WarnContext(request.Context(), "tpi request: error getting GeoTIFF tile for lon/lat coordinates", "error", err,
 				"longitude", longitude, "latitude", latitude, "ID", tpiRequest.ID)
-			tpiResponse.Attributes.Error.Code = "8100"
-			tpiResponse.Attributes.Error.Title = "getting GeoTIFF tile for lon/lat coordinates"
-			tpiResponse.Attributes.Error.Detail = err.Error()
-			buildTPIResponse(writer, http.StatusBadRequest, tpiResponse)
+			if errors.Is(err, ErrOutsideCoverage) {
+				tpiResponse.Attributes.Error = newErrorObject(EndpointTPI, ReasonOutsideCoverage, describeOutsideCoverage(err))
+				buildTPIResponse(writer, request, http.StatusNotFound, tpiResponse)
+				return
+			}
+			if errors.Is(err, ErrSourceUnavailable) {
+				tpiResponse.Attributes.Error = newErrorObject(EndpointTPI, ReasonSourceUnavailableLonLat, err.Error())
+				buildTPIResponse(writer, request, http.StatusServiceUnavailable, tpiResponse)
+				return
+			}
+			tpiResponse.Attributes.Error = newErrorObject(EndpointTPI, ReasonGettingTileLonLat, err.Error())
+			buildTPIResponse(writer, request, http.StatusBadRequest, tpiResponse)
 			return
 		}
 	}
 
+	// explicit output format (overrides default for type of coordinates)
+	if tpiRequest.Attributes.OutputFormat != "" {
+		outputFormat = strings.ToLower(tpiRequest.Attributes.OutputFormat)
+	}
+
+	// conditional request: unchanged response (same parameters, same tiles) needs no processing
+	etag := buildETag("tpi", tpiRequest, tiles)
+	if checkNotModified(writer, request, etag) {
+		return
+	}
+
+	// color of nodata areas (transparent, fill, preserve)
+	colorTextFileContent := applyNoDataHandling(tpiRequest.Attributes.ColorTextFileContent, tpiRequest.Attributes.NoDataHandling, tpiRequest.Attributes.NoDataColor)
+
 	// build tpi for all existing tiles
-	for _, tile := range tiles {
-		tpi, err := generateTPIObjectForTile(tile, outputFormat, tpiRequest.Attributes.ColorTextFileContent, tpiRequest.Attributes.ColoringAlgorithm)
-		if err != nil {
-			slog.Warn("tpi request: error generating tpi object for tile", "error", err, "ID", tpiRequest.ID)
-			tpiResponse.Attributes.Error.Code = "8120"
-			tpiResponse.Attributes.Error.Title = "error generating tpi object for tile"
-			tpiResponse.Attributes.Error.Detail = err.Error()
-			buildTPIResponse(writer, http.StatusBadRequest, tpiResponse)
+	tpis, tileErrors, err := generateObjectsForTiles(request.Context(), tiles, func(tile TileMetadata) (TPI, error) {
+		return generateTPIObjectForTile(request.Context(), tile, outputFormat, tpiRequest.Attributes.GeoTIFFOptions, colorTextFileContent, tpiRequest.Attributes.ColoringAlgorithm, tpiRequest.Attributes.MaskWater)
+	})
+	if err != nil {
+		slog.WarnContext(request.Context(), "tpi request: error generating tpi object for tile", "error", err, "ID", tpiRequest.ID)
+		if errors.Is(err, context.Canceled) {
+			// client disconnected, processing aborted, no response required
+			return
+		}
+		if errors.Is(err, ErrServerBusy) {
+			tpiResponse.Attributes.Error = newErrorObject(EndpointTPI, ReasonServerBusy, err.Error())
+			writer.Header().Set("Retry-After", strconv.Itoa(getProgConfig().GDALJobQueue.RetryAfter))
+			buildTPIResponse(writer, request, http.StatusTooManyRequests, tpiResponse)
 			return
 		}
-		tpiResponse.Attributes.TPIs = append(tpiResponse.Attributes.TPIs, tpi)
+		tpiResponse.Attributes.Error = newErrorObject(EndpointTPI, ReasonGeneratingObject, err.Error())
+		buildTPIResponse(writer, request, http.StatusBadRequest, tpiResponse)
+		return
+	}
+	tpiResponse.Attributes.TPIs = tpis
+	tpiResponse.Attributes.TileErrors = tileErrors
+	if len(tileErrors) > 0 {
+		tpiResponse.Attributes.Warnings = append(tpiResponse.Attributes.Warnings,
+			fmt.Sprintf("%d of %d tiles could not be processed (see TileErrors)", len(tileErrors), len(tiles)))
+		slog.WarnContext(request.Context(), "tpi request: partial success, tiles could not be processed", "tile errors", tileErrors, "ID", tpiRequest.ID)
 	}
+	addUsage(request.Context(), 0, len(tpis))
 
 	// success response
 	tpiResponse.Attributes.IsError = false
-	buildTPIResponse(writer, http.StatusOK, tpiResponse)
+	if len(tileErrors) == 0 {
+		// partial responses are not cacheable
+		setETag(writer, etag)
+	}
+	buildTPIResponse(writer, request, http.StatusOK, tpiResponse)
 }
 
 /*
@@ -167,18 +256,6 @@ func verifyTPIRequestData(request *http.Request, tpiRequest TPIRequest) error {
 		return fmt.Errorf("unexpected or missing HTTP header field Content-Type, value = [%s], expected 'application/json'", contentType)
 	}
 
-	// verify HTTP header
-	accept := request.Header.Get("Accept")
-	isAcceptValid := true
-	switch {
-	case strings.HasPrefix(strings.ToLower(accept), "application/json"):
-	default:
-		isAcceptValid = false
-	}
-	if !isAcceptValid {
-		return fmt.Errorf("unexpected or missing HTTP header field Accept, value = [%s], expected 'application/json'", accept)
-	}
-
 	// verify Type
 	if tpiRequest.Type != TypeTPIRequest {
 		return fmt.Errorf("unexpected request Type [%v]", tpiRequest.Type)
@@ -228,77 +305,71 @@ func verifyTPIRequestData(request *http.Request, tpiRequest TPIRequest) error {
 		}
 	}
 
-	return nil
-}
+	// verify output format
+	switch strings.ToLower(tpiRequest.Attributes.OutputFormat) {
+	case "":
+	case "geotiff":
+	case "png":
+	default:
+		return errors.New("unsupported output format (not geotiff, png)")
+	}
 
-/*
-buildTPIResponse builds HTTP responses with specified status and body.
-It sets the Content-Type and Content-Length headers before writing the response body.
-This function is used to construct consistent HTTP responses throughout the application.
-*/
-func buildTPIResponse(writer http.ResponseWriter, httpStatus int, tpiResponse TPIResponse) {
-	// log limit length of body (e.g., the tpi objects as part of the body can be very large)
-	maxBodyLength := 1024
-
-	// CORS: allow requests from any origin
-	writer.Header().Set("Access-Control-Allow-Origin", "*")
-	// CORS: allowed methods
-	writer.Header().Set("Access-Control-Allow-Methods", "POST")
-	// CORS: allowed headers
-	writer.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
-	// marshal response
-	body, err := json.MarshalIndent(tpiResponse, "", "  ")
+	// verify GeoTIFF creation options
+	err = verifyGeoTIFFOptions(tpiRequest.Attributes.GeoTIFFOptions)
 	if err != nil {
-		slog.Error("error marshaling point response", "error", err, "body length", len(body),
-			fmt.Sprintf("body (limited to first %d bytes)", maxBodyLength), body[:maxBodyLength])
-
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
+		return err
 	}
 
-	// gzip response body
-	var bytesBuffer bytes.Buffer
-	gz := gzip.NewWriter(&bytesBuffer)
-
-	_, err = gz.Write(body)
+	// verify nodata handling
+	err = verifyNoDataHandling(tpiRequest.Attributes.NoDataHandling, tpiRequest.Attributes.NoDataColor)
 	if err != nil {
-		slog.Error("error [%v] at gz.Write()", "error", err)
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
+		return err
 	}
 
-	err = gz.Close()
+	// verify water masking
+	err = verifyMaskWater(tpiRequest.Attributes.MaskWater)
 	if err != nil {
-		slog.Error("error [%v] at gz.Close()", "error", err)
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
+		return err
 	}
 
-	// set headers
-	writer.Header().Set("Content-Encoding", "gzip")
-	writer.Header().Set("Content-Type", JSONAPIMediaType)
-	writer.WriteHeader(httpStatus)
+	return nil
+}
 
-	// send response
-	_, err = writer.Write(bytesBuffer.Bytes())
-	if err != nil {
-		slog.Error("error writing HTTP response body", "error", err, "body length", len(body),
-			fmt.Sprintf("body (limited to first %d bytes)", maxBodyLength), body[:maxBodyLength])
-	}
+/*
+buildTPIResponse builds HTTP responses with specified status and body.
+It sets the Content-Type and Content-Length headers before writing the response body.
+This function is used to construct consistent HTTP responses throughout the application.
+*/
+func buildTPIResponse(writer http.ResponseWriter, request *http.Request, httpStatus int, tpiResponse TPIResponse) {
+	// response metadata (versions, processing duration, cache hit)
+	tpiResponse.Meta = newResponseMeta(request.Context())
+
+	// skip compression if payload is already compressed (e.g. PNG)
+	skipCompression := len(tpiResponse.Attributes.TPIs) > 0 && isCompressedDataFormat(tpiResponse.Attributes.TPIs[0].DataFormat)
+
+	// encode (streaming) and send response
+	streamJSONResponse(writer, request, httpStatus, tpiResponse, skipCompression)
 }
 
 /*
 generateTPIObjectForTile builds tpi object for given tile index.
 */
-func generateTPIObjectForTile(tile TileMetadata, outputFormat string, colorTextFileContent []string, coloringAlgorithm string) (TPI, error) {
+func generateTPIObjectForTile(ctx context.Context, tile TileMetadata, outputFormat string, geotiffOptions GeoTIFFOptions, colorTextFileContent []string, coloringAlgorithm string, maskWater bool) (TPI, error) {
 	var tpi TPI
 	var boundingBox WGS84BoundingBox
 
-	// run operations in temp directory
-	tempDir, err := os.MkdirTemp("", "dtm-elevation-service-tpi-")
+	// lookup response cache
+	cacheKey := buildResponseCacheKey("tpi", tile.Index, tile.Actuality, outputFormat, geotiffOptions, colorTextFileContent, coloringAlgorithm, maskWater)
+	cached, found := responseCache.Get(cacheKey)
+	recordCacheLookup(ctx, found)
+	if found {
+		return cached.(TPI), nil
+	}
+
+	// run operations in temp directory (color text file) and in memory (/vsimem)
+	tempDir, err := createTempDirectory("tpi")
 	if err != nil {
-		return tpi, fmt.Errorf("error [%w] at os.MkdirTemp()", err)
+		return tpi, fmt.Errorf("error [%w] at createTempDirectory()", err)
 	}
 	defer func() {
 		_ = os.RemoveAll(tempDir)
@@ -311,80 +382,33 @@ func generateTPIObjectForTile(tile TileMetadata, outputFormat string, colorTextF
 		return tpi, fmt.Errorf("error [%w] creating 'color-text-file'", err)
 	}
 
-	inputGeoTIFF := tile.Path
-	tpiUTMGeoTIFF := filepath.Join(tempDir, tile.Index+".tpi.utm.tif")
-	tpiColorUTMGeoTIFF := filepath.Join(tempDir, tile.Index+".tpi.color.utm.tif")
-	tpiWebmercatorGeoTIFF := filepath.Join(tempDir, tile.Index+".tpi.webmercator.tif")
-	tpiColorWebmercatoPNG := filepath.Join(tempDir, tile.Index+".tpi.color.webmercator.png")
-
-	// 1. create native tpi with 'gdaldem tpi'
-	commandExitStatus, commandOutput, err := runCommand("gdaldem", []string{"TPI", inputGeoTIFF, tpiUTMGeoTIFF, "-compute_edges"})
+	// create colored tpi (GeoTIFF in UTM or PNG in Webmercator) with 'gdaldem TPI' and 'gdaldem color-relief'
+	data, err := dtm.GenerateProduct(ctx, tile.Path, dtm.ProductOptions{
+		Name:            "tpi",
+		Mode:            "TPI",
+		ModeSwitches:    []string{"-compute_edges"},
+		ColorTextFile:   colorTextFile,
+		NearestColor:    coloringAlgorithm == "rounding",
+		OutputFormat:    outputFormat,
+		CreationOptions: buildGeoTIFFCreationOptions(geotiffOptions),
+		PostProcess:     waterMasking(maskWater),
+	})
 	if err != nil {
-		return tpi, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
+		return tpi, fmt.Errorf("error [%w] at dtm.GenerateProduct()", err)
 	}
-	// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-	// fmt.Printf("commandOutput: %s\n", commandOutput)
 
-	var data []byte
-	switch strings.ToLower(outputFormat) {
-	case "geotiff":
-		// 2. colorize tpi with 'gdaldem color-relief'
-		options := []string{"color-relief", tpiUTMGeoTIFF, colorTextFile, tpiColorUTMGeoTIFF, "-alpha"}
-		if coloringAlgorithm == "rounding" {
-			options = append(options, "-nearest_color_entry")
-		}
-		commandExitStatus, commandOutput, err = runCommand("gdaldem", options)
-		if err != nil {
-			return tpi, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
-		}
-		// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-		// fmt.Printf("commandOutput: %s\n", commandOutput)
-
-		data, err = os.ReadFile(tpiColorUTMGeoTIFF)
-		if err != nil {
-			return tpi, fmt.Errorf("error [%w] at os.ReadFile()", err)
-		}
-
-	case "png":
-		// 2. convert UTM (EPSG:25832/EPSG:25833) to Webmercator (EPSG:3857) with 'gdalwarp'
-		commandExitStatus, commandOutput, err = runCommand("gdalwarp", []string{"-t_srs", "EPSG:3857", tpiUTMGeoTIFF, tpiWebmercatorGeoTIFF})
-		if err != nil {
-			return tpi, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
-		}
-		// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-		// fmt.Printf("commandOutput: %s\n", commandOutput)
-
-		// 3. colorize TPI with 'gdaldem color-relief' (creates PNG file)
-		options := []string{"color-relief", tpiWebmercatorGeoTIFF, colorTextFile, tpiColorWebmercatoPNG, "-alpha"}
-		if coloringAlgorithm == "rounding" {
-			options = append(options, "-nearest_color_entry")
-		}
-		commandExitStatus, commandOutput, err = runCommand("gdaldem", options)
-		if err != nil {
-			return tpi, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
-		}
-		// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-		// fmt.Printf("commandOutput: %s\n", commandOutput)
-
-		// 4. get bounding box (in wgs84) for webmercator tif (georeference of webmercator png )
+	// get bounding box (in wgs84) for webmercator png (georeference of webmercator png)
+	if strings.EqualFold(outputFormat, "png") {
 		boundingBox, err = calculateWGS84BoundingBox(tile)
 		if err != nil {
 			return tpi, fmt.Errorf("error [%w] at calculateWGS84BoundingBox(), file: %s", err, tile.Path)
 		}
-
-		// read result file
-		data, err = os.ReadFile(tpiColorWebmercatoPNG)
-		if err != nil {
-			return tpi, fmt.Errorf("error [%w] at os.ReadFile()", err)
-		}
-
-	default:
-		return tpi, fmt.Errorf("unsupported format [%s]", outputFormat)
 	}
 
 	// set TPI return structure
 	tpi.Data = data
 	tpi.DataFormat = outputFormat
+	tpi.Filename = buildObjectFilename(tile.Index, "tpi", "", tile.Actuality, outputFormat)
 	tpi.Actuality = tile.Actuality
 	tpi.Origin = tile.Source
 	tpi.TileIndex = tile.Index
@@ -394,11 +418,21 @@ func generateTPIObjectForTile(tile TileMetadata, outputFormat string, colorTextF
 	attribution := "unknown"
 	resource, err := getElevationResource(tile.Source)
 	if err != nil {
-		slog.Error("tpi request: error getting elevation resource", "error", err, "source", tile.Source)
+		slog.ErrorContext(ctx, "tpi request: error getting elevation resource", "error", err, "source", tile.Source)
 	} else {
 		attribution = resource.Attribution
 	}
 	tpi.Attribution = attribution
 
+	// get provenance of source files (delivery file name and checksum)
+	sourceFiles, err := getSourceFiles(tile)
+	if err != nil {
+		slog.ErrorContext(ctx, "tpi request: error getting source files", "error", err, "tile", tile.Index)
+	}
+	tpi.SourceFiles = sourceFiles
+
+	// add to response cache
+	responseCache.Add(cacheKey, tpi, len(tpi.Data))
+
 	return tpi, nil
 }
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/airbusgeo/godal"
+)
+
+// CachedTransform is a reusable coordinate transformation (not safe for concurrent use, therefore pooled).
+type CachedTransform struct {
+	*godal.Transform
+	sourceSRS  *godal.SpatialRef
+	targetSRS  *godal.SpatialRef
+	sourceEPSG int
+	targetEPSG int
+}
+
+// transformPools holds a pool of transformations for each (source, target) EPSG pair
+var (
+	transformPools      = make(map[[2]int]chan *CachedTransform)
+	transformPoolsMutex sync.Mutex
+)
+
+/*
+getTransformPool returns the pool for the given EPSG pair (created on first use).
+*/
+func getTransformPool(sourceEPSG, targetEPSG int) chan *CachedTransform {
+	transformPoolsMutex.Lock()
+	defer transformPoolsMutex.Unlock()
+
+	key := [2]int{sourceEPSG, targetEPSG}
+	pool, found := transformPools[key]
+	if !found {
+		pool = make(chan *CachedTransform, 2*runtime.NumCPU())
+		transformPools[key] = pool
+	}
+	return pool
+}
+
+/*
+acquireTransform returns a coordinate transformation from the pool, or creates a new one if the pool is empty.
+The transformation must be returned with releaseTransform().
+*/
+func acquireTransform(sourceEPSG, targetEPSG int) (*CachedTransform, error) {
+	select {
+	case cachedTransform := <-getTransformPool(sourceEPSG, targetEPSG):
+		return cachedTransform, nil
+	default:
+	}
+
+	sourceSRS, err := godal.NewSpatialRefFromEPSG(sourceEPSG)
+	if err != nil {
+		return nil, fmt.Errorf("error creating source SRS (EPSG:%d): %w", sourceEPSG, err)
+	}
+
+	targetSRS, err := godal.NewSpatialRefFromEPSG(targetEPSG)
+	if err != nil {
+		sourceSRS.Close()
+		return nil, fmt.Errorf("error creating target SRS (EPSG:%d): %w", targetEPSG, err)
+	}
+
+	transform, err := godal.NewTransform(sourceSRS, targetSRS)
+	if err != nil {
+		sourceSRS.Close()
+		targetSRS.Close()
+		return nil, fmt.Errorf("error creating coordinate transformation from EPSG:%d to EPSG:%d: %w", sourceEPSG, targetEPSG, err)
+	}
+
+	return &CachedTransform{Transform: transform, sourceSRS: sourceSRS, targetSRS: targetSRS,
+		sourceEPSG: sourceEPSG, targetEPSG: targetEPSG}, nil
+}
+
+/*
+releaseTransform returns a coordinate transformation to the pool (or destroys it if the pool is full).
+*/
+func releaseTransform(cachedTransform *CachedTransform) {
+	select {
+	case getTransformPool(cachedTransform.sourceEPSG, cachedTransform.targetEPSG) <- cachedTransform:
+	default:
+		cachedTransform.Transform.Close()
+		cachedTransform.sourceSRS.Close()
+		cachedTransform.targetSRS.Close()
+	}
+}
@@ -1,8 +1,7 @@
 package main
 
 import (
-	"bytes"
-	"compress/gzip"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,8 +10,11 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync/atomic"
+
+	"klaus/elevation/dtm-elevation-service/pkg/dtm"
 )
 
 /*
@@ -34,18 +36,14 @@ func triRequest(writer http.ResponseWriter, request *http.Request) {
 		// check specifically for the error returned by MaxBytesReader
 		var maxBytesErr *http.MaxBytesError
 		if errors.As(err, &maxBytesErr) {
-			slog.Warn("tri request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
-			triResponse.Attributes.Error.Code = "9000"
-			triResponse.Attributes.Error.Title = "request body too large"
-			triResponse.Attributes.Error.Detail = fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit)
-			buildTRIResponse(writer, http.StatusRequestEntityTooLarge, triResponse)
+			slog.WarnContext(request.Context(), "tri request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
+			triResponse.Attributes.Error = newErrorObject(EndpointTRI, ReasonRequestBodyTooLarge, fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit))
+			buildTRIResponse(writer, request, http.StatusRequestEntityTooLarge, triResponse)
 		} else {
 			// handle other read errors
-			slog.Warn("tri request: error reading request body", "error", err, "ID", "unknown")
-			triResponse.Attributes.Error.Code = "9020"
-			triResponse.Attributes.Error.Title = "error reading request body"
-			triResponse.Attributes.Error.Detail = err.Error()
-			buildTRIResponse(writer, http.StatusBadRequest, triResponse)
+			slog.WarnContext(request.Context(), "tri request: error reading request body", "error", err, "ID", "unknown")
+			triResponse.Attributes.Error = newErrorObject(EndpointTRI, ReasonReadingRequestBody, err.Error())
+			buildTRIResponse(writer, request, http.StatusBadRequest, triResponse)
 		}
 		return
 	}
@@ -54,14 +52,52 @@ func triRequest(writer http.ResponseWriter, request *http.Request) {
 	triRequest := TRIRequest{}
 	err = json.Unmarshal(bodyData, &triRequest)
 	if err != nil {
-		slog.Warn("tri request: error unmarshaling request body", "error", err, "ID", "unknown")
-		triResponse.Attributes.Error.Code = "9040"
-		triResponse.Attributes.Error.Title = "error unmarshaling request body"
-		triResponse.Attributes.Error.Detail = err.Error()
-		buildTRIResponse(writer, http.StatusBadRequest, triResponse)
+		slog.WarnContext(request.Context(), "tri request: error unmarshaling request body", "error", err, "ID", "unknown")
+		triResponse.Attributes.Error = newErrorObject(EndpointTRI, ReasonUnmarshalingRequestBody, err.Error())
+		buildTRIResponse(writer, request, http.StatusBadRequest, triResponse)
 		return
 	}
 
+	// resolve place name (geocoding) into lon/lat coordinates
+	if triRequest.Attributes.Place != "" {
+		place, err := geocodePlace(request.Context(), triRequest.Attributes.Place)
+		if err != nil {
+			slog.WarnContext(request.Context(), "tri request: error geocoding place", "error", err, "place", triRequest.Attributes.Place, "ID", triRequest.ID)
+			triResponse.ID = triRequest.ID
+			triResponse.Attributes.Place = triRequest.Attributes.Place
+			if errors.Is(err, ErrPlaceNotFound) {
+				triResponse.Attributes.Error = newErrorObject(EndpointTRI, ReasonPlaceNotFound, err.Error())
+				buildTRIResponse(writer, request, http.StatusNotFound, triResponse)
+				return
+			}
+			if errors.Is(err, ErrGeocoderUnavailable) {
+				triResponse.Attributes.Error = newErrorObject(EndpointTRI, ReasonGeocoderUnavailable, err.Error())
+				buildTRIResponse(writer, request, http.StatusServiceUnavailable, triResponse)
+				return
+			}
+			triResponse.Attributes.Error = newErrorObject(EndpointTRI, ReasonVerifyingRequestData, err.Error())
+			buildTRIResponse(writer, request, http.StatusBadRequest, triResponse)
+			return
+		}
+		triRequest.Attributes.Zone = 0
+		triRequest.Attributes.Longitude = place.Longitude
+		triRequest.Attributes.Latitude = place.Latitude
+		triResponse.Attributes.PlaceName = place.DisplayName
+	}
+
+	// named color table (stored server-side) instead of color text file content
+	if triRequest.Attributes.ColorTableName != "" {
+		triRequest.Attributes.ColorTextFileContent, err = resolveColorTable(request.Context(), triRequest.Attributes.ColorTableName, triRequest.Attributes.ColorTextFileContent)
+		if err != nil {
+			slog.WarnContext(request.Context(), "tri request: error resolving color table", "error", err, "name", triRequest.Attributes.ColorTableName, "ID", triRequest.ID)
+			triResponse.ID = triRequest.ID
+			triResponse.Attributes.ColorTableName = triRequest.Attributes.ColorTableName
+			triResponse.Attributes.Error = newErrorObject(EndpointTRI, ReasonVerifyingRequestData, err.Error())
+			buildTRIResponse(writer, request, http.StatusBadRequest, triResponse)
+			return
+		}
+	}
+
 	// copy request parameters into response
 	triResponse.ID = triRequest.ID
 	triResponse.Attributes.Zone = triRequest.Attributes.Zone
@@ -69,17 +105,21 @@ func triRequest(writer http.ResponseWriter, request *http.Request) {
 	triResponse.Attributes.Northing = triRequest.Attributes.Northing
 	triResponse.Attributes.Longitude = triRequest.Attributes.Longitude
 	triResponse.Attributes.Latitude = triRequest.Attributes.Latitude
+	triResponse.Attributes.Place = triRequest.Attributes.Place
+	triResponse.Attributes.OutputFormat = triRequest.Attributes.OutputFormat
 	triResponse.Attributes.ColorTextFileContent = triRequest.Attributes.ColorTextFileContent
+	triResponse.Attributes.ColorTableName = triRequest.Attributes.ColorTableName
 	triResponse.Attributes.ColoringAlgorithm = triRequest.Attributes.ColoringAlgorithm
+	if triRequest.Attributes.ColoringAlgorithm == "" {
+		triResponse.Attributes.Warnings = append(triResponse.Attributes.Warnings, "ColoringAlgorithm not set, default 'interpolation' applied")
+	}
 
 	// verify request data
 	err = verifyTRIRequestData(request, triRequest)
 	if err != nil {
-		slog.Warn("tri request: error verifying request data", "error", err, "ID", triRequest.ID)
-		triResponse.Attributes.Error.Code = "9060"
-		triResponse.Attributes.Error.Title = "error verifying request data"
-		triResponse.Attributes.Error.Detail = err.Error()
-		buildTRIResponse(writer, http.StatusBadRequest, triResponse)
+		slog.WarnContext(request.Context(), "tri request: error verifying request data", "error", err, "ID", triRequest.ID)
+		triResponse.Attributes.Error = newErrorObject(EndpointTRI, ReasonVerifyingRequestData, err.Error())
+		buildTRIResponse(writer, request, http.StatusBadRequest, triResponse)
 		return
 	}
 
@@ -102,12 +142,20 @@ func triRequest(writer http.ResponseWriter, request *http.Request) {
 		// get all tiles (metadata) for given UTM coordinates
 		tiles, err = getAllTilesUTM(zone, easting, northing)
 		if err != nil {
-			slog.Warn("tri request: error getting GeoTIFF tile for UTM coordinates", "error", err,
+			slog.WarnContext(request.Context(), "tri request: error getting GeoTIFF tile for UTM coordinates", "error", err,
 				"easting", easting, "northing", northing, "zone", zone, "ID", triRequest.ID)
-			triResponse.Attributes.Error.Code = "9080"
-			triResponse.Attributes.Error.Title = "getting GeoTIFF tile for UTM coordinates"
-			triResponse.Attributes.Error.Detail = err.Error()
-			buildTRIResponse(writer, http.StatusBadRequest, triResponse)
+			if errors.Is(err, ErrOutsideCoverage) {
+				triResponse.Attributes.Error = newErrorObject(EndpointTRI, ReasonOutsideCoverage, describeOutsideCoverage(err))
+				buildTRIResponse(writer, request, http.StatusNotFound, triResponse)
+				return
+			}
+			if errors.Is(err, ErrSourceUnavailable) {
+				triResponse.Attributes.Error = newErrorObject(EndpointTRI, ReasonSourceUnavailable, err.Error())
+				buildTRIResponse(writer, request, http.StatusServiceUnavailable, triResponse)
+				return
+			}
+			triResponse.Attributes.Error = newErrorObject(EndpointTRI, ReasonGettingTileUTM, err.Error())
+			buildTRIResponse(writer, request, http.StatusBadRequest, triResponse)
 			return
 		}
 	} else {
@@ -120,33 +168,74 @@ func triRequest(writer http.ResponseWriter, request *http.Request) {
 		tiles, err = getAllTilesLonLat(longitude, latitude)
 		if err != nil {
 			err = fmt.Errorf("error [%w] getting tile for coordinates lon: %.8f, lat: %.8f", err, longitude, latitude)
-			slog.Warn("tri request: error getting GeoTIFF tile for lon/lat coordinates", "error", err,
+			slog.WarnContext(request.Context(), "tri request: error getting GeoTIFF tile for lon/lat coordinates", "error", err,
 				"longitude", longitude, "latitude", latitude, "ID", triRequest.ID)
-			triResponse.Attributes.Error.Code = "9100"
-			triResponse.Attributes.Error.Title = "getting GeoTIFF tile for lon/lat coordinates"
-			triResponse.Attributes.Error.Detail = err.Error()
-			buildTRIResponse(writer, http.StatusBadRequest, triResponse)
+			if errors.Is(err, ErrOutsideCoverage) {
+				triResponse.Attributes.Error = newErrorObject(EndpointTRI, ReasonOutsideCoverage, describeOutsideCoverage(err))
+				buildTRIResponse(writer, request, http.StatusNotFound, triResponse)
+				return
+			}
+			if errors.Is(err, ErrSourceUnavailable) {
+				triResponse.Attributes.Error = newErrorObject(EndpointTRI, ReasonSourceUnavailableLonLat, err.Error())
+				buildTRIResponse(writer, request, http.StatusServiceUnavailable, triResponse)
+				return
+			}
+			triResponse.Attributes.Error = newErrorObject(EndpointTRI, ReasonGettingTileLonLat, err.Error())
+			buildTRIResponse(writer, request, http.StatusBadRequest, triResponse)
 			return
 		}
 	}
 
+	// explicit output format (overrides default for type of coordinates)
+	if triRequest.Attributes.OutputFormat != "" {
+		outputFormat = strings.ToLower(triRequest.Attributes.OutputFormat)
+	}
+
+	// conditional request: unchanged response (same parameters, same tiles) needs no processing
+	etag := buildETag("tri", triRequest, tiles)
+	if checkNotModified(writer, request, etag) {
+		return
+	}
+
+	// color of nodata areas (transparent, fill, preserve)
+	colorTextFileContent := applyNoDataHandling(triRequest.Attributes.ColorTextFileContent, triRequest.Attributes.NoDataHandling, triRequest.Attributes.NoDataColor)
+
 	// build tri for all existing tiles
-	for _, tile := range tiles {
-		tri, err := generateTRIObjectForTile(tile, outputFormat, triRequest.Attributes.ColorTextFileContent, triRequest.Attributes.ColoringAlgorithm)
-		if err != nil {
-			slog.Warn("tri request: error generating tri object for tile", "error", err, "ID", triRequest.ID)
-			triResponse.Attributes.Error.Code = "9120"
-			triResponse.Attributes.Error.Title = "error generating tri object for tile"
-			triResponse.Attributes.Error.Detail = err.Error()
-			buildTRIResponse(writer, http.StatusBadRequest, triResponse)
+	tris, tileErrors, err := generateObjectsForTiles(request.Context(), tiles, func(tile TileMetadata) (TRI, error) {
+		return generateTRIObjectForTile(request.Context(), tile, outputFormat, triRequest.Attributes.GeoTIFFOptions, colorTextFileContent, triRequest.Attributes.ColoringAlgorithm)
+	})
+	if err != nil {
+		slog.WarnContext(request.Context(), "tri request: error generating tri object for tile", "error", err, "ID", triRequest.ID)
+		if errors.Is(err, context.Canceled) {
+			// client disconnected, processing aborted, no response required
+			return
+		}
+		if errors.Is(err, ErrServerBusy) {
+			triResponse.Attributes.Error = newErrorObject(EndpointTRI, ReasonServerBusy, err.Error())
+			writer.Header().Set("Retry-After", strconv.Itoa(getProgConfig().GDALJobQueue.RetryAfter))
+			buildTRIResponse(writer, request, http.StatusTooManyRequests, triResponse)
 			return
 		}
-		triResponse.Attributes.TRIs = append(triResponse.Attributes.TRIs, tri)
+		triResponse.Attributes.Error = newErrorObject(EndpointTRI, ReasonGeneratingObject, err.Error())
+		buildTRIResponse(writer, request, http.StatusBadRequest, triResponse)
+		return
+	}
+	triResponse.Attributes.TRIs = tris
+	triResponse.Attributes.TileErrors = tileErrors
+	if len(tileErrors) > 0 {
+		triResponse.Attributes.Warnings = append(triResponse.Attributes.Warnings,
+			fmt.Sprintf("%d of %d tiles could not be processed (see TileErrors)", len(tileErrors), len(tiles)))
+		slog.WarnContext(request.Context(), "tri request: partial success, tiles could not be processed", "tile errors", tileErrors, "ID", triRequest.ID)
 	}
+	addUsage(request.Context(), 0, len(tris))
 
 	// success response
 	triResponse.Attributes.IsError = false
-	buildTRIResponse(writer, http.StatusOK, triResponse)
+	if len(tileErrors) == 0 {
+		// partial responses are not cacheable
+		setETag(writer, etag)
+	}
+	buildTRIResponse(writer, request, http.StatusOK, triResponse)
 }
 
 /*
@@ -167,18 +256,6 @@ func verifyTRIRequestData(request *http.Request, triRequest TRIRequest) error {
 		return fmt.Errorf("unexpected or missing HTTP header field Content-Type, value = [%s], expected 'application/json'", contentType)
 	}
 
-	// verify HTTP header
-	accept := request.Header.Get("Accept")
-	isAcceptValid := true
-	switch {
-	case strings.HasPrefix(strings.ToLower(accept), "application/json"):
-	default:
-		isAcceptValid = false
-	}
-	if !isAcceptValid {
-		return fmt.Errorf("unexpected or missing HTTP header field Accept, value = [%s], expected 'application/json'", accept)
-	}
-
 	// verify Type
 	if triRequest.Type != TypeTRIRequest {
 		return fmt.Errorf("unexpected request Type [%v]", triRequest.Type)
@@ -228,6 +305,27 @@ func verifyTRIRequestData(request *http.Request, triRequest TRIRequest) error {
 		}
 	}
 
+	// verify output format
+	switch strings.ToLower(triRequest.Attributes.OutputFormat) {
+	case "":
+	case "geotiff":
+	case "png":
+	default:
+		return errors.New("unsupported output format (not geotiff, png)")
+	}
+
+	// verify GeoTIFF creation options
+	err = verifyGeoTIFFOptions(triRequest.Attributes.GeoTIFFOptions)
+	if err != nil {
+		return err
+	}
+
+	// verify nodata handling
+	err = verifyNoDataHandling(triRequest.Attributes.NoDataHandling, triRequest.Attributes.NoDataColor)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -236,69 +334,36 @@ buildTRIResponse builds HTTP responses with specified status and body.
 It sets the Content-Type and Content-Length headers before writing the response body.
 This function is used to construct consistent HTTP responses throughout the application.
 */
-func buildTRIResponse(writer http.ResponseWriter, httpStatus int, triResponse TRIResponse) {
-	// log limit length of body (e.g., the tri objects as part of the body can be very large)
-	maxBodyLength := 1024
-
-	// CORS: allow requests from any origin
-	writer.Header().Set("Access-Control-Allow-Origin", "*")
-	// CORS: allowed methods
-	writer.Header().Set("Access-Control-Allow-Methods", "POST")
-	// CORS: allowed headers
-	writer.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
-	// marshal response
-	body, err := json.MarshalIndent(triResponse, "", "  ")
-	if err != nil {
-		slog.Error("error marshaling point response", "error", err, "body length", len(body),
-			fmt.Sprintf("body (limited to first %d bytes)", maxBodyLength), body[:maxBodyLength])
-
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
+func buildTRIResponse(writer http.ResponseWriter, request *http.Request, httpStatus int, triResponse TRIResponse) {
+	// response metadata (versions, processing duration, cache hit)
+	triResponse.Meta = newResponseMeta(request.Context())
 
-	// gzip response body
-	var bytesBuffer bytes.Buffer
-	gz := gzip.NewWriter(&bytesBuffer)
+	// skip compression if payload is already compressed (e.g. PNG)
+	skipCompression := len(triResponse.Attributes.TRIs) > 0 && isCompressedDataFormat(triResponse.Attributes.TRIs[0].DataFormat)
 
-	_, err = gz.Write(body)
-	if err != nil {
-		slog.Error("error [%v] at gz.Write()", "error", err)
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-
-	err = gz.Close()
-	if err != nil {
-		slog.Error("error [%v] at gz.Close()", "error", err)
-		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-
-	// set headers
-	writer.Header().Set("Content-Encoding", "gzip")
-	writer.Header().Set("Content-Type", JSONAPIMediaType)
-	writer.WriteHeader(httpStatus)
-
-	// send response
-	_, err = writer.Write(bytesBuffer.Bytes())
-	if err != nil {
-		slog.Error("error writing HTTP response body", "error", err, "body length", len(body),
-			fmt.Sprintf("body (limited to first %d bytes)", maxBodyLength), body[:maxBodyLength])
-	}
+	// encode (streaming) and send response
+	streamJSONResponse(writer, request, httpStatus, triResponse, skipCompression)
 }
 
 /*
 generateTRIObjectForTile builds tri object for given tile index.
 */
-func generateTRIObjectForTile(tile TileMetadata, outputFormat string, colorTextFileContent []string, coloringAlgorithm string) (TRI, error) {
+func generateTRIObjectForTile(ctx context.Context, tile TileMetadata, outputFormat string, geotiffOptions GeoTIFFOptions, colorTextFileContent []string, coloringAlgorithm string) (TRI, error) {
 	var tri TRI
 	var boundingBox WGS84BoundingBox
 
-	// run operations in temp directory
-	tempDir, err := os.MkdirTemp("", "dtm-elevation-service-tri-")
+	// lookup response cache
+	cacheKey := buildResponseCacheKey("tri", tile.Index, tile.Actuality, outputFormat, geotiffOptions, colorTextFileContent, coloringAlgorithm)
+	cached, found := responseCache.Get(cacheKey)
+	recordCacheLookup(ctx, found)
+	if found {
+		return cached.(TRI), nil
+	}
+
+	// run operations in temp directory (color text file) and in memory (/vsimem)
+	tempDir, err := createTempDirectory("tri")
 	if err != nil {
-		return tri, fmt.Errorf("error [%w] at os.MkdirTemp()", err)
+		return tri, fmt.Errorf("error [%w] at createTempDirectory()", err)
 	}
 	defer func() {
 		_ = os.RemoveAll(tempDir)
@@ -311,84 +376,33 @@ func generateTRIObjectForTile(tile TileMetadata, outputFormat string, colorTextF
 		return tri, fmt.Errorf("error [%w] creating 'color-text-file'", err)
 	}
 
-	inputGeoTIFF := tile.Path
-	triUTMGeoTIFF := filepath.Join(tempDir, tile.Index+".tri.utm.tif")
-	triColorUTMGeoTIFF := filepath.Join(tempDir, tile.Index+".tri.color.utm.tif")
-	triWebmercatorGeoTIFF := filepath.Join(tempDir, tile.Index+".tri.webmercator.tif")
-	triColorWebmercatoPNG := filepath.Join(tempDir, tile.Index+".tri.color.webmercator.png")
-
-	// 1. create native TRI with 'gdaldem TRI'
+	// create colored tri (GeoTIFF in UTM or PNG in Webmercator) with 'gdaldem TRI' and 'gdaldem color-relief'
 	// e.g. gdaldem TRI 602_5251.tif 602_5251_tri.utm.tif -alg Riley -compute_edges
-	commandExitStatus, commandOutput, err := runCommand("gdaldem", []string{"TRI", inputGeoTIFF, triUTMGeoTIFF, "-alg", "Riley", "-compute_edges"})
+	data, err := dtm.GenerateProduct(ctx, tile.Path, dtm.ProductOptions{
+		Name:            "tri",
+		Mode:            "TRI",
+		ModeSwitches:    []string{"-alg", "Riley", "-compute_edges"},
+		ColorTextFile:   colorTextFile,
+		NearestColor:    coloringAlgorithm == "rounding",
+		OutputFormat:    outputFormat,
+		CreationOptions: buildGeoTIFFCreationOptions(geotiffOptions),
+	})
 	if err != nil {
-		return tri, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
+		return tri, fmt.Errorf("error [%w] at dtm.GenerateProduct()", err)
 	}
-	// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-	// fmt.Printf("commandOutput: %s\n", commandOutput)
-
-	var data []byte
-	switch strings.ToLower(outputFormat) {
-	case "geotiff":
-		// 2. colorize tri with 'gdaldem color-relief'
-		// e.g. gdaldem color-relief 602_5251_tri.utm.tif tri-colors.txt 602_5251_tri.utm.png -alpha
-		options := []string{"color-relief", triUTMGeoTIFF, colorTextFile, triColorUTMGeoTIFF, "-alpha"}
-		if coloringAlgorithm == "rounding" {
-			options = append(options, "-nearest_color_entry")
-		}
-		commandExitStatus, commandOutput, err = runCommand("gdaldem", options)
-		if err != nil {
-			return tri, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
-		}
-		// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-		// fmt.Printf("commandOutput: %s\n", commandOutput)
-
-		data, err = os.ReadFile(triColorUTMGeoTIFF)
-		if err != nil {
-			return tri, fmt.Errorf("error [%w] at os.ReadFile()", err)
-		}
 
-	case "png":
-		// 2. convert UTM (EPSG:25832/EPSG:25833) to Webmercator (EPSG:3857) with 'gdalwarp'
-		// e.g. gdalwarp -t_srs EPSG:3857 602_5251_tri.utm.tif 602_5251_tri.webmercator.tif
-		commandExitStatus, commandOutput, err = runCommand("gdalwarp", []string{"-t_srs", "EPSG:3857", triUTMGeoTIFF, triWebmercatorGeoTIFF})
-		if err != nil {
-			return tri, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
-		}
-		// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-		// fmt.Printf("commandOutput: %s\n", commandOutput)
-
-		// 3. colorize tri with 'gdaldem color-relief' (creates PNG file)
-		// e.g. gdaldem color-relief 602_5251_tri.webmercator.tif tri-colors.txt 602_5251_tri.webmercator.png -alpha
-		options := []string{"color-relief", triWebmercatorGeoTIFF, colorTextFile, triColorWebmercatoPNG, "-alpha"}
-		if coloringAlgorithm == "rounding" {
-			options = append(options, "-nearest_color_entry")
-		}
-		commandExitStatus, commandOutput, err = runCommand("gdaldem", options)
-		if err != nil {
-			return tri, fmt.Errorf("error [%w: %d - %s] at runCommand()", err, commandExitStatus, commandOutput)
-		}
-		// fmt.Printf("commandExitStatus: %d\n", commandExitStatus)
-		// fmt.Printf("commandOutput: %s\n", commandOutput)
-
-		// 4. get bounding box (in wgs84) for webmercator tif (georeference of webmercator png )
+	// get bounding box (in wgs84) for webmercator png (georeference of webmercator png)
+	if strings.EqualFold(outputFormat, "png") {
 		boundingBox, err = calculateWGS84BoundingBox(tile)
 		if err != nil {
 			return tri, fmt.Errorf("error [%w] at calculateWGS84BoundingBox(), file: %s", err, tile.Path)
 		}
-
-		// read result file
-		data, err = os.ReadFile(triColorWebmercatoPNG)
-		if err != nil {
-			return tri, fmt.Errorf("error [%w] at os.ReadFile()", err)
-		}
-
-	default:
-		return tri, fmt.Errorf("unsupported format [%s]", outputFormat)
 	}
 
 	// set contour return structure
 	tri.Data = data
 	tri.DataFormat = outputFormat
+	tri.Filename = buildObjectFilename(tile.Index, "tri", "", tile.Actuality, outputFormat)
 	tri.Actuality = tile.Actuality
 	tri.Origin = tile.Source
 	tri.TileIndex = tile.Index
@@ -398,11 +412,21 @@ func generateTRIObjectForTile(tile TileMetadata, outputFormat string, colorTextF
 	attribution := "unknown"
 	resource, err := getElevationResource(tile.Source)
 	if err != nil {
-		slog.Error("tri request: error getting elevation resource", "error", err, "source", tile.Source)
+		slog.ErrorContext(ctx, "tri request: error getting elevation resource", "error", err, "source", tile.Source)
 	} else {
 		attribution = resource.Attribution
 	}
 	tri.Attribution = attribution
 
+	// get provenance of source files (delivery file name and checksum)
+	sourceFiles, err := getSourceFiles(tile)
+	if err != nil {
+		slog.ErrorContext(ctx, "tri request: error getting source files", "error", err, "tile", tile.Index)
+	}
+	tri.SourceFiles = sourceFiles
+
+	// add to response cache
+	responseCache.Add(cacheKey, tri, len(tri.Data))
+
 	return tri, nil
 }
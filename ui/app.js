@@ -0,0 +1,176 @@
+// DTM elevation service: interactive web UI (demos, manual QA of tile deliveries)
+"use strict";
+
+const map = L.map("map").setView([51.16, 10.45], 6);
+L.tileLayer("https://tile.openstreetmap.org/{z}/{x}/{y}.png", {
+  maxZoom: 19,
+  attribution: '&copy; <a href="https://www.openstreetmap.org/copyright">OpenStreetMap</a>',
+}).addTo(map);
+
+const overlays = L.layerGroup().addTo(map);
+const result = document.getElementById("result");
+const profileCanvas = document.getElementById("profile");
+let profileStart = null;
+
+function showResult(text, isError) {
+  result.textContent = text;
+  result.className = isError ? "error" : "";
+}
+
+// post request to API endpoint (same origin), returns response object
+async function postRequest(endpoint, body) {
+  const headers = { "Content-Type": "application/json", Accept: "application/json" };
+  const token = document.getElementById("token").value.trim();
+  if (token !== "") {
+    headers.Authorization = "Bearer " + token;
+  }
+  const response = await fetch(endpoint, { method: "POST", headers: headers, body: JSON.stringify(body) });
+  const data = await response.json();
+  if (data.Attributes && data.Attributes.IsError) {
+    const error = data.Attributes.Error;
+    throw new Error(error.Code + ": " + error.Title + " (" + error.Detail + ")");
+  }
+  return data;
+}
+
+function base64ToBlob(data, type) {
+  const binary = atob(data);
+  const bytes = new Uint8Array(binary.length);
+  for (let i = 0; i < binary.length; i++) {
+    bytes[i] = binary.charCodeAt(i);
+  }
+  return new Blob([bytes], { type: type });
+}
+
+async function queryPoint(latlng) {
+  const data = await postRequest("/v1/point", {
+    Type: "PointRequest",
+    ID: "ui",
+    Attributes: { Longitude: latlng.lng, Latitude: latlng.lat },
+  });
+  const a = data.Attributes;
+  L.marker(latlng).addTo(overlays).bindPopup(a.Elevation.toFixed(2) + " m").openPopup();
+  showResult(
+    "Höhe: " + a.Elevation.toFixed(2) + " m\n" +
+    "Kachel: " + a.TileIndex + "\n" +
+    "Quelle: " + a.Origin + " (" + a.Actuality + ")\n" +
+    a.Attribution
+  );
+}
+
+async function queryProfile(latlng) {
+  if (profileStart === null) {
+    profileStart = latlng;
+    L.circleMarker(latlng, { radius: 5 }).addTo(overlays);
+    showResult("Endpunkt des Profils anklicken ...");
+    return;
+  }
+  const start = profileStart;
+  profileStart = null;
+  L.polyline([start, latlng], { color: "red" }).addTo(overlays);
+  const data = await postRequest("/v1/elevationprofile", {
+    type: "ElevationProfileRequest",
+    id: "ui",
+    Attributes: {
+      PointA: { Longitude: start.lng, Latitude: start.lat },
+      PointB: { Longitude: latlng.lng, Latitude: latlng.lat },
+      MaxTotalProfilePoints: 300,
+      MinStepSize: 1.0,
+    },
+  });
+  const profile = data.Attributes.Profile || [];
+  drawProfile(profile);
+  const elevations = profile.map((p) => p.Elevation);
+  showResult(
+    "Profilpunkte: " + profile.length + "\n" +
+    "Länge: " + (profile.length > 0 ? profile[profile.length - 1].Distance.toFixed(0) : 0) + " m\n" +
+    "Min/Max: " + Math.min(...elevations).toFixed(1) + " / " + Math.max(...elevations).toFixed(1) + " m"
+  );
+}
+
+function drawProfile(profile) {
+  const ctx = profileCanvas.getContext("2d");
+  profileCanvas.hidden = profile.length < 2;
+  ctx.clearRect(0, 0, profileCanvas.width, profileCanvas.height);
+  if (profile.length < 2) {
+    return;
+  }
+  const minElevation = Math.min(...profile.map((p) => p.Elevation));
+  const maxElevation = Math.max(...profile.map((p) => p.Elevation));
+  const length = profile[profile.length - 1].Distance || 1;
+  const range = maxElevation - minElevation || 1;
+  ctx.beginPath();
+  profile.forEach((p, i) => {
+    const x = (p.Distance / length) * (profileCanvas.width - 1);
+    const y = profileCanvas.height - 1 - ((p.Elevation - minElevation) / range) * (profileCanvas.height - 1);
+    if (i === 0) {
+      ctx.moveTo(x, y);
+    } else {
+      ctx.lineTo(x, y);
+    }
+  });
+  ctx.strokeStyle = "#b00020";
+  ctx.stroke();
+}
+
+async function queryHillshade(latlng) {
+  const data = await postRequest("/v1/hillshade", {
+    Type: "HillshadeRequest",
+    ID: "ui",
+    Attributes: {
+      Longitude: latlng.lng,
+      Latitude: latlng.lat,
+      GradientAlgorithm: "Horn",
+      VerticalExaggeration: 1.0,
+      AzimuthOfLight: 315,
+      AltitudeOfLight: 45,
+      ShadingVariant: document.getElementById("shading").value,
+      OutputFormat: "png",
+    },
+  });
+  for (const hillshade of data.Attributes.Hillshades) {
+    const box = hillshade.BoundingBox;
+    const url = URL.createObjectURL(base64ToBlob(hillshade.Data, "image/png"));
+    L.imageOverlay(url, [[box.MinLat, box.MinLon], [box.MaxLat, box.MaxLon]], { opacity: 0.7 }).addTo(overlays);
+  }
+  showResult("Schummerung: " + data.Attributes.Hillshades.map((h) => h.TileIndex + " (" + h.Origin + ", " + h.Actuality + ")").join("\n"));
+}
+
+async function queryContours(latlng) {
+  const data = await postRequest("/v1/contours", {
+    Type: "ContoursRequest",
+    ID: "ui",
+    Attributes: {
+      Longitude: latlng.lng,
+      Latitude: latlng.lat,
+      Equidistance: parseFloat(document.getElementById("equidistance").value),
+    },
+  });
+  for (const contour of data.Attributes.Contours) {
+    const geojson = JSON.parse(new TextDecoder().decode(Uint8Array.from(atob(contour.Data), (c) => c.charCodeAt(0))));
+    L.geoJSON(geojson, { style: { color: "#8b4513", weight: 1 } }).addTo(overlays);
+  }
+  showResult("Höhenlinien: " + data.Attributes.Contours.map((c) => c.TileIndex + " (" + c.Origin + ", " + c.Actuality + ")").join("\n"));
+}
+
+const handlers = { point: queryPoint, profile: queryProfile, hillshade: queryHillshade, contours: queryContours };
+
+map.on("click", async (event) => {
+  const mode = document.querySelector('input[name="mode"]:checked').value;
+  if (mode !== "profile") {
+    profileStart = null;
+  }
+  showResult("Anfrage läuft ...");
+  try {
+    await handlers[mode](event.latlng);
+  } catch (error) {
+    showResult(error.message, true);
+  }
+});
+
+document.getElementById("clear").addEventListener("click", () => {
+  overlays.clearLayers();
+  profileStart = null;
+  profileCanvas.hidden = true;
+  showResult("Auf die Karte klicken ...");
+});
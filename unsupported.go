@@ -4,18 +4,137 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
+	"strings"
 )
 
+// serviceMux is the request multiplexer of the public listener (source for the allowed methods of a route)
+var serviceMux *http.ServeMux
+
+// candidate methods for the allowed methods of a route
+var routeMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodOptions,
+}
+
+// media types produced by routes (default: application/json)
+var producedMediaTypes = map[string][]string{
+	"/v1/point": {"application/json", "text/plain"},
+}
+
+/*
+getAllowedMethods returns the methods registered for the path of the request (empty if the route is unknown).
+*/
+func getAllowedMethods(request *http.Request) []string {
+	if serviceMux == nil {
+		return nil
+	}
+	allowedMethods := []string{}
+	for _, method := range routeMethods {
+		probe := request.Clone(request.Context())
+		probe.Method = method
+		_, pattern := serviceMux.Handler(probe)
+		if pattern != "" && pattern != "/" {
+			allowedMethods = append(allowedMethods, method)
+		}
+	}
+	return allowedMethods
+}
+
 /*
 unsupportedRequest handles 'unsupported' requests from clients.
-It sends a "400 Bad Request" error message for unexpected HTTP requests.
-The function logs a warning message and writes an error message to the response.
+It sends a "405 Method Not Allowed" (with 'Allow' header) for known routes requested with an unsupported
+method and a "404 Not Found" for unknown routes. The function logs a warning message and writes an error
+message to the response.
 */
-func unsupportedRequest(writer http.ResponseWriter, _ *http.Request) {
+func unsupportedRequest(writer http.ResponseWriter, request *http.Request) {
+	allowedMethods := getAllowedMethods(request)
+
 	// prepare response
 	writer.Header().Set("Content-Type", TextPlainMediaType)
-	writer.WriteHeader(http.StatusBadRequest)
-	errorMessage := "unsupported http request (e.g. route or method)"
-	slog.Warn(errorMessage)
+	var errorMessage string
+	if len(allowedMethods) > 0 {
+		writer.Header().Set("Allow", strings.Join(allowedMethods, ", "))
+		writer.WriteHeader(http.StatusMethodNotAllowed)
+		errorMessage = fmt.Sprintf("unsupported http method [%s], allowed methods: %s", request.Method, strings.Join(allowedMethods, ", "))
+	} else {
+		writer.WriteHeader(http.StatusNotFound)
+		errorMessage = "unsupported http request (unknown route)"
+	}
+	slog.WarnContext(request.Context(), errorMessage)
 	fmt.Fprint(writer, errorMessage)
 }
+
+/*
+isMediaTypeAcceptable checks if one of the media types satisfies the Accept header (media ranges incl.
+wildcards, quality value 0 excludes a media range). A missing Accept header accepts any media type.
+*/
+func isMediaTypeAcceptable(accept string, mediaTypes []string) bool {
+	if strings.TrimSpace(accept) == "" {
+		return true
+	}
+	for mediaRange := range strings.SplitSeq(accept, ",") {
+		parameters := strings.Split(mediaRange, ";")
+		acceptedType := strings.ToLower(strings.TrimSpace(parameters[0]))
+		excluded := slices.ContainsFunc(parameters[1:], func(parameter string) bool {
+			name, value, _ := strings.Cut(strings.TrimSpace(parameter), "=")
+			return strings.EqualFold(strings.TrimSpace(name), "q") && strings.Trim(strings.TrimSpace(value), "0.") == ""
+		})
+		if excluded {
+			continue
+		}
+		for _, mediaType := range mediaTypes {
+			mainType, _, _ := strings.Cut(mediaType, "/")
+			if acceptedType == "*/*" || acceptedType == mediaType || acceptedType == mainType+"/*" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+/*
+negotiationMiddleware rejects requests to known routes with "406 Not Acceptable" if the Accept header
+cannot be satisfied by the media types produced by the route.
+*/
+func negotiationMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method == http.MethodOptions || serviceMux == nil {
+			next.ServeHTTP(writer, request)
+			return
+		}
+		_, pattern := serviceMux.Handler(request)
+		if pattern == "" || pattern == "/" || strings.HasPrefix(pattern, "GET /ui") {
+			next.ServeHTTP(writer, request)
+			return
+		}
+
+		mediaTypes, found := producedMediaTypes[request.URL.Path]
+		switch {
+		case found:
+		case pattern == "GET /v1/jobs/{id}/events":
+			mediaTypes = []string{"text/event-stream", "application/json"}
+		case strings.HasPrefix(request.URL.Path, "/v2/"):
+			mediaTypes = []string{JSONAPIV2MediaType, "application/json"}
+		default:
+			mediaTypes = []string{"application/json"}
+		}
+		accept := request.Header.Get("Accept")
+		if !isMediaTypeAcceptable(accept, mediaTypes) {
+			// client provided Accept header is only logged, not reflected in response
+			availableMediaTypes := strings.Join(mediaTypes, ", ")
+			slog.WarnContext(request.Context(), "not acceptable", "accept", accept, "available media types", availableMediaTypes)
+			writer.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			writer.Header().Set("X-Content-Type-Options", "nosniff")
+			writer.WriteHeader(http.StatusNotAcceptable)
+			fmt.Fprintf(writer, "not acceptable: Accept header cannot be satisfied, available media types: %s", availableMediaTypes)
+			return
+		}
+		next.ServeHTTP(writer, request)
+	})
+}
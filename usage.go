@@ -0,0 +1,346 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// TypeUsageReportResponse is the type of the usage report response
+const TypeUsageReportResponse = "UsageReportResponse"
+
+// anonymousTenant is the tenant of requests without authentication
+const anonymousTenant = "anonymous"
+
+// UsageCounters represents the usage of an endpoint by a tenant.
+type UsageCounters struct {
+	Requests           uint64
+	SuccessfulRequests uint64
+	GPXPoints          uint64
+	RasterTiles        uint64
+}
+
+// UsageMonth represents the usage of all tenants in a month (tenant -> endpoint -> counters).
+type UsageMonth struct {
+	Month   string // YYYY-MM
+	Tenants map[string]map[string]*UsageCounters
+}
+
+// UsageAccounting aggregates the usage per tenant and month and persists it in the accounting directory.
+type UsageAccounting struct {
+	mutex   sync.Mutex
+	current *UsageMonth
+	dirty   bool
+}
+
+// usageAccounting is the global usage accounting
+var usageAccounting = &UsageAccounting{}
+
+// requestUsage represents the usage (billable units) of a single request.
+type requestUsage struct {
+	mutex       sync.Mutex
+	gpxPoints   uint64
+	rasterTiles uint64
+}
+
+type tenantKey struct{}
+type requestUsageKey struct{}
+
+// regular expression for month parameter (YYYY-MM)
+var usageMonthRegex = regexp.MustCompile(`^\d{4}-(0[1-9]|1[0-2])$`)
+
+/*
+withTenant returns a copy of the context with the tenant (e.g. subject of the JWT).
+*/
+func withTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+/*
+getTenant returns the tenant of the request context (anonymous if not authenticated).
+*/
+func getTenant(ctx context.Context) string {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	if !ok || tenant == "" {
+		return anonymousTenant
+	}
+	return tenant
+}
+
+/*
+addUsage adds billable units (GPX points, generated raster tiles) to the usage of the current request.
+*/
+func addUsage(ctx context.Context, gpxPoints int, rasterTiles int) {
+	usage, ok := ctx.Value(requestUsageKey{}).(*requestUsage)
+	if !ok {
+		return
+	}
+	usage.mutex.Lock()
+	usage.gpxPoints += uint64(gpxPoints)
+	usage.rasterTiles += uint64(rasterTiles)
+	usage.mutex.Unlock()
+}
+
+/*
+usageMiddleware records the usage (requests, GPX points, raster tiles) of all API endpoints per tenant.
+*/
+func usageMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if !getProgConfig().Accounting.Enabled || !isAccountedEndpoint(request.URL.Path) || request.Method == http.MethodOptions {
+			next.ServeHTTP(writer, request)
+			return
+		}
+
+		usage := &requestUsage{}
+		recorder := &responseRecorder{ResponseWriter: writer}
+		next.ServeHTTP(recorder, request.WithContext(context.WithValue(request.Context(), requestUsageKey{}, usage)))
+
+		usage.mutex.Lock()
+		defer usage.mutex.Unlock()
+		successful := recorder.status == 0 || (recorder.status >= 200 && recorder.status < 400)
+		usageAccounting.record(getTenant(request.Context()), request.URL.Path, successful, usage.gpxPoints, usage.rasterTiles, time.Now())
+	})
+}
+
+/*
+isAccountedEndpoint checks if the path is an API endpoint subject to accounting.
+*/
+func isAccountedEndpoint(path string) bool {
+	return slices.ContainsFunc(openAPIEndpoints, func(endpoint OpenAPIEndpoint) bool {
+		return endpoint.Path == path
+	}) || slices.ContainsFunc(v2Endpoints, func(endpoint V2Endpoint) bool {
+		return endpoint.Path == path
+	}) || slices.ContainsFunc(jobOnlyEndpoints, func(endpoint V2Endpoint) bool {
+		return endpoint.Endpoint.Path == path
+	})
+}
+
+/*
+record adds the usage of a request to the usage of the current month. At the turn of the month, the
+usage of the previous month is persisted and a new month begins.
+*/
+func (u *UsageAccounting) record(tenant string, endpoint string, successful bool, gpxPoints uint64, rasterTiles uint64, now time.Time) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	month := now.UTC().Format("2006-01")
+	if u.current == nil || u.current.Month != month {
+		u.switchMonth(month)
+	}
+
+	endpoints, found := u.current.Tenants[tenant]
+	if !found {
+		endpoints = make(map[string]*UsageCounters)
+		u.current.Tenants[tenant] = endpoints
+	}
+	counters, found := endpoints[endpoint]
+	if !found {
+		counters = &UsageCounters{}
+		endpoints[endpoint] = counters
+	}
+	counters.Requests++
+	if successful {
+		counters.SuccessfulRequests++
+		counters.GPXPoints += gpxPoints
+		counters.RasterTiles += rasterTiles
+	}
+	u.dirty = true
+}
+
+/*
+switchMonth persists the usage of the current month and loads (or creates) the usage of the given month
+(called with locked mutex).
+*/
+func (u *UsageAccounting) switchMonth(month string) {
+	if u.current != nil && u.dirty {
+		err := writeUsageMonth(u.current)
+		if err != nil {
+			slog.Error("error persisting usage accounting", "error", err, "month", u.current.Month)
+		}
+	}
+	usageMonth, err := readUsageMonth(month)
+	if err != nil {
+		slog.Error("error reading usage accounting, starting with empty usage", "error", err, "month", month)
+		usageMonth = &UsageMonth{Month: month, Tenants: make(map[string]map[string]*UsageCounters)}
+	}
+	u.current = usageMonth
+	u.dirty = false
+}
+
+/*
+save persists the usage of the current month (if modified).
+*/
+func (u *UsageAccounting) save() {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	if u.current == nil || !u.dirty {
+		return
+	}
+	err := writeUsageMonth(u.current)
+	if err != nil {
+		slog.Error("error persisting usage accounting", "error", err, "month", u.current.Month)
+		return
+	}
+	u.dirty = false
+}
+
+/*
+report returns a copy of the usage of the given month (current month from memory, past months from file).
+*/
+func (u *UsageAccounting) report(month string) (*UsageMonth, error) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	if u.current != nil && u.current.Month == month {
+		report := &UsageMonth{Month: month, Tenants: make(map[string]map[string]*UsageCounters, len(u.current.Tenants))}
+		for tenant, endpoints := range u.current.Tenants {
+			report.Tenants[tenant] = make(map[string]*UsageCounters, len(endpoints))
+			for endpoint, counters := range endpoints {
+				countersCopy := *counters
+				report.Tenants[tenant][endpoint] = &countersCopy
+			}
+		}
+		return report, nil
+	}
+	return readUsageMonth(month)
+}
+
+/*
+getUsageFile returns the name of the accounting file for the month.
+*/
+func getUsageFile(month string) string {
+	return filepath.Join(getProgConfig().Accounting.Directory, "usage-"+month+".json")
+}
+
+/*
+readUsageMonth reads the usage of a month from the accounting file (empty usage if file does not exist).
+*/
+func readUsageMonth(month string) (*UsageMonth, error) {
+	usageMonth := &UsageMonth{Month: month, Tenants: make(map[string]map[string]*UsageCounters)}
+
+	filename := getUsageFile(month)
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return usageMonth, nil
+		}
+		return nil, fmt.Errorf("error [%w] at os.ReadFile(), file: %s", err, filename)
+	}
+	err = json.Unmarshal(data, usageMonth)
+	if err != nil {
+		return nil, fmt.Errorf("error [%w] at json.Unmarshal(), file: %s", err, filename)
+	}
+	if usageMonth.Tenants == nil {
+		usageMonth.Tenants = make(map[string]map[string]*UsageCounters)
+	}
+	return usageMonth, nil
+}
+
+/*
+writeUsageMonth writes the usage of a month to the accounting file (via temporary file and rename).
+*/
+func writeUsageMonth(usageMonth *UsageMonth) error {
+	data, err := json.MarshalIndent(usageMonth, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error [%w] at json.MarshalIndent()", err)
+	}
+	filename := getUsageFile(usageMonth.Month)
+	err = os.MkdirAll(filepath.Dir(filename), 0755)
+	if err != nil {
+		return fmt.Errorf("error [%w] at os.MkdirAll(), directory: %s", err, filepath.Dir(filename))
+	}
+	tempFilename := filename + ".tmp"
+	err = os.WriteFile(tempFilename, data, 0644)
+	if err != nil {
+		return fmt.Errorf("error [%w] at os.WriteFile(), file: %s", err, tempFilename)
+	}
+	err = os.Rename(tempFilename, filename)
+	if err != nil {
+		return fmt.Errorf("error [%w] at os.Rename(), file: %s", err, filename)
+	}
+	return nil
+}
+
+/*
+usageReportRequest handles 'usage report' request (admin endpoint). Exports the usage of a month
+(parameter 'month', YYYY-MM, default: current month) as JSON or CSV (parameter 'format').
+*/
+func usageReportRequest(writer http.ResponseWriter, request *http.Request) {
+	if !getProgConfig().Accounting.Enabled {
+		http.Error(writer, "usage accounting disabled", http.StatusNotFound)
+		return
+	}
+
+	month := request.URL.Query().Get("month")
+	if month == "" {
+		month = time.Now().UTC().Format("2006-01")
+	}
+	if !usageMonthRegex.MatchString(month) {
+		http.Error(writer, "invalid month (expected: YYYY-MM)", http.StatusBadRequest)
+		return
+	}
+	format := request.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" {
+		http.Error(writer, "invalid format (expected: json, csv)", http.StatusBadRequest)
+		return
+	}
+
+	report, err := usageAccounting.report(month)
+	if err != nil {
+		slog.ErrorContext(request.Context(), "usage report request: error reading usage", "error", err, "month", month)
+		http.Error(writer, "error reading usage", http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Cache-Control", "no-store")
+	if format == "json" {
+		usageReportResponse := struct {
+			Type       string
+			ID         string
+			Attributes *UsageMonth
+		}{Type: TypeUsageReportResponse, ID: month, Attributes: report}
+		streamJSONResponse(writer, request, http.StatusOK, usageReportResponse, false)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	writer.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="usage-%s.csv"`, month))
+	writer.WriteHeader(http.StatusOK)
+	csvWriter := csv.NewWriter(writer)
+	_ = csvWriter.Write([]string{"Month", "Tenant", "Endpoint", "Requests", "SuccessfulRequests", "GPXPoints", "RasterTiles"})
+	tenants := make([]string, 0, len(report.Tenants))
+	for tenant := range report.Tenants {
+		tenants = append(tenants, tenant)
+	}
+	slices.Sort(tenants)
+	for _, tenant := range tenants {
+		endpoints := make([]string, 0, len(report.Tenants[tenant]))
+		for endpoint := range report.Tenants[tenant] {
+			endpoints = append(endpoints, endpoint)
+		}
+		slices.Sort(endpoints)
+		for _, endpoint := range endpoints {
+			counters := report.Tenants[tenant][endpoint]
+			_ = csvWriter.Write([]string{month, tenant, endpoint,
+				strconv.FormatUint(counters.Requests, 10),
+				strconv.FormatUint(counters.SuccessfulRequests, 10),
+				strconv.FormatUint(counters.GPXPoints, 10),
+				strconv.FormatUint(counters.RasterTiles, 10)})
+		}
+	}
+	csvWriter.Flush()
+}
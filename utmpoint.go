@@ -7,6 +7,8 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"sync/atomic"
 )
@@ -22,41 +24,47 @@ func utmPointRequest(writer http.ResponseWriter, request *http.Request) {
 	// statistics
 	atomic.AddUint64(&UTMPointRequests, 1)
 
-	// limit overall request body size
-	request.Body = http.MaxBytesReader(writer, request.Body, MaxPointRequestBodySize)
+	var utmPointRequest UTMPointRequest
+	var err error
+	if request.Method == http.MethodGet {
+		// simple query with URL parameters (e.g. from browsers, spreadsheets, monitoring probes)
+		utmPointRequest, err = parseUTMPointQuery(request.URL.Query())
+		if err != nil {
+			slog.WarnContext(request.Context(), "utm point request: error parsing query parameters", "error", err, "ID", "unknown")
+			utmPointResponse.Attributes.Error = newErrorObject(EndpointUTMPoint, ReasonParsingQueryParameters, err.Error())
+			buildUTMPointResponse(writer, request, http.StatusBadRequest, utmPointResponse)
+			return
+		}
+	} else {
+		// limit overall request body size
+		request.Body = http.MaxBytesReader(writer, request.Body, MaxPointRequestBodySize)
 
-	// read request
-	bodyData, err := io.ReadAll(request.Body)
-	if err != nil {
-		// check specifically for the error returned by MaxBytesReader
-		var maxBytesErr *http.MaxBytesError
-		if errors.As(err, &maxBytesErr) {
-			slog.Warn("utm point request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
-			utmPointResponse.Attributes.Error.Code = "3000"
-			utmPointResponse.Attributes.Error.Title = "request body too large"
-			utmPointResponse.Attributes.Error.Detail = fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit)
-			buildUTMPointResponse(writer, http.StatusRequestEntityTooLarge, utmPointResponse)
-		} else {
-			// handle other read errors
-			slog.Warn("utm point request: error reading request body", "error", err, "ID", "unknown")
-			utmPointResponse.Attributes.Error.Code = "3020"
-			utmPointResponse.Attributes.Error.Title = "error reading request body"
-			utmPointResponse.Attributes.Error.Detail = err.Error()
-			buildUTMPointResponse(writer, http.StatusBadRequest, utmPointResponse)
+		// read request
+		bodyData, err := io.ReadAll(request.Body)
+		if err != nil {
+			// check specifically for the error returned by MaxBytesReader
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				slog.WarnContext(request.Context(), "utm point request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
+				utmPointResponse.Attributes.Error = newErrorObject(EndpointUTMPoint, ReasonRequestBodyTooLarge, fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit))
+				buildUTMPointResponse(writer, request, http.StatusRequestEntityTooLarge, utmPointResponse)
+			} else {
+				// handle other read errors
+				slog.WarnContext(request.Context(), "utm point request: error reading request body", "error", err, "ID", "unknown")
+				utmPointResponse.Attributes.Error = newErrorObject(EndpointUTMPoint, ReasonReadingRequestBody, err.Error())
+				buildUTMPointResponse(writer, request, http.StatusBadRequest, utmPointResponse)
+			}
+			return
 		}
-		return
-	}
 
-	// unmarshal request
-	utmPointRequest := UTMPointRequest{}
-	err = json.Unmarshal(bodyData, &utmPointRequest)
-	if err != nil {
-		slog.Warn("utm point request: error unmarshaling request body", "error", err, "ID", "unknown")
-		utmPointResponse.Attributes.Error.Code = "3040"
-		utmPointResponse.Attributes.Error.Title = "error unmarshaling request body"
-		utmPointResponse.Attributes.Error.Detail = err.Error()
-		buildUTMPointResponse(writer, http.StatusBadRequest, utmPointResponse)
-		return
+		// unmarshal request
+		err = json.Unmarshal(bodyData, &utmPointRequest)
+		if err != nil {
+			slog.WarnContext(request.Context(), "utm point request: error unmarshaling request body", "error", err, "ID", "unknown")
+			utmPointResponse.Attributes.Error = newErrorObject(EndpointUTMPoint, ReasonUnmarshalingRequestBody, err.Error())
+			buildUTMPointResponse(writer, request, http.StatusBadRequest, utmPointResponse)
+			return
+		}
 	}
 
 	// copy request parameters into response
@@ -64,26 +72,51 @@ func utmPointRequest(writer http.ResponseWriter, request *http.Request) {
 	utmPointResponse.Attributes.Zone = utmPointRequest.Attributes.Zone
 	utmPointResponse.Attributes.Easting = utmPointRequest.Attributes.Easting
 	utmPointResponse.Attributes.Northing = utmPointRequest.Attributes.Northing
+	utmPointResponse.Attributes.VerticalOffset = utmPointRequest.Attributes.VerticalOffset
+	utmPointResponse.Attributes.Interpolation = utmPointRequest.Attributes.Interpolation
 
 	// verify request data
 	err = verifyUTMPointRequestData(request, utmPointRequest)
 	if err != nil {
-		slog.Warn("utm point request: error verifying request data", "error", err, "ID", utmPointRequest.ID)
-		utmPointResponse.Attributes.Error.Code = "3060"
-		utmPointResponse.Attributes.Error.Title = "error verifying request data"
-		utmPointResponse.Attributes.Error.Detail = err.Error()
-		buildUTMPointResponse(writer, http.StatusBadRequest, utmPointResponse)
+		slog.WarnContext(request.Context(), "utm point request: error verifying request data", "error", err, "ID", utmPointRequest.ID)
+		utmPointResponse.Attributes.Error = newErrorObject(EndpointUTMPoint, ReasonVerifyingRequestData, err.Error())
+		buildUTMPointResponse(writer, request, http.StatusBadRequest, utmPointResponse)
 		return
 	}
 
-	// get elevation
-	elevation, tile, err := getElevationForUTMPoint(utmPointRequest.Attributes.Zone, utmPointRequest.Attributes.Easting, utmPointRequest.Attributes.Northing)
+	// set default interpolation
+	if utmPointResponse.Attributes.Interpolation == "" {
+		utmPointResponse.Attributes.Interpolation = "nearest"
+	}
+
+	// get elevation (value of grid cell or bilinear interpolated)
+	var elevation float64
+	var tile TileMetadata
+	if utmPointResponse.Attributes.Interpolation == "bilinear" {
+		var cells []InterpolationCell
+		var warnings []string
+		elevation, tile, cells, warnings, err = getBilinearElevationForUTMPoint(utmPointRequest.Attributes.Zone, utmPointRequest.Attributes.Easting, utmPointRequest.Attributes.Northing)
+		utmPointResponse.Attributes.Warnings = append(utmPointResponse.Attributes.Warnings, warnings...)
+		if utmPointRequest.Attributes.Debug {
+			utmPointResponse.Attributes.Cells = cells
+		}
+	} else {
+		elevation, tile, err = getElevationForUTMPoint(utmPointRequest.Attributes.Zone, utmPointRequest.Attributes.Easting, utmPointRequest.Attributes.Northing)
+	}
 	if err != nil {
-		slog.Debug("utm point request: error getting elevation for utm point", "error", err, "ID", utmPointRequest.ID)
-		utmPointResponse.Attributes.Error.Code = "3080"
-		utmPointResponse.Attributes.Error.Title = "error getting elevation"
-		utmPointResponse.Attributes.Error.Detail = err.Error()
-		buildUTMPointResponse(writer, http.StatusBadRequest, utmPointResponse)
+		slog.DebugContext(request.Context(), "utm point request: error getting elevation for utm point", "error", err, "ID", utmPointRequest.ID)
+		if errors.Is(err, ErrOutsideCoverage) {
+			utmPointResponse.Attributes.Error = newErrorObject(EndpointUTMPoint, ReasonOutsideCoverage, describeOutsideCoverage(err))
+			buildUTMPointResponse(writer, request, http.StatusNotFound, utmPointResponse)
+			return
+		}
+		if errors.Is(err, ErrSourceUnavailable) {
+			utmPointResponse.Attributes.Error = newErrorObject(EndpointUTMPoint, ReasonSourceUnavailable, err.Error())
+			buildUTMPointResponse(writer, request, http.StatusServiceUnavailable, utmPointResponse)
+			return
+		}
+		utmPointResponse.Attributes.Error = newErrorObject(EndpointUTMPoint, ReasonGettingElevation, err.Error())
+		buildUTMPointResponse(writer, request, http.StatusBadRequest, utmPointResponse)
 		return
 	}
 
@@ -92,50 +125,85 @@ func utmPointRequest(writer http.ResponseWriter, request *http.Request) {
 	origin := "unknown"
 	resource, err := getElevationResource(tile.Source)
 	if err != nil {
-		slog.Error("point request: error getting elevation resource", "error", err, "source", tile.Source, "ID", utmPointRequest.ID)
+		slog.ErrorContext(request.Context(), "point request: error getting elevation resource", "error", err, "source", tile.Source, "ID", utmPointRequest.ID)
 	} else {
 		attribution = resource.Attribution
 		origin = resource.Code
 	}
 
-	// success response
-	utmPointResponse.Attributes.Elevation = elevation
+	// success response (elevation relative to custom datum)
+	utmPointResponse.Attributes.Elevation = elevation + utmPointRequest.Attributes.VerticalOffset
 	utmPointResponse.Attributes.Actuality = tile.Actuality
 	utmPointResponse.Attributes.Origin = origin
 	utmPointResponse.Attributes.Attribution = attribution
 	utmPointResponse.Attributes.TileIndex = tile.Index
 	utmPointResponse.Attributes.IsError = false
-	buildUTMPointResponse(writer, http.StatusOK, utmPointResponse)
+	buildUTMPointResponse(writer, request, http.StatusOK, utmPointResponse)
 }
 
 /*
-verifyUTMPointRequestData verifies 'utm point' request data.
-It performs several checks on the request data to ensure its validity.
+parseUTMPointQuery builds UTM point request from URL query parameters (zone, easting, northing, offset, interpolation,
+debug, id).
 */
-func verifyUTMPointRequestData(request *http.Request, utmPointRequest UTMPointRequest) error {
-	// verify HTTP header
-	contentType := request.Header.Get("Content-Type")
-	isContentTypeValid := true
-	switch {
-	case strings.HasPrefix(strings.ToLower(contentType), "application/json"):
-		// potentially check charset=utf-8 specifically if required
-	default:
-		isContentTypeValid = false
+func parseUTMPointQuery(query url.Values) (UTMPointRequest, error) {
+	utmPointRequest := UTMPointRequest{Type: TypeUTMPointRequest, ID: query.Get("id")}
+
+	zone, err := strconv.Atoi(query.Get("zone"))
+	if err != nil {
+		return utmPointRequest, fmt.Errorf("invalid or missing query parameter 'zone' (%w)", err)
+	}
+	easting, err := strconv.ParseFloat(query.Get("easting"), 64)
+	if err != nil {
+		return utmPointRequest, fmt.Errorf("invalid or missing query parameter 'easting' (%w)", err)
 	}
-	if !isContentTypeValid {
-		return fmt.Errorf("unexpected or missing HTTP header field Content-Type, value = [%s], expected 'application/json'", contentType)
+	northing, err := strconv.ParseFloat(query.Get("northing"), 64)
+	if err != nil {
+		return utmPointRequest, fmt.Errorf("invalid or missing query parameter 'northing' (%w)", err)
 	}
+	utmPointRequest.Attributes.Zone = zone
+	utmPointRequest.Attributes.Easting = easting
+	utmPointRequest.Attributes.Northing = northing
 
-	// verify HTTP header
-	accept := request.Header.Get("Accept")
-	isAcceptValid := true
-	switch {
-	case strings.HasPrefix(strings.ToLower(accept), "application/json"):
-	default:
-		isAcceptValid = false
+	// optional vertical offset (custom datum)
+	if query.Has("offset") {
+		verticalOffset, err := strconv.ParseFloat(query.Get("offset"), 64)
+		if err != nil {
+			return utmPointRequest, fmt.Errorf("invalid query parameter 'offset' (%w)", err)
+		}
+		utmPointRequest.Attributes.VerticalOffset = verticalOffset
+	}
+
+	// optional interpolation (nearest, bilinear) and debug output
+	utmPointRequest.Attributes.Interpolation = query.Get("interpolation")
+	if query.Has("debug") {
+		debug, err := strconv.ParseBool(query.Get("debug"))
+		if err != nil {
+			return utmPointRequest, fmt.Errorf("invalid query parameter 'debug' (%w)", err)
+		}
+		utmPointRequest.Attributes.Debug = debug
 	}
-	if !isAcceptValid {
-		return fmt.Errorf("unexpected or missing HTTP header field Accept, value = [%s], expected 'application/json'", accept)
+
+	return utmPointRequest, nil
+}
+
+/*
+verifyUTMPointRequestData verifies 'utm point' request data.
+It performs several checks on the request data to ensure its validity.
+*/
+func verifyUTMPointRequestData(request *http.Request, utmPointRequest UTMPointRequest) error {
+	// verify HTTP headers (JSON request body only)
+	if request.Method == http.MethodPost {
+		contentType := request.Header.Get("Content-Type")
+		isContentTypeValid := true
+		switch {
+		case strings.HasPrefix(strings.ToLower(contentType), "application/json"):
+			// potentially check charset=utf-8 specifically if required
+		default:
+			isContentTypeValid = false
+		}
+		if !isContentTypeValid {
+			return fmt.Errorf("unexpected or missing HTTP header field Content-Type, value = [%s], expected 'application/json'", contentType)
+		}
 	}
 
 	// verify Type
@@ -153,6 +221,24 @@ func verifyUTMPointRequestData(request *http.Request, utmPointRequest UTMPointRe
 		return errors.New("invalid zone for Germany")
 	}
 
+	// verify Attributes.VerticalOffset
+	err := verifyVerticalOffset(utmPointRequest.Attributes.VerticalOffset)
+	if err != nil {
+		return err
+	}
+
+	// verify Attributes.Interpolation
+	switch utmPointRequest.Attributes.Interpolation {
+	case "", "nearest", "bilinear":
+	default:
+		return errors.New("unsupported interpolation (not 'nearest' or 'bilinear')")
+	}
+
+	// verify Attributes.Debug
+	if utmPointRequest.Attributes.Debug && utmPointRequest.Attributes.Interpolation != "bilinear" {
+		return errors.New("Debug requires interpolation 'bilinear'")
+	}
+
 	return nil
 }
 
@@ -161,21 +247,17 @@ buildUTMPointResponse builds HTTP responses with specified status and body.
 It sets the Content-Type and Content-Length headers before writing the response body.
 This function is used to construct consistent HTTP responses throughout the application.
 */
-func buildUTMPointResponse(writer http.ResponseWriter, httpStatus int, utmPointResponse UTMPointResponse) {
+func buildUTMPointResponse(writer http.ResponseWriter, request *http.Request, httpStatus int, utmPointResponse UTMPointResponse) {
+	// response metadata (versions, processing duration, cache hit)
+	utmPointResponse.Meta = newResponseMeta(request.Context())
+
 	// log limit length of body (we don't expect large bodies)
 	maxBodyLength := 1024
 
-	// CORS: allow requests from any origin
-	writer.Header().Set("Access-Control-Allow-Origin", "*")
-	// CORS: allowed methods
-	writer.Header().Set("Access-Control-Allow-Methods", "POST")
-	// CORS: allowed headers
-	writer.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
 	// marshal response
-	body, err := json.MarshalIndent(utmPointResponse, "", "  ")
+	body, err := marshalResponse(utmPointResponse)
 	if err != nil {
-		slog.Error("error marshaling point response", "error", err, "body length", len(body),
+		slog.ErrorContext(request.Context(), "error marshaling point response", "error", err, "body length", len(body),
 			fmt.Sprintf("body (limited to first %d bytes)", maxBodyLength), body[:maxBodyLength])
 
 		http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
@@ -187,7 +269,7 @@ func buildUTMPointResponse(writer http.ResponseWriter, httpStatus int, utmPointR
 	writer.WriteHeader(httpStatus)
 	_, err = writer.Write(body)
 	if err != nil {
-		slog.Error("error writing HTTP response body", "error", err, "body length", len(body),
+		slog.ErrorContext(request.Context(), "error writing HTTP response body", "error", err, "body length", len(body),
 			fmt.Sprintf("body (limited to first %d bytes)", maxBodyLength), body[:maxBodyLength])
 	}
 }
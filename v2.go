@@ -0,0 +1,312 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// JSONAPIV2MediaType is the media type of API v2 responses (JSON:API)
+const JSONAPIV2MediaType = "application/vnd.api+json"
+
+// v2ETagSuffix distinguishes the ETag of v2 representations from v1 representations
+const v2ETagSuffix = "-v2"
+
+/*
+API v2 wraps the v1 handlers: the JSON:API request document is translated into a v1 request, the v1
+handler processes it, and the v1 response is translated into a JSON:API document (data/errors/meta).
+v1 remains unchanged.
+*/
+
+// V2Endpoint represents an API v2 endpoint and the v1 handler it is based on.
+type V2Endpoint struct {
+	Path        string
+	Methods     []string
+	Endpoint    *ErrorEndpoint // v1 endpoint (path, error codes)
+	RequestType string         // v1 request type
+	MaxBodySize int64
+	Handler     http.HandlerFunc
+}
+
+// v2Endpoints lists all API v2 endpoints
+var v2Endpoints = []V2Endpoint{
+	{"/v2/point", []string{http.MethodGet, http.MethodPost}, EndpointPoint, TypePointRequest, MaxPointRequestBodySize, pointRequest},
+	{"/v2/utmpoint", []string{http.MethodGet, http.MethodPost}, EndpointUTMPoint, TypeUTMPointRequest, MaxPointRequestBodySize, utmPointRequest},
+	{"/v2/gpx", []string{http.MethodPost}, EndpointGPX, TypeGPXRequest, MaxGpxRequestBodySize, gpxRequest},
+	{"/v2/gpxanalyze", []string{http.MethodPost}, EndpointGPXAnalyze, TypeGPXAnalyzeRequest, MaxGpxAnalyzeRequestBodySize, gpxAnalyzeRequest},
+	{"/v2/contours", []string{http.MethodPost}, EndpointContours, TypeContoursRequest, MaxContoursRequestBodySize, contoursRequest},
+	{"/v2/hillshade", []string{http.MethodPost}, EndpointHillshade, TypeHillshadeRequest, MaxHillshadeRequestBodySize, hillshadeRequest},
+	{"/v2/slope", []string{http.MethodPost}, EndpointSlope, TypeSlopeRequest, MaxSlopeRequestBodySize, slopeRequest},
+	{"/v2/aspect", []string{http.MethodPost}, EndpointAspect, TypeAspectRequest, MaxAspectRequestBodySize, aspectRequest},
+	{"/v2/tpi", []string{http.MethodPost}, EndpointTPI, TypeTPIRequest, MaxTPIRequestBodySize, tpiRequest},
+	{"/v2/tri", []string{http.MethodPost}, EndpointTRI, TypeTRIRequest, MaxTRIRequestBodySize, triRequest},
+	{"/v2/roughness", []string{http.MethodPost}, EndpointRoughness, TypeRoughnessRequest, MaxRoughnessRequestBodySize, roughnessRequest},
+	{"/v2/rawtif", []string{http.MethodPost}, EndpointRawTIF, TypeRawTIFRequest, MaxRawTIFRequestBodySize, rawtifRequest},
+	{"/v2/colorrelief", []string{http.MethodPost}, EndpointColorRelief, TypeColorReliefRequest, MaxColorReliefRequestBodySize, colorReliefRequest},
+	{"/v2/histogram", []string{http.MethodPost}, EndpointHistogram, TypeHistogramRequest, MaxHistogramRequestBodySize, histogramRequest},
+	{"/v2/elevationprofile", []string{http.MethodPost}, EndpointElevationProfile, TypeElevationProfileRequest, MaxElevationProfileRequestBodySize, elevationprofileRequest},
+	{"/v2/visualize", []string{http.MethodPost}, EndpointVisualize, TypeVisualizeRequest, MaxVisualizeRequestBodySize, visualizeRequest},
+	{"/v2/compare", []string{http.MethodPost}, EndpointCompare, TypeCompareRequest, MaxCompareRequestBodySize, compareRequest},
+	{"/v2/aspectrose", []string{http.MethodPost}, EndpointAspectRose, TypeAspectRoseRequest, MaxAspectRoseRequestBodySize, aspectRoseRequest},
+	{"/v2/elevationrange", []string{http.MethodPost}, EndpointElevationRange, TypeElevationRangeRequest, MaxElevationRangeRequestBodySize, elevationRangeRequest},
+	{"/v2/contourline", []string{http.MethodPost}, EndpointContourLine, TypeContourLineRequest, MaxContourLineRequestBodySize, contourLineRequest},
+	{"/v2/elevationmatrix", []string{http.MethodPost}, EndpointElevationMatrix, TypeElevationMatrixRequest, MaxElevationMatrixRequestBodySize, elevationMatrixRequest},
+	{"/v2/legend", []string{http.MethodPost}, EndpointLegend, TypeLegendRequest, MaxLegendRequestBodySize, legendRequest},
+}
+
+// V2ResourceObject represents a JSON:API resource object.
+type V2ResourceObject struct {
+	Type       string         `json:"type"`
+	ID         string         `json:"id,omitempty"`
+	Attributes map[string]any `json:"attributes"`
+}
+
+// V2ErrorObject represents a JSON:API error object.
+type V2ErrorObject struct {
+	Status string `json:"status"`
+	Code   string `json:"code,omitempty"`
+	Title  string `json:"title"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// V2Document represents a JSON:API top-level document.
+type V2Document struct {
+	Data   *V2ResourceObject `json:"data,omitempty"`
+	Errors []V2ErrorObject   `json:"errors,omitempty"`
+	Meta   map[string]any    `json:"meta,omitempty"`
+}
+
+// v1Document represents the common envelope of all v1 requests and responses.
+type v1Document struct {
+	Type       string
+	ID         string
+	Attributes map[string]any
+	Meta       map[string]any
+}
+
+/*
+camelCaseType converts a v1 type name into a JSON:API type name (e.g. UTMPointResponse -> utmPointResponse).
+*/
+func camelCaseType(typeName string) string {
+	runes := []rune(typeName)
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+	// keep the first letter of the next word (e.g. 'P' of UTMPoint) in upper case
+	if upper > 1 && upper < len(runes) {
+		upper--
+	}
+	for i := range upper {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+/*
+v2BufferedWriter buffers the response of the v1 handler (translated afterwards).
+*/
+type v2BufferedWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+/*
+Header returns the header map of the buffered response.
+*/
+func (w *v2BufferedWriter) Header() http.Header {
+	return w.header
+}
+
+/*
+WriteHeader records the status code (only the first call is effective).
+*/
+func (w *v2BufferedWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+/*
+Write appends the data to the buffered body (status 200 if not set before).
+*/
+func (w *v2BufferedWriter) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(data)
+}
+
+/*
+v2MediaTypeWriter sets the JSON:API media type for the response (overrides the v1 media type).
+*/
+type v2MediaTypeWriter struct {
+	http.ResponseWriter
+}
+
+func (w v2MediaTypeWriter) WriteHeader(status int) {
+	w.Header().Set("Content-Type", JSONAPIV2MediaType)
+	w.ResponseWriter.WriteHeader(status)
+}
+
+/*
+v2Handler returns the handler for an API v2 endpoint.
+*/
+func v2Handler(endpoint V2Endpoint) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		// build v1 request (without compression, v1 media types, v1 ETags)
+		v1Request := request.Clone(request.Context())
+		v1Request.URL.Path = endpoint.Endpoint.Path
+		v1Request.Header.Del("Accept-Encoding")
+		v1Request.Header.Set("Accept", "application/json")
+		if ifNoneMatch := request.Header.Get("If-None-Match"); ifNoneMatch != "" {
+			v1Request.Header.Set("If-None-Match", strings.ReplaceAll(ifNoneMatch, v2ETagSuffix+`"`, `"`))
+		}
+		query := v1Request.URL.Query()
+		query.Del("format")
+		v1Request.URL.RawQuery = query.Encode()
+
+		if request.Method == http.MethodPost {
+			body, err := translateV2RequestBody(writer, request, endpoint)
+			if err != nil {
+				status := http.StatusBadRequest
+				errorObject := newErrorObject(endpoint.Endpoint, ReasonUnmarshalingRequestBody, err.Error())
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					status = http.StatusRequestEntityTooLarge
+					errorObject = newErrorObject(endpoint.Endpoint, ReasonRequestBodyTooLarge, fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit))
+				}
+				slog.WarnContext(request.Context(), "v2 request: error translating request document", "error", err, "path", request.URL.Path)
+				document := V2Document{Errors: []V2ErrorObject{{strconv.Itoa(status), errorObject.Code, errorObject.Title, errorObject.Detail}}}
+				streamJSONResponse(v2MediaTypeWriter{writer}, request, status, document, false)
+				return
+			}
+			v1Request.Body = io.NopCloser(bytes.NewReader(body))
+			v1Request.ContentLength = int64(len(body))
+			v1Request.Header.Set("Content-Type", "application/json")
+		}
+
+		// process request with v1 handler
+		v1Response := &v2BufferedWriter{header: make(http.Header)}
+		endpoint.Handler(v1Response, v1Request)
+
+		// pass headers of v1 response (except representation headers)
+		for name, values := range v1Response.header {
+			switch name {
+			case "Content-Type", "Content-Encoding", "Content-Length", "Vary":
+				continue
+			case "Etag":
+				writer.Header().Set("ETag", strings.TrimSuffix(values[0], `"`)+v2ETagSuffix+`"`)
+			default:
+				writer.Header()[name] = values
+			}
+		}
+		if v1Response.status == http.StatusNotModified {
+			writer.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		document, err := translateV1ResponseBody(v1Response.body.Bytes(), v1Response.status)
+		if err != nil {
+			slog.ErrorContext(request.Context(), "v2 request: error translating v1 response", "error", err, "path", request.URL.Path)
+			errorObject := newErrorObject(EndpointService, ReasonInternalServerError, "")
+			document = V2Document{Errors: []V2ErrorObject{{strconv.Itoa(http.StatusInternalServerError), errorObject.Code, errorObject.Title, ""}}}
+			v1Response.status = http.StatusInternalServerError
+		}
+		streamJSONResponse(v2MediaTypeWriter{writer}, request, v1Response.status, document, false)
+	}
+}
+
+/*
+translateV2RequestBody translates a JSON:API request document {"data": {"type", "id", "attributes"}} into a v1
+request body {"Type", "ID", "Attributes"}.
+*/
+func translateV2RequestBody(writer http.ResponseWriter, request *http.Request, endpoint V2Endpoint) ([]byte, error) {
+	bodyData, err := io.ReadAll(http.MaxBytesReader(writer, request.Body, endpoint.MaxBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("error [%w] at io.ReadAll()", err)
+	}
+
+	var document struct {
+		Data *struct {
+			Type       string          `json:"type"`
+			ID         string          `json:"id"`
+			Attributes json.RawMessage `json:"attributes"`
+		} `json:"data"`
+	}
+	err = json.Unmarshal(bodyData, &document)
+	if err != nil {
+		return nil, fmt.Errorf("error [%w] at json.Unmarshal()", err)
+	}
+	if document.Data == nil {
+		return nil, errors.New("missing primary data (member 'data')")
+	}
+	expectedType := camelCaseType(endpoint.RequestType)
+	if document.Data.Type != expectedType {
+		return nil, fmt.Errorf("unexpected resource type [%s], expected [%s]", document.Data.Type, expectedType)
+	}
+	if len(document.Data.Attributes) == 0 {
+		document.Data.Attributes = json.RawMessage("{}")
+	}
+
+	v1Body := struct {
+		Type       string
+		ID         string
+		Attributes json.RawMessage
+	}{endpoint.RequestType, document.Data.ID, document.Data.Attributes}
+	data, err := json.Marshal(v1Body)
+	if err != nil {
+		return nil, fmt.Errorf("error [%w] at json.Marshal()", err)
+	}
+	return data, nil
+}
+
+/*
+translateV1ResponseBody translates a v1 response into a JSON:API document. Errors are returned as 'errors'
+(with HTTP status), warnings are moved to 'meta'.
+*/
+func translateV1ResponseBody(body []byte, status int) (V2Document, error) {
+	var v1Response v1Document
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	err := decoder.Decode(&v1Response)
+	if err != nil {
+		return V2Document{}, fmt.Errorf("error [%w] at decoder.Decode()", err)
+	}
+
+	document := V2Document{Meta: make(map[string]any)}
+	for name, value := range v1Response.Meta {
+		document.Meta[camelCaseType(name)] = value
+	}
+	if warnings, found := v1Response.Attributes["Warnings"]; found && warnings != nil {
+		document.Meta["warnings"] = warnings
+	}
+
+	isError, _ := v1Response.Attributes["IsError"].(bool)
+	if isError {
+		errorObject, _ := v1Response.Attributes["Error"].(map[string]any)
+		code, _ := errorObject["Code"].(string)
+		title, _ := errorObject["Title"].(string)
+		detail, _ := errorObject["Detail"].(string)
+		document.Errors = []V2ErrorObject{{strconv.Itoa(status), code, title, detail}}
+		return document, nil
+	}
+
+	delete(v1Response.Attributes, "IsError")
+	delete(v1Response.Attributes, "Error")
+	delete(v1Response.Attributes, "Warnings")
+	document.Data = &V2ResourceObject{
+		Type:       camelCaseType(v1Response.Type),
+		ID:         v1Response.ID,
+		Attributes: v1Response.Attributes,
+	}
+	return document, nil
+}
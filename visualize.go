@@ -0,0 +1,465 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+/*
+visualizeRequest handles 'visualize request' from client.
+The visualization is generated by the shared pipeline for the requested type of visualization.
+*/
+func visualizeRequest(writer http.ResponseWriter, request *http.Request) {
+	var visualizeResponse = VisualizeResponse{Type: TypeVisualizeResponse, ID: "unknown"}
+	visualizeResponse.Attributes.IsError = true
+
+	// statistics
+	atomic.AddUint64(&VisualizeRequests, 1)
+
+	// limit overall request body size
+	request.Body = http.MaxBytesReader(writer, request.Body, MaxVisualizeRequestBodySize)
+
+	// read request
+	bodyData, err := io.ReadAll(request.Body)
+	if err != nil {
+		// check specifically for the error returned by MaxBytesReader
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			slog.WarnContext(request.Context(), "visualize request: request body too large", "limit", maxBytesErr.Limit, "ID", "unknown")
+			visualizeResponse.Attributes.Error = newErrorObject(EndpointVisualize, ReasonRequestBodyTooLarge, fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit))
+			buildVisualizeResponse(writer, request, http.StatusRequestEntityTooLarge, visualizeResponse)
+		} else {
+			// handle other read errors
+			slog.WarnContext(request.Context(), "visualize request: error reading request body", "error", err, "ID", "unknown")
+			visualizeResponse.Attributes.Error = newErrorObject(EndpointVisualize, ReasonReadingRequestBody, err.Error())
+			buildVisualizeResponse(writer, request, http.StatusBadRequest, visualizeResponse)
+		}
+		return
+	}
+
+	// unmarshal request
+	visualizeRequest := VisualizeRequest{}
+	err = json.Unmarshal(bodyData, &visualizeRequest)
+	if err != nil {
+		slog.WarnContext(request.Context(), "visualize request: error unmarshaling request body", "error", err, "ID", "unknown")
+		visualizeResponse.Attributes.Error = newErrorObject(EndpointVisualize, ReasonUnmarshalingRequestBody, err.Error())
+		buildVisualizeResponse(writer, request, http.StatusBadRequest, visualizeResponse)
+		return
+	}
+
+	// resolve place name (geocoding) into lon/lat coordinates
+	if visualizeRequest.Attributes.Place != "" {
+		place, err := geocodePlace(request.Context(), visualizeRequest.Attributes.Place)
+		if err != nil {
+			slog.WarnContext(request.Context(), "visualize request: error geocoding place", "error", err, "place", visualizeRequest.Attributes.Place, "ID", visualizeRequest.ID)
+			visualizeResponse.ID = visualizeRequest.ID
+			visualizeResponse.Attributes.Place = visualizeRequest.Attributes.Place
+			if errors.Is(err, ErrPlaceNotFound) {
+				visualizeResponse.Attributes.Error = newErrorObject(EndpointVisualize, ReasonPlaceNotFound, err.Error())
+				buildVisualizeResponse(writer, request, http.StatusNotFound, visualizeResponse)
+				return
+			}
+			if errors.Is(err, ErrGeocoderUnavailable) {
+				visualizeResponse.Attributes.Error = newErrorObject(EndpointVisualize, ReasonGeocoderUnavailable, err.Error())
+				buildVisualizeResponse(writer, request, http.StatusServiceUnavailable, visualizeResponse)
+				return
+			}
+			visualizeResponse.Attributes.Error = newErrorObject(EndpointVisualize, ReasonVerifyingRequestData, err.Error())
+			buildVisualizeResponse(writer, request, http.StatusBadRequest, visualizeResponse)
+			return
+		}
+		visualizeRequest.Attributes.Zone = 0
+		visualizeRequest.Attributes.Longitude = place.Longitude
+		visualizeRequest.Attributes.Latitude = place.Latitude
+		visualizeResponse.Attributes.PlaceName = place.DisplayName
+	}
+
+	// named color table (stored server-side) instead of color text file content
+	if visualizeRequest.Attributes.ColorTableName != "" {
+		visualizeRequest.Attributes.ColorTextFileContent, err = resolveColorTable(request.Context(), visualizeRequest.Attributes.ColorTableName, visualizeRequest.Attributes.ColorTextFileContent)
+		if err != nil {
+			slog.WarnContext(request.Context(), "visualize request: error resolving color table", "error", err, "name", visualizeRequest.Attributes.ColorTableName, "ID", visualizeRequest.ID)
+			visualizeResponse.ID = visualizeRequest.ID
+			visualizeResponse.Attributes.ColorTableName = visualizeRequest.Attributes.ColorTableName
+			visualizeResponse.Attributes.Error = newErrorObject(EndpointVisualize, ReasonVerifyingRequestData, err.Error())
+			buildVisualizeResponse(writer, request, http.StatusBadRequest, visualizeResponse)
+			return
+		}
+	}
+
+	// copy request parameters into response
+	visualizeResponse.ID = visualizeRequest.ID
+	visualizeResponse.Attributes.Zone = visualizeRequest.Attributes.Zone
+	visualizeResponse.Attributes.Easting = visualizeRequest.Attributes.Easting
+	visualizeResponse.Attributes.Northing = visualizeRequest.Attributes.Northing
+	visualizeResponse.Attributes.Longitude = visualizeRequest.Attributes.Longitude
+	visualizeResponse.Attributes.Latitude = visualizeRequest.Attributes.Latitude
+	visualizeResponse.Attributes.Place = visualizeRequest.Attributes.Place
+	visualizeResponse.Attributes.OutputFormat = visualizeRequest.Attributes.OutputFormat
+	visualizeResponse.Attributes.TypeOfVisualization = visualizeRequest.Attributes.TypeOfVisualization
+	visualizeResponse.Attributes.GradientAlgorithm = visualizeRequest.Attributes.GradientAlgorithm
+	visualizeResponse.Attributes.ColorTextFileContent = visualizeRequest.Attributes.ColorTextFileContent
+	visualizeResponse.Attributes.ColorTableName = visualizeRequest.Attributes.ColorTableName
+	visualizeResponse.Attributes.ColoringAlgorithm = visualizeRequest.Attributes.ColoringAlgorithm
+	visualizeResponse.Attributes.VerticalExaggeration = visualizeRequest.Attributes.VerticalExaggeration
+	visualizeResponse.Attributes.AzimuthOfLight = visualizeRequest.Attributes.AzimuthOfLight
+	visualizeResponse.Attributes.AltitudeOfLight = visualizeRequest.Attributes.AltitudeOfLight
+	visualizeResponse.Attributes.ShadingVariant = visualizeRequest.Attributes.ShadingVariant
+	visualizeResponse.Attributes.ZeroForFlat = visualizeRequest.Attributes.ZeroForFlat
+
+	// verify request data
+	err = verifyVisualizeRequestData(request, visualizeRequest)
+	if err != nil {
+		slog.WarnContext(request.Context(), "visualize request: error verifying request data", "error", err, "ID", visualizeRequest.ID)
+		visualizeResponse.Attributes.Error = newErrorObject(EndpointVisualize, ReasonVerifyingRequestData, err.Error())
+		buildVisualizeResponse(writer, request, http.StatusBadRequest, visualizeResponse)
+		return
+	}
+	visualization := visualizationTypes[strings.ToLower(visualizeRequest.Attributes.TypeOfVisualization)]
+	if visualization.usesColorTextFile && visualizeRequest.Attributes.ColoringAlgorithm == "" {
+		visualizeResponse.Attributes.Warnings = append(visualizeResponse.Attributes.Warnings, "ColoringAlgorithm not set, default 'interpolation' applied")
+	}
+
+	zone := 0
+	easting := 0.0
+	northing := 0.0
+	longitude := 0.0
+	latitude := 0.0
+	var tiles []TileMetadata
+	var outputFormat string
+
+	// determine type of coordinates
+	if visualizeRequest.Attributes.Zone != 0 {
+		// input from UTM coordinates
+		zone = visualizeRequest.Attributes.Zone
+		easting = visualizeRequest.Attributes.Easting
+		northing = visualizeRequest.Attributes.Northing
+		outputFormat = "geotiff"
+
+		// get all tiles (metadata) for given UTM coordinates
+		tiles, err = getAllTilesUTM(zone, easting, northing)
+		if err != nil {
+			slog.WarnContext(request.Context(), "visualize request: error getting GeoTIFF tile for UTM coordinates", "error", err,
+				"easting", easting, "northing", northing, "zone", zone, "ID", visualizeRequest.ID)
+			if errors.Is(err, ErrOutsideCoverage) {
+				visualizeResponse.Attributes.Error = newErrorObject(EndpointVisualize, ReasonOutsideCoverage, describeOutsideCoverage(err))
+				buildVisualizeResponse(writer, request, http.StatusNotFound, visualizeResponse)
+				return
+			}
+			if errors.Is(err, ErrSourceUnavailable) {
+				visualizeResponse.Attributes.Error = newErrorObject(EndpointVisualize, ReasonSourceUnavailable, err.Error())
+				buildVisualizeResponse(writer, request, http.StatusServiceUnavailable, visualizeResponse)
+				return
+			}
+			visualizeResponse.Attributes.Error = newErrorObject(EndpointVisualize, ReasonGettingTileUTM, err.Error())
+			buildVisualizeResponse(writer, request, http.StatusBadRequest, visualizeResponse)
+			return
+		}
+	} else {
+		// input from lon/lat coordinates
+		longitude = visualizeRequest.Attributes.Longitude
+		latitude = visualizeRequest.Attributes.Latitude
+		outputFormat = "png"
+
+		// get all tiles (metadata) for given lon/lat coordinates
+		tiles, err = getAllTilesLonLat(longitude, latitude)
+		if err != nil {
+			err = fmt.Errorf("error [%w] getting tile for coordinates lon: %.8f, lat: %.8f", err, longitude, latitude)
+			slog.WarnContext(request.Context(), "visualize request: error getting GeoTIFF tile for lon/lat coordinates", "error", err,
+				"longitude", longitude, "latitude", latitude, "ID", visualizeRequest.ID)
+			if errors.Is(err, ErrOutsideCoverage) {
+				visualizeResponse.Attributes.Error = newErrorObject(EndpointVisualize, ReasonOutsideCoverage, describeOutsideCoverage(err))
+				buildVisualizeResponse(writer, request, http.StatusNotFound, visualizeResponse)
+				return
+			}
+			if errors.Is(err, ErrSourceUnavailable) {
+				visualizeResponse.Attributes.Error = newErrorObject(EndpointVisualize, ReasonSourceUnavailableLonLat, err.Error())
+				buildVisualizeResponse(writer, request, http.StatusServiceUnavailable, visualizeResponse)
+				return
+			}
+			visualizeResponse.Attributes.Error = newErrorObject(EndpointVisualize, ReasonGettingTileLonLat, err.Error())
+			buildVisualizeResponse(writer, request, http.StatusBadRequest, visualizeResponse)
+			return
+		}
+	}
+
+	// explicit output format (overrides default for type of coordinates)
+	if visualizeRequest.Attributes.OutputFormat != "" {
+		outputFormat = strings.ToLower(visualizeRequest.Attributes.OutputFormat)
+	}
+
+	// conditional request: unchanged response (same parameters, same tiles) needs no processing
+	etag := buildETag("visualize", visualizeRequest, tiles)
+	if checkNotModified(writer, request, etag) {
+		return
+	}
+
+	// color of nodata areas (transparent, fill, preserve), request parameters already copied into response
+	visualizeRequest.Attributes.ColorTextFileContent = applyNoDataHandling(visualizeRequest.Attributes.ColorTextFileContent,
+		visualizeRequest.Attributes.NoDataHandling, visualizeRequest.Attributes.NoDataColor)
+
+	// build visualization for all existing tiles
+	visualizations, tileErrors, err := generateObjectsForTiles(request.Context(), tiles, func(tile TileMetadata) (Visualization, error) {
+		return visualization.generate(request.Context(), tile, outputFormat, visualizeRequest)
+	})
+	if err != nil {
+		slog.WarnContext(request.Context(), "visualize request: error generating visualization object for tile", "error", err, "ID", visualizeRequest.ID)
+		if errors.Is(err, context.Canceled) {
+			// client disconnected, processing aborted, no response required
+			return
+		}
+		if errors.Is(err, ErrServerBusy) {
+			visualizeResponse.Attributes.Error = newErrorObject(EndpointVisualize, ReasonServerBusy, err.Error())
+			writer.Header().Set("Retry-After", strconv.Itoa(getProgConfig().GDALJobQueue.RetryAfter))
+			buildVisualizeResponse(writer, request, http.StatusTooManyRequests, visualizeResponse)
+			return
+		}
+		visualizeResponse.Attributes.Error = newErrorObject(EndpointVisualize, ReasonGeneratingObject, err.Error())
+		buildVisualizeResponse(writer, request, http.StatusBadRequest, visualizeResponse)
+		return
+	}
+	visualizeResponse.Attributes.Visualizations = visualizations
+	visualizeResponse.Attributes.TileErrors = tileErrors
+	if len(tileErrors) > 0 {
+		visualizeResponse.Attributes.Warnings = append(visualizeResponse.Attributes.Warnings,
+			fmt.Sprintf("%d of %d tiles could not be processed (see TileErrors)", len(tileErrors), len(tiles)))
+		slog.WarnContext(request.Context(), "visualize request: partial success, tiles could not be processed", "tile errors", tileErrors, "ID", visualizeRequest.ID)
+	}
+	addUsage(request.Context(), 0, len(visualizations))
+
+	// success response
+	visualizeResponse.Attributes.IsError = false
+	if len(tileErrors) == 0 {
+		// partial responses are not cacheable
+		setETag(writer, etag)
+	}
+	buildVisualizeResponse(writer, request, http.StatusOK, visualizeResponse)
+}
+
+/*
+verifyVisualizeRequestData verifies 'visualize' request data.
+It performs several checks on the request data to ensure its validity.
+*/
+func verifyVisualizeRequestData(request *http.Request, visualizeRequest VisualizeRequest) error {
+	// verify HTTP header
+	contentType := request.Header.Get("Content-Type")
+	isContentTypeValid := true
+	switch {
+	case strings.HasPrefix(strings.ToLower(contentType), "application/json"):
+		// potentially check charset=utf-8 specifically if required
+	default:
+		isContentTypeValid = false
+	}
+	if !isContentTypeValid {
+		return fmt.Errorf("unexpected or missing HTTP header field Content-Type, value = [%s], expected 'application/json'", contentType)
+	}
+
+	// verify Type
+	if visualizeRequest.Type != TypeVisualizeRequest {
+		return fmt.Errorf("unexpected request Type [%v]", visualizeRequest.Type)
+	}
+
+	// verify ID
+	if len(visualizeRequest.ID) > 1024 {
+		return errors.New("ID must be 0-1024 characters long")
+	}
+
+	// verify coordinates (either utm or lon/lat coordinates must be set)
+	if visualizeRequest.Attributes.Zone == 0 && visualizeRequest.Attributes.Longitude == 0 {
+		return errors.New("either utm or lon/lat coordinates must be set")
+	}
+
+	// verify zone for Germany (Zone: 32 or 33)
+	if visualizeRequest.Attributes.Zone != 0 {
+		if visualizeRequest.Attributes.Zone < 32 || visualizeRequest.Attributes.Zone > 33 {
+			return errors.New("invalid zone for Germany")
+		}
+	}
+
+	// verify longitude for Germany (Longitude: from  5.8663° E to 15.0419° E)
+	if visualizeRequest.Attributes.Longitude != 0 {
+		if visualizeRequest.Attributes.Longitude > 15.3 || visualizeRequest.Attributes.Longitude < 5.5 {
+			return errors.New("invalid longitude for Germany")
+		}
+	}
+
+	// verify latitude for Germany (Latitude: from 47.2701° N to 55.0586° N)
+	if visualizeRequest.Attributes.Latitude != 0 {
+		if visualizeRequest.Attributes.Latitude > 55.3 || visualizeRequest.Attributes.Latitude < 47.0 {
+			return errors.New("invalid latitude for Germany")
+		}
+	}
+
+	// verify type of visualization
+	visualization, found := visualizationTypes[strings.ToLower(visualizeRequest.Attributes.TypeOfVisualization)]
+	if !found {
+		return fmt.Errorf("unsupported type of visualization (not %s)", strings.Join(getVisualizationTypeNames(), ", "))
+	}
+
+	// verify gradient algorithm
+	if visualization.usesGradientAlgorithm {
+		if !(visualizeRequest.Attributes.GradientAlgorithm == "Horn" || visualizeRequest.Attributes.GradientAlgorithm == "ZevenbergenThorne") {
+			return errors.New("unsupported gradient algorithm (not Horn or ZevenbergenThorne)")
+		}
+	}
+
+	if visualization.usesColorTextFile {
+		// verify 'color text file content'
+		err := verifyColorTextFileContent(visualizeRequest.Attributes.ColorTextFileContent)
+		if err != nil {
+			return fmt.Errorf("invalid color text file content (%w)", err)
+		}
+
+		// verify coloring algorithm
+		if visualizeRequest.Attributes.ColoringAlgorithm != "" {
+			if !(visualizeRequest.Attributes.ColoringAlgorithm == "interpolation" || visualizeRequest.Attributes.ColoringAlgorithm == "rounding") {
+				return errors.New("unsupported coloring algorithm (not 'interpolation' or 'rounding')")
+			}
+		}
+	}
+
+	if visualization.usesLightSource {
+		// verify vertical exaggeration
+		if visualizeRequest.Attributes.VerticalExaggeration < 0.0 || visualizeRequest.Attributes.VerticalExaggeration > 100.0 {
+			return errors.New("vertical exaggeration must be between 0.0 and 100.0")
+		}
+
+		// verify azimuth of light source
+		if visualizeRequest.Attributes.AzimuthOfLight > 360 {
+			return errors.New("azimuth of light source must be between 0 and 360")
+		}
+
+		// verify altitude of light source
+		if visualizeRequest.Attributes.AltitudeOfLight > 90 {
+			return errors.New("altitude of light source must be between 0 and 90")
+		}
+
+		// verify shading variant
+		switch strings.ToLower(visualizeRequest.Attributes.ShadingVariant) {
+		case "regular":
+		case "combined":
+		case "multidirectional":
+		case "igor":
+		case "textured":
+		default:
+			return errors.New("unsupported shading variant (not regular, combined, multidirectional, igor, textured)")
+		}
+	}
+
+	// verify output format
+	switch strings.ToLower(visualizeRequest.Attributes.OutputFormat) {
+	case "":
+	case "geotiff":
+	case "png":
+	default:
+		return errors.New("unsupported output format (not geotiff, png)")
+	}
+
+	// verify GeoTIFF creation options
+	err := verifyGeoTIFFOptions(visualizeRequest.Attributes.GeoTIFFOptions)
+	if err != nil {
+		return err
+	}
+
+	// verify nodata handling
+	err = verifyNoDataHandling(visualizeRequest.Attributes.NoDataHandling, visualizeRequest.Attributes.NoDataColor)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+/*
+buildVisualizeResponse builds HTTP responses with specified status and body.
+It sets the Content-Type and Content-Length headers before writing the response body.
+This function is used to construct consistent HTTP responses throughout the application.
+*/
+func buildVisualizeResponse(writer http.ResponseWriter, request *http.Request, httpStatus int, visualizeResponse VisualizeResponse) {
+	// response metadata (versions, processing duration, cache hit)
+	visualizeResponse.Meta = newResponseMeta(request.Context())
+
+	// skip compression if payload is already compressed (e.g. PNG)
+	skipCompression := len(visualizeResponse.Attributes.Visualizations) > 0 && isCompressedDataFormat(visualizeResponse.Attributes.Visualizations[0].DataFormat)
+
+	// encode (streaming) and send response
+	streamJSONResponse(writer, request, httpStatus, visualizeResponse, skipCompression)
+}
+
+// visualizationType represents a type of visualization (parameters and generator) of the shared pipeline.
+type visualizationType struct {
+	usesGradientAlgorithm bool
+	usesColorTextFile     bool
+	usesLightSource       bool
+	generate              func(ctx context.Context, tile TileMetadata, outputFormat string, visualizeRequest VisualizeRequest) (Visualization, error)
+}
+
+// visualizationTypes lists all types of visualization supported by the 'visualize' endpoint
+var visualizationTypes = map[string]visualizationType{
+	"slope": {usesGradientAlgorithm: true, usesColorTextFile: true,
+		generate: func(ctx context.Context, tile TileMetadata, outputFormat string, visualizeRequest VisualizeRequest) (Visualization, error) {
+			slope, err := generateSlopeObjectForTile(ctx, tile, outputFormat, visualizeRequest.Attributes.GeoTIFFOptions, visualizeRequest.Attributes.GradientAlgorithm,
+				visualizeRequest.Attributes.ColorTextFileContent, visualizeRequest.Attributes.ColoringAlgorithm, false)
+			return Visualization(slope), err
+		}},
+	"aspect": {usesGradientAlgorithm: true, usesColorTextFile: true,
+		generate: func(ctx context.Context, tile TileMetadata, outputFormat string, visualizeRequest VisualizeRequest) (Visualization, error) {
+			aspect, err := generateAspectObjectForTile(ctx, tile, outputFormat, visualizeRequest.Attributes.GeoTIFFOptions, visualizeRequest.Attributes.GradientAlgorithm,
+				visualizeRequest.Attributes.ColorTextFileContent, visualizeRequest.Attributes.ColoringAlgorithm, visualizeRequest.Attributes.ZeroForFlat)
+			return Visualization(aspect), err
+		}},
+	"tri": {usesColorTextFile: true,
+		generate: func(ctx context.Context, tile TileMetadata, outputFormat string, visualizeRequest VisualizeRequest) (Visualization, error) {
+			tri, err := generateTRIObjectForTile(ctx, tile, outputFormat, visualizeRequest.Attributes.GeoTIFFOptions,
+				visualizeRequest.Attributes.ColorTextFileContent, visualizeRequest.Attributes.ColoringAlgorithm)
+			return Visualization(tri), err
+		}},
+	"tpi": {usesColorTextFile: true,
+		generate: func(ctx context.Context, tile TileMetadata, outputFormat string, visualizeRequest VisualizeRequest) (Visualization, error) {
+			tpi, err := generateTPIObjectForTile(ctx, tile, outputFormat, visualizeRequest.Attributes.GeoTIFFOptions,
+				visualizeRequest.Attributes.ColorTextFileContent, visualizeRequest.Attributes.ColoringAlgorithm, false)
+			return Visualization(tpi), err
+		}},
+	"roughness": {usesColorTextFile: true,
+		generate: func(ctx context.Context, tile TileMetadata, outputFormat string, visualizeRequest VisualizeRequest) (Visualization, error) {
+			roughness, err := generateRoughnessObjectForTile(ctx, tile, outputFormat, visualizeRequest.Attributes.GeoTIFFOptions,
+				visualizeRequest.Attributes.ColorTextFileContent, visualizeRequest.Attributes.ColoringAlgorithm, false)
+			return Visualization(roughness), err
+		}},
+	"colorrelief": {usesColorTextFile: true,
+		generate: func(ctx context.Context, tile TileMetadata, outputFormat string, visualizeRequest VisualizeRequest) (Visualization, error) {
+			colorRelief, err := generateColorReliefObjectForTile(ctx, tile, outputFormat, visualizeRequest.Attributes.GeoTIFFOptions,
+				visualizeRequest.Attributes.ColorTextFileContent, visualizeRequest.Attributes.ColoringAlgorithm, nil)
+			return Visualization(colorRelief), err
+		}},
+	"hillshade": {usesGradientAlgorithm: true, usesLightSource: true,
+		generate: func(ctx context.Context, tile TileMetadata, outputFormat string, visualizeRequest VisualizeRequest) (Visualization, error) {
+			hillshade, err := generateHillshadeObjectForTile(ctx, tile, outputFormat, visualizeRequest.Attributes.GeoTIFFOptions, visualizeRequest.Attributes.GradientAlgorithm,
+				visualizeRequest.Attributes.VerticalExaggeration, visualizeRequest.Attributes.AzimuthOfLight,
+				visualizeRequest.Attributes.AltitudeOfLight, visualizeRequest.Attributes.ShadingVariant, nil)
+			return Visualization{Data: hillshade.Data, DataFormat: hillshade.DataFormat, Filename: hillshade.Filename,
+				Actuality: hillshade.Actuality, Origin: hillshade.Origin, Attribution: hillshade.Attribution,
+				TileIndex: hillshade.TileIndex, BoundingBox: hillshade.BoundingBox, SourceFiles: hillshade.SourceFiles}, err
+		}},
+}
+
+/*
+getVisualizationTypeNames returns the (sorted) names of all supported types of visualization.
+*/
+func getVisualizationTypeNames() []string {
+	names := make([]string, 0, len(visualizationTypes))
+	for name := range visualizationTypes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"klaus/elevation/dtm-elevation-service/pkg/dtm"
+)
+
+/*
+verifyMaskWater verifies that water masking is possible (water mask configured).
+*/
+func verifyMaskWater(maskWater bool) error {
+	if maskWater && getProgConfig().WaterMask.GeoPackage == "" {
+		return errors.New("water masking not supported (no water mask configured)")
+	}
+	return nil
+}
+
+/*
+maskWaterAreas sets all pixels covered by water bodies (see configuration 'WaterMask') to nodata (-9999).
+The raster file (e.g. native slope in /vsimem) is updated in place, the color relief renders nodata
+according to the color text file ('nv' entry, see NoDataHandling).
+*/
+func maskWaterAreas(ctx context.Context, rasterFile string) error {
+	config := getProgConfig().WaterMask
+	if config.GeoPackage == "" {
+		return errors.New("no water mask configured")
+	}
+
+	// e.g. gdal_rasterize -burn -9999 -l water_areas water.gpkg 32_497_5670.slope.utm.tif
+	switches := []string{"-burn", "-9999"}
+	if config.Layer != "" {
+		switches = append(switches, "-l", config.Layer)
+	}
+	err := dtm.Rasterize(ctx, config.GeoPackage, rasterFile, switches)
+	if err != nil {
+		return fmt.Errorf("error [%w] at dtm.Rasterize()", err)
+	}
+	return nil
+}
+
+/*
+waterMasking returns the post-processing of terrain products that sets water bodies to nodata (nil = no masking).
+*/
+func waterMasking(maskWater bool) func(ctx context.Context, filename string) error {
+	if !maskWater {
+		return nil
+	}
+	return maskWaterAreas
+}
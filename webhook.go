@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// callback states
+const (
+	callbackPending   = "pending"
+	callbackDelivered = "delivered"
+	callbackFailed    = "failed"
+)
+
+// headers of callback requests
+const (
+	callbackSignatureHeader = "X-DTM-Signature-256"
+	callbackTimestampHeader = "X-DTM-Timestamp"
+	callbackJobIDHeader     = "X-DTM-Job-ID"
+)
+
+// delays between delivery attempts of a callback (first attempt immediately)
+var callbackRetryDelays = []time.Duration{5 * time.Second, 30 * time.Second, 2 * time.Minute}
+
+// callbackClient is the HTTP client for callbacks (no redirects, restricted target addresses)
+var callbackClient = &http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 5 * time.Second,
+			Control: verifyCallbackAddress,
+		}).DialContext,
+		TLSHandshakeTimeout: 5 * time.Second,
+	},
+	CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+/*
+verifyCallbackAddress rejects connections to non-public addresses (loopback, private, link-local networks),
+unless private callbacks are allowed by configuration. This prevents requests into the internal network (SSRF).
+*/
+func verifyCallbackAddress(_ string, address string, _ syscall.RawConn) error {
+	if getProgConfig().Jobs.AllowPrivateCallbacks {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("error [%w] at net.SplitHostPort(), address: %s", err, address)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified() || ip.IsMulticast() {
+		return fmt.Errorf("callback to non-public address [%s] not allowed", host)
+	}
+	return nil
+}
+
+/*
+verifyCallback verifies callback URL and secret of a job request.
+*/
+func verifyCallback(callbackURL string, callbackSecret string) error {
+	if len(callbackURL) > 2048 {
+		return errors.New("CallbackURL must be 0-2048 characters long")
+	}
+	parsedURL, err := url.Parse(callbackURL)
+	if err != nil {
+		return fmt.Errorf("invalid CallbackURL: %w", err)
+	}
+	if (parsedURL.Scheme != "https" && parsedURL.Scheme != "http") || parsedURL.Host == "" {
+		return errors.New("invalid CallbackURL (absolute http or https URL expected)")
+	}
+	if len(callbackSecret) < 16 || len(callbackSecret) > 256 {
+		return errors.New("CallbackSecret must be 16-256 characters long")
+	}
+	return nil
+}
+
+/*
+signCallback returns the signature of a callback: sha256=hex(hmac-sha256(secret, timestamp + "." + body)).
+*/
+func signCallback(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+/*
+notifyJobCallback calls the callback URL of a finished job (POST JobResponse) and retries failed deliveries.
+A delivery is successful if the receiver responds with HTTP status 2xx.
+*/
+func notifyJobCallback(ctx context.Context, job *Job) {
+	jobResponse := JobResponse{Type: TypeJobResponse, ID: job.id}
+	job.fillResponse(&jobResponse)
+	body, err := json.Marshal(jobResponse)
+	if err != nil {
+		slog.ErrorContext(ctx, "job callback: error marshaling job response", "error", err, "job", job.id)
+		job.setCallbackState(callbackFailed)
+		return
+	}
+
+	for attempt := 0; attempt <= len(callbackRetryDelays); attempt++ {
+		if attempt > 0 {
+			time.Sleep(callbackRetryDelays[attempt-1])
+		}
+		err = deliverCallback(ctx, job, body)
+		if err == nil {
+			job.setCallbackState(callbackDelivered)
+			slog.InfoContext(ctx, "job callback: delivered", "job", job.id, "attempt", attempt+1)
+			return
+		}
+		slog.WarnContext(ctx, "job callback: error delivering callback", "error", err, "job", job.id, "attempt", attempt+1)
+	}
+	job.setCallbackState(callbackFailed)
+}
+
+/*
+deliverCallback sends one (signed) callback request.
+*/
+func deliverCallback(ctx context.Context, job *Job, body []byte) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, job.callbackURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error [%w] at http.NewRequestWithContext()", err)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", progName+"/"+progVersion)
+	request.Header.Set(callbackJobIDHeader, job.id)
+	request.Header.Set(callbackTimestampHeader, timestamp)
+	request.Header.Set(callbackSignatureHeader, signCallback(job.callbackSecret, timestamp, body))
+
+	response, err := callbackClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("error [%w] at client.Do()", err)
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64*1024))
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("unexpected HTTP status [%d]", response.StatusCode)
+	}
+	return nil
+}
+
+/*
+setCallbackState sets the state of the callback of a job.
+*/
+func (job *Job) setCallbackState(state string) {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+	job.callbackState = state
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// webUIFiles contains the static files of the interactive web UI (Leaflet based)
+//
+//go:embed ui
+var webUIFiles embed.FS
+
+// content security policy of the web UI (Leaflet from unpkg, map tiles from OpenStreetMap)
+const webUIContentSecurityPolicy = "default-src 'self'; script-src 'self' https://unpkg.com; style-src 'self' https://unpkg.com; " +
+	"img-src 'self' data: blob: https://unpkg.com https://tile.openstreetmap.org; connect-src 'self'; frame-ancestors 'none'"
+
+/*
+webUIHandler returns the handler for the interactive web UI (GET /ui/): click a point for elevation, draw a line
+for a profile, preview hillshade and contours. The UI is served only if enabled by configuration.
+*/
+func webUIHandler() http.Handler {
+	files, err := fs.Sub(webUIFiles, "ui")
+	if err != nil {
+		// embedded directory always exists
+		panic(err)
+	}
+	fileServer := http.StripPrefix("/ui/", http.FileServerFS(files))
+
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if !getProgConfig().WebUI.Enabled {
+			unsupportedRequest(writer, request)
+			return
+		}
+		writer.Header().Set("Content-Security-Policy", webUIContentSecurityPolicy)
+		writer.Header().Set("X-Content-Type-Options", "nosniff")
+		fileServer.ServeHTTP(writer, request)
+	})
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// WorkerPoolConfig represents the concurrency limit of an endpoint class.
+type WorkerPoolConfig struct {
+	MaxParallel  int `yaml:"MaxParallel"`  // max concurrent processing units (0 = not limited by class)
+	MaxQueueWait int `yaml:"MaxQueueWait"` // max wait in seconds for a free slot (0 = reject immediately)
+}
+
+// workerPool limits the number of concurrent processing units (semaphore with bounded queue wait).
+type workerPool struct {
+	slots        chan struct{}
+	maxQueueWait time.Duration
+	waiting      atomic.Int64 // units currently waiting for a free slot
+	queued       *uint64      // statistics counter: units which had to wait
+	rejected     *uint64      // statistics counter: units rejected after max queue wait
+}
+
+/*
+newWorkerPool creates a worker pool. Returns nil (not limited) if maxParallel <= 0.
+*/
+func newWorkerPool(maxParallel int, maxQueueWait time.Duration, queued *uint64, rejected *uint64) *workerPool {
+	if maxParallel <= 0 {
+		return nil
+	}
+	return &workerPool{
+		slots:        make(chan struct{}, maxParallel),
+		maxQueueWait: maxQueueWait,
+		queued:       queued,
+		rejected:     rejected,
+	}
+}
+
+/*
+acquire waits for a free slot of the pool. Returns ErrServerBusy if no slot became available within
+the max queue wait time, or the context error if the request was canceled. A nil pool is not limited.
+*/
+func (p *workerPool) acquire(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if p == nil {
+		return nil
+	}
+
+	// fast path: free slot available
+	select {
+	case p.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if p.maxQueueWait <= 0 {
+		atomic.AddUint64(p.rejected, 1)
+		return ErrServerBusy
+	}
+
+	// queue until slot becomes available or timeout
+	atomic.AddUint64(p.queued, 1)
+	p.waiting.Add(1)
+	defer p.waiting.Add(-1)
+	timer := time.NewTimer(p.maxQueueWait)
+	defer timer.Stop()
+	select {
+	case p.slots <- struct{}{}:
+		return nil
+	case <-timer.C:
+		atomic.AddUint64(p.rejected, 1)
+		return ErrServerBusy
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+/*
+release releases a slot of the pool.
+*/
+func (p *workerPool) release() {
+	if p == nil {
+		return
+	}
+	<-p.slots
+}
+
+/*
+running returns the number of occupied slots.
+*/
+func (p *workerPool) running() int {
+	if p == nil {
+		return 0
+	}
+	return len(p.slots)
+}
+
+/*
+queueDepth returns the number of units waiting for a free slot.
+*/
+func (p *workerPool) queueDepth() int64 {
+	if p == nil {
+		return 0
+	}
+	return p.waiting.Load()
+}
+
+// endpoint classes with separate worker pools
+const (
+	EndpointClassPoints   = "points"
+	EndpointClassGPX      = "gpx"
+	EndpointClassRaster   = "raster"
+	EndpointClassContours = "contours"
+)
+
+// endpointClasses lists the endpoint classes (in order of metrics output)
+var endpointClasses = []string{EndpointClassPoints, EndpointClassGPX, EndpointClassRaster, EndpointClassContours}
+
+// endpointClassOf maps endpoints to their class (endpoints without class are not limited, e.g. legend)
+var endpointClassOf = map[*ErrorEndpoint]string{
+	EndpointPoint:            EndpointClassPoints,
+	EndpointUTMPoint:         EndpointClassPoints,
+	EndpointElevationProfile: EndpointClassPoints,
+	EndpointCompare:          EndpointClassPoints,
+	EndpointGPX:              EndpointClassGPX,
+	EndpointGPXAnalyze:       EndpointClassGPX,
+	EndpointHillshade:        EndpointClassRaster,
+	EndpointSlope:            EndpointClassRaster,
+	EndpointAspect:           EndpointClassRaster,
+	EndpointTPI:              EndpointClassRaster,
+	EndpointTRI:              EndpointClassRaster,
+	EndpointRoughness:        EndpointClassRaster,
+	EndpointRawTIF:           EndpointClassRaster,
+	EndpointColorRelief:      EndpointClassRaster,
+	EndpointHistogram:        EndpointClassRaster,
+	EndpointVisualize:        EndpointClassRaster,
+	EndpointAspectRose:       EndpointClassRaster,
+	EndpointElevationMatrix:  EndpointClassRaster,
+	EndpointExport:           EndpointClassRaster,
+	EndpointContours:         EndpointClassContours,
+	EndpointContourLine:      EndpointClassContours,
+	EndpointElevationRange:   EndpointClassContours,
+	EndpointContoursExport:   EndpointClassContours,
+}
+
+// workerPools holds the worker pool per endpoint class (nil = class not limited)
+var workerPools map[string]*workerPool
+
+/*
+initWorkerPools initializes the worker pools of all endpoint classes (max queue wait in seconds).
+Points and GPX requests are limited per request, raster and contours requests per GDAL processing job
+(a multi-tile request runs several GDAL jobs in parallel).
+*/
+func initWorkerPools() {
+	config := getProgConfig().WorkerPools
+	newPool := func(poolConfig WorkerPoolConfig) *workerPool {
+		return newWorkerPool(poolConfig.MaxParallel, time.Duration(poolConfig.MaxQueueWait)*time.Second, &WorkerPoolQueued, &WorkerPoolRejections)
+	}
+	workerPools = map[string]*workerPool{
+		EndpointClassPoints:   newPool(config.Points),
+		EndpointClassGPX:      newPool(config.GPX),
+		EndpointClassRaster:   newPool(config.Raster),
+		EndpointClassContours: newPool(config.Contours),
+	}
+}
+
+// workerPoolKey is the context key for the worker pool of GDAL processing jobs (raster and contours class)
+type workerPoolKey struct{}
+
+/*
+withWorkerPool returns a context whose GDAL processing jobs are limited by the worker pool of the endpoint class.
+*/
+func withWorkerPool(ctx context.Context, endpoint *ErrorEndpoint) context.Context {
+	class := endpointClassOf[endpoint]
+	if class != EndpointClassRaster && class != EndpointClassContours {
+		return ctx
+	}
+	pool := workerPools[class]
+	if pool == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, workerPoolKey{}, pool)
+}
+
+/*
+getContextWorkerPool returns the worker pool for GDAL processing jobs from the context (nil if not limited).
+*/
+func getContextWorkerPool(ctx context.Context) *workerPool {
+	pool, _ := ctx.Value(workerPoolKey{}).(*workerPool)
+	return pool
+}
+
+/*
+getEndpointByPath returns the endpoint (error code registry) for a v1 or v2 request path (nil if unknown).
+*/
+func getEndpointByPath(path string) *ErrorEndpoint {
+	for _, endpoint := range errorEndpoints {
+		if endpoint.Path != "" && endpoint.Path == path {
+			return endpoint
+		}
+	}
+	index := slices.IndexFunc(v2Endpoints, func(endpoint V2Endpoint) bool { return endpoint.Path == path })
+	if index < 0 {
+		return nil
+	}
+	return v2Endpoints[index].Endpoint
+}
+
+/*
+workerPoolMiddleware applies the concurrency limit of the endpoint class: points and GPX requests wait for a
+free slot of their class (or are rejected with '429 Too Many Requests'), raster and contours requests get the
+pool of their class for their GDAL processing jobs (see acquireGDALJobSlot). This way one class cannot starve the others.
+*/
+func workerPoolMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		endpoint := getEndpointByPath(request.URL.Path)
+		class := endpointClassOf[endpoint]
+		if class == "" || request.Method == http.MethodOptions {
+			next.ServeHTTP(writer, request)
+			return
+		}
+
+		if class == EndpointClassRaster || class == EndpointClassContours {
+			next.ServeHTTP(writer, request.WithContext(withWorkerPool(request.Context(), endpoint)))
+			return
+		}
+
+		pool := workerPools[class]
+		err := pool.acquire(request.Context())
+		if err != nil {
+			if request.Context().Err() != nil {
+				// client disconnected, no response required
+				return
+			}
+			retryAfter := getProgConfig().GDALJobQueue.RetryAfter
+			slog.WarnContext(request.Context(), "request rejected by worker pool", "class", class, "path", request.URL.Path)
+			writer.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			writer.Header().Set("Content-Type", TextPlainMediaType)
+			writer.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprintf(writer, "server busy, too many concurrent %s requests, retry after %d seconds", class, retryAfter)
+			return
+		}
+		defer pool.release()
+		next.ServeHTTP(writer, request)
+	})
+}